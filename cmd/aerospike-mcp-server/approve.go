@@ -0,0 +1,55 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/mcp"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// approveElevation implements the approve-elevation subcommand, which
+// prints the approval code for a role elevation request made through the
+// elevate_role tool. It returns the exit status.
+func approveElevation(args []string) int {
+	flags := flag.NewFlagSet("approve-elevation", flag.ExitOnError)
+	configPath := flags.String("config", "", "Configuration file supplying the approval key")
+	keyFile := flags.String("key-file", "", "Elevation approval key file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s approve-elevation [-config file] [-key-file file] request\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if *configPath != "" && *keyFile == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+			return 2
+		}
+		*keyFile = cfg.Elevation.ApprovalKeyFile
+	}
+	if flags.NArg() != 1 || *keyFile == "" {
+		flags.Usage()
+		return 2
+	}
+
+	req, err := mcp.ParseElevationRequest(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	key, err := mcp.ReadApprovalKey(*keyFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	fmt.Printf("Approving role %s for %s for client %q (request %s)\n", req.Role, req.Duration, req.Client, req.ID)
+	fmt.Printf("Approval code: %s\n", req.ApprovalCode(key))
+	return 0
+}
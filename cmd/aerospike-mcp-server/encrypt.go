@@ -0,0 +1,65 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/secrets"
+)
+
+// encryptSecret implements the encrypt-secret subcommand, which encrypts a
+// value read from stdin for use as an "enc:" config value, or generates a
+// new key. It returns the exit status.
+func encryptSecret(args []string) int {
+	flags := flag.NewFlagSet("encrypt-secret", flag.ExitOnError)
+	keyEnv := flags.String("key-env", secrets.DefaultKeyEnv, "Environment variable holding the base64 encryption key")
+	generate := flags.Bool("generate-key", false, "Print a new random key and exit")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s encrypt-secret [-key-env name] < value\n       %s encrypt-secret -generate-key\n", os.Args[0], os.Args[0])
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if *generate {
+		key, err := secrets.GenerateKey()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to generate key: %v\n", err)
+			return 1
+		}
+		fmt.Println(key)
+		return 0
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	key, err := secrets.ParseKey(os.Getenv(*keyEnv))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *keyEnv, err)
+		return 2
+	}
+
+	// Read the value from stdin so it doesn't end up in shell history; one
+	// trailing newline is dropped
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && value == "" {
+		fmt.Fprintln(os.Stderr, "No value given on stdin")
+		return 2
+	}
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "\n"), "\r")
+
+	encrypted, err := secrets.Encrypt(key, value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encrypt: %v\n", err)
+		return 1
+	}
+	fmt.Println(encrypted)
+	return 0
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// initConfig implements the init subcommand, which writes an example
+// configuration listing every option with its default. It returns the exit
+// status.
+func initConfig(args []string) int {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	format := flags.String("format", "", "Output format: yaml or json (default from the -o extension, else yaml)")
+	output := flags.String("o", "", "File to write (default standard output)")
+	force := flags.Bool("force", false, "Overwrite an existing file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s init [-format yaml|json] [-o file] [-force]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if *format == "" {
+		*format = "yaml"
+		if strings.EqualFold(filepath.Ext(*output), ".json") {
+			*format = "json"
+		}
+	}
+	data, err := config.Sample(strings.ToLower(*format))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return 0
+	}
+
+	mode := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		mode = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(*output, mode, 0o600)
+	if os.IsExist(err) {
+		fmt.Fprintf(os.Stderr, "%s already exists (use -force to overwrite)\n", *output)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", *output, err)
+		return 1
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *output, err)
+		return 1
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", *output, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", *output)
+	return 0
+}
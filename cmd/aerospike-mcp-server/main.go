@@ -7,13 +7,16 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/logging"
 	"github.com/dringdahl0320/aerospike-mcp-server/internal/mcp"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/secrets"
 	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
 )
 
@@ -23,9 +26,27 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "init":
+			os.Exit(initConfig(os.Args[2:]))
+		case "verify-audit":
+			os.Exit(verifyAudit(os.Args[2:]))
+		case "approve-elevation":
+			os.Exit(approveElevation(os.Args[2:]))
+		case "encrypt-secret":
+			os.Exit(encryptSecret(os.Args[2:]))
+		}
+	}
+
 	// Parse command line flags
 	configPath := flag.String("config", "", "Path to configuration file")
 	showVersion := flag.Bool("version", false, "Show version information")
+	flag.String("hosts", "", "Comma-separated Aerospike hosts as host:port (overrides the config file)")
+	flag.String("namespace", "", "Default namespace (overrides the config file)")
+	flag.String("role", "", "Role: read-only, read-write, or admin (overrides the config file)")
+	flag.String("transport", "", "Transport: stdio, sse, websocket, unix, or grpc (overrides the config file)")
+	flag.Int("port", 0, "Port for the sse, websocket, and grpc transports (overrides the config file)")
 	flag.Parse()
 
 	if *showVersion {
@@ -33,10 +54,23 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Flags given on the command line take precedence over the config file
+	// and environment
+	overrides := config.Overrides{}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "hosts", "namespace", "role", "transport", "port":
+			overrides[f.Name] = f.Value.String()
+		}
+	})
+
 	// Load configuration
-	cfg, err := config.Load(*configPath)
+	cfg, err := config.Load(*configPath, overrides)
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		fatal("Failed to load configuration", err)
+	}
+	if err := logging.Setup(os.Stderr, cfg.Log.Level, cfg.Log.Format); err != nil {
+		fatal("Failed to configure logging", err)
 	}
 
 	// Create context with cancellation
@@ -48,22 +82,146 @@ func main() {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		<-sigChan
-		log.Println("Shutdown signal received, closing connections...")
+		slog.Info("Shutdown signal received, closing connections")
 		cancel()
 	}()
 
+	// Resolve credentials from Vault if configured
+	var vault *secrets.VaultClient
+	var vaultCreds *secrets.Credentials
+	var vaultInterval time.Duration
+	if cfg.Secrets.Vault != nil {
+		vault, err = secrets.NewVaultClient(cfg.Secrets.Vault)
+		if err != nil {
+			fatal("Failed to configure Vault", err)
+		}
+		if cfg.Secrets.Vault.Path != "" {
+			vaultCreds, vaultInterval, err = vault.Fetch(ctx)
+			if err != nil {
+				fatal("Failed to read credentials from Vault", err)
+			}
+			vaultCreds.Apply(cfg)
+		}
+	}
+
+	// Resolve secret references in the config
+	refs := secrets.FindRefs(cfg, secrets.NewResolver(cfg.Secrets, vault))
+	var resolved *secrets.Resolved
+	if !refs.Empty() {
+		resolved, err = refs.Resolve(ctx)
+		if err != nil {
+			fatal("Failed to resolve secrets", err)
+		}
+		resolved.Apply(cfg)
+	}
+
 	// Initialize Aerospike client
 	asClient, err := aerospike.NewClient(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to Aerospike: %v", err)
+		fatal("Failed to connect to Aerospike", err)
 	}
 	defer asClient.Close()
 
-	log.Printf("Connected to Aerospike cluster: %s", asClient.ClusterName())
+	if asClient.IsConnected() {
+		slog.Info("Connected to Aerospike cluster", "cluster", asClient.ClusterName())
+		startupChecks(ctx, asClient, cfg)
+	} else {
+		slog.Warn("Aerospike cluster not available, starting degraded and retrying in the background")
+		go func() {
+			if asClient.WaitConnected(ctx) == nil {
+				slog.Info("Connected to Aerospike cluster", "cluster", asClient.ClusterName())
+			}
+		}()
+	}
 
 	// Create and run MCP server
 	server := mcp.NewServer(asClient, cfg)
+
+	// Reload the configuration on SIGHUP; OperatorReload logs the outcome.
+	// Unlike the reload_config tool, it can lift read-only mode
+	server.SetConfigLoader(func() (*config.Config, error) {
+		return config.Load(*configPath, overrides)
+	})
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hupChan:
+				slog.Info("Reload signal received")
+				_, _ = server.OperatorReload(ctx)
+			}
+		}
+	}()
+
+	// Pick up rotated secrets
+	reconnect := func() {
+		if err := asClient.Reconnect(); err != nil {
+			slog.Error("Failed to reconnect with rotated credentials", "error", err)
+			return
+		}
+		slog.Info("Reconnected to Aerospike with rotated credentials")
+	}
+	if vaultCreds != nil {
+		go vault.Watch(ctx, vaultCreds, vaultInterval, func(creds *secrets.Credentials) {
+			creds.Apply(cfg)
+			reconnect()
+		})
+	}
+	if files := secrets.FindFiles(cfg); !files.Empty() {
+		interval := time.Duration(cfg.Secrets.RefreshIntervalSec) * time.Second
+		go files.Watch(ctx, interval, func(creds *secrets.Credentials) {
+			creds.Apply(cfg)
+			reconnect()
+		})
+	}
+	if resolved != nil {
+		interval := time.Duration(cfg.Secrets.RefreshIntervalSec) * time.Second
+		previous := resolved
+		go refs.Watch(ctx, resolved, interval, func(r *secrets.Resolved) {
+			if r.APIKeys != nil {
+				server.UpdateAPIKeys(r.APIKeys)
+			}
+			r.Apply(cfg)
+			if !r.Credentials.Equal(&previous.Credentials) {
+				reconnect()
+			}
+			previous = r
+		})
+	}
+
 	if err := server.Run(ctx); err != nil {
-		log.Fatalf("MCP server error: %v", err)
+		fatal("MCP server error", err)
+	}
+}
+
+// startupChecks opens warm connections and runs the startup probe, as
+// configured, before the server accepts requests. A failed probe is fatal.
+func startupChecks(ctx context.Context, client *aerospike.Client, cfg *config.Config) {
+	if n := cfg.ClientPolicy.WarmUpConnections; n > 0 {
+		opened, err := client.WarmUp(n)
+		if err != nil {
+			slog.Warn("Failed to warm up connections", "opened", opened, "error", err)
+		} else {
+			slog.Info("Warmed up connections", "opened", opened)
+		}
+	}
+
+	if cfg.StartupProbe.Enabled {
+		probeCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.StartupProbe.TimeoutMs)*time.Millisecond)
+		defer cancel()
+		namespaces, err := client.Probe(probeCtx, cfg.StartupProbe.RequiredNamespaces)
+		if err != nil {
+			fatal("Startup probe failed", err)
+		}
+		slog.Info("Startup probe passed", "namespaces", namespaces)
 	}
 }
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
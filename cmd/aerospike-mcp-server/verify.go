@@ -0,0 +1,78 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// verifyAudit implements the verify-audit subcommand, which checks the hash
+// chain and signatures of an audit log file. It returns the exit status.
+func verifyAudit(args []string) int {
+	flags := flag.NewFlagSet("verify-audit", flag.ExitOnError)
+	configPath := flags.String("config", "", "Configuration file supplying the audit file and signing key")
+	keyFile := flags.String("key-file", "", "Audit signing key file")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s verify-audit [-config file] [-key-file file] [audit-log]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	path := flags.Arg(0)
+	if *configPath != "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+			return 2
+		}
+		if path == "" {
+			path = cfg.Audit.FilePath
+		}
+		if *keyFile == "" {
+			*keyFile = cfg.Audit.SigningKeyFile
+		}
+	}
+	if path == "" {
+		flags.Usage()
+		return 2
+	}
+
+	var key []byte
+	if *keyFile != "" {
+		var err error
+		if key, err = audit.ReadSigningKey(*keyFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	file, err := audit.OpenLog(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open audit log: %v\n", err)
+		return 2
+	}
+	defer file.Close()
+
+	result, err := audit.Verify(file, key)
+	if err != nil {
+		fmt.Printf("FAILED: %s: %v\n", path, err)
+		return 1
+	}
+
+	fmt.Printf("OK: %s: %d chained events, %d signatures\n", path, result.Events, result.Signatures)
+	if result.Unchained > 0 {
+		fmt.Printf("Note: %d events precede the hash chain and were not verified\n", result.Unchained)
+	}
+	if key == nil {
+		fmt.Println("Warning: no signing key given; signatures were not checked")
+	} else if result.Unsigned > 0 {
+		fmt.Printf("Warning: the last %d events are not covered by a signature\n", result.Unsigned)
+	}
+	return 0
+}
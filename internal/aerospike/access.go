@@ -0,0 +1,125 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// namespaceAccessCounters are the namespace statistics AccessStats sums:
+// the transactions clients have made since each node started.
+var namespaceAccessCounters = []string{
+	"client_read_success", "client_read_not_found", "client_read_error", "client_read_timeout",
+	"client_write_success", "client_write_error", "client_write_timeout",
+	"client_delete_success", "client_delete_not_found",
+	"client_udf_complete", "batch_sub_read_success", "batch_sub_write_success",
+	"fail_generation", "fail_key_busy", "fail_record_too_big",
+}
+
+// setAccessCounters are the set statistics AccessStats sums.
+var setAccessCounters = []string{
+	"objects", "tombstones", "data_used_bytes", "memory_data_bytes", "device_data_bytes",
+}
+
+// AccessStats are a namespace's transaction counters and a set's record
+// counts, summed over the active nodes that answered. The cluster counts
+// transactions per namespace, not per set, and since each node started.
+// Errors lists the nodes that didn't answer.
+type AccessStats struct {
+	Namespace         string           `json:"namespace"`
+	Set               string           `json:"set,omitempty"`
+	Nodes             int              `json:"nodes"`
+	NamespaceCounters map[string]int64 `json:"namespace_counters"`
+	SetCounters       map[string]int64 `json:"set_counters,omitempty"`
+	Errors            []string         `json:"errors,omitempty"`
+}
+
+// GetAccessStats returns the access statistics of a namespace and, if
+// setName isn't empty, a set, asking every active node in parallel. It
+// fails only if no node answered.
+func (c *Client) GetAccessStats(ctx context.Context, namespace, setName string) (*AccessStats, error) {
+	client := c.conn()
+	if client == nil || !client.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	nodes := slices.DeleteFunc(slices.Clone(client.GetNodes()), func(node *as.Node) bool {
+		return !node.IsActive()
+	})
+	nsCommand, setCommand := "namespace/"+namespace, "sets/"+namespace+"/"+setName
+	commands := []string{nsCommand}
+	if setName != "" {
+		commands = append(commands, setCommand)
+	}
+
+	nsStats := make([]map[string]string, len(nodes))
+	setStats := make([]map[string]string, len(nodes))
+	start := time.Now()
+	errs := c.eachNode(ctx, nodes, func(ctx context.Context, node *as.Node, i int) error {
+		info, err := node.RequestInfo(infoPolicyFor(ctx), commands...)
+		if err != nil {
+			return err
+		}
+		if err := infoError(info[nsCommand]); err != nil {
+			return err
+		}
+		nsStats[i] = parseInfoString(info[nsCommand])
+		if setName != "" {
+			setStats[i] = parseSetStats(info[setCommand])
+		}
+		return nil
+	})
+	nodeErr := errors.Join(errs...)
+	c.observe(ctx, "access_stats", start, nodeErr)
+
+	stats := &AccessStats{Namespace: namespace, Set: setName, NamespaceCounters: make(map[string]int64)}
+	if setName != "" {
+		stats.SetCounters = make(map[string]int64)
+	}
+	for i, err := range errs {
+		if err != nil {
+			stats.Errors = append(stats.Errors, err.Error())
+			continue
+		}
+		stats.Nodes++
+		sumCounters(stats.NamespaceCounters, nsStats[i], namespaceAccessCounters)
+		if setName != "" {
+			sumCounters(stats.SetCounters, setStats[i], setAccessCounters)
+		}
+	}
+	if stats.Nodes == 0 && len(nodes) > 0 {
+		return nil, fmt.Errorf("reading access statistics: %w", nodeErr)
+	}
+	return stats, nil
+}
+
+// parseSetStats parses the colon-separated statistics of one set.
+func parseSetStats(info string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(strings.TrimSuffix(info, ";"), ":") {
+		if kv := strings.SplitN(pair, "=", 2); len(kv) == 2 {
+			result[kv[0]] = kv[1]
+		}
+	}
+	return result
+}
+
+// sumCounters adds the named integer fields of stats to totals. Fields a
+// node doesn't report, as older servers don't report newer counters, are
+// left out.
+func sumCounters(totals map[string]int64, stats map[string]string, names []string) {
+	for _, name := range names {
+		if n, err := strconv.ParseInt(stats[name], 10, 64); err == nil {
+			totals[name] += n
+		}
+	}
+}
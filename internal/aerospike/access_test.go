@@ -0,0 +1,28 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSetStats(t *testing.T) {
+	got := parseSetStats("objects=120:tombstones=0:data_used_bytes=4096:set=users;")
+	want := map[string]string{"objects": "120", "tombstones": "0", "data_used_bytes": "4096", "set": "users"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestSumCounters(t *testing.T) {
+	totals := make(map[string]int64)
+	sumCounters(totals, map[string]string{"objects": "120", "tombstones": "3", "set": "users"}, setAccessCounters)
+	sumCounters(totals, map[string]string{"objects": "80", "memory_data_bytes": "bad"}, setAccessCounters)
+
+	want := map[string]int64{"objects": 200, "tombstones": 3}
+	if !reflect.DeepEqual(totals, want) {
+		t.Errorf("Expected %v, got %v", want, totals)
+	}
+}
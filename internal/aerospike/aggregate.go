@@ -0,0 +1,200 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// AggregateResult summarizes a numeric bin over the records a query or scan
+// matches. Count is the records matched and Values those holding a number
+// in the bin; Min, Max, and Sum are integers if every value is. Incomplete
+// reports that the call's deadline ended the aggregation early.
+type AggregateResult struct {
+	Namespace  string      `json:"namespace"`
+	Set        string      `json:"set,omitempty"`
+	Bin        string      `json:"bin,omitempty"`
+	Count      int64       `json:"count"`
+	Values     int64       `json:"values"`
+	Min        interface{} `json:"min,omitempty"`
+	Max        interface{} `json:"max,omitempty"`
+	Sum        interface{} `json:"sum,omitempty"`
+	Avg        *float64    `json:"avg,omitempty"`
+	Incomplete bool        `json:"incomplete,omitempty"`
+}
+
+// Aggregate computes the count, and the min, max, sum, and average of
+// binName, over the records of a set that match filter, an index filter,
+// and expression, a filter expression; either may be nil to match every
+// record. Only binName is read from each record, and no bins at all if it
+// is empty, which counts the records.
+func (c *Client) Aggregate(ctx context.Context, namespace, setName, binName string, filter *QueryFilter, expression interface{}) (*AggregateResult, error) {
+	stmt := as.NewStatement(namespace, setName)
+	if binName != "" {
+		stmt = as.NewStatement(namespace, setName, binName)
+	}
+	if filter != nil {
+		asFilter, err := filter.build()
+		if err != nil {
+			return nil, err
+		}
+		if err := stmt.SetFilter(asFilter); err != nil {
+			return nil, fmt.Errorf("setting filter: %w", err)
+		}
+	}
+
+	policy := c.queryPolicyFor(ctx, namespace)
+	policy.IncludeBinData = binName != ""
+	if expression != nil {
+		exp, err := buildExpression(expression)
+		if err != nil {
+			return nil, fmt.Errorf("filter_expression: %w", err)
+		}
+		policy.FilterExpression = exp
+	}
+
+	release, err := c.scans.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	recordset, err := c.conn().Query(policy, stmt)
+	if err != nil {
+		c.observe(ctx, "aggregate", start, err)
+		return nil, fmt.Errorf("executing query: %w", err)
+	}
+	defer recordset.Close()
+
+	var agg aggregator
+	incomplete, err := c.aggregate(ctx, start, recordset, namespace, setName, binName, &agg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := agg.result()
+	result.Namespace, result.Set, result.Bin = namespace, setName, binName
+	result.Incomplete = incomplete
+	return result, nil
+}
+
+// aggregate adds the visible records of a query to agg. It returns true if
+// the call's deadline ended the query first.
+func (c *Client) aggregate(ctx context.Context, start time.Time, recordset *as.Recordset, namespace, setName, binName string, agg *aggregator) (bool, error) {
+	progress := progressFrom(ctx)
+	results := recordset.Results()
+	for {
+		var rec *as.Result
+		select {
+		case rec = <-results:
+		case <-ctx.Done():
+			c.observe(ctx, "aggregate", start, ctx.Err())
+			return true, nil
+		}
+		if rec == nil {
+			break
+		}
+		if rec.Err != nil {
+			c.observe(ctx, "aggregate", start, rec.Err)
+			if deadlineReached(ctx, rec.Err) {
+				return true, nil
+			}
+			return false, fmt.Errorf("aggregate result error: %w", rec.Err)
+		}
+		if !c.keyVisible(ctx, namespace, setName, rec.Record.Key.Value()) {
+			continue
+		}
+
+		var value interface{}
+		if binName != "" {
+			value = rec.Record.Bins[binName]
+		}
+		agg.add(value)
+		progress.add(1)
+	}
+
+	c.observe(ctx, "aggregate", start, nil)
+	return false, nil
+}
+
+// aggregator accumulates the values of a numeric bin. Integers are summed
+// exactly until a float is seen or the sum overflows.
+type aggregator struct {
+	count, values int64
+	intSum        int64
+	floatSum      float64
+	floats        bool
+	min, max      interface{}
+}
+
+// add counts a record holding v, which is ignored unless it is a number.
+func (a *aggregator) add(v interface{}) {
+	a.count++
+
+	var f float64
+	switch n := v.(type) {
+	case float64:
+		f = n
+		a.floatSum += n
+		a.floats = true
+	default:
+		i, ok := toInt64(v)
+		if !ok {
+			return
+		}
+		f = float64(i)
+		sum := a.intSum + i
+		if (i > 0 && sum < a.intSum) || (i < 0 && sum > a.intSum) {
+			a.floatSum += float64(a.intSum) + f
+			a.intSum = 0
+			a.floats = true
+		} else {
+			a.intSum = sum
+		}
+		v = i
+	}
+
+	if a.values == 0 || f < numberValue(a.min) {
+		a.min = v
+	}
+	if a.values == 0 || f > numberValue(a.max) {
+		a.max = v
+	}
+	a.values++
+}
+
+// result returns the aggregates of the values added.
+func (a *aggregator) result() *AggregateResult {
+	result := &AggregateResult{Count: a.count, Values: a.values}
+	if a.values == 0 {
+		return result
+	}
+	result.Min, result.Max = a.min, a.max
+
+	var sum float64
+	if a.floats {
+		sum = a.floatSum + float64(a.intSum)
+		result.Sum = sum
+	} else {
+		sum = float64(a.intSum)
+		result.Sum = a.intSum
+	}
+	avg := sum / float64(a.values)
+	result.Avg = &avg
+	return result
+}
+
+// numberValue returns an integer or float as a float.
+func numberValue(v interface{}) float64 {
+	if f, ok := v.(float64); ok {
+		return f
+	}
+	n, _ := toInt64(v)
+	return float64(n)
+}
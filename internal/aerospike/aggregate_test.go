@@ -0,0 +1,57 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestAggregator(t *testing.T) {
+	avg := func(f float64) *float64 { return &f }
+	tests := []struct {
+		name   string
+		values []interface{}
+		want   AggregateResult
+	}{
+		{
+			name:   "integers",
+			values: []interface{}{5, int64(-2), 9},
+			want:   AggregateResult{Count: 3, Values: 3, Min: int64(-2), Max: int64(9), Sum: int64(12), Avg: avg(4)},
+		},
+		{
+			name:   "mixed numbers",
+			values: []interface{}{1, 2.5, 4},
+			want:   AggregateResult{Count: 3, Values: 3, Min: int64(1), Max: int64(4), Sum: 7.5, Avg: avg(2.5)},
+		},
+		{
+			name:   "non-numeric and missing values",
+			values: []interface{}{"a", nil, 3, []interface{}{1}},
+			want:   AggregateResult{Count: 4, Values: 1, Min: int64(3), Max: int64(3), Sum: int64(3), Avg: avg(3)},
+		},
+		{
+			name:   "count only",
+			values: []interface{}{nil, nil},
+			want:   AggregateResult{Count: 2},
+		},
+		{
+			name:   "overflow",
+			values: []interface{}{int64(math.MaxInt64), int64(math.MaxInt64)},
+			want:   AggregateResult{Count: 2, Values: 2, Min: int64(math.MaxInt64), Max: int64(math.MaxInt64), Sum: 2 * float64(math.MaxInt64), Avg: avg(float64(math.MaxInt64))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var agg aggregator
+			for _, v := range tt.values {
+				agg.add(v)
+			}
+			if got := agg.result(); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/google/uuid"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// ErrAuditSetReadOnly is returned for tool writes to the set audit events
+// are stored in.
+var ErrAuditSetReadOnly = errors.New("the audit set is read-only")
+
+// AuditSink stores audit events as records in an Aerospike set, one record
+// per event, keyed by a random UUID. It implements audit.Sink.
+type AuditSink struct {
+	client    *Client
+	namespace string
+	set       string
+	ttl       int
+}
+
+// NewAuditSink creates a sink writing to the set described by cfg.
+func NewAuditSink(client *Client, cfg *config.AuditAerospikeConfig) *AuditSink {
+	return &AuditSink{client: client, namespace: cfg.Namespace, set: cfg.Set, ttl: cfg.TTLSec}
+}
+
+// WriteEvent stores one audit event. It bypasses role and key checks: the
+// server records events whatever the role of the client being audited.
+func (s *AuditSink) WriteEvent(ctx context.Context, event audit.Event) error {
+	bins, err := auditBins(event)
+	if err != nil {
+		return err
+	}
+	key, keyErr := as.NewKey(s.namespace, s.set, uuid.NewString())
+	if keyErr != nil {
+		return fmt.Errorf("creating key: %w", keyErr)
+	}
+
+	policy := as.NewWritePolicy(0, uint32(s.ttl))
+	policy.TotalTimeout = s.client.writePolicy.TotalTimeout
+	policy.MaxRetries = s.client.writePolicy.MaxRetries
+	policy.SendKey = true
+	policy.RecordExistsAction = as.CREATE_ONLY
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < policy.TotalTimeout || policy.TotalTimeout == 0 {
+			policy.TotalTimeout = remaining
+		}
+	}
+
+	start := time.Now()
+	err = s.client.conn().Put(policy, key, bins)
+	s.client.observe(ctx, "audit_put", start, err)
+	if err != nil {
+		return fmt.Errorf("writing audit record: %w", err)
+	}
+	return nil
+}
+
+// Close implements audit.Sink. The cluster connection belongs to the
+// server and is closed separately.
+func (s *AuditSink) Close() error {
+	return nil
+}
+
+// auditBins converts an event to bins. The timestamp is stored in Unix
+// milliseconds so it can be range-queried with a numeric index, and details
+// as JSON text. Empty fields are omitted.
+func auditBins(event audit.Event) (as.BinMap, error) {
+	bins := as.BinMap{
+		"ts":          event.Timestamp.UnixMilli(),
+		"level":       string(event.Level),
+		"category":    string(event.Category),
+		"operation":   event.Operation,
+		"duration_ns": int64(event.Duration),
+		"success":     event.Success,
+	}
+	text := map[string]string{
+		"namespace":  event.Namespace,
+		"set":        event.Set,
+		"key":        event.Key,
+		"user":       event.User,
+		"client_id":  event.ClientID,
+		"request_id": event.RequestID,
+		"error":      event.Error,
+		"prev_hash":  event.PrevHash,
+		"hash":       event.Hash,
+	}
+	for name, value := range text {
+		if value != "" {
+			bins[name] = value
+		}
+	}
+	if event.RecordCount != 0 {
+		bins["record_count"] = event.RecordCount
+	}
+	if event.Seq != 0 {
+		bins["seq"] = int64(event.Seq)
+	}
+	if len(event.Details) > 0 {
+		details, err := json.Marshal(event.Details)
+		if err != nil {
+			return nil, fmt.Errorf("encoding audit details: %w", err)
+		}
+		bins["details"] = string(details)
+	}
+	return bins, nil
+}
+
+// checkWritable returns ErrAuditSetReadOnly if namespace and setName hold
+// the audit sink's records, so clients can't alter the audit history.
+func (c *Client) checkWritable(namespace, setName string) error {
+	if sink := c.config.Audit.Aerospike; sink != nil && sink.Namespace == namespace && sink.Set == setName {
+		return fmt.Errorf("%w: %s/%s", ErrAuditSetReadOnly, namespace, setName)
+	}
+	return nil
+}
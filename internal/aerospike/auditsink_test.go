@@ -0,0 +1,86 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestAuditBins(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	bins, err := auditBins(audit.Event{
+		Timestamp: now,
+		Level:     audit.LevelAudit,
+		Category:  audit.CategoryWrite,
+		Operation: "put_record",
+		Namespace: "test",
+		Key:       "user:1",
+		Duration:  1500 * time.Microsecond,
+		Success:   true,
+		Details:   map[string]interface{}{"bins": []string{"email"}},
+		Seq:       7,
+	})
+	if err != nil {
+		t.Fatalf("auditBins() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"ts":          now.UnixMilli(),
+		"category":    "WRITE",
+		"operation":   "put_record",
+		"namespace":   "test",
+		"key":         "user:1",
+		"duration_ns": int64(1500000),
+		"success":     true,
+		"details":     `{"bins":["email"]}`,
+		"seq":         int64(7),
+	}
+	for name, value := range want {
+		if bins[name] != value {
+			t.Errorf("Expected bin %s=%v, got %v", name, value, bins[name])
+		}
+	}
+	for _, name := range []string{"set", "user", "error", "hash", "record_count"} {
+		if _, ok := bins[name]; ok {
+			t.Errorf("Expected empty field %s to be omitted", name)
+		}
+	}
+	for name := range bins {
+		if len(name) > 15 {
+			t.Errorf("Bin name %s exceeds 15 characters", name)
+		}
+	}
+}
+
+func TestAuditSetReadOnly(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleAdmin
+	cfg.Audit.Aerospike = &config.AuditAerospikeConfig{Namespace: "ops", Set: "mcp_audit"}
+	c := &Client{config: cfg}
+	ctx := context.Background()
+
+	checks := map[string]error{
+		"put":                c.PutRecord(ctx, "ops", "mcp_audit", "k", map[string]interface{}{"a": 1}, nil, 0),
+		"truncate set":       c.TruncateSet(ctx, "ops", "mcp_audit"),
+		"truncate namespace": c.TruncateSet(ctx, "ops", ""),
+	}
+	_, checks["delete"] = c.DeleteRecord(ctx, "ops", "mcp_audit", "k")
+	_, checks["udf"] = c.ExecuteUDF(ctx, "ops", "mcp_audit", "k", "m", "f", nil)
+
+	for name, err := range checks {
+		if !errors.Is(err, ErrAuditSetReadOnly) {
+			t.Errorf("%s: expected ErrAuditSetReadOnly, got %v", name, err)
+		}
+	}
+
+	if err := c.checkWritable("ops", "other"); err != nil {
+		t.Errorf("Expected other sets to be writable, got %v", err)
+	}
+}
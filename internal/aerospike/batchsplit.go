@@ -0,0 +1,127 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
+)
+
+// checkBatchSize refuses a batch call naming more keys than the config's
+// BatchLimit.
+func (c *Client) checkBatchSize(n int) error {
+	if limit := c.config.BatchLimit(); n > limit {
+		if c.config.BatchSplit.Enabled {
+			return fmt.Errorf("batch size %d exceeds batch_split.max_keys %d", n, limit)
+		}
+		return fmt.Errorf("batch size %d exceeds maximum %d", n, limit)
+	}
+	return nil
+}
+
+// batchChunks returns the bounds of consecutive chunks of at most size of
+// n items. A size of zero or less gives one chunk.
+func batchChunks(n, size int) [][2]int {
+	if size <= 0 || n <= size {
+		return [][2]int{{0, n}}
+	}
+	chunks := make([][2]int, 0, (n+size-1)/size)
+	for lo := 0; lo < n; lo += size {
+		chunks = append(chunks, [2]int{lo, min(lo+size, n)})
+	}
+	return chunks
+}
+
+// batchOperate sends records in one BatchOperate command, or, when
+// batch_split is enabled, in commands of at most max_batch_size records,
+// batch_split.concurrency at a time. It returns, for each record, the error
+// of the command that carried it, or nil if that command succeeded. The
+// records' own results are left in the records.
+func (c *Client) batchOperate(ctx context.Context, operation string, policy *as.BatchPolicy, records []as.BatchRecordIfc) []error {
+	size := 0
+	if c.config.BatchSplit.Enabled {
+		size = c.config.MaxBatchSize
+	}
+	chunks := batchChunks(len(records), size)
+
+	chunkErrs := runParallel(ctx, len(chunks), c.config.BatchSplit.Concurrency, 0, func(ctx context.Context, i int) error {
+		chunk := records[chunks[i][0]:chunks[i][1]]
+		start := time.Now()
+		err := c.conn().BatchOperate(policy, chunk)
+		c.observe(ctx, operation, start, err)
+		return err
+	})
+
+	errs := make([]error, len(records))
+	for i, chunk := range chunks {
+		if chunkErrs[i] == nil {
+			continue
+		}
+		err := chunkErrs[i]
+		if len(chunks) > 1 {
+			err = fmt.Errorf("keys %d-%d: %w", chunk[0], chunk[1]-1, err)
+		}
+		for j := chunk[0]; j < chunk[1]; j++ {
+			errs[j] = err
+		}
+	}
+	return errs
+}
+
+// batchOperateUntil sends records like batchOperate, but one command at a
+// time, in order, stopping after a command that failed or left a record
+// for which failed returns true. Within a command, each node stops at its
+// first failing record and the nodes are sent to one at a time. Records a
+// node skipped, and those of the commands not sent, get errNotAttempted.
+func (c *Client) batchOperateUntil(ctx context.Context, operation string, policy *as.BatchPolicy, records []as.BatchRecordIfc, failed func(i int) bool) []error {
+	policy.RespondAllKeys = false
+	policy.ConcurrentNodes = 1
+
+	size := 0
+	if c.config.BatchSplit.Enabled {
+		size = c.config.MaxBatchSize
+	}
+	chunks := batchChunks(len(records), size)
+
+	errs := make([]error, len(records))
+	var stopErr error
+	for _, chunk := range chunks {
+		if stopErr == nil {
+			stopErr = ctx.Err()
+		}
+		if stopErr != nil {
+			for j := chunk[0]; j < chunk[1]; j++ {
+				errs[j] = stopErr
+			}
+			continue
+		}
+
+		start := time.Now()
+		var err error = c.conn().BatchOperate(policy, records[chunk[0]:chunk[1]])
+		c.observe(ctx, operation, start, err)
+		if err != nil {
+			if len(chunks) > 1 {
+				err = fmt.Errorf("keys %d-%d: %w", chunk[0], chunk[1]-1, err)
+			}
+			for j := chunk[0]; j < chunk[1]; j++ {
+				errs[j] = err
+			}
+			stopErr = errNotAttempted
+			continue
+		}
+		for j := chunk[0]; j < chunk[1]; j++ {
+			if records[j].BatchRec().ResultCode == types.NO_RESPONSE {
+				errs[j] = errNotAttempted
+			}
+			if failed(j) {
+				stopErr = errNotAttempted
+			}
+		}
+	}
+	return errs
+}
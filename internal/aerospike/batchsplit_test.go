@@ -0,0 +1,53 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestBatchChunks(t *testing.T) {
+	tests := []struct {
+		n, size int
+		want    string
+	}{
+		{10, 0, "[[0 10]]"},
+		{10, 10, "[[0 10]]"},
+		{10, 4, "[[0 4] [4 8] [8 10]]"},
+		{12, 4, "[[0 4] [4 8] [8 12]]"},
+		{0, 4, "[[0 0]]"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprint(batchChunks(tt.n, tt.size)); got != tt.want {
+			t.Errorf("batchChunks(%d, %d) = %s, expected %s", tt.n, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBatchSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		split   config.BatchSplitConfig
+		size    int
+		wantErr bool
+	}{
+		{"within max_batch_size", config.BatchSplitConfig{}, 100, false},
+		{"over max_batch_size", config.BatchSplitConfig{}, 101, true},
+		{"split within max_keys", config.BatchSplitConfig{Enabled: true, MaxKeys: 500}, 500, false},
+		{"split over max_keys", config.BatchSplitConfig{Enabled: true, MaxKeys: 500}, 501, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{config: &config.Config{MaxBatchSize: 100, BatchSplit: tt.split}}
+			if err := c.checkBatchSize(tt.size); (err != nil) != tt.wantErr {
+				t.Errorf("checkBatchSize(%d) error = %v, wantErr %v", tt.size, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/metrics"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// ErrBenchmarkDisabled is returned by Benchmark when no benchmark set is
+// configured.
+var ErrBenchmarkDisabled = errors.New("benchmark is disabled: configure benchmark.set")
+
+const (
+	// benchmarkKeyPrefix prefixes the keys of the records a benchmark writes.
+	benchmarkKeyPrefix = "benchmark-"
+
+	// benchmarkCleanupTimeout bounds deleting a benchmark's records, which
+	// goes ahead after the call's own deadline.
+	benchmarkCleanupTimeout = 10 * time.Second
+)
+
+// BenchmarkOptions describe a benchmark workload.
+type BenchmarkOptions struct {
+	Operations  int     // operations measured
+	Concurrency int     // operations in flight at once
+	ReadRatio   float64 // share of operations that are reads, 0 to 1
+	RecordBytes int     // size of the bin written
+	Keys        int     // distinct records read and written
+}
+
+// BenchmarkResult reports a benchmark's throughput and the latency of its
+// reads and writes.
+type BenchmarkResult struct {
+	Namespace    string              `json:"namespace"`
+	Set          string              `json:"set"`
+	Operations   int                 `json:"operations"`
+	Reads        int                 `json:"reads"`
+	Writes       int                 `json:"writes"`
+	Errors       int                 `json:"errors"`
+	FirstError   string              `json:"first_error,omitempty"`
+	Incomplete   bool                `json:"incomplete,omitempty"` // stopped at the call's deadline
+	DurationMs   float64             `json:"duration_ms"`
+	Throughput   float64             `json:"throughput_ops_per_sec"`
+	ReadLatency  metrics.LatencyStat `json:"read_latency_ms"`
+	WriteLatency metrics.LatencyStat `json:"write_latency_ms"`
+}
+
+// benchmarkSample is the outcome of one benchmark operation.
+type benchmarkSample struct {
+	read    bool
+	latency time.Duration
+	err     error
+}
+
+// Benchmark runs a read/write workload against the configured benchmark
+// set and reports its latency percentiles and throughput. It first writes
+// the records the workload uses, and deletes them when it is done.
+func (c *Client) Benchmark(ctx context.Context, opts BenchmarkOptions) (*BenchmarkResult, error) {
+	bench := c.config.Benchmark
+	if bench.Set == "" {
+		return nil, ErrBenchmarkDisabled
+	}
+	if role := c.config.NamespaceRole(ctx, bench.Namespace); !role.CanAdmin() {
+		return nil, &config.RoleError{Operation: "admin operations", Role: role}
+	}
+	if err := c.checkWritable(bench.Namespace, bench.Set); err != nil {
+		return nil, err
+	}
+	if err := checkBenchmarkOptions(opts, bench); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*as.Key, opts.Keys)
+	for i := range keys {
+		value := benchmarkKeyPrefix + strconv.Itoa(i)
+		if err := c.checkKey(ctx, bench.Namespace, bench.Set, value); err != nil {
+			return nil, err
+		}
+		key, err := as.NewKey(bench.Namespace, bench.Set, value)
+		if err != nil {
+			return nil, fmt.Errorf("creating key: %w", err)
+		}
+		keys[i] = key
+	}
+	bins := as.BinMap{"data": make([]byte, opts.RecordBytes)}
+
+	// Write every record first, so reads find them
+	for _, key := range keys {
+		if err := c.conn().Put(c.writePolicyFor(ctx, bench.Namespace), key, bins); err != nil {
+			return nil, fmt.Errorf("preparing benchmark records: %w", err)
+		}
+	}
+	defer c.deleteBenchmarkRecords(ctx, bench.Namespace, keys)
+
+	samples := make([]benchmarkSample, opts.Operations)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(seed))
+			for ctx.Err() == nil {
+				i := int(next.Add(1)) - 1
+				if i >= len(samples) {
+					return
+				}
+				key := keys[random.Intn(len(keys))]
+				read := random.Float64() < opts.ReadRatio
+
+				opStart := time.Now()
+				var err error
+				if read {
+					_, err = c.conn().Get(c.readPolicyFor(ctx, bench.Namespace), key)
+				} else {
+					err = c.conn().Put(c.writePolicyFor(ctx, bench.Namespace), key, bins)
+				}
+				samples[i] = benchmarkSample{read: read, latency: time.Since(opStart), err: err}
+			}
+		}(start.UnixNano() + int64(w))
+	}
+	wg.Wait()
+
+	completed := min(int(next.Load()), len(samples))
+	result := summarizeBenchmark(samples[:completed], time.Since(start))
+	result.Namespace, result.Set = bench.Namespace, bench.Set
+	result.Incomplete = completed < len(samples)
+	return result, nil
+}
+
+// checkBenchmarkOptions checks a workload against the configured limits.
+func checkBenchmarkOptions(opts BenchmarkOptions, bench config.BenchmarkConfig) error {
+	switch {
+	case opts.Operations < 1 || opts.Operations > bench.MaxOperations:
+		return fmt.Errorf("operations must be between 1 and %d", bench.MaxOperations)
+	case opts.Concurrency < 1 || opts.Concurrency > bench.MaxConcurrency:
+		return fmt.Errorf("concurrency must be between 1 and %d", bench.MaxConcurrency)
+	case math.IsNaN(opts.ReadRatio) || opts.ReadRatio < 0 || opts.ReadRatio > 1:
+		return fmt.Errorf("read_ratio must be between 0 and 1")
+	case opts.RecordBytes < 1 || opts.RecordBytes > 1<<20:
+		return fmt.Errorf("record_bytes must be between 1 and %d", 1<<20)
+	case opts.Keys < 1 || opts.Keys > opts.Operations:
+		return fmt.Errorf("keys must be between 1 and operations")
+	}
+	return nil
+}
+
+// summarizeBenchmark computes a benchmark's result from its operations.
+// Failed operations count towards throughput but not latency.
+func summarizeBenchmark(samples []benchmarkSample, elapsed time.Duration) *BenchmarkResult {
+	result := &BenchmarkResult{
+		Operations: len(samples),
+		DurationMs: float64(elapsed.Microseconds()) / 1000,
+	}
+	if elapsed > 0 {
+		result.Throughput = math.Round(float64(len(samples))/elapsed.Seconds()*10) / 10
+	}
+
+	var reads, writes []time.Duration
+	for _, sample := range samples {
+		if sample.read {
+			result.Reads++
+		} else {
+			result.Writes++
+		}
+		if sample.err != nil {
+			if result.Errors == 0 {
+				result.FirstError = sample.err.Error()
+			}
+			result.Errors++
+			continue
+		}
+		if sample.read {
+			reads = append(reads, sample.latency)
+		} else {
+			writes = append(writes, sample.latency)
+		}
+	}
+	result.ReadLatency = metrics.Latencies(reads)
+	result.WriteLatency = metrics.Latencies(writes)
+	return result
+}
+
+// deleteBenchmarkRecords removes the records a benchmark wrote. Failures
+// are ignored: the records hold no data and are overwritten by the next
+// benchmark.
+func (c *Client) deleteBenchmarkRecords(ctx context.Context, namespace string, keys []*as.Key) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), benchmarkCleanupTimeout)
+	defer cancel()
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return
+		}
+		_, _ = c.conn().Delete(c.writePolicyFor(ctx, namespace), key)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestCheckBenchmarkOptions(t *testing.T) {
+	bench := config.BenchmarkConfig{Namespace: "test", Set: "bench", MaxOperations: 1000, MaxConcurrency: 8}
+	valid := BenchmarkOptions{Operations: 500, Concurrency: 4, ReadRatio: 0.5, RecordBytes: 100, Keys: 50}
+
+	tests := []struct {
+		name    string
+		modify  func(*BenchmarkOptions)
+		wantErr bool
+	}{
+		{"valid", func(o *BenchmarkOptions) {}, false},
+		{"write only", func(o *BenchmarkOptions) { o.ReadRatio = 0 }, false},
+		{"too many operations", func(o *BenchmarkOptions) { o.Operations = 1001 }, true},
+		{"no concurrency", func(o *BenchmarkOptions) { o.Concurrency = 0 }, true},
+		{"too much concurrency", func(o *BenchmarkOptions) { o.Concurrency = 9 }, true},
+		{"read ratio above 1", func(o *BenchmarkOptions) { o.ReadRatio = 1.5 }, true},
+		{"read ratio NaN", func(o *BenchmarkOptions) { o.ReadRatio = math.NaN() }, true},
+		{"empty records", func(o *BenchmarkOptions) { o.RecordBytes = 0 }, true},
+		{"more keys than operations", func(o *BenchmarkOptions) { o.Keys = 501 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+			if err := checkBenchmarkOptions(opts, bench); (err != nil) != tt.wantErr {
+				t.Errorf("checkBenchmarkOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSummarizeBenchmark(t *testing.T) {
+	samples := []benchmarkSample{
+		{read: true, latency: 1 * time.Millisecond},
+		{read: true, latency: 3 * time.Millisecond},
+		{read: false, latency: 2 * time.Millisecond},
+		{read: false, latency: 50 * time.Millisecond, err: errors.New("timeout")},
+	}
+
+	result := summarizeBenchmark(samples, 2*time.Second)
+	if result.Operations != 4 || result.Reads != 2 || result.Writes != 2 || result.Errors != 1 {
+		t.Errorf("Expected 4 operations, 2 reads, 2 writes, and 1 error, got %+v", result)
+	}
+	if result.FirstError != "timeout" {
+		t.Errorf("Expected the first error, got %q", result.FirstError)
+	}
+	if result.Throughput != 2 {
+		t.Errorf("Expected 2 ops/sec, got %v", result.Throughput)
+	}
+	if result.ReadLatency.P99 != 3 || result.WriteLatency.Max != 2 {
+		t.Errorf("Expected failed operations left out of latency, got reads %+v and writes %+v",
+			result.ReadLatency, result.WriteLatency)
+	}
+}
+
+func TestBenchmarkDisabled(t *testing.T) {
+	c := &Client{config: &config.Config{Role: config.RoleAdmin}}
+	if _, err := c.Benchmark(context.Background(), BenchmarkOptions{}); !errors.Is(err, ErrBenchmarkDisabled) {
+		t.Errorf("Expected ErrBenchmarkDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// BinProfile describes the values a bin holds in sampled records, without
+// the values themselves: how many records have it, how many distinct
+// values there are, and, for lists and maps, how many elements they hold.
+// TopValueShare is the fraction of the records with the bin that hold its
+// most common value. Value sizes are as JSON.
+type BinProfile struct {
+	Bin               string   `json:"bin"`
+	Sampled           int      `json:"records_sampled"`
+	Present           int      `json:"records_with_bin"`
+	Types             []string `json:"types"`
+	Distinct          int      `json:"distinct_values"`
+	TopValueShare     float64  `json:"top_value_share"`
+	AvgValueBytes     float64  `json:"avg_value_bytes"`
+	AvgElements       float64  `json:"avg_elements,omitempty"`
+	MaxElements       int      `json:"max_elements,omitempty"`
+	DistinctElements  int      `json:"distinct_list_elements,omitempty"`
+	DistinctMapKeys   int      `json:"distinct_map_keys,omitempty"`
+	DistinctMapValues int      `json:"distinct_map_values,omitempty"`
+}
+
+// ProfileBin profiles bin over records.
+func ProfileBin(records []*Record, bin string) *BinProfile {
+	p := &BinProfile{Bin: bin, Sampled: len(records), Types: []string{}}
+	types := make(map[string]bool)
+	values := make(map[string]int)
+	elements := make(map[string]bool)
+	mapKeys := make(map[string]bool)
+	mapValues := make(map[string]bool)
+	var valueBytes, elementCount, collections int
+
+	for _, record := range records {
+		value, ok := record.Bins[bin]
+		if !ok {
+			continue
+		}
+		p.Present++
+		data, _ := json.Marshal(value)
+		values[string(data)]++
+		valueBytes += len(data)
+
+		switch kind, items := collectionItems(value); kind {
+		case "list":
+			types[kind] = true
+			collections++
+			elementCount += len(items)
+			p.MaxElements = max(p.MaxElements, len(items))
+			for _, item := range items {
+				elements[jsonKey(item)] = true
+			}
+		case "map":
+			types[kind] = true
+			collections++
+			elementCount += len(items) / 2
+			p.MaxElements = max(p.MaxElements, len(items)/2)
+			for i := 0; i+1 < len(items); i += 2 {
+				mapKeys[jsonKey(items[i])] = true
+				mapValues[jsonKey(items[i+1])] = true
+			}
+		default:
+			types[getTypeName(value)] = true
+		}
+	}
+
+	for t := range types {
+		p.Types = append(p.Types, t)
+	}
+	sort.Strings(p.Types)
+	p.Distinct = len(values)
+	p.DistinctElements, p.DistinctMapKeys, p.DistinctMapValues = len(elements), len(mapKeys), len(mapValues)
+	if p.Present > 0 {
+		top := 0
+		for _, n := range values {
+			top = max(top, n)
+		}
+		p.TopValueShare = float64(top) / float64(p.Present)
+		p.AvgValueBytes = float64(valueBytes) / float64(p.Present)
+	}
+	if collections > 0 {
+		p.AvgElements = float64(elementCount) / float64(collections)
+	}
+	return p
+}
+
+// collectionItems returns "list" and a list's elements, or "map" and a
+// map's keys and values in turn, for a value as records hold it, where
+// maps without string keys are given as [key, value] pairs under a hint.
+func collectionItems(v interface{}) (string, []interface{}) {
+	switch val := v.(type) {
+	case []interface{}:
+		return "list", val
+	case map[string]interface{}:
+		if len(val) == 1 {
+			for _, hint := range []string{mapHint, orderedMapHint} {
+				if pairs, ok := val[hint].([]interface{}); ok {
+					items := make([]interface{}, 0, 2*len(pairs))
+					for _, pair := range pairs {
+						if kv, ok := pair.([]interface{}); ok && len(kv) == 2 {
+							items = append(items, kv[0], kv[1])
+						}
+					}
+					return "map", items
+				}
+			}
+			if _, ok := val[bytesHint]; ok {
+				return "", nil
+			}
+		}
+		items := make([]interface{}, 0, 2*len(val))
+		for k, item := range val {
+			items = append(items, k, item)
+		}
+		return "map", items
+	}
+	return "", nil
+}
+
+// jsonKey returns v as JSON, to count distinct values.
+func jsonKey(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfileBin(t *testing.T) {
+	records := []*Record{
+		{Bins: map[string]interface{}{"status": "active", "tags": []interface{}{"a", "b"}, "attrs": map[string]interface{}{"x": int64(1)}}},
+		{Bins: map[string]interface{}{"status": "active", "tags": []interface{}{"b", "c", "d"}, "attrs": map[string]interface{}{mapHint: []interface{}{[]interface{}{int64(7), "y"}, []interface{}{int64(8), int64(1)}}}}},
+		{Bins: map[string]interface{}{"status": "closed", "tags": "none"}},
+		{Bins: map[string]interface{}{"blob": map[string]interface{}{bytesHint: "AAE="}}},
+	}
+
+	tests := []struct {
+		bin  string
+		want BinProfile
+	}{
+		{"status", BinProfile{Bin: "status", Sampled: 4, Present: 3, Types: []string{"string"}, Distinct: 2, TopValueShare: 2.0 / 3, AvgValueBytes: 8}},
+		{"tags", BinProfile{Bin: "tags", Sampled: 4, Present: 3, Types: []string{"list", "string"}, Distinct: 3, TopValueShare: 1.0 / 3, AvgValueBytes: 28.0 / 3, AvgElements: 2.5, MaxElements: 3, DistinctElements: 4}},
+		{"attrs", BinProfile{Bin: "attrs", Sampled: 4, Present: 2, Types: []string{"map"}, Distinct: 2, TopValueShare: 0.5, AvgValueBytes: 15.5, AvgElements: 1.5, MaxElements: 2, DistinctMapKeys: 3, DistinctMapValues: 2}},
+		{"blob", BinProfile{Bin: "blob", Sampled: 4, Present: 1, Types: []string{"map"}, Distinct: 1, TopValueShare: 1, AvgValueBytes: 17}},
+		{"missing", BinProfile{Bin: "missing", Sampled: 4, Types: []string{}}},
+	}
+
+	for _, tt := range tests {
+		if got := ProfileBin(records, tt.bin); !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("ProfileBin(%s):\nexpected %+v\ngot      %+v", tt.bin, tt.want, *got)
+		}
+	}
+}
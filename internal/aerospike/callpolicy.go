@@ -0,0 +1,95 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// maxCallRetries caps the retries a call policy may ask for.
+const maxCallRetries = 10
+
+// CallPolicy overrides the configured policies for one tool call's reads
+// and writes. Unset fields keep the configured values. The timeout replaces
+// the configured and namespace timeouts, but the call's own time limit
+// still applies.
+type CallPolicy struct {
+	TimeoutMs     *int   `json:"timeout_ms,omitempty"`
+	MaxRetries    *int   `json:"max_retries,omitempty"`
+	ReadMode      string `json:"read_mode,omitempty"` // an AP or a strong consistency read mode
+	DurableDelete *bool  `json:"durable_delete,omitempty"`
+	SendKey       *bool  `json:"send_key,omitempty"`
+}
+
+// Validate returns an error if a field is out of range or names an unknown
+// read mode.
+func (p CallPolicy) Validate() error {
+	if p.TimeoutMs != nil && *p.TimeoutMs <= 0 {
+		return fmt.Errorf("invalid policy.timeout_ms: %d (must be positive)", *p.TimeoutMs)
+	}
+	if p.MaxRetries != nil && (*p.MaxRetries < 0 || *p.MaxRetries > maxCallRetries) {
+		return fmt.Errorf("invalid policy.max_retries: %d (must be between 0 and %d)", *p.MaxRetries, maxCallRetries)
+	}
+	if p.ReadMode != "" {
+		_, ap := readModeAP(p.ReadMode)
+		_, sc := readModeSC(p.ReadMode)
+		if !ap && !sc {
+			return fmt.Errorf("invalid policy.read_mode: %s (must be one, all, session, linearize, allow_replica, or allow_unavailable)", p.ReadMode)
+		}
+	}
+	return nil
+}
+
+// callPolicyContextKey is the context key for a call's policy overrides.
+type callPolicyContextKey struct{}
+
+// WithCallPolicy returns a context whose reads and writes use p, which must
+// be valid.
+func WithCallPolicy(ctx context.Context, p CallPolicy) context.Context {
+	if p == (CallPolicy{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, callPolicyContextKey{}, p)
+}
+
+// applyCallPolicy sets the timeout, retries, and read mode in ctx's call
+// policy on policy.
+func applyCallPolicy(ctx context.Context, policy *as.BasePolicy) {
+	p, ok := ctx.Value(callPolicyContextKey{}).(CallPolicy)
+	if !ok {
+		return
+	}
+	if p.TimeoutMs != nil {
+		policy.TotalTimeout = time.Duration(*p.TimeoutMs) * time.Millisecond
+		if policy.SocketTimeout > policy.TotalTimeout {
+			policy.SocketTimeout = policy.TotalTimeout
+		}
+	}
+	if p.MaxRetries != nil {
+		policy.MaxRetries = *p.MaxRetries
+	}
+	if mode, ok := readModeAP(p.ReadMode); ok {
+		policy.ReadModeAP = mode
+	}
+	if mode, ok := readModeSC(p.ReadMode); ok {
+		policy.ReadModeSC = mode
+	}
+}
+
+// writeOptions returns the durable delete and send key settings for a
+// write: ctx's call policy's, or durableDelete and sendKey.
+func writeOptions(ctx context.Context, durableDelete, sendKey bool) (bool, bool) {
+	p, _ := ctx.Value(callPolicyContextKey{}).(CallPolicy)
+	if p.DurableDelete != nil {
+		durableDelete = *p.DurableDelete
+	}
+	if p.SendKey != nil {
+		sendKey = *p.SendKey
+	}
+	return durableDelete, sendKey
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestCallPolicyPolicies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	c := newClient(cfg)
+
+	timeout, retries, yes := 250, 0, true
+	ctx := WithCallPolicy(context.Background(), CallPolicy{
+		TimeoutMs: &timeout, MaxRetries: &retries, ReadMode: "all", DurableDelete: &yes, SendKey: &yes,
+	})
+
+	read := c.readPolicyFor(ctx, "test")
+	if read.TotalTimeout != 250*time.Millisecond || read.MaxRetries != 0 || read.ReadModeAP != as.ReadModeAPAll {
+		t.Errorf("Expected overridden read policy, got %v/%d/%v", read.TotalTimeout, read.MaxRetries, read.ReadModeAP)
+	}
+	if read.SocketTimeout > read.TotalTimeout {
+		t.Errorf("Expected the socket timeout within the total timeout, got %v", read.SocketTimeout)
+	}
+	write := c.writePolicyFor(ctx, "test")
+	if write.TotalTimeout != 250*time.Millisecond || !write.DurableDelete || !write.SendKey {
+		t.Errorf("Expected overridden write policy, got %v/%v/%v", write.TotalTimeout, write.DurableDelete, write.SendKey)
+	}
+	if batch := c.batchPolicyFor(ctx, "test"); batch.MaxRetries != 0 {
+		t.Errorf("Expected overridden batch policy, got %d retries", batch.MaxRetries)
+	}
+
+	// The call's deadline still limits the timeout
+	deadline, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if read := c.readPolicyFor(deadline, "test"); read.TotalTimeout > 50*time.Millisecond {
+		t.Errorf("Expected the deadline to cap the timeout, got %v", read.TotalTimeout)
+	}
+
+	// The configured policies are unchanged
+	if c.writePolicy.DurableDelete || c.readPolicy.MaxRetries != cfg.MaxRetries {
+		t.Error("Expected the configured policies to be unchanged")
+	}
+	if read := c.readPolicyFor(context.Background(), "test"); read.MaxRetries != cfg.MaxRetries {
+		t.Errorf("Expected the configured retries without a call policy, got %d", read.MaxRetries)
+	}
+}
+
+func TestCallPolicyReadModeSC(t *testing.T) {
+	c := newClient(config.DefaultConfig())
+	ctx := WithCallPolicy(context.Background(), CallPolicy{ReadMode: "linearize"})
+	if policy := c.readPolicyFor(ctx, "test"); policy.ReadModeSC != as.ReadModeSCLinearize || policy.ReadModeAP != as.ReadModeAPOne {
+		t.Errorf("Expected a strong consistency read mode, got %v/%v", policy.ReadModeAP, policy.ReadModeSC)
+	}
+}
+
+func TestSendKey(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SendKey = true
+	c := newClient(cfg)
+
+	if policy := c.writePolicyFor(context.Background(), "test"); !policy.SendKey {
+		t.Error("Expected writes to send the key when send_key is configured")
+	}
+	no := false
+	ctx := WithCallPolicy(context.Background(), CallPolicy{SendKey: &no})
+	if policy := c.writePolicyFor(ctx, "test"); policy.SendKey {
+		t.Error("Expected the call policy to override send_key")
+	}
+	if durable, sendKey := writeOptions(context.Background(), false, cfg.SendKey); durable || !sendKey {
+		t.Errorf("Expected batch writes to send the key, got durable=%v send_key=%v", durable, sendKey)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// Bin values JSON cannot represent directly are returned wrapped in a hint,
+// and accepted the same way, so a record read from the cluster can be
+// written back unchanged:
+//
+//	{"$bytes": "<base64>"}                    a byte array
+//	{"$map": [[1, "a"], [2, "b"]]}            a map with keys other than strings
+//	{"$ordered_map": [["a", 1], ["b", 2]]}    a key-ordered map
+//
+// Maps whose keys are all strings are plain JSON objects, unless the object
+// would itself look like a hint.
+const (
+	bytesHint      = "$bytes"
+	mapHint        = "$map"
+	orderedMapHint = "$ordered_map"
+)
+
+// hints are the keys that make a single-key JSON object a type hint.
+var hints = map[string]bool{
+	floatHint: true, intHint: true, geoJSONHint: true,
+	bytesHint: true, mapHint: true, orderedMapHint: true,
+}
+
+// orderedMap is a bin value to be written as a key-ordered map.
+type orderedMap map[interface{}]interface{}
+
+// cdtHint returns the value of a bytes, map, or ordered map hint.
+func cdtHint(name string, raw interface{}, preserveFloat bool) (interface{}, error) {
+	if name == bytesHint {
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s needs a base64 string, got %T", bytesHint, raw)
+		}
+		data, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", bytesHint, err)
+		}
+		return data, nil
+	}
+
+	pairs, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s needs a list of [key, value] pairs, got %T", name, raw)
+	}
+	m := make(map[interface{}]interface{}, len(pairs))
+	for i, item := range pairs {
+		pair, ok := item.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("%s[%d] must be a [key, value] pair", name, i)
+		}
+		key, err := mapKey(pair[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+		}
+		value, err := normalizeNested(pair[1], preserveFloat)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", name, i, err)
+		}
+		m[key] = value
+	}
+	if name == orderedMapHint {
+		return orderedMap(m), nil
+	}
+	return m, nil
+}
+
+// mapKey converts a map key decoded from JSON to a string or an integer.
+func mapKey(v interface{}) (interface{}, error) {
+	if hint, ok := v.(map[string]interface{}); ok && len(hint) == 1 {
+		if raw, ok := hint[intHint]; ok {
+			v = raw
+		}
+	}
+	switch key := v.(type) {
+	case string:
+		return key, nil
+	case float64:
+		if n, ok := wholeNumber(key); ok {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("map keys must be strings or integers, got %v", v)
+}
+
+// encodeBins replaces the bin values read from the cluster that JSON cannot
+// represent with their hints.
+func encodeBins(bins map[string]interface{}) map[string]interface{} {
+	for name, value := range bins {
+		bins[name] = encodeValue(value)
+	}
+	return bins
+}
+
+// encodeValue returns v, or the hint standing for it if JSON cannot
+// represent it, with the values v holds encoded the same way.
+func encodeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		return map[string]interface{}{bytesHint: base64.StdEncoding.EncodeToString(val)}
+	case as.GeoJSONValue:
+		return geoJSONTag(val)
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, item := range val {
+			list[i] = encodeValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		if len(val) == 1 {
+			for k := range val {
+				if hints[k] {
+					return map[string]interface{}{mapHint: encodePairs(stringKeyed(val))}
+				}
+			}
+		}
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			m[k] = encodeValue(item)
+		}
+		return m
+	case map[interface{}]interface{}:
+		if m, ok := stringKeys(val); ok {
+			return encodeValue(m)
+		}
+		return map[string]interface{}{mapHint: encodePairs(val)}
+	case orderedMap:
+		return map[string]interface{}{orderedMapHint: encodePairs(val)}
+	case []as.MapPair:
+		pairs := make([]interface{}, len(val))
+		for i, pair := range val {
+			pairs[i] = []interface{}{pair.Key, encodeValue(pair.Value)}
+		}
+		return map[string]interface{}{orderedMapHint: pairs}
+	default:
+		return v
+	}
+}
+
+// stringKeys returns m with string keys, or false if some key is not a
+// string.
+func stringKeys(m map[interface{}]interface{}) (map[string]interface{}, bool) {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		s, ok := k.(string)
+		if !ok {
+			return nil, false
+		}
+		out[s] = v
+	}
+	return out, true
+}
+
+// stringKeyed returns m with interface keys.
+func stringKeyed(m map[string]interface{}) map[interface{}]interface{} {
+	out := make(map[interface{}]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// encodePairs returns a map's entries as [key, value] pairs, ordered by key
+// so the output is stable: integers before strings, as Aerospike orders
+// them.
+func encodePairs(m map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keyLess(keys[i], keys[j]) })
+
+	pairs := make([]interface{}, len(keys))
+	for i, k := range keys {
+		pairs[i] = []interface{}{k, encodeValue(m[k])}
+	}
+	return pairs
+}
+
+// keyLess orders map keys: integers, then floats, then strings, then any
+// other type by its printed form.
+func keyLess(a, b interface{}) bool {
+	ra, rb := keyRank(a), keyRank(b)
+	if ra != rb {
+		return ra < rb
+	}
+	switch x := a.(type) {
+	case string:
+		return x < b.(string)
+	case float64:
+		return x < b.(float64)
+	}
+	if ra == 0 {
+		x, _ := toInt64(a)
+		y, _ := toInt64(b)
+		return x < y
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// keyRank returns the position of a key's type in the key order.
+func keyRank(k interface{}) int {
+	switch k.(type) {
+	case int, int32, int64:
+		return 0
+	case float64:
+		return 1
+	case string:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// binOperations returns the operations writing bins: a put of each value,
+// except that an ordered map is written with a key-ordered map policy,
+// after removing the bin's current value.
+func binOperations(bins map[string]interface{}) []*as.Operation {
+	ops := make([]*as.Operation, 0, len(bins))
+	for name, value := range bins {
+		m, ordered := value.(orderedMap)
+		if !ordered {
+			ops = append(ops, as.PutOp(as.NewBin(name, value)))
+			continue
+		}
+		policy := as.NewMapPolicyWithFlags(as.MapOrder.KEY_ORDERED, as.MapWriteFlagsDefault)
+		ops = append(ops,
+			as.PutOp(as.NewBin(name, nil)),
+			as.MapPutItemsOp(policy, name, map[interface{}]interface{}(m)))
+	}
+	return ops
+}
+
+// hasOrderedMap returns true if a bin's value is an ordered map, which a
+// plain put cannot write.
+func hasOrderedMap(bins map[string]interface{}) bool {
+	for _, value := range bins {
+		if _, ok := value.(orderedMap); ok {
+			return true
+		}
+	}
+	return false
+}
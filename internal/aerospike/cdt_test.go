@@ -0,0 +1,114 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+func TestEncodeValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"string-keyed map", map[interface{}]interface{}{"b": 2, "a": 1}, `{"a":1,"b":2}`},
+		{"integer-keyed map", map[interface{}]interface{}{10: "x", 2: "y"}, `{"$map":[[2,"y"],[10,"x"]]}`},
+		{"mixed keys", map[interface{}]interface{}{"a": 1, 5: 2}, `{"$map":[[5,2],["a",1]]}`},
+		{"bytes", []byte{1, 2, 3}, `{"$bytes":"AQID"}`},
+		{"ordered map", []as.MapPair{{Key: "z", Value: 1}, {Key: "a", Value: []byte{0}}}, `{"$ordered_map":[["z",1],["a",{"$bytes":"AA=="}]]}`},
+		{"nested", []interface{}{map[interface{}]interface{}{1: []byte{255}}}, `[{"$map":[[1,{"$bytes":"/w=="}]]}]`},
+		{"map that looks like a hint", map[interface{}]interface{}{"$bytes": "AQID"}, `{"$map":[["$bytes","AQID"]]}`},
+		{"scalar", 42, `42`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(encodeValue(tt.value))
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, data)
+			}
+		})
+	}
+}
+
+func TestCDTRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{} // as read from the cluster
+		want  interface{} // as written back
+	}{
+		{"integer-keyed map", map[interface{}]interface{}{1: "a", 2: int64(3)}, map[interface{}]interface{}{int64(1): "a", int64(2): int64(3)}},
+		{"bytes", []byte("raw"), []byte("raw")},
+		{"ordered map", []as.MapPair{{Key: "b", Value: 1}, {Key: "a", Value: 2}}, orderedMap{"b": int64(1), "a": int64(2)}},
+		{"list of bytes", []interface{}{[]byte{1}, "s"}, []interface{}{[]byte{1}, "s"}},
+		{"map that looks like a hint", map[interface{}]interface{}{"$int": "x"}, map[interface{}]interface{}{"$int": "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(encodeValue(tt.value))
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var decoded interface{}
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			got, err := normalizeBinValue(decoded, false)
+			if err != nil {
+				t.Fatalf("normalizeBinValue(%s) error = %v", data, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %#v, got %#v (from %s)", tt.want, got, data)
+			}
+		})
+	}
+}
+
+func TestCDTHintErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"bytes not base64", `{"$bytes": "not base64!"}`},
+		{"map not pairs", `{"$map": {"a": 1}}`},
+		{"map pair too short", `{"$map": [[1]]}`},
+		{"fractional map key", `{"$map": [[1.5, "a"]]}`},
+		{"nested ordered map", `[{"$ordered_map": [["a", 1]]}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(tt.value), &v); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if _, err := normalizeBinValue(v, false); err == nil {
+				t.Errorf("Expected %s to be rejected", tt.value)
+			}
+		})
+	}
+}
+
+func TestBinOperations(t *testing.T) {
+	bins := map[string]interface{}{"name": "alice", "scores": orderedMap{"a": int64(1)}}
+	if !hasOrderedMap(bins) {
+		t.Error("Expected an ordered map to be found")
+	}
+	// A put of name, and a removal and map write of scores
+	if ops := binOperations(bins); len(ops) != 3 {
+		t.Errorf("Expected 3 operations, got %d", len(ops))
+	}
+	if hasOrderedMap(map[string]interface{}{"m": map[interface{}]interface{}{1: 2}}) {
+		t.Error("Expected an unordered map not to need operations")
+	}
+}
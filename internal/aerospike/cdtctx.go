@@ -0,0 +1,153 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"fmt"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// CDT operation types. They act on a list or map bin, or with a context on
+// a list or map nested inside it, without transferring the whole bin.
+const (
+	OpListAppend   OperationType = "list_append"
+	OpListGet      OperationType = "list_get"
+	OpListRemove   OperationType = "list_remove"
+	OpListSize     OperationType = "list_size"
+	OpMapPut       OperationType = "map_put"
+	OpMapGet       OperationType = "map_get"
+	OpMapRemove    OperationType = "map_remove"
+	OpMapIncrement OperationType = "map_increment"
+	OpMapSize      OperationType = "map_size"
+)
+
+// cdtWrites are the CDT operation types that modify the bin.
+var cdtWrites = map[OperationType]bool{
+	OpListAppend: true, OpListRemove: true,
+	OpMapPut: true, OpMapRemove: true, OpMapIncrement: true,
+}
+
+// isCDTOperation returns true if t is a CDT operation type.
+func isCDTOperation(t OperationType) bool {
+	switch t {
+	case OpListGet, OpListSize, OpMapGet, OpMapSize:
+		return true
+	}
+	return cdtWrites[t]
+}
+
+// CDTContextStep is one step of a path into the lists and maps nested in a
+// bin. Type is list_index, list_rank, list_value, map_index, map_rank,
+// map_key, or map_value; Value is the index, rank, value, or key selecting
+// the element to descend into. Negative indexes and ranks count from the
+// end.
+type CDTContextStep struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// buildCDTContext converts a path to the client's context.
+func buildCDTContext(steps []CDTContextStep) ([]*as.CDTContext, error) {
+	if len(steps) == 0 {
+		return nil, nil
+	}
+
+	ctx := make([]*as.CDTContext, len(steps))
+	for i, step := range steps {
+		var err error
+		switch step.Type {
+		case "list_index", "list_rank", "map_index", "map_rank":
+			n, ok := wholeNumber(step.Value)
+			if !ok {
+				err = fmt.Errorf("%s needs an integer value, got %v", step.Type, step.Value)
+				break
+			}
+			switch step.Type {
+			case "list_index":
+				ctx[i] = as.CtxListIndex(int(n))
+			case "list_rank":
+				ctx[i] = as.CtxListRank(int(n))
+			case "map_index":
+				ctx[i] = as.CtxMapIndex(int(n))
+			default:
+				ctx[i] = as.CtxMapRank(int(n))
+			}
+		case "map_key":
+			var key interface{}
+			if key, err = mapKey(step.Value); err == nil {
+				ctx[i] = as.CtxMapKey(as.NewValue(key))
+			}
+		case "list_value", "map_value":
+			var value interface{}
+			if value, err = normalizeNested(step.Value, false); err == nil {
+				if step.Type == "list_value" {
+					ctx[i] = as.CtxListValue(as.NewValue(value))
+				} else {
+					ctx[i] = as.CtxMapValue(as.NewValue(value))
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown type %q (must be list_index, list_rank, list_value, map_index, map_rank, map_key, or map_value)", step.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ctx[%d]: %w", i, err)
+		}
+	}
+	return ctx, nil
+}
+
+// buildCDTOperation converts a CDT operation request.
+func buildCDTOperation(op OperateRequest) (*as.Operation, error) {
+	ctx, err := buildCDTContext(op.Ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s on bin %s: %w", op.Type, op.BinName, err)
+	}
+
+	var value, key interface{}
+	if op.Value != nil {
+		if value, err = normalizeNested(op.Value, false); err != nil {
+			return nil, fmt.Errorf("%s value for bin %s: %w", op.Type, op.BinName, err)
+		}
+	}
+	switch op.Type {
+	case OpMapPut, OpMapGet, OpMapRemove, OpMapIncrement:
+		if key, err = mapKey(op.Key); err != nil {
+			return nil, fmt.Errorf("%s key for bin %s: %w", op.Type, op.BinName, err)
+		}
+	case OpListGet, OpListRemove:
+		if op.Index == nil {
+			return nil, fmt.Errorf("%s requires an index for bin %s", op.Type, op.BinName)
+		}
+	}
+
+	switch op.Type {
+	case OpListAppend:
+		if op.Value == nil {
+			return nil, fmt.Errorf("list_append requires a value for bin %s", op.BinName)
+		}
+		return as.ListAppendWithPolicyContextOp(as.DefaultListPolicy(), op.BinName, ctx, value), nil
+	case OpListGet:
+		return as.ListGetOp(op.BinName, *op.Index, ctx...), nil
+	case OpListRemove:
+		return as.ListRemoveOp(op.BinName, *op.Index, ctx...), nil
+	case OpListSize:
+		return as.ListSizeOp(op.BinName, ctx...), nil
+	case OpMapPut:
+		return as.MapPutOp(as.DefaultMapPolicy(), op.BinName, key, value, ctx...), nil
+	case OpMapGet:
+		return as.MapGetByKeyOp(op.BinName, key, as.MapReturnType.VALUE, ctx...), nil
+	case OpMapRemove:
+		return as.MapRemoveByKeyOp(op.BinName, key, as.MapReturnType.NONE, ctx...), nil
+	case OpMapIncrement:
+		if _, ok := value.(int64); !ok {
+			if _, ok := value.(float64); !ok {
+				return nil, fmt.Errorf("map_increment requires a numeric value for bin %s", op.BinName)
+			}
+		}
+		return as.MapIncrementOp(as.DefaultMapPolicy(), op.BinName, key, value, ctx...), nil
+	default:
+		return as.MapSizeOp(op.BinName, ctx...), nil
+	}
+}
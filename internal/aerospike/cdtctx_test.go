@@ -0,0 +1,91 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildCDTContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    int
+		wantErr string
+	}{
+		{name: "none", path: `[]`},
+		{name: "map to list to map", path: `[{"type":"map_key","value":"orders"},{"type":"list_index","value":-1},{"type":"map_key","value":"items"}]`, want: 3},
+		{name: "ranks and values", path: `[{"type":"list_rank","value":0},{"type":"map_rank","value":-1},{"type":"list_value","value":"a"},{"type":"map_value","value":3},{"type":"map_index","value":2}]`, want: 5},
+		{name: "integer map key", path: `[{"type":"map_key","value":7}]`, want: 1},
+		{name: "fractional index", path: `[{"type":"list_index","value":1.5}]`, wantErr: "ctx[0]: list_index needs an integer"},
+		{name: "unknown step", path: `[{"type":"map_key","value":"a"},{"type":"set_member","value":1}]`, wantErr: "ctx[1]: unknown type"},
+		{name: "list as map key", path: `[{"type":"map_key","value":[1]}]`, wantErr: "map keys must be strings or integers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var steps []CDTContextStep
+			if err := json.Unmarshal([]byte(tt.path), &steps); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			ctx, err := buildCDTContext(steps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildCDTContext() error = %v", err)
+			}
+			if len(ctx) != tt.want {
+				t.Errorf("Expected %d context steps, got %d", tt.want, len(ctx))
+			}
+		})
+	}
+}
+
+func TestBuildCDTOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     string
+		wantErr string
+	}{
+		{name: "nested map put", ops: `[{"type":"map_put","bin_name":"profile","key":"city","value":"Paris","ctx":[{"type":"map_key","value":"address"}]}]`},
+		{name: "list reads", ops: `[{"type":"list_get","bin_name":"tags","index":0},{"type":"list_size","bin_name":"tags"}]`},
+		{name: "list append", ops: `[{"type":"list_append","bin_name":"tags","value":{"$bytes":"AQ=="}}]`},
+		{name: "map increment", ops: `[{"type":"map_increment","bin_name":"counts","key":"views","value":1}]`},
+		{name: "map reads and removal", ops: `[{"type":"map_get","bin_name":"m","key":1},{"type":"map_remove","bin_name":"m","key":"a"},{"type":"map_size","bin_name":"m"}]`},
+		{name: "list get without index", ops: `[{"type":"list_get","bin_name":"tags"}]`, wantErr: "requires an index"},
+		{name: "list append without value", ops: `[{"type":"list_append","bin_name":"tags"}]`, wantErr: "requires a value"},
+		{name: "map put without key", ops: `[{"type":"map_put","bin_name":"m","value":1}]`, wantErr: "map_put key"},
+		{name: "map increment by a string", ops: `[{"type":"map_increment","bin_name":"m","key":"a","value":"x"}]`, wantErr: "numeric value"},
+		{name: "ctx on a scalar operation", ops: `[{"type":"increment","bin_name":"n","value":1,"ctx":[{"type":"list_index","value":0}]}]`, wantErr: "does not take a ctx"},
+		{name: "bad ctx", ops: `[{"type":"map_get","bin_name":"m","key":"a","ctx":[{"type":"nope"}]}]`, wantErr: "map_get on bin m: ctx[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []OperateRequest
+			if err := json.Unmarshal([]byte(tt.ops), &requests); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			ops, err := buildOperations(requests)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildOperations() error = %v", err)
+			}
+			if len(ops) != len(requests) {
+				t.Errorf("Expected %d operations, got %d", len(requests), len(ops))
+			}
+		})
+	}
+}
@@ -8,20 +8,28 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
 
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tracing"
 	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
 )
 
 // Client wraps the Aerospike client with additional MCP-specific functionality.
 type Client struct {
-	client           *as.Client
+	client           atomic.Pointer[as.Client]
 	config           *config.Config
 	defaultNamespace string
 	readPolicy       *as.BasePolicy
@@ -29,66 +37,75 @@ type Client struct {
 	scanPolicy       *as.ScanPolicy
 	queryPolicy      *as.QueryPolicy
 	batchPolicy      *as.BatchPolicy
+	scans            *scanLimiter
+	keyRules         []keyRule
+	observer         Observer
+	metadata         *metadataCache
 }
 
 // NewClient creates a new Aerospike client connection.
 func NewClient(cfg *config.Config) (*Client, error) {
-	// Build host list
-	hosts := make([]*as.Host, len(cfg.Hosts))
-	for i, h := range cfg.Hosts {
-		hosts[i] = as.NewHost(h.Host, h.Port)
-	}
-
-	// Configure client policy
-	clientPolicy := as.NewClientPolicy()
-	clientPolicy.Timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
-
-	// Set authentication if provided
-	if cfg.User != "" {
-		clientPolicy.User = cfg.User
-		clientPolicy.Password = cfg.Password
-	}
-
-	// Configure TLS if enabled
-	if cfg.TLS.Enabled {
-		tlsConfig, err := buildTLSConfig(cfg.TLS)
-		if err != nil {
-			return nil, fmt.Errorf("configuring TLS: %w", err)
-		}
-		clientPolicy.TlsConfig = tlsConfig
-	}
-
-	// Connect to cluster
-	client, err := as.NewClientWithPolicyAndHost(clientPolicy, hosts...)
+	client, err := connect(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("connecting to Aerospike cluster: %w", err)
+		return nil, err
 	}
 
+	c := newClient(cfg)
+	c.client.Store(client)
+	return c, nil
+}
+
+// newClient creates a Client with policies built from cfg, without a
+// cluster connection.
+func newClient(cfg *config.Config) *Client {
 	// Build policies
 	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+	// Validate has already checked the names
+	replica, _ := replicaPolicy(cfg.Replica)
+	readModeAP, _ := readModeAP(cfg.ReadModeAP)
+	readModeSC, _ := readModeSC(cfg.ReadModeSC)
 
 	readPolicy := as.NewPolicy()
 	readPolicy.TotalTimeout = timeout
 	readPolicy.MaxRetries = cfg.MaxRetries
+	readPolicy.ReplicaPolicy = replica
+	readPolicy.ReadModeAP = readModeAP
+	readPolicy.ReadModeSC = readModeSC
 
 	writePolicy := as.NewWritePolicy(0, 0)
 	writePolicy.TotalTimeout = timeout
 	writePolicy.MaxRetries = cfg.MaxRetries
+	writePolicy.SendKey = cfg.SendKey
 
 	scanPolicy := as.NewScanPolicy()
 	scanPolicy.TotalTimeout = timeout
 	scanPolicy.MaxRetries = cfg.MaxRetries
+	scanPolicy.RecordsPerSecond = cfg.ScanLimits.RecordsPerSecond
+	scanPolicy.MaxConcurrentNodes = cfg.ScanLimits.MaxConcurrentNodes
 
 	queryPolicy := as.NewQueryPolicy()
 	queryPolicy.TotalTimeout = timeout
 	queryPolicy.MaxRetries = cfg.MaxRetries
+	queryPolicy.RecordsPerSecond = cfg.ScanLimits.RecordsPerSecond
+	queryPolicy.MaxConcurrentNodes = cfg.ScanLimits.MaxConcurrentNodes
+	queryPolicy.ReplicaPolicy = replica
+	queryPolicy.ReadModeAP = readModeAP
+	queryPolicy.ReadModeSC = readModeSC
 
 	batchPolicy := as.NewBatchPolicy()
 	batchPolicy.TotalTimeout = timeout
 	batchPolicy.MaxRetries = cfg.MaxRetries
+	batchPolicy.ReplicaPolicy = replica
+	batchPolicy.ReadModeAP = readModeAP
+	batchPolicy.ReadModeSC = readModeSC
+
+	applyOperationPolicy(readPolicy, cfg.Policies.Read)
+	applyOperationPolicy(&writePolicy.BasePolicy, cfg.Policies.Write)
+	applyOperationPolicy(&scanPolicy.BasePolicy, cfg.Policies.Scan)
+	applyOperationPolicy(&queryPolicy.BasePolicy, cfg.Policies.Query)
+	applyOperationPolicy(&batchPolicy.BasePolicy, cfg.Policies.Batch)
 
 	return &Client{
-		client:           client,
 		config:           cfg,
 		defaultNamespace: cfg.Namespace,
 		readPolicy:       readPolicy,
@@ -96,7 +113,135 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		scanPolicy:       scanPolicy,
 		queryPolicy:      queryPolicy,
 		batchPolicy:      batchPolicy,
-	}, nil
+		scans:            newScanLimiter(cfg.ScanLimits),
+		keyRules:         compileKeyRules(cfg.KeyRules),
+		metadata:         newMetadataCache(time.Duration(cfg.MetadataCacheTTLSec) * time.Second),
+	}
+}
+
+// applyOperationPolicy sets the timeouts and retry backoff that cfg
+// overrides for one class of operation.
+func applyOperationPolicy(policy *as.BasePolicy, cfg config.OperationPolicy) {
+	if cfg.TotalTimeoutMs > 0 {
+		policy.TotalTimeout = time.Duration(cfg.TotalTimeoutMs) * time.Millisecond
+	}
+	if cfg.SocketTimeoutMs > 0 {
+		policy.SocketTimeout = time.Duration(cfg.SocketTimeoutMs) * time.Millisecond
+	}
+	if cfg.SleepBetweenRetriesMs > 0 {
+		policy.SleepBetweenRetries = time.Duration(cfg.SleepBetweenRetriesMs) * time.Millisecond
+	}
+	if cfg.SleepMultiplier > 0 {
+		policy.SleepMultiplier = cfg.SleepMultiplier
+	}
+}
+
+// connect opens a cluster connection using the credentials and TLS settings in cfg.
+func connect(cfg *config.Config) (*as.Client, error) {
+	// Build host list
+	hosts := make([]*as.Host, len(cfg.Hosts))
+	for i, h := range cfg.Hosts {
+		hosts[i] = as.NewHost(h.Host, h.Port)
+	}
+
+	clientPolicy, err := buildClientPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Connect to cluster. With lazy_connect the client is returned even if
+	// no seed answered, and keeps trying them in the background.
+	clientPolicy.FailIfNotConnected = !cfg.LazyConnect
+	client, err := as.NewClientWithPolicyAndHost(clientPolicy, hosts...)
+	if err != nil && (client == nil || !cfg.LazyConnect) {
+		return nil, fmt.Errorf("connecting to Aerospike cluster: %w", err)
+	}
+	return client, nil
+}
+
+// buildClientPolicy creates the client policy for the configured timeout,
+// authentication mode, credentials, and TLS settings.
+func buildClientPolicy(cfg *config.Config) (*as.ClientPolicy, error) {
+	clientPolicy := as.NewClientPolicy()
+	clientPolicy.Timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	applyPoolSettings(clientPolicy, cfg.ClientPolicy)
+	clientPolicy.UseServicesAlternate = cfg.UseServicesAlternate
+	clientPolicy.RackAware = cfg.RackAware
+	clientPolicy.RackIds = cfg.RackIDs
+
+	switch strings.ToLower(cfg.AuthMode) {
+	case "", config.AuthModeInternal:
+		clientPolicy.AuthMode = as.AuthModeInternal
+	case config.AuthModeExternal:
+		clientPolicy.AuthMode = as.AuthModeExternal
+	case config.AuthModePKI:
+		// The cluster identifies the client by its TLS certificate
+		clientPolicy.AuthMode = as.AuthModePKI
+	default:
+		return nil, fmt.Errorf("unsupported auth mode: %s", cfg.AuthMode)
+	}
+
+	// Set authentication if provided. Rotated secrets can change the
+	// credentials while the server runs, so they are read under cfg's lock.
+	user, password, tlsCfg := cfg.ClusterCredentials()
+	if user != "" && clientPolicy.AuthMode != as.AuthModePKI {
+		clientPolicy.User = user
+		clientPolicy.Password = password
+	}
+
+	// Configure TLS if enabled
+	if tlsCfg.Enabled {
+		tlsConfig, err := buildTLSConfig(tlsCfg)
+		if err != nil {
+			return nil, fmt.Errorf("configuring TLS: %w", err)
+		}
+		clientPolicy.TlsConfig = tlsConfig
+	}
+
+	return clientPolicy, nil
+}
+
+// applyPoolSettings sets the connection pool and tending settings that cfg
+// overrides.
+func applyPoolSettings(policy *as.ClientPolicy, cfg config.ClientPolicyConfig) {
+	if cfg.ConnectionQueueSize > 0 {
+		policy.ConnectionQueueSize = cfg.ConnectionQueueSize
+	}
+	policy.MinConnectionsPerNode = cfg.MinConnectionsPerNode
+	switch {
+	case cfg.MaxErrorRate > 0:
+		policy.MaxErrorRate = cfg.MaxErrorRate
+	case cfg.MaxErrorRate < 0:
+		policy.MaxErrorRate = 0 // no limit
+	}
+	if cfg.ErrorRateWindow > 0 {
+		policy.ErrorRateWindow = cfg.ErrorRateWindow
+	}
+	if cfg.IdleTimeoutMs > 0 {
+		policy.IdleTimeout = time.Duration(cfg.IdleTimeoutMs) * time.Millisecond
+	}
+	if cfg.LoginTimeoutMs > 0 {
+		policy.LoginTimeout = time.Duration(cfg.LoginTimeoutMs) * time.Millisecond
+	}
+	if cfg.TendIntervalMs > 0 {
+		policy.TendInterval = time.Duration(cfg.TendIntervalMs) * time.Millisecond
+	}
+}
+
+// Reconnect opens a new cluster connection with the current configuration,
+// for example after credentials have been rotated, and closes the old one.
+// Requests in flight on the old connection are given a grace period.
+func (c *Client) Reconnect() error {
+	client, err := connect(c.config)
+	if err != nil {
+		return err
+	}
+
+	c.metadata.clear()
+	if old := c.client.Swap(client); old != nil {
+		time.AfterFunc(time.Duration(c.config.TimeoutMs)*time.Millisecond+time.Second, old.Close)
+	}
+	return nil
 }
 
 // buildTLSConfig creates a TLS configuration from the provided settings.
@@ -106,11 +251,15 @@ func buildTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
 	}
 
 	// Load CA certificate
-	if cfg.CAFile != "" {
-		caCert, err := os.ReadFile(cfg.CAFile)
+	caCert := cfg.CAPEM
+	if caCert == nil && cfg.CAFile != "" {
+		var err error
+		caCert, err = os.ReadFile(cfg.CAFile)
 		if err != nil {
 			return nil, fmt.Errorf("reading CA file: %w", err)
 		}
+	}
+	if caCert != nil {
 		caCertPool := x509.NewCertPool()
 		if !caCertPool.AppendCertsFromPEM(caCert) {
 			return nil, fmt.Errorf("failed to parse CA certificate")
@@ -119,7 +268,14 @@ func buildTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
 	}
 
 	// Load client certificate if provided (mTLS)
-	if cfg.CertFile != "" && cfg.KeyFile != "" {
+	switch {
+	case cfg.CertPEM != nil && cfg.KeyPEM != nil:
+		cert, err := tls.X509KeyPair(cfg.CertPEM, cfg.KeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parsing client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case cfg.CertFile != "" && cfg.KeyFile != "":
 		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
 		if err != nil {
 			return nil, fmt.Errorf("loading client certificate: %w", err)
@@ -130,16 +286,48 @@ func buildTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
+// conn returns the current cluster connection.
+func (c *Client) conn() *as.Client {
+	return c.client.Load()
+}
+
+// Observer receives the duration and outcome of each cluster operation.
+type Observer func(operation string, duration time.Duration, err error)
+
+// SetObserver sets the function called after each cluster operation. It must
+// be called before the client is used.
+func (c *Client) SetObserver(fn Observer) {
+	c.observer = fn
+}
+
+// observe reports an operation that began at start to the observer, adds it
+// to the request's OperationTimes, and records it as a span of the request's
+// trace. A missing record is an answer rather than a failure.
+func (c *Client) observe(ctx context.Context, operation string, start time.Time, err error) {
+	if errors.Is(err, as.ErrKeyNotFound) {
+		err = nil
+	}
+	tracing.Record(ctx, "aerospike "+operation, tracing.KindClient, start, err,
+		tracing.String("db.system", "aerospike"),
+		tracing.String("db.operation.name", operation),
+	)
+	duration := time.Since(start)
+	addOperationTime(ctx, duration)
+	if c.observer != nil {
+		c.observer(operation, duration, err)
+	}
+}
+
 // Close closes the Aerospike client connection.
 func (c *Client) Close() {
-	if c.client != nil {
-		c.client.Close()
+	if client := c.client.Load(); client != nil {
+		client.Close()
 	}
 }
 
 // ClusterName returns the name of the connected cluster.
 func (c *Client) ClusterName() string {
-	nodes := c.client.GetNodes()
+	nodes := c.conn().GetNodes()
 	if len(nodes) == 0 {
 		return "unknown"
 	}
@@ -148,7 +336,62 @@ func (c *Client) ClusterName() string {
 
 // IsConnected returns true if the client is connected to the cluster.
 func (c *Client) IsConnected() bool {
-	return c.client.IsConnected()
+	client := c.conn()
+	return client != nil && client.IsConnected()
+}
+
+// WaitConnected waits until the client is connected to the cluster, or
+// returns ctx's error.
+func (c *Client) WaitConnected(ctx context.Context) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for !c.IsConnected() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// ErrNotConnected is returned by Ping when the client has no cluster
+// connection.
+var ErrNotConnected = errors.New("not connected to the cluster")
+
+// Ping checks that the cluster answers requests by sending a lightweight
+// info command to one of its nodes, and returns the node count.
+func (c *Client) Ping(ctx context.Context) (int, error) {
+	client := c.conn()
+	if client == nil || !client.IsConnected() {
+		return 0, ErrNotConnected
+	}
+	nodes := client.GetNodes()
+	if len(nodes) == 0 {
+		return 0, ErrNotConnected
+	}
+
+	start := time.Now()
+	_, err := nodes[0].RequestInfo(infoPolicyFor(ctx), "build")
+	c.observe(ctx, "ping", start, err)
+	if err != nil {
+		return len(nodes), fmt.Errorf("pinging node %s: %w", nodes[0].GetName(), err)
+	}
+	return len(nodes), nil
+}
+
+// ClusterStats returns the client's connection and command statistics,
+// aggregated across the cluster's nodes.
+func (c *Client) ClusterStats() (map[string]interface{}, error) {
+	stats, err := c.conn().Stats()
+	if err != nil {
+		return nil, fmt.Errorf("reading client stats: %w", err)
+	}
+	return map[string]interface{}{
+		"open_connections": stats["open-connections"],
+		"total_nodes":      stats["total-nodes"],
+		"aggregated":       stats["cluster-aggregated-stats"],
+	}, nil
 }
 
 // Config returns the client configuration.
@@ -170,10 +413,18 @@ type NamespaceInfo struct {
 	ObjectCount       int64  `json:"object_count"`
 }
 
-// ListNamespaces returns all namespaces in the cluster.
+// ListNamespaces returns all namespaces in the cluster, from the metadata
+// cache if it is fresh.
 func (c *Client) ListNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
-	node := c.client.GetNodes()[0]
-	infoMap, err := node.RequestInfo(as.NewInfoPolicy(), "namespaces")
+	return cached(c.metadata, cacheNamespaces, func() ([]NamespaceInfo, error) {
+		return c.listNamespaces(ctx)
+	})
+}
+
+// listNamespaces reads the namespaces from the cluster.
+func (c *Client) listNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
+	node := c.conn().GetNodes()[0]
+	infoMap, err := node.RequestInfo(infoPolicyFor(ctx), "namespaces")
 	if err != nil {
 		return nil, fmt.Errorf("requesting namespaces: %w", err)
 	}
@@ -198,8 +449,8 @@ func (c *Client) ListNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
 
 // DescribeNamespace returns detailed information about a namespace.
 func (c *Client) DescribeNamespace(ctx context.Context, namespace string) (*NamespaceInfo, error) {
-	node := c.client.GetNodes()[0]
-	infoMap, err := node.RequestInfo(as.NewInfoPolicy(), "namespace/"+namespace)
+	node := c.conn().GetNodes()[0]
+	infoMap, err := node.RequestInfo(infoPolicyFor(ctx), "namespace/"+namespace)
 	if err != nil {
 		return nil, fmt.Errorf("requesting namespace info: %w", err)
 	}
@@ -242,10 +493,18 @@ type SetInfo struct {
 	StopWrites  bool   `json:"stop_writes"`
 }
 
-// ListSets returns all sets in a namespace.
+// ListSets returns all sets in a namespace, from the metadata cache if it
+// is fresh.
 func (c *Client) ListSets(ctx context.Context, namespace string) ([]SetInfo, error) {
-	node := c.client.GetNodes()[0]
-	infoMap, err := node.RequestInfo(as.NewInfoPolicy(), "sets/"+namespace)
+	return cached(c.metadata, cacheSets+namespace, func() ([]SetInfo, error) {
+		return c.listSets(ctx, namespace)
+	})
+}
+
+// listSets reads a namespace's sets from the cluster.
+func (c *Client) listSets(ctx context.Context, namespace string) ([]SetInfo, error) {
+	node := c.conn().GetNodes()[0]
+	infoMap, err := node.RequestInfo(infoPolicyFor(ctx), "sets/"+namespace)
 	if err != nil {
 		return nil, fmt.Errorf("requesting sets: %w", err)
 	}
@@ -290,66 +549,157 @@ func (c *Client) ListSets(ctx context.Context, namespace string) ([]SetInfo, err
 	return sets, nil
 }
 
-// DescribeSet returns detailed information about a set.
-func (c *Client) DescribeSet(ctx context.Context, namespace, setName string) (*SetInfo, error) {
-	sets, err := c.ListSets(ctx, namespace)
-	if err != nil {
-		return nil, err
+// ============================================================================
+// Query and Read Operations
+// ============================================================================
+
+// Record represents an Aerospike record. Key is the user key, which scans
+// and queries return only for records written with send_key; Digest, the
+// hex RIPEMD-160 digest the cluster stores records by, is always set.
+// Expiration is the TTL the client library reports; TTLRemaining and
+// ExpiresAt restate it as seconds and a time, or -1 and "never" for a
+// record that does not expire. LastUpdateTime is set only by GetRecord.
+type Record struct {
+	Key            string                 `json:"key,omitempty"`
+	Digest         string                 `json:"digest,omitempty"`
+	Namespace      string                 `json:"namespace"`
+	Set            string                 `json:"set,omitempty"`
+	Bins           map[string]interface{} `json:"bins"`
+	Generation     uint32                 `json:"generation"`
+	Expiration     uint32                 `json:"expiration"`
+	TTLRemaining   int64                  `json:"ttl_seconds_remaining"`
+	ExpiresAt      string                 `json:"expires_at"`
+	LastUpdateTime string                 `json:"last_update_time,omitempty"`
+
+	partition int  // for scans and queries, the partition holding the record
+	size      int  // serialized size, if counted against max_result_bytes
+	missing   bool // the key has no record
+}
+
+// neverExpires is the Expiration the client library reports for a record
+// with no TTL.
+const neverExpires = math.MaxUint32
+
+// setExpiration sets the record's Expiration, and its TTLRemaining and
+// ExpiresAt as of now.
+func (r *Record) setExpiration(expiration uint32, now time.Time) {
+	r.Expiration = expiration
+	r.TTLRemaining, r.ExpiresAt = expiry(expiration, now)
+}
+
+// expiry returns the seconds until a record with the given Expiration
+// expires and the time it does, as of now, or -1 and "never".
+func expiry(expiration uint32, now time.Time) (int64, string) {
+	if expiration == 0 || expiration == neverExpires {
+		return -1, "never"
 	}
+	return int64(expiration), now.Add(time.Duration(expiration) * time.Second).UTC().Format(time.RFC3339)
+}
 
-	for _, set := range sets {
-		if set.Name == setName {
-			return &set, nil
-		}
+// lastUpdateBin names the bin GetRecord reads a record's last update time
+// into. It is removed from the bins returned.
+const lastUpdateBin = "__last_update"
+
+// takeLastUpdate removes the last update time GetRecord read from bins and
+// returns it in RFC 3339 format, or "" if the server did not return it.
+func takeLastUpdate(bins map[string]interface{}) string {
+	value, ok := bins[lastUpdateBin]
+	if !ok {
+		return ""
 	}
+	delete(bins, lastUpdateBin)
 
-	return nil, fmt.Errorf("set not found: %s.%s", namespace, setName)
+	var nanos int64
+	switch v := value.(type) {
+	case int:
+		nanos = int64(v)
+	case int64:
+		nanos = v
+	default:
+		return ""
+	}
+	return time.Unix(0, nanos).UTC().Format(time.RFC3339Nano)
 }
 
-// ============================================================================
-// Query and Read Operations
-// ============================================================================
+// recordNotFound is how a key with no record is serialized.
+type recordNotFound struct {
+	Found     bool   `json:"found"`
+	Key       string `json:"key"`
+	Digest    string `json:"digest,omitempty"`
+	Namespace string `json:"namespace"`
+	Set       string `json:"set,omitempty"`
+}
 
-// Record represents an Aerospike record.
-type Record struct {
-	Key        string                 `json:"key"`
-	Namespace  string                 `json:"namespace"`
-	Set        string                 `json:"set,omitempty"`
-	Bins       map[string]interface{} `json:"bins"`
-	Generation uint32                 `json:"generation"`
-	Expiration uint32                 `json:"expiration"`
+// missingRecord returns the result for a key with no record.
+func missingRecord(namespace, setName, keyValue string, key *as.Key) *Record {
+	return &Record{Key: keyValue, Digest: digestString(key), Namespace: namespace, Set: setName, missing: true}
+}
+
+// Found returns false if the record's key has no record.
+func (r *Record) Found() bool {
+	return !r.missing
 }
 
-// GetRecord retrieves a single record by key.
+// MarshalJSON serializes the record, or {"found": false} with the key for a
+// key that has no record.
+func (r *Record) MarshalJSON() ([]byte, error) {
+	if r.missing {
+		return json.Marshal(recordNotFound{Key: r.Key, Digest: r.Digest, Namespace: r.Namespace, Set: r.Set})
+	}
+	type record Record // without this method
+	return json.Marshal((*record)(r))
+}
+
+// GetRecord retrieves a single record by key, with its last update time.
+// A missing record is returned with Found false.
 func (c *Client) GetRecord(ctx context.Context, namespace, setName, keyValue string, binNames []string) (*Record, error) {
+	if err := c.checkKey(ctx, namespace, setName, keyValue); err != nil {
+		return nil, err
+	}
+
 	key, err := as.NewKey(namespace, setName, keyValue)
 	if err != nil {
 		return nil, fmt.Errorf("creating key: %w", err)
 	}
 
-	var rec *as.Record
+	// Read the bins and the last update time together; the expression
+	// evaluates to nothing rather than failing where it is unavailable
+	ops := []*as.Operation{as.GetOp()}
 	if len(binNames) > 0 {
-		rec, err = c.client.Get(c.readPolicy, key, binNames...)
-	} else {
-		rec, err = c.client.Get(c.readPolicy, key)
+		ops = ops[:0]
+		for _, name := range binNames {
+			ops = append(ops, as.GetBinOp(name))
+		}
 	}
+	ops = append(ops, as.ExpReadOp(lastUpdateBin, as.ExpLastUpdate(), as.ExpReadFlagEvalNoFail))
+	policy := &as.WritePolicy{BasePolicy: *c.readPolicyFor(ctx, namespace)}
+
+	start := time.Now()
+	rec, err := c.conn().Operate(policy, key, ops...)
+	if errors.Is(err, as.ErrKeyNotFound) {
+		rec, err = nil, nil
+	}
+	c.observe(ctx, "get", start, err)
 
 	if err != nil {
 		return nil, fmt.Errorf("getting record: %w", err)
 	}
 
 	if rec == nil {
-		return nil, nil // Record not found
+		return missingRecord(namespace, setName, keyValue, key), nil
 	}
 
-	return &Record{
-		Key:        keyValue,
-		Namespace:  namespace,
-		Set:        setName,
-		Bins:       rec.Bins,
-		Generation: rec.Generation,
-		Expiration: rec.Expiration,
-	}, nil
+	record := &Record{
+		Key:            keyValue,
+		Digest:         digestString(key),
+		Namespace:      namespace,
+		Set:            setName,
+		Generation:     rec.Generation,
+		LastUpdateTime: takeLastUpdate(rec.Bins),
+		Bins:           encodeBins(rec.Bins),
+	}
+	record.setExpiration(rec.Expiration, time.Now())
+	return record, nil
 }
 
 // BatchGetRequest represents a batch get request item.
@@ -360,152 +710,355 @@ type BatchGetRequest struct {
 	BinNames  []string `json:"bin_names,omitempty"`
 }
 
-// BatchGet retrieves multiple records in a single request.
+// BatchGet retrieves multiple records in a single request, or in several
+// when batch_split applies. Each key reads only its own BinNames, or all
+// bins if it names none. Keys without a record get a record whose Found
+// is false.
 func (c *Client) BatchGet(ctx context.Context, requests []BatchGetRequest) ([]*Record, error) {
-	if len(requests) > c.config.MaxBatchSize {
-		return nil, fmt.Errorf("batch size %d exceeds maximum %d", len(requests), c.config.MaxBatchSize)
+	if err := c.checkBatchSize(len(requests)); err != nil {
+		return nil, err
 	}
 
-	keys := make([]*as.Key, len(requests))
+	reads := make([]*as.BatchRead, len(requests))
+	records := make([]as.BatchRecordIfc, len(requests))
+	namespaces := make([]string, len(requests))
 	for i, req := range requests {
+		namespaces[i] = req.Namespace
+		if err := c.checkKey(ctx, req.Namespace, req.Set, req.Key); err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
 		key, err := as.NewKey(req.Namespace, req.Set, req.Key)
 		if err != nil {
 			return nil, fmt.Errorf("creating key %d: %w", i, err)
 		}
-		keys[i] = key
+		reads[i] = batchRead(key, req.BinNames)
+		records[i] = reads[i]
 	}
 
-	records, err := c.client.BatchGet(c.batchPolicy, keys)
-	if err != nil {
-		return nil, fmt.Errorf("batch get: %w", err)
+	for _, err := range c.batchOperate(ctx, "batch_get", c.batchPolicyFor(ctx, namespaces...), records) {
+		if err != nil {
+			return nil, fmt.Errorf("batch get: %w", err)
+		}
 	}
 
-	results := make([]*Record, len(records))
-	for i, rec := range records {
-		if rec == nil {
-			results[i] = nil
+	budget := c.newResultBudget()
+	now := time.Now()
+	results := make([]*Record, len(reads))
+	for i, read := range reads {
+		switch read.ResultCode {
+		case types.OK:
+		case types.KEY_NOT_FOUND_ERROR:
+			results[i] = missingRecord(requests[i].Namespace, requests[i].Set, requests[i].Key, read.Key)
+			continue
+		default:
+			if read.Err != nil {
+				return nil, fmt.Errorf("batch get: key %d: %w", i, read.Err)
+			}
+			return nil, fmt.Errorf("batch get: key %d: %s", i, types.ResultCodeToString(read.ResultCode))
+		}
+		if read.Record == nil {
+			results[i] = missingRecord(requests[i].Namespace, requests[i].Set, requests[i].Key, read.Key)
 			continue
 		}
 		results[i] = &Record{
 			Key:        requests[i].Key,
+			Digest:     digestString(read.Key),
 			Namespace:  requests[i].Namespace,
 			Set:        requests[i].Set,
-			Bins:       rec.Bins,
-			Generation: rec.Generation,
-			Expiration: rec.Expiration,
+			Bins:       encodeBins(read.Record.Bins),
+			Generation: read.Record.Generation,
+		}
+		results[i].setExpiration(read.Record.Expiration, now)
+		if !budget.add(results[i]) {
+			return results[:i], fmt.Errorf("batch get: %w", ErrResultTooLarge)
 		}
 	}
 
 	return results, nil
 }
 
-// QueryFilter represents a query filter.
+// batchRead returns a batch record reading binNames from key, or all bins
+// if binNames is empty.
+func batchRead(key *as.Key, binNames []string) *as.BatchRead {
+	read := as.NewBatchRead(nil, key, binNames)
+	read.ReadAllBins = len(binNames) == 0
+	return read
+}
+
+// QueryFilter represents a query filter. A "contains" filter matches
+// records whose list or map bin, indexed with CollectionType, holds Value,
+// or if Value is unset a number from Begin to End.
 type QueryFilter struct {
-	BinName    string      `json:"bin_name"`
-	FilterType string      `json:"filter_type"` // "equal", "range", "contains"
-	Value      interface{} `json:"value"`
-	Begin      int64       `json:"begin,omitempty"`
-	End        int64       `json:"end,omitempty"`
+	BinName        string         `json:"bin_name"`
+	FilterType     string         `json:"filter_type"` // "equal", "range", "contains"
+	Value          interface{}    `json:"value"`
+	Begin          int64          `json:"begin,omitempty"`
+	End            int64          `json:"end,omitempty"`
+	CollectionType CollectionType `json:"collection_type,omitempty"` // LIST, MAPKEYS, or MAPVALUES, for "contains"
 }
 
-// QueryRecords executes a secondary index query.
-func (c *Client) QueryRecords(ctx context.Context, namespace, setName, indexName string, filter QueryFilter, maxRecords int) ([]*Record, error) {
-	if maxRecords <= 0 {
-		maxRecords = c.config.DefaultMaxRecords
+// build returns the index filter for f.
+func (f QueryFilter) build() (*as.Filter, error) {
+	if f.BinName == "" {
+		return nil, fmt.Errorf("filter bin_name is required")
 	}
 
-	stmt := as.NewStatement(namespace, setName)
-
-	// Apply filter
-	var asFilter *as.Filter
-	switch filter.FilterType {
+	switch f.FilterType {
 	case "equal":
-		switch v := filter.Value.(type) {
-		case int, int64:
-			asFilter = as.NewEqualFilter(filter.BinName, v)
-		case string:
-			asFilter = as.NewEqualFilter(filter.BinName, v)
+		value, err := filterValue(f.Value)
+		if err != nil {
+			return nil, err
 		}
+		return as.NewEqualFilter(f.BinName, value), nil
+
 	case "range":
-		asFilter = as.NewRangeFilter(filter.BinName, filter.Begin, filter.End)
+		return as.NewRangeFilter(f.BinName, f.Begin, f.End), nil
+
+	case "contains":
+		collection, err := indexCollectionType(f.CollectionType)
+		if err != nil {
+			return nil, err
+		}
+		if collection == as.ICT_DEFAULT {
+			return nil, fmt.Errorf("contains filter needs a collection_type of LIST, MAPKEYS, or MAPVALUES")
+		}
+		if f.Value == nil {
+			return as.NewContainsRangeFilter(f.BinName, collection, f.Begin, f.End), nil
+		}
+		value, err := filterValue(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		return as.NewContainsFilter(f.BinName, collection, value), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported filter_type %q (must be equal, range, or contains)", f.FilterType)
 	}
+}
 
-	if asFilter != nil {
-		_ = stmt.SetFilter(asFilter)
+// filterValue converts a filter value decoded from JSON to the string or
+// integer an index holds.
+func filterValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case int:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case float64:
+		if val == math.Trunc(val) {
+			return int64(val), nil
+		}
 	}
+	return nil, fmt.Errorf("filter value must be a string or an integer, got %v", v)
+}
 
-	recordset, err := c.client.Query(c.queryPolicy, stmt)
+// QueryRecords executes a secondary index query, returning a page of up to
+// maxRecords records from cursor on.
+func (c *Client) QueryRecords(ctx context.Context, namespace, setName, indexName string, filter QueryFilter, maxRecords int, cursor string) (*RecordPage, error) {
+	asFilter, err := filter.build()
 	if err != nil {
+		return nil, err
+	}
+
+	maxRecords = c.maxRecords(namespace, maxRecords)
+	pc, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := pc.partitionFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := c.scans.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stmt := as.NewStatement(namespace, setName)
+	if err := stmt.SetFilter(asFilter); err != nil {
+		return nil, fmt.Errorf("setting filter: %w", err)
+	}
+
+	policy := c.queryPolicyFor(ctx, namespace)
+	policy.MaxRecords = int64(maxRecords)
+	before := partitionStates(partitions)
+
+	start := time.Now()
+	recordset, err := c.conn().QueryPartitions(policy, stmt, partitions)
+	if err != nil {
+		c.observe(ctx, "query", start, err)
 		return nil, fmt.Errorf("executing query: %w", err)
 	}
 	defer recordset.Close()
 
-	records := make([]*Record, 0)
-	for rec := range recordset.Results() {
-		if rec.Err != nil {
-			return nil, fmt.Errorf("query result error: %w", rec.Err)
-		}
-		records = append(records, &Record{
-			Key:        fmt.Sprintf("%v", rec.Record.Key.Value()),
-			Namespace:  namespace,
-			Set:        setName,
-			Bins:       rec.Record.Bins,
-			Generation: rec.Record.Generation,
-			Expiration: rec.Record.Expiration,
-		})
-		if len(records) >= maxRecords {
-			break
-		}
+	records, err := c.collect(ctx, "query", start, recordset, namespace, setName, maxRecords)
+	order, ordered := recordOrderFrom(ctx)
+	if ordered {
+		records = order.apply(records)
 	}
-
-	return records, nil
+	if err != nil {
+		return &RecordPage{Records: records}, err
+	}
+	if ordered {
+		return c.orderedPage(records), nil
+	}
+	return c.scanPage(records, partitions, before, pc.Returned), nil
 }
 
-// ScanSet performs a full set scan.
-func (c *Client) ScanSet(ctx context.Context, namespace, setName string, binNames []string, maxRecords int, samplePercent int) ([]*Record, error) {
-	if maxRecords <= 0 {
-		maxRecords = c.config.DefaultMaxRecords
+// ScanSet performs a full set scan, returning a page of up to maxRecords
+// records from cursor on.
+func (c *Client) ScanSet(ctx context.Context, namespace, setName string, binNames []string, maxRecords int, samplePercent int, cursor string) (*RecordPage, error) {
+	maxRecords = c.maxRecords(namespace, maxRecords)
+	pc, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := pc.partitionFilter()
+	if err != nil {
+		return nil, err
 	}
 
-	policy := as.NewScanPolicy()
-	policy.TotalTimeout = c.scanPolicy.TotalTimeout
-	policy.MaxRetries = c.scanPolicy.MaxRetries
+	release, err := c.scans.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	policy := c.scanPolicyFor(ctx, namespace)
+	policy.MaxRecords = int64(maxRecords)
+	before := partitionStates(partitions)
 
-	recordset, err := c.client.ScanAll(policy, namespace, setName, binNames...)
+	start := time.Now()
+	recordset, err := c.conn().ScanPartitions(policy, partitions, namespace, setName, binNames...)
 	if err != nil {
+		c.observe(ctx, "scan", start, err)
 		return nil, fmt.Errorf("executing scan: %w", err)
 	}
 	defer recordset.Close()
 
-	records := make([]*Record, 0)
-	for rec := range recordset.Results() {
+	records, err := c.collect(ctx, "scan", start, recordset, namespace, setName, maxRecords)
+	order, ordered := recordOrderFrom(ctx)
+	if ordered {
+		records = order.apply(records)
+	}
+	if err != nil {
+		return &RecordPage{Records: records}, err
+	}
+	if ordered {
+		return c.orderedPage(records), nil
+	}
+	return c.scanPage(records, partitions, before, pc.Returned), nil
+}
+
+// collectPrealloc caps the records collect allocates up front.
+const collectPrealloc = 1024
+
+// collect reads the visible records from a scan or query, which the
+// cluster limits to the policy's MaxRecords. Every record is read, so the
+// partition filter ends where the page does. Records are allocated in one
+// block sized for maxRecords, up to collectPrealloc. If the operation runs
+// out of time, it returns the records read so far with ErrIncomplete; if
+// they would exceed max_result_bytes, as many as fit max_response_bytes with
+// ErrResultTooLarge.
+func (c *Client) collect(ctx context.Context, operation string, start time.Time, recordset *as.Recordset, namespace, setName string, maxRecords int) ([]*Record, error) {
+	size := max(0, min(maxRecords, collectPrealloc))
+	records := make([]*Record, 0, size)
+	block := make([]Record, size)
+	progress := progressFrom(ctx)
+	budget := c.newResultBudget()
+	results := recordset.Results()
+	for {
+		var rec *as.Result
+		select {
+		case rec = <-results:
+		case <-ctx.Done():
+			c.observe(ctx, operation, start, ctx.Err())
+			return records, fmt.Errorf("%s: %w", operation, ErrIncomplete)
+		}
+		if rec == nil {
+			break
+		}
 		if rec.Err != nil {
-			return nil, fmt.Errorf("scan result error: %w", rec.Err)
+			c.observe(ctx, operation, start, rec.Err)
+			if deadlineReached(ctx, rec.Err) {
+				return records, fmt.Errorf("%s: %w: %w", operation, ErrIncomplete, rec.Err)
+			}
+			return nil, fmt.Errorf("%s result error: %w", operation, rec.Err)
 		}
-		records = append(records, &Record{
-			Key:        fmt.Sprintf("%v", rec.Record.Key.Value()),
+		value := rec.Record.Key.Value()
+		if !c.keyVisible(ctx, namespace, setName, value) {
+			continue
+		}
+
+		var record *Record
+		if len(records) < len(block) {
+			record = &block[len(records)]
+		} else {
+			record = &Record{}
+		}
+		*record = Record{
+			Key:        keyString(value),
+			Digest:     digestString(rec.Record.Key),
 			Namespace:  namespace,
 			Set:        setName,
-			Bins:       rec.Record.Bins,
+			Bins:       encodeBins(rec.Record.Bins),
 			Generation: rec.Record.Generation,
-			Expiration: rec.Record.Expiration,
-		})
-		if len(records) >= maxRecords {
-			break
+			partition:  rec.Record.Key.PartitionId(),
+		}
+		record.setExpiration(rec.Record.Expiration, time.Now())
+		if !budget.add(record) {
+			err := fmt.Errorf("%s: %w", operation, ErrResultTooLarge)
+			c.observe(ctx, operation, start, err)
+			return records[:c.FitRecords(records)], err
 		}
+		records = append(records, record)
+		progress.add(1)
 	}
 
+	c.observe(ctx, operation, start, nil)
 	return records, nil
 }
 
+// keyString formats a record's user key. Records stored without their key
+// have none, and get an empty string.
+func keyString(value as.Value) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
+
+// digestString formats a key's digest as hex.
+func digestString(key *as.Key) string {
+	return hex.EncodeToString(key.Digest())
+}
+
 // ============================================================================
 // Write Operations
 // ============================================================================
 
-// PutRecord inserts or updates a record.
-func (c *Client) PutRecord(ctx context.Context, namespace, setName, keyValue string, bins map[string]interface{}, ttl int) error {
-	if !c.config.CanWrite() {
-		return fmt.Errorf("write operations not permitted for role: %s", c.config.Role)
+// PutRecord inserts or updates a record, writing bins and removing the bins
+// named in deleteBins. Removing every bin deletes the record.
+func (c *Client) PutRecord(ctx context.Context, namespace, setName, keyValue string, bins map[string]interface{}, deleteBins []string, ttl int) error {
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanWrite() {
+		return &config.RoleError{Operation: "write operations", Role: role}
+	}
+
+	if err := c.checkWritable(namespace, setName); err != nil {
+		return err
+	}
+
+	if err := c.checkKey(ctx, namespace, setName, keyValue); err != nil {
+		return err
+	}
+
+	normalizedBins, err := writeBins(bins, deleteBins, c.preserveFloat(ctx))
+	if err != nil {
+		return err
 	}
 
 	key, err := as.NewKey(namespace, setName, keyValue)
@@ -513,14 +1066,18 @@ func (c *Client) PutRecord(ctx context.Context, namespace, setName, keyValue str
 		return fmt.Errorf("creating key: %w", err)
 	}
 
-	policy := as.NewWritePolicy(0, uint32(ttl))
-	policy.TotalTimeout = c.writePolicy.TotalTimeout
-	policy.MaxRetries = c.writePolicy.MaxRetries
+	policy := c.writePolicyFor(ctx, namespace)
+	policy.Expiration = uint32(ttl)
+	policy.RecordExistsAction = writeModeFrom(ctx).action()
 
-	// Normalize bins to convert float64 whole numbers to int64 for proper Aerospike type handling
-	normalizedBins := normalizeBins(bins)
-	binMap := as.BinMap(normalizedBins)
-	if err := c.client.Put(policy, key, binMap); err != nil {
+	start := time.Now()
+	if hasOrderedMap(normalizedBins) {
+		_, err = c.conn().Operate(policy, key, binOperations(normalizedBins)...)
+	} else {
+		err = c.conn().Put(policy, key, as.BinMap(normalizedBins))
+	}
+	c.observe(ctx, "put", start, err)
+	if err != nil {
 		return fmt.Errorf("putting record: %w", err)
 	}
 
@@ -529,8 +1086,16 @@ func (c *Client) PutRecord(ctx context.Context, namespace, setName, keyValue str
 
 // DeleteRecord removes a record.
 func (c *Client) DeleteRecord(ctx context.Context, namespace, setName, keyValue string) (bool, error) {
-	if !c.config.CanWrite() {
-		return false, fmt.Errorf("write operations not permitted for role: %s", c.config.Role)
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanWrite() {
+		return false, &config.RoleError{Operation: "write operations", Role: role}
+	}
+
+	if err := c.checkWritable(namespace, setName); err != nil {
+		return false, err
+	}
+
+	if err := c.checkKey(ctx, namespace, setName, keyValue); err != nil {
+		return false, err
 	}
 
 	key, err := as.NewKey(namespace, setName, keyValue)
@@ -538,7 +1103,9 @@ func (c *Client) DeleteRecord(ctx context.Context, namespace, setName, keyValue
 		return false, fmt.Errorf("creating key: %w", err)
 	}
 
-	existed, err := c.client.Delete(c.writePolicy, key)
+	start := time.Now()
+	existed, err := c.conn().Delete(c.writePolicyFor(ctx, namespace), key)
+	c.observe(ctx, "delete", start, err)
 	if err != nil {
 		return false, fmt.Errorf("deleting record: %w", err)
 	}
@@ -547,73 +1114,204 @@ func (c *Client) DeleteRecord(ctx context.Context, namespace, setName, keyValue
 }
 
 // BatchWriteRequest represents a single write operation in a batch.
+// Generation, Mode, and DurableDelete override the batch's policy for this
+// operation alone: the write fails unless the record's generation equals
+// Generation, a put treats an existing record as Mode says, and
+// DurableDelete replaces the call's durable_delete.
 type BatchWriteRequest struct {
-	Namespace string                 `json:"namespace"`
-	Set       string                 `json:"set,omitempty"`
-	Key       string                 `json:"key"`
-	Bins      map[string]interface{} `json:"bins"`
-	TTL       int                    `json:"ttl,omitempty"`
-	Operation string                 `json:"operation"` // "put", "delete"
+	Namespace     string                 `json:"namespace"`
+	Set           string                 `json:"set,omitempty"`
+	Key           string                 `json:"key"`
+	Bins          map[string]interface{} `json:"bins"`
+	DeleteBins    []string               `json:"delete_bins,omitempty"` // bins a put removes
+	TTL           int                    `json:"ttl,omitempty"`
+	Operation     string                 `json:"operation"` // "put", "delete"
+	Generation    *uint32                `json:"generation,omitempty"`
+	Mode          WriteMode              `json:"mode,omitempty"` // puts only
+	DurableDelete *bool                  `json:"durable_delete,omitempty"`
+}
+
+// writeMode returns the request's write mode, or else the batch's.
+func (req BatchWriteRequest) writeMode(ctx context.Context) WriteMode {
+	if req.Mode != "" {
+		return req.Mode
+	}
+	return writeModeFrom(ctx)
+}
+
+// checkGeneration returns why the request would fail on a record of
+// generation gen, or "" if it wouldn't. A record that doesn't exist has no
+// generation to check.
+func (req BatchWriteRequest) checkGeneration(exists bool, gen uint32) string {
+	if req.Generation == nil || !exists || gen == *req.Generation {
+		return ""
+	}
+	return fmt.Sprintf("generation mismatch (record has %d, expected %d)", gen, *req.Generation)
 }
 
 // BatchWriteResult represents the result of a batch write operation.
+// ResultCode is the cluster's result code for the record, or omitted if the
+// write was refused before it was sent.
 type BatchWriteResult struct {
-	Key     string `json:"key"`
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	Key        string `json:"key"`
+	Success    bool   `json:"success"`
+	ResultCode int    `json:"result_code,omitempty"`
+	InDoubt    bool   `json:"in_doubt,omitempty"`
+	Error      string `json:"error,omitempty"`
 }
 
-// BatchWrite executes multiple write operations.
-func (c *Client) BatchWrite(ctx context.Context, requests []BatchWriteRequest) ([]BatchWriteResult, error) {
-	if !c.config.CanWrite() {
-		return nil, fmt.Errorf("write operations not permitted for role: %s", c.config.Role)
+// errNotAttempted is the error of the batch writes left unsent because an
+// earlier one failed and the batch was to stop on its first error.
+var errNotAttempted = errors.New("not attempted: an earlier operation failed")
+
+// BatchWrite executes multiple write operations in a single batch command,
+// or in several when batch_split applies. Each write is checked against the caller's role, read-only sets, and key
+// rules first; those refused get an error result and the rest are sent
+// together. If stopOnError is set, the writes after one that is refused
+// here, or after the command holding one the cluster fails, are not
+// attempted, and the cluster stops writing to each node at its first
+// failure.
+func (c *Client) BatchWrite(ctx context.Context, requests []BatchWriteRequest, stopOnError bool) ([]BatchWriteResult, error) {
+	if role := c.config.EffectiveRole(ctx); !role.CanWrite() {
+		return nil, &config.RoleError{Operation: "write operations", Role: role}
 	}
 
-	if len(requests) > c.config.MaxBatchSize {
-		return nil, fmt.Errorf("batch size %d exceeds maximum %d", len(requests), c.config.MaxBatchSize)
+	if err := c.checkBatchSize(len(requests)); err != nil {
+		return nil, err
 	}
 
 	results := make([]BatchWriteResult, len(requests))
+	records := make([]as.BatchRecordIfc, 0, len(requests))
+	indexes := make([]int, 0, len(requests)) // request index of each record
+	var namespaces []string
 
 	for i, req := range requests {
 		results[i] = BatchWriteResult{Key: req.Key}
 
-		key, err := as.NewKey(req.Namespace, req.Set, req.Key)
-		if err != nil {
-			results[i].Success = false
-			results[i].Error = fmt.Sprintf("creating key: %v", err)
+		// Stop issuing writes once the request has timed out
+		if err := ctx.Err(); err != nil {
+			results[i].Error = err.Error()
 			continue
 		}
 
-		switch req.Operation {
-		case "put", "":
-			policy := as.NewWritePolicy(0, uint32(req.TTL))
-			policy.TotalTimeout = c.writePolicy.TotalTimeout
-			// Normalize bins to convert float64 whole numbers to int64
-			normalizedBins := normalizeBins(req.Bins)
-			binMap := as.BinMap(normalizedBins)
-			if err := c.client.Put(policy, key, binMap); err != nil {
-				results[i].Success = false
-				results[i].Error = fmt.Sprintf("put: %v", err)
-			} else {
-				results[i].Success = true
+		record, err := c.batchWriteRecord(ctx, req)
+		if err != nil {
+			results[i].Error = err.Error()
+			if stopOnError {
+				for k := i + 1; k < len(requests); k++ {
+					results[k] = BatchWriteResult{Key: requests[k].Key, Error: errNotAttempted.Error()}
+				}
+				break
 			}
+			continue
+		}
+		records = append(records, record)
+		indexes = append(indexes, i)
+		namespaces = append(namespaces, req.Namespace)
+	}
 
-		case "delete":
-			if _, err := c.client.Delete(c.writePolicy, key); err != nil {
-				results[i].Success = false
-				results[i].Error = fmt.Sprintf("delete: %v", err)
-			} else {
-				results[i].Success = true
-			}
+	if len(records) == 0 {
+		return results, nil
+	}
 
-		default:
-			results[i].Success = false
-			results[i].Error = fmt.Sprintf("unknown operation: %s", req.Operation)
+	policy := c.batchPolicyFor(ctx, namespaces...)
+	var errs []error
+	if stopOnError {
+		errs = c.batchOperateUntil(ctx, "batch_write", policy, records, func(j int) bool {
+			return !batchWriteResult(requests[indexes[j]], records[j].BatchRec(), nil).Success
+		})
+	} else {
+		errs = c.batchOperate(ctx, "batch_write", policy, records)
+	}
+	for j, record := range records {
+		results[indexes[j]] = batchWriteResult(requests[indexes[j]], record.BatchRec(), errs[j])
+	}
+	return results, nil
+}
+
+// batchWriteRecord checks a batch write request and builds the batch record
+// that performs it.
+func (c *Client) batchWriteRecord(ctx context.Context, req BatchWriteRequest) (as.BatchRecordIfc, error) {
+	if role := c.config.NamespaceRole(ctx, req.Namespace); !role.CanWrite() {
+		return nil, &config.RoleError{Operation: "write operations", Role: role}
+	}
+	if err := c.checkWritable(req.Namespace, req.Set); err != nil {
+		return nil, err
+	}
+	if err := c.checkKey(ctx, req.Namespace, req.Set, req.Key); err != nil {
+		return nil, err
+	}
+
+	key, err := as.NewKey(req.Namespace, req.Set, req.Key)
+	if err != nil {
+		return nil, fmt.Errorf("creating key: %w", err)
+	}
+
+	if err := req.Mode.Validate(); err != nil {
+		return nil, err
+	}
+	durableDelete, sendKey := writeOptions(ctx, false, c.config.SendKey)
+	if req.DurableDelete != nil {
+		durableDelete = *req.DurableDelete
+	}
+	generationPolicy, generation := as.NONE, uint32(0)
+	if req.Generation != nil {
+		generationPolicy, generation = as.EXPECT_GEN_EQUAL, *req.Generation
+	}
+
+	switch req.Operation {
+	case "put", "":
+		policy := as.NewBatchWritePolicy()
+		policy.Expiration = uint32(req.TTL)
+		policy.RecordExistsAction = req.writeMode(ctx).action()
+		policy.GenerationPolicy, policy.Generation = generationPolicy, generation
+		policy.DurableDelete, policy.SendKey = durableDelete, sendKey
+		normalizedBins, err := writeBins(req.Bins, req.DeleteBins, c.preserveFloat(ctx))
+		if err != nil {
+			return nil, err
+		}
+		return as.NewBatchWrite(policy, key, binOperations(normalizedBins)...), nil
+
+	case "delete":
+		if req.Mode != "" {
+			return nil, fmt.Errorf("mode applies only to put operations")
 		}
+		policy := as.NewBatchDeletePolicy()
+		policy.GenerationPolicy, policy.Generation = generationPolicy, generation
+		policy.DurableDelete, policy.SendKey = durableDelete, sendKey
+		return as.NewBatchDelete(policy, key), nil
+
+	default:
+		return nil, fmt.Errorf("unknown operation: %s", req.Operation)
 	}
+}
 
-	return results, nil
+// batchWriteResult converts the outcome of one record of a batch command.
+// batchErr is the error of the command as a whole, reported for records it
+// left without a result. Deleting a record that doesn't exist succeeds, as
+// it does outside a batch.
+func batchWriteResult(req BatchWriteRequest, record *as.BatchRecord, batchErr error) BatchWriteResult {
+	result := BatchWriteResult{Key: req.Key, InDoubt: record.InDoubt}
+	if record.ResultCode != types.NO_RESPONSE {
+		result.ResultCode = int(record.ResultCode)
+	}
+
+	operation := req.Operation
+	if operation == "" {
+		operation = "put"
+	}
+	switch {
+	case record.ResultCode == types.OK,
+		operation == "delete" && record.ResultCode == types.KEY_NOT_FOUND_ERROR:
+		result.Success = true
+	case record.Err != nil:
+		result.Error = fmt.Sprintf("%s: %v", operation, record.Err)
+	case record.ResultCode == types.NO_RESPONSE && batchErr != nil:
+		result.Error = fmt.Sprintf("%s: %v", operation, batchErr)
+	default:
+		result.Error = fmt.Sprintf("%s: %s", operation, types.ResultCodeToString(record.ResultCode))
+	}
+	return result
 }
 
 // OperationType defines the type of atomic operation.
@@ -627,11 +1325,19 @@ const (
 	OpRead      OperationType = "read"
 )
 
-// OperateRequest represents an atomic operation request.
+// OperateRequest represents an atomic operation request. Key, Index, and
+// Ctx apply to CDT operations: the map key or list index to act on, and
+// the path to the nested list or map holding it. Expression and Flags
+// apply to expression operations.
 type OperateRequest struct {
-	Type    OperationType `json:"type"`
-	BinName string        `json:"bin_name"`
-	Value   interface{}   `json:"value,omitempty"`
+	Type       OperationType    `json:"type"`
+	BinName    string           `json:"bin_name"`
+	Value      interface{}      `json:"value,omitempty"`
+	Key        interface{}      `json:"key,omitempty"`
+	Index      *int             `json:"index,omitempty"`
+	Ctx        []CDTContextStep `json:"ctx,omitempty"`
+	Expression interface{}      `json:"expression,omitempty"`
+	Flags      []string         `json:"flags,omitempty"`
 }
 
 // OperateResult represents the result of an operate call.
@@ -643,8 +1349,21 @@ type OperateResult struct {
 
 // Operate executes atomic read-modify-write operations on a single record.
 func (c *Client) Operate(ctx context.Context, namespace, setName, keyValue string, operations []OperateRequest, ttl int) (*OperateResult, error) {
-	if !c.config.CanWrite() {
-		return nil, fmt.Errorf("write operations not permitted for role: %s", c.config.Role)
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanWrite() {
+		return nil, &config.RoleError{Operation: "write operations", Role: role}
+	}
+
+	ops, err := buildOperations(operations)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkWritable(namespace, setName); err != nil {
+		return nil, err
+	}
+
+	if err := c.checkKey(ctx, namespace, setName, keyValue); err != nil {
+		return nil, err
 	}
 
 	key, err := as.NewKey(namespace, setName, keyValue)
@@ -652,9 +1371,43 @@ func (c *Client) Operate(ctx context.Context, namespace, setName, keyValue strin
 		return nil, fmt.Errorf("creating key: %w", err)
 	}
 
-	// Build operations
+	policy := c.writePolicyFor(ctx, namespace)
+	policy.Expiration = uint32(ttl)
+
+	start := time.Now()
+	rec, err := c.conn().Operate(policy, key, ops...)
+	c.observe(ctx, "operate", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("operate: %w", err)
+	}
+
+	result := &OperateResult{
+		Success: true,
+	}
+	if rec != nil {
+		result.Bins = encodeBins(rec.Bins)
+		result.Generation = rec.Generation
+	}
+
+	return result, nil
+}
+
+// buildOperations converts operate requests into client operations.
+func buildOperations(operations []OperateRequest) ([]*as.Operation, error) {
 	ops := make([]*as.Operation, 0, len(operations))
 	for _, op := range operations {
+		if isCDTOperation(op.Type) {
+			cdtOp, err := buildCDTOperation(op)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, cdtOp)
+			continue
+		}
+		if len(op.Ctx) > 0 {
+			return nil, fmt.Errorf("%s does not take a ctx", op.Type)
+		}
+
 		switch op.Type {
 		case OpIncrement:
 			if intVal, ok := toInt64(op.Value); ok {
@@ -680,6 +1433,13 @@ func (c *Client) Operate(ctx context.Context, namespace, setName, keyValue strin
 		case OpTouch:
 			ops = append(ops, as.TouchOp())
 
+		case OpExpRead, OpExpWrite:
+			expOp, err := buildExpOperation(op)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, expOp)
+
 		case OpRead:
 			if op.BinName != "" {
 				ops = append(ops, as.GetBinOp(op.BinName))
@@ -691,24 +1451,7 @@ func (c *Client) Operate(ctx context.Context, namespace, setName, keyValue strin
 			return nil, fmt.Errorf("unknown operation type: %s", op.Type)
 		}
 	}
-
-	policy := as.NewWritePolicy(0, uint32(ttl))
-	policy.TotalTimeout = c.writePolicy.TotalTimeout
-
-	rec, err := c.client.Operate(policy, key, ops...)
-	if err != nil {
-		return nil, fmt.Errorf("operate: %w", err)
-	}
-
-	result := &OperateResult{
-		Success: true,
-	}
-	if rec != nil {
-		result.Bins = rec.Bins
-		result.Generation = rec.Generation
-	}
-
-	return result, nil
+	return ops, nil
 }
 
 // toInt64 converts various numeric types to int64.
@@ -729,35 +1472,170 @@ func toInt64(v interface{}) (int64, bool) {
 	}
 }
 
-// normalizeBinValue converts float64 values that represent whole numbers to int64.
-// This is necessary because JSON unmarshals all numbers as float64, but Aerospike's
-// increment operation only works on integer bins.
-func normalizeBinValue(v interface{}) interface{} {
+// Type hints name a bin value's type explicitly: {"$float": 42} writes a
+// float and {"$int": 42} an integer, whatever normalization would do. See
+// also geoJSONHint and the CDT hints in cdt.go.
+const (
+	floatHint = "$float"
+	intHint   = "$int"
+)
+
+// preserveFloatContextKey is the context key for a call's preserve_float
+// setting.
+type preserveFloatContextKey struct{}
+
+// WithPreserveFloat returns a context whose writes keep whole-number floats
+// as floats if preserve is true, or convert them to integers if it is
+// false, overriding the preserve_float setting. A nil preserve keeps the
+// setting.
+func WithPreserveFloat(ctx context.Context, preserve *bool) context.Context {
+	if preserve == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, preserveFloatContextKey{}, *preserve)
+}
+
+// preserveFloat returns true if writes in ctx keep whole-number floats.
+func (c *Client) preserveFloat(ctx context.Context) bool {
+	if preserve, ok := ctx.Value(preserveFloatContextKey{}).(bool); ok {
+		return preserve
+	}
+	return c.config.PreserveFloat
+}
+
+// typeHint returns the value a type hint stands for. ok is false if v is
+// not a type hint.
+func typeHint(v interface{}, preserveFloat bool) (value interface{}, ok bool, err error) {
+	hint, isMap := v.(map[string]interface{})
+	if !isMap || len(hint) != 1 {
+		return nil, false, nil
+	}
+	for name, raw := range hint {
+		switch name {
+		case floatHint:
+			f, isNumber := raw.(float64)
+			if !isNumber {
+				return nil, true, fmt.Errorf("%s needs a number, got %T", floatHint, raw)
+			}
+			return f, true, nil
+		case intHint:
+			n, isWhole := wholeNumber(raw)
+			if !isWhole {
+				return nil, true, fmt.Errorf("%s needs a whole number, got %v", intHint, raw)
+			}
+			return n, true, nil
+		case geoJSONHint:
+			geo, err := geoJSONValue(raw)
+			if err != nil {
+				return nil, true, err
+			}
+			return geo, true, nil
+		case bytesHint, mapHint, orderedMapHint:
+			value, err := cdtHint(name, raw, preserveFloat)
+			return value, true, err
+		}
+	}
+	return nil, false, nil
+}
+
+// wholeNumber returns v as an int64 if it is a number decoded from JSON
+// with no fractional part.
+func wholeNumber(v interface{}) (int64, bool) {
+	f, isNumber := v.(float64)
+	if !isNumber || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// normalizeBinValue resolves type hints, and unless preserveFloat is set
+// converts floats that are whole numbers to int64, in a value and the lists
+// and maps it holds. JSON decodes every number as float64, so without this
+// integer bins would be written as floats, and Aerospike's increment
+// operation only works on integer bins. Only a bin's own value may be an
+// ordered map.
+func normalizeBinValue(v interface{}, preserveFloat bool) (interface{}, error) {
+	if value, ok, err := typeHint(v, preserveFloat); ok {
+		return value, err
+	}
+
 	switch val := v.(type) {
 	case float64:
 		// Check if it's a whole number
-		if val == float64(int64(val)) {
-			return int64(val)
+		if !preserveFloat && val == float64(int64(val)) {
+			return int64(val), nil
 		}
-		return val
+		return val, nil
 	case float32:
 		// Check if it's a whole number
-		if val == float32(int64(val)) {
-			return int64(val)
+		if !preserveFloat && val == float32(int64(val)) {
+			return int64(val), nil
 		}
-		return val
+		return val, nil
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, item := range val {
+			value, err := normalizeNested(item, preserveFloat)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			list[i] = value
+		}
+		return list, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			value, err := normalizeNested(item, preserveFloat)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			m[k] = value
+		}
+		return m, nil
 	default:
-		return v
+		return v, nil
 	}
 }
 
-// normalizeBins converts all whole-number floats in a bin map to integers.
-func normalizeBins(bins map[string]interface{}) map[string]interface{} {
+// normalizeNested normalizes a value held in a list or map.
+func normalizeNested(v interface{}, preserveFloat bool) (interface{}, error) {
+	value, err := normalizeBinValue(v, preserveFloat)
+	if _, ordered := value.(orderedMap); ordered {
+		return nil, fmt.Errorf("%s is only supported as a bin's value", orderedMapHint)
+	}
+	return value, err
+}
+
+// normalizeBins normalizes every value in a bin map.
+func normalizeBins(bins map[string]interface{}, preserveFloat bool) (map[string]interface{}, error) {
 	normalized := make(map[string]interface{}, len(bins))
 	for k, v := range bins {
-		normalized[k] = normalizeBinValue(v)
+		value, err := normalizeBinValue(v, preserveFloat)
+		if err != nil {
+			return nil, fmt.Errorf("bin %s: %w", k, err)
+		}
+		normalized[k] = value
+	}
+	return normalized, nil
+}
+
+// writeBins returns the normalized bins a put writes, with a nil value for
+// each bin in deleteBins, which removes the bin.
+func writeBins(bins map[string]interface{}, deleteBins []string, preserveFloat bool) (map[string]interface{}, error) {
+	normalized, err := normalizeBins(bins, preserveFloat)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range deleteBins {
+		if _, ok := normalized[name]; ok {
+			return nil, fmt.Errorf("bin %s is both written and deleted", name)
+		}
+		normalized[name] = nil
 	}
-	return normalized
+	if len(normalized) == 0 {
+		return nil, errors.New("no bins to write or delete")
+	}
+	return normalized, nil
 }
 
 // ============================================================================
@@ -772,12 +1650,23 @@ type IndexInfo struct {
 	Bin       string `json:"bin"`
 	Type      string `json:"type"`
 	State     string `json:"state"`
+
+	indexType string // default, or the collection type of a CDT index
+	context   string // the CDT context of an index on a nested value
 }
 
-// ListIndexes returns all secondary indexes in a namespace.
+// ListIndexes returns all secondary indexes in a namespace, from the
+// metadata cache if it is fresh.
 func (c *Client) ListIndexes(ctx context.Context, namespace string) ([]IndexInfo, error) {
-	node := c.client.GetNodes()[0]
-	infoMap, err := node.RequestInfo(as.NewInfoPolicy(), "sindex/"+namespace)
+	return cached(c.metadata, cacheIndexes+namespace, func() ([]IndexInfo, error) {
+		return c.listIndexes(ctx, namespace)
+	})
+}
+
+// listIndexes reads a namespace's secondary indexes from the cluster.
+func (c *Client) listIndexes(ctx context.Context, namespace string) ([]IndexInfo, error) {
+	node := c.conn().GetNodes()[0]
+	infoMap, err := node.RequestInfo(infoPolicyFor(ctx), "sindex/"+namespace)
 	if err != nil {
 		return nil, fmt.Errorf("requesting indexes: %w", err)
 	}
@@ -794,27 +1683,7 @@ func (c *Client) ListIndexes(ctx context.Context, namespace string) ([]IndexInfo
 		if line == "" {
 			continue
 		}
-		idx := IndexInfo{Namespace: namespace}
-		pairs := strings.Split(line, ":")
-		for _, pair := range pairs {
-			kv := strings.SplitN(pair, "=", 2)
-			if len(kv) != 2 {
-				continue
-			}
-			switch kv[0] {
-			case "indexname":
-				idx.Name = kv[1]
-			case "set":
-				idx.Set = kv[1]
-			case "bin":
-				idx.Bin = kv[1]
-			case "type":
-				idx.Type = kv[1]
-			case "state":
-				idx.State = kv[1]
-			}
-		}
-		if idx.Name != "" {
+		if idx, _ := parseIndexLine(namespace, line); idx.Name != "" {
 			indexes = append(indexes, idx)
 		}
 	}
@@ -822,6 +1691,37 @@ func (c *Client) ListIndexes(ctx context.Context, namespace string) ([]IndexInfo
 	return indexes, nil
 }
 
+// parseIndexLine parses one index of a sindex listing, whose fields are
+// separated by colons. It returns the index and all of its fields.
+func parseIndexLine(namespace, line string) (IndexInfo, map[string]string) {
+	idx := IndexInfo{Namespace: namespace}
+	fields := make(map[string]string)
+	for _, pair := range strings.Split(line, ":") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		fields[kv[0]] = kv[1]
+		switch kv[0] {
+		case "indexname":
+			idx.Name = kv[1]
+		case "set":
+			idx.Set = kv[1]
+		case "bin":
+			idx.Bin = kv[1]
+		case "type":
+			idx.Type = kv[1]
+		case "state":
+			idx.State = kv[1]
+		case "indextype":
+			idx.indexType = kv[1]
+		case "context":
+			idx.context = kv[1]
+		}
+	}
+	return idx, fields
+}
+
 // IndexType represents the type of secondary index.
 type IndexType string
 
@@ -842,10 +1742,27 @@ const (
 	CollectionMapValues CollectionType = "MAPVALUES"
 )
 
-// CreateIndex creates a secondary index on a bin.
-func (c *Client) CreateIndex(ctx context.Context, namespace, setName, indexName, binName string, indexType IndexType, collectionType CollectionType) error {
-	if !c.config.CanAdmin() {
-		return fmt.Errorf("admin operations not permitted for role: %s", c.config.Role)
+// indexCollectionType converts a collection type to the client's.
+func indexCollectionType(collectionType CollectionType) (as.IndexCollectionType, error) {
+	switch collectionType {
+	case CollectionDefault, "":
+		return as.ICT_DEFAULT, nil
+	case CollectionList:
+		return as.ICT_LIST, nil
+	case CollectionMapKeys:
+		return as.ICT_MAPKEYS, nil
+	case CollectionMapValues:
+		return as.ICT_MAPVALUES, nil
+	default:
+		return 0, fmt.Errorf("invalid collection type: %s", collectionType)
+	}
+}
+
+// CreateIndex creates a secondary index on a bin, or with a path on a
+// value nested inside it.
+func (c *Client) CreateIndex(ctx context.Context, namespace, setName, indexName, binName string, indexType IndexType, collectionType CollectionType, path []CDTContextStep) error {
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanAdmin() {
+		return &config.RoleError{Operation: "admin operations", Role: role}
 	}
 
 	var asIndexType as.IndexType
@@ -862,52 +1779,41 @@ func (c *Client) CreateIndex(ctx context.Context, namespace, setName, indexName,
 		return fmt.Errorf("invalid index type: %s", indexType)
 	}
 
-	var asCollectionType as.IndexCollectionType
-	switch collectionType {
-	case CollectionDefault, "":
-		asCollectionType = as.ICT_DEFAULT
-	case CollectionList:
-		asCollectionType = as.ICT_LIST
-	case CollectionMapKeys:
-		asCollectionType = as.ICT_MAPKEYS
-	case CollectionMapValues:
-		asCollectionType = as.ICT_MAPVALUES
-	default:
-		return fmt.Errorf("invalid collection type: %s", collectionType)
+	asCollectionType, err := indexCollectionType(collectionType)
+	if err != nil {
+		return err
+	}
+	cdtCtx, err := buildCDTContext(path)
+	if err != nil {
+		return err
 	}
 
-	task, err := c.client.CreateComplexIndex(nil, namespace, setName, indexName, binName, asIndexType, asCollectionType)
+	// The listing changes whether or not the build finishes in time
+	defer c.metadata.invalidate(cacheIndexes + namespace)
+
+	start := time.Now()
+	task, err := c.conn().CreateComplexIndex(adminPolicyFor(ctx), namespace, setName, indexName, binName, asIndexType, asCollectionType, cdtCtx...)
+	c.observe(ctx, "create_index", start, err)
 	if err != nil {
 		return fmt.Errorf("creating index: %w", err)
 	}
 
 	// Wait for index creation to complete
-	for {
-		done, err := task.IsDone()
-		if err != nil {
-			return fmt.Errorf("waiting for index creation: %w", err)
-		}
-		if done {
-			break
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			// Continue waiting
-		}
-	}
-
-	return nil
+	return waitForTask(ctx, "index creation", func() (bool, error) { return task.IsDone() })
 }
 
 // DropIndex removes a secondary index.
 func (c *Client) DropIndex(ctx context.Context, namespace, indexName string) error {
-	if !c.config.CanAdmin() {
-		return fmt.Errorf("admin operations not permitted for role: %s", c.config.Role)
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanAdmin() {
+		return &config.RoleError{Operation: "admin operations", Role: role}
 	}
 
-	if err := c.client.DropIndex(nil, namespace, "", indexName); err != nil {
+	defer c.metadata.invalidate(cacheIndexes + namespace)
+
+	start := time.Now()
+	err := c.conn().DropIndex(adminPolicyFor(ctx), namespace, "", indexName)
+	c.observe(ctx, "drop_index", start, err)
+	if err != nil {
 		return fmt.Errorf("dropping index: %w", err)
 	}
 
@@ -916,11 +1822,29 @@ func (c *Client) DropIndex(ctx context.Context, namespace, indexName string) err
 
 // TruncateSet removes all records from a set.
 func (c *Client) TruncateSet(ctx context.Context, namespace, setName string) error {
-	if !c.config.CanAdmin() {
-		return fmt.Errorf("admin operations not permitted for role: %s", c.config.Role)
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanAdmin() {
+		return &config.RoleError{Operation: "admin operations", Role: role}
+	}
+
+	// The audit set can't be truncated, directly or with its namespace
+	if err := c.checkWritable(namespace, setName); err != nil {
+		return err
+	}
+	if sink := c.config.Audit.Aerospike; setName == "" && sink != nil && sink.Namespace == namespace {
+		return fmt.Errorf("%w: truncating namespace %s would remove it", ErrAuditSetReadOnly, namespace)
+	}
+
+	// Truncation would remove keys outside the permitted patterns
+	if c.keyRestricted(ctx, namespace, setName) {
+		return fmt.Errorf("%w: truncating %s/%s is not allowed while key_rules apply", ErrKeyNotPermitted, namespace, setName)
 	}
 
-	if err := c.client.Truncate(nil, namespace, setName, nil); err != nil {
+	defer c.metadata.invalidate(cacheNamespaces, cacheSets+namespace)
+
+	start := time.Now()
+	err := c.conn().Truncate(infoPolicyFor(ctx), namespace, setName, nil)
+	c.observe(ctx, "truncate", start, err)
+	if err != nil {
 		return fmt.Errorf("truncating set: %w", err)
 	}
 
@@ -938,9 +1862,17 @@ type UDFInfo struct {
 	Hash string `json:"hash"`
 }
 
-// ListUDFs returns all registered UDF modules.
+// ListUDFs returns all registered UDF modules, from the metadata cache if
+// it is fresh.
 func (c *Client) ListUDFs(ctx context.Context) ([]UDFInfo, error) {
-	udfs, err := c.client.ListUDF(nil)
+	return cached(c.metadata, cacheUDFs, func() ([]UDFInfo, error) {
+		return c.listUDFs(ctx)
+	})
+}
+
+// listUDFs reads the registered UDF modules from the cluster.
+func (c *Client) listUDFs(ctx context.Context) ([]UDFInfo, error) {
+	udfs, err := c.conn().ListUDF(&adminPolicyFor(ctx).BasePolicy)
 	if err != nil {
 		return nil, fmt.Errorf("listing UDFs: %w", err)
 	}
@@ -959,79 +1891,64 @@ func (c *Client) ListUDFs(ctx context.Context) ([]UDFInfo, error) {
 
 // RegisterUDF registers a Lua UDF module on the cluster.
 func (c *Client) RegisterUDF(ctx context.Context, moduleName, code string) error {
-	if !c.config.CanAdmin() {
-		return fmt.Errorf("admin operations not permitted for role: %s", c.config.Role)
+	if role := c.config.EffectiveRole(ctx); !role.CanAdmin() {
+		return &config.RoleError{Operation: "admin operations", Role: role}
 	}
 
-	task, err := c.client.RegisterUDF(nil, []byte(code), moduleName, as.LUA)
+	defer c.metadata.invalidate(cacheUDFs)
+
+	start := time.Now()
+	task, err := c.conn().RegisterUDF(adminPolicyFor(ctx), []byte(code), moduleName, as.LUA)
+	c.observe(ctx, "register_udf", start, err)
 	if err != nil {
 		return fmt.Errorf("registering UDF: %w", err)
 	}
 
 	// Wait for registration to complete
-	for {
-		done, err := task.IsDone()
-		if err != nil {
-			return fmt.Errorf("waiting for UDF registration: %w", err)
-		}
-		if done {
-			break
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			// Continue waiting
-		}
-	}
-
-	return nil
+	return waitForTask(ctx, "UDF registration", func() (bool, error) { return task.IsDone() })
 }
 
 // RemoveUDF removes a UDF module from the cluster.
 func (c *Client) RemoveUDF(ctx context.Context, moduleName string) error {
-	if !c.config.CanAdmin() {
-		return fmt.Errorf("admin operations not permitted for role: %s", c.config.Role)
+	if role := c.config.EffectiveRole(ctx); !role.CanAdmin() {
+		return &config.RoleError{Operation: "admin operations", Role: role}
 	}
 
-	task, err := c.client.RemoveUDF(nil, moduleName)
+	defer c.metadata.invalidate(cacheUDFs)
+
+	start := time.Now()
+	task, err := c.conn().RemoveUDF(adminPolicyFor(ctx), moduleName)
+	c.observe(ctx, "remove_udf", start, err)
 	if err != nil {
 		return fmt.Errorf("removing UDF: %w", err)
 	}
 
 	// Wait for removal to complete
-	for {
-		done, err := task.IsDone()
-		if err != nil {
-			return fmt.Errorf("waiting for UDF removal: %w", err)
-		}
-		if done {
-			break
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			// Continue waiting
-		}
-	}
-
-	return nil
+	return waitForTask(ctx, "UDF removal", func() (bool, error) { return task.IsDone() })
 }
 
 // ExecuteUDF executes a UDF on a single record.
 func (c *Client) ExecuteUDF(ctx context.Context, namespace, setName, keyValue, moduleName, functionName string, args []interface{}) (interface{}, error) {
+	if err := c.checkWritable(namespace, setName); err != nil {
+		return nil, err
+	}
+	if err := c.checkKey(ctx, namespace, setName, keyValue); err != nil {
+		return nil, err
+	}
+
 	key, err := as.NewKey(namespace, setName, keyValue)
 	if err != nil {
 		return nil, fmt.Errorf("creating key: %w", err)
 	}
 
-	result, err := c.client.Execute(nil, key, moduleName, functionName, as.NewValue(args))
+	start := time.Now()
+	result, err := c.conn().Execute(c.writePolicyFor(ctx, namespace), key, moduleName, functionName, as.NewValue(args))
+	c.observe(ctx, "execute_udf", start, err)
 	if err != nil {
 		return nil, fmt.Errorf("executing UDF: %w", err)
 	}
 
-	return result, nil
+	return encodeValue(result), nil
 }
 
 // ============================================================================
@@ -1055,7 +1972,7 @@ type NodeInfo struct {
 
 // GetClusterInfo returns cluster topology and status.
 func (c *Client) GetClusterInfo(ctx context.Context) (*ClusterInfo, error) {
-	nodes := c.client.GetNodes()
+	nodes := c.conn().GetNodes()
 	nodeInfos := make([]NodeInfo, len(nodes))
 
 	clusterName := ""
@@ -1078,7 +1995,8 @@ func (c *Client) GetClusterInfo(ctx context.Context) (*ClusterInfo, error) {
 	}, nil
 }
 
-// NodeStats contains performance metrics for a node.
+// NodeStats contains performance metrics for a node. Error is set, and the
+// metrics are empty, if the node didn't answer.
 type NodeStats struct {
 	Name        string            `json:"name"`
 	Address     string            `json:"address"`
@@ -1089,64 +2007,73 @@ type NodeStats struct {
 	UsedDisk    int64             `json:"used_disk_bytes"`
 	TotalDisk   int64             `json:"total_disk_bytes"`
 	ClientConns int               `json:"client_connections"`
-	Stats       map[string]string `json:"stats"`
+	Stats       map[string]string `json:"stats,omitempty"`
+	Error       string            `json:"error,omitempty"`
 }
 
 // GetNodeStats returns performance metrics for a specific node or all nodes.
+// Nodes are queried in parallel; a node that fails is listed with its
+// error, and an error is returned only if no node answered.
 func (c *Client) GetNodeStats(ctx context.Context, nodeName string) ([]NodeStats, error) {
-	nodes := c.client.GetNodes()
-	results := make([]NodeStats, 0)
-
-	for _, node := range nodes {
-		if nodeName != "" && node.GetName() != nodeName {
-			continue
+	nodes := c.conn().GetNodes()
+	if nodeName != "" {
+		nodes = slices.DeleteFunc(slices.Clone(nodes), func(node *as.Node) bool {
+			return node.GetName() != nodeName
+		})
+		if len(nodes) == 0 {
+			return nil, fmt.Errorf("node not found: %s", nodeName)
 		}
+	}
 
-		infoMap, err := node.RequestInfo(as.NewInfoPolicy(), "statistics")
+	results := make([]NodeStats, len(nodes))
+	start := time.Now()
+	errs := c.eachNode(ctx, nodes, func(ctx context.Context, node *as.Node, i int) error {
+		results[i] = NodeStats{Name: node.GetName(), Address: node.GetHost().String()}
+		infoMap, err := node.RequestInfo(infoPolicyFor(ctx), "statistics")
 		if err != nil {
-			continue
-		}
-
-		stats := parseInfoString(infoMap["statistics"])
-
-		nodeStats := NodeStats{
-			Name:    node.GetName(),
-			Address: node.GetHost().String(),
-			Stats:   stats,
-		}
-
-		// Parse specific stats
-		if v, ok := stats["cluster_size"]; ok {
-			nodeStats.ClusterSize, _ = strconv.Atoi(v)
-		}
-		if v, ok := stats["uptime"]; ok {
-			nodeStats.Uptime, _ = strconv.ParseInt(v, 10, 64)
-		}
-		if v, ok := stats["system_total_mem_size"]; ok {
-			nodeStats.TotalMemory, _ = strconv.ParseInt(v, 10, 64)
+			return err
 		}
-		if v, ok := stats["system_free_mem_pct"]; ok {
-			pct, _ := strconv.ParseInt(v, 10, 64)
-			nodeStats.UsedMemory = nodeStats.TotalMemory * (100 - pct) / 100
-		}
-		if v, ok := stats["client_connections"]; ok {
-			nodeStats.ClientConns, _ = strconv.Atoi(v)
-		}
-
-		results = append(results, nodeStats)
-
-		if nodeName != "" {
-			break
+		results[i].parse(parseInfoString(infoMap["statistics"]))
+		return nil
+	})
+	err := errors.Join(errs...)
+	c.observe(ctx, "node_stats", start, err)
+
+	answered := 0
+	for i, err := range errs {
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
 		}
+		answered++
 	}
-
-	if nodeName != "" && len(results) == 0 {
-		return nil, fmt.Errorf("node not found: %s", nodeName)
+	if answered == 0 && len(nodes) > 0 {
+		return nil, fmt.Errorf("reading node statistics: %w", err)
 	}
-
 	return results, nil
 }
 
+// parse sets the node's metrics from its statistics.
+func (s *NodeStats) parse(stats map[string]string) {
+	s.Stats = stats
+	if v, ok := stats["cluster_size"]; ok {
+		s.ClusterSize, _ = strconv.Atoi(v)
+	}
+	if v, ok := stats["uptime"]; ok {
+		s.Uptime, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if v, ok := stats["system_total_mem_size"]; ok {
+		s.TotalMemory, _ = strconv.ParseInt(v, 10, 64)
+	}
+	if v, ok := stats["system_free_mem_pct"]; ok {
+		pct, _ := strconv.ParseInt(v, 10, 64)
+		s.UsedMemory = s.TotalMemory * (100 - pct) / 100
+	}
+	if v, ok := stats["client_connections"]; ok {
+		s.ClientConns, _ = strconv.Atoi(v)
+	}
+}
+
 // parseInfoString parses a semicolon-separated key=value info string.
 func parseInfoString(info string) map[string]string {
 	result := make(map[string]string)
@@ -4,7 +4,17 @@
 package aerospike
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
 
 	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
 )
@@ -39,6 +49,47 @@ func TestNewClientConfig(t *testing.T) {
 	}
 }
 
+func TestBuildClientPolicyAuthMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		authMode string
+		wantMode as.AuthMode
+		wantUser string
+		wantErr  bool
+	}{
+		{"default", "", as.AuthModeInternal, "mcp", false},
+		{"internal", "internal", as.AuthModeInternal, "mcp", false},
+		{"external", "EXTERNAL", as.AuthModeExternal, "mcp", false},
+		{"pki ignores user", "pki", as.AuthModePKI, "", false},
+		{"unknown", "kerberos", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				AuthMode:  tt.authMode,
+				User:      "mcp",
+				Password:  "secret",
+				TimeoutMs: 1000,
+			}
+
+			policy, err := buildClientPolicy(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildClientPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if policy.AuthMode != tt.wantMode {
+				t.Errorf("Expected auth mode %v, got %v", tt.wantMode, policy.AuthMode)
+			}
+			if policy.User != tt.wantUser {
+				t.Errorf("Expected user '%s', got '%s'", tt.wantUser, policy.User)
+			}
+		})
+	}
+}
+
 func TestRecordConversion(t *testing.T) {
 	// Test Record struct JSON marshaling
 	rec := Record{
@@ -100,6 +151,177 @@ func TestBatchWriteResult(t *testing.T) {
 	}
 }
 
+func TestBatchWriteStopsAtDeadline(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadWrite
+	c := &Client{config: cfg}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := c.BatchWrite(ctx, []BatchWriteRequest{
+		{Namespace: "test", Set: "users", Key: "a", Operation: "put"},
+		{Namespace: "test", Set: "users", Key: "b", Operation: "delete"},
+	}, false)
+	if err != nil {
+		t.Fatalf("BatchWrite() error = %v", err)
+	}
+	for _, result := range results {
+		if result.Success || result.Error != context.Canceled.Error() {
+			t.Errorf("Expected %s to fail with %q, got %+v", result.Key, context.Canceled, result)
+		}
+	}
+}
+
+func TestBatchWriteRecordResults(t *testing.T) {
+	batchErr := errors.New("cluster unavailable")
+	tests := []struct {
+		name        string
+		operation   string
+		record      as.BatchRecord
+		batchErr    error
+		wantSuccess bool
+		wantCode    int
+		wantError   string
+	}{
+		{"put", "put", as.BatchRecord{ResultCode: types.OK}, nil, true, 0, ""},
+		{"delete missing record", "delete", as.BatchRecord{ResultCode: types.KEY_NOT_FOUND_ERROR}, nil, true, 2, ""},
+		{"put failed", "", as.BatchRecord{ResultCode: types.KEY_BUSY, InDoubt: true}, nil, false, int(types.KEY_BUSY), "put: "},
+		{"not sent", "put", as.BatchRecord{ResultCode: types.NO_RESPONSE}, batchErr, false, 0, "put: cluster unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.record
+			got := batchWriteResult(BatchWriteRequest{Key: "k", Operation: tt.operation}, &record, tt.batchErr)
+			if got.Success != tt.wantSuccess || got.ResultCode != tt.wantCode || got.InDoubt != tt.record.InDoubt {
+				t.Errorf("Expected success=%v, result code %d, got %+v", tt.wantSuccess, tt.wantCode, got)
+			}
+			if !strings.HasPrefix(got.Error, tt.wantError) || (tt.wantError == "") != (got.Error == "") {
+				t.Errorf("Expected error %q, got %q", tt.wantError, got.Error)
+			}
+		})
+	}
+}
+
+func TestBatchWriteRecord(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadWrite
+	c := &Client{config: cfg}
+	ctx := context.Background()
+
+	record, err := c.batchWriteRecord(ctx, BatchWriteRequest{Namespace: "test", Key: "a", Bins: map[string]interface{}{"n": 1.0}, TTL: 60})
+	if err != nil {
+		t.Fatalf("batchWriteRecord() error = %v", err)
+	}
+	if _, ok := record.(*as.BatchWrite); !ok {
+		t.Errorf("Expected a put to be a BatchWrite, got %T", record)
+	}
+
+	record, err = c.batchWriteRecord(ctx, BatchWriteRequest{Namespace: "test", Key: "a", Operation: "delete"})
+	if err != nil {
+		t.Fatalf("batchWriteRecord() error = %v", err)
+	}
+	if _, ok := record.(*as.BatchDelete); !ok {
+		t.Errorf("Expected a delete to be a BatchDelete, got %T", record)
+	}
+
+	if _, err := c.batchWriteRecord(ctx, BatchWriteRequest{Namespace: "test", Key: "a", Operation: "touch"}); err == nil {
+		t.Error("Expected error for unknown operation")
+	}
+}
+
+func TestBatchWriteRecordPolicy(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadWrite
+	c := &Client{config: cfg}
+	ctx := WithWriteMode(context.Background(), WriteModeReplace)
+	gen, durable := uint32(7), true
+
+	record, err := c.batchWriteRecord(ctx, BatchWriteRequest{Namespace: "test", Key: "a", Bins: map[string]interface{}{"n": 1}, Generation: &gen, Mode: WriteModeCreateOnly, DurableDelete: &durable})
+	if err != nil {
+		t.Fatalf("batchWriteRecord() error = %v", err)
+	}
+	put := record.(*as.BatchWrite).Policy
+	if put.GenerationPolicy != as.EXPECT_GEN_EQUAL || put.Generation != 7 {
+		t.Errorf("Expected generation 7 to be expected, got %v %d", put.GenerationPolicy, put.Generation)
+	}
+	if put.RecordExistsAction != as.CREATE_ONLY || !put.DurableDelete {
+		t.Errorf("Expected the item's mode and durable_delete, got %v %v", put.RecordExistsAction, put.DurableDelete)
+	}
+
+	record, err = c.batchWriteRecord(ctx, BatchWriteRequest{Namespace: "test", Key: "b", Bins: map[string]interface{}{"n": 1}})
+	if err != nil {
+		t.Fatalf("batchWriteRecord() error = %v", err)
+	}
+	if put := record.(*as.BatchWrite).Policy; put.RecordExistsAction != as.REPLACE || put.GenerationPolicy != as.NONE {
+		t.Errorf("Expected the batch's mode and no generation check, got %v %v", put.RecordExistsAction, put.GenerationPolicy)
+	}
+
+	record, err = c.batchWriteRecord(ctx, BatchWriteRequest{Namespace: "test", Key: "c", Operation: "delete", Generation: &gen})
+	if err != nil {
+		t.Fatalf("batchWriteRecord() error = %v", err)
+	}
+	if del := record.(*as.BatchDelete).Policy; del.GenerationPolicy != as.EXPECT_GEN_EQUAL || del.Generation != 7 {
+		t.Errorf("Expected the delete to expect generation 7, got %v %d", del.GenerationPolicy, del.Generation)
+	}
+
+	tests := []BatchWriteRequest{
+		{Namespace: "test", Key: "d", Bins: map[string]interface{}{"n": 1}, Mode: "upsert"},
+		{Namespace: "test", Key: "e", Operation: "delete", Mode: WriteModeUpdateOnly},
+	}
+	for _, req := range tests {
+		if _, err := c.batchWriteRecord(ctx, req); err == nil {
+			t.Errorf("Expected error for %s with mode %q", req.Key, req.Mode)
+		}
+	}
+}
+
+func TestBatchWriteStopOnError(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadWrite
+	c := &Client{config: cfg}
+
+	results, err := c.BatchWrite(context.Background(), []BatchWriteRequest{
+		{Namespace: "test", Key: "a", Operation: "touch"},
+		{Namespace: "test", Key: "b", Operation: "delete"},
+		{Namespace: "test", Key: "c", Operation: "delete"},
+	}, true)
+	if err != nil {
+		t.Fatalf("BatchWrite() error = %v", err)
+	}
+	if results[0].Error != "unknown operation: touch" {
+		t.Errorf("Expected the first operation to be refused, got %+v", results[0])
+	}
+	for _, result := range results[1:] {
+		if result.Success || result.Error != errNotAttempted.Error() {
+			t.Errorf("Expected %s not to be attempted, got %+v", result.Key, result)
+		}
+	}
+}
+
+func TestCheckGeneration(t *testing.T) {
+	gen := uint32(3)
+	tests := []struct {
+		name   string
+		req    BatchWriteRequest
+		exists bool
+		gen    uint32
+		want   string
+	}{
+		{"no generation", BatchWriteRequest{}, true, 5, ""},
+		{"match", BatchWriteRequest{Generation: &gen}, true, 3, ""},
+		{"mismatch", BatchWriteRequest{Generation: &gen}, true, 5, "generation mismatch (record has 5, expected 3)"},
+		{"missing record", BatchWriteRequest{Generation: &gen}, false, 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.req.checkGeneration(tt.exists, tt.gen); got != tt.want {
+			t.Errorf("%s: Expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
 func TestOperateRequest(t *testing.T) {
 	// Test OperateRequest struct
 	req := OperateRequest{
@@ -203,23 +425,36 @@ func TestToInt64(t *testing.T) {
 
 func TestNormalizeBinValue(t *testing.T) {
 	tests := []struct {
-		name     string
-		input    interface{}
-		expected interface{}
+		name          string
+		input         interface{}
+		preserveFloat bool
+		expected      interface{}
+		wantErr       bool
 	}{
-		{"float64 whole number", float64(42.0), int64(42)},
-		{"float64 decimal", float64(42.5), float64(42.5)},
-		{"float32 whole number", float32(42.0), int64(42)},
-		{"float32 decimal", float32(42.5), float32(42.5)},
-		{"int64", int64(42), int64(42)},
-		{"string", "hello", "hello"},
-		{"nil", nil, nil},
+		{name: "float64 whole number", input: float64(42.0), expected: int64(42)},
+		{name: "float64 decimal", input: float64(42.5), expected: float64(42.5)},
+		{name: "float32 whole number", input: float32(42.0), expected: int64(42)},
+		{name: "float32 decimal", input: float32(42.5), expected: float32(42.5)},
+		{name: "int64", input: int64(42), expected: int64(42)},
+		{name: "string", input: "hello", expected: "hello"},
+		{name: "nil", input: nil, expected: nil},
+		{name: "preserved float", input: float64(42.0), preserveFloat: true, expected: float64(42)},
+		{name: "float hint", input: map[string]interface{}{"$float": float64(42)}, expected: float64(42)},
+		{name: "int hint", input: map[string]interface{}{"$int": float64(42)}, preserveFloat: true, expected: int64(42)},
+		{name: "int hint with a fraction", input: map[string]interface{}{"$int": 42.5}, wantErr: true},
+		{name: "float hint with a string", input: map[string]interface{}{"$float": "42"}, wantErr: true},
+		{name: "map that is not a hint", input: map[string]interface{}{"$float": float64(1), "b": float64(2)}, expected: map[string]interface{}{"$float": int64(1), "b": int64(2)}},
+		{name: "nested whole numbers", input: []interface{}{float64(1), 2.5}, expected: []interface{}{int64(1), 2.5}},
+		{name: "nested preserved float", input: []interface{}{float64(1)}, preserveFloat: true, expected: []interface{}{float64(1)}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := normalizeBinValue(tt.input)
-			if result != tt.expected {
+			result, err := normalizeBinValue(tt.input, tt.preserveFloat)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeBinValue(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("normalizeBinValue(%v) = %v (%T), want %v (%T)", tt.input, result, result, tt.expected, tt.expected)
 			}
 		})
@@ -234,7 +469,10 @@ func TestNormalizeBins(t *testing.T) {
 		"active": true,
 	}
 
-	normalized := normalizeBins(bins)
+	normalized, err := normalizeBins(bins, false)
+	if err != nil {
+		t.Fatalf("normalizeBins() error = %v", err)
+	}
 
 	// count should be converted to int64
 	if v, ok := normalized["count"].(int64); !ok || v != 100 {
@@ -356,3 +594,304 @@ func TestParseInfoString(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildClientPolicyPool(t *testing.T) {
+	tests := []struct {
+		name  string
+		pool  config.ClientPolicyConfig
+		check func(*as.ClientPolicy) bool
+	}{
+		{"defaults", config.ClientPolicyConfig{}, func(p *as.ClientPolicy) bool {
+			return p.ConnectionQueueSize == 100 && p.MaxErrorRate == 100 && p.ErrorRateWindow == 1 &&
+				p.IdleTimeout == 0 && p.LoginTimeout == 10*time.Second && p.TendInterval == time.Second
+		}},
+		{"wan", config.ClientPolicyConfig{
+			ConnectionQueueSize:   256,
+			MinConnectionsPerNode: 16,
+			MaxErrorRate:          500,
+			ErrorRateWindow:       5,
+			IdleTimeoutMs:         55000,
+			LoginTimeoutMs:        30000,
+			TendIntervalMs:        3000,
+		}, func(p *as.ClientPolicy) bool {
+			return p.ConnectionQueueSize == 256 && p.MinConnectionsPerNode == 16 && p.MaxErrorRate == 500 &&
+				p.ErrorRateWindow == 5 && p.IdleTimeout == 55*time.Second && p.LoginTimeout == 30*time.Second &&
+				p.TendInterval == 3*time.Second
+		}},
+		{"error limit disabled", config.ClientPolicyConfig{MaxErrorRate: -1}, func(p *as.ClientPolicy) bool {
+			return p.MaxErrorRate == 0
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := buildClientPolicy(&config.Config{TimeoutMs: 1000, ClientPolicy: tt.pool})
+			if err != nil {
+				t.Fatalf("buildClientPolicy() error = %v", err)
+			}
+			if !tt.check(policy) {
+				t.Errorf("Unexpected client policy: %+v", policy)
+			}
+		})
+	}
+}
+
+func TestBuildClientPolicyServicesAlternate(t *testing.T) {
+	for _, alternate := range []bool{false, true} {
+		policy, err := buildClientPolicy(&config.Config{TimeoutMs: 1000, UseServicesAlternate: alternate})
+		if err != nil {
+			t.Fatalf("buildClientPolicy() error = %v", err)
+		}
+		if policy.UseServicesAlternate != alternate {
+			t.Errorf("Expected UseServicesAlternate %v, got %v", alternate, policy.UseServicesAlternate)
+		}
+	}
+}
+
+func TestRackAwarePolicies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RackAware = true
+	cfg.RackIDs = []int{2, 1}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	clientPolicy, err := buildClientPolicy(cfg)
+	if err != nil {
+		t.Fatalf("buildClientPolicy() error = %v", err)
+	}
+	if !clientPolicy.RackAware || len(clientPolicy.RackIds) != 2 || clientPolicy.RackIds[0] != 2 {
+		t.Errorf("Expected rack-aware client policy preferring rack 2, got %v %v", clientPolicy.RackAware, clientPolicy.RackIds)
+	}
+
+	c := newClient(cfg)
+	for name, replica := range map[string]as.ReplicaPolicy{
+		"read":  c.readPolicy.ReplicaPolicy,
+		"batch": c.batchPolicy.ReplicaPolicy,
+		"query": c.queryPolicy.ReplicaPolicy,
+	} {
+		if replica != as.PREFER_RACK {
+			t.Errorf("Expected %s replica policy PREFER_RACK, got %v", name, replica)
+		}
+	}
+	if c.writePolicy.ReplicaPolicy != as.SEQUENCE {
+		t.Errorf("Expected write replica policy SEQUENCE, got %v", c.writePolicy.ReplicaPolicy)
+	}
+}
+
+func TestOperationPolicies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Policies = config.OperationPolicies{
+		Read:  config.OperationPolicy{TotalTimeoutMs: 500, SocketTimeoutMs: 200, SleepBetweenRetriesMs: 10, SleepMultiplier: 2},
+		Scan:  config.OperationPolicy{TotalTimeoutMs: 60000, SocketTimeoutMs: 5000},
+		Batch: config.OperationPolicy{SocketTimeoutMs: 300},
+	}
+	c := newClient(cfg)
+
+	tests := []struct {
+		name       string
+		policy     *as.BasePolicy
+		total      time.Duration
+		socket     time.Duration
+		sleep      time.Duration
+		multiplier float64
+	}{
+		{"read", c.readPolicy, 500 * time.Millisecond, 200 * time.Millisecond, 10 * time.Millisecond, 2},
+		{"scan", &c.scanPolicy.BasePolicy, time.Minute, 5 * time.Second, time.Millisecond, 1},
+		{"batch keeps timeout_ms", &c.batchPolicy.BasePolicy, time.Second, 300 * time.Millisecond, time.Millisecond, 1},
+		{"write defaults", &c.writePolicy.BasePolicy, time.Second, 30 * time.Second, time.Millisecond, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.policy
+			if p.TotalTimeout != tt.total || p.SocketTimeout != tt.socket || p.SleepBetweenRetries != tt.sleep || p.SleepMultiplier != tt.multiplier {
+				t.Errorf("Expected %v/%v/%v/%g, got %v/%v/%v/%g", tt.total, tt.socket, tt.sleep, tt.multiplier,
+					p.TotalTimeout, p.SocketTimeout, p.SleepBetweenRetries, p.SleepMultiplier)
+			}
+		})
+	}
+}
+
+func TestLazyConnect(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Hosts = []config.Host{{Host: "127.0.0.1", Port: 1}}
+	cfg.TimeoutMs = 200
+
+	if _, err := NewClient(cfg); err == nil {
+		t.Fatal("Expected an error connecting to an unavailable cluster")
+	}
+
+	cfg.LazyConnect = true
+	c, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() with lazy_connect error = %v", err)
+	}
+	defer c.Close()
+
+	if c.IsConnected() {
+		t.Error("Expected the client not to be connected")
+	}
+	if _, err := c.Ping(context.Background()); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Expected ErrNotConnected from Ping, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := c.WaitConnected(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected WaitConnected to time out, got %v", err)
+	}
+}
+
+func TestBatchRead(t *testing.T) {
+	key, err := as.NewKey("test", "users", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := batchRead(key, []string{"name", "email"})
+	if read.ReadAllBins || len(read.BinNames) != 2 {
+		t.Errorf("Expected only the named bins to be read, got %+v", read)
+	}
+	if read = batchRead(key, nil); !read.ReadAllBins {
+		t.Error("Expected all bins to be read when none are named")
+	}
+}
+
+func TestKeyString(t *testing.T) {
+	tests := []struct {
+		value as.Value
+		want  string
+	}{
+		{as.NewStringValue("user:1"), "user:1"},
+		{as.NewIntegerValue(42), "42"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := keyString(tt.value); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestRecordJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		record *Record
+		want   string
+	}{
+		{
+			name:   "found",
+			record: &Record{Key: "user:1", Digest: "ab", Namespace: "test", Set: "users", Bins: map[string]interface{}{"age": 30}, Generation: 2},
+			want:   `{"key":"user:1","digest":"ab","namespace":"test","set":"users","bins":{"age":30},"generation":2,"expiration":0,"ttl_seconds_remaining":0,"expires_at":""}`,
+		},
+		{
+			name:   "not found",
+			record: &Record{Key: "user:2", Digest: "cd", Namespace: "test", Set: "users", missing: true},
+			want:   `{"found":false,"key":"user:2","digest":"cd","namespace":"test","set":"users"}`,
+		},
+		{
+			name:   "scanned without a stored key",
+			record: &Record{Digest: "ef", Namespace: "test", Bins: map[string]interface{}{}},
+			want:   `{"digest":"ef","namespace":"test","bins":{},"generation":0,"expiration":0,"ttl_seconds_remaining":0,"expires_at":""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal([]*Record{tt.record})
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if got := string(data); got != "["+tt.want+"]" {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+			if tt.record.Found() == tt.record.missing {
+				t.Errorf("Expected Found() = %v", !tt.record.missing)
+			}
+		})
+	}
+}
+
+func TestSetExpiration(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expiration uint32
+		wantTTL    int64
+		wantAt     string
+	}{
+		{3600, 3600, "2024-05-01T13:00:00Z"},
+		{1, 1, "2024-05-01T12:00:01Z"},
+		{math.MaxUint32, -1, "never"},
+		{0, -1, "never"},
+	}
+
+	for _, tt := range tests {
+		var rec Record
+		rec.setExpiration(tt.expiration, now)
+		if rec.Expiration != tt.expiration || rec.TTLRemaining != tt.wantTTL || rec.ExpiresAt != tt.wantAt {
+			t.Errorf("setExpiration(%d): expected %d and %q, got %d and %q",
+				tt.expiration, tt.wantTTL, tt.wantAt, rec.TTLRemaining, rec.ExpiresAt)
+		}
+	}
+}
+
+func TestTakeLastUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		bins     map[string]interface{}
+		want     string
+		wantBins int
+	}{
+		{"int", map[string]interface{}{"age": 30, lastUpdateBin: 1714564800123000000}, "2024-05-01T12:00:00.123Z", 1},
+		{"int64", map[string]interface{}{lastUpdateBin: int64(1714564800000000000)}, "2024-05-01T12:00:00Z", 0},
+		{"not returned", map[string]interface{}{"age": 30}, "", 1},
+		{"evaluated to nothing", map[string]interface{}{lastUpdateBin: nil}, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := takeLastUpdate(tt.bins); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+			if len(tt.bins) != tt.wantBins {
+				t.Errorf("Expected %d bins left, got %v", tt.wantBins, tt.bins)
+			}
+		})
+	}
+}
+
+func TestQueryFilterBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  QueryFilter
+		wantErr string
+	}{
+		{name: "equal string", filter: QueryFilter{BinName: "city", FilterType: "equal", Value: "Paris"}},
+		{name: "equal number from JSON", filter: QueryFilter{BinName: "age", FilterType: "equal", Value: float64(30)}},
+		{name: "equal fraction", filter: QueryFilter{BinName: "age", FilterType: "equal", Value: 30.5}, wantErr: "string or an integer"},
+		{name: "range", filter: QueryFilter{BinName: "age", FilterType: "range", Begin: 18, End: 65}},
+		{name: "contains value", filter: QueryFilter{BinName: "tags", FilterType: "contains", CollectionType: CollectionList, Value: "premium"}},
+		{name: "contains range", filter: QueryFilter{BinName: "scores", FilterType: "contains", CollectionType: CollectionMapValues, Begin: 1, End: 10}},
+		{name: "contains without collection type", filter: QueryFilter{BinName: "tags", FilterType: "contains", Value: "premium"}, wantErr: "collection_type"},
+		{name: "contains with unknown collection type", filter: QueryFilter{BinName: "tags", FilterType: "contains", CollectionType: "SET", Value: "x"}, wantErr: "invalid collection type"},
+		{name: "unknown filter type", filter: QueryFilter{BinName: "loc", FilterType: "geo"}, wantErr: "unsupported filter_type"},
+		{name: "no filter type", filter: QueryFilter{BinName: "age"}, wantErr: "unsupported filter_type"},
+		{name: "no bin", filter: QueryFilter{FilterType: "equal", Value: "x"}, wantErr: "bin_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.filter.build()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || f == nil {
+				t.Errorf("Expected a filter, got %v", err)
+			}
+		})
+	}
+}
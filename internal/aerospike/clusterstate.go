@@ -0,0 +1,122 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// ClusterState is a snapshot of the cluster's membership and health.
+type ClusterState struct {
+	Nodes               []string `json:"nodes"`                // active node names, sorted
+	MigrationsRemaining int64    `json:"migrations_remaining"` // partitions left to migrate, summed over nodes
+	StopWrites          []string `json:"stop_writes"`          // namespaces refusing writes on any node, sorted
+}
+
+// ClusterState asks every active node, in parallel, for its namespaces'
+// migration and stop-writes status. It fails if any node doesn't answer,
+// naming each node that failed.
+func (c *Client) ClusterState(ctx context.Context) (*ClusterState, error) {
+	client := c.conn()
+	if client == nil || !client.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	nodes := slices.DeleteFunc(slices.Clone(client.GetNodes()), func(node *as.Node) bool {
+		return !node.IsActive()
+	})
+	health := make([]nodeHealth, len(nodes))
+
+	start := time.Now()
+	err := errors.Join(c.eachNode(ctx, nodes, func(ctx context.Context, node *as.Node, i int) error {
+		var err error
+		health[i], err = requestNodeHealth(node, infoPolicyFor(ctx))
+		return err
+	})...)
+	c.observe(ctx, "cluster_state", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return mergeHealth(health), nil
+}
+
+// nodeHealth is one node's part of the ClusterState.
+type nodeHealth struct {
+	name       string
+	migrations int64
+	stopWrites []string
+}
+
+// requestNodeHealth asks a node for its namespaces and then their status.
+func requestNodeHealth(node *as.Node, policy *as.InfoPolicy) (nodeHealth, error) {
+	health := nodeHealth{name: node.GetName()}
+
+	info, err := node.RequestInfo(policy, "namespaces")
+	if err != nil {
+		return health, fmt.Errorf("requesting namespaces: %w", err)
+	}
+	var commands []string
+	for _, ns := range strings.Split(info["namespaces"], ";") {
+		if ns != "" {
+			commands = append(commands, "namespace/"+ns)
+		}
+	}
+	if len(commands) == 0 {
+		return health, nil
+	}
+
+	info, err = node.RequestInfo(policy, commands...)
+	if err != nil {
+		return health, fmt.Errorf("requesting namespace status: %w", err)
+	}
+	for _, command := range commands {
+		migrations, stopWrites := namespaceHealth(info[command])
+		health.migrations += migrations
+		if stopWrites {
+			health.stopWrites = append(health.stopWrites, strings.TrimPrefix(command, "namespace/"))
+		}
+	}
+	return health, nil
+}
+
+// mergeHealth combines the nodes' health into the cluster's state.
+func mergeHealth(nodes []nodeHealth) *ClusterState {
+	state := &ClusterState{Nodes: []string{}, StopWrites: []string{}}
+	stopped := make(map[string]bool)
+	for _, node := range nodes {
+		state.Nodes = append(state.Nodes, node.name)
+		state.MigrationsRemaining += node.migrations
+		for _, ns := range node.stopWrites {
+			stopped[ns] = true
+		}
+	}
+
+	for ns := range stopped {
+		state.StopWrites = append(state.StopWrites, ns)
+	}
+	sort.Strings(state.Nodes)
+	sort.Strings(state.StopWrites)
+	return state
+}
+
+// namespaceHealth reads the partitions a node still has to send or receive
+// and whether it refuses writes from a namespace's statistics.
+func namespaceHealth(info string) (migrations int64, stopWrites bool) {
+	stats := parseInfoString(info)
+	for _, name := range []string{"migrate_tx_partitions_remaining", "migrate_rx_partitions_remaining"} {
+		n, _ := strconv.ParseInt(stats[name], 10, 64)
+		migrations += n
+	}
+	stopWrites = stats["stop_writes"] == "true" || stats["clock_skew_stop_writes"] == "true"
+	return migrations, stopWrites
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNamespaceHealth(t *testing.T) {
+	tests := []struct {
+		name           string
+		info           string
+		wantMigrations int64
+		wantStopWrites bool
+	}{
+		{"healthy", "objects=10;migrate_tx_partitions_remaining=0;migrate_rx_partitions_remaining=0;stop_writes=false", 0, false},
+		{"migrating", "migrate_tx_partitions_remaining=12;migrate_rx_partitions_remaining=3;stop_writes=false", 15, false},
+		{"stop writes", "stop_writes=true", 0, true},
+		{"clock skew", "stop_writes=false;clock_skew_stop_writes=true", 0, true},
+		{"empty", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, stopWrites := namespaceHealth(tt.info)
+			if migrations != tt.wantMigrations || stopWrites != tt.wantStopWrites {
+				t.Errorf("Expected %d migrations and stop_writes=%v, got %d and %v",
+					tt.wantMigrations, tt.wantStopWrites, migrations, stopWrites)
+			}
+		})
+	}
+}
+
+func TestMergeHealth(t *testing.T) {
+	state := mergeHealth([]nodeHealth{
+		{name: "BB9", migrations: 4, stopWrites: []string{"test"}},
+		{name: "BB7", migrations: 2, stopWrites: []string{"ads", "test"}},
+		{name: "BB8"},
+	})
+
+	if got := strings.Join(state.Nodes, ","); got != "BB7,BB8,BB9" {
+		t.Errorf("Expected sorted nodes, got %s", got)
+	}
+	if state.MigrationsRemaining != 6 {
+		t.Errorf("Expected 6 migrations remaining, got %d", state.MigrationsRemaining)
+	}
+	if got := strings.Join(state.StopWrites, ","); got != "ads,test" {
+		t.Errorf("Expected stop_writes ads,test, got %s", got)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// ErrIncomplete is returned, along with the records read so far, by scans
+// and queries that run out of time before they finish.
+var ErrIncomplete = errors.New("deadline reached before all records were read")
+
+// applyDeadline shortens policy's timeouts so the operation ends by ctx's
+// deadline. The client library doesn't take a context, so this is how
+// deadlines reach the cluster.
+func applyDeadline(ctx context.Context, policy *as.BasePolicy) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	remaining := time.Until(deadline)
+	if remaining < time.Millisecond {
+		remaining = time.Millisecond
+	}
+	if policy.TotalTimeout != 0 && policy.TotalTimeout <= remaining {
+		return
+	}
+	policy.TotalTimeout = remaining
+	if policy.SocketTimeout > remaining {
+		policy.SocketTimeout = remaining
+	}
+}
+
+// readPolicyFor returns the read policy for namespace with ctx's read
+// options and call policy, limited to ctx's deadline.
+func (c *Client) readPolicyFor(ctx context.Context, namespace string) *as.BasePolicy {
+	policy := *c.readPolicy
+	c.applyNamespaceTimeout(&policy, namespace)
+	applyReadOptions(ctx, &policy)
+	applyCallPolicy(ctx, &policy)
+	applyDeadline(ctx, &policy)
+	return &policy
+}
+
+// writePolicyFor returns the write policy for namespace with ctx's call
+// policy, limited to ctx's deadline.
+func (c *Client) writePolicyFor(ctx context.Context, namespace string) *as.WritePolicy {
+	policy := *c.writePolicy
+	c.applyNamespaceTimeout(&policy.BasePolicy, namespace)
+	applyCallPolicy(ctx, &policy.BasePolicy)
+	policy.DurableDelete, policy.SendKey = writeOptions(ctx, policy.DurableDelete, policy.SendKey)
+	applyDeadline(ctx, &policy.BasePolicy)
+	return &policy
+}
+
+// batchPolicyFor returns the batch policy for namespaces with ctx's read
+// options and call policy, limited to ctx's deadline.
+func (c *Client) batchPolicyFor(ctx context.Context, namespaces ...string) *as.BatchPolicy {
+	policy := *c.batchPolicy
+	c.applyNamespaceTimeout(&policy.BasePolicy, namespaces...)
+	applyReadOptions(ctx, &policy.BasePolicy)
+	applyCallPolicy(ctx, &policy.BasePolicy)
+	applyDeadline(ctx, &policy.BasePolicy)
+	return &policy
+}
+
+// scanPolicyFor returns the scan policy for namespace with ctx's scan
+// throttle and call policy, limited to ctx's deadline.
+func (c *Client) scanPolicyFor(ctx context.Context, namespace string) *as.ScanPolicy {
+	policy := *c.scanPolicy
+	c.applyNamespaceTimeout(&policy.BasePolicy, namespace)
+	applyCallPolicy(ctx, &policy.BasePolicy)
+	applyScanThrottle(ctx, &policy.MultiPolicy)
+	applyFilterExpression(ctx, &policy.BasePolicy)
+	applyDeadline(ctx, &policy.BasePolicy)
+	return &policy
+}
+
+// queryPolicyFor returns the query policy for namespace with ctx's read
+// options, scan throttle, and call policy, limited to ctx's deadline.
+func (c *Client) queryPolicyFor(ctx context.Context, namespace string) *as.QueryPolicy {
+	policy := *c.queryPolicy
+	c.applyNamespaceTimeout(&policy.BasePolicy, namespace)
+	applyReadOptions(ctx, &policy.BasePolicy)
+	applyCallPolicy(ctx, &policy.BasePolicy)
+	applyScanThrottle(ctx, &policy.MultiPolicy)
+	applyFilterExpression(ctx, &policy.BasePolicy)
+	applyDeadline(ctx, &policy.BasePolicy)
+	return &policy
+}
+
+// adminPolicyFor returns the default policy for index and UDF commands,
+// limited to ctx's deadline.
+func adminPolicyFor(ctx context.Context) *as.WritePolicy {
+	policy := as.NewWritePolicy(0, 0)
+	applyDeadline(ctx, &policy.BasePolicy)
+	return policy
+}
+
+// infoPolicyFor returns the info policy for ctx: one that times out at its
+// deadline, or the default.
+func infoPolicyFor(ctx context.Context) *as.InfoPolicy {
+	policy := as.NewInfoPolicy()
+	if deadline, ok := ctx.Deadline(); ok {
+		policy.Timeout = max(time.Until(deadline), time.Millisecond)
+	}
+	return policy
+}
+
+// deadlineReached reports whether a scan or query failed because it ran
+// out of time, rather than for another reason.
+func deadlineReached(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || Code(err) == ErrorTimeout
+}
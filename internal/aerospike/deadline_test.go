@@ -0,0 +1,54 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+func TestApplyDeadline(t *testing.T) {
+	tests := []struct {
+		name        string
+		deadline    time.Duration // 0 for no deadline
+		total       time.Duration
+		wantClamped bool
+	}{
+		{"no deadline", 0, time.Second, false},
+		{"shorter deadline", 100 * time.Millisecond, time.Second, true},
+		{"longer deadline", time.Minute, time.Second, false},
+		{"no policy timeout", 100 * time.Millisecond, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+			policy := as.NewPolicy()
+			policy.TotalTimeout = tt.total
+			policy.SocketTimeout = 30 * time.Second
+
+			applyDeadline(ctx, policy)
+			if !tt.wantClamped {
+				if policy.TotalTimeout != tt.total || policy.SocketTimeout != 30*time.Second {
+					t.Errorf("Expected timeouts unchanged, got total %v and socket %v", policy.TotalTimeout, policy.SocketTimeout)
+				}
+				return
+			}
+			if policy.TotalTimeout <= 0 || policy.TotalTimeout > tt.deadline {
+				t.Errorf("Expected total timeout within %v, got %v", tt.deadline, policy.TotalTimeout)
+			}
+			if policy.SocketTimeout > policy.TotalTimeout {
+				t.Errorf("Expected socket timeout within %v, got %v", policy.TotalTimeout, policy.SocketTimeout)
+			}
+		})
+	}
+}
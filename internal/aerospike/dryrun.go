@@ -0,0 +1,284 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// BinChange describes how a write would change a bin. A nil value means the
+// bin is absent.
+type BinChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// MarshalJSON serializes the change with its values encoded as records
+// are.
+func (c BinChange) MarshalJSON() ([]byte, error) {
+	type binChange BinChange // without this method
+	return json.Marshal(binChange{Before: encodeValue(c.Before), After: encodeValue(c.After)})
+}
+
+// DryRunResult describes what a write would change, without performing it.
+type DryRunResult struct {
+	DryRun     bool                 `json:"dry_run"`
+	Operation  string               `json:"operation"`
+	Namespace  string               `json:"namespace"`
+	Set        string               `json:"set,omitempty"`
+	Key        string               `json:"key"`
+	Exists     bool                 `json:"exists"`
+	Generation uint32               `json:"generation"`        // current generation
+	TTL        uint32               `json:"ttl"`               // current remaining TTL in seconds
+	NewTTL     *int                 `json:"new_ttl,omitempty"` // TTL the write would set
+	Changes    map[string]BinChange `json:"changes,omitempty"`
+	Error      string               `json:"error,omitempty"` // why the write would fail
+}
+
+// current reads the record a write would affect. A missing record is not an
+// error, but a key the request may not access is.
+func (c *Client) current(ctx context.Context, namespace, setName, keyValue string) (*DryRunResult, map[string]interface{}, error) {
+	if err := c.checkKey(ctx, namespace, setName, keyValue); err != nil {
+		return nil, nil, err
+	}
+
+	key, err := as.NewKey(namespace, setName, keyValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating key: %w", err)
+	}
+
+	result := &DryRunResult{DryRun: true, Namespace: namespace, Set: setName, Key: keyValue}
+
+	start := time.Now()
+	rec, err := c.conn().Get(c.readPolicyFor(ctx, namespace), key)
+	c.observe(ctx, "get", start, err)
+	if err != nil && !errors.Is(err, as.ErrKeyNotFound) {
+		return nil, nil, fmt.Errorf("reading current record: %w", err)
+	}
+	if err != nil || rec == nil {
+		return result, map[string]interface{}{}, nil
+	}
+
+	result.Exists = true
+	result.Generation = rec.Generation
+	result.TTL = rec.Expiration
+	return result, rec.Bins, nil
+}
+
+// DryRunPut reports what PutRecord would change.
+func (c *Client) DryRunPut(ctx context.Context, namespace, setName, keyValue string, bins map[string]interface{}, deleteBins []string, ttl int) (*DryRunResult, error) {
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanWrite() {
+		return nil, &config.RoleError{Operation: "write operations", Role: role}
+	}
+
+	result, before, err := c.current(ctx, namespace, setName, keyValue)
+	if err != nil {
+		return nil, err
+	}
+	result.Operation = "put"
+	result.NewTTL = &ttl
+	mode := writeModeFrom(ctx)
+	if result.Changes, err = putChanges(before, bins, deleteBins, mode, c.preserveFloat(ctx)); err != nil {
+		return nil, err
+	}
+	result.Error = mode.check(result.Exists)
+	return result, nil
+}
+
+// DryRunDelete reports what DeleteRecord would change.
+func (c *Client) DryRunDelete(ctx context.Context, namespace, setName, keyValue string) (*DryRunResult, error) {
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanWrite() {
+		return nil, &config.RoleError{Operation: "write operations", Role: role}
+	}
+
+	result, before, err := c.current(ctx, namespace, setName, keyValue)
+	if err != nil {
+		return nil, err
+	}
+	result.Operation = "delete"
+	result.Changes = deleteChanges(before)
+	return result, nil
+}
+
+// DryRunBatchWrite reports what BatchWrite would change. Operations that
+// would fail are reported with an error rather than failing the batch.
+func (c *Client) DryRunBatchWrite(ctx context.Context, requests []BatchWriteRequest) ([]DryRunResult, error) {
+	if role := c.config.EffectiveRole(ctx); !role.CanWrite() {
+		return nil, &config.RoleError{Operation: "write operations", Role: role}
+	}
+
+	if err := c.checkBatchSize(len(requests)); err != nil {
+		return nil, err
+	}
+
+	results := make([]DryRunResult, len(requests))
+	for i, req := range requests {
+		operation := req.Operation
+		if operation == "" {
+			operation = "put"
+		}
+
+		result, before, err := c.current(ctx, req.Namespace, req.Set, req.Key)
+		if role := c.config.NamespaceRole(ctx, req.Namespace); err == nil && !role.CanWrite() {
+			err = &config.RoleError{Operation: "write operations", Role: role}
+		}
+		if err != nil {
+			results[i] = DryRunResult{DryRun: true, Operation: operation, Namespace: req.Namespace, Set: req.Set, Key: req.Key, Error: err.Error()}
+			continue
+		}
+		result.Operation = operation
+
+		switch operation {
+		case "put":
+			ttl := req.TTL
+			result.NewTTL = &ttl
+			mode := req.writeMode(ctx)
+			changes, err := putChanges(before, req.Bins, req.DeleteBins, mode, c.preserveFloat(ctx))
+			if err == nil {
+				err = mode.Validate()
+			}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Error = mode.check(result.Exists)
+			}
+			result.Changes = changes
+		case "delete":
+			result.Changes = deleteChanges(before)
+			if req.Mode != "" {
+				result.Error = "mode applies only to put operations"
+			}
+		default:
+			result.Error = fmt.Sprintf("unknown operation: %s", req.Operation)
+		}
+		if result.Error == "" {
+			result.Error = req.checkGeneration(result.Exists, result.Generation)
+		}
+		results[i] = *result
+	}
+
+	return results, nil
+}
+
+// DryRunOperate reports what Operate would change. Increments, appends, and
+// prepends are applied to the current bin values to predict the result.
+func (c *Client) DryRunOperate(ctx context.Context, namespace, setName, keyValue string, operations []OperateRequest, ttl int) (*DryRunResult, error) {
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanWrite() {
+		return nil, &config.RoleError{Operation: "write operations", Role: role}
+	}
+
+	if _, err := buildOperations(operations); err != nil {
+		return nil, err
+	}
+
+	result, before, err := c.current(ctx, namespace, setName, keyValue)
+	if err != nil {
+		return nil, err
+	}
+	result.Operation = "operate"
+	result.NewTTL = &ttl
+
+	changes, err := operateChanges(before, operations)
+	if err != nil {
+		return nil, err
+	}
+	result.Changes = changes
+	return result, nil
+}
+
+// putChanges returns the bin changes a put of bins and deleteBins would make.
+// Bins not named in the put are left unchanged, unless mode replaces the
+// record's bins, which removes them.
+func putChanges(before, bins map[string]interface{}, deleteBins []string, mode WriteMode, preserveFloat bool) (map[string]BinChange, error) {
+	normalized, err := writeBins(bins, deleteBins, preserveFloat)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]BinChange, len(normalized))
+	for name, value := range normalized {
+		changes[name] = BinChange{Before: before[name], After: value}
+	}
+	if mode == WriteModeReplace || mode == WriteModeReplaceOnly {
+		for name, value := range before {
+			if _, ok := changes[name]; !ok {
+				changes[name] = BinChange{Before: value}
+			}
+		}
+	}
+	return changes, nil
+}
+
+// deleteChanges returns the bin changes deleting a record would make.
+func deleteChanges(before map[string]interface{}) map[string]BinChange {
+	changes := make(map[string]BinChange, len(before))
+	for name, value := range before {
+		changes[name] = BinChange{Before: value}
+	}
+	return changes
+}
+
+// operateChanges applies operations to a copy of the current bins and
+// returns the bins that would change.
+func operateChanges(before map[string]interface{}, operations []OperateRequest) (map[string]BinChange, error) {
+	after := make(map[string]interface{}, len(before))
+	for name, value := range before {
+		after[name] = value
+	}
+
+	changes := make(map[string]BinChange)
+	for _, op := range operations {
+		current := after[op.BinName]
+
+		var next interface{}
+		switch op.Type {
+		case OpIncrement:
+			delta, _ := toInt64(op.Value)
+			switch v := current.(type) {
+			case nil:
+				next = delta
+			case float64:
+				next = v + float64(delta)
+			default:
+				n, ok := toInt64(v)
+				if !ok {
+					return nil, fmt.Errorf("increment requires integer bin %s, found %T", op.BinName, current)
+				}
+				next = n + delta
+			}
+		case OpAppend, OpPrepend:
+			s, ok := current.(string)
+			if current != nil && !ok {
+				return nil, fmt.Errorf("%s requires string bin %s, found %T", op.Type, op.BinName, current)
+			}
+			if op.Type == OpAppend {
+				next = s + op.Value.(string)
+			} else {
+				next = op.Value.(string) + s
+			}
+		default:
+			if cdtWrites[op.Type] || op.Type == OpExpWrite {
+				return nil, fmt.Errorf("dry run cannot predict %s on bin %s", op.Type, op.BinName)
+			}
+			// touch, read, CDT reads, and exp_read don't change bin values
+			continue
+		}
+
+		after[op.BinName] = next
+		change, ok := changes[op.BinName]
+		if !ok {
+			change.Before = current
+		}
+		change.After = next
+		changes[op.BinName] = change
+	}
+	return changes, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPutChanges(t *testing.T) {
+	before := map[string]interface{}{"name": "alice", "age": 30}
+	changes, err := putChanges(before, map[string]interface{}{"age": float64(31), "city": "Paris"}, nil, "", false)
+	if err != nil {
+		t.Fatalf("putChanges() error = %v", err)
+	}
+
+	expected := map[string]BinChange{
+		"age":  {Before: 30, After: int64(31)},
+		"city": {Before: nil, After: "Paris"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, changes)
+	}
+
+	if _, err := putChanges(before, map[string]interface{}{"age": map[string]interface{}{"$int": "x"}}, nil, "", false); err == nil {
+		t.Error("Expected an invalid type hint to fail")
+	}
+
+	changes, err = putChanges(before, map[string]interface{}{"age": float64(31)}, nil, WriteModeReplace, false)
+	if err != nil {
+		t.Fatalf("putChanges() error = %v", err)
+	}
+	expected = map[string]BinChange{
+		"age":  {Before: 30, After: int64(31)},
+		"name": {Before: "alice"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected replace to remove other bins: %v, got %v", expected, changes)
+	}
+}
+
+func TestWriteMode(t *testing.T) {
+	tests := []struct {
+		mode      WriteMode
+		exists    string
+		missing   string
+		wantValid bool
+	}{
+		{mode: "", wantValid: true},
+		{mode: WriteModeCreateOnly, exists: "record already exists (mode create_only)", wantValid: true},
+		{mode: WriteModeUpdateOnly, missing: "record not found (mode update_only)", wantValid: true},
+		{mode: WriteModeReplace, wantValid: true},
+		{mode: WriteModeReplaceOnly, missing: "record not found (mode replace_only)", wantValid: true},
+		{mode: "upsert"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			if err := tt.mode.Validate(); (err == nil) != tt.wantValid {
+				t.Fatalf("Expected valid %v, got %v", tt.wantValid, err)
+			}
+			if !tt.wantValid {
+				return
+			}
+			if got := tt.mode.check(true); got != tt.exists {
+				t.Errorf("Expected %q for an existing record, got %q", tt.exists, got)
+			}
+			if got := tt.mode.check(false); got != tt.missing {
+				t.Errorf("Expected %q for a missing record, got %q", tt.missing, got)
+			}
+		})
+	}
+}
+
+func TestWriteBins(t *testing.T) {
+	tests := []struct {
+		name       string
+		bins       map[string]interface{}
+		deleteBins []string
+		want       map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "write and delete",
+			bins:       map[string]interface{}{"age": float64(31)},
+			deleteBins: []string{"city"},
+			want:       map[string]interface{}{"age": int64(31), "city": nil},
+		},
+		{
+			name:       "delete only",
+			deleteBins: []string{"city", "name"},
+			want:       map[string]interface{}{"city": nil, "name": nil},
+		},
+		{
+			name:       "written and deleted",
+			bins:       map[string]interface{}{"city": "Paris"},
+			deleteBins: []string{"city"},
+			wantErr:    true,
+		},
+		{
+			name:    "nothing to write",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := writeBins(tt.bins, tt.deleteBins, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("writeBins() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDeleteChanges(t *testing.T) {
+	changes := deleteChanges(map[string]interface{}{"name": "alice"})
+
+	expected := map[string]BinChange{"name": {Before: "alice", After: nil}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, changes)
+	}
+}
+
+func TestOperateChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     map[string]interface{}
+		operations []OperateRequest
+		expected   map[string]BinChange
+		wantErr    bool
+	}{
+		{
+			name:   "increment existing and missing bins",
+			before: map[string]interface{}{"count": 5},
+			operations: []OperateRequest{
+				{Type: OpIncrement, BinName: "count", Value: float64(2)},
+				{Type: OpIncrement, BinName: "count", Value: float64(3)},
+				{Type: OpIncrement, BinName: "views", Value: float64(1)},
+			},
+			expected: map[string]BinChange{
+				"count": {Before: 5, After: int64(10)},
+				"views": {Before: nil, After: int64(1)},
+			},
+		},
+		{
+			name:   "append and prepend",
+			before: map[string]interface{}{"path": "/b"},
+			operations: []OperateRequest{
+				{Type: OpPrepend, BinName: "path", Value: "/a"},
+				{Type: OpAppend, BinName: "path", Value: "/c"},
+			},
+			expected: map[string]BinChange{
+				"path": {Before: "/b", After: "/a/b/c"},
+			},
+		},
+		{
+			name:       "touch and read change nothing",
+			before:     map[string]interface{}{"count": 5},
+			operations: []OperateRequest{{Type: OpTouch}, {Type: OpRead, BinName: "count"}},
+			expected:   map[string]BinChange{},
+		},
+		{
+			name:       "increment string bin",
+			before:     map[string]interface{}{"name": "alice"},
+			operations: []OperateRequest{{Type: OpIncrement, BinName: "name", Value: float64(1)}},
+			wantErr:    true,
+		},
+		{
+			name:       "CDT reads change nothing",
+			before:     map[string]interface{}{"tags": []interface{}{"a"}},
+			operations: []OperateRequest{{Type: OpListSize, BinName: "tags"}},
+			expected:   map[string]BinChange{},
+		},
+		{
+			name:       "CDT write",
+			before:     map[string]interface{}{"tags": []interface{}{"a"}},
+			operations: []OperateRequest{{Type: OpListAppend, BinName: "tags", Value: "b"}},
+			wantErr:    true,
+		},
+		{
+			name:       "expression write",
+			before:     map[string]interface{}{"score": 120},
+			operations: []OperateRequest{{Type: OpExpWrite, BinName: "score", Expression: map[string]interface{}{"op": "ttl"}}},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := operateChanges(tt.before, tt.operations)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("operateChanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(changes, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, changes)
+			}
+		})
+	}
+}
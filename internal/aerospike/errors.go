@@ -0,0 +1,76 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// ErrorCode classifies why an operation failed, so callers can decide
+// whether to retry, back off, or give up without parsing error messages.
+type ErrorCode string
+
+const (
+	ErrorNotFound       ErrorCode = "not_found"       // the record doesn't exist
+	ErrorExists         ErrorCode = "exists"          // the record already exists
+	ErrorTimeout        ErrorCode = "timeout"         // the client or server timed out
+	ErrorHotKey         ErrorCode = "hot_key"         // too many concurrent operations on the record
+	ErrorDeviceOverload ErrorCode = "device_overload" // storage isn't keeping up with writes
+	ErrorForbidden      ErrorCode = "forbidden"       // the role, key rules, or cluster refused the operation
+	ErrorQuotaExceeded  ErrorCode = "quota_exceeded"  // a cluster or server quota was exceeded
+	ErrorUnavailable    ErrorCode = "unavailable"     // the cluster can't be reached or is busy
+	ErrorInvalid        ErrorCode = "invalid_request" // the cluster rejected the request's parameters
+	ErrorInternal       ErrorCode = "internal"        // any other failure
+)
+
+// Code classifies err. It returns "" for a nil error.
+func Code(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	var roleErr *config.RoleError
+	switch {
+	case errors.As(err, &roleErr), errors.Is(err, ErrKeyNotPermitted), errors.Is(err, ErrAuditSetReadOnly):
+		return ErrorForbidden
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorTimeout
+	case errors.Is(err, ErrNotConnected), errors.Is(err, ErrScanLimit):
+		return ErrorUnavailable
+	case errors.Is(err, ErrInvalidCursor), errors.Is(err, ErrLargeScan):
+		return ErrorInvalid
+	}
+
+	var asErr as.Error
+	if !errors.As(err, &asErr) {
+		return ErrorInternal
+	}
+	switch {
+	case asErr.Matches(types.KEY_NOT_FOUND_ERROR):
+		return ErrorNotFound
+	case asErr.Matches(types.KEY_EXISTS_ERROR):
+		return ErrorExists
+	case asErr.Matches(types.TIMEOUT):
+		return ErrorTimeout
+	case asErr.Matches(types.KEY_BUSY):
+		return ErrorHotKey
+	case asErr.Matches(types.DEVICE_OVERLOAD):
+		return ErrorDeviceOverload
+	case asErr.Matches(types.ROLE_VIOLATION, types.FAIL_FORBIDDEN, types.NOT_AUTHENTICATED, types.NOT_WHITELISTED):
+		return ErrorForbidden
+	case asErr.Matches(types.QUOTA_EXCEEDED):
+		return ErrorQuotaExceeded
+	case asErr.Matches(types.SERVER_NOT_AVAILABLE, types.INVALID_NODE_ERROR, types.NO_AVAILABLE_CONNECTIONS_TO_NODE, types.NETWORK_ERROR):
+		return ErrorUnavailable
+	case asErr.Matches(types.PARAMETER_ERROR, types.RECORD_TOO_BIG, types.BIN_TYPE_ERROR):
+		return ErrorInvalid
+	}
+	return ErrorInternal
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"nil", nil, ""},
+		{"not found", fmt.Errorf("deleting record: %w", as.ErrKeyNotFound), ErrorNotFound},
+		{"record exists", &as.AerospikeError{ResultCode: types.KEY_EXISTS_ERROR}, ErrorExists},
+		{"client timeout", fmt.Errorf("getting record: %w", as.ErrTimeout), ErrorTimeout},
+		{"context deadline", fmt.Errorf("scanning: %w", context.DeadlineExceeded), ErrorTimeout},
+		{"hot key", &as.AerospikeError{ResultCode: types.KEY_BUSY}, ErrorHotKey},
+		{"device overload", &as.AerospikeError{ResultCode: types.DEVICE_OVERLOAD}, ErrorDeviceOverload},
+		{"cluster role", &as.AerospikeError{ResultCode: types.ROLE_VIOLATION}, ErrorForbidden},
+		{"server role", &config.RoleError{Operation: "write operations", Role: config.RoleReadOnly}, ErrorForbidden},
+		{"key rules", fmt.Errorf("%w: test/users/1", ErrKeyNotPermitted), ErrorForbidden},
+		{"cluster quota", &as.AerospikeError{ResultCode: types.QUOTA_EXCEEDED}, ErrorQuotaExceeded},
+		{"not connected", ErrNotConnected, ErrorUnavailable},
+		{"parameter", &as.AerospikeError{ResultCode: types.PARAMETER_ERROR}, ErrorInvalid},
+		{"unconfirmed large scan", fmt.Errorf("%w: test.users holds 200000 records", ErrLargeScan), ErrorInvalid},
+		{"other", errors.New("boom"), ErrorInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
+)
+
+// ExistsResult reports whether a key has a record, and if so the record's
+// generation and TTL.
+type ExistsResult struct {
+	Key          string `json:"key"`
+	Exists       bool   `json:"exists"`
+	Generation   uint32 `json:"generation,omitempty"`
+	TTLRemaining int64  `json:"ttl_seconds_remaining,omitempty"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+}
+
+// BatchExists checks which keys of a set have records. It reads only the
+// records' headers, so no bins are transferred.
+func (c *Client) BatchExists(ctx context.Context, namespace, setName string, keys []string) ([]ExistsResult, error) {
+	if err := c.checkBatchSize(len(keys)); err != nil {
+		return nil, err
+	}
+
+	reads := make([]*as.BatchRead, len(keys))
+	records := make([]as.BatchRecordIfc, len(keys))
+	for i, keyValue := range keys {
+		if err := c.checkKey(ctx, namespace, setName, keyValue); err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		key, err := as.NewKey(namespace, setName, keyValue)
+		if err != nil {
+			return nil, fmt.Errorf("creating key %d: %w", i, err)
+		}
+		reads[i] = as.NewBatchReadHeader(nil, key)
+		records[i] = reads[i]
+	}
+
+	for _, err := range c.batchOperate(ctx, "batch_exists", c.batchPolicyFor(ctx, namespace), records) {
+		if err != nil {
+			return nil, fmt.Errorf("batch exists: %w", err)
+		}
+	}
+
+	now := time.Now()
+	results := make([]ExistsResult, len(reads))
+	for i, read := range reads {
+		result, err := existsResult(keys[i], read.BatchRec(), now)
+		if err != nil {
+			return nil, fmt.Errorf("batch exists: key %d: %w", i, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// existsResult converts the outcome of one header read.
+func existsResult(keyValue string, read *as.BatchRecord, now time.Time) (ExistsResult, error) {
+	result := ExistsResult{Key: keyValue}
+	switch read.ResultCode {
+	case types.OK:
+	case types.KEY_NOT_FOUND_ERROR:
+		return result, nil
+	default:
+		if read.Err != nil {
+			return result, read.Err
+		}
+		return result, fmt.Errorf("%s", types.ResultCodeToString(read.ResultCode))
+	}
+	if read.Record == nil {
+		return result, nil
+	}
+
+	result.Exists = true
+	result.Generation = read.Record.Generation
+	result.TTLRemaining, result.ExpiresAt = expiry(read.Record.Expiration, now)
+	return result, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"testing"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/aerospike/aerospike-client-go/v7/types"
+)
+
+func TestExistsResult(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		read    as.BatchRecord
+		want    ExistsResult
+		wantErr bool
+	}{
+		{
+			name: "exists",
+			read: as.BatchRecord{ResultCode: types.OK, Record: &as.Record{Generation: 3, Expiration: 60}},
+			want: ExistsResult{Key: "k", Exists: true, Generation: 3, TTLRemaining: 60, ExpiresAt: "2024-05-01T12:01:00Z"},
+		},
+		{
+			name: "never expires",
+			read: as.BatchRecord{ResultCode: types.OK, Record: &as.Record{Generation: 1, Expiration: neverExpires}},
+			want: ExistsResult{Key: "k", Exists: true, Generation: 1, TTLRemaining: -1, ExpiresAt: "never"},
+		},
+		{
+			name: "missing",
+			read: as.BatchRecord{ResultCode: types.KEY_NOT_FOUND_ERROR},
+			want: ExistsResult{Key: "k"},
+		},
+		{
+			name:    "failed",
+			read:    as.BatchRecord{ResultCode: types.TIMEOUT},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := existsResult("k", &tt.read, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("existsResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// Expression operation types. They evaluate an expression on the server,
+// within the same atomic operate call, and return its value under the
+// operation's bin name or write it to that bin.
+const (
+	OpExpRead  OperationType = "exp_read"
+	OpExpWrite OperationType = "exp_write"
+)
+
+// An expression is given as JSON:
+//
+//	42, 1.5, "text", true, null       a literal; {"$float": 42} and
+//	                                  {"$int": 42} fix a number's type
+//	{"bin": "score", "type": "int"}   a bin's value, of type int, float,
+//	                                  string, bool, list, map, blob, or geo
+//	{"op": "min", "args": [...]}      an operator applied to expressions
+//	{"op": "bin_exists", "bin": "x"}  whether a bin exists
+//
+// Integers and floats don't mix in arithmetic or comparisons, so a literal
+// compared with a float bin must be a float.
+
+// expBins are the bin expressions by bin type.
+var expBins = map[string]func(name string) *as.Expression{
+	"int":    as.ExpIntBin,
+	"float":  as.ExpFloatBin,
+	"string": as.ExpStringBin,
+	"bool":   as.ExpBoolBin,
+	"list":   as.ExpListBin,
+	"map":    as.ExpMapBin,
+	"blob":   as.ExpBlobBin,
+	"geo":    as.ExpGeoBin,
+}
+
+// expMetadata are the operators reading record metadata, which take no
+// arguments. unknown makes an exp_write with the eval_no_fail flag leave
+// the bin unchanged, which with cond gives a conditional update.
+var expMetadata = map[string]func() *as.Expression{
+	"ttl":          as.ExpTTL,
+	"last_update":  as.ExpLastUpdate,
+	"since_update": as.ExpSinceUpdate,
+	"record_size":  as.ExpRecordSize,
+	"key_exists":   as.ExpKeyExists,
+	"unknown":      as.ExpUnknown,
+}
+
+// expUnary are the operators taking one argument.
+var expUnary = map[string]func(*as.Expression) *as.Expression{
+	"not":      as.ExpNot,
+	"abs":      as.ExpNumAbs,
+	"floor":    as.ExpNumFloor,
+	"ceil":     as.ExpNumCeil,
+	"to_int":   as.ExpToInt,
+	"to_float": as.ExpToFloat,
+}
+
+// expBinary are the operators taking two arguments.
+var expBinary = map[string]func(*as.Expression, *as.Expression) *as.Expression{
+	"eq":  as.ExpEq,
+	"ne":  as.ExpNotEq,
+	"gt":  as.ExpGreater,
+	"ge":  as.ExpGreaterEq,
+	"lt":  as.ExpLess,
+	"le":  as.ExpLessEq,
+	"mod": as.ExpNumMod,
+	"pow": as.ExpNumPow,
+}
+
+// expVariadic are the operators taking two or more arguments.
+var expVariadic = map[string]func(...*as.Expression) *as.Expression{
+	"add": as.ExpNumAdd,
+	"sub": as.ExpNumSub,
+	"mul": as.ExpNumMul,
+	"div": as.ExpNumDiv,
+	"min": as.ExpMin,
+	"max": as.ExpMax,
+	"and": as.ExpAnd,
+	"or":  as.ExpOr,
+}
+
+// expWriteFlags are the flags an exp_write accepts.
+var expWriteFlags = map[string]as.ExpWriteFlags{
+	"create_only":    as.ExpWriteFlagCreateOnly,
+	"update_only":    as.ExpWriteFlagUpdateOnly,
+	"allow_delete":   as.ExpWriteFlagAllowDelete,
+	"policy_no_fail": as.ExpWriteFlagPolicyNoFail,
+	"eval_no_fail":   as.ExpWriteFlagEvalNoFail,
+}
+
+// buildExpOperation converts an expression operation request.
+func buildExpOperation(op OperateRequest) (*as.Operation, error) {
+	if op.Expression == nil {
+		return nil, fmt.Errorf("%s requires an expression for bin %s", op.Type, op.BinName)
+	}
+	exp, err := buildExpression(op.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("%s expression for bin %s: %w", op.Type, op.BinName, err)
+	}
+
+	if op.Type == OpExpRead {
+		flags := as.ExpReadFlagDefault
+		for _, flag := range op.Flags {
+			if flag != "eval_no_fail" {
+				return nil, fmt.Errorf("exp_read flag %q is not supported (must be eval_no_fail)", flag)
+			}
+			flags |= as.ExpReadFlagEvalNoFail
+		}
+		return as.ExpReadOp(op.BinName, exp, flags), nil
+	}
+
+	flags := as.ExpWriteFlagDefault
+	for _, flag := range op.Flags {
+		f, ok := expWriteFlags[flag]
+		if !ok {
+			return nil, fmt.Errorf("exp_write flag %q is not supported (must be one of %s)", flag, strings.Join(sortedKeys(expWriteFlags), ", "))
+		}
+		flags |= f
+	}
+	return as.ExpWriteOp(op.BinName, exp, flags), nil
+}
+
+// buildExpression converts an expression from its JSON form, or a literal
+// int64 built in code.
+func buildExpression(v interface{}) (*as.Expression, error) {
+	switch val := v.(type) {
+	case nil:
+		return as.ExpNilValue(), nil
+	case bool:
+		return as.ExpBoolVal(val), nil
+	case string:
+		return as.ExpStringVal(val), nil
+	case int64:
+		return as.ExpIntVal(val), nil
+	case float64:
+		if n, ok := wholeNumber(val); ok {
+			return as.ExpIntVal(n), nil
+		}
+		return as.ExpFloatVal(val), nil
+	case map[string]interface{}:
+		return buildExpressionNode(val)
+	default:
+		return nil, fmt.Errorf("unsupported expression %v", v)
+	}
+}
+
+// buildExpressionNode converts a typed literal, bin, or operator.
+func buildExpressionNode(node map[string]interface{}) (*as.Expression, error) {
+	if _, isHint, err := typeHint(node, false); isHint || err != nil {
+		return typedLiteral(node)
+	}
+
+	if name, ok := node["bin"]; ok && node["op"] == nil {
+		binName, ok := name.(string)
+		if !ok || binName == "" {
+			return nil, fmt.Errorf("bin needs a bin name, got %v", name)
+		}
+		binType, _ := node["type"].(string)
+		bin, ok := expBins[binType]
+		if !ok {
+			return nil, fmt.Errorf("bin %s needs a type (one of %s), got %v", binName, strings.Join(sortedKeys(expBins), ", "), node["type"])
+		}
+		return bin(binName), nil
+	}
+
+	op, ok := node["op"].(string)
+	if !ok {
+		return nil, fmt.Errorf(`expression objects need an "op" or a "bin", got %v`, node)
+	}
+	if op == "bin_exists" {
+		binName, ok := node["bin"].(string)
+		if !ok || binName == "" {
+			return nil, fmt.Errorf("bin_exists needs a bin name")
+		}
+		return as.ExpBinExists(binName), nil
+	}
+	if metadata, ok := expMetadata[op]; ok {
+		return metadata(), nil
+	}
+
+	rawArgs, _ := node["args"].([]interface{})
+	args := make([]*as.Expression, len(rawArgs))
+	for i, raw := range rawArgs {
+		arg, err := buildExpression(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s args[%d]: %w", op, i, err)
+		}
+		args[i] = arg
+	}
+
+	if fn, ok := expUnary[op]; ok {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s takes 1 argument, got %d", op, len(args))
+		}
+		return fn(args[0]), nil
+	}
+	if fn, ok := expBinary[op]; ok {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%s takes 2 arguments, got %d", op, len(args))
+		}
+		return fn(args[0], args[1]), nil
+	}
+	if fn, ok := expVariadic[op]; ok {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("%s takes at least 2 arguments, got %d", op, len(args))
+		}
+		return fn(args...), nil
+	}
+	if op == "cond" {
+		// condition, value pairs followed by the default value
+		if len(args) < 3 || len(args)%2 == 0 {
+			return nil, fmt.Errorf("cond takes condition and value pairs and a default value, got %d arguments", len(args))
+		}
+		return as.ExpCond(args...), nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+// typedLiteral converts a number wrapped in a $float or $int hint.
+func typedLiteral(node map[string]interface{}) (*as.Expression, error) {
+	value, _, err := typeHint(node, false)
+	if err != nil {
+		return nil, err
+	}
+	switch n := value.(type) {
+	case int64:
+		return as.ExpIntVal(n), nil
+	case float64:
+		return as.ExpFloatVal(n), nil
+	default:
+		return nil, fmt.Errorf("only %s and %s literals are supported in expressions", floatHint, intHint)
+	}
+}
+
+// sortedKeys returns a map's keys in order, for error messages.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// filterExpressionContextKey is the context key for a request's filter
+// expression.
+type filterExpressionContextKey struct{}
+
+// withFilterExpression returns a context whose scans and queries return
+// only the records exp matches.
+func withFilterExpression(ctx context.Context, exp *as.Expression) context.Context {
+	return context.WithValue(ctx, filterExpressionContextKey{}, exp)
+}
+
+// applyFilterExpression sets ctx's filter expression, if any, on policy.
+func applyFilterExpression(ctx context.Context, policy *as.BasePolicy) {
+	if exp, ok := ctx.Value(filterExpressionContextKey{}).(*as.Expression); ok {
+		policy.FilterExpression = exp
+	}
+}
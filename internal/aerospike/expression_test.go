@@ -0,0 +1,54 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildExpOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     string
+		wantErr string
+	}{
+		{name: "capped score", ops: `[{"type":"exp_write","bin_name":"score","expression":{"op":"min","args":[{"bin":"score","type":"int"},100]}}]`},
+		{name: "conditional update", ops: `[{"type":"exp_write","bin_name":"status","flags":["eval_no_fail"],"expression":{"op":"cond","args":[{"op":"gt","args":[{"bin":"score","type":"float"},{"$float":90}]},"gold",{"op":"unknown"}]}}]`},
+		{name: "read computed value", ops: `[{"type":"exp_read","bin_name":"total","expression":{"op":"add","args":[{"bin":"a","type":"int"},{"bin":"b","type":"int"},1]}}]`},
+		{name: "metadata and bin_exists", ops: `[{"type":"exp_read","bin_name":"fresh","flags":["eval_no_fail"],"expression":{"op":"and","args":[{"op":"bin_exists","bin":"a"},{"op":"lt","args":[{"op":"since_update"},60000]}]}}]`},
+		{name: "missing expression", ops: `[{"type":"exp_write","bin_name":"score"}]`, wantErr: "requires an expression"},
+		{name: "bin without type", ops: `[{"type":"exp_read","bin_name":"x","expression":{"bin":"score"}}]`, wantErr: "bin score needs a type"},
+		{name: "unknown operator", ops: `[{"type":"exp_read","bin_name":"x","expression":{"op":"median","args":[1,2]}}]`, wantErr: `unknown operator "median"`},
+		{name: "wrong arity", ops: `[{"type":"exp_read","bin_name":"x","expression":{"op":"eq","args":[1]}}]`, wantErr: "eq takes 2 arguments"},
+		{name: "cond without default", ops: `[{"type":"exp_read","bin_name":"x","expression":{"op":"cond","args":[true,1]}}]`, wantErr: "cond takes"},
+		{name: "nested error", ops: `[{"type":"exp_read","bin_name":"x","expression":{"op":"not","args":[{"op":"nope"}]}}]`, wantErr: "not args[0]"},
+		{name: "bad literal hint", ops: `[{"type":"exp_read","bin_name":"x","expression":{"$bytes":"AQ=="}}]`, wantErr: "only $float and $int"},
+		{name: "unknown write flag", ops: `[{"type":"exp_write","bin_name":"x","expression":1,"flags":["upsert"]}]`, wantErr: `flag "upsert"`},
+		{name: "write flag on read", ops: `[{"type":"exp_read","bin_name":"x","expression":1,"flags":["create_only"]}]`, wantErr: "must be eval_no_fail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []OperateRequest
+			if err := json.Unmarshal([]byte(tt.ops), &requests); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			ops, err := buildOperations(requests)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildOperations() error = %v", err)
+			}
+			if len(ops) != len(requests) {
+				t.Errorf("Expected %d operations, got %d", len(requests), len(ops))
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"fmt"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// geoJSONHint marks a bin value as GeoJSON, as {"$geojson": {...}}. Written
+// values with the hint are stored as GeoJSON, which GEO2DSPHERE indexes
+// require, rather than as strings or maps, and encodeValue returns GeoJSON
+// bins with it so they can be written back unchanged.
+const geoJSONHint = "$geojson"
+
+// geoJSONValue converts the value of a GeoJSON hint, a GeoJSON object or
+// its text, to a GeoJSON bin value.
+func geoJSONValue(v interface{}) (as.GeoJSONValue, error) {
+	text, isString := v.(string)
+	if !isString {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", geoJSONHint, err)
+		}
+		text = string(data)
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &object); err != nil {
+		return "", fmt.Errorf("%s needs a GeoJSON object", geoJSONHint)
+	}
+	if _, ok := object["type"].(string); !ok {
+		return "", fmt.Errorf("%s needs a GeoJSON object with a type", geoJSONHint)
+	}
+	return as.NewGeoJSONValue(text), nil
+}
+
+// geoJSONTag returns a GeoJSON value read from the cluster wrapped in the
+// GeoJSON hint.
+func geoJSONTag(geo as.GeoJSONValue) map[string]interface{} {
+	if json.Valid([]byte(geo)) {
+		return map[string]interface{}{geoJSONHint: json.RawMessage(geo)}
+	}
+	return map[string]interface{}{geoJSONHint: string(geo)}
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+func TestGeoJSONHint(t *testing.T) {
+	point := `{"type":"Point","coordinates":[-122.5,37.7]}`
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    as.GeoJSONValue
+		wantErr bool
+	}{
+		{name: "object", value: map[string]interface{}{"type": "Point", "coordinates": []interface{}{-122.5, 37.7}}, want: `{"coordinates":[-122.5,37.7],"type":"Point"}`},
+		{name: "text", value: point, want: as.GeoJSONValue(point)},
+		{name: "not JSON", value: "37.7,-122.5", wantErr: true},
+		{name: "no type", value: map[string]interface{}{"coordinates": []interface{}{1.0, 2.0}}, wantErr: true},
+		{name: "number", value: float64(1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeBinValue(map[string]interface{}{geoJSONHint: tt.value}, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeBinValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Expected %v (%T), got %v (%T)", tt.want, tt.want, got, got)
+			}
+		})
+	}
+}
+
+func TestEncodeGeoJSON(t *testing.T) {
+	point := `{"type":"Point","coordinates":[-122.5,37.7]}`
+	bins := encodeBins(map[string]interface{}{"loc": as.NewGeoJSONValue(point), "name": "cafe"})
+
+	data, err := json.Marshal(bins)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"loc":{"$geojson":` + point + `},"name":"cafe"}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	// The tagged bin writes back as GeoJSON
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	normalized, err := normalizeBins(decoded, false)
+	if err != nil {
+		t.Fatalf("normalizeBins() error = %v", err)
+	}
+	if _, ok := normalized["loc"].(as.GeoJSONValue); !ok {
+		t.Errorf("Expected loc to round-trip as GeoJSON, got %T", normalized["loc"])
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// IndexDetails is a secondary index's definition, with every field the
+// cluster lists for it in Config, and its statistics on each node.
+type IndexDetails struct {
+	IndexInfo
+	Config map[string]string `json:"config"`
+	Nodes  []IndexNodeStats  `json:"nodes"`
+}
+
+// IndexNodeStats is one node's statistics for an index. Error is set, and
+// Stats empty, if the node didn't answer.
+type IndexNodeStats struct {
+	Node  string            `json:"node"`
+	Stats map[string]string `json:"stats,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
+
+// GetIndexDetails returns an index's definition, as the first node lists
+// it, and its statistics from every active node, asked in parallel. It
+// fails only if the index isn't listed or no node answered.
+func (c *Client) GetIndexDetails(ctx context.Context, namespace, indexName string) (*IndexDetails, error) {
+	client := c.conn()
+	if client == nil || !client.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	listCommand := "sindex/" + namespace
+	start := time.Now()
+	info, err := client.GetNodes()[0].RequestInfo(infoPolicyFor(ctx), listCommand)
+	if err != nil {
+		c.observe(ctx, "index_details", start, err)
+		return nil, fmt.Errorf("requesting indexes: %w", err)
+	}
+	var details *IndexDetails
+	for _, line := range strings.Split(info[listCommand], ";") {
+		if idx, fields := parseIndexLine(namespace, line); idx.Name == indexName {
+			details = &IndexDetails{IndexInfo: idx, Config: fields}
+			break
+		}
+	}
+	if details == nil {
+		c.observe(ctx, "index_details", start, nil)
+		return nil, fmt.Errorf("index not found: %s", indexName)
+	}
+
+	nodes := slices.DeleteFunc(slices.Clone(client.GetNodes()), func(node *as.Node) bool {
+		return !node.IsActive()
+	})
+	details.Nodes = make([]IndexNodeStats, len(nodes))
+	statsCommand := "sindex/" + namespace + "/" + indexName
+	errs := c.eachNode(ctx, nodes, func(ctx context.Context, node *as.Node, i int) error {
+		details.Nodes[i].Node = node.GetName()
+		info, err := node.RequestInfo(infoPolicyFor(ctx), statsCommand)
+		if err != nil {
+			return err
+		}
+		if err := infoError(info[statsCommand]); err != nil {
+			return err
+		}
+		details.Nodes[i].Stats = parseInfoString(info[statsCommand])
+		return nil
+	})
+	nodeErr := errors.Join(errs...)
+	c.observe(ctx, "index_details", start, nodeErr)
+
+	answered := 0
+	for i, err := range errs {
+		if err != nil {
+			details.Nodes[i].Error = err.Error()
+			continue
+		}
+		answered++
+	}
+	if answered == 0 && len(nodes) > 0 {
+		return nil, fmt.Errorf("reading index statistics: %w", nodeErr)
+	}
+	return details, nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"testing"
+)
+
+func TestParseIndexLine(t *testing.T) {
+	line := "ns=test:indexname=idx_age:set=users:bin=age:type=numeric:indextype=default:context=NULL:state=RW"
+	idx, fields := parseIndexLine("test", line)
+
+	want := IndexInfo{Name: "idx_age", Namespace: "test", Set: "users", Bin: "age", Type: "numeric", State: "RW", indexType: "default", context: "NULL"}
+	if idx != want {
+		t.Errorf("Expected %+v, got %+v", want, idx)
+	}
+	if len(fields) != 8 || fields["ns"] != "test" || fields["indextype"] != "default" {
+		t.Errorf("Expected all 8 fields, got %v", fields)
+	}
+
+	if idx, fields := parseIndexLine("test", ""); idx.Name != "" || len(fields) != 0 {
+		t.Errorf("Expected nothing from an empty line, got %+v %v", idx, fields)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// ErrKeyNotPermitted is returned when a primary key doesn't match the
+// key_rules that apply to the request.
+var ErrKeyNotPermitted = errors.New("key not permitted")
+
+// keyRule is a config.KeyRule with its pattern compiled.
+type keyRule struct {
+	config.KeyRule
+	re *regexp.Regexp
+}
+
+// compileKeyRules compiles the key patterns, anchored to match whole keys.
+// Validate has already checked that they compile.
+func compileKeyRules(rules []config.KeyRule) []keyRule {
+	compiled := make([]keyRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile("^(?:" + rule.Pattern + ")$")
+		if err != nil {
+			continue
+		}
+		compiled = append(compiled, keyRule{KeyRule: rule, re: re})
+	}
+	return compiled
+}
+
+// applies reports whether the rule restricts keys in the set for role.
+func (r *keyRule) applies(namespace, setName string, role config.Role) bool {
+	if len(r.Roles) > 0 {
+		found := false
+		for _, rr := range r.Roles {
+			if rr == role {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return matchPattern(r.Namespace, namespace) && matchPattern(r.Set, setName)
+}
+
+// matchPattern matches a path.Match pattern, where an empty pattern matches
+// anything.
+func matchPattern(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// checkKey returns an error if keyValue doesn't match every key rule that
+// applies to the set for the request's role. Rules match the role the
+// client was granted, so read-only mode doesn't change which keys it sees.
+func (c *Client) checkKey(ctx context.Context, namespace, setName, keyValue string) error {
+	if len(c.keyRules) == 0 {
+		return nil
+	}
+	role := c.config.GrantedRole(ctx)
+	for i := range c.keyRules {
+		rule := &c.keyRules[i]
+		if rule.applies(namespace, setName, role) && !rule.re.MatchString(keyValue) {
+			return fmt.Errorf("%w: %q does not match pattern %q for %s/%s", ErrKeyNotPermitted, keyValue, rule.Pattern, namespace, setName)
+		}
+	}
+	return nil
+}
+
+// keyRestricted reports whether any key rule applies to the set for the
+// role granted to the request.
+func (c *Client) keyRestricted(ctx context.Context, namespace, setName string) bool {
+	role := c.config.GrantedRole(ctx)
+	for i := range c.keyRules {
+		if c.keyRules[i].applies(namespace, setName, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyVisible reports whether a scan or query result may be returned. When
+// key rules apply, records whose keys weren't stored with the record can't
+// be checked and are withheld.
+func (c *Client) keyVisible(ctx context.Context, namespace, setName string, keyValue interface{}) bool {
+	if len(c.keyRules) == 0 {
+		return true
+	}
+	if keyValue == nil {
+		return !c.keyRestricted(ctx, namespace, setName)
+	}
+	return c.checkKey(ctx, namespace, setName, fmt.Sprintf("%v", keyValue)) == nil
+}
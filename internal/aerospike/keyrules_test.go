@@ -0,0 +1,130 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func newKeyRuleClient(rules ...config.KeyRule) *Client {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleAdmin
+	cfg.KeyRules = rules
+	return &Client{config: cfg, keyRules: compileKeyRules(rules)}
+}
+
+func TestCheckKey(t *testing.T) {
+	c := newKeyRuleClient(
+		config.KeyRule{Namespace: "test", Pattern: "acme:.*"},
+		config.KeyRule{Namespace: "test", Set: "audit_*", Pattern: `acme:\d+`},
+		config.KeyRule{Namespace: "prod", Pattern: "acme:.*", Roles: []config.Role{config.RoleReadOnly}},
+	)
+	admin := context.Background()
+	readOnly := config.WithRole(context.Background(), config.RoleReadOnly)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		namespace string
+		set       string
+		key       string
+		wantErr   bool
+	}{
+		{"matching prefix", admin, "test", "users", "acme:42", false},
+		{"other tenant", admin, "test", "users", "globex:42", true},
+		{"pattern matches whole key", admin, "test", "users", "xacme:42", true},
+		{"all matching rules apply", admin, "test", "audit_log", "acme:abc", true},
+		{"all matching rules pass", admin, "test", "audit_log", "acme:7", false},
+		{"unrestricted namespace", admin, "other", "users", "globex:42", false},
+		{"rule for another role", admin, "prod", "users", "globex:42", false},
+		{"rule for this role", readOnly, "prod", "users", "globex:42", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.checkKey(tt.ctx, tt.namespace, tt.set, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrKeyNotPermitted) {
+				t.Errorf("Expected ErrKeyNotPermitted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyVisible(t *testing.T) {
+	c := newKeyRuleClient(config.KeyRule{Namespace: "test", Pattern: "acme:.*"})
+	ctx := context.Background()
+
+	if !c.keyVisible(ctx, "test", "users", "acme:1") {
+		t.Error("Expected matching key to be visible")
+	}
+	if c.keyVisible(ctx, "test", "users", "globex:1") {
+		t.Error("Expected other tenant's key to be withheld")
+	}
+	if c.keyVisible(ctx, "test", "users", nil) {
+		t.Error("Expected record without a stored key to be withheld")
+	}
+	if !c.keyVisible(ctx, "other", "users", nil) {
+		t.Error("Expected record in unrestricted namespace to be visible")
+	}
+}
+
+func TestKeyRulesEnforced(t *testing.T) {
+	c := newKeyRuleClient(config.KeyRule{Namespace: "test", Pattern: "acme:.*"})
+	ctx := context.Background()
+
+	// Each call is rejected before reaching the cluster
+	if _, err := c.GetRecord(ctx, "test", "users", "globex:1", nil); !errors.Is(err, ErrKeyNotPermitted) {
+		t.Errorf("GetRecord: expected ErrKeyNotPermitted, got %v", err)
+	}
+	if err := c.PutRecord(ctx, "test", "users", "globex:1", map[string]interface{}{"a": 1}, nil, 0); !errors.Is(err, ErrKeyNotPermitted) {
+		t.Errorf("PutRecord: expected ErrKeyNotPermitted, got %v", err)
+	}
+	if _, err := c.DeleteRecord(ctx, "test", "users", "globex:1"); !errors.Is(err, ErrKeyNotPermitted) {
+		t.Errorf("DeleteRecord: expected ErrKeyNotPermitted, got %v", err)
+	}
+	if _, err := c.BatchGet(ctx, []BatchGetRequest{{Namespace: "test", Key: "globex:1"}}); !errors.Is(err, ErrKeyNotPermitted) {
+		t.Errorf("BatchGet: expected ErrKeyNotPermitted, got %v", err)
+	}
+	if _, err := c.DryRunPut(ctx, "test", "users", "globex:1", nil, nil, 0); !errors.Is(err, ErrKeyNotPermitted) {
+		t.Errorf("DryRunPut: expected ErrKeyNotPermitted, got %v", err)
+	}
+	if err := c.TruncateSet(ctx, "test", "users"); !errors.Is(err, ErrKeyNotPermitted) {
+		t.Errorf("TruncateSet: expected ErrKeyNotPermitted, got %v", err)
+	}
+
+	results, err := c.BatchWrite(ctx, []BatchWriteRequest{{Namespace: "test", Key: "globex:1", Operation: "delete"}}, false)
+	if err != nil {
+		t.Fatalf("BatchWrite() error = %v", err)
+	}
+	if results[0].Success || results[0].Error == "" {
+		t.Errorf("BatchWrite: expected per-record error, got %+v", results[0])
+	}
+}
+
+func TestKeyRulesReadOnlyMode(t *testing.T) {
+	c := newKeyRuleClient(config.KeyRule{Namespace: "prod", Pattern: "acme:.*", Roles: []config.Role{config.RoleReadWrite}})
+	ctx := config.WithRole(context.Background(), config.RoleReadWrite)
+	c.config.SetReadOnly(true)
+
+	// Read-only mode lowers what the client may do, not which keys it sees
+	if err := c.checkKey(ctx, "prod", "users", "globex:1"); !errors.Is(err, ErrKeyNotPermitted) {
+		t.Errorf("Expected ErrKeyNotPermitted while read-only, got %v", err)
+	}
+	if _, err := c.GetRecord(ctx, "prod", "users", "globex:1", nil); !errors.Is(err, ErrKeyNotPermitted) {
+		t.Errorf("GetRecord: expected ErrKeyNotPermitted while read-only, got %v", err)
+	}
+	if c.keyVisible(ctx, "prod", "users", nil) {
+		t.Error("Expected record without a stored key to be withheld while read-only")
+	}
+	if err := c.checkKey(config.WithRole(context.Background(), config.RoleReadOnly), "prod", "users", "globex:1"); err != nil {
+		t.Errorf("Expected the rule not to apply to a read-only client, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// latenciesCommand is the info command returning a node's latency
+// histograms with the default thresholds of 1, 8, and 64 units.
+const latenciesCommand = "latencies:"
+
+// LatencyHistogram is one of a node's latency histograms, such as
+// {test}-read for reads in namespace test: its throughput, and the
+// percentage of operations slower than 1, 8, and 64 units, usually msec.
+type LatencyHistogram struct {
+	Node      string  `json:"node"`
+	Name      string  `json:"name"`
+	Unit      string  `json:"unit"`
+	OpsPerSec float64 `json:"ops_per_sec"`
+	PctOver1  float64 `json:"pct_over_1"`
+	PctOver8  float64 `json:"pct_over_8"`
+	PctOver64 float64 `json:"pct_over_64"`
+}
+
+// GetLatencies returns the latency histograms of every active node, asked
+// in parallel. Histograms without traffic since the server started are
+// left out. It fails if any node doesn't answer.
+func (c *Client) GetLatencies(ctx context.Context) ([]LatencyHistogram, error) {
+	client := c.conn()
+	if client == nil || !client.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	nodes := slices.DeleteFunc(slices.Clone(client.GetNodes()), func(node *as.Node) bool {
+		return !node.IsActive()
+	})
+	perNode := make([][]LatencyHistogram, len(nodes))
+
+	start := time.Now()
+	err := errors.Join(c.eachNode(ctx, nodes, func(ctx context.Context, node *as.Node, i int) error {
+		info, err := node.RequestInfo(infoPolicyFor(ctx), latenciesCommand)
+		if err != nil {
+			return err
+		}
+		if err := infoError(info[latenciesCommand]); err != nil {
+			return err
+		}
+		perNode[i] = parseLatencies(node.GetName(), info[latenciesCommand])
+		return nil
+	})...)
+	c.observe(ctx, "latencies", start, err)
+	if err != nil {
+		return nil, err
+	}
+	var histograms []LatencyHistogram
+	for _, h := range perNode {
+		histograms = append(histograms, h...)
+	}
+	return histograms, nil
+}
+
+// parseLatencies parses a latencies: response, whose histograms are
+// separated by semicolons, each name:unit,ops/sec,>1,>8,>64.
+func parseLatencies(node, response string) []LatencyHistogram {
+	var histograms []LatencyHistogram
+	for _, entry := range strings.Split(response, ";") {
+		name, value, ok := strings.Cut(entry, ":")
+		fields := strings.Split(value, ",")
+		if !ok || len(fields) < 5 {
+			continue
+		}
+		h := LatencyHistogram{Node: node, Name: name, Unit: fields[0]}
+		values := []*float64{&h.OpsPerSec, &h.PctOver1, &h.PctOver8, &h.PctOver64}
+		for i, v := range values {
+			*v, _ = strconv.ParseFloat(fields[i+1], 64)
+		}
+		histograms = append(histograms, h)
+	}
+	return histograms
+}
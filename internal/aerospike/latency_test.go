@@ -0,0 +1,30 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"testing"
+)
+
+func TestParseLatencies(t *testing.T) {
+	response := "batch-index:;{test}-read:msec,1520.3,2.10,0.35,0.01;{test}-write:usec,88.0,40.00,5.50,0.00;{test}-udf:;bad"
+
+	got := parseLatencies("BB9", response)
+	want := []LatencyHistogram{
+		{Node: "BB9", Name: "{test}-read", Unit: "msec", OpsPerSec: 1520.3, PctOver1: 2.10, PctOver8: 0.35, PctOver64: 0.01},
+		{Node: "BB9", Name: "{test}-write", Unit: "usec", OpsPerSec: 88, PctOver1: 40, PctOver8: 5.5},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d histograms, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], got[i])
+		}
+	}
+
+	if got := parseLatencies("BB9", ""); len(got) != 0 {
+		t.Errorf("Expected no histograms, got %+v", got)
+	}
+}
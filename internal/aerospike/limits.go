@@ -0,0 +1,130 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// ErrScanLimit is returned when starting a scan or query would exceed the
+// configured concurrency limits.
+var ErrScanLimit = errors.New("too many concurrent scans and queries")
+
+// ErrLargeScan is returned for a scan of a set holding more records than
+// scan_limits.confirm_above_records that wasn't confirmed.
+var ErrLargeScan = errors.New("scan of a large set requires confirm_large_scan")
+
+// scanLimiter bounds the number of scans and queries running at once, both
+// across the server and per client. Requests over the limit are rejected
+// rather than queued, so a client can't pile up work behind the limit.
+type scanLimiter struct {
+	max       int // 0 is unlimited
+	perClient int // 0 is unlimited
+
+	mu       sync.Mutex
+	running  int
+	byClient map[string]int
+}
+
+// newScanLimiter creates a limiter for cfg, or returns nil if it sets no
+// concurrency limits.
+func newScanLimiter(cfg config.ScanLimitConfig) *scanLimiter {
+	if cfg.MaxConcurrent <= 0 && cfg.MaxConcurrentPerClient <= 0 {
+		return nil
+	}
+	return &scanLimiter{
+		max:       cfg.MaxConcurrent,
+		perClient: cfg.MaxConcurrentPerClient,
+		byClient:  make(map[string]int),
+	}
+}
+
+// acquire reserves a slot for a scan or query by the client in ctx. The
+// returned function releases it.
+func (l *scanLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	client := audit.ClientKey(ctx)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.max > 0 && l.running >= l.max {
+		return nil, fmt.Errorf("%w: %d running on this server (max %d)", ErrScanLimit, l.running, l.max)
+	}
+	if l.perClient > 0 && l.byClient[client] >= l.perClient {
+		return nil, fmt.Errorf("%w: %d running for this client (max %d)", ErrScanLimit, l.byClient[client], l.perClient)
+	}
+
+	l.running++
+	l.byClient[client]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.running--
+			if l.byClient[client]--; l.byClient[client] <= 0 {
+				delete(l.byClient, client)
+			}
+		})
+	}, nil
+}
+
+// CheckLargeScan checks a scan of a set, or of a whole namespace if setName
+// is empty, against scan_limits.confirm_above_records. It returns the
+// records the cluster reports in the set if there are more than that, or 0
+// if there are not or the check is disabled. A larger set is an
+// ErrLargeScan error unless confirmed.
+func (c *Client) CheckLargeScan(ctx context.Context, namespace, setName string, confirmed bool) (int64, error) {
+	threshold := c.config.ScanLimits.ConfirmAboveRecords
+	if threshold <= 0 {
+		return 0, nil
+	}
+
+	var objects int64
+	if setName == "" {
+		info, err := c.DescribeNamespace(ctx, namespace)
+		if err != nil {
+			return 0, err
+		}
+		objects = info.ObjectCount
+	} else {
+		sets, err := c.ListSets(ctx, namespace)
+		if err != nil {
+			return 0, err
+		}
+		for _, set := range sets {
+			if set.Name == setName {
+				objects = set.ObjectCount
+			}
+		}
+	}
+
+	if objects <= threshold {
+		return 0, nil
+	}
+	if !confirmed {
+		return 0, fmt.Errorf("%w: %s holds %d records, more than %d; pass confirm_large_scan: true to scan it",
+			ErrLargeScan, scanTarget(namespace, setName), objects, threshold)
+	}
+	return objects, nil
+}
+
+// scanTarget names a set, or a namespace for a scan of every set.
+func scanTarget(namespace, setName string) string {
+	if setName == "" {
+		return "namespace " + namespace
+	}
+	return namespace + "." + setName
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestScanLimiter(t *testing.T) {
+	l := newScanLimiter(config.ScanLimitConfig{MaxConcurrent: 3, MaxConcurrentPerClient: 2})
+	alice := audit.WithUser(context.Background(), "alice")
+	bob := audit.WithUser(context.Background(), "bob")
+
+	release1, err := l.acquire(alice)
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if _, err := l.acquire(alice); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if _, err := l.acquire(alice); !errors.Is(err, ErrScanLimit) {
+		t.Errorf("Expected per-client limit, got %v", err)
+	}
+
+	if _, err := l.acquire(bob); err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	if _, err := l.acquire(bob); !errors.Is(err, ErrScanLimit) {
+		t.Errorf("Expected server limit, got %v", err)
+	}
+
+	// Releasing is idempotent and frees a slot
+	release1()
+	release1()
+	if _, err := l.acquire(bob); err != nil {
+		t.Errorf("Expected slot after release, got %v", err)
+	}
+	if l.running != 3 {
+		t.Errorf("Expected 3 running, got %d", l.running)
+	}
+}
+
+func TestScanLimiterUnlimited(t *testing.T) {
+	l := newScanLimiter(config.ScanLimitConfig{RecordsPerSecond: 500})
+	if l != nil {
+		t.Fatal("Expected no limiter without concurrency limits")
+	}
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	release()
+}
+
+func TestCheckLargeScan(t *testing.T) {
+	c := &Client{
+		config:   &config.Config{ScanLimits: config.ScanLimitConfig{ConfirmAboveRecords: 1000}},
+		metadata: newMetadataCache(time.Minute),
+	}
+	c.metadata.put(cacheSets+"test", []SetInfo{
+		{Name: "small", Namespace: "test", ObjectCount: 1000},
+		{Name: "large", Namespace: "test", ObjectCount: 1001},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		set       string
+		confirmed bool
+		want      int64
+		wantErr   bool
+	}{
+		{name: "small set", set: "small"},
+		{name: "large set", set: "large", wantErr: true},
+		{name: "confirmed large set", set: "large", confirmed: true, want: 1001},
+		{name: "unknown set", set: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.CheckLargeScan(ctx, "test", tt.set, tt.confirmed)
+			if tt.wantErr {
+				if !errors.Is(err, ErrLargeScan) {
+					t.Errorf("Expected ErrLargeScan, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CheckLargeScan() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+
+	c.config.ScanLimits.ConfirmAboveRecords = 0
+	if _, err := c.CheckLargeScan(ctx, "test", "large", false); err != nil {
+		t.Errorf("Expected no check when disabled, got %v", err)
+	}
+}
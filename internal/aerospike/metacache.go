@@ -0,0 +1,125 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metadata cache keys. Per-namespace entries append the namespace.
+const (
+	cacheNamespaces = "namespaces"
+	cacheSets       = "sets/"
+	cacheIndexes    = "sindex/"
+	cacheUDFs       = "udfs"
+)
+
+// metadataCache keeps the namespace, set, index, and UDF listings for a
+// short time, so tools and resources/list don't query every node on each
+// call. Admin operations invalidate the entries they change. A nil cache
+// caches nothing.
+type metadataCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// newMetadataCache creates a cache keeping entries for ttl, or returns nil
+// if ttl is not positive.
+func newMetadataCache(ttl time.Duration) *metadataCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &metadataCache{ttl: ttl, now: time.Now, entries: make(map[string]cacheEntry)}
+}
+
+// get returns the unexpired value for key.
+func (m *metadataCache) get(key string) (interface{}, bool) {
+	if m == nil {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok || !m.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// put stores value for key.
+func (m *metadataCache) put(key string, value interface{}) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = cacheEntry{value: value, expires: m.now().Add(m.ttl)}
+}
+
+// invalidate removes the entries for keys. A key ending in "/" removes
+// every entry it prefixes.
+func (m *metadataCache) invalidate(keys ...string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if !strings.HasSuffix(key, "/") {
+			delete(m.entries, key)
+			continue
+		}
+		for k := range m.entries {
+			if strings.HasPrefix(k, key) {
+				delete(m.entries, k)
+			}
+		}
+	}
+}
+
+// clear removes every entry.
+func (m *metadataCache) clear() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]cacheEntry)
+}
+
+// cached returns the cached listing for key, or loads and caches it. The
+// caller gets its own copy of the slice.
+func cached[T any](m *metadataCache, key string, load func() ([]T, error)) ([]T, error) {
+	if value, ok := m.get(key); ok {
+		return slices.Clone(value.([]T)), nil
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	m.put(key, slices.Clone(value))
+	return value, nil
+}
+
+// RefreshMetadata drops the cached metadata for namespace, or all cached
+// metadata if namespace is empty, so the next listing reads the cluster.
+func (c *Client) RefreshMetadata(_ context.Context, namespace string) {
+	if namespace == "" {
+		c.metadata.clear()
+		return
+	}
+	c.metadata.invalidate(cacheNamespaces, cacheSets+namespace, cacheIndexes+namespace)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMetadataCache(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	m := newMetadataCache(30 * time.Second)
+	m.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() ([]string, error) {
+		loads++
+		return []string{"a", "b"}, nil
+	}
+
+	tests := []struct {
+		name      string
+		advance   time.Duration
+		before    func()
+		wantLoads int
+	}{
+		{name: "first call loads", wantLoads: 1},
+		{name: "fresh entry is reused", advance: 10 * time.Second, wantLoads: 1},
+		{name: "expired entry reloads", advance: 30 * time.Second, wantLoads: 2},
+		{name: "invalidated entry reloads", before: func() { m.invalidate("sets/test") }, wantLoads: 3},
+		{name: "prefix invalidates", before: func() { m.invalidate(cacheSets) }, wantLoads: 4},
+		{name: "other key leaves entry", before: func() { m.invalidate("sets/other") }, wantLoads: 4},
+		{name: "clear reloads", before: func() { m.clear() }, wantLoads: 5},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+		if tt.before != nil {
+			tt.before()
+		}
+		sets, err := cached(m, "sets/test", load)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if len(sets) != 2 {
+			t.Errorf("%s: expected 2 sets, got %v", tt.name, sets)
+		}
+		if loads != tt.wantLoads {
+			t.Errorf("%s: expected %d loads, got %d", tt.name, tt.wantLoads, loads)
+		}
+	}
+}
+
+func TestMetadataCacheCopies(t *testing.T) {
+	m := newMetadataCache(time.Minute)
+	load := func() ([]string, error) { return []string{"a"}, nil }
+
+	first, _ := cached(m, cacheUDFs, load)
+	first[0] = "changed"
+
+	second, _ := cached(m, cacheUDFs, load)
+	if second[0] != "a" {
+		t.Errorf("Expected cached listing to be unaffected by callers, got %q", second[0])
+	}
+}
+
+func TestMetadataCacheErrors(t *testing.T) {
+	m := newMetadataCache(time.Minute)
+	loads := 0
+	fail := func() ([]string, error) {
+		loads++
+		return nil, errors.New("node unavailable")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cached(m, cacheNamespaces, fail); err == nil {
+			t.Fatal("Expected an error")
+		}
+	}
+	if loads != 2 {
+		t.Errorf("Expected errors not to be cached, got %d loads", loads)
+	}
+}
+
+func TestMetadataCacheDisabled(t *testing.T) {
+	m := newMetadataCache(0)
+	if m != nil {
+		t.Fatal("Expected a zero ttl to disable the cache")
+	}
+
+	loads := 0
+	load := func() ([]string, error) {
+		loads++
+		return []string{"a"}, nil
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := cached(m, cacheNamespaces, load); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	m.invalidate(cacheNamespaces)
+	m.clear()
+	if loads != 3 {
+		t.Errorf("Expected every call to load, got %d loads", loads)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// applyNamespaceTimeout replaces policy's total timeout with the shortest
+// timeout_ms set for the namespaces in their namespace policies.
+func (c *Client) applyNamespaceTimeout(policy *as.BasePolicy, namespaces ...string) {
+	var timeout time.Duration
+	for _, namespace := range namespaces {
+		ms := c.config.NamespacePolicy(namespace).TimeoutMs
+		if ms <= 0 {
+			continue
+		}
+		if d := time.Duration(ms) * time.Millisecond; timeout == 0 || d < timeout {
+			timeout = d
+		}
+	}
+	if timeout == 0 {
+		return
+	}
+	policy.TotalTimeout = timeout
+	if policy.SocketTimeout > timeout {
+		policy.SocketTimeout = timeout
+	}
+}
+
+// maxRecords returns the number of records a scan or query in namespace
+// may return: the requested number, or the default if none was requested,
+// limited by the namespace's max_records.
+func (c *Client) maxRecords(namespace string, requested int) int {
+	limit := c.config.NamespacePolicy(namespace).MaxRecords
+	switch {
+	case requested <= 0 && limit > 0:
+		return limit
+	case requested <= 0:
+		return c.config.DefaultMaxRecords
+	case limit > 0 && requested > limit:
+		return limit
+	}
+	return requested
+}
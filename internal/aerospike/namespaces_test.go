@@ -0,0 +1,107 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func newNamespaceClient() *Client {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadWrite
+	cfg.Namespaces = map[string]config.NamespacePolicy{
+		"test": {TimeoutMs: 5000, MaxRecords: 10000},
+		"prod": {TimeoutMs: 200, MaxRecords: 100, Role: config.RoleReadOnly},
+	}
+	policy := as.NewPolicy()
+	policy.TotalTimeout = time.Second
+	policy.SocketTimeout = time.Second
+	return &Client{config: cfg, readPolicy: policy, batchPolicy: as.NewBatchPolicy()}
+}
+
+func TestMaxRecords(t *testing.T) {
+	c := newNamespaceClient()
+
+	tests := []struct {
+		name      string
+		namespace string
+		requested int
+		expected  int
+	}{
+		{"namespace default", "prod", 0, 100},
+		{"namespace limit", "prod", 500, 100},
+		{"within namespace limit", "prod", 50, 50},
+		{"raised limit", "test", 5000, 5000},
+		{"server default", "other", 0, c.config.DefaultMaxRecords},
+		{"unlimited request", "other", 5000, 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.maxRecords(tt.namespace, tt.requested); got != tt.expected {
+				t.Errorf("Expected %d records, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNamespaceTimeout(t *testing.T) {
+	c := newNamespaceClient()
+
+	tests := []struct {
+		name      string
+		namespace string
+		total     time.Duration
+		socket    time.Duration
+	}{
+		{"shorter", "prod", 200 * time.Millisecond, 200 * time.Millisecond},
+		{"longer", "test", 5 * time.Second, time.Second},
+		{"no policy", "other", time.Second, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := c.readPolicyFor(context.Background(), tt.namespace)
+			if policy.TotalTimeout != tt.total || policy.SocketTimeout != tt.socket {
+				t.Errorf("Expected timeouts %v/%v, got %v/%v", tt.total, tt.socket, policy.TotalTimeout, policy.SocketTimeout)
+			}
+		})
+	}
+
+	// A batch across namespaces uses the shortest timeout
+	if got := c.batchPolicyFor(context.Background(), "test", "prod").TotalTimeout; got != 200*time.Millisecond {
+		t.Errorf("Expected batch timeout 200ms, got %v", got)
+	}
+}
+
+func TestNamespaceReadOnly(t *testing.T) {
+	c := newNamespaceClient()
+	ctx := config.WithRole(context.Background(), config.RoleAdmin)
+
+	var roleErr *config.RoleError
+	if err := c.PutRecord(ctx, "prod", "users", "k", map[string]interface{}{"a": 1}, nil, 0); !errors.As(err, &roleErr) {
+		t.Errorf("Expected RoleError for put in read-only namespace, got %v", err)
+	}
+	if _, err := c.DeleteRecord(ctx, "prod", "users", "k"); !errors.As(err, &roleErr) {
+		t.Errorf("Expected RoleError for delete in read-only namespace, got %v", err)
+	}
+	if err := c.TruncateSet(ctx, "prod", "users"); !errors.As(err, &roleErr) {
+		t.Errorf("Expected RoleError for truncate in read-only namespace, got %v", err)
+	}
+
+	results, err := c.BatchWrite(ctx, []BatchWriteRequest{{Namespace: "prod", Set: "users", Key: "k", Operation: "delete"}}, false)
+	if err != nil {
+		t.Fatalf("BatchWrite() error = %v", err)
+	}
+	if results[0].Success || results[0].Error == "" {
+		t.Errorf("Expected batch write in read-only namespace to fail, got %+v", results[0])
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// nodeConfigCommand is the info command returning a node's service
+// configuration.
+const nodeConfigCommand = "get-config:context=service"
+
+// NodeDetails is a node's metrics, as GetNodeStats reports them, and its
+// service configuration.
+type NodeDetails struct {
+	NodeStats
+	Config map[string]string `json:"config"`
+}
+
+// GetNodeDetails returns the statistics and service configuration of the
+// node named nodeName, read in one info request.
+func (c *Client) GetNodeDetails(ctx context.Context, nodeName string) (*NodeDetails, error) {
+	for _, node := range c.conn().GetNodes() {
+		if node.GetName() != nodeName {
+			continue
+		}
+
+		start := time.Now()
+		info, err := node.RequestInfo(infoPolicyFor(ctx), "statistics", nodeConfigCommand)
+		c.observe(ctx, "node_details", start, err)
+		if err != nil {
+			return nil, fmt.Errorf("requesting node %s details: %w", nodeName, err)
+		}
+		if err := infoError(info[nodeConfigCommand]); err != nil {
+			return nil, fmt.Errorf("reading node %s config: %w", nodeName, err)
+		}
+
+		details := &NodeDetails{NodeStats: NodeStats{Name: nodeName, Address: node.GetHost().String()}}
+		details.parse(parseInfoString(info["statistics"]))
+		details.Config = parseInfoString(info[nodeConfigCommand])
+		return details, nil
+	}
+	return nil, fmt.Errorf("node not found: %s", nodeName)
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// runParallel calls fn for items 0 to n-1, such as nodes, concurrently, at
+// most parallelism at a time, giving each call a context limited to timeout.
+// It returns each call's error in item order. Items not yet started when ctx
+// ends get ctx's error. A parallelism or timeout of zero means no limit.
+func runParallel(ctx context.Context, n, parallelism int, timeout time.Duration, fn func(ctx context.Context, i int) error) []error {
+	if parallelism <= 0 {
+		parallelism = n
+	}
+	errs := make([]error, n)
+	sem := make(chan struct{}, max(parallelism, 1))
+
+	var wg sync.WaitGroup
+loop:
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for ; i < n; i++ {
+				errs[i] = ctx.Err()
+			}
+			break loop
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			nodeCtx, cancel := ctx, context.CancelFunc(func() {})
+			if timeout > 0 {
+				nodeCtx, cancel = context.WithTimeout(ctx, timeout)
+			}
+			defer cancel()
+			errs[i] = fn(nodeCtx, i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
+
+// eachNode runs fn for every node with the configured node_info
+// parallelism and per-node timeout. Errors name the node that failed.
+func (c *Client) eachNode(ctx context.Context, nodes []*as.Node, fn func(ctx context.Context, node *as.Node, i int) error) []error {
+	limits := c.config.NodeInfo
+	return runParallel(ctx, len(nodes), limits.Parallelism, time.Duration(limits.TimeoutMs)*time.Millisecond,
+		func(ctx context.Context, i int) error {
+			if err := fn(ctx, nodes[i], i); err != nil {
+				return fmt.Errorf("node %s: %w", nodes[i].GetName(), err)
+			}
+			return nil
+		})
+}
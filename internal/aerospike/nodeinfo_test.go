@@ -0,0 +1,78 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunParallelParallelism(t *testing.T) {
+	var running, peak atomic.Int32
+	errs := runParallel(context.Background(), 10, 3, 0, func(ctx context.Context, i int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+
+	if len(errs) != 10 {
+		t.Fatalf("Expected 10 results, got %d", len(errs))
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("Expected at most 3 concurrent calls, got %d", p)
+	}
+}
+
+func TestRunParallelErrors(t *testing.T) {
+	errs := runParallel(context.Background(), 3, 0, 20*time.Millisecond, func(ctx context.Context, i int) error {
+		switch i {
+		case 1:
+			<-ctx.Done()
+			return ctx.Err()
+		case 2:
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	if errs[0] != nil {
+		t.Errorf("Expected node 0 to succeed, got %v", errs[0])
+	}
+	if !errors.Is(errs[1], context.DeadlineExceeded) {
+		t.Errorf("Expected node 1 to time out, got %v", errs[1])
+	}
+	if errs[2] == nil || errs[2].Error() != "connection refused" {
+		t.Errorf("Expected node 2's error, got %v", errs[2])
+	}
+}
+
+func TestRunParallelCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	errs := runParallel(ctx, 4, 1, 0, func(ctx context.Context, i int) error {
+		calls++
+		return nil
+	})
+
+	for i, err := range errs {
+		if err == nil && i >= calls {
+			t.Errorf("Expected node %d to report the cancellation", i)
+		}
+	}
+	if calls > 1 {
+		t.Errorf("Expected no more calls after cancellation, got %d", calls)
+	}
+}
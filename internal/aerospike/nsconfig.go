@@ -0,0 +1,184 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// maxDefaultTTL is the longest default-ttl the server accepts, ten years.
+const maxDefaultTTL = 10 * 365 * 24 * 60 * 60
+
+// NamespaceConfig is a namespace's default TTL and, if a set was named,
+// that set's configuration, as the first cluster node reports them.
+// DefaultTTL is in seconds; 0 means records don't expire.
+type NamespaceConfig struct {
+	Namespace  string     `json:"namespace"`
+	DefaultTTL int64      `json:"default_ttl"`
+	Set        *SetConfig `json:"set,omitempty"`
+}
+
+// SetConfig is a set's configuration. EnableIndex keeps a set index, so
+// scans of the set don't read the whole namespace; StopWritesCount refuses
+// writes once the set holds that many records, or never if it is 0.
+type SetConfig struct {
+	Name            string `json:"name"`
+	EnableIndex     bool   `json:"enable_index"`
+	StopWritesCount int64  `json:"stop_writes_count"`
+}
+
+// ConfigChange lists the settings to change; nil fields are left as they
+// are. DefaultTTL applies to the namespace, the others to a set.
+type ConfigChange struct {
+	DefaultTTL      *int64 `json:"default_ttl,omitempty"`
+	EnableIndex     *bool  `json:"enable_index,omitempty"`
+	StopWritesCount *int64 `json:"stop_writes_count,omitempty"`
+}
+
+// Validate returns an error if the change is empty, a value is out of
+// range, or a setting doesn't apply to the namespace or set named.
+func (ch ConfigChange) Validate(setName string) error {
+	if ch == (ConfigChange{}) {
+		return fmt.Errorf("no settings to change")
+	}
+	if ch.DefaultTTL != nil {
+		if setName != "" {
+			return fmt.Errorf("default_ttl is a namespace setting; omit set_name to change it")
+		}
+		if *ch.DefaultTTL < 0 || *ch.DefaultTTL > maxDefaultTTL {
+			return fmt.Errorf("default_ttl must be between 0 and %d seconds", maxDefaultTTL)
+		}
+	}
+	if (ch.EnableIndex != nil || ch.StopWritesCount != nil) && setName == "" {
+		return fmt.Errorf("enable_index and stop_writes_count are set settings and require set_name")
+	}
+	if ch.StopWritesCount != nil && *ch.StopWritesCount < 0 {
+		return fmt.Errorf("stop_writes_count must not be negative")
+	}
+	return nil
+}
+
+// commands returns the set-config info commands making the change, one
+// setting each.
+func (ch ConfigChange) commands(namespace, setName string) []string {
+	prefix := "set-config:context=namespace;id=" + namespace
+	if setName != "" {
+		prefix += ";set=" + setName
+	}
+	var commands []string
+	if ch.DefaultTTL != nil {
+		commands = append(commands, prefix+";default-ttl="+strconv.FormatInt(*ch.DefaultTTL, 10))
+	}
+	if ch.EnableIndex != nil {
+		commands = append(commands, prefix+";enable-index="+strconv.FormatBool(*ch.EnableIndex))
+	}
+	if ch.StopWritesCount != nil {
+		commands = append(commands, prefix+";stop-writes-count="+strconv.FormatInt(*ch.StopWritesCount, 10))
+	}
+	return commands
+}
+
+// GetNamespaceConfig returns a namespace's default TTL, and a set's
+// configuration if setName is not empty.
+func (c *Client) GetNamespaceConfig(ctx context.Context, namespace, setName string) (*NamespaceConfig, error) {
+	node := c.conn().GetNodes()[0]
+	nsCommand := "get-config:context=namespace;id=" + namespace
+	setCommand := "sets/" + namespace + "/" + setName
+	commands := []string{nsCommand}
+	if setName != "" {
+		commands = append(commands, setCommand)
+	}
+
+	start := time.Now()
+	info, err := node.RequestInfo(infoPolicyFor(ctx), commands...)
+	c.observe(ctx, "get_config", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("requesting namespace config: %w", err)
+	}
+	if err := infoError(info[nsCommand]); err != nil {
+		return nil, fmt.Errorf("reading namespace %s config: %w", namespace, err)
+	}
+
+	cfg := &NamespaceConfig{Namespace: namespace}
+	cfg.DefaultTTL, _ = strconv.ParseInt(parseInfoString(info[nsCommand])["default-ttl"], 10, 64)
+	if setName == "" {
+		return cfg, nil
+	}
+
+	// Set statistics are separated by colons
+	setInfo := strings.TrimSuffix(info[setCommand], ";")
+	if setInfo == "" {
+		return nil, fmt.Errorf("set not found: %s.%s", namespace, setName)
+	}
+	if err := infoError(setInfo); err != nil {
+		return nil, fmt.Errorf("reading set %s.%s config: %w", namespace, setName, err)
+	}
+	stats := parseInfoString(strings.ReplaceAll(setInfo, ":", ";"))
+	cfg.Set = &SetConfig{Name: setName, EnableIndex: stats["enable-index"] == "true"}
+	cfg.Set.StopWritesCount, _ = strconv.ParseInt(stats["stop-writes-count"], 10, 64)
+	return cfg, nil
+}
+
+// SetNamespaceConfig applies a configuration change to every active node,
+// in parallel, and returns the configuration before the change. Nodes keep
+// their settings independently, so it fails if any node refuses, naming
+// each; the nodes that accepted keep the change.
+func (c *Client) SetNamespaceConfig(ctx context.Context, namespace, setName string, change ConfigChange) (*NamespaceConfig, error) {
+	if role := c.config.NamespaceRole(ctx, namespace); !role.CanAdmin() {
+		return nil, &config.RoleError{Operation: "admin operations", Role: role}
+	}
+	if err := change.Validate(setName); err != nil {
+		return nil, err
+	}
+
+	before, err := c.GetNamespaceConfig(ctx, namespace, setName)
+	if err != nil {
+		return nil, err
+	}
+
+	// stop-writes-count is listed with the set
+	defer c.metadata.invalidate(cacheSets + namespace)
+
+	nodes := slices.DeleteFunc(slices.Clone(c.conn().GetNodes()), func(node *as.Node) bool {
+		return !node.IsActive()
+	})
+	commands := change.commands(namespace, setName)
+
+	start := time.Now()
+	err = errors.Join(c.eachNode(ctx, nodes, func(ctx context.Context, node *as.Node, i int) error {
+		for _, command := range commands {
+			info, err := node.RequestInfo(infoPolicyFor(ctx), command)
+			if err != nil {
+				return err
+			}
+			if err := infoError(info[command]); err != nil {
+				return fmt.Errorf("%s: %w", command, err)
+			}
+		}
+		return nil
+	})...)
+	c.observe(ctx, "set_config", start, err)
+	if err != nil {
+		return before, fmt.Errorf("setting config: %w", err)
+	}
+	return before, nil
+}
+
+// infoError returns the error an info command answered with, if any.
+func infoError(response string) error {
+	if strings.HasPrefix(strings.ToLower(response), "error") || strings.HasPrefix(strings.ToLower(response), "fail") {
+		return errors.New(response)
+	}
+	return nil
+}
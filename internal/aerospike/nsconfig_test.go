@@ -0,0 +1,76 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigChange(t *testing.T) {
+	ttl, negative, tooLong := int64(86400), int64(-1), int64(maxDefaultTTL+1)
+	enable, count := true, int64(500000)
+
+	tests := []struct {
+		name    string
+		set     string
+		change  ConfigChange
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "namespace default ttl",
+			change: ConfigChange{DefaultTTL: &ttl},
+			want:   []string{"set-config:context=namespace;id=test;default-ttl=86400"},
+		},
+		{
+			name:   "set settings",
+			set:    "users",
+			change: ConfigChange{EnableIndex: &enable, StopWritesCount: &count},
+			want: []string{
+				"set-config:context=namespace;id=test;set=users;enable-index=true",
+				"set-config:context=namespace;id=test;set=users;stop-writes-count=500000",
+			},
+		},
+		{name: "empty", wantErr: "no settings to change"},
+		{name: "default ttl on a set", set: "users", change: ConfigChange{DefaultTTL: &ttl}, wantErr: "namespace setting"},
+		{name: "negative ttl", change: ConfigChange{DefaultTTL: &negative}, wantErr: "between 0 and"},
+		{name: "ttl too long", change: ConfigChange{DefaultTTL: &tooLong}, wantErr: "between 0 and"},
+		{name: "set setting without a set", change: ConfigChange{EnableIndex: &enable}, wantErr: "require set_name"},
+		{name: "negative stop writes", set: "users", change: ConfigChange{StopWritesCount: &negative}, wantErr: "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.change.Validate(tt.set)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := tt.change.commands("test", tt.set); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInfoError(t *testing.T) {
+	for response, wantErr := range map[string]bool{
+		"ok":                      false,
+		"":                        false,
+		"ERROR::invalid-param":    true,
+		"error:4:unknown context": true,
+		"FAIL:4:bad value":        true,
+	} {
+		if err := infoError(response); (err != nil) != wantErr {
+			t.Errorf("Expected error %v for %q, got %v", wantErr, response, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type opTimesKey struct{}
+
+// OperationTimes accumulates the time spent in cluster operations on behalf
+// of one request.
+type OperationTimes struct {
+	mu    sync.Mutex
+	count int
+	total time.Duration
+}
+
+// WithOperationTimes returns a context whose cluster operations are added to
+// the returned OperationTimes.
+func WithOperationTimes(ctx context.Context) (context.Context, *OperationTimes) {
+	t := &OperationTimes{}
+	return context.WithValue(ctx, opTimesKey{}, t), t
+}
+
+// Totals returns the number of operations and their combined duration.
+func (t *OperationTimes) Totals() (int, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count, t.total
+}
+
+// addOperationTime adds an operation to the context's OperationTimes, if any.
+func addOperationTime(ctx context.Context, duration time.Duration) {
+	t, ok := ctx.Value(opTimesKey{}).(*OperationTimes)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	t.count++
+	t.total += duration
+	t.mu.Unlock()
+}
+
+type progressKey struct{}
+
+// Progress counts the records a scan or query has read so far, so a
+// background job can report how far it has got while it runs.
+type Progress struct {
+	records atomic.Int64
+}
+
+// WithProgress returns a context whose scans and queries count the records
+// they read in the returned Progress.
+func WithProgress(ctx context.Context) (context.Context, *Progress) {
+	p := &Progress{}
+	return context.WithValue(ctx, progressKey{}, p), p
+}
+
+// Records returns the number of records read so far.
+func (p *Progress) Records() int64 {
+	if p == nil {
+		return 0
+	}
+	return p.records.Load()
+}
+
+// progressFrom returns the context's Progress, or nil if it has none.
+func progressFrom(ctx context.Context) *Progress {
+	p, _ := ctx.Value(progressKey{}).(*Progress)
+	return p
+}
+
+// add counts n more records. A nil Progress ignores the call.
+func (p *Progress) add(n int64) {
+	if p != nil {
+		p.records.Add(n)
+	}
+}
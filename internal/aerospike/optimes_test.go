@@ -0,0 +1,24 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOperationTimes(t *testing.T) {
+	ctx, times := WithOperationTimes(context.Background())
+	addOperationTime(ctx, 3*time.Millisecond)
+	addOperationTime(ctx, 2*time.Millisecond)
+
+	// Operations outside the request aren't counted
+	addOperationTime(context.Background(), time.Second)
+
+	count, total := times.Totals()
+	if count != 2 || total != 5*time.Millisecond {
+		t.Errorf("Expected 2 operations taking 5ms, got %d taking %v", count, total)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// RecordOrder sorts the records one scan or query returns by a bin's value,
+// and skips the first Offset of them. Only the records the call reads, up
+// to max_records, are sorted, so an ordered page has no cursor: a next page
+// would be sorted on its own.
+type RecordOrder struct {
+	OrderBy   string `json:"order_by,omitempty"`
+	Direction string `json:"direction,omitempty"` // asc (default) or desc
+	Offset    int    `json:"offset,omitempty"`
+}
+
+// Validate returns an error if the direction is unknown or the offset
+// negative.
+func (o RecordOrder) Validate() error {
+	switch o.Direction {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("invalid direction: %s (must be asc or desc)", o.Direction)
+	}
+	if o.Direction != "" && o.OrderBy == "" {
+		return fmt.Errorf("direction requires order_by")
+	}
+	if o.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+	return nil
+}
+
+// recordOrderContextKey is the context key for a request's record order.
+type recordOrderContextKey struct{}
+
+// WithRecordOrder returns a context whose scans and queries return their
+// records in order o, which must be valid.
+func WithRecordOrder(ctx context.Context, o RecordOrder) context.Context {
+	if o == (RecordOrder{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, recordOrderContextKey{}, o)
+}
+
+// recordOrderFrom returns ctx's record order, or false if it has none.
+func recordOrderFrom(ctx context.Context) (RecordOrder, bool) {
+	o, ok := ctx.Value(recordOrderContextKey{}).(RecordOrder)
+	return o, ok
+}
+
+// apply sorts records and drops the first Offset of them. The sort is
+// stable, and records without the bin come last in either direction.
+func (o RecordOrder) apply(records []*Record) []*Record {
+	if o.OrderBy != "" {
+		desc := o.Direction == "desc"
+		sort.SliceStable(records, func(i, j int) bool {
+			a, aok := records[i].Bins[o.OrderBy]
+			b, bok := records[j].Bins[o.OrderBy]
+			if !aok || !bok {
+				return aok && !bok
+			}
+			if desc {
+				return valueLess(b, a)
+			}
+			return valueLess(a, b)
+		})
+	}
+	if o.Offset >= len(records) {
+		return records[:0]
+	}
+	return records[o.Offset:]
+}
+
+// valueLess orders bin values: numbers by value, whether integers or
+// floats, then strings, then other values as map keys are ordered.
+func valueLess(a, b interface{}) bool {
+	ra, rb := keyRank(a), keyRank(b)
+	if ra != rb && ra <= 1 && rb <= 1 {
+		return numberValue(a) < numberValue(b)
+	}
+	return keyLess(a, b)
+}
+
+// orderedPage builds the page for records read by an ordered scan or
+// query: as many as fit within max_response_bytes, without a cursor.
+func (c *Client) orderedPage(records []*Record) *RecordPage {
+	n := c.FitRecords(records)
+	return &RecordPage{Records: records[:n], Truncated: n < len(records)}
+}
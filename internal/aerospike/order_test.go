@@ -0,0 +1,63 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecordOrderApply(t *testing.T) {
+	records := func() []*Record {
+		return []*Record{
+			{Key: "a", Bins: map[string]interface{}{"score": 7}},
+			{Key: "b", Bins: map[string]interface{}{}},
+			{Key: "c", Bins: map[string]interface{}{"score": 2.5}},
+			{Key: "d", Bins: map[string]interface{}{"score": 12}},
+			{Key: "e", Bins: map[string]interface{}{"score": "n/a"}},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		order RecordOrder
+		want  []string
+	}{
+		{name: "ascending", order: RecordOrder{OrderBy: "score"}, want: []string{"c", "a", "d", "e", "b"}},
+		{name: "descending", order: RecordOrder{OrderBy: "score", Direction: "desc"}, want: []string{"e", "d", "a", "c", "b"}},
+		{name: "offset", order: RecordOrder{OrderBy: "score", Direction: "desc", Offset: 1}, want: []string{"d", "a", "c", "b"}},
+		{name: "offset without order", order: RecordOrder{Offset: 3}, want: []string{"d", "e"}},
+		{name: "offset past the end", order: RecordOrder{OrderBy: "score", Offset: 9}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, rec := range tt.order.apply(records()) {
+				got = append(got, rec.Key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecordOrderValidate(t *testing.T) {
+	tests := []struct {
+		order   RecordOrder
+		wantErr bool
+	}{
+		{RecordOrder{}, false},
+		{RecordOrder{OrderBy: "score", Direction: "desc", Offset: 10}, false},
+		{RecordOrder{OrderBy: "score", Direction: "down"}, true},
+		{RecordOrder{Direction: "asc"}, true},
+		{RecordOrder{OrderBy: "score", Offset: -1}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.order.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.order, err, tt.wantErr)
+		}
+	}
+}
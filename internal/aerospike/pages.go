@@ -0,0 +1,214 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// ErrInvalidCursor is returned for a cursor that wasn't returned by an
+// earlier page.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// RecordPage is a page of scan, query, or batch results.
+type RecordPage struct {
+	Records []*Record
+
+	// Cursor continues after the page's last record; it is empty when no
+	// records remain.
+	Cursor string
+
+	// Truncated reports whether records were left out of the page to keep
+	// it within max_response_bytes.
+	Truncated bool
+
+	// EstimatedTotal is the number of records expected from every page
+	// together, or 0 if unknown.
+	EstimatedTotal int
+}
+
+// pageCursor is the state a cursor carries to the next page.
+type pageCursor struct {
+	Partitions []byte `json:"p,omitempty"` // as.PartitionFilter.EncodeCursor output; scans and queries
+	Returned   int    `json:"n"`           // records returned by earlier pages; for batches, the next key
+}
+
+// encode returns the cursor as an opaque string.
+func (pc pageCursor) encode() string {
+	data, _ := json.Marshal(pc)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor returned by encode. An empty cursor starts
+// from the beginning.
+func decodeCursor(cursor string) (pageCursor, error) {
+	var pc pageCursor
+	if cursor == "" {
+		return pc, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &pc)
+	}
+	if err == nil && pc.Returned < 0 {
+		err = errors.New("negative record count")
+	}
+	if err != nil {
+		return pc, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return pc, nil
+}
+
+// partitionFilter returns the partitions a scan or query resumes from: all
+// of them, or those left by the cursor.
+func (pc pageCursor) partitionFilter() (*as.PartitionFilter, error) {
+	filter := as.NewPartitionFilterAll()
+	if len(pc.Partitions) == 0 {
+		return filter, nil
+	}
+	if err := filter.DecodeCursor(pc.Partitions); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return filter, nil
+}
+
+// partitionStates copies the partition states of filter, keyed by
+// partition ID, before a scan or query advances them.
+func partitionStates(filter *as.PartitionFilter) map[int]as.PartitionStatus {
+	states := make(map[int]as.PartitionStatus, len(filter.Partitions))
+	for _, ps := range filter.Partitions {
+		state := *ps
+		state.Digest = append([]byte(nil), ps.Digest...)
+		states[ps.Id] = state
+	}
+	return states
+}
+
+// FitRecords returns how many of records, from the first, fit within
+// max_response_bytes once serialized. It's at least one, so every page
+// makes progress.
+func (c *Client) FitRecords(records []*Record) int {
+	limit := c.config.MaxResponseBytes
+	if limit <= 0 {
+		return len(records)
+	}
+	size := 2 // the enclosing brackets
+	for i, rec := range records {
+		n := rec.size
+		if n == 0 {
+			data, err := json.Marshal(rec)
+			if err != nil {
+				continue
+			}
+			n = len(data)
+		}
+		if size += n + 1; size > limit && i > 0 {
+			return i
+		}
+	}
+	return len(records)
+}
+
+// scanPage builds the page for the records a paginated scan or query read
+// from filter, whose partitions were in states before it ran. Truncation
+// ends at a partition boundary: the partitions of records left out are
+// returned to their earlier states, so the next page reads them again
+// without skipping or repeating records.
+func (c *Client) scanPage(records []*Record, filter *as.PartitionFilter, before map[int]as.PartitionStatus, returned int) *RecordPage {
+	page := &RecordPage{Records: records}
+	more := !filter.IsDone()
+
+	if n := c.FitRecords(records); n < len(records) {
+		dropped := make(map[int]bool)
+		for _, rec := range records[n:] {
+			dropped[rec.partition] = true
+		}
+		kept := keepPartitions(records, dropped)
+		if len(kept) == 0 {
+			// The first partition's records are kept whatever their size
+			delete(dropped, records[0].partition)
+			kept = keepPartitions(records, dropped)
+		}
+
+		for _, ps := range filter.Partitions {
+			if !dropped[ps.Id] {
+				continue
+			}
+			state, ok := before[ps.Id]
+			if !ok {
+				state = as.PartitionStatus{Id: ps.Id}
+			}
+			state.Retry = true
+			*ps = state
+		}
+		page.Records, page.Truncated, more = kept, true, true
+	}
+
+	returned += len(page.Records)
+	done := 0
+	for _, ps := range filter.Partitions {
+		if !ps.Retry {
+			done++
+		}
+	}
+	switch {
+	case !more:
+		page.EstimatedTotal = returned
+	case done > 0:
+		page.EstimatedTotal = returned * len(filter.Partitions) / done
+	}
+
+	if more {
+		partitions, err := filter.EncodeCursor()
+		if err == nil {
+			page.Cursor = pageCursor{Partitions: partitions, Returned: returned}.encode()
+		}
+	}
+	return page
+}
+
+// keepPartitions returns the records outside the dropped partitions.
+func keepPartitions(records []*Record, dropped map[int]bool) []*Record {
+	kept := make([]*Record, 0, len(records))
+	for _, rec := range records {
+		if !dropped[rec.partition] {
+			kept = append(kept, rec)
+		}
+	}
+	return kept
+}
+
+// BatchGetPage retrieves the records for requests from cursor on, as many
+// as fit within max_response_bytes. Its cursor continues with the next
+// request. If the records would exceed max_result_bytes, it returns those
+// read so far with ErrResultTooLarge, and still a cursor.
+func (c *Client) BatchGetPage(ctx context.Context, requests []BatchGetRequest, cursor string) (*RecordPage, error) {
+	pc, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Returned > len(requests) {
+		return nil, fmt.Errorf("%w: starts after the last key", ErrInvalidCursor)
+	}
+
+	records, err := c.BatchGet(ctx, requests[pc.Returned:])
+	if err != nil && !errors.Is(err, ErrResultTooLarge) {
+		return nil, err
+	}
+
+	page := &RecordPage{Records: records, EstimatedTotal: len(requests)}
+	if n := c.FitRecords(records); n < len(records) {
+		page.Records, page.Truncated = records[:n], true
+	}
+	if next := pc.Returned + len(page.Records); next < len(requests) {
+		page.Cursor = pageCursor{Returned: next}.encode()
+	}
+	return page, err
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// pageRecords returns one record per partition, each serializing to about
+// size bytes.
+func pageRecords(size int, partitions ...int) []*Record {
+	records := make([]*Record, len(partitions))
+	for i, p := range partitions {
+		records[i] = &Record{Key: "k", Namespace: "test", Bins: map[string]interface{}{"v": strings.Repeat("x", size)}, partition: p}
+	}
+	return records
+}
+
+func TestFitRecords(t *testing.T) {
+	one, _ := json.Marshal(pageRecords(100, 0)[0])
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected int
+	}{
+		{"unlimited", 0, 4},
+		{"all fit", 10 * len(one), 4},
+		{"two fit", 2*len(one) + 4, 2},
+		{"first always kept", 10, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.MaxResponseBytes = tt.limit
+			c := newClient(cfg)
+			if got := c.FitRecords(pageRecords(100, 0, 1, 2, 3)); got != tt.expected {
+				t.Errorf("Expected %d records to fit, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+// pageFilter returns a filter over partitions 0-3 as a scan that read
+// every partition to its end leaves it, and their states before the scan.
+func pageFilter() (*as.PartitionFilter, map[int]as.PartitionStatus) {
+	filter := as.NewPartitionFilterByRange(0, 4)
+	for id := 0; id < 4; id++ {
+		filter.Partitions = append(filter.Partitions, &as.PartitionStatus{Id: id, Retry: true, Digest: []byte{byte(id)}})
+	}
+	before := partitionStates(filter)
+	for _, ps := range filter.Partitions {
+		ps.Retry, ps.Digest = false, []byte{0xff}
+	}
+	filter.Done = true
+	return filter, before
+}
+
+func TestScanPage(t *testing.T) {
+	one, _ := json.Marshal(pageRecords(100, 0)[0])
+
+	tests := []struct {
+		name       string
+		limit      int
+		partitions []int
+		kept       int
+		reverted   []int
+	}{
+		{"complete", 0, []int{0, 1, 2, 3}, 4, nil},
+		{"truncated at partition boundary", 2*len(one) + 4, []int{0, 1, 2, 3}, 2, []int{2, 3}},
+		{"partition split across limit", 2*len(one) + 4, []int{0, 1, 1, 2}, 1, []int{1, 2}},
+		{"first partition kept", 10, []int{0, 0, 1}, 2, []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.MaxResponseBytes = tt.limit
+			c := newClient(cfg)
+			filter, before := pageFilter()
+
+			page := c.scanPage(pageRecords(100, tt.partitions...), filter, before, 5)
+			if len(page.Records) != tt.kept {
+				t.Errorf("Expected %d records, got %d", tt.kept, len(page.Records))
+			}
+			if page.Truncated != (tt.reverted != nil) {
+				t.Errorf("Expected truncated %v, got %v", tt.reverted != nil, page.Truncated)
+			}
+			if tt.reverted == nil {
+				if page.Cursor != "" || page.EstimatedTotal != 5+tt.kept {
+					t.Errorf("Expected a final page of %d records, got cursor %q and total %d", 5+tt.kept, page.Cursor, page.EstimatedTotal)
+				}
+				return
+			}
+
+			// The cursor resumes the reverted partitions from their earlier
+			// states and skips the rest
+			pc, err := decodeCursor(page.Cursor)
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if pc.Returned != 5+tt.kept {
+				t.Errorf("Expected %d records returned, got %d", 5+tt.kept, pc.Returned)
+			}
+			resumed, err := pc.partitionFilter()
+			if err != nil {
+				t.Fatalf("partitionFilter() error = %v", err)
+			}
+			reverted := make(map[int]bool)
+			for _, id := range tt.reverted {
+				reverted[id] = true
+			}
+			for _, ps := range resumed.Partitions {
+				wantDigest := []byte{0xff}
+				if reverted[ps.Id] {
+					wantDigest = []byte{byte(ps.Id)}
+				}
+				if ps.Retry != reverted[ps.Id] || string(ps.Digest) != string(wantDigest) {
+					t.Errorf("Expected partition %d retry %v from %v, got %v from %v", ps.Id, reverted[ps.Id], wantDigest, ps.Retry, ps.Digest)
+				}
+			}
+			if want := (5 + tt.kept) * 4 / (4 - len(tt.reverted)); page.EstimatedTotal != want {
+				t.Errorf("Expected estimated total %d, got %d", want, page.EstimatedTotal)
+			}
+		})
+	}
+}
+
+func TestInvalidCursor(t *testing.T) {
+	c := newClient(config.DefaultConfig())
+	ctx := context.Background()
+	requests := []BatchGetRequest{{Namespace: "test", Key: "1"}}
+
+	for _, cursor := range []string{"not a cursor!", pageCursor{Returned: -1}.encode(), pageCursor{Partitions: []byte("junk")}.encode()} {
+		pc, err := decodeCursor(cursor)
+		if err == nil {
+			_, err = pc.partitionFilter()
+		}
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", cursor, err)
+		}
+	}
+
+	if _, err := c.BatchGetPage(ctx, requests, pageCursor{Returned: 2}.encode()); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor for a cursor past the last key, got %v", err)
+	}
+	if code := Code(ErrInvalidCursor); code != ErrorInvalid {
+		t.Errorf("Expected code %s, got %s", ErrorInvalid, code)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// ReadOptions override the configured read consistency and replica
+// selection for one request's reads, batch reads, and queries. Empty fields
+// keep the configured values.
+type ReadOptions struct {
+	ReadModeAP string `json:"read_mode_ap,omitempty"`
+	ReadModeSC string `json:"read_mode_sc,omitempty"`
+	Replica    string `json:"replica,omitempty"`
+}
+
+// Validate returns an error if an option names an unknown mode or policy.
+func (o ReadOptions) Validate() error {
+	if _, ok := readModeAP(o.ReadModeAP); o.ReadModeAP != "" && !ok {
+		return fmt.Errorf("invalid read_mode_ap: %s (must be one or all)", o.ReadModeAP)
+	}
+	if _, ok := readModeSC(o.ReadModeSC); o.ReadModeSC != "" && !ok {
+		return fmt.Errorf("invalid read_mode_sc: %s (must be session, linearize, allow_replica, or allow_unavailable)", o.ReadModeSC)
+	}
+	if _, ok := replicaPolicy(o.Replica); o.Replica != "" && !ok {
+		return fmt.Errorf("invalid replica: %s (must be master, master_proles, random, sequence, or prefer_rack)", o.Replica)
+	}
+	return nil
+}
+
+// readOptionsContextKey is the context key for a request's read options.
+type readOptionsContextKey struct{}
+
+// WithReadOptions returns a context whose reads use opts, which must be
+// valid.
+func WithReadOptions(ctx context.Context, opts ReadOptions) context.Context {
+	if opts == (ReadOptions{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, readOptionsContextKey{}, opts)
+}
+
+// applyReadOptions sets the read modes and replica policy in ctx's read
+// options on policy.
+func applyReadOptions(ctx context.Context, policy *as.BasePolicy) {
+	opts, ok := ctx.Value(readOptionsContextKey{}).(ReadOptions)
+	if !ok {
+		return
+	}
+	if mode, ok := readModeAP(opts.ReadModeAP); ok {
+		policy.ReadModeAP = mode
+	}
+	if mode, ok := readModeSC(opts.ReadModeSC); ok {
+		policy.ReadModeSC = mode
+	}
+	if replica, ok := replicaPolicy(opts.Replica); ok {
+		policy.ReplicaPolicy = replica
+	}
+}
+
+// readModeAP returns the client library's AP read mode for a name.
+func readModeAP(name string) (as.ReadModeAP, bool) {
+	switch name {
+	case config.ReadModeAPOne:
+		return as.ReadModeAPOne, true
+	case config.ReadModeAPAll:
+		return as.ReadModeAPAll, true
+	}
+	return as.ReadModeAPOne, false
+}
+
+// readModeSC returns the client library's strong consistency read mode for
+// a name.
+func readModeSC(name string) (as.ReadModeSC, bool) {
+	switch name {
+	case config.ReadModeSCSession:
+		return as.ReadModeSCSession, true
+	case config.ReadModeSCLinearize:
+		return as.ReadModeSCLinearize, true
+	case config.ReadModeSCAllowReplica:
+		return as.ReadModeSCAllowReplica, true
+	case config.ReadModeSCAllowUnavailable:
+		return as.ReadModeSCAllowUnavailable, true
+	}
+	return as.ReadModeSCSession, false
+}
+
+// replicaPolicy returns the client library's replica policy for a name.
+func replicaPolicy(name string) (as.ReplicaPolicy, bool) {
+	switch name {
+	case config.ReplicaMaster:
+		return as.MASTER, true
+	case config.ReplicaMasterProles:
+		return as.MASTER_PROLES, true
+	case config.ReplicaRandom:
+		return as.RANDOM, true
+	case config.ReplicaSequence:
+		return as.SEQUENCE, true
+	case config.ReplicaPreferRack:
+		return as.PREFER_RACK, true
+	}
+	return as.SEQUENCE, false
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestReadOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ReadOptions
+		wantErr bool
+	}{
+		{"empty", ReadOptions{}, false},
+		{"all valid", ReadOptions{ReadModeAP: "all", ReadModeSC: "linearize", Replica: "master"}, false},
+		{"invalid ap mode", ReadOptions{ReadModeAP: "quorum"}, true},
+		{"invalid sc mode", ReadOptions{ReadModeSC: "eventual"}, true},
+		{"invalid replica", ReadOptions{Replica: "nearest"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadOptionsPolicies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ReadModeSC = config.ReadModeSCAllowReplica
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	c := newClient(cfg)
+
+	policy := c.readPolicyFor(context.Background(), "test")
+	if policy.ReadModeAP != as.ReadModeAPOne || policy.ReadModeSC != as.ReadModeSCAllowReplica || policy.ReplicaPolicy != as.SEQUENCE {
+		t.Errorf("Expected configured read policy, got %v/%v/%v", policy.ReadModeAP, policy.ReadModeSC, policy.ReplicaPolicy)
+	}
+
+	ctx := WithReadOptions(context.Background(), ReadOptions{ReadModeSC: "linearize", Replica: "master"})
+	policy = c.readPolicyFor(ctx, "test")
+	if policy.ReadModeAP != as.ReadModeAPOne || policy.ReadModeSC != as.ReadModeSCLinearize || policy.ReplicaPolicy != as.MASTER {
+		t.Errorf("Expected overridden read policy, got %v/%v/%v", policy.ReadModeAP, policy.ReadModeSC, policy.ReplicaPolicy)
+	}
+	if batch := c.batchPolicyFor(ctx, "test"); batch.ReadModeSC != as.ReadModeSCLinearize || batch.ReplicaPolicy != as.MASTER {
+		t.Errorf("Expected overridden batch policy, got %v/%v", batch.ReadModeSC, batch.ReplicaPolicy)
+	}
+	if query := c.queryPolicyFor(ctx, "test"); query.ReadModeSC != as.ReadModeSCLinearize || query.ReplicaPolicy != as.MASTER {
+		t.Errorf("Expected overridden query policy, got %v/%v", query.ReadModeSC, query.ReplicaPolicy)
+	}
+
+	// The configured policies are unchanged
+	if c.readPolicy.ReadModeSC != as.ReadModeSCAllowReplica {
+		t.Errorf("Expected configured read mode to be unchanged, got %v", c.readPolicy.ReadModeSC)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// RecordProfile describes the shape of sampled records, without their
+// keys or values: their size as JSON, bin count, generation, and TTL, how
+// deeply each bin's lists and maps nest, and the length of the user keys
+// stored with them. TTLs are the seconds remaining of the records that
+// expire; NeverExpire counts the others.
+type RecordProfile struct {
+	Sampled     int            `json:"records_sampled"`
+	Bytes       *Distribution  `json:"record_bytes,omitempty"`
+	Bins        *Distribution  `json:"bins_per_record,omitempty"`
+	Generation  *Distribution  `json:"generation,omitempty"`
+	TTL         *Distribution  `json:"ttl_seconds,omitempty"`
+	NeverExpire int            `json:"never_expire"`
+	CDTDepth    map[string]int `json:"max_cdt_depth,omitempty"`
+	StoredKeys  int            `json:"records_with_stored_key"`
+	KeyLength   *Distribution  `json:"key_length,omitempty"`
+}
+
+// Distribution summarizes values by their minimum, percentiles, and
+// maximum.
+type Distribution struct {
+	Min int64 `json:"min"`
+	P50 int64 `json:"p50"`
+	P90 int64 `json:"p90"`
+	P99 int64 `json:"p99"`
+	Max int64 `json:"max"`
+}
+
+// ProfileRecords profiles sampled records.
+func ProfileRecords(records []*Record) *RecordProfile {
+	p := &RecordProfile{CDTDepth: make(map[string]int)}
+	var sizes, bins, generations, ttls, keyLengths []int64
+
+	for _, rec := range records {
+		if rec == nil {
+			continue
+		}
+		p.Sampled++
+		if data, err := json.Marshal(rec); err == nil {
+			sizes = append(sizes, int64(len(data)))
+		}
+		bins = append(bins, int64(len(rec.Bins)))
+		generations = append(generations, int64(rec.Generation))
+		if rec.TTLRemaining < 0 {
+			p.NeverExpire++
+		} else {
+			ttls = append(ttls, rec.TTLRemaining)
+		}
+		if rec.Key != "" {
+			p.StoredKeys++
+			keyLengths = append(keyLengths, int64(len(rec.Key)))
+		}
+		for name, value := range rec.Bins {
+			if depth := cdtDepth(value); depth > 0 {
+				p.CDTDepth[name] = max(p.CDTDepth[name], depth)
+			}
+		}
+	}
+
+	p.Bytes, p.Bins, p.Generation = distribution(sizes), distribution(bins), distribution(generations)
+	p.TTL, p.KeyLength = distribution(ttls), distribution(keyLengths)
+	return p
+}
+
+// cdtDepth returns how deeply lists and maps nest in v: 0 for a scalar, 1
+// for a list or map of scalars, and so on.
+func cdtDepth(v interface{}) int {
+	kind, items := collectionItems(v)
+	if kind == "" {
+		return 0
+	}
+	depth := 0
+	for _, item := range items {
+		depth = max(depth, cdtDepth(item))
+	}
+	return depth + 1
+}
+
+// distribution summarizes values, or returns nil if there are none.
+// Percentiles are nearest-rank.
+func distribution(values []int64) *Distribution {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := func(p int) int64 {
+		return sorted[(p*len(sorted)+99)/100-1]
+	}
+	return &Distribution{Min: sorted[0], P50: rank(50), P90: rank(90), P99: rank(99), Max: sorted[len(sorted)-1]}
+}
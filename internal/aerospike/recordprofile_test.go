@@ -0,0 +1,59 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfileRecords(t *testing.T) {
+	records := []*Record{
+		{Key: "user1", Generation: 1, TTLRemaining: -1, Bins: map[string]interface{}{"name": "a", "tags": []interface{}{"x"}}},
+		{Key: "user10", Generation: 5, TTLRemaining: 3600, Bins: map[string]interface{}{"name": "b", "tags": []interface{}{[]interface{}{"y"}}}},
+		{Generation: 2, TTLRemaining: 60, Bins: map[string]interface{}{"attrs": map[string]interface{}{mapHint: []interface{}{[]interface{}{int64(1), map[string]interface{}{"z": int64(2)}}}}}},
+		nil,
+	}
+
+	p := ProfileRecords(records)
+	if p.Sampled != 3 || p.NeverExpire != 1 || p.StoredKeys != 2 {
+		t.Errorf("Expected 3 sampled, 1 never expiring, 2 stored keys, got %+v", p)
+	}
+	if want := (Distribution{Min: 1, P50: 2, P90: 2, P99: 2, Max: 2}); *p.Bins != want {
+		t.Errorf("Expected bins %+v, got %+v", want, *p.Bins)
+	}
+	if want := (Distribution{Min: 1, P50: 2, P90: 5, P99: 5, Max: 5}); *p.Generation != want {
+		t.Errorf("Expected generations %+v, got %+v", want, *p.Generation)
+	}
+	if want := (Distribution{Min: 60, P50: 60, P90: 3600, P99: 3600, Max: 3600}); *p.TTL != want {
+		t.Errorf("Expected TTLs %+v, got %+v", want, *p.TTL)
+	}
+	if want := (Distribution{Min: 5, P50: 5, P90: 6, P99: 6, Max: 6}); *p.KeyLength != want {
+		t.Errorf("Expected key lengths %+v, got %+v", want, *p.KeyLength)
+	}
+	if want := map[string]int{"tags": 2, "attrs": 2}; !reflect.DeepEqual(p.CDTDepth, want) {
+		t.Errorf("Expected CDT depths %v, got %v", want, p.CDTDepth)
+	}
+	if p.Bytes == nil || p.Bytes.Min <= 0 || p.Bytes.Min > p.Bytes.Max {
+		t.Errorf("Expected a record size distribution, got %+v", p.Bytes)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	values := make([]int64, 100)
+	for i := range values {
+		values[i] = int64(100 - i)
+	}
+
+	want := Distribution{Min: 1, P50: 50, P90: 90, P99: 99, Max: 100}
+	if got := distribution(values); *got != want {
+		t.Errorf("Expected %+v, got %+v", want, *got)
+	}
+	if values[0] != 100 {
+		t.Error("Expected the values to be left unsorted")
+	}
+	if got := distribution(nil); got != nil {
+		t.Errorf("Expected nil for no values, got %+v", got)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrResultTooLarge is returned, along with the records read so far, by
+// scans, queries, and batches whose records would exceed max_result_bytes.
+var ErrResultTooLarge = errors.New("result exceeded max_result_bytes")
+
+// resultBudget tracks the serialized size of the records a scan, query, or
+// batch has accumulated, so one that would hold more than max_result_bytes
+// in memory stops early.
+type resultBudget struct {
+	limit int // zero is unlimited
+	used  int
+}
+
+// newResultBudget returns a budget of max_result_bytes.
+func (c *Client) newResultBudget() *resultBudget {
+	return &resultBudget{limit: c.config.MaxResultBytes}
+}
+
+// add counts rec against the budget, returning false if it doesn't fit.
+// The size is kept on rec, so FitRecords doesn't serialize it again.
+func (b *resultBudget) add(rec *Record) bool {
+	if b.limit <= 0 {
+		return true
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return true
+	}
+	rec.size = len(data)
+	b.used += rec.size + 1
+	return b.used <= b.limit
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestResultBudget(t *testing.T) {
+	one, _ := json.Marshal(pageRecords(100, 0)[0])
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected int
+	}{
+		{"unlimited", 0, 4},
+		{"all fit", 4 * (len(one) + 1), 4},
+		{"two fit", 3*(len(one)+1) - 1, 2},
+		{"none fit", 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.MaxResultBytes = tt.limit
+			budget := newClient(cfg).newResultBudget()
+
+			added := 0
+			for _, rec := range pageRecords(100, 0, 1, 2, 3) {
+				if !budget.add(rec) {
+					break
+				}
+				added++
+			}
+			if added != tt.expected {
+				t.Errorf("Expected %d records within the budget, got %d", tt.expected, added)
+			}
+		})
+	}
+}
+
+func TestFitRecordsCountedSize(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MaxResponseBytes = 100
+	c := newClient(cfg)
+
+	// Sizes counted against the budget aren't serialized again
+	records := pageRecords(10, 0, 1, 2)
+	for _, rec := range records {
+		rec.size = 40
+	}
+	if got := c.FitRecords(records); got != 2 {
+		t.Errorf("Expected 2 records to fit by their counted size, got %d", got)
+	}
+}
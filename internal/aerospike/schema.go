@@ -0,0 +1,150 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// DefaultSchemaSample is the number of records DescribeSet samples to infer
+// a set's schema.
+const DefaultSchemaSample = 100
+
+// BinSchema represents inferred schema for a bin. A bin is nullable if
+// some sampled records don't have it.
+type BinSchema struct {
+	Name     string      `json:"name"`
+	Types    []string    `json:"types"`
+	Nullable bool        `json:"nullable"`
+	Sample   interface{} `json:"sample,omitempty"`
+}
+
+// SetSchema represents inferred schema for a set. AvgRecordSize is the
+// average size in bytes of the sampled records as JSON, an estimate of how
+// large a record is in a result.
+type SetSchema struct {
+	Namespace     string      `json:"namespace"`
+	Set           string      `json:"set"`
+	Bins          []BinSchema `json:"bins"`
+	SampleSize    int         `json:"sample_size"`
+	AvgRecordSize int         `json:"avg_record_size"`
+}
+
+// SetDescription is a set's metadata with the schema inferred from a sample
+// of its records.
+type SetDescription struct {
+	SetInfo
+	Schema *SetSchema `json:"schema"`
+}
+
+// DescribeSet returns a set's metadata and the schema of up to sampleSize
+// of its records, or DefaultSchemaSample if sampleSize is not positive.
+func (c *Client) DescribeSet(ctx context.Context, namespace, setName string, sampleSize int) (*SetDescription, error) {
+	sets, err := c.ListSets(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, set := range sets {
+		if set.Name != setName {
+			continue
+		}
+		if sampleSize <= 0 {
+			sampleSize = DefaultSchemaSample
+		}
+		// A sample cut short by the deadline or max_result_bytes still
+		// describes the records it holds.
+		page, err := c.ScanSet(ctx, namespace, setName, nil, sampleSize, 0, "")
+		if err != nil && !errors.Is(err, ErrIncomplete) && !errors.Is(err, ErrResultTooLarge) {
+			return nil, err
+		}
+		schema := InferSchema(page.Records)
+		schema.Namespace, schema.Set = namespace, setName
+		return &SetDescription{SetInfo: set, Schema: schema}, nil
+	}
+
+	return nil, fmt.Errorf("set not found: %s.%s", namespace, setName)
+}
+
+// InferSchema builds a schema from sampled records. Bins are sorted by name
+// and their types alphabetically.
+func InferSchema(records []*Record) *SetSchema {
+	binTypes := make(map[string]map[string]bool)
+	binSamples := make(map[string]interface{})
+	binCounts := make(map[string]int)
+	binNullable := make(map[string]bool)
+
+	sampled, totalSize := 0, 0
+	for _, rec := range records {
+		if rec == nil {
+			continue
+		}
+		sampled++
+		if data, err := json.Marshal(rec); err == nil {
+			totalSize += len(data)
+		}
+		for binName, value := range rec.Bins {
+			if _, ok := binTypes[binName]; !ok {
+				binTypes[binName] = make(map[string]bool)
+				binSamples[binName] = value
+			}
+			binCounts[binName]++
+			if value == nil {
+				binNullable[binName] = true
+			} else {
+				binTypes[binName][getTypeName(value)] = true
+			}
+		}
+	}
+
+	bins := make([]BinSchema, 0, len(binTypes))
+	for name, types := range binTypes {
+		bins = append(bins, BinSchema{
+			Name:     name,
+			Types:    sortedKeys(types),
+			Nullable: binNullable[name] || binCounts[name] < sampled,
+			Sample:   binSamples[name],
+		})
+	}
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Name < bins[j].Name })
+
+	schema := &SetSchema{Bins: bins, SampleSize: sampled}
+	if sampled > 0 {
+		schema.AvgRecordSize = totalSize / sampled
+	}
+	for _, rec := range records {
+		if rec != nil {
+			schema.Namespace = rec.Namespace
+			schema.Set = rec.Set
+			break
+		}
+	}
+	return schema
+}
+
+// getTypeName returns the type name for a value.
+func getTypeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case int, int32, int64:
+		return "integer"
+	case float32, float64:
+		return "float"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "map"
+	case []byte:
+		return "bytes"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	records := []*Record{
+		{Namespace: "test", Set: "users", Bins: map[string]interface{}{"name": "ann", "age": int64(30), "tags": []interface{}{"a"}}},
+		nil,
+		{Namespace: "test", Set: "users", Bins: map[string]interface{}{"name": "bob", "age": 31.5}},
+	}
+
+	schema := InferSchema(records)
+	want := []BinSchema{
+		{Name: "age", Types: []string{"float", "integer"}, Sample: int64(30)},
+		{Name: "name", Types: []string{"string"}, Sample: "ann"},
+		{Name: "tags", Types: []string{"list"}, Nullable: true, Sample: []interface{}{"a"}},
+	}
+	if !reflect.DeepEqual(schema.Bins, want) {
+		t.Errorf("Expected bins %+v, got %+v", want, schema.Bins)
+	}
+	if schema.SampleSize != 2 {
+		t.Errorf("Expected sample size 2, got %d", schema.SampleSize)
+	}
+	if schema.Namespace != "test" || schema.Set != "users" {
+		t.Errorf("Expected test.users, got %s.%s", schema.Namespace, schema.Set)
+	}
+	if schema.AvgRecordSize <= 0 {
+		t.Errorf("Expected a positive average record size, got %d", schema.AvgRecordSize)
+	}
+
+	empty := InferSchema(nil)
+	if empty.Bins == nil || len(empty.Bins) != 0 || empty.SampleSize != 0 || empty.AvgRecordSize != 0 {
+		t.Errorf("Expected an empty schema, got %+v", empty)
+	}
+}
@@ -0,0 +1,470 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The SQL dialect sql_query accepts is a single SELECT:
+//
+//	SELECT * | bin, ... FROM namespace[.set]
+//	  [WHERE condition] [ORDER BY bin [ASC | DESC]] [LIMIT n]
+//
+// A condition compares a bin with a literal (=, !=, <>, <, <=, >, >=),
+// tests a range (bin BETWEEN a AND b) or a list (bin IN (a, b)), and
+// combines them with AND, OR, NOT, and parentheses. PK stands for the
+// primary key, which may only be compared with = or IN on its own.
+// Literals are numbers, 'strings', TRUE, and FALSE. Identifiers may be
+// quoted with double quotes or backquotes.
+
+// sqlStatement is a parsed SELECT.
+type sqlStatement struct {
+	Bins      []string // nil for *
+	Namespace string
+	Set       string
+	Where     sqlCondition // nil for none
+	OrderBy   string
+	Desc      bool
+	Limit     int
+}
+
+// sqlCondition is a WHERE condition: *sqlCompare, *sqlIn, *sqlLogic, or
+// *sqlNot.
+type sqlCondition interface{}
+
+// sqlCompare compares a bin with a literal. BETWEEN is parsed as a pair of
+// comparisons.
+type sqlCompare struct {
+	Bin   string
+	Op    string // =, !=, <, <=, >, >=
+	Value interface{}
+}
+
+// sqlIn tests whether a bin equals one of a list of literals.
+type sqlIn struct {
+	Bin    string
+	Values []interface{}
+}
+
+// sqlLogic is an AND or OR of two or more conditions.
+type sqlLogic struct {
+	Op   string // and, or
+	Args []sqlCondition
+}
+
+// sqlNot negates a condition.
+type sqlNot struct {
+	Arg sqlCondition
+}
+
+// sqlPK is the pseudo-bin naming the primary key.
+const sqlPK = "PK"
+
+// sqlToken is a lexical token. Quoted identifiers and strings keep their
+// kind so they are never taken for keywords.
+type sqlToken struct {
+	kind string // ident, quoted, string, number, symbol, end
+	text string
+	pos  int
+}
+
+// tokenizeSQL splits a statement into tokens.
+func tokenizeSQL(sql string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(sql)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '\'' || r == '"' || r == '`':
+			var text strings.Builder
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated quote at position %d", start)
+				}
+				if runes[i] == r {
+					// A doubled quote stands for the quote itself
+					if i+1 < len(runes) && runes[i+1] == r {
+						text.WriteRune(r)
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				text.WriteRune(runes[i])
+			}
+			kind := "quoted"
+			if r == '\'' {
+				kind = "string"
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: text.String(), pos: start})
+			continue
+		case unicode.IsDigit(r) || (r == '-' || r == '.') && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				(runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E')); i++ {
+			}
+			tokens = append(tokens, sqlToken{kind: "number", text: string(runes[start:i]), pos: start})
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i++; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '-'); i++ {
+			}
+			tokens = append(tokens, sqlToken{kind: "ident", text: string(runes[start:i]), pos: start})
+			continue
+		}
+
+		// Two-character operators first
+		if i+1 < len(runes) {
+			switch two := string(runes[i : i+2]); two {
+			case "!=", "<>", "<=", ">=":
+				tokens = append(tokens, sqlToken{kind: "symbol", text: two, pos: start})
+				i += 2
+				continue
+			}
+		}
+		if !strings.ContainsRune(",()*=<>.;", r) {
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, start)
+		}
+		tokens = append(tokens, sqlToken{kind: "symbol", text: string(r), pos: start})
+		i++
+	}
+	return append(tokens, sqlToken{kind: "end", pos: len(runes)}), nil
+}
+
+// sqlParser parses a token stream by recursive descent.
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+}
+
+// parseSQL parses a SELECT statement.
+func parseSQL(sql string) (*sqlStatement, error) {
+	tokens, err := tokenizeSQL(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{tokens: tokens}
+	stmt, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// peek returns the next token.
+func (p *sqlParser) peek() sqlToken {
+	return p.tokens[p.pos]
+}
+
+// next consumes the next token.
+func (p *sqlParser) next() sqlToken {
+	t := p.tokens[p.pos]
+	if t.kind != "end" {
+		p.pos++
+	}
+	return t
+}
+
+// keyword consumes the next token if it is the keyword kw.
+func (p *sqlParser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == "ident" && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// symbol consumes the next token if it is the symbol s.
+func (p *sqlParser) symbol(s string) bool {
+	if t := p.peek(); t.kind == "symbol" && t.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// errorf returns a syntax error at the next token.
+func (p *sqlParser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	found := "end of statement"
+	if t.kind != "end" {
+		found = strconv.Quote(t.text)
+	}
+	return fmt.Errorf("syntax error at position %d: %s, found %s", t.pos, fmt.Sprintf(format, args...), found)
+}
+
+// sqlKeywords are the words that can't be used as unquoted identifiers.
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "AND": true, "OR": true, "NOT": true,
+	"BETWEEN": true, "IN": true, "ORDER": true, "BY": true, "ASC": true, "DESC": true,
+	"LIMIT": true, "TRUE": true, "FALSE": true,
+}
+
+// identifier consumes a bin, namespace, or set name.
+func (p *sqlParser) identifier(what string) (string, error) {
+	t := p.peek()
+	if t.kind == "quoted" || t.kind == "ident" && !sqlKeywords[strings.ToUpper(t.text)] {
+		p.pos++
+		return t.text, nil
+	}
+	return "", p.errorf("expected %s", what)
+}
+
+// statement parses SELECT ... FROM ... and its optional clauses.
+func (p *sqlParser) statement() (*sqlStatement, error) {
+	if !p.keyword("SELECT") {
+		return nil, p.errorf("expected SELECT")
+	}
+	stmt := &sqlStatement{}
+	if !p.symbol("*") {
+		for {
+			bin, err := p.identifier("a bin name or *")
+			if err != nil {
+				return nil, err
+			}
+			stmt.Bins = append(stmt.Bins, bin)
+			if !p.symbol(",") {
+				break
+			}
+		}
+	}
+
+	if !p.keyword("FROM") {
+		return nil, p.errorf("expected FROM")
+	}
+	var err error
+	if stmt.Namespace, err = p.identifier("a namespace"); err != nil {
+		return nil, err
+	}
+	if p.symbol(".") {
+		if stmt.Set, err = p.identifier("a set name"); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.keyword("WHERE") {
+		if stmt.Where, err = p.or(); err != nil {
+			return nil, err
+		}
+	}
+	if p.keyword("ORDER") {
+		if !p.keyword("BY") {
+			return nil, p.errorf("expected BY")
+		}
+		if stmt.OrderBy, err = p.identifier("a bin name"); err != nil {
+			return nil, err
+		}
+		if p.keyword("DESC") {
+			stmt.Desc = true
+		} else {
+			p.keyword("ASC")
+		}
+	}
+	if p.keyword("LIMIT") {
+		t := p.next()
+		n, err := strconv.Atoi(t.text)
+		if t.kind != "number" || err != nil || n < 1 {
+			p.pos--
+			return nil, p.errorf("expected a positive LIMIT")
+		}
+		stmt.Limit = n
+	}
+	p.symbol(";")
+	if p.peek().kind != "end" {
+		return nil, p.errorf("expected end of statement")
+	}
+	return stmt, nil
+}
+
+// or parses conditions joined by OR.
+func (p *sqlParser) or() (sqlCondition, error) {
+	return p.logic("OR", p.and)
+}
+
+// and parses conditions joined by AND.
+func (p *sqlParser) and() (sqlCondition, error) {
+	return p.logic("AND", p.not)
+}
+
+// logic parses operands joined by the keyword op.
+func (p *sqlParser) logic(op string, operand func() (sqlCondition, error)) (sqlCondition, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	args := []sqlCondition{first}
+	for p.keyword(op) {
+		arg, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if len(args) == 1 {
+		return first, nil
+	}
+	return &sqlLogic{Op: strings.ToLower(op), Args: args}, nil
+}
+
+// not parses an optionally negated condition.
+func (p *sqlParser) not() (sqlCondition, error) {
+	if p.keyword("NOT") {
+		arg, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &sqlNot{Arg: arg}, nil
+	}
+	return p.predicate()
+}
+
+// predicate parses a parenthesized condition or a test of one bin.
+func (p *sqlParser) predicate() (sqlCondition, error) {
+	if p.symbol("(") {
+		cond, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.symbol(")") {
+			return nil, p.errorf("expected )")
+		}
+		return cond, nil
+	}
+
+	// A literal on the left is compared the other way round
+	if value, ok, err := p.literal(); ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+		op, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		bin, err := p.identifier("a bin name")
+		if err != nil {
+			return nil, err
+		}
+		return &sqlCompare{Bin: bin, Op: flipComparison[op], Value: value}, nil
+	}
+
+	bin, err := p.identifier("a bin name or (")
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(bin, sqlPK) && p.tokens[p.pos-1].kind == "ident" {
+		bin = sqlPK
+	}
+
+	switch {
+	case p.keyword("BETWEEN"):
+		low, err := p.requireLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword("AND") {
+			return nil, p.errorf("expected AND")
+		}
+		high, err := p.requireLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &sqlLogic{Op: "and", Args: []sqlCondition{
+			&sqlCompare{Bin: bin, Op: ">=", Value: low},
+			&sqlCompare{Bin: bin, Op: "<=", Value: high},
+		}}, nil
+
+	case p.keyword("IN"):
+		if !p.symbol("(") {
+			return nil, p.errorf("expected (")
+		}
+		in := &sqlIn{Bin: bin}
+		for {
+			value, err := p.requireLiteral()
+			if err != nil {
+				return nil, err
+			}
+			in.Values = append(in.Values, value)
+			if !p.symbol(",") {
+				break
+			}
+		}
+		if !p.symbol(")") {
+			return nil, p.errorf("expected , or )")
+		}
+		return in, nil
+	}
+
+	op, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	value, err := p.requireLiteral()
+	if err != nil {
+		return nil, err
+	}
+	return &sqlCompare{Bin: bin, Op: op, Value: value}, nil
+}
+
+// flipComparison gives the operator comparing the other way round.
+var flipComparison = map[string]string{
+	"=": "=", "!=": "!=", "<": ">", "<=": ">=", ">": "<", ">=": "<=",
+}
+
+// comparison consumes a comparison operator.
+func (p *sqlParser) comparison() (string, error) {
+	t := p.peek()
+	if t.kind == "symbol" {
+		op := t.text
+		if op == "<>" {
+			op = "!="
+		}
+		if _, ok := flipComparison[op]; ok {
+			p.pos++
+			return op, nil
+		}
+	}
+	return "", p.errorf("expected a comparison, BETWEEN, or IN")
+}
+
+// literal consumes a literal if the next token is one: an int64, float64,
+// string, or bool.
+func (p *sqlParser) literal() (interface{}, bool, error) {
+	t := p.peek()
+	switch {
+	case t.kind == "string":
+		p.pos++
+		return t.text, true, nil
+	case t.kind == "number":
+		p.pos++
+		if n, err := strconv.ParseInt(t.text, 10, 64); err == nil {
+			return n, true, nil
+		}
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid number %s at position %d", t.text, t.pos)
+		}
+		return f, true, nil
+	case p.keyword("TRUE"):
+		return true, true, nil
+	case p.keyword("FALSE"):
+		return false, true, nil
+	}
+	return nil, false, nil
+}
+
+// requireLiteral consumes a literal, or fails.
+func (p *sqlParser) requireLiteral() (interface{}, error) {
+	value, ok, err := p.literal()
+	if !ok && err == nil {
+		err = p.errorf("expected a number, 'string', TRUE, or FALSE")
+	}
+	return value, err
+}
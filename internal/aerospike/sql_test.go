@@ -0,0 +1,104 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		want    *sqlStatement
+		wantErr string
+	}{
+		{
+			name: "all bins",
+			sql:  "SELECT * FROM test.users",
+			want: &sqlStatement{Namespace: "test", Set: "users"},
+		},
+		{
+			name: "clauses",
+			sql:  `select name, "last login" from test.users where age >= 21 and city = 'O''Hare' order by score desc limit 20;`,
+			want: &sqlStatement{
+				Bins:      []string{"name", "last login"},
+				Namespace: "test",
+				Set:       "users",
+				Where: &sqlLogic{Op: "and", Args: []sqlCondition{
+					&sqlCompare{Bin: "age", Op: ">=", Value: int64(21)},
+					&sqlCompare{Bin: "city", Op: "=", Value: "O'Hare"},
+				}},
+				OrderBy: "score",
+				Desc:    true,
+				Limit:   20,
+			},
+		},
+		{
+			name: "precedence and negation",
+			sql:  "SELECT * FROM test WHERE NOT a = 1 OR b <> 2.5 AND (c < -3 OR TRUE = d)",
+			want: &sqlStatement{
+				Namespace: "test",
+				Where: &sqlLogic{Op: "or", Args: []sqlCondition{
+					&sqlNot{Arg: &sqlCompare{Bin: "a", Op: "=", Value: int64(1)}},
+					&sqlLogic{Op: "and", Args: []sqlCondition{
+						&sqlCompare{Bin: "b", Op: "!=", Value: 2.5},
+						&sqlLogic{Op: "or", Args: []sqlCondition{
+							&sqlCompare{Bin: "c", Op: "<", Value: int64(-3)},
+							&sqlCompare{Bin: "d", Op: "=", Value: true},
+						}},
+					}},
+				}},
+			},
+		},
+		{
+			name: "between and in",
+			sql:  "SELECT * FROM test.s WHERE age BETWEEN 18 AND 65 AND pk IN ('a', 'b')",
+			want: &sqlStatement{
+				Namespace: "test",
+				Set:       "s",
+				Where: &sqlLogic{Op: "and", Args: []sqlCondition{
+					&sqlLogic{Op: "and", Args: []sqlCondition{
+						&sqlCompare{Bin: "age", Op: ">=", Value: int64(18)},
+						&sqlCompare{Bin: "age", Op: "<=", Value: int64(65)},
+					}},
+					&sqlIn{Bin: sqlPK, Values: []interface{}{"a", "b"}},
+				}},
+			},
+		},
+		{
+			name: "literal first",
+			sql:  "SELECT * FROM test.s WHERE 10 < score",
+			want: &sqlStatement{Namespace: "test", Set: "s", Where: &sqlCompare{Bin: "score", Op: ">", Value: int64(10)}},
+		},
+		{name: "not a select", sql: "DELETE FROM test.s", wantErr: "expected SELECT"},
+		{name: "missing from", sql: "SELECT a, b", wantErr: "expected FROM"},
+		{name: "keyword as bin", sql: "SELECT * FROM test WHERE limit = 1", wantErr: "expected a bin name"},
+		{name: "unterminated string", sql: "SELECT * FROM test WHERE a = 'x", wantErr: "unterminated quote"},
+		{name: "bad limit", sql: "SELECT * FROM test LIMIT 0", wantErr: "expected a positive LIMIT"},
+		{name: "trailing input", sql: "SELECT * FROM test.s.t", wantErr: "expected end of statement"},
+		{name: "bad character", sql: "SELECT * FROM test WHERE a = 1 + 2", wantErr: `unexpected character '+'`},
+		{name: "missing literal", sql: "SELECT * FROM test WHERE a = b", wantErr: "expected a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSQL(tt.sql)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSQL() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,456 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// SQL plan types, from cheapest to most expensive.
+const (
+	PlanPrimaryKey = "primary_key" // one record read by key
+	PlanBatchGet   = "batch_get"   // records read by a list of keys
+	PlanIndexQuery = "index_query" // secondary index query
+	PlanScan       = "scan"        // full set scan
+)
+
+// SQLPlan describes how sql_query runs a statement: the cluster operation
+// it uses, and the part of the WHERE clause left to a filter expression,
+// which the cluster evaluates on each record the operation reads.
+// ReadLimit is the records read, which is more than Limit when the records
+// are sorted, since only the records read can be.
+type SQLPlan struct {
+	Type             string       `json:"type"`
+	Namespace        string       `json:"namespace"`
+	Set              string       `json:"set,omitempty"`
+	Keys             []string     `json:"keys,omitempty"`
+	Index            string       `json:"index,omitempty"`
+	IndexFilter      *QueryFilter `json:"index_filter,omitempty"`
+	FilterExpression interface{}  `json:"filter_expression,omitempty"`
+	Bins             []string     `json:"bins,omitempty"`
+	OrderBy          string       `json:"order_by,omitempty"`
+	Direction        string       `json:"direction,omitempty"`
+	Limit            int          `json:"limit"`
+	ReadLimit        int          `json:"read_limit,omitempty"`
+}
+
+// SQLResult is the result of sql_query: the plan it chose and the records
+// it returned. Partial and Reason report a query that ran out of time or
+// result space; Truncated one cut to fit max_response_bytes.
+type SQLResult struct {
+	Plan      *SQLPlan  `json:"plan"`
+	Records   []*Record `json:"records"`
+	Partial   bool      `json:"partial,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// ExplainSQL parses a SELECT statement and returns the plan SQLQuery would
+// run it with.
+func (c *Client) ExplainSQL(ctx context.Context, sql string) (*SQLPlan, error) {
+	stmt, err := parseSQL(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []IndexInfo
+	if stmt.Where != nil {
+		if indexes, err = c.ListIndexes(ctx, stmt.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	plan, err := planSQL(stmt, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.Limit == 0 {
+		plan.Limit = c.maxRecords(stmt.Namespace, 0)
+	} else {
+		plan.Limit = c.maxRecords(stmt.Namespace, plan.Limit)
+	}
+	if plan.OrderBy != "" && (plan.Type == PlanIndexQuery || plan.Type == PlanScan) {
+		plan.ReadLimit = c.maxRecords(stmt.Namespace, 0)
+	}
+	return plan, nil
+}
+
+// SQLQuery runs a SELECT statement and returns its records with the plan
+// it chose.
+func (c *Client) SQLQuery(ctx context.Context, sql string) (*SQLResult, error) {
+	plan, err := c.ExplainSQL(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	result := &SQLResult{Plan: plan}
+
+	// The sort bin is read even if it isn't selected, and dropped after
+	readBins := plan.Bins
+	if readBins != nil && plan.OrderBy != "" && !slices.Contains(readBins, plan.OrderBy) {
+		readBins = append(append([]string(nil), readBins...), plan.OrderBy)
+	}
+	order := RecordOrder{OrderBy: plan.OrderBy, Direction: plan.Direction}
+
+	var records []*Record
+	switch plan.Type {
+	case PlanPrimaryKey:
+		record, err := c.GetRecord(ctx, plan.Namespace, plan.Set, plan.Keys[0], readBins)
+		if err != nil {
+			return nil, err
+		}
+		if record.Found() {
+			records = append(records, record)
+		}
+
+	case PlanBatchGet:
+		requests := make([]BatchGetRequest, len(plan.Keys))
+		for i, key := range plan.Keys {
+			requests[i] = BatchGetRequest{Namespace: plan.Namespace, Set: plan.Set, Key: key, BinNames: readBins}
+		}
+		found, err := c.BatchGet(ctx, requests)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range found {
+			if record != nil && record.Found() {
+				records = append(records, record)
+			}
+		}
+		records = order.apply(records)
+
+	default:
+		if plan.FilterExpression != nil {
+			exp, err := buildExpression(plan.FilterExpression)
+			if err != nil {
+				return nil, err
+			}
+			ctx = withFilterExpression(ctx, exp)
+		}
+		ctx = WithRecordOrder(ctx, order)
+		limit := plan.Limit
+		if plan.ReadLimit > 0 {
+			limit = plan.ReadLimit
+		}
+
+		var page *RecordPage
+		if plan.Type == PlanIndexQuery {
+			page, err = c.QueryRecords(ctx, plan.Namespace, plan.Set, plan.Index, *plan.IndexFilter, limit, "")
+		} else {
+			page, err = c.ScanSet(ctx, plan.Namespace, plan.Set, readBins, limit, 0, "")
+		}
+		if errors.Is(err, ErrIncomplete) || errors.Is(err, ErrResultTooLarge) {
+			result.Partial, result.Reason = true, err.Error()
+		} else if err != nil {
+			return nil, err
+		}
+		records = page.Records
+		result.Truncated = page.Truncated
+	}
+
+	if len(records) > plan.Limit {
+		records = records[:plan.Limit]
+	}
+	if plan.Bins != nil {
+		projectBins(records, plan.Bins)
+	}
+	result.Records = records
+	if result.Records == nil {
+		result.Records = []*Record{}
+	}
+	return result, nil
+}
+
+// planSQL chooses how to run a statement given the namespace's indexes. A
+// WHERE clause on the primary key alone reads records by key. Otherwise a
+// condition on an indexed bin that the rest of the clause is ANDed with,
+// if there is one, runs as an index query, and whatever the index doesn't
+// cover as a filter expression.
+func planSQL(stmt *sqlStatement, indexes []IndexInfo) (*SQLPlan, error) {
+	plan := &SQLPlan{
+		Type:      PlanScan,
+		Namespace: stmt.Namespace,
+		Set:       stmt.Set,
+		Bins:      stmt.Bins,
+		OrderBy:   stmt.OrderBy,
+		Limit:     stmt.Limit,
+	}
+	if stmt.OrderBy != "" {
+		plan.Direction = "asc"
+		if stmt.Desc {
+			plan.Direction = "desc"
+		}
+	}
+
+	if keys, ok, err := primaryKeys(stmt.Where); ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+		plan.Type, plan.Keys = PlanBatchGet, keys
+		if len(keys) == 1 {
+			plan.Type = PlanPrimaryKey
+		}
+		return plan, nil
+	}
+
+	conditions := conjuncts(stmt.Where)
+	if index, filter, rest, ok := chooseIndex(stmt.Set, conditions, indexes); ok {
+		plan.Type, plan.Index, plan.IndexFilter = PlanIndexQuery, index, filter
+		conditions = rest
+	}
+
+	var exps []interface{}
+	for _, cond := range conditions {
+		exp, err := sqlExpression(cond)
+		if err != nil {
+			return nil, err
+		}
+		exps = append(exps, exp)
+	}
+	switch len(exps) {
+	case 0:
+	case 1:
+		plan.FilterExpression = exps[0]
+	default:
+		plan.FilterExpression = map[string]interface{}{"op": "and", "args": exps}
+	}
+	return plan, nil
+}
+
+// primaryKeys returns the keys a WHERE clause reads, and true, if it tests
+// only the primary key, with = or IN.
+func primaryKeys(where sqlCondition) ([]string, bool, error) {
+	var values []interface{}
+	switch cond := where.(type) {
+	case *sqlCompare:
+		if cond.Bin != sqlPK {
+			return nil, false, nil
+		}
+		if cond.Op != "=" {
+			return nil, true, fmt.Errorf("PK can only be compared with = or IN")
+		}
+		values = []interface{}{cond.Value}
+	case *sqlIn:
+		if cond.Bin != sqlPK {
+			return nil, false, nil
+		}
+		values = cond.Values
+	default:
+		return nil, false, nil
+	}
+
+	keys := make([]string, len(values))
+	for i, value := range values {
+		key, ok := value.(string)
+		if !ok {
+			return nil, true, fmt.Errorf("PK values must be 'strings', got %v", value)
+		}
+		keys[i] = key
+	}
+	return keys, true, nil
+}
+
+// conjuncts returns the conditions a WHERE clause ANDs together.
+func conjuncts(where sqlCondition) []sqlCondition {
+	if where == nil {
+		return nil
+	}
+	logic, ok := where.(*sqlLogic)
+	if !ok || logic.Op != "and" {
+		return []sqlCondition{where}
+	}
+	var conditions []sqlCondition
+	for _, arg := range logic.Args {
+		conditions = append(conditions, conjuncts(arg)...)
+	}
+	return conditions
+}
+
+// chooseIndex finds a usable index for the first condition that can use
+// one: a comparison of an integer, or string equality, on a bin with a
+// plain numeric or string index on the set. Integer comparisons of the same
+// bin are combined into one range. It returns the index, its filter, and
+// the conditions the filter leaves.
+func chooseIndex(set string, conditions []sqlCondition, indexes []IndexInfo) (string, *QueryFilter, []sqlCondition, bool) {
+	for _, cond := range conditions {
+		cmp, ok := cond.(*sqlCompare)
+		if !ok || cmp.Op == "!=" || cmp.Bin == sqlPK {
+			continue
+		}
+
+		switch value := cmp.Value.(type) {
+		case string:
+			if cmp.Op != "=" {
+				continue
+			}
+			if index, ok := findIndex(indexes, set, cmp.Bin, "string"); ok {
+				filter := &QueryFilter{BinName: cmp.Bin, FilterType: "equal", Value: value}
+				return index, filter, without(conditions, cond), true
+			}
+
+		case int64:
+			index, ok := findIndex(indexes, set, cmp.Bin, "numeric")
+			if !ok {
+				continue
+			}
+			begin, end := int64(math.MinInt64), int64(math.MaxInt64)
+			var rest []sqlCondition
+			for _, other := range conditions {
+				if c, ok := other.(*sqlCompare); ok && c.Bin == cmp.Bin {
+					if n, ok := c.Value.(int64); ok && narrowRange(c.Op, n, &begin, &end) {
+						continue
+					}
+				}
+				rest = append(rest, other)
+			}
+			filter := &QueryFilter{BinName: cmp.Bin, FilterType: "range", Begin: begin, End: end}
+			if begin == end {
+				filter = &QueryFilter{BinName: cmp.Bin, FilterType: "equal", Value: begin}
+			}
+			return index, filter, rest, true
+		}
+	}
+	return "", nil, conditions, false
+}
+
+// narrowRange narrows the inclusive range begin to end by an integer
+// comparison, returning false if the comparison can't be expressed as a
+// range.
+func narrowRange(op string, n int64, begin, end *int64) bool {
+	switch op {
+	case "=":
+		*begin, *end = max(*begin, n), min(*end, n)
+	case ">=":
+		*begin = max(*begin, n)
+	case ">":
+		if n == math.MaxInt64 {
+			return false
+		}
+		*begin = max(*begin, n+1)
+	case "<=":
+		*end = min(*end, n)
+	case "<":
+		if n == math.MinInt64 {
+			return false
+		}
+		*end = min(*end, n-1)
+	default:
+		return false
+	}
+	return true
+}
+
+// findIndex returns the name of a ready, plain index of the given type on a
+// set's bin. Indexes on list or map elements or nested values don't apply.
+func findIndex(indexes []IndexInfo, set, bin, indexType string) (string, bool) {
+	for _, idx := range indexes {
+		if idx.Set != set || idx.Bin != bin || !strings.EqualFold(idx.Type, indexType) {
+			continue
+		}
+		if idx.State != "" && !strings.EqualFold(idx.State, "RW") {
+			continue
+		}
+		if (idx.indexType != "" && !strings.EqualFold(idx.indexType, "default") && !strings.EqualFold(idx.indexType, "none")) ||
+			(idx.context != "" && !strings.EqualFold(idx.context, "null")) {
+			continue
+		}
+		return idx.Name, true
+	}
+	return "", false
+}
+
+// without returns conditions less cond.
+func without(conditions []sqlCondition, cond sqlCondition) []sqlCondition {
+	rest := make([]sqlCondition, 0, len(conditions))
+	for _, c := range conditions {
+		if c != cond {
+			rest = append(rest, c)
+		}
+	}
+	return rest
+}
+
+// sqlOperators are the expression operators for SQL comparisons.
+var sqlOperators = map[string]string{
+	"=": "eq", "!=": "ne", "<": "lt", "<=": "le", ">": "gt", ">=": "ge",
+}
+
+// sqlExpression converts a condition to an expression in the JSON form
+// buildExpression takes. The literal a bin is compared with decides the
+// type the bin is read as.
+func sqlExpression(cond sqlCondition) (interface{}, error) {
+	switch c := cond.(type) {
+	case *sqlCompare:
+		if c.Bin == sqlPK {
+			return nil, fmt.Errorf("PK can only be tested on its own, with = or IN")
+		}
+		var binType string
+		value := c.Value
+		switch v := c.Value.(type) {
+		case int64:
+			binType = "int"
+		case float64:
+			binType = "float"
+			value = map[string]interface{}{floatHint: v}
+		case string:
+			binType = "string"
+		case bool:
+			binType = "bool"
+		}
+		return map[string]interface{}{
+			"op":   sqlOperators[c.Op],
+			"args": []interface{}{map[string]interface{}{"bin": c.Bin, "type": binType}, value},
+		}, nil
+
+	case *sqlIn:
+		var args []interface{}
+		for _, value := range c.Values {
+			exp, err := sqlExpression(&sqlCompare{Bin: c.Bin, Op: "=", Value: value})
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, exp)
+		}
+		if len(args) == 1 {
+			return args[0], nil
+		}
+		return map[string]interface{}{"op": "or", "args": args}, nil
+
+	case *sqlLogic:
+		args := make([]interface{}, len(c.Args))
+		for i, arg := range c.Args {
+			exp, err := sqlExpression(arg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = exp
+		}
+		return map[string]interface{}{"op": c.Op, "args": args}, nil
+
+	case *sqlNot:
+		exp, err := sqlExpression(c.Arg)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"op": "not", "args": []interface{}{exp}}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported condition %v", cond)
+	}
+}
+
+// projectBins removes the bins not in bins from records.
+func projectBins(records []*Record, bins []string) {
+	for _, record := range records {
+		for name := range record.Bins {
+			if !slices.Contains(bins, name) {
+				delete(record.Bins, name)
+			}
+		}
+	}
+}
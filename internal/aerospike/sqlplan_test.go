@@ -0,0 +1,100 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPlanSQL(t *testing.T) {
+	indexes := []IndexInfo{
+		{Name: "users_age", Set: "users", Bin: "age", Type: "numeric", State: "RW"},
+		{Name: "users_city", Set: "users", Bin: "city", Type: "STRING", State: "RW"},
+		{Name: "users_tags", Set: "users", Bin: "tags", Type: "string", State: "RW", indexType: "list"},
+		{Name: "orders_age", Set: "orders", Bin: "age", Type: "numeric", State: "RW"},
+	}
+
+	tests := []struct {
+		name    string
+		sql     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "primary key",
+			sql:  "SELECT name FROM test.users WHERE PK = 'u1'",
+			want: `{"type":"primary_key","namespace":"test","set":"users","keys":["u1"],"bins":["name"],"limit":0}`,
+		},
+		{
+			name: "batch of keys",
+			sql:  "SELECT * FROM test.users WHERE pk IN ('u1', 'u2') LIMIT 5",
+			want: `{"type":"batch_get","namespace":"test","set":"users","keys":["u1","u2"],"limit":5}`,
+		},
+		{
+			name: "range on an indexed bin",
+			sql:  "SELECT * FROM test.users WHERE age > 17 AND age <= 65 AND active = true",
+			want: `{"type":"index_query","namespace":"test","set":"users","index":"users_age","index_filter":{"bin_name":"age","filter_type":"range","value":null,"begin":18,"end":65},` +
+				`"filter_expression":{"args":[{"bin":"active","type":"bool"},true],"op":"eq"},"limit":0}`,
+		},
+		{
+			name: "string equality",
+			sql:  "SELECT * FROM test.users WHERE score < 2.5 AND city = 'Paris' ORDER BY score DESC",
+			want: `{"type":"index_query","namespace":"test","set":"users","index":"users_city","index_filter":{"bin_name":"city","filter_type":"equal","value":"Paris"},` +
+				`"filter_expression":{"args":[{"bin":"score","type":"float"},{"$float":2.5}],"op":"lt"},"order_by":"score","direction":"desc","limit":0}`,
+		},
+		{
+			name: "integer equality",
+			sql:  "SELECT * FROM test.users WHERE age = 30",
+			want: `{"type":"index_query","namespace":"test","set":"users","index":"users_age","index_filter":{"bin_name":"age","filter_type":"equal","value":30},"limit":0}`,
+		},
+		{
+			name: "OR needs a scan",
+			sql:  "SELECT * FROM test.users WHERE age = 30 OR city IN ('Paris')",
+			want: `{"type":"scan","namespace":"test","set":"users","filter_expression":{"args":[{"args":[{"bin":"age","type":"int"},30],"op":"eq"},{"args":[{"bin":"city","type":"string"},"Paris"],"op":"eq"}],"op":"or"},"limit":0}`,
+		},
+		{
+			name: "index on another set or a list",
+			sql:  "SELECT * FROM test.accounts WHERE age = 30 AND NOT tags = 'x'",
+			want: `{"type":"scan","namespace":"test","set":"accounts","filter_expression":{"args":[{"args":[{"bin":"age","type":"int"},30],"op":"eq"},{"args":[{"args":[{"bin":"tags","type":"string"},"x"],"op":"eq"}],"op":"not"}],"op":"and"},"limit":0}`,
+		},
+		{
+			name: "no where clause",
+			sql:  "SELECT * FROM test.users LIMIT 10",
+			want: `{"type":"scan","namespace":"test","set":"users","limit":10}`,
+		},
+		{name: "PK range", sql: "SELECT * FROM test.users WHERE PK > 'a'", wantErr: "PK can only be compared with = or IN"},
+		{name: "PK with other conditions", sql: "SELECT * FROM test.users WHERE PK = 'a' AND age = 1", wantErr: "PK can only be tested on its own"},
+		{name: "numeric PK", sql: "SELECT * FROM test.users WHERE PK = 7", wantErr: "PK values must be 'strings'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := parseSQL(tt.sql)
+			if err != nil {
+				t.Fatalf("parseSQL() error = %v", err)
+			}
+			plan, err := planSQL(stmt, indexes)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("planSQL() error = %v", err)
+			}
+			got, _ := json.Marshal(plan)
+			if string(got) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+			if plan.FilterExpression != nil {
+				if _, err := buildExpression(plan.FilterExpression); err != nil {
+					t.Errorf("buildExpression() error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WarmUp opens up to count connections to each node, so the first requests
+// don't pay for connection setup. It returns the number of connections
+// opened.
+func (c *Client) WarmUp(count int) (int, error) {
+	client := c.conn()
+	if client == nil || !client.IsConnected() {
+		return 0, ErrNotConnected
+	}
+	opened, err := client.WarmUp(count)
+	if err != nil {
+		return opened, fmt.Errorf("warming up connections: %w", err)
+	}
+	return opened, nil
+}
+
+// Probe checks that the cluster answers and has the default namespace and
+// each of required, and returns the cluster's namespaces.
+func (c *Client) Probe(ctx context.Context, required []string) ([]string, error) {
+	client := c.conn()
+	if client == nil || !client.IsConnected() {
+		return nil, ErrNotConnected
+	}
+	nodes := client.GetNodes()
+	if len(nodes) == 0 {
+		return nil, ErrNotConnected
+	}
+
+	start := time.Now()
+	info, err := nodes[0].RequestInfo(infoPolicyFor(ctx), "namespaces")
+	c.observe(ctx, "probe", start, err)
+	if err != nil {
+		return nil, fmt.Errorf("listing namespaces on node %s: %w", nodes[0].GetName(), err)
+	}
+
+	namespaces := splitNamespaces(info["namespaces"])
+	if c.defaultNamespace != "" {
+		required = append([]string{c.defaultNamespace}, required...)
+	}
+	if missing := missingNamespaces(namespaces, required); len(missing) > 0 {
+		return namespaces, fmt.Errorf("namespace %s not found in the cluster (available: %s)",
+			strings.Join(missing, ", "), strings.Join(namespaces, ", "))
+	}
+	return namespaces, nil
+}
+
+// splitNamespaces returns the sorted names in a "namespaces" info value.
+func splitNamespaces(info string) []string {
+	namespaces := []string{}
+	for _, ns := range strings.Split(info, ";") {
+		if ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// missingNamespaces returns the names in required that aren't in
+// namespaces, without duplicates.
+func missingNamespaces(namespaces, required []string) []string {
+	found := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		found[ns] = true
+	}
+	var missing []string
+	for _, ns := range required {
+		if !found[ns] {
+			missing = append(missing, ns)
+			found[ns] = true
+		}
+	}
+	return missing
+}
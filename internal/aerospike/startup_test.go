@@ -0,0 +1,40 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     string
+		required []string
+		want     []string
+	}{
+		{"all present", "test;bar", []string{"test", "bar"}, nil},
+		{"missing", "bar", []string{"test", "bar"}, []string{"test"}},
+		{"duplicates", "bar", []string{"test", "test"}, []string{"test"}},
+		{"empty cluster", "", []string{"test"}, []string{"test"}},
+		{"nothing required", "test", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := missingNamespaces(splitNamespaces(tt.info), tt.required)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSplitNamespaces(t *testing.T) {
+	got := splitNamespaces("test;bar;")
+	if want := []string{"bar", "test"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
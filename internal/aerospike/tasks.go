@@ -0,0 +1,57 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Polling intervals for cluster tasks. Each poll is an info request to every
+// node, so the interval doubles up to taskPollMax.
+var (
+	taskPollInitial  = 25 * time.Millisecond
+	taskPollMax      = time.Second
+	taskProgressTick = 5 * time.Second
+)
+
+// waitForTask polls a long-running cluster task, such as an index build or
+// UDF registration, with exponential backoff until isDone reports that it
+// has finished, its status can't be read, or ctx is done. Long waits are logged with the time
+// elapsed, every taskProgressTick.
+func waitForTask(ctx context.Context, operation string, isDone func() (bool, error)) error {
+	start := time.Now()
+	interval := taskPollInitial
+	lastProgress := start
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %s after %s: %w", operation, time.Since(start).Round(time.Millisecond), ctx.Err())
+		case <-timer.C:
+		}
+
+		done, err := isDone()
+		if err != nil {
+			return fmt.Errorf("waiting for %s: %w", operation, err)
+		}
+		if done {
+			return nil
+		}
+
+		if time.Since(lastProgress) >= taskProgressTick {
+			lastProgress = time.Now()
+			slog.InfoContext(ctx, "Waiting for cluster task", "operation", operation, "elapsed", time.Since(start).Round(time.Second))
+		}
+
+		timer.Reset(interval)
+		if interval *= 2; interval > taskPollMax {
+			interval = taskPollMax
+		}
+	}
+}
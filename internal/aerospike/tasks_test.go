@@ -0,0 +1,65 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeTask finishes after a number of polls, recording when it was polled.
+type fakeTask struct {
+	remaining int
+	err       error
+	polls     []time.Time
+}
+
+func (f *fakeTask) IsDone() (bool, error) {
+	f.polls = append(f.polls, time.Now())
+	if f.err != nil {
+		return false, f.err
+	}
+	f.remaining--
+	return f.remaining <= 0, nil
+}
+
+func TestWaitForTaskBacksOff(t *testing.T) {
+	task := &fakeTask{remaining: 4}
+	if err := waitForTask(context.Background(), "index creation", task.IsDone); err != nil {
+		t.Fatalf("waitForTask() error = %v", err)
+	}
+	if len(task.polls) != 4 {
+		t.Fatalf("Expected 4 polls, got %d", len(task.polls))
+	}
+
+	// Polls are spaced 25ms, 50ms, then 100ms apart
+	for i, min := range []time.Duration{taskPollInitial, 2 * taskPollInitial, 4 * taskPollInitial} {
+		if gap := task.polls[i+1].Sub(task.polls[i]); gap < min {
+			t.Errorf("Expected poll %d at least %s after the previous one, got %s", i+1, min, gap)
+		}
+	}
+}
+
+func TestWaitForTaskDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+
+	task := &fakeTask{remaining: 1000}
+	err := waitForTask(ctx, "UDF registration", task.IsDone)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	if len(task.polls) > 4 {
+		t.Errorf("Expected backoff to limit polling, got %d polls in 60ms", len(task.polls))
+	}
+}
+
+func TestWaitForTaskError(t *testing.T) {
+	task := &fakeTask{err: errors.New("node unavailable")}
+	if err := waitForTask(context.Background(), "UDF removal", task.IsDone); err == nil {
+		t.Error("Expected the task's error")
+	}
+}
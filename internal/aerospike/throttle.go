@@ -0,0 +1,64 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// ScanThrottle slows one request's scans and queries below the configured
+// scan_limits. Zero fields keep the configured limits, and values above a
+// configured limit are capped at it, so a caller can only lower the load.
+type ScanThrottle struct {
+	RecordsPerSecond   int `json:"records_per_second,omitempty"`   // per node
+	MaxConcurrentNodes int `json:"max_concurrent_nodes,omitempty"` // nodes scanned at once
+}
+
+// Validate returns an error if a limit is negative.
+func (t ScanThrottle) Validate() error {
+	if t.RecordsPerSecond < 0 {
+		return fmt.Errorf("records_per_second must not be negative")
+	}
+	if t.MaxConcurrentNodes < 0 {
+		return fmt.Errorf("max_concurrent_nodes must not be negative")
+	}
+	return nil
+}
+
+// scanThrottleContextKey is the context key for a request's scan throttle.
+type scanThrottleContextKey struct{}
+
+// WithScanThrottle returns a context whose scans and queries are throttled
+// by t, which must be valid.
+func WithScanThrottle(ctx context.Context, t ScanThrottle) context.Context {
+	if t == (ScanThrottle{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, scanThrottleContextKey{}, t)
+}
+
+// applyScanThrottle lowers policy's limits to those in ctx's scan throttle.
+func applyScanThrottle(ctx context.Context, policy *as.MultiPolicy) {
+	t, ok := ctx.Value(scanThrottleContextKey{}).(ScanThrottle)
+	if !ok {
+		return
+	}
+	policy.RecordsPerSecond = lowerLimit(policy.RecordsPerSecond, t.RecordsPerSecond)
+	policy.MaxConcurrentNodes = lowerLimit(policy.MaxConcurrentNodes, t.MaxConcurrentNodes)
+}
+
+// lowerLimit returns the stricter of a configured and a requested limit,
+// where zero is unlimited.
+func lowerLimit(configured, requested int) int {
+	if requested == 0 {
+		return configured
+	}
+	if configured == 0 {
+		return requested
+	}
+	return min(configured, requested)
+}
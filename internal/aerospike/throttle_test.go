@@ -0,0 +1,64 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestScanThrottleValidate(t *testing.T) {
+	tests := []struct {
+		throttle ScanThrottle
+		wantErr  bool
+	}{
+		{ScanThrottle{}, false},
+		{ScanThrottle{RecordsPerSecond: 100, MaxConcurrentNodes: 1}, false},
+		{ScanThrottle{RecordsPerSecond: -1}, true},
+		{ScanThrottle{MaxConcurrentNodes: -1}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.throttle.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.throttle, err, tt.wantErr)
+		}
+	}
+}
+
+func TestScanThrottlePolicies(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured config.ScanLimitConfig
+		throttle   ScanThrottle
+		wantRate   int
+		wantNodes  int
+	}{
+		{"configured", config.ScanLimitConfig{RecordsPerSecond: 5000, MaxConcurrentNodes: 2}, ScanThrottle{}, 5000, 2},
+		{"lowered", config.ScanLimitConfig{RecordsPerSecond: 5000, MaxConcurrentNodes: 2}, ScanThrottle{RecordsPerSecond: 100, MaxConcurrentNodes: 1}, 100, 1},
+		{"capped", config.ScanLimitConfig{RecordsPerSecond: 5000, MaxConcurrentNodes: 2}, ScanThrottle{RecordsPerSecond: 10000, MaxConcurrentNodes: 8}, 5000, 2},
+		{"unlimited by config", config.ScanLimitConfig{}, ScanThrottle{RecordsPerSecond: 100, MaxConcurrentNodes: 1}, 100, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.ScanLimits = tt.configured
+			c := newClient(cfg)
+			ctx := WithScanThrottle(context.Background(), tt.throttle)
+
+			scan := c.scanPolicyFor(ctx, "test")
+			if scan.RecordsPerSecond != tt.wantRate || scan.MaxConcurrentNodes != tt.wantNodes {
+				t.Errorf("Expected scan limits %d/%d, got %d/%d", tt.wantRate, tt.wantNodes, scan.RecordsPerSecond, scan.MaxConcurrentNodes)
+			}
+			query := c.queryPolicyFor(ctx, "test")
+			if query.RecordsPerSecond != tt.wantRate || query.MaxConcurrentNodes != tt.wantNodes {
+				t.Errorf("Expected query limits %d/%d, got %d/%d", tt.wantRate, tt.wantNodes, query.RecordsPerSecond, query.MaxConcurrentNodes)
+			}
+			if c.scanPolicy.RecordsPerSecond != tt.configured.RecordsPerSecond {
+				t.Errorf("Expected configured scan policy to be unchanged, got %d", c.scanPolicy.RecordsPerSecond)
+			}
+		})
+	}
+}
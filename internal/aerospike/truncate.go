@@ -0,0 +1,113 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// TruncatePreview describes what truncating a set, or a whole namespace,
+// would remove. Records is the number of distinct records: the objects
+// every node stores, divided by the replication factor. Bytes is the data
+// they take on all nodes, replicas included.
+type TruncatePreview struct {
+	Namespace         string `json:"namespace"`
+	Set               string `json:"set,omitempty"`
+	Records           int64  `json:"records"`
+	Bytes             int64  `json:"bytes"`
+	ReplicationFactor int    `json:"replication_factor"`
+	Nodes             int    `json:"nodes"`
+	PreviewOnly       bool   `json:"preview_only,omitempty"`
+}
+
+// PreviewTruncate asks every active node, in parallel, how many records
+// and bytes of a set it stores, or of the namespace if setName is empty.
+// It fails if any node doesn't answer, since the preview would be short.
+func (c *Client) PreviewTruncate(ctx context.Context, namespace, setName string) (*TruncatePreview, error) {
+	client := c.conn()
+	if client == nil || !client.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	nodes := slices.DeleteFunc(slices.Clone(client.GetNodes()), func(node *as.Node) bool {
+		return !node.IsActive()
+	})
+	usage := make([]truncateUsage, len(nodes))
+
+	start := time.Now()
+	err := errors.Join(c.eachNode(ctx, nodes, func(ctx context.Context, node *as.Node, i int) error {
+		nsCommand := "namespace/" + namespace
+		commands := []string{nsCommand}
+		setCommand := "sets/" + namespace + "/" + setName
+		if setName != "" {
+			commands = append(commands, setCommand)
+		}
+		info, err := node.RequestInfo(infoPolicyFor(ctx), commands...)
+		if err != nil {
+			return err
+		}
+		// Set statistics are separated by colons, namespace ones by semicolons
+		var setStats map[string]string
+		if setName != "" {
+			setStats = parseInfoString(strings.ReplaceAll(strings.TrimSuffix(info[setCommand], ";"), ":", ";"))
+		}
+		usage[i] = nodeTruncateUsage(parseInfoString(info[nsCommand]), setStats)
+		return nil
+	})...)
+	c.observe(ctx, "truncate_preview", start, err)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &TruncatePreview{Namespace: namespace, Set: setName, Nodes: len(nodes), ReplicationFactor: 1}
+	var objects int64
+	for _, u := range usage {
+		objects += u.objects
+		preview.Bytes += u.bytes
+		preview.ReplicationFactor = max(preview.ReplicationFactor, u.replicationFactor)
+	}
+	preview.Records = objects / int64(preview.ReplicationFactor)
+	return preview, nil
+}
+
+// truncateUsage is one node's part of a TruncatePreview.
+type truncateUsage struct {
+	objects           int64
+	bytes             int64
+	replicationFactor int
+}
+
+// nodeTruncateUsage reads the objects and bytes a node stores from its
+// namespace statistics, or from setStats for a set. Server 7 reports
+// data_used_bytes; earlier servers report device and memory usage, of
+// which the device figure is used when the namespace has both.
+func nodeTruncateUsage(nsStats, setStats map[string]string) truncateUsage {
+	var u truncateUsage
+	for _, key := range []string{"effective_replication_factor", "replication-factor"} {
+		if n, err := strconv.Atoi(nsStats[key]); err == nil && n > 0 {
+			u.replicationFactor = n
+			break
+		}
+	}
+
+	stats, byteKeys := nsStats, []string{"data_used_bytes", "device_used_bytes", "memory_used_bytes"}
+	if setStats != nil {
+		stats, byteKeys = setStats, []string{"data_used_bytes", "device_data_bytes", "memory_data_bytes"}
+	}
+	u.objects, _ = strconv.ParseInt(stats["objects"], 10, 64)
+	for _, key := range byteKeys {
+		if v, ok := stats[key]; ok {
+			u.bytes, _ = strconv.ParseInt(v, 10, 64)
+			break
+		}
+	}
+	return u
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import "testing"
+
+func TestNodeTruncateUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		nsStats  map[string]string
+		setStats map[string]string
+		want     truncateUsage
+	}{
+		{
+			name:    "namespace on server 7",
+			nsStats: map[string]string{"objects": "1200", "data_used_bytes": "98304", "effective_replication_factor": "2", "replication-factor": "3"},
+			want:    truncateUsage{objects: 1200, bytes: 98304, replicationFactor: 2},
+		},
+		{
+			name:    "namespace on server 6",
+			nsStats: map[string]string{"objects": "10", "device_used_bytes": "4096", "memory_used_bytes": "1024", "replication-factor": "2"},
+			want:    truncateUsage{objects: 10, bytes: 4096, replicationFactor: 2},
+		},
+		{
+			name:     "set",
+			nsStats:  map[string]string{"objects": "1200", "data_used_bytes": "98304", "replication-factor": "2"},
+			setStats: map[string]string{"objects": "300", "memory_data_bytes": "2048"},
+			want:     truncateUsage{objects: 300, bytes: 2048, replicationFactor: 2},
+		},
+		{
+			name:     "set missing on the node",
+			nsStats:  map[string]string{},
+			setStats: map[string]string{},
+			want:     truncateUsage{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeTruncateUsage(tt.nsStats, tt.setStats); got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package aerospike
+
+import (
+	"context"
+	"fmt"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+// WriteMode is how a put treats an existing record. The default creates
+// the record or updates the bins written, keeping the others.
+type WriteMode string
+
+const (
+	WriteModeCreateOnly  WriteMode = "create_only"  // fail if the record exists
+	WriteModeUpdateOnly  WriteMode = "update_only"  // fail if it doesn't; keep other bins
+	WriteModeReplace     WriteMode = "replace"      // create it, or replace all its bins
+	WriteModeReplaceOnly WriteMode = "replace_only" // fail if it doesn't exist; replace all its bins
+)
+
+// writeModeActions maps the write modes to the cluster's exists actions.
+var writeModeActions = map[WriteMode]as.RecordExistsAction{
+	"":                   as.UPDATE,
+	WriteModeCreateOnly:  as.CREATE_ONLY,
+	WriteModeUpdateOnly:  as.UPDATE_ONLY,
+	WriteModeReplace:     as.REPLACE,
+	WriteModeReplaceOnly: as.REPLACE_ONLY,
+}
+
+// Validate returns an error if the mode is unknown.
+func (m WriteMode) Validate() error {
+	if _, ok := writeModeActions[m]; !ok {
+		return fmt.Errorf("invalid mode: %s (must be create_only, update_only, replace, or replace_only)", m)
+	}
+	return nil
+}
+
+// action returns the cluster's exists action for the mode.
+func (m WriteMode) action() as.RecordExistsAction {
+	return writeModeActions[m]
+}
+
+// check returns why a put in this mode would fail on a record that exists
+// or not, or "" if it wouldn't.
+func (m WriteMode) check(exists bool) string {
+	switch {
+	case exists && m == WriteModeCreateOnly:
+		return "record already exists (mode create_only)"
+	case !exists && (m == WriteModeUpdateOnly || m == WriteModeReplaceOnly):
+		return fmt.Sprintf("record not found (mode %s)", m)
+	}
+	return ""
+}
+
+// writeModeContextKey is the context key for a request's write mode.
+type writeModeContextKey struct{}
+
+// WithWriteMode returns a context whose puts use mode m, which must be
+// valid.
+func WithWriteMode(ctx context.Context, m WriteMode) context.Context {
+	if m == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, writeModeContextKey{}, m)
+}
+
+// writeModeFrom returns ctx's write mode, or the default.
+func writeModeFrom(ctx context.Context) WriteMode {
+	m, _ := ctx.Value(writeModeContextKey{}).(WriteMode)
+	return m
+}
@@ -0,0 +1,285 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// signatureOperation is the operation of the events that sign the chain.
+const signatureOperation = "audit_signature"
+
+// defaultSignEvery is the number of events between signatures.
+const defaultSignEvery = 100
+
+// hashSuffix precedes the hash at the end of every chained event line.
+const hashSuffix = `,"hash":"`
+
+// chain links audit events into a hash chain. Each event records the hash of
+// the one before it, and its own hash covers every other field, so editing,
+// removing, or reordering events breaks the chain. If a signing key is set,
+// every signEvery events an audit_signature event carrying an HMAC of the
+// chain head is written, so the chain can't be recomputed without the key.
+type chain struct {
+	seq       uint64
+	prevHash  string
+	key       []byte
+	signEvery int
+	unsigned  int    // events since the last signature
+	first     uint64 // first sequence number since the last signature
+}
+
+// newChain creates a chain that continues from the last chained event in
+// path, or in its most recently rotated file if path has none.
+func newChain(path string, key []byte, signEvery int) (*chain, error) {
+	if signEvery <= 0 {
+		signEvery = defaultSignEvery
+	}
+	c := &chain{key: key, signEvery: signEvery}
+
+	if path == "" {
+		return c, nil
+	}
+	found, err := c.resume(path)
+	if err != nil || found {
+		return c, err
+	}
+
+	backups, err := listBackups(path)
+	if err == nil && len(backups) > 0 {
+		_, err = c.resume(backups[len(backups)-1].path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// resume continues the chain from the last chained event in the file at
+// path, reporting whether it found one.
+func (c *chain) resume(path string) (bool, error) {
+	file, err := OpenLog(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading audit chain: %w", err)
+	}
+	defer file.Close()
+
+	found := false
+	err = eachLine(file, func(_ int, line []byte) error {
+		body, hash, ok := splitHash(line)
+		if !ok {
+			return nil
+		}
+		var event struct {
+			Seq uint64 `json:"seq"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil
+		}
+		c.seq, c.prevHash = event.Seq, hash
+		found = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("reading audit chain: %w", err)
+	}
+	return found, nil
+}
+
+// link assigns the event its place in the chain and returns the line to
+// write.
+func (c *chain) link(event *Event) ([]byte, error) {
+	event.Seq = c.seq + 1
+	event.PrevHash = c.prevHash
+	event.Hash = ""
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	event.Hash = hex.EncodeToString(sum[:])
+
+	c.seq = event.Seq
+	c.prevHash = event.Hash
+	if event.Operation != signatureOperation {
+		if c.unsigned == 0 {
+			c.first = event.Seq
+		}
+		c.unsigned++
+	}
+	return appendHash(data, event.Hash), nil
+}
+
+// signatureDue reports whether the chain should be signed now.
+func (c *chain) signatureDue() bool {
+	return c.key != nil && c.unsigned >= c.signEvery
+}
+
+// signature returns an event signing the chain up to its current head.
+func (c *chain) signature() Event {
+	event := Event{
+		Timestamp: time.Now().UTC(),
+		Level:     LevelAudit,
+		Category:  CategorySystem,
+		Operation: signatureOperation,
+		Success:   true,
+		Details: map[string]interface{}{
+			"first_seq": c.first,
+			"last_seq":  c.seq,
+			"signature": sign(c.key, c.prevHash),
+		},
+	}
+	c.unsigned = 0
+	return event
+}
+
+// sign returns the hex HMAC-SHA256 of hash.
+func sign(key []byte, hash string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// appendHash adds the hash field to the end of a marshaled event.
+func appendHash(data []byte, hash string) []byte {
+	line := make([]byte, 0, len(data)+len(hashSuffix)+len(hash)+2)
+	line = append(line, data[:len(data)-1]...)
+	line = append(line, hashSuffix...)
+	line = append(line, hash...)
+	return append(line, '"', '}')
+}
+
+// splitHash separates a chained event line into the marshaled event that was
+// hashed and its hash. It returns false if the line isn't chained.
+func splitHash(line []byte) ([]byte, string, bool) {
+	n := len(hashSuffix) + sha256.Size*2 + 2
+	if len(line) < n+1 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return nil, "", false
+	}
+	tail := line[len(line)-n:]
+	if !bytes.HasPrefix(tail, []byte(hashSuffix)) {
+		return nil, "", false
+	}
+
+	hash := string(tail[len(hashSuffix) : n-2])
+	body := append(append([]byte{}, line[:len(line)-n]...), '}')
+	return body, hash, true
+}
+
+// eachLine calls fn with every non-empty line of r and its line number.
+func eachLine(r io.Reader, fn func(n int, line []byte) error) error {
+	reader := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimRight(line, "\r\n"); len(line) > 0 {
+			if ferr := fn(n, line); ferr != nil {
+				return ferr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ReadSigningKey reads an audit signing key from a file. Surrounding
+// whitespace is ignored.
+func ReadSigningKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading audit signing key: %w", err)
+	}
+	key := bytes.TrimSpace(data)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("audit signing key file %s is empty", path)
+	}
+	return key, nil
+}
+
+// VerifyResult summarizes a verified audit log.
+type VerifyResult struct {
+	Events     int // chained events, including signatures
+	Signatures int // audit_signature events
+	Unchained  int // events written before hash chaining was enabled
+	Unsigned   int // events after the last signature
+}
+
+// Verify checks the hash chain of an audit log, and its signatures if key is
+// set. It returns an error naming the first line at which the log was
+// modified. Events written before chaining was enabled are skipped.
+func Verify(r io.Reader, key []byte) (*VerifyResult, error) {
+	result := &VerifyResult{}
+	var prev Event
+
+	err := eachLine(r, func(n int, line []byte) error {
+		body, hash, ok := splitHash(line)
+		if !ok {
+			if result.Events == 0 {
+				result.Unchained++
+				return nil
+			}
+			return fmt.Errorf("line %d: event is not hash-chained", n)
+		}
+
+		sum := sha256.Sum256(body)
+		if hex.EncodeToString(sum[:]) != hash {
+			return fmt.Errorf("line %d: hash mismatch, event was modified", n)
+		}
+
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+
+		// The first event may continue a chain from a rotated-away file
+		if result.Events > 0 {
+			if event.PrevHash != prev.Hash {
+				return fmt.Errorf("line %d: chain broken, events were removed or reordered", n)
+			}
+			if event.Seq != prev.Seq+1 {
+				return fmt.Errorf("line %d: expected sequence %d, got %d", n, prev.Seq+1, event.Seq)
+			}
+		}
+		event.Hash = hash
+
+		if event.Category == CategorySystem && event.Operation == signatureOperation {
+			if key != nil {
+				sig, _ := event.Details["signature"].(string)
+				if !hmac.Equal([]byte(sig), []byte(sign(key, event.PrevHash))) {
+					return fmt.Errorf("line %d: invalid signature", n)
+				}
+			}
+			result.Signatures++
+			result.Unsigned = 0
+		} else {
+			result.Unsigned++
+		}
+
+		result.Events++
+		prev = event
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newChainLogger(t *testing.T, path string, key string, signEvery int) *Logger {
+	t.Helper()
+	cfg := Config{Enabled: true, FilePath: path, HashChain: true, SignEvery: signEvery}
+	if key != "" {
+		cfg.SigningKeyFile = filepath.Join(t.TempDir(), "audit.key")
+		if err := os.WriteFile(cfg.SigningKeyFile, []byte(key+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logger, err := NewLogger(cfg)
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+	return logger
+}
+
+func writeChainedLog(t *testing.T, key string, events int) []string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger := newChainLogger(t, path, key, 3)
+	for i := 0; i < events; i++ {
+		logger.Log(Event{Level: LevelInfo, Category: CategoryRead, Operation: "get_record", Key: strings.Repeat("k", i+1), Success: true})
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestVerify(t *testing.T) {
+	lines := writeChainedLog(t, "s3cret", 5)
+
+	// 5 events, a signature after the third, and one on close
+	if len(lines) != 7 {
+		t.Fatalf("Expected 7 lines, got %d", len(lines))
+	}
+
+	tests := []struct {
+		name    string
+		lines   func() []string
+		key     string
+		wantErr string
+	}{
+		{
+			name:  "intact",
+			lines: func() []string { return lines },
+			key:   "s3cret",
+		},
+		{
+			name:  "intact without key",
+			lines: func() []string { return lines },
+		},
+		{
+			name: "modified event",
+			lines: func() []string {
+				l := append([]string{}, lines...)
+				l[1] = strings.Replace(l[1], `"success":true`, `"success":false`, 1)
+				return l
+			},
+			key:     "s3cret",
+			wantErr: "line 2: hash mismatch",
+		},
+		{
+			name: "removed event",
+			lines: func() []string {
+				l := append([]string{}, lines[:1]...)
+				return append(l, lines[2:]...)
+			},
+			key:     "s3cret",
+			wantErr: "line 2: chain broken",
+		},
+		{
+			name: "reordered events",
+			lines: func() []string {
+				l := append([]string{}, lines...)
+				l[0], l[1] = l[1], l[0]
+				return l
+			},
+			key:     "s3cret",
+			wantErr: "line 2: chain broken",
+		},
+		{
+			name:    "wrong key",
+			lines:   func() []string { return lines },
+			key:     "other",
+			wantErr: "line 4: invalid signature",
+		},
+		{
+			name: "unchained event appended",
+			lines: func() []string {
+				return append(append([]string{}, lines...), `{"operation":"forged"}`)
+			},
+			key:     "s3cret",
+			wantErr: "line 8: event is not hash-chained",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key []byte
+			if tt.key != "" {
+				key = []byte(tt.key)
+			}
+			_, err := Verify(strings.NewReader(strings.Join(tt.lines(), "\n")), key)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected log to verify, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyResult(t *testing.T) {
+	lines := writeChainedLog(t, "s3cret", 5)
+	input := `{"operation":"before_chaining"}` + "\n" + strings.Join(lines[:5], "\n")
+
+	result, err := Verify(strings.NewReader(input), []byte("s3cret"))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Unchained != 1 {
+		t.Errorf("Expected 1 unchained event, got %d", result.Unchained)
+	}
+	if result.Events != 5 || result.Signatures != 1 {
+		t.Errorf("Expected 5 events and 1 signature, got %d and %d", result.Events, result.Signatures)
+	}
+	if result.Unsigned != 1 {
+		t.Errorf("Expected 1 unsigned event, got %d", result.Unsigned)
+	}
+}
+
+func TestChainResumes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for i := 0; i < 2; i++ {
+		logger := newChainLogger(t, path, "", 0)
+		logger.Log(Event{Category: CategoryWrite, Operation: "put_record", Success: true})
+		logger.Log(Event{Category: CategoryWrite, Operation: "delete_record", Success: true})
+		if err := logger.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Verify(bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("Expected chain to continue across restarts, got %v", err)
+	}
+	if result.Events != 4 {
+		t.Errorf("Expected 4 events, got %d", result.Events)
+	}
+}
+
+func TestChainEventFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newChainLogger(t, "", "", 0)
+	logger.SetOutput(&buf)
+
+	logger.Log(Event{Category: CategoryRead, Operation: "first"})
+	logger.Log(Event{Category: CategoryRead, Operation: "second"})
+
+	events := logger.GetRecentEvents(2)
+	if events[0].Seq != 1 || events[1].Seq != 2 {
+		t.Errorf("Expected sequence 1, 2, got %d, %d", events[0].Seq, events[1].Seq)
+	}
+	if events[0].PrevHash != "" {
+		t.Errorf("Expected no previous hash for first event, got %s", events[0].PrevHash)
+	}
+	if events[1].PrevHash != events[0].Hash || events[0].Hash == "" {
+		t.Errorf("Expected second event to link to %s, got %s", events[0].Hash, events[1].PrevHash)
+	}
+}
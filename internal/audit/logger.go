@@ -9,7 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"sync"
 	"time"
@@ -34,6 +34,7 @@ const (
 	CategoryAdmin  Category = "ADMIN"
 	CategoryAuth   Category = "AUTH"
 	CategorySystem Category = "SYSTEM"
+	CategoryRedact Category = "REDACT"
 )
 
 // Event represents an audit log event.
@@ -47,11 +48,18 @@ type Event struct {
 	Key         string                 `json:"key,omitempty"`
 	User        string                 `json:"user,omitempty"`
 	ClientID    string                 `json:"client_id,omitempty"`
+	RequestID   string                 `json:"request_id,omitempty"`
 	Duration    time.Duration          `json:"duration_ns"`
 	Success     bool                   `json:"success"`
 	Error       string                 `json:"error,omitempty"`
 	Details     map[string]interface{} `json:"details,omitempty"`
 	RecordCount int                    `json:"record_count,omitempty"`
+
+	// Hash chain fields, set when hash chaining is enabled. Hash must remain
+	// the last field.
+	Seq      uint64 `json:"seq,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
 }
 
 // Logger provides audit logging functionality.
@@ -62,6 +70,8 @@ type Logger struct {
 	minLevel Level
 	buffer   []Event
 	bufSize  int
+	chain    *chain // nil unless hash chaining is enabled
+	sinks    []*queuedSink
 }
 
 // Config holds audit logger configuration.
@@ -70,6 +80,15 @@ type Config struct {
 	FilePath   string `json:"file_path,omitempty"`
 	BufferSize int    `json:"buffer_size"`
 	MinLevel   Level  `json:"min_level"`
+
+	// Tamper evidence: hash-chain events, and sign the chain every
+	// SignEvery events with the key in SigningKeyFile.
+	HashChain      bool   `json:"hash_chain"`
+	SigningKeyFile string `json:"signing_key_file,omitempty"`
+	SignEvery      int    `json:"sign_every,omitempty"`
+
+	// Rotation and retention of FilePath
+	Rotation RotationConfig `json:"rotation,omitempty"`
 }
 
 // DefaultConfig returns default audit configuration.
@@ -83,9 +102,30 @@ func DefaultConfig() Config {
 
 // NewLogger creates a new audit logger.
 func NewLogger(cfg Config) (*Logger, error) {
+	// Continue the hash chain from the end of the existing file
+	var c *chain
+	if cfg.HashChain {
+		var key []byte
+		var err error
+		if cfg.SigningKeyFile != "" {
+			if key, err = ReadSigningKey(cfg.SigningKeyFile); err != nil {
+				return nil, err
+			}
+		}
+		if c, err = newChain(cfg.FilePath, key, cfg.SignEvery); err != nil {
+			return nil, err
+		}
+	}
+
 	var writer io.Writer = os.Stderr
 
-	if cfg.FilePath != "" {
+	if cfg.FilePath != "" && cfg.Rotation.Enabled() {
+		file, err := openRotatingFile(cfg.FilePath, cfg.Rotation)
+		if err != nil {
+			return nil, err
+		}
+		writer = file
+	} else if cfg.FilePath != "" {
 		file, err := os.OpenFile(cfg.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
 			return nil, fmt.Errorf("opening audit log file: %w", err)
@@ -104,6 +144,7 @@ func NewLogger(cfg Config) (*Logger, error) {
 		minLevel: cfg.MinLevel,
 		buffer:   make([]Event, 0, bufSize),
 		bufSize:  bufSize,
+		chain:    c,
 	}, nil
 }
 
@@ -121,19 +162,49 @@ func (l *Logger) Log(event Event) {
 	defer l.mu.Unlock()
 
 	// Write to output
-	data, err := json.Marshal(event)
-	if err != nil {
-		log.Printf("Audit log marshal error: %v", err)
+	if !l.write(&event) {
 		return
 	}
 
-	_, _ = l.writer.Write(append(data, '\n'))
-
 	// Buffer for potential batch operations
 	l.buffer = append(l.buffer, event)
 	if len(l.buffer) >= l.bufSize {
 		l.buffer = l.buffer[1:] // Keep buffer size limited
 	}
+
+	if l.chain != nil && l.chain.signatureDue() {
+		signature := l.chain.signature()
+		l.write(&signature)
+	}
+}
+
+// write marshals an event, linking it into the hash chain if enabled, and
+// writes it. The caller must hold l.mu.
+func (l *Logger) write(event *Event) bool {
+	var data []byte
+	var err error
+	if l.chain != nil {
+		data, err = l.chain.link(event)
+	} else {
+		data, err = json.Marshal(event)
+	}
+	if err != nil {
+		slog.Error("Audit log marshal error", "error", err)
+		return false
+	}
+
+	_, _ = l.writer.Write(append(data, '\n'))
+	for _, sink := range l.sinks {
+		sink.send(*event)
+	}
+	return true
+}
+
+// SetOutput replaces the writer events are written to.
+func (l *Logger) SetOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writer = w
 }
 
 // LogRead logs a read operation.
@@ -166,6 +237,7 @@ func (l *Logger) LogRead(ctx context.Context, operation, namespace, set, key str
 			event.ClientID = clientIDStr
 		}
 	}
+	event.RequestID = RequestIDFromContext(ctx)
 
 	l.Log(event)
 }
@@ -200,6 +272,7 @@ func (l *Logger) LogWrite(ctx context.Context, operation, namespace, set, key st
 			event.ClientID = clientIDStr
 		}
 	}
+	event.RequestID = RequestIDFromContext(ctx)
 
 	l.Log(event)
 }
@@ -231,6 +304,7 @@ func (l *Logger) LogAdmin(ctx context.Context, operation string, details map[str
 			event.ClientID = clientIDStr
 		}
 	}
+	event.RequestID = RequestIDFromContext(ctx)
 
 	l.Log(event)
 }
@@ -246,6 +320,7 @@ func (l *Logger) LogAuth(ctx context.Context, operation string, success bool, de
 		Level:     level,
 		Category:  CategoryAuth,
 		Operation: operation,
+		RequestID: RequestIDFromContext(ctx),
 		Success:   success,
 		Details:   details,
 	}
@@ -253,6 +328,50 @@ func (l *Logger) LogAuth(ctx context.Context, operation string, success bool, de
 	l.Log(event)
 }
 
+// LogRedaction logs that bin values were withheld from a response. bins
+// lists bins removed by redaction rules and masked lists bins in which PII
+// was masked.
+func (l *Logger) LogRedaction(ctx context.Context, operation, namespace, set string, bins, masked []string) {
+	details := map[string]interface{}{}
+	if len(bins) > 0 {
+		details["bins"] = bins
+	}
+	if len(masked) > 0 {
+		details["pii_masked"] = masked
+	}
+
+	l.Log(Event{
+		Level:     LevelAudit,
+		Category:  CategoryRedact,
+		Operation: operation,
+		Namespace: namespace,
+		Set:       set,
+		User:      UserFromContext(ctx),
+		ClientID:  ClientIDFromContext(ctx),
+		RequestID: RequestIDFromContext(ctx),
+		Success:   true,
+		Details:   details,
+	})
+}
+
+// LogOverride logs that a caller confirmed an operation a safety check
+// would otherwise refuse, such as a scan of a large set. details describes
+// the check.
+func (l *Logger) LogOverride(ctx context.Context, category Category, operation, namespace, set string, details map[string]interface{}) {
+	l.Log(Event{
+		Level:     LevelAudit,
+		Category:  category,
+		Operation: operation,
+		Namespace: namespace,
+		Set:       set,
+		User:      UserFromContext(ctx),
+		ClientID:  ClientIDFromContext(ctx),
+		RequestID: RequestIDFromContext(ctx),
+		Success:   true,
+		Details:   details,
+	})
+}
+
 // GetRecentEvents returns the most recent buffered events.
 func (l *Logger) GetRecentEvents(count int) []Event {
 	l.mu.Lock()
@@ -268,11 +387,24 @@ func (l *Logger) GetRecentEvents(count int) []Event {
 	return events
 }
 
-// Close closes the audit logger.
+// Close closes the audit logger, first signing any events written since the
+// last signature.
 func (l *Logger) Close() error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	if l.enabled && l.chain != nil && l.chain.key != nil && l.chain.unsigned > 0 {
+		signature := l.chain.signature()
+		l.write(&signature)
+	}
+
+	for _, sink := range l.sinks {
+		if err := sink.close(); err != nil {
+			slog.Error("Closing audit sink failed", "sink", sink.name, "error", err)
+		}
+	}
+	l.sinks = nil
+
 	if closer, ok := l.writer.(io.Closer); ok && l.writer != os.Stderr && l.writer != os.Stdout {
 		return closer.Close()
 	}
@@ -283,8 +415,9 @@ func (l *Logger) Close() error {
 type contextKey string
 
 const (
-	ContextKeyUser     contextKey = "audit_user"
-	ContextKeyClientID contextKey = "audit_client_id"
+	ContextKeyUser      contextKey = "audit_user"
+	ContextKeyClientID  contextKey = "audit_client_id"
+	ContextKeyRequestID contextKey = "audit_request_id"
 )
 
 // WithUser adds user information to context.
@@ -296,3 +429,40 @@ func WithUser(ctx context.Context, user string) context.Context {
 func WithClientID(ctx context.Context, clientID string) context.Context {
 	return context.WithValue(ctx, ContextKeyClientID, clientID)
 }
+
+// WithRequestID adds the ID assigned to the request being handled to
+// context.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ContextKeyRequestID, requestID)
+}
+
+// UserFromContext returns the user stored in context, if any.
+func UserFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(ContextKeyUser).(string)
+	return user
+}
+
+// ClientIDFromContext returns the client ID stored in context, if any.
+func ClientIDFromContext(ctx context.Context) string {
+	clientID, _ := ctx.Value(ContextKeyClientID).(string)
+	return clientID
+}
+
+// RequestIDFromContext returns the request ID stored in context, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(ContextKeyRequestID).(string)
+	return requestID
+}
+
+// ClientKey identifies the client a request is counted against by rate and
+// concurrency limits: the authenticated user, else the transport's client
+// ID. Unidentified clients share the key "".
+func ClientKey(ctx context.Context) string {
+	if user := UserFromContext(ctx); user != "" {
+		return "user:" + user
+	}
+	if clientID := ClientIDFromContext(ctx); clientID != "" {
+		return "client:" + clientID
+	}
+	return ""
+}
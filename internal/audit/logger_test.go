@@ -125,6 +125,34 @@ func TestLogWrite(t *testing.T) {
 	}
 }
 
+func TestLogOverride(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &Logger{
+		writer:  &buf,
+		enabled: true,
+		buffer:  make([]Event, 0, 10),
+		bufSize: 10,
+	}
+
+	ctx := WithUser(context.Background(), "test_user")
+	logger.LogOverride(ctx, CategoryRead, "scan_set", "test_ns", "test_set", map[string]interface{}{"confirm_large_scan": true})
+
+	var logged Event
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &logged); err != nil {
+		t.Fatalf("Failed to parse logged event: %v", err)
+	}
+
+	if logged.Level != LevelAudit || logged.Category != CategoryRead {
+		t.Errorf("Expected an AUDIT READ event, got %s %s", logged.Level, logged.Category)
+	}
+	if logged.User != "test_user" || logged.Set != "test_set" {
+		t.Errorf("Expected test_user on test_set, got %s on %s", logged.User, logged.Set)
+	}
+	if logged.Details["confirm_large_scan"] != true {
+		t.Errorf("Expected confirm_large_scan in details, got %v", logged.Details)
+	}
+}
+
 func TestGetRecentEvents(t *testing.T) {
 	logger := &Logger{
 		writer:  &bytes.Buffer{},
@@ -159,3 +187,31 @@ func TestDisabledLogger(t *testing.T) {
 		t.Error("Disabled logger should not write output")
 	}
 }
+
+func TestContextAccessors(t *testing.T) {
+	ctx := context.Background()
+	if UserFromContext(ctx) != "" || ClientIDFromContext(ctx) != "" {
+		t.Error("Expected empty values from bare context")
+	}
+
+	ctx = WithClientID(WithUser(ctx, "alice"), "client_1")
+	if got := UserFromContext(ctx); got != "alice" {
+		t.Errorf("UserFromContext() = '%s', want 'alice'", got)
+	}
+	if got := ClientIDFromContext(ctx); got != "client_1" {
+		t.Errorf("ClientIDFromContext() = '%s', want 'client_1'", got)
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	ctx := context.Background()
+	if key := ClientKey(ctx); key != "" {
+		t.Errorf("Expected shared key, got '%s'", key)
+	}
+	if key := ClientKey(WithClientID(ctx, "ws-1")); key != "client:ws-1" {
+		t.Errorf("Expected 'client:ws-1', got '%s'", key)
+	}
+	if key := ClientKey(WithUser(WithClientID(ctx, "ws-1"), "ci-bot")); key != "user:ci-bot" {
+		t.Errorf("Expected 'user:ci-bot', got '%s'", key)
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// luaDeniedGlobals are global functions UDFs may not use: they load or run
+// code from outside the module or change function environments. require
+// would also return denied libraries, as in require("os").execute.
+var luaDeniedGlobals = map[string]bool{
+	"require":    true,
+	"module":     true,
+	"load":       true,
+	"loadfile":   true,
+	"loadstring": true,
+	"dofile":     true,
+	"setfenv":    true,
+	"getfenv":    true,
+}
+
+// luaDeniedLibraries are libraries no member of which UDFs may use.
+// package.loaded holds every loaded library, including os and io.
+var luaDeniedLibraries = map[string]bool{
+	"io":      true,
+	"debug":   true,
+	"package": true,
+}
+
+// luaDeniedMembers are library functions UDFs may not use. Other members of
+// these libraries, such as os.time, are allowed.
+var luaDeniedMembers = map[string]bool{
+	"os.execute": true,
+	"os.exit":    true,
+	"os.remove":  true,
+	"os.rename":  true,
+	"os.tmpname": true,
+	"os.getenv":  true,
+}
+
+// luaRestrictedNames are globals that may only be used through constant
+// field accesses, such as os.time. Using them any other way, for example
+// aliasing os or indexing _G with a computed key, would hide which function
+// is called.
+var luaRestrictedNames = map[string]bool{
+	"os":      true,
+	"io":      true,
+	"debug":   true,
+	"package": true,
+	"_G":      true,
+	"_ENV":    true,
+}
+
+// checkLuaCode parses a Lua module and walks it for uses of dangerous
+// functions and libraries. It returns a ValidationError for a syntax error
+// or the first dangerous use found.
+func checkLuaCode(code string) error {
+	chunk, err := parse.Parse(strings.NewReader(code), "udf")
+	if err != nil {
+		var syntaxErr *parse.Error
+		if errors.As(err, &syntaxErr) {
+			if syntaxErr.Pos.Line == parse.EOF {
+				return ValidationError{Field: "code", Message: fmt.Sprintf("syntax error at end of code: %s", syntaxErr.Message)}
+			}
+			return ValidationError{
+				Field:   "code",
+				Message: fmt.Sprintf("syntax error on line %d near '%s': %s", syntaxErr.Pos.Line, syntaxErr.Token, syntaxErr.Message),
+			}
+		}
+		return ValidationError{Field: "code", Message: fmt.Sprintf("syntax error: %v", err)}
+	}
+
+	c := &luaChecker{}
+	c.block(chunk, nil)
+	if c.err != nil {
+		return *c.err
+	}
+	return nil
+}
+
+// luaChecker walks a Lua syntax tree. It tracks local variables so that a
+// local that shadows a library name isn't mistaken for the library.
+type luaChecker struct {
+	scopes []map[string]bool
+	err    *ValidationError
+}
+
+func (c *luaChecker) fail(line int, format string, args ...interface{}) {
+	if c.err == nil {
+		c.err = &ValidationError{Field: "code", Message: fmt.Sprintf("line %d: ", line) + fmt.Sprintf(format, args...)}
+	}
+}
+
+func (c *luaChecker) push(names ...string) {
+	scope := make(map[string]bool, len(names))
+	for _, name := range names {
+		scope[name] = true
+	}
+	c.scopes = append(c.scopes, scope)
+}
+
+func (c *luaChecker) pop() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+func (c *luaChecker) declare(names ...string) {
+	for _, name := range names {
+		c.scopes[len(c.scopes)-1][name] = true
+	}
+}
+
+func (c *luaChecker) isLocal(name string) bool {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if c.scopes[i][name] {
+			return true
+		}
+	}
+	return false
+}
+
+// block walks statements in a new scope declaring names.
+func (c *luaChecker) block(stmts []ast.Stmt, names []string) {
+	c.push(names...)
+	defer c.pop()
+	for _, stmt := range stmts {
+		c.stmt(stmt)
+	}
+}
+
+func (c *luaChecker) stmt(stmt ast.Stmt) {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		c.exprs(s.Lhs)
+		c.exprs(s.Rhs)
+	case *ast.LocalAssignStmt:
+		c.exprs(s.Exprs)
+		c.declare(s.Names...)
+	case *ast.FuncCallStmt:
+		c.expr(s.Expr)
+	case *ast.DoBlockStmt:
+		c.block(s.Stmts, nil)
+	case *ast.WhileStmt:
+		c.expr(s.Condition)
+		c.block(s.Stmts, nil)
+	case *ast.RepeatStmt:
+		// The condition can see the body's locals
+		c.push()
+		for _, st := range s.Stmts {
+			c.stmt(st)
+		}
+		c.expr(s.Condition)
+		c.pop()
+	case *ast.IfStmt:
+		c.expr(s.Condition)
+		c.block(s.Then, nil)
+		c.block(s.Else, nil)
+	case *ast.NumberForStmt:
+		c.exprs([]ast.Expr{s.Init, s.Limit, s.Step})
+		c.block(s.Stmts, []string{s.Name})
+	case *ast.GenericForStmt:
+		c.exprs(s.Exprs)
+		c.block(s.Stmts, s.Names)
+	case *ast.FuncDefStmt:
+		c.expr(s.Name.Func)
+		c.expr(s.Name.Receiver)
+		c.expr(s.Func)
+	case *ast.ReturnStmt:
+		c.exprs(s.Exprs)
+	}
+}
+
+func (c *luaChecker) exprs(exprs []ast.Expr) {
+	for _, e := range exprs {
+		c.expr(e)
+	}
+}
+
+func (c *luaChecker) expr(expr ast.Expr) {
+	switch e := expr.(type) {
+	case *ast.IdentExpr:
+		if c.isLocal(e.Value) {
+			return
+		}
+		if luaDeniedGlobals[e.Value] {
+			c.fail(e.Line(), "uses potentially dangerous function %s", e.Value)
+		} else if luaRestrictedNames[e.Value] {
+			c.fail(e.Line(), "uses %s indirectly", e.Value)
+		}
+	case *ast.AttrGetExpr:
+		if name := c.qualifiedName(e); name != "" {
+			c.member(e.Line(), name)
+			return
+		}
+		c.expr(e.Object)
+		c.expr(e.Key)
+	case *ast.FuncCallExpr:
+		c.expr(e.Func)
+		c.expr(e.Receiver)
+		c.exprs(e.Args)
+	case *ast.TableExpr:
+		for _, field := range e.Fields {
+			c.expr(field.Key)
+			c.expr(field.Value)
+		}
+	case *ast.LogicalOpExpr:
+		c.exprs([]ast.Expr{e.Lhs, e.Rhs})
+	case *ast.RelationalOpExpr:
+		c.exprs([]ast.Expr{e.Lhs, e.Rhs})
+	case *ast.StringConcatOpExpr:
+		c.exprs([]ast.Expr{e.Lhs, e.Rhs})
+	case *ast.ArithmeticOpExpr:
+		c.exprs([]ast.Expr{e.Lhs, e.Rhs})
+	case *ast.UnaryMinusOpExpr:
+		c.expr(e.Expr)
+	case *ast.UnaryNotOpExpr:
+		c.expr(e.Expr)
+	case *ast.UnaryLenOpExpr:
+		c.expr(e.Expr)
+	case *ast.FunctionExpr:
+		var params []string
+		if e.ParList != nil {
+			params = e.ParList.Names
+		}
+		c.block(e.Stmts, params)
+	}
+}
+
+// member checks a constant field access on a global, such as os.execute or
+// _G["os"].execute, given its dotted name.
+func (c *luaChecker) member(line int, name string) {
+	// _G.x is the global x
+	for strings.HasPrefix(name, "_G.") {
+		name = strings.TrimPrefix(name, "_G.")
+	}
+
+	parts := strings.SplitN(name, ".", 3)
+	switch {
+	case len(parts) == 1:
+		if luaDeniedGlobals[name] {
+			c.fail(line, "uses potentially dangerous function %s", name)
+		} else if luaRestrictedNames[name] {
+			c.fail(line, "uses %s indirectly", name)
+		}
+	case luaDeniedLibraries[parts[0]]:
+		c.fail(line, "uses potentially dangerous library %s", parts[0])
+	case luaDeniedMembers[parts[0]+"."+parts[1]]:
+		c.fail(line, "uses potentially dangerous function %s.%s", parts[0], parts[1])
+	}
+}
+
+// qualifiedName returns the dotted name of a chain of constant field
+// accesses on a global, such as "os.execute" for os["execute"], or "" if
+// the expression isn't one.
+func (c *luaChecker) qualifiedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.IdentExpr:
+		if c.isLocal(e.Value) {
+			return ""
+		}
+		return e.Value
+	case *ast.AttrGetExpr:
+		key, ok := e.Key.(*ast.StringExpr)
+		if !ok {
+			return ""
+		}
+		if base := c.qualifiedName(e.Object); base != "" {
+			return base + "." + key.Value
+		}
+	}
+	return ""
+}
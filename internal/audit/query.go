@@ -0,0 +1,64 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"strings"
+	"time"
+)
+
+// Query selects audit events. Zero fields match every event.
+type Query struct {
+	Since     time.Time
+	Until     time.Time
+	Category  Category
+	Operation string
+	Namespace string
+	RequestID string
+	Success   *bool
+	Limit     int // most recent matching events to return; 0 returns all
+}
+
+// Match reports whether event satisfies the query.
+func (q Query) Match(event Event) bool {
+	if !q.Since.IsZero() && event.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && event.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.Category != "" && !strings.EqualFold(string(event.Category), string(q.Category)) {
+		return false
+	}
+	if q.Operation != "" && event.Operation != q.Operation {
+		return false
+	}
+	if q.Namespace != "" && event.Namespace != q.Namespace {
+		return false
+	}
+	if q.RequestID != "" && event.RequestID != q.RequestID {
+		return false
+	}
+	if q.Success != nil && event.Success != *q.Success {
+		return false
+	}
+	return true
+}
+
+// Query returns the buffered events matching q, newest first.
+func (l *Logger) Query(q Query) []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := []Event{}
+	for i := len(l.buffer) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(events) >= q.Limit {
+			break
+		}
+		if q.Match(l.buffer[i]) {
+			events = append(events, l.buffer[i])
+		}
+	}
+	return events
+}
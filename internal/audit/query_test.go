@@ -0,0 +1,73 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestQueryMatch(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	event := Event{
+		Timestamp: now,
+		Category:  CategoryWrite,
+		Operation: "put_record",
+		Namespace: "test",
+		Success:   true,
+	}
+	succeeded, failed := true, false
+
+	tests := []struct {
+		name  string
+		query Query
+		want  bool
+	}{
+		{"empty", Query{}, true},
+		{"in range", Query{Since: now.Add(-time.Minute), Until: now.Add(time.Minute)}, true},
+		{"before range", Query{Since: now.Add(time.Minute)}, false},
+		{"after range", Query{Until: now.Add(-time.Minute)}, false},
+		{"category", Query{Category: "write"}, true},
+		{"other category", Query{Category: CategoryRead}, false},
+		{"operation", Query{Operation: "put_record"}, true},
+		{"other operation", Query{Operation: "delete_record"}, false},
+		{"other namespace", Query{Namespace: "prod"}, false},
+		{"success", Query{Success: &succeeded}, true},
+		{"failure", Query{Success: &failed}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Match(event); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoggerQuery(t *testing.T) {
+	logger, _ := NewLogger(Config{Enabled: true, BufferSize: 10})
+	logger.SetOutput(io.Discard)
+
+	for i, op := range []string{"get_record", "put_record", "get_record", "put_record"} {
+		logger.Log(Event{Level: LevelInfo, Category: CategoryRead, Operation: op, Key: strconv.Itoa(i)})
+	}
+
+	events := logger.Query(Query{Operation: "put_record"})
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Key != "3" || events[1].Key != "1" {
+		t.Errorf("Expected newest events first, got keys %s, %s", events[0].Key, events[1].Key)
+	}
+
+	if events := logger.Query(Query{Limit: 3}); len(events) != 3 {
+		t.Errorf("Expected limit of 3 events, got %d", len(events))
+	}
+	if events := logger.Query(Query{Operation: "truncate_set"}); events == nil || len(events) != 0 {
+		t.Errorf("Expected an empty result, got %v", events)
+	}
+}
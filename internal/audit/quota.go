@@ -0,0 +1,127 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// QuotaExceededError is returned when a write would take a client over its
+// write quota.
+type QuotaExceededError struct {
+	Window    string // "hour" or "day"
+	Limit     int
+	Used      int
+	Requested int
+	ResetAt   time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("write quota exceeded: %d of %d records modified this %s, %d more requested; resets at %s",
+		e.Used, e.Limit, e.Window, e.Requested, e.ResetAt.Format(time.RFC3339))
+}
+
+// WriteQuota caps the number of records each client may modify or delete
+// per clock hour and per UTC day. It limits the damage a misbehaving agent
+// can do, where the rate limiter only limits how fast it can do it.
+type WriteQuota struct {
+	perHour int // 0 is unlimited
+	perDay  int // 0 is unlimited
+	now     func() time.Time
+
+	mu    sync.Mutex
+	usage map[string]*quotaUsage
+	swept time.Time // day window usage was last swept
+}
+
+// quotaUsage counts a client's modified records in the current windows.
+type quotaUsage struct {
+	hour      time.Time // start of the current hour window
+	day       time.Time // start of the current day window
+	hourCount int
+	dayCount  int
+}
+
+// NewWriteQuota creates a write quota. It returns nil if both limits are
+// zero; a nil quota allows everything.
+func NewWriteQuota(perHour, perDay int) *WriteQuota {
+	if perHour <= 0 && perDay <= 0 {
+		return nil
+	}
+	return &WriteQuota{
+		perHour: perHour,
+		perDay:  perDay,
+		now:     time.Now,
+		usage:   make(map[string]*quotaUsage),
+	}
+}
+
+// Reserve counts n modified records against client's quota. If that would
+// exceed the quota nothing is counted and a *QuotaExceededError is
+// returned.
+func (q *WriteQuota) Reserve(client string, n int) error {
+	if q == nil || n <= 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now().UTC()
+	hour := now.Truncate(time.Hour)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Forget clients that haven't written since a previous day
+	if !q.swept.Equal(day) {
+		for c, u := range q.usage {
+			if u.day.Before(day) {
+				delete(q.usage, c)
+			}
+		}
+		q.swept = day
+	}
+
+	u, ok := q.usage[client]
+	if !ok {
+		u = &quotaUsage{}
+		q.usage[client] = u
+	}
+	if !u.hour.Equal(hour) {
+		u.hour, u.hourCount = hour, 0
+	}
+	if !u.day.Equal(day) {
+		u.day, u.dayCount = day, 0
+	}
+
+	if q.perDay > 0 && u.dayCount+n > q.perDay {
+		return &QuotaExceededError{Window: "day", Limit: q.perDay, Used: u.dayCount, Requested: n, ResetAt: day.AddDate(0, 0, 1)}
+	}
+	if q.perHour > 0 && u.hourCount+n > q.perHour {
+		return &QuotaExceededError{Window: "hour", Limit: q.perHour, Used: u.hourCount, Requested: n, ResetAt: hour.Add(time.Hour)}
+	}
+
+	u.hourCount += n
+	u.dayCount += n
+	return nil
+}
+
+// Release returns n records reserved for client in the current windows,
+// for a write that failed without modifying anything.
+func (q *WriteQuota) Release(client string, n int) {
+	if q == nil || n <= 0 {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	u, ok := q.usage[client]
+	if !ok {
+		return
+	}
+	u.hourCount = max(u.hourCount-n, 0)
+	u.dayCount = max(u.dayCount-n, 0)
+}
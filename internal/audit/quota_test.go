@@ -0,0 +1,73 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriteQuota(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+	q := NewWriteQuota(5, 8)
+	q.now = func() time.Time { return now }
+
+	if err := q.Reserve("alice", 4); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	var quotaErr *QuotaExceededError
+	err := q.Reserve("alice", 2)
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("Expected QuotaExceededError, got %v", err)
+	}
+	if quotaErr.Window != "hour" || quotaErr.Used != 4 || quotaErr.Limit != 5 {
+		t.Errorf("Expected hour quota with 4 of 5 used, got %+v", quotaErr)
+	}
+	if !quotaErr.ResetAt.Equal(time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected reset at 11:00, got %v", quotaErr.ResetAt)
+	}
+
+	// Other clients have their own quota
+	if err := q.Reserve("bob", 5); err != nil {
+		t.Errorf("Expected separate quota for bob, got %v", err)
+	}
+
+	// The hour window resets, the day window doesn't
+	now = now.Add(time.Hour)
+	if err := q.Reserve("alice", 4); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	err = q.Reserve("alice", 1)
+	if !errors.As(err, &quotaErr) || quotaErr.Window != "day" {
+		t.Fatalf("Expected day quota exceeded, got %v", err)
+	}
+
+	// Released records can be used again
+	q.Release("alice", 2)
+	if err := q.Reserve("alice", 1); err != nil {
+		t.Errorf("Expected released records to be available, got %v", err)
+	}
+
+	// A new day resets both windows
+	now = now.Add(24 * time.Hour)
+	if err := q.Reserve("alice", 5); err != nil {
+		t.Errorf("Expected quota to reset on a new day, got %v", err)
+	}
+	if _, ok := q.usage["bob"]; ok {
+		t.Error("Expected usage from a previous day to be swept")
+	}
+}
+
+func TestWriteQuotaDisabled(t *testing.T) {
+	q := NewWriteQuota(0, 0)
+	if q != nil {
+		t.Fatal("Expected nil quota when unlimited")
+	}
+	if err := q.Reserve("alice", 1000); err != nil {
+		t.Errorf("Expected nil quota to allow writes, got %v", err)
+	}
+	q.Release("alice", 1)
+}
@@ -5,6 +5,7 @@ package audit
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"time"
 )
@@ -95,6 +96,27 @@ func (r *RateLimiter) AllowN(n int) bool {
 	return false
 }
 
+// Take checks if a request is allowed and, if not, returns how long until
+// it would be.
+func (r *RateLimiter) Take() (bool, time.Duration) {
+	if !r.enabled {
+		return true, 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refill()
+
+	if r.tokens >= 1 {
+		r.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - r.tokens) / r.refillRate * float64(time.Second))
+	return false, wait
+}
+
 // Wait blocks until a request is allowed or returns error if rate limited.
 func (r *RateLimiter) Wait() error {
 	if !r.enabled {
@@ -137,3 +159,130 @@ func (r *RateLimiter) GetStats() map[string]interface{} {
 		"refill_rate":      r.refillRate,
 	}
 }
+
+// ClientRateLimiter gives each client a separate token bucket per request
+// category. Categories without a configured limit are not rate-limited.
+type ClientRateLimiter struct {
+	mu        sync.Mutex
+	enabled   bool
+	limits    map[Category]RateLimitConfig
+	buckets   map[clientBucket]*RateLimiter
+	lastSweep time.Time
+}
+
+type clientBucket struct {
+	client   string
+	category Category
+}
+
+// clientSweepInterval is how often buckets that have refilled completely,
+// and so are indistinguishable from new ones, are discarded.
+const clientSweepInterval = time.Minute
+
+// NewClientRateLimiter creates a rate limiter with the given per-client
+// limits for each category.
+func NewClientRateLimiter(enabled bool, limits map[Category]RateLimitConfig) *ClientRateLimiter {
+	return &ClientRateLimiter{
+		enabled:   enabled,
+		limits:    normalizeLimits(limits),
+		buckets:   make(map[clientBucket]*RateLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// SetLimits replaces the limits, for example after the configuration is
+// reloaded. Every client starts again with a full bucket.
+func (c *ClientRateLimiter) SetLimits(enabled bool, limits map[Category]RateLimitConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = enabled
+	c.limits = normalizeLimits(limits)
+	c.buckets = make(map[clientBucket]*RateLimiter)
+}
+
+// normalizeLimits drops categories without a rate and fills in default
+// burst sizes.
+func normalizeLimits(limits map[Category]RateLimitConfig) map[Category]RateLimitConfig {
+	normalized := make(map[Category]RateLimitConfig, len(limits))
+	for category, limit := range limits {
+		if limit.RequestsPerSec <= 0 {
+			continue
+		}
+		if limit.BurstSize <= 0 {
+			limit.BurstSize = int(math.Ceil(limit.RequestsPerSec))
+		}
+		limit.Enabled = true
+		normalized[category] = limit
+	}
+	return normalized
+}
+
+// Allow checks if a client may make a request in the given category and, if
+// not, returns how long until it may.
+func (c *ClientRateLimiter) Allow(client string, category Category) (bool, time.Duration) {
+	c.mu.Lock()
+	limit, ok := c.limits[category]
+	if !c.enabled || !ok {
+		c.mu.Unlock()
+		return true, 0
+	}
+	if time.Since(c.lastSweep) > clientSweepInterval {
+		c.sweep()
+	}
+	key := clientBucket{client: client, category: category}
+	bucket, ok := c.buckets[key]
+	if !ok {
+		bucket = NewRateLimiter(limit)
+		c.buckets[key] = bucket
+	}
+	c.mu.Unlock()
+
+	return bucket.Take()
+}
+
+// sweep discards full buckets. The caller must hold c.mu.
+func (c *ClientRateLimiter) sweep() {
+	for key, bucket := range c.buckets {
+		bucket.mu.Lock()
+		bucket.refill()
+		full := bucket.tokens >= bucket.maxTokens
+		bucket.mu.Unlock()
+		if full {
+			delete(c.buckets, key)
+		}
+	}
+	c.lastSweep = time.Now()
+}
+
+// Stats returns the configured limits, the number of clients being tracked,
+// and how many of their buckets are empty.
+func (c *ClientRateLimiter) Stats() map[string]interface{} {
+	c.mu.Lock()
+	limits := make(map[string]interface{}, len(c.limits))
+	for category, limit := range c.limits {
+		limits[string(category)] = map[string]interface{}{
+			"requests_per_sec": limit.RequestsPerSec,
+			"burst":            limit.BurstSize,
+		}
+	}
+	clients := make(map[string]bool)
+	throttled := 0
+	for key, bucket := range c.buckets {
+		clients[key.client] = true
+		bucket.mu.Lock()
+		bucket.refill()
+		if bucket.tokens < 1 {
+			throttled++
+		}
+		bucket.mu.Unlock()
+	}
+	enabled := c.enabled
+	c.mu.Unlock()
+
+	return map[string]interface{}{
+		"enabled":           enabled,
+		"limits":            limits,
+		"tracked_clients":   len(clients),
+		"throttled_buckets": throttled,
+	}
+}
@@ -133,3 +133,133 @@ func TestRateLimiterGetStats(t *testing.T) {
 		t.Errorf("Expected refill_rate 100, got %v", stats["refill_rate"])
 	}
 }
+
+func TestRateLimiterTake(t *testing.T) {
+	rl := NewRateLimiter(RateLimitConfig{
+		Enabled:        true,
+		RequestsPerSec: 2,
+		BurstSize:      1,
+	})
+
+	if ok, _ := rl.Take(); !ok {
+		t.Fatal("First request should be allowed")
+	}
+
+	ok, retryAfter := rl.Take()
+	if ok {
+		t.Fatal("Second request should be denied")
+	}
+	if retryAfter <= 0 || retryAfter > 500*time.Millisecond {
+		t.Errorf("Expected retry after at most 500ms, got %v", retryAfter)
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	rl := NewClientRateLimiter(true, map[Category]RateLimitConfig{
+		CategoryWrite: {RequestsPerSec: 1, BurstSize: 2},
+		CategoryAdmin: {RequestsPerSec: 0.5},
+	})
+
+	// Each client has its own write budget
+	for _, client := range []string{"alice", "bob"} {
+		for i := 0; i < 2; i++ {
+			if ok, _ := rl.Allow(client, CategoryWrite); !ok {
+				t.Errorf("Write %d for %s should be allowed", i+1, client)
+			}
+		}
+		if ok, _ := rl.Allow(client, CategoryWrite); ok {
+			t.Errorf("Third write for %s should be denied", client)
+		}
+	}
+
+	// Categories have separate budgets; reads are unlimited
+	if ok, _ := rl.Allow("alice", CategoryAdmin); !ok {
+		t.Error("Admin request should be allowed after writes are exhausted")
+	}
+	if ok, _ := rl.Allow("alice", CategoryAdmin); ok {
+		t.Error("Admin burst should default to one second of requests")
+	}
+	for i := 0; i < 100; i++ {
+		if ok, _ := rl.Allow("alice", CategoryRead); !ok {
+			t.Fatal("Reads should not be limited")
+		}
+	}
+}
+
+func TestClientRateLimiterSetLimits(t *testing.T) {
+	rl := NewClientRateLimiter(true, map[Category]RateLimitConfig{
+		CategoryWrite: {RequestsPerSec: 1, BurstSize: 1},
+	})
+	rl.Allow("alice", CategoryWrite)
+	if ok, _ := rl.Allow("alice", CategoryWrite); ok {
+		t.Fatal("Second write should be denied")
+	}
+
+	rl.SetLimits(true, map[Category]RateLimitConfig{
+		CategoryWrite: {RequestsPerSec: 1, BurstSize: 3},
+		CategoryRead:  {RequestsPerSec: 1, BurstSize: 1},
+	})
+	for i := 0; i < 3; i++ {
+		if ok, _ := rl.Allow("alice", CategoryWrite); !ok {
+			t.Errorf("Write %d should be allowed under the new burst", i+1)
+		}
+	}
+	rl.Allow("alice", CategoryRead)
+	if ok, _ := rl.Allow("alice", CategoryRead); ok {
+		t.Error("Reads should be limited after the new limits apply")
+	}
+
+	rl.SetLimits(false, nil)
+	if ok, _ := rl.Allow("alice", CategoryWrite); !ok {
+		t.Error("Disabling the rate limiter should allow all requests")
+	}
+}
+
+func TestClientRateLimiterDisabled(t *testing.T) {
+	rl := NewClientRateLimiter(false, map[Category]RateLimitConfig{
+		CategoryWrite: {RequestsPerSec: 1, BurstSize: 1},
+	})
+
+	for i := 0; i < 10; i++ {
+		if ok, _ := rl.Allow("alice", CategoryWrite); !ok {
+			t.Error("Disabled rate limiter should allow all requests")
+		}
+	}
+}
+
+func TestClientRateLimiterSweep(t *testing.T) {
+	rl := NewClientRateLimiter(true, map[Category]RateLimitConfig{
+		CategoryWrite: {RequestsPerSec: 1000, BurstSize: 1},
+	})
+
+	rl.Allow("alice", CategoryWrite)
+	time.Sleep(5 * time.Millisecond)
+
+	rl.mu.Lock()
+	rl.sweep()
+	remaining := len(rl.buckets)
+	rl.mu.Unlock()
+
+	if remaining != 0 {
+		t.Errorf("Expected refilled buckets to be discarded, got %d", remaining)
+	}
+}
+
+func TestClientRateLimiterStats(t *testing.T) {
+	rl := NewClientRateLimiter(true, map[Category]RateLimitConfig{
+		CategoryWrite: {RequestsPerSec: 1, BurstSize: 1},
+	})
+	rl.Allow("alice", CategoryWrite) // uses alice's only token
+	rl.Allow("bob", CategoryRead)    // unlimited, so not tracked
+
+	stats := rl.Stats()
+	if stats["tracked_clients"] != 1 {
+		t.Errorf("Expected 1 tracked client, got %v", stats["tracked_clients"])
+	}
+	if stats["throttled_buckets"] != 1 {
+		t.Errorf("Expected 1 throttled bucket, got %v", stats["throttled_buckets"])
+	}
+	if _, ok := stats["limits"].(map[string]interface{})["WRITE"]; !ok {
+		t.Errorf("Expected the WRITE limit, got %v", stats["limits"])
+	}
+}
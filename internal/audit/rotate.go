@@ -0,0 +1,313 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp appended to rotated audit files. It
+// sorts in time order.
+const backupTimeFormat = "20060102T150405.000"
+
+// RotationConfig controls rotation of the audit log file. Zero values
+// disable each limit.
+type RotationConfig struct {
+	MaxSizeMB           int  `json:"max_size_mb,omitempty"`           // rotate when the file would exceed this size
+	RotateIntervalHours int  `json:"rotate_interval_hours,omitempty"` // rotate when the file's first event is older than this
+	Compress            bool `json:"compress,omitempty"`              // gzip rotated files
+	MaxFiles            int  `json:"max_files,omitempty"`             // rotated files to keep
+	MaxAgeDays          int  `json:"max_age_days,omitempty"`          // delete rotated files older than this
+}
+
+// Enabled reports whether the file is ever rotated.
+func (c RotationConfig) Enabled() bool {
+	return c.MaxSizeMB > 0 || c.RotateIntervalHours > 0
+}
+
+// rotatingFile is an audit log file that is rotated when it grows too large
+// or too old. Rotated files are renamed with a timestamp suffix, then
+// compressed and pruned in the background. Writes are serialized by the
+// Logger.
+type rotatingFile struct {
+	path     string
+	maxSize  int64
+	interval time.Duration
+	compress bool
+	maxFiles int
+	maxAge   time.Duration
+	now      func() time.Time
+
+	file    *os.File
+	size    int64
+	created time.Time // timestamp of the file's first event
+
+	cleanupMu sync.Mutex // serializes compression and pruning
+	wg        sync.WaitGroup
+}
+
+// openRotatingFile opens path for appending and tidies up any rotated files
+// left by a previous run.
+func openRotatingFile(path string, cfg RotationConfig) (*rotatingFile, error) {
+	r := &rotatingFile{
+		path:     path,
+		maxSize:  int64(cfg.MaxSizeMB) * 1024 * 1024,
+		interval: time.Duration(cfg.RotateIntervalHours) * time.Hour,
+		compress: cfg.Compress,
+		maxFiles: cfg.MaxFiles,
+		maxAge:   time.Duration(cfg.MaxAgeDays) * 24 * time.Hour,
+		now:      time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	r.cleanup()
+	return r, nil
+}
+
+// open opens the current file, noting its size and age.
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("opening audit log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("opening audit log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.created = r.now()
+	if r.size > 0 {
+		r.created = firstEventTime(file, info.ModTime())
+	}
+	return nil
+}
+
+// firstEventTime returns the timestamp of the first event in file, or
+// fallback if it can't be read.
+func firstEventTime(file *os.File, fallback time.Time) time.Time {
+	line, err := bufio.NewReader(io.NewSectionReader(file, 0, 64*1024)).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return fallback
+	}
+	var event struct {
+		Timestamp time.Time `json:"timestamp"`
+	}
+	if json.Unmarshal(line, &event) != nil || event.Timestamp.IsZero() {
+		return fallback
+	}
+	return event.Timestamp
+}
+
+// Write appends p to the file, rotating it first if p would take it over
+// the size limit or the file has reached the rotation interval.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.rotationDue(len(p)) {
+		if err := r.rotate(); err != nil {
+			slog.Error("Audit log rotation failed", "error", err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotationDue(n int) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.maxSize > 0 && r.size+int64(n) > r.maxSize {
+		return true
+	}
+	return r.interval > 0 && r.now().Sub(r.created) >= r.interval
+}
+
+// rotate renames the current file and starts a new one.
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	backup := r.path + "." + r.now().UTC().Format(backupTimeFormat)
+	renameErr := os.Rename(r.path, backup)
+
+	// Keep logging even if the rename failed
+	if err := r.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+
+	r.cleanup()
+	return nil
+}
+
+// cleanup compresses and prunes rotated files in the background.
+func (r *rotatingFile) cleanup() {
+	now := r.now()
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.cleanupMu.Lock()
+		defer r.cleanupMu.Unlock()
+
+		if r.compress {
+			r.compressBackups()
+		}
+		r.prune(now)
+	}()
+}
+
+func (r *rotatingFile) compressBackups() {
+	backups, err := listBackups(r.path)
+	if err != nil {
+		slog.Error("Listing rotated audit logs failed", "error", err)
+		return
+	}
+	for _, b := range backups {
+		if strings.HasSuffix(b.path, ".gz") {
+			continue
+		}
+		if err := compressFile(b.path); err != nil {
+			slog.Error("Compressing rotated audit log failed", "file", b.path, "error", err)
+		}
+	}
+}
+
+// prune deletes rotated files beyond the retention limits.
+func (r *rotatingFile) prune(now time.Time) {
+	if r.maxFiles <= 0 && r.maxAge <= 0 {
+		return
+	}
+	backups, err := listBackups(r.path)
+	if err != nil {
+		slog.Error("Listing rotated audit logs failed", "error", err)
+		return
+	}
+
+	cutoff := now.Add(-r.maxAge)
+	for i, b := range backups {
+		keep := len(backups) - i // this file and the newer ones
+		if (r.maxFiles > 0 && keep > r.maxFiles) || (r.maxAge > 0 && b.rotated.Before(cutoff)) {
+			if err := os.Remove(b.path); err != nil {
+				slog.Error("Deleting rotated audit log failed", "file", b.path, "error", err)
+			}
+		}
+	}
+}
+
+// Close closes the file and waits for background cleanup to finish.
+func (r *rotatingFile) Close() error {
+	err := r.file.Close()
+	r.wg.Wait()
+	return err
+}
+
+// compressFile gzips path to path.gz and removes the original.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// backup is a rotated audit log file.
+type backup struct {
+	path    string
+	rotated time.Time
+}
+
+// listBackups returns the rotated files of the audit log at path, oldest
+// first.
+func listBackups(path string) ([]backup, error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), base+".")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		rotated, err := time.Parse(backupTimeFormat, strings.TrimSuffix(suffix, ".gz"))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, entry.Name()), rotated: rotated})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].rotated.Before(backups[j].rotated) })
+	return backups, nil
+}
+
+// OpenLog opens an audit log file for reading, decompressing it if it is a
+// gzipped rotated file.
+func OpenLog(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return gzipFile{zr, file}, nil
+}
+
+// gzipFile closes both the gzip reader and the file beneath it.
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestRotatingFile opens a rotating file whose clock advances a second
+// on every reading.
+func newTestRotatingFile(t *testing.T, cfg RotationConfig) (*rotatingFile, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	r, err := openRotatingFile(path, cfg)
+	if err != nil {
+		t.Fatalf("openRotatingFile() error = %v", err)
+	}
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return r, path
+}
+
+func TestRotateBySize(t *testing.T) {
+	r, path := newTestRotatingFile(t, RotationConfig{MaxSizeMB: 1, Compress: true, MaxFiles: 2})
+	r.maxSize = 100
+
+	line := strings.Repeat("x", 59) + "\n"
+	for i := 0; i < 5; i++ {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, _ := listBackups(path)
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 rotated files to be kept, got %d", len(backups))
+	}
+	for _, b := range backups {
+		if !strings.HasSuffix(b.path, ".gz") {
+			t.Errorf("Expected %s to be compressed", b.path)
+		}
+		file, err := OpenLog(b.path)
+		if err != nil {
+			t.Fatalf("OpenLog() error = %v", err)
+		}
+		data, _ := io.ReadAll(file)
+		file.Close()
+		if string(data) != line {
+			t.Errorf("Expected rotated file to hold one line, got %q", data)
+		}
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != line {
+		t.Errorf("Expected current file to hold the last line, got %q", data)
+	}
+}
+
+func TestRotateByInterval(t *testing.T) {
+	r, path := newTestRotatingFile(t, RotationConfig{RotateIntervalHours: 1})
+
+	_, _ = r.Write([]byte("first\n"))
+	_, _ = r.Write([]byte("second\n"))
+	r.created = r.now().Add(-time.Hour)
+	_, _ = r.Write([]byte("third\n"))
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, _ := listBackups(path)
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 rotated file, got %d", len(backups))
+	}
+	if data, _ := os.ReadFile(backups[0].path); string(data) != "first\nsecond\n" {
+		t.Errorf("Unexpected rotated file contents %q", data)
+	}
+}
+
+func TestPruneByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	now := time.Now().UTC()
+	old := path + "." + now.Add(-48*time.Hour).Format(backupTimeFormat)
+	recent := path + "." + now.Add(-time.Hour).Format(backupTimeFormat)
+	for _, p := range []string{old, recent, path + ".unrelated"} {
+		if err := os.WriteFile(p, []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := openRotatingFile(path, RotationConfig{MaxSizeMB: 1, MaxAgeDays: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Close()
+
+	for p, want := range map[string]bool{old: false, recent: true, path + ".unrelated": true} {
+		if _, err := os.Stat(p); (err == nil) != want {
+			t.Errorf("Expected %s to exist: %v", filepath.Base(p), want)
+		}
+	}
+}
+
+func TestChainContinuesAfterRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger := newChainLogger(t, path, "", 0)
+	logger.Log(Event{Level: LevelInfo, Category: CategoryRead, Operation: "get_record", Success: true})
+	logger.Log(Event{Level: LevelInfo, Category: CategoryRead, Operation: "get_record", Success: true})
+	_ = logger.Close()
+
+	// Rotate and compress the log while the server is down
+	backup := path + "." + time.Now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(path, backup); err != nil {
+		t.Fatal(err)
+	}
+	if err := compressFile(backup); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := newChain(path, nil, 0)
+	if err != nil {
+		t.Fatalf("newChain() error = %v", err)
+	}
+	if c.seq != 2 || c.prevHash == "" {
+		t.Errorf("Expected chain to continue from sequence 2, got %d", c.seq)
+	}
+}
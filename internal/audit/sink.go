@@ -0,0 +1,121 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// sinkQueueSize is the number of events a sink may fall behind by before
+// events are dropped.
+const sinkQueueSize = 1000
+
+// Sink receives a copy of every audit event written to the log, for
+// example to ship events to another store.
+type Sink interface {
+	WriteEvent(ctx context.Context, event Event) error
+	Close() error
+}
+
+// queuedSink delivers events to a Sink from a background goroutine, so a
+// slow or unavailable backend never blocks the request being audited.
+// Events are dropped while the queue is full.
+type queuedSink struct {
+	name   string
+	sink   Sink
+	events chan Event
+	done   chan struct{}
+
+	mu       sync.Mutex
+	dropped  int
+	failures int
+}
+
+func newQueuedSink(name string, sink Sink) *queuedSink {
+	q := &queuedSink{
+		name:   name,
+		sink:   sink,
+		events: make(chan Event, sinkQueueSize),
+		done:   make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// send queues an event without blocking.
+func (q *queuedSink) send(event Event) {
+	select {
+	case q.events <- event:
+	default:
+		q.mu.Lock()
+		q.dropped++
+		if q.dropped == 1 {
+			slog.Warn("Audit sink queue full, dropping events", "sink", q.name)
+		}
+		q.mu.Unlock()
+	}
+}
+
+func (q *queuedSink) run() {
+	defer close(q.done)
+	for event := range q.events {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := q.sink.WriteEvent(ctx, event)
+		cancel()
+		q.report(err)
+	}
+}
+
+// report logs the first failure of a run of failures, and the recovery.
+func (q *queuedSink) report(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	switch {
+	case err != nil:
+		q.failures++
+		if q.failures == 1 {
+			slog.Error("Audit sink write failed", "sink", q.name, "error", err)
+		}
+	case q.failures > 0 || q.dropped > 0:
+		slog.Info("Audit sink recovered", "sink", q.name, "failed", q.failures, "dropped", q.dropped)
+		q.failures, q.dropped = 0, 0
+	}
+}
+
+// close delivers the queued events and closes the sink.
+func (q *queuedSink) close() error {
+	close(q.events)
+	<-q.done
+	return q.sink.Close()
+}
+
+// AddSink sends every subsequent event to sink as well as the log. name
+// identifies the sink in server logs.
+func (l *Logger) AddSink(name string, sink Sink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sinks = append(l.sinks, newQueuedSink(name, sink))
+}
+
+// FailingSinks returns the names of the sinks whose most recent write
+// failed.
+func (l *Logger) FailingSinks() []string {
+	l.mu.Lock()
+	sinks := l.sinks
+	l.mu.Unlock()
+
+	var failing []string
+	for _, q := range sinks {
+		q.mu.Lock()
+		if q.failures > 0 {
+			failing = append(failing, q.name)
+		}
+		q.mu.Unlock()
+	}
+	return failing
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink collects the events it receives, optionally waiting for
+// release before each one.
+type recordingSink struct {
+	mu      sync.Mutex
+	events  []Event
+	err     error
+	release chan struct{}
+	closed  bool
+}
+
+func (s *recordingSink) WriteEvent(_ context.Context, event Event) error {
+	if s.release != nil {
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestSink(t *testing.T) {
+	path := t.TempDir() + "/audit.log"
+	logger := newChainLogger(t, path, "", 0)
+	sink := &recordingSink{}
+	logger.AddSink("test", sink)
+
+	logger.Log(Event{Level: LevelAudit, Category: CategoryWrite, Operation: "put_record", Success: true})
+	logger.Log(Event{Level: LevelAudit, Category: CategoryWrite, Operation: "delete_record", Success: true})
+	if err := logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !sink.closed {
+		t.Error("Expected Close to close the sink")
+	}
+	if len(sink.events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(sink.events))
+	}
+	if sink.events[1].Operation != "delete_record" || sink.events[1].Seq != 2 || sink.events[1].Hash == "" {
+		t.Errorf("Expected chained events in order, got %+v", sink.events[1])
+	}
+}
+
+func TestSinkDoesNotBlock(t *testing.T) {
+	logger, _ := NewLogger(Config{Enabled: true})
+	logger.SetOutput(io.Discard)
+	sink := &recordingSink{release: make(chan struct{}), err: errors.New("unavailable")}
+	logger.AddSink("slow", sink)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < sinkQueueSize+10; i++ {
+			logger.Log(Event{Level: LevelInfo, Category: CategoryRead, Operation: "get_record"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected logging not to wait for a slow sink")
+	}
+
+	close(sink.release)
+	_ = logger.Close()
+	if n := len(sink.events); n > sinkQueueSize+1 {
+		t.Errorf("Expected overflowing events to be dropped, got %d", n)
+	}
+}
+
+func TestFailingSinks(t *testing.T) {
+	logger, _ := NewLogger(Config{Enabled: true})
+	logger.SetOutput(io.Discard)
+	logger.AddSink("ok", &recordingSink{})
+	logger.AddSink("down", &recordingSink{err: errors.New("unavailable")})
+
+	defer logger.Close()
+
+	logger.Log(Event{Level: LevelInfo, Category: CategoryRead, Operation: "get_record"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(logger.FailingSinks()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	failing := logger.FailingSinks()
+	if len(failing) != 1 || failing[0] != "down" {
+		t.Errorf("Expected [down], got %v", failing)
+	}
+}
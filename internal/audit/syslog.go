@@ -0,0 +1,204 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syslogFacilities maps facility names to RFC 5424 facility codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogConfig configures a syslog sink.
+type SyslogConfig struct {
+	Network  string // "udp", "tcp", or "tls"
+	Address  string // host:port
+	Facility string // default "local0"
+	AppName  string // default "aerospike-mcp-server"
+
+	// For "tls": the CA that signed the collector's certificate (system
+	// roots if empty), and an optional client certificate.
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// SyslogSink sends audit events to a syslog collector as RFC 5424 messages
+// whose body is the event's JSON. Over TCP and TLS, messages are framed by
+// octet counting (RFC 6587, RFC 5425).
+type SyslogSink struct {
+	network  string
+	address  string
+	facility int
+	appName  string
+	hostname string
+	tls      *tls.Config
+
+	conn net.Conn
+}
+
+// NewSyslogSink creates a syslog sink. The connection is opened on the
+// first event.
+func NewSyslogSink(cfg SyslogConfig) (*SyslogSink, error) {
+	s := &SyslogSink{
+		network: strings.ToLower(cfg.Network),
+		address: cfg.Address,
+		appName: cfg.AppName,
+	}
+	if s.appName == "" {
+		s.appName = "aerospike-mcp-server"
+	}
+
+	facility := strings.ToLower(cfg.Facility)
+	if facility == "" {
+		facility = "local0"
+	}
+	code, ok := syslogFacilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility: %s", cfg.Facility)
+	}
+	s.facility = code
+
+	switch s.network {
+	case "udp", "tcp":
+	case "tls":
+		tlsConfig, err := syslogTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		s.tls = tlsConfig
+	default:
+		return nil, fmt.Errorf("unsupported syslog network: %s (must be udp, tcp, or tls)", cfg.Network)
+	}
+
+	if s.hostname, _ = os.Hostname(); s.hostname == "" {
+		s.hostname = "-"
+	}
+	return s, nil
+}
+
+func syslogTLSConfig(cfg SyslogConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading syslog CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse syslog CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.CertFile != "" && cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading syslog client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// WriteEvent sends one event, reconnecting once if the connection was lost.
+func (s *SyslogSink) WriteEvent(ctx context.Context, event Event) error {
+	msg, err := s.format(event)
+	if err != nil {
+		return err
+	}
+	if s.network != "udp" {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err = s.send(ctx, msg); err == nil || attempt == 1 {
+			return err
+		}
+	}
+}
+
+func (s *SyslogSink) send(ctx context.Context, msg []byte) error {
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return fmt.Errorf("connecting to syslog: %w", err)
+		}
+		s.conn = conn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	}
+	if _, err := s.conn.Write(msg); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("writing to syslog: %w", err)
+	}
+	return nil
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	if s.tls != nil {
+		dialer := &tls.Dialer{Config: s.tls}
+		return dialer.DialContext(ctx, "tcp", s.address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, s.network, s.address)
+}
+
+// format renders an event as an RFC 5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG
+func (s *SyslogSink) format(event Event) ([]byte, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit event: %w", err)
+	}
+	msgID := string(event.Category)
+	if msgID == "" {
+		msgID = "-"
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %d %s - ",
+		s.facility*8+syslogSeverity(event.Level),
+		event.Timestamp.UTC().Format(time.RFC3339Nano),
+		s.hostname, s.appName, os.Getpid(), msgID)
+	return append([]byte(header), body...), nil
+}
+
+// syslogSeverity maps an audit level to a syslog severity.
+func syslogSeverity(level Level) int {
+	switch level {
+	case LevelError:
+		return 3
+	case LevelWarning:
+		return 4
+	case LevelAudit:
+		return 5 // notice
+	default:
+		return 6 // informational
+	}
+}
+
+// Close closes the connection.
+func (s *SyslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
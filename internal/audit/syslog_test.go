@@ -0,0 +1,145 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSinkUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "udp", Address: conn.LocalAddr().String(), Facility: "auth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := Event{Timestamp: ts, Level: LevelAudit, Category: CategoryWrite, Operation: "put_record", Success: true}
+	if err := sink.WriteEvent(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := string(buf[:n])
+
+	// auth (4) * 8 + notice (5)
+	prefix := fmt.Sprintf("<37>1 2024-05-01T12:00:00Z %s aerospike-mcp-server ", sink.hostname)
+	if !strings.HasPrefix(msg, prefix) {
+		t.Fatalf("Expected message to start with %q, got %q", prefix, msg)
+	}
+	_, body, ok := strings.Cut(msg, " WRITE - ")
+	if !ok {
+		t.Fatalf("Expected MSGID WRITE, got %q", msg)
+	}
+	var got Event
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("Expected JSON body, got %q: %v", body, err)
+	}
+	if got.Operation != "put_record" {
+		t.Errorf("Expected operation put_record, got %s", got.Operation)
+	}
+}
+
+func TestSyslogSinkTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go readOctetCounted(conn, received)
+		}
+	}()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "tcp", Address: ln.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	ctx := context.Background()
+	for _, op := range []string{"put_record", "delete_record"} {
+		if err := sink.WriteEvent(ctx, Event{Level: LevelError, Category: CategoryWrite, Operation: op}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, op := range []string{"put_record", "delete_record"} {
+		select {
+		case msg := <-received:
+			// local0 (16) * 8 + error (3)
+			if !strings.HasPrefix(msg, "<131>1 ") || !strings.Contains(msg, `"operation":"`+op+`"`) {
+				t.Errorf("Expected framed %s message, got %q", op, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %s", op)
+		}
+	}
+}
+
+// readOctetCounted reads "LEN MSG" frames from conn.
+func readOctetCounted(conn net.Conn, received chan<- string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		length, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		if err != nil {
+			return
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return
+		}
+		received <- string(msg)
+	}
+}
+
+func TestNewSyslogSinkInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SyslogConfig
+	}{
+		{"unknown network", SyslogConfig{Network: "http", Address: "localhost:514"}},
+		{"unknown facility", SyslogConfig{Network: "udp", Address: "localhost:514", Facility: "local9"}},
+		{"missing CA file", SyslogConfig{Network: "tls", Address: "localhost:6514", CAFile: "/nonexistent/ca.pem"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSyslogSink(tt.cfg); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -56,14 +56,41 @@ func NewValidator(cfg ValidatorConfig) *Validator {
 
 // ValidationError represents a validation error.
 type ValidationError struct {
-	Field   string
-	Message string
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 func (e ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Field, e.Message)
 }
 
+// ValidationErrors collects the validation errors for a request.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Add records err, if it is non-nil, under the given field name. An empty
+// field keeps the name reported by the validator.
+func (e *ValidationErrors) Add(field string, err error) {
+	if err == nil {
+		return
+	}
+	ve, ok := err.(ValidationError)
+	if !ok {
+		ve = ValidationError{Field: field, Message: err.Error()}
+	}
+	if field != "" {
+		ve.Field = field
+	}
+	*e = append(*e, ve)
+}
+
 // ValidateNamespace validates a namespace name.
 func (v *Validator) ValidateNamespace(namespace string) error {
 	if namespace == "" {
@@ -202,31 +229,15 @@ func (v *Validator) ValidateIndexName(indexName string) error {
 	return nil
 }
 
-// ValidateUDFCode validates UDF Lua code.
+// ValidateUDFCode validates UDF Lua code. The code must parse, and may not
+// use functions or libraries that reach outside the Aerospike sandbox, such
+// as os.execute, io, or loadfile.
 func (v *Validator) ValidateUDFCode(code string) error {
 	if code == "" {
 		return ValidationError{Field: "code", Message: "cannot be empty"}
 	}
 
-	// Check for potentially dangerous patterns
-	dangerousPatterns := []string{
-		"os.execute",
-		"io.popen",
-		"loadfile",
-		"dofile",
-	}
-
-	codeLower := strings.ToLower(code)
-	for _, pattern := range dangerousPatterns {
-		if strings.Contains(codeLower, pattern) {
-			return ValidationError{
-				Field:   "code",
-				Message: fmt.Sprintf("contains potentially dangerous function: %s", pattern),
-			}
-		}
-	}
-
-	return nil
+	return checkLuaCode(code)
 }
 
 // ValidateModuleName validates a UDF module name.
@@ -4,6 +4,7 @@
 package audit
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -170,6 +171,28 @@ func TestValidateUDFCode(t *testing.T) {
 		{"dangerous os.execute", "os.execute('rm -rf /')", true},
 		{"dangerous io.popen", "io.popen('ls')", true},
 		{"dangerous loadfile", "loadfile('/etc/passwd')", true},
+		{"syntax error", "function hello( return 'world' end", true},
+		{"unterminated function", "function hello() return 1", true},
+		{"pattern in comment", "-- never call os.execute here\nfunction f() return 1 end", false},
+		{"pattern in string", "function f() return 'io.popen' end", false},
+		{"allowed os member", "function f(rec) rec['t'] = os.time() return rec end", false},
+		{"indexed call", "os['execute']('ls')", true},
+		{"aliased library", "local o = os\no.execute('ls')", true},
+		{"computed key", "local k = 'exe' .. 'cute'\nos[k]('ls')", true},
+		{"via _G", "_G.os.execute('ls')", true},
+		{"computed global", "_G['o' .. 's'].execute('ls')", true},
+		{"io library", "function f() local fh = io.open('/etc/passwd') end", true},
+		{"io method call", "io.stdout:write('x')", true},
+		{"debug library", "debug.sethook()", true},
+		{"load", "local f = load('return 1')", true},
+		{"require", "require('os').execute('ls')", true},
+		{"require without parentheses", "local o = require 'os'\no.execute('ls')", true},
+		{"package.loaded", "package.loaded.os.execute('ls')", true},
+		{"package.loaded indexed", "package['loaded']['io'].popen('ls')", true},
+		{"package via _G", "_G.package.loaded.os.execute('ls')", true},
+		{"nested function", "function outer() local function inner() dofile('x') end end", true},
+		{"local shadows library", "local os = { execute = function() end }\nos.execute()", false},
+		{"parameter shadows library", "function f(io) return io.open end", false},
 	}
 
 	for _, tt := range tests {
@@ -208,6 +231,22 @@ func TestValidateModuleName(t *testing.T) {
 	}
 }
 
+func TestValidationErrors(t *testing.T) {
+	var errs ValidationErrors
+	errs.Add("", nil)
+	errs.Add("", ValidationError{Field: "namespace", Message: "cannot be empty"})
+	errs.Add("keys[0].key", ValidationError{Field: "key", Message: "cannot be empty"})
+	errs.Add("operations", errors.New("has the wrong type"))
+
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %d", len(errs))
+	}
+	expected := "namespace: cannot be empty; keys[0].key: cannot be empty; operations: has the wrong type"
+	if errs.Error() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, errs.Error())
+	}
+}
+
 func TestSanitizeString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -229,3 +268,25 @@ func TestSanitizeString(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateUDFCodeMessages(t *testing.T) {
+	v := NewValidator(DefaultValidatorConfig())
+
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"dangerous call", "function f()\n  return os.execute('ls')\nend", "line 2: uses potentially dangerous function os.execute"},
+		{"syntax error", "function f()\n  return (1\nend", "syntax error on line 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateUDFCode(tt.code)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing '%s', got %v", tt.want, err)
+			}
+		})
+	}
+}
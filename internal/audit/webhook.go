@@ -0,0 +1,186 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WebhookConfig configures a webhook sink. Zero values use the defaults.
+type WebhookConfig struct {
+	URL           string
+	Headers       map[string]string // e.g. Authorization
+	BatchSize     int               // events per request; default 100
+	FlushInterval time.Duration     // maximum time an event waits; default 1s
+	MaxRetries    int               // retries of a failed request; default 3, -1 for none
+	Timeout       time.Duration     // per request; default 5s
+}
+
+// WebhookSink POSTs audit events to an HTTP endpoint as JSON arrays. Events
+// are batched until BatchSize is reached or FlushInterval passes, and failed
+// requests are retried with exponential backoff.
+type WebhookSink struct {
+	url        string
+	headers    map[string]string
+	batchSize  int
+	maxRetries int
+	backoff    time.Duration // delay before the first retry, doubled after each
+	client     *http.Client
+
+	mu    sync.Mutex
+	batch []Event
+
+	flushMu sync.Mutex // serializes requests so batches arrive in order
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewWebhookSink creates a webhook sink and starts its flush timer.
+func NewWebhookSink(cfg WebhookConfig) (*WebhookSink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	w := &WebhookSink{
+		url:        cfg.URL,
+		headers:    cfg.Headers,
+		batchSize:  cfg.BatchSize,
+		maxRetries: cfg.MaxRetries,
+		backoff:    500 * time.Millisecond,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = 100
+	}
+	switch {
+	case w.maxRetries == 0:
+		w.maxRetries = 3
+	case w.maxRetries < 0:
+		w.maxRetries = 0 // never retry
+	}
+	if w.client.Timeout <= 0 {
+		w.client.Timeout = 5 * time.Second
+	}
+	interval := cfg.FlushInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go w.run(interval)
+	return w, nil
+}
+
+// WriteEvent adds an event to the current batch, sending the batch if it is
+// full.
+func (w *WebhookSink) WriteEvent(ctx context.Context, event Event) error {
+	w.mu.Lock()
+	w.batch = append(w.batch, event)
+	full := len(w.batch) >= w.batchSize
+	w.mu.Unlock()
+
+	if !full {
+		return nil
+	}
+	return w.flush(ctx)
+}
+
+func (w *WebhookSink) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := w.flush(ctx); err != nil {
+				slog.Error("Audit webhook delivery failed", "error", err)
+			}
+			cancel()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// flush sends the current batch. A batch that can't be delivered after all
+// retries is dropped.
+func (w *WebhookSink) flush(ctx context.Context) error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	batch := w.batch
+	w.batch = nil
+	w.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("encoding audit events: %w", err)
+	}
+
+	delay := w.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= w.maxRetries {
+			return fmt.Errorf("dropped %d audit events: %w", len(batch), err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("dropped %d audit events: %w", len(batch), ctx.Err())
+		}
+		delay *= 2
+	}
+}
+
+// post sends one request, reporting whether a failure is worth retrying:
+// connection errors, 429, and 5xx responses are.
+func (w *WebhookSink) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("posting to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// Close stops the flush timer and sends any remaining events.
+func (w *WebhookSink) Close() error {
+	close(w.stop)
+	<-w.done
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return w.flush(ctx)
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookRecorder is a webhook endpoint that fails the first failures
+// requests and records the batches it accepts.
+type webhookRecorder struct {
+	mu       sync.Mutex
+	failures int
+	requests int
+	batches  [][]Event
+	header   http.Header
+}
+
+func (h *webhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests++
+	h.header = r.Header.Clone()
+	if h.failures > 0 {
+		h.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var batch []Event
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.batches = append(h.batches, batch)
+}
+
+func TestWebhookSinkBatching(t *testing.T) {
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	sink, err := NewWebhookSink(WebhookConfig{
+		URL:           server.URL,
+		Headers:       map[string]string{"Authorization": "Bearer secret"},
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, op := range []string{"put_record", "delete_record", "get_record"} {
+		if err := sink.WriteEvent(ctx, Event{Operation: op}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.batches) != 2 {
+		t.Fatalf("Expected 2 batches, got %d", len(recorder.batches))
+	}
+	if len(recorder.batches[0]) != 2 || len(recorder.batches[1]) != 1 {
+		t.Errorf("Expected batches of 2 and 1 events, got %d and %d", len(recorder.batches[0]), len(recorder.batches[1]))
+	}
+	if recorder.batches[1][0].Operation != "get_record" {
+		t.Errorf("Expected the final batch to hold get_record, got %s", recorder.batches[1][0].Operation)
+	}
+	if got := recorder.header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Expected Authorization header, got %q", got)
+	}
+	if got := recorder.header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", got)
+	}
+}
+
+func TestWebhookSinkFlushInterval(t *testing.T) {
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	sink, err := NewWebhookSink(WebhookConfig{URL: server.URL, FlushInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	if err := sink.WriteEvent(context.Background(), Event{Operation: "put_record"}); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		recorder.mu.Lock()
+		n := len(recorder.batches)
+		recorder.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the timed flush")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebhookSinkRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		maxRetries   int
+		wantRequests int
+		wantErr      bool
+	}{
+		{"succeeds after retries", 2, 3, 3, false},
+		{"gives up", 5, 2, 3, true},
+		{"retries disabled", 1, -1, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &webhookRecorder{failures: tt.failures}
+			server := httptest.NewServer(recorder)
+			defer server.Close()
+
+			sink, err := NewWebhookSink(WebhookConfig{URL: server.URL, BatchSize: 1, FlushInterval: time.Hour, MaxRetries: tt.maxRetries})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sink.backoff = time.Millisecond
+			defer sink.Close()
+
+			err = sink.WriteEvent(context.Background(), Event{Operation: "put_record"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if recorder.requests != tt.wantRequests {
+				t.Errorf("Expected %d requests, got %d", tt.wantRequests, recorder.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package auth provides bearer token validation for the HTTP transports.
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+const (
+	// keyRefreshInterval is how long fetched signing keys are cached.
+	keyRefreshInterval = 5 * time.Minute
+
+	// minRefreshInterval rate-limits refetches triggered by unknown key IDs.
+	minRefreshInterval = 30 * time.Second
+
+	// clockSkew is the leeway applied to exp and nbf checks.
+	clockSkew = time.Minute
+)
+
+// Claims holds the validated claims of a token.
+type Claims map[string]interface{}
+
+// String returns a string claim, or an empty string if absent.
+func (c Claims) String(name string) string {
+	v, _ := c[name].(string)
+	return v
+}
+
+// OIDCVerifier validates JWT bearer tokens issued by an OIDC provider.
+type OIDCVerifier struct {
+	cfg        config.OIDCConfig
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	jwksURL     string
+	lastRefresh time.Time
+	now         func() time.Time
+}
+
+// NewOIDCVerifier creates a verifier for the configured issuer.
+func NewOIDCVerifier(cfg config.OIDCConfig) *OIDCVerifier {
+	return &OIDCVerifier{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		jwksURL:    cfg.JWKSURL,
+		now:        time.Now,
+	}
+}
+
+// Verify checks the token's signature, issuer, audience, and validity period
+// and returns its claims.
+func (v *OIDCVerifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("decoding token header: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decoding token signature: %w", err)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("decoding token claims: %w", err)
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// validateClaims checks the registered claims against the configuration.
+func (v *OIDCVerifier) validateClaims(claims Claims) error {
+	if iss := claims.String("iss"); iss != v.cfg.Issuer {
+		return fmt.Errorf("unexpected issuer: %s", iss)
+	}
+
+	if v.cfg.Audience != "" && !hasAudience(claims["aud"], v.cfg.Audience) {
+		return fmt.Errorf("token not issued for audience %s", v.cfg.Audience)
+	}
+
+	now := v.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return fmt.Errorf("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("token not yet valid")
+	}
+
+	return nil
+}
+
+// hasAudience reports whether the aud claim (string or array) contains want.
+func hasAudience(aud interface{}, want string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == want
+	case []interface{}:
+		for _, v := range a {
+			if s, ok := v.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key returns the signing key with the given ID, fetching the provider's key
+// set if it is stale or does not contain the key.
+func (v *OIDCVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	since := v.now().Sub(v.lastRefresh)
+	if key, ok := v.lookupLocked(kid); ok && since < keyRefreshInterval {
+		return key, nil
+	}
+
+	if v.keys == nil || since >= minRefreshInterval {
+		if err := v.refreshLocked(ctx); err != nil {
+			if key, ok := v.lookupLocked(kid); ok {
+				return key, nil
+			}
+			return nil, err
+		}
+	}
+
+	if key, ok := v.lookupLocked(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key: %s", kid)
+}
+
+// lookupLocked finds a key by ID. Tokens without a key ID are accepted when
+// the key set contains exactly one key.
+func (v *OIDCVerifier) lookupLocked(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// refreshLocked fetches the provider's JSON Web Key Set, discovering its URL
+// from the issuer's OpenID configuration if it was not configured.
+func (v *OIDCVerifier) refreshLocked(ctx context.Context) error {
+	v.lastRefresh = v.now()
+
+	if v.jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		discoveryURL := strings.TrimSuffix(v.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+		if err := v.getJSON(ctx, discoveryURL, &discovery); err != nil {
+			return fmt.Errorf("discovering OIDC configuration: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return fmt.Errorf("OIDC configuration has no jwks_uri")
+		}
+		v.jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := v.getJSON(ctx, v.jwksURL, &set); err != nil {
+		return fmt.Errorf("fetching JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	v.keys = keys
+
+	return nil
+}
+
+// getJSON fetches a URL and decodes the JSON response body.
+func (v *OIDCVerifier) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// jsonWebKey is a single key from a JWKS document.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey converts the JWK to an RSA or ECDSA public key.
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+// verifySignature checks a JWS signature. Only asymmetric algorithms are
+// accepted; "none" and HMAC algorithms are rejected.
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match RSA key", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(pub, hash, digest, signature); err != nil {
+			return fmt.Errorf("invalid token signature")
+		}
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("algorithm %s does not match EC key", alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("invalid token signature")
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+
+	return nil
+}
+
+// decodeSegment decodes a base64url-encoded JSON token segment.
+func decodeSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// decodeBigInt decodes a base64url-encoded big-endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
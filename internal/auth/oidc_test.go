@@ -0,0 +1,165 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15() error = %v", err)
+	}
+	return signed + "." + b64(sig)
+}
+
+func newTestProvider(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": server.URL + "/jwks"})
+		case "/jwks":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"keys": []map[string]string{{
+					"kty": "RSA",
+					"kid": "key-1",
+					"use": "sig",
+					"n":   b64(key.N.Bytes()),
+					"e":   b64(big.NewInt(int64(key.E)).Bytes()),
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOIDCVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	provider := newTestProvider(t, key)
+
+	verifier := NewOIDCVerifier(config.OIDCConfig{
+		Issuer:   provider.URL,
+		Audience: "aerospike-mcp",
+	})
+
+	now := time.Now().Unix()
+	valid := map[string]interface{}{
+		"iss": provider.URL,
+		"sub": "alice",
+		"aud": []string{"aerospike-mcp", "other"},
+		"exp": now + 300,
+	}
+
+	claims, err := verifier.Verify(context.Background(), signRS256(t, key, "key-1", valid))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.String("sub") != "alice" {
+		t.Errorf("Expected sub 'alice', got '%s'", claims.String("sub"))
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(map[string]interface{})
+		kid    string
+	}{
+		{"wrong issuer", func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }, "key-1"},
+		{"wrong audience", func(c map[string]interface{}) { c["aud"] = "someone-else" }, "key-1"},
+		{"expired", func(c map[string]interface{}) { c["exp"] = now - 3600 }, "key-1"},
+		{"not yet valid", func(c map[string]interface{}) { c["nbf"] = now + 3600 }, "key-1"},
+		{"missing expiry", func(c map[string]interface{}) { delete(c, "exp") }, "key-1"},
+		{"unknown key", func(c map[string]interface{}) {}, "key-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := make(map[string]interface{}, len(valid))
+			for k, v := range valid {
+				claims[k] = v
+			}
+			tt.mutate(claims)
+			if _, err := verifier.Verify(context.Background(), signRS256(t, key, tt.kid, claims)); err == nil {
+				t.Error("Expected verification to fail")
+			}
+		})
+	}
+}
+
+func TestOIDCVerifyRejectsTamperedToken(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	provider := newTestProvider(t, key)
+	verifier := NewOIDCVerifier(config.OIDCConfig{Issuer: provider.URL})
+
+	token := signRS256(t, key, "key-1", map[string]interface{}{
+		"iss": provider.URL,
+		"sub": "alice",
+		"exp": time.Now().Unix() + 300,
+	})
+
+	parts := strings.Split(token, ".")
+	forged, _ := json.Marshal(map[string]interface{}{
+		"iss": provider.URL,
+		"sub": "mallory",
+		"exp": time.Now().Unix() + 300,
+	})
+	tampered := parts[0] + "." + b64(forged) + "." + parts[2]
+
+	if _, err := verifier.Verify(context.Background(), tampered); err == nil {
+		t.Error("Expected tampered token to be rejected")
+	}
+}
+
+func TestVerifySignatureAlgorithms(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	signed := []byte("header.payload")
+	digest := sha256.Sum256(signed)
+	r, s, _ := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	if err := verifySignature("ES256", &ecKey.PublicKey, signed, sig); err != nil {
+		t.Errorf("ES256 verification failed: %v", err)
+	}
+
+	for _, alg := range []string{"none", "HS256", "RS256"} {
+		if err := verifySignature(alg, &ecKey.PublicKey, signed, sig); err == nil {
+			t.Errorf("Expected algorithm %s to be rejected for EC key", alg)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package logging configures the server's structured logger. Records logged
+// with a context carry the request ID, tool name, and client identity from
+// that context, using the same field names as audit events so the two can be
+// correlated.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+)
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type toolKey struct{}
+
+// New returns a logger writing to w at the given level ("debug", "info",
+// "warn", or "error") in the given format ("text" or "json").
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid log level: %s", level)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		h = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format: %s", format)
+	}
+
+	return slog.New(contextHandler{h}), nil
+}
+
+// Setup makes a logger created by New the default for both log/slog and
+// the standard log package.
+func Setup(w io.Writer, level, format string) error {
+	logger, err := New(w, level, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// WithTool returns ctx carrying the name of the tool being called.
+func WithTool(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolKey{}, name)
+}
+
+// contextHandler adds the request attributes in a record's context.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if id := audit.RequestIDFromContext(ctx); id != "" {
+			r.AddAttrs(slog.String("request_id", id))
+		}
+		if tool, _ := ctx.Value(toolKey{}).(string); tool != "" {
+			r.AddAttrs(slog.String("tool", tool))
+		}
+		if user := audit.UserFromContext(ctx); user != "" {
+			r.AddAttrs(slog.String("user", user))
+		}
+		if clientID := audit.ClientIDFromContext(ctx); clientID != "" {
+			r.AddAttrs(slog.String("client_id", clientID))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		format  string
+		wantErr bool
+	}{
+		{"defaults", "", "", false},
+		{"json debug", "debug", "json", false},
+		{"upper case", "WARN", "TEXT", false},
+		{"invalid level", "verbose", "text", true},
+		{"invalid format", "info", "xml", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&bytes.Buffer{}, tt.level, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := audit.WithClientID(audit.WithUser(context.Background(), "alice"), "session-1")
+	ctx = WithTool(audit.WithRequestID(ctx, "42"), "get_record")
+	logger.With("component", "test").InfoContext(ctx, "Tool call completed")
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Invalid JSON log line: %v", err)
+	}
+	want := map[string]string{
+		"msg":        "Tool call completed",
+		"request_id": "42",
+		"tool":       "get_record",
+		"user":       "alice",
+		"client_id":  "session-1",
+		"component":  "test",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("Expected %s=%q, got %v", key, value, record[key])
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := New(&buf, "warn", FormatText)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("Expected only warnings, got %q", buf.String())
+	}
+}
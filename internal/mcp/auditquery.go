@@ -0,0 +1,217 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/resources"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// queryAuditLogTool is the name of the audit search tool.
+const queryAuditLogTool = "query_audit_log"
+
+// auditRecentURI is the resource listing recent audit events. It accepts the
+// tool's filters as query parameters.
+const auditRecentURI = "aerospike://audit/recent"
+
+// defaultAuditQueryLimit is the number of events returned unless the caller
+// asks for more.
+const defaultAuditQueryLimit = 100
+
+// auditQueryArgs are the filters accepted by query_audit_log.
+type auditQueryArgs struct {
+	Since     string `json:"since"`
+	Until     string `json:"until"`
+	Category  string `json:"category"`
+	Operation string `json:"operation"`
+	Namespace string `json:"namespace"`
+	RequestID string `json:"request_id"`
+	Success   *bool  `json:"success"`
+	Limit     int    `json:"limit"`
+}
+
+// query converts the arguments to an audit query.
+func (a auditQueryArgs) query() (audit.Query, error) {
+	q := audit.Query{
+		Category:  audit.Category(strings.ToUpper(a.Category)),
+		Operation: a.Operation,
+		Namespace: a.Namespace,
+		RequestID: a.RequestID,
+		Success:   a.Success,
+		Limit:     a.Limit,
+	}
+	var err error
+	if a.Since != "" {
+		if q.Since, err = time.Parse(time.RFC3339, a.Since); err != nil {
+			return q, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if a.Until != "" {
+		if q.Until, err = time.Parse(time.RFC3339, a.Until); err != nil {
+			return q, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	switch q.Category {
+	case "", audit.CategoryRead, audit.CategoryWrite, audit.CategoryAdmin, audit.CategoryAuth, audit.CategorySystem, audit.CategoryRedact:
+	default:
+		return q, fmt.Errorf("invalid category: %s", a.Category)
+	}
+	if q.Limit < 0 {
+		return q, fmt.Errorf("invalid limit: %d", q.Limit)
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultAuditQueryLimit
+	}
+	return q, nil
+}
+
+// auditQueryArgsFromURI reads the filters from the query string of an
+// aerospike://audit/recent URI.
+func auditQueryArgsFromURI(uri string) (auditQueryArgs, error) {
+	var args auditQueryArgs
+	u, err := url.Parse(uri)
+	if err != nil {
+		return args, fmt.Errorf("invalid resource URI: %w", err)
+	}
+	values := u.Query()
+	args.Since = values.Get("since")
+	args.Until = values.Get("until")
+	args.Category = values.Get("category")
+	args.Operation = values.Get("operation")
+	args.Namespace = values.Get("namespace")
+	args.RequestID = values.Get("request_id")
+	if v := values.Get("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			return args, fmt.Errorf("invalid success: %q", v)
+		}
+		args.Success = &success
+	}
+	if v := values.Get("limit"); v != "" {
+		if args.Limit, err = strconv.Atoi(v); err != nil {
+			return args, fmt.Errorf("invalid limit: %q", v)
+		}
+	}
+	return args, nil
+}
+
+// isAuditRecentURI reports whether uri names the recent audit events
+// resource, with or without filters.
+func isAuditRecentURI(uri string) bool {
+	return uri == auditRecentURI || strings.HasPrefix(uri, auditRecentURI+"?")
+}
+
+// canQueryAudit reports whether the audit log can be searched by the client.
+// Audit events describe every client's activity, so only admins may, even
+// while the server is read-only.
+func (s *Server) canQueryAudit(ctx context.Context) bool {
+	return s.auditLogger != nil && s.config.GrantedRole(ctx).CanAdmin()
+}
+
+// searchAudit returns the buffered audit events matching args.
+func (s *Server) searchAudit(ctx context.Context, args auditQueryArgs) (map[string]interface{}, error) {
+	if !s.canQueryAudit(ctx) {
+		return nil, &config.RoleError{Operation: "tool " + queryAuditLogTool, Role: s.config.GrantedRole(ctx)}
+	}
+	q, err := args.query()
+	if err != nil {
+		return nil, err
+	}
+	events := s.auditLogger.Query(q)
+	return map[string]interface{}{
+		"events": events,
+		"count":  len(events),
+	}, nil
+}
+
+// callQueryAuditLog handles the query_audit_log tool.
+func (s *Server) callQueryAuditLog(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args auditQueryArgs
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	return s.searchAudit(ctx, args)
+}
+
+// readAuditResource handles reads of aerospike://audit/recent.
+func (s *Server) readAuditResource(ctx context.Context, uri string) (string, error) {
+	args, err := auditQueryArgsFromURI(uri)
+	if err != nil {
+		return "", err
+	}
+	result, err := s.searchAudit(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// auditRecentVariants are filtered views of the recent audit events listed
+// alongside the full resource, for clients that only read listed URIs.
+var auditRecentVariants = []struct {
+	query, name, description string
+}{
+	{"success=false", "Failed Audit Events", "Recent audited events that failed, newest first"},
+	{"category=WRITE", "Write Audit Events", "Recent audited writes, newest first"},
+	{"category=ADMIN", "Admin Audit Events", "Recent audited admin operations, newest first"},
+	{"category=AUTH", "Auth Audit Events", "Recent authentication and authorization events, newest first"},
+}
+
+// auditRecentResources describes the recent audit events resource and its
+// filtered variants.
+func auditRecentResources() []resources.ResourceDefinition {
+	definitions := []resources.ResourceDefinition{{
+		URI:         auditRecentURI,
+		Name:        "Recent Audit Events",
+		Description: "Most recent audited events, newest first. Accepts since, until, category, operation, namespace, request_id, success, and limit query parameters.",
+		MimeType:    "application/json",
+	}}
+	for _, v := range auditRecentVariants {
+		definitions = append(definitions, resources.ResourceDefinition{
+			URI:         auditRecentURI + "?" + v.query,
+			Name:        v.name,
+			Description: v.description,
+			MimeType:    "application/json",
+		})
+	}
+	return definitions
+}
+
+// queryAuditLogDefinition describes the query_audit_log tool.
+func queryAuditLogDefinition() tools.ToolDefinition {
+	return tools.ToolDefinition{
+		Name: queryAuditLogTool,
+		Description: "Search recent audit events held in memory, newest first. " +
+			"Covers the last audit.buffer_size events; older events are only in the audit log file.",
+		InputSchema: tools.InputSchema{
+			Type: "object",
+			Properties: map[string]tools.Property{
+				"since":      {Type: "string", Description: "Earliest event time (RFC 3339)"},
+				"until":      {Type: "string", Description: "Latest event time (RFC 3339)"},
+				"category":   {Type: "string", Description: "Event category", Enum: []string{"READ", "WRITE", "ADMIN", "AUTH", "SYSTEM", "REDACT"}},
+				"operation":  {Type: "string", Description: "Operation or tool name, e.g. put_record"},
+				"namespace":  {Type: "string", Description: "Namespace the event touched"},
+				"request_id": {Type: "string", Description: "Request ID from a server log line or error response"},
+				"success":    {Type: "boolean", Description: "Only successful (true) or failed (false) events"},
+				"limit":      {Type: "integer", Description: "Maximum events to return", Default: defaultAuditQueryLimit},
+			},
+		},
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func newAuditQueryTestServer(t *testing.T, role config.Role) *Server {
+	t.Helper()
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(io.Discard)
+	auditLogger.Log(audit.Event{Level: audit.LevelAudit, Category: audit.CategoryWrite, Operation: "put_record", Namespace: "test", Success: true})
+	auditLogger.Log(audit.Event{Level: audit.LevelAudit, Category: audit.CategoryWrite, Operation: "delete_record", Namespace: "test", Success: false})
+	auditLogger.Log(audit.Event{Level: audit.LevelInfo, Category: audit.CategoryRead, Operation: "get_record", Namespace: "prod", RequestID: "req-1", Success: true})
+
+	cfg := config.DefaultConfig()
+	cfg.Role = role
+	return &Server{
+		config:      cfg,
+		auditLogger: auditLogger,
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+	}
+}
+
+func TestQueryAuditLogTool(t *testing.T) {
+	s := newAuditQueryTestServer(t, config.RoleAdmin)
+
+	tests := []struct {
+		name    string
+		args    string
+		want    []string
+		wantErr bool
+	}{
+		{"all", `{}`, []string{"get_record", "delete_record", "put_record"}, false},
+		{"category", `{"category":"write"}`, []string{"delete_record", "put_record"}, false},
+		{"failures", `{"success":false}`, []string{"delete_record"}, false},
+		{"namespace and limit", `{"namespace":"test","limit":1}`, []string{"delete_record"}, false},
+		{"request id", `{"request_id":"req-1"}`, []string{"get_record"}, false},
+		{"time range", `{"until":"2000-01-01T00:00:00Z"}`, nil, false},
+		{"invalid time", `{"since":"yesterday"}`, nil, true},
+		{"invalid category", `{"category":"debug"}`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _ := json.Marshal(ToolsCallParams{Name: queryAuditLogTool, Arguments: json.RawMessage(tt.args)})
+			result, rpcErr := s.handleToolsCall(context.Background(), params)
+			if rpcErr != nil {
+				t.Fatalf("Unexpected error: %v", rpcErr)
+			}
+			if result.IsError != tt.wantErr {
+				t.Fatalf("Expected IsError=%v, got %s", tt.wantErr, result.Content[0].Text)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var out struct {
+				Events []audit.Event `json:"events"`
+			}
+			_ = json.Unmarshal([]byte(result.Content[0].Text), &out)
+			var got []string
+			for _, e := range out.Events {
+				got = append(got, e.Operation)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQueryAuditLogRequiresAdmin(t *testing.T) {
+	s := newAuditQueryTestServer(t, config.RoleReadWrite)
+
+	listed, _ := s.handleToolsList(context.Background())
+	for _, tool := range listed.Tools {
+		if tool.Name == queryAuditLogTool {
+			t.Error("Expected query_audit_log not to be listed for read-write")
+		}
+	}
+
+	params, _ := json.Marshal(ToolsCallParams{Name: queryAuditLogTool, Arguments: json.RawMessage(`{}`)})
+	result, _ := s.handleToolsCall(context.Background(), params)
+	if result == nil || !result.IsError {
+		t.Error("Expected query_audit_log to be refused for read-write")
+	}
+
+	readParams, _ := json.Marshal(ResourcesReadParams{URI: auditRecentURI})
+	if _, rpcErr := s.handleResourcesRead(context.Background(), readParams); rpcErr == nil {
+		t.Error("Expected audit resource to be refused for read-write")
+	}
+}
+
+func TestAuditRecentResource(t *testing.T) {
+	s := newAuditQueryTestServer(t, config.RoleAdmin)
+
+	params, _ := json.Marshal(ResourcesReadParams{URI: auditRecentURI + "?category=READ&success=true"})
+	result, rpcErr := s.handleResourcesRead(context.Background(), params)
+	if rpcErr != nil {
+		t.Fatalf("Unexpected error: %v", rpcErr)
+	}
+
+	var out struct {
+		Count  int           `json:"count"`
+		Events []audit.Event `json:"events"`
+	}
+	if err := json.Unmarshal([]byte(result.Contents[0].Text), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Count != 1 || out.Events[0].Operation != "get_record" {
+		t.Errorf("Expected the get_record event, got %+v", out.Events)
+	}
+
+	params, _ = json.Marshal(ResourcesReadParams{URI: auditRecentURI + "?limit=many"})
+	if _, rpcErr := s.handleResourcesRead(context.Background(), params); rpcErr == nil {
+		t.Error("Expected invalid limit to be rejected")
+	}
+}
+
+func TestAuditRecentVariants(t *testing.T) {
+	s := newAuditQueryTestServer(t, config.RoleAdmin)
+
+	want := map[string]int{
+		auditRecentURI:                     3,
+		auditRecentURI + "?success=false":  1,
+		auditRecentURI + "?category=WRITE": 2,
+		auditRecentURI + "?category=ADMIN": 0,
+		auditRecentURI + "?category=AUTH":  0,
+	}
+	definitions := auditRecentResources()
+	if len(definitions) != len(want) {
+		t.Fatalf("Expected %d audit resources, got %d", len(want), len(definitions))
+	}
+	for _, def := range definitions {
+		params, _ := json.Marshal(ResourcesReadParams{URI: def.URI})
+		result, rpcErr := s.handleResourcesRead(context.Background(), params)
+		if rpcErr != nil {
+			t.Fatalf("%s: unexpected error: %v", def.URI, rpcErr)
+		}
+		var out struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal([]byte(result.Contents[0].Text), &out); err != nil {
+			t.Fatal(err)
+		}
+		if count, ok := want[def.URI]; !ok || out.Count != count {
+			t.Errorf("%s: Expected %d events, got %d", def.URI, count, out.Count)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/auth"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// identity describes an authenticated HTTP client.
+type identity struct {
+	user   string
+	role   config.Role // empty if the credential doesn't assert a role
+	method string
+}
+
+// authenticator verifies API keys and OIDC bearer tokens presented by HTTP
+// transport clients.
+type authenticator struct {
+	mu sync.RWMutex
+	// keys maps the SHA-256 digest of each key to its name. Looking keys up
+	// by digest avoids comparing secrets byte-by-byte.
+	keys map[[sha256.Size]byte]string
+
+	oidc    *auth.OIDCVerifier
+	oidcCfg *config.OIDCConfig
+}
+
+// newAuthenticator creates an authenticator for the configured credentials.
+// It returns nil if authentication is not enabled.
+func newAuthenticator(cfg config.AuthConfig) *authenticator {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	a := &authenticator{keys: hashKeys(cfg.APIKeys)}
+	if cfg.OIDC != nil {
+		a.oidc = auth.NewOIDCVerifier(*cfg.OIDC)
+		a.oidcCfg = cfg.OIDC
+	}
+	return a
+}
+
+// hashKeys indexes API keys by the SHA-256 digest of their value.
+func hashKeys(apiKeys []config.APIKey) map[[sha256.Size]byte]string {
+	keys := make(map[[sha256.Size]byte]string, len(apiKeys))
+	for _, key := range apiKeys {
+		keys[sha256.Sum256([]byte(key.Key))] = key.Name
+	}
+	return keys
+}
+
+// setKeys replaces the accepted API keys.
+func (a *authenticator) setKeys(apiKeys []config.APIKey) {
+	keys := hashKeys(apiKeys)
+	a.mu.Lock()
+	a.keys = keys
+	a.mu.Unlock()
+}
+
+// verify authenticates the request, returning the client identity or the
+// reason authentication failed.
+func (a *authenticator) verify(r *http.Request) (*identity, string) {
+	return a.verifyHeader(r.Context(), r.Header.Get("Authorization"))
+}
+
+// verifyHeader authenticates the credential in an Authorization header
+// value, from an HTTP request or gRPC metadata.
+func (a *authenticator) verifyHeader(ctx context.Context, header string) (*identity, string) {
+	if header == "" {
+		return nil, "missing Authorization header"
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, "unsupported authorization scheme"
+	}
+	token = strings.TrimSpace(token)
+
+	a.mu.RLock()
+	name, ok := a.keys[sha256.Sum256([]byte(token))]
+	a.mu.RUnlock()
+	if ok {
+		return &identity{user: name, method: "api_key"}, ""
+	}
+
+	if a.oidc != nil && strings.Count(token, ".") == 2 {
+		claims, err := a.oidc.Verify(ctx, token)
+		if err != nil {
+			return nil, err.Error()
+		}
+		return &identity{
+			user:   claims.String(a.oidcCfg.UserClaim),
+			role:   a.roleFromClaims(claims),
+			method: "oidc",
+		}, ""
+	}
+
+	return nil, "invalid credentials"
+}
+
+// roleFromClaims maps the configured role claim to the most privileged role
+// it names. The claim may be a string or an array of strings. With a
+// role_map, only mapped values grant a role, so an IdP group that happens
+// to share a role's name grants nothing; without one, values name roles
+// directly.
+func (a *authenticator) roleFromClaims(claims auth.Claims) config.Role {
+	if a.oidcCfg.RoleClaim == "" {
+		return ""
+	}
+
+	var values []string
+	switch v := claims[a.oidcCfg.RoleClaim].(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var best config.Role
+	for _, value := range values {
+		role := config.Role(value)
+		if len(a.oidcCfg.RoleMap) > 0 {
+			role = a.oidcCfg.RoleMap[value]
+		}
+		if role.Valid() && role.Rank() > best.Rank() {
+			best = role
+		}
+	}
+	return best
+}
+
+// authenticate wraps an HTTP handler so that requests must present a valid
+// API key or OIDC token, or a verified TLS client certificate. The client
+// identity is recorded as the audit user for the request. Failed attempts
+// are written to the audit log.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A verified client certificate identifies the client unless it
+		// also presents a bearer credential
+		if user := certIdentity(r.TLS); user != "" && r.Header.Get("Authorization") == "" {
+			id := &identity{user: user, method: "mtls"}
+			next.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), id)))
+			return
+		}
+
+		if s.auth == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		id, reason := s.auth.verify(r)
+		if id == nil {
+			if s.auditLogger != nil {
+				s.auditLogger.LogAuth(r.Context(), "authenticate", false, map[string]interface{}{
+					"path":        r.URL.Path,
+					"remote_addr": r.RemoteAddr,
+					"reason":      reason,
+				})
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="aerospike-mcp-server"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), id)))
+	})
+}
+
+// restrictSource wraps an HTTP handler so that only clients connecting from
+// an allowed network are served. Others receive 403 Forbidden and the attempt
+// is written to the audit log. The connection's address is used, not
+// forwarding headers, which clients can set freely.
+func (s *Server) restrictSource(next http.Handler) http.Handler {
+	if len(s.allowed) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.sourceAllowed(r.RemoteAddr) {
+			if s.auditLogger != nil {
+				s.auditLogger.LogAuth(r.Context(), "source_address", false, map[string]interface{}{
+					"path":        r.URL.Path,
+					"remote_addr": r.RemoteAddr,
+					"reason":      "source address not in allowed_cidrs",
+				})
+			}
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// sourceAllowed reports whether a "host:port" remote address is within an
+// allowed network.
+func (s *Server) sourceAllowed(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range s.allowed {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateAPIKeys replaces the API keys accepted by the HTTP transports, for
+// example after they are rotated in a secret store. It has no effect if
+// authentication was not enabled at startup.
+func (s *Server) UpdateAPIKeys(keys []config.APIKey) {
+	if s.auth != nil {
+		s.auth.setKeys(keys)
+	}
+}
+
+// withIdentity stores the authenticated identity in the request context.
+func withIdentity(ctx context.Context, id *identity) context.Context {
+	ctx = audit.WithUser(ctx, id.user)
+	if id.role != "" {
+		ctx = config.WithRole(ctx, id.role)
+	}
+	return ctx
+}
+
+// resolveRole applies the client_roles mapping for the client identified in
+// ctx. A mapped role overrides any role asserted by the client's credentials.
+func (s *Server) resolveRole(ctx context.Context) context.Context {
+	if s.config == nil {
+		return ctx
+	}
+	if role, ok := s.config.RoleForClient(audit.UserFromContext(ctx), audit.ClientIDFromContext(ctx)); ok {
+		ctx = config.WithRole(ctx, role)
+	}
+	return s.elevate(ctx)
+}
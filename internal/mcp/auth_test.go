@@ -0,0 +1,227 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/auth"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestAuthenticate(t *testing.T) {
+	var auditBuf bytes.Buffer
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(&auditBuf)
+
+	s := &Server{
+		auditLogger: auditLogger,
+		auth: newAuthenticator(config.AuthConfig{
+			APIKeys: []config.APIKey{{Name: "ci", Key: "secret-key"}},
+		}),
+	}
+
+	var gotUser string
+	handler := s.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = audit.UserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"valid key", "Bearer secret-key", http.StatusOK},
+		{"missing header", "", http.StatusUnauthorized},
+		{"wrong key", "Bearer wrong", http.StatusUnauthorized},
+		{"wrong scheme", "Basic secret-key", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+
+	if gotUser != "ci" {
+		t.Errorf("Expected audit user 'ci', got '%s'", gotUser)
+	}
+
+	if n := strings.Count(auditBuf.String(), `"category":"AUTH"`); n != 3 {
+		t.Errorf("Expected 3 AUTH audit events, got %d", n)
+	}
+}
+
+func TestAuthenticateDisabled(t *testing.T) {
+	s := &Server{auth: newAuthenticator(config.AuthConfig{})}
+
+	handler := s.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sse", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with auth disabled, got %d", rec.Code)
+	}
+}
+
+func TestRoleFromClaims(t *testing.T) {
+	a := newAuthenticator(config.AuthConfig{
+		OIDC: &config.OIDCConfig{
+			Issuer:    "https://sso.example.com",
+			UserClaim: "sub",
+			RoleClaim: "groups",
+			RoleMap: map[string]config.Role{
+				"db-admins":  config.RoleAdmin,
+				"developers": config.RoleReadWrite,
+			},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		claims auth.Claims
+		want   config.Role
+	}{
+		{"mapped group", auth.Claims{"groups": []interface{}{"developers"}}, config.RoleReadWrite},
+		{"highest wins", auth.Claims{"groups": []interface{}{"developers", "db-admins"}}, config.RoleAdmin},
+		{"unmapped role name", auth.Claims{"groups": []interface{}{"admin", "developers"}}, config.RoleReadWrite},
+		{"unknown value", auth.Claims{"groups": "marketing"}, ""},
+		{"missing claim", auth.Claims{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.roleFromClaims(tt.claims); got != tt.want {
+				t.Errorf("roleFromClaims() = '%s', want '%s'", got, tt.want)
+			}
+		})
+	}
+
+	// Without a role map, claim values name roles directly
+	unmapped := newAuthenticator(config.AuthConfig{
+		OIDC: &config.OIDCConfig{Issuer: "https://sso.example.com", RoleClaim: "groups"},
+	})
+	if got := unmapped.roleFromClaims(auth.Claims{"groups": "read-only"}); got != config.RoleReadOnly {
+		t.Errorf("roleFromClaims() = '%s', want '%s'", got, config.RoleReadOnly)
+	}
+}
+
+func TestResolveRole(t *testing.T) {
+	s := &Server{config: &config.Config{
+		Role:        config.RoleReadOnly,
+		ClientRoles: map[string]config.Role{"ci": config.RoleReadWrite, "ws-1": config.RoleAdmin},
+	}}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want config.Role
+	}{
+		{"anonymous", context.Background(), config.RoleReadOnly},
+		{"mapped user", audit.WithUser(context.Background(), "ci"), config.RoleReadWrite},
+		{"mapped client id", audit.WithClientID(context.Background(), "ws-1"), config.RoleAdmin},
+		{"unmapped user keeps token role", config.WithRole(audit.WithUser(context.Background(), "other"), config.RoleAdmin), config.RoleAdmin},
+		{"mapping overrides token role", config.WithRole(audit.WithUser(context.Background(), "ci"), config.RoleAdmin), config.RoleReadWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.config.EffectiveRole(s.resolveRole(tt.ctx)); got != tt.want {
+				t.Errorf("Expected role '%s', got '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUpdateAPIKeys(t *testing.T) {
+	s := &Server{auth: newAuthenticator(config.AuthConfig{
+		APIKeys: []config.APIKey{{Name: "ci", Key: "old-key"}},
+	})}
+
+	s.UpdateAPIKeys([]config.APIKey{{Name: "ci", Key: "new-key"}})
+
+	for key, wantOK := range map[string]bool{"old-key": false, "new-key": true} {
+		req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		id, _ := s.auth.verify(req)
+		if (id != nil) != wantOK {
+			t.Errorf("Key %s: expected accepted=%v", key, wantOK)
+		}
+	}
+}
+
+func TestRestrictSource(t *testing.T) {
+	var auditBuf bytes.Buffer
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(&auditBuf)
+
+	cfg := &config.Config{AllowedCIDRs: []string{"10.0.0.0/8", "192.168.1.5", "fd00::/8"}}
+	allowed, err := cfg.AllowedNetworks()
+	if err != nil {
+		t.Fatalf("AllowedNetworks() error = %v", err)
+	}
+	s := &Server{auditLogger: auditLogger, allowed: allowed}
+
+	handler := s.restrictSource(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		remoteAddr string
+		status     int
+	}{
+		{"10.1.2.3:51000", http.StatusOK},
+		{"192.168.1.5:51000", http.StatusOK},
+		{"[fd00::1]:51000", http.StatusOK},
+		{"192.168.1.6:51000", http.StatusForbidden},
+		{"203.0.113.7:51000", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remoteAddr, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "10.0.0.1")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+
+	if n := strings.Count(auditBuf.String(), `"operation":"source_address"`); n != 2 {
+		t.Errorf("Expected 2 rejected source audit events, got %d", n)
+	}
+}
+
+func TestRestrictSourceDisabled(t *testing.T) {
+	s := &Server{}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+	req.RemoteAddr = "203.0.113.7:51000"
+	s.restrictSource(next).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 without an allowlist, got %d", rec.Code)
+	}
+}
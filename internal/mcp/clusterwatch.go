@@ -0,0 +1,141 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+)
+
+// clusterEventLogger names the source of cluster event notifications.
+const clusterEventLogger = "aerospike.cluster"
+
+// clusterEvent is a change in the cluster's topology or health.
+type clusterEvent struct {
+	Level     string `json:"-"` // notifications/message level
+	Event     string `json:"event"`
+	Node      string `json:"node,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Message   string `json:"message"`
+}
+
+// clusterWatcher compares successive cluster states.
+type clusterWatcher struct {
+	prev        *aerospike.ClusterState // nil before the first successful poll
+	unreachable bool
+}
+
+// update records the result of a poll and returns what changed since the
+// previous one. The first state is a baseline and produces no events.
+func (w *clusterWatcher) update(state *aerospike.ClusterState, err error) []clusterEvent {
+	if err != nil {
+		if w.unreachable {
+			return nil
+		}
+		w.unreachable = true
+		return []clusterEvent{{Level: "error", Event: "cluster_unreachable", Message: fmt.Sprintf("Cluster unreachable: %v", err)}}
+	}
+
+	var events []clusterEvent
+	if w.unreachable {
+		w.unreachable = false
+		events = append(events, clusterEvent{Level: "notice", Event: "cluster_reachable", Message: "Cluster reachable again"})
+	}
+	prev := w.prev
+	w.prev = state
+	if prev == nil {
+		return events
+	}
+
+	for _, node := range added(prev.Nodes, state.Nodes) {
+		events = append(events, clusterEvent{Level: "notice", Event: "node_joined", Node: node, Message: "Node " + node + " joined the cluster"})
+	}
+	for _, node := range added(state.Nodes, prev.Nodes) {
+		events = append(events, clusterEvent{Level: "warning", Event: "node_left", Node: node, Message: "Node " + node + " left the cluster"})
+	}
+
+	switch {
+	case prev.MigrationsRemaining == 0 && state.MigrationsRemaining > 0:
+		events = append(events, clusterEvent{Level: "notice", Event: "migrations_started",
+			Message: fmt.Sprintf("Migrations started: %d partitions remaining", state.MigrationsRemaining)})
+	case prev.MigrationsRemaining > 0 && state.MigrationsRemaining == 0:
+		events = append(events, clusterEvent{Level: "notice", Event: "migrations_finished", Message: "Migrations finished"})
+	}
+
+	for _, ns := range added(prev.StopWrites, state.StopWrites) {
+		events = append(events, clusterEvent{Level: "critical", Event: "stop_writes", Namespace: ns, Message: "Namespace " + ns + " stopped accepting writes"})
+	}
+	for _, ns := range added(state.StopWrites, prev.StopWrites) {
+		events = append(events, clusterEvent{Level: "notice", Event: "stop_writes_cleared", Namespace: ns, Message: "Namespace " + ns + " accepts writes again"})
+	}
+	return events
+}
+
+// added returns the names in next that aren't in prev.
+func added(prev, next []string) []string {
+	seen := make(map[string]bool, len(prev))
+	for _, name := range prev {
+		seen[name] = true
+	}
+	var names []string
+	for _, name := range next {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// watchCluster polls the cluster state until ctx is done, notifying
+// connected clients of changes. It only polls while a client is connected;
+// the first poll after a client connects sets a new baseline.
+func (s *Server) watchCluster(ctx context.Context) {
+	interval := time.Duration(s.config.ClusterEvents.PollIntervalMs) * time.Millisecond
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var watcher clusterWatcher
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if s.notifier.count() == 0 {
+			watcher = clusterWatcher{}
+			continue
+		}
+
+		pollCtx, cancel := context.WithTimeout(ctx, interval)
+		state, err := s.clusterState(pollCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		for _, event := range watcher.update(state, err) {
+			s.notifyClusterEvent(ctx, event)
+		}
+	}
+}
+
+// notifyClusterEvent logs a cluster event and sends it to connected clients.
+func (s *Server) notifyClusterEvent(ctx context.Context, event clusterEvent) {
+	level := slog.LevelInfo
+	if event.Level != "notice" {
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, event.Message, "event", event.Event, "node", event.Node, "namespace", event.Namespace)
+
+	s.notifier.notify("notifications/message", LoggingMessageParams{
+		Level:  event.Level,
+		Logger: clusterEventLogger,
+		Data:   event,
+	})
+}
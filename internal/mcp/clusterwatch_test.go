@@ -0,0 +1,144 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestClusterWatcherUpdate(t *testing.T) {
+	healthy := &aerospike.ClusterState{Nodes: []string{"A", "B"}, StopWrites: []string{}}
+
+	tests := []struct {
+		name  string
+		state *aerospike.ClusterState
+		err   error
+		want  []string
+	}{
+		{"baseline", healthy, nil, nil},
+		{"unchanged", healthy, nil, nil},
+		{"node left and migrating", &aerospike.ClusterState{Nodes: []string{"A"}, MigrationsRemaining: 40}, nil, []string{"node_left:B", "migrations_started"}},
+		{"still migrating", &aerospike.ClusterState{Nodes: []string{"A"}, MigrationsRemaining: 12}, nil, nil},
+		{"unreachable", nil, errors.New("timeout"), []string{"cluster_unreachable"}},
+		{"still unreachable", nil, errors.New("timeout"), nil},
+		{"node joined and stop writes", &aerospike.ClusterState{Nodes: []string{"A", "C"}, StopWrites: []string{"test"}}, nil, []string{"cluster_reachable", "node_joined:C", "migrations_finished", "stop_writes:test"}},
+		{"stop writes cleared", &aerospike.ClusterState{Nodes: []string{"A", "C"}}, nil, []string{"stop_writes_cleared:test"}},
+	}
+
+	var watcher clusterWatcher
+	for _, tt := range tests {
+		var got []string
+		for _, event := range watcher.update(tt.state, tt.err) {
+			name := event.Event
+			if subject := event.Node + event.Namespace; subject != "" {
+				name += ":" + subject
+			}
+			got = append(got, name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWatchClusterNotifies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ClusterEvents = config.ClusterEventsConfig{Enabled: true, PollIntervalMs: 5}
+
+	states := make(chan *aerospike.ClusterState, 10)
+	s := &Server{
+		config:   cfg,
+		notifier: newNotifier(),
+		clusterState: func(ctx context.Context) (*aerospike.ClusterState, error) {
+			select {
+			case state := <-states:
+				return state, nil
+			default:
+				return &aerospike.ClusterState{Nodes: []string{"A", "B"}}, nil
+			}
+		},
+	}
+
+	received := make(chan []byte, 10)
+	defer s.notifier.subscribe("client", func(msg []byte) { received <- msg })()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watchCluster(ctx)
+
+	// Let the watcher take a baseline before the node leaves
+	time.Sleep(20 * time.Millisecond)
+	states <- &aerospike.ClusterState{Nodes: []string{"A"}}
+
+	select {
+	case msg := <-received:
+		var notification struct {
+			Method string `json:"method"`
+			Params struct {
+				Level  string                 `json:"level"`
+				Logger string                 `json:"logger"`
+				Data   map[string]interface{} `json:"data"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal(msg, &notification); err != nil {
+			t.Fatal(err)
+		}
+		if notification.Method != "notifications/message" || notification.Params.Level != "warning" ||
+			notification.Params.Logger != clusterEventLogger || notification.Params.Data["node"] != "B" {
+			t.Errorf("Expected a node_left warning for B, got %s", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a notification")
+	}
+}
+
+func TestInitializeLoggingCapability(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		cfg := config.DefaultConfig()
+		cfg.ClusterEvents.Enabled = enabled
+		s := &Server{config: cfg}
+
+		result, _ := s.handleInitialize(context.Background(), nil)
+		if (result.Capabilities.Logging != nil) != enabled {
+			t.Errorf("Expected logging capability=%v with cluster events enabled=%v", enabled, enabled)
+		}
+	}
+}
+
+func TestServeStreamNotifications(t *testing.T) {
+	s := &Server{config: config.DefaultConfig(), notifier: newNotifier()}
+
+	in, input := io.Pipe()
+	var out bytes.Buffer
+	done := make(chan error)
+	go func() { done <- s.serveStream(context.Background(), in, &out) }()
+
+	for s.notifier.count() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	s.notifier.notify("notifications/message", LoggingMessageParams{Level: "warning", Data: "node left"})
+	time.Sleep(20 * time.Millisecond)
+	input.Close()
+
+	if err := <-done; err != nil {
+		t.Fatalf("serveStream() error = %v", err)
+	}
+	if s.notifier.count() != 0 {
+		t.Error("Expected the stream to unsubscribe when it ends")
+	}
+	want := `{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"warning","data":"node left"}}` + "\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+}
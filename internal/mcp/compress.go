@@ -0,0 +1,64 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// compressor is a compressing writer that can flush what it holds, so each
+// event reaches the client without waiting for more output.
+type compressor interface {
+	io.Writer
+	Flush() error
+	Close() error
+}
+
+// contentEncodings are the encodings the server compresses with, most
+// preferred first.
+var contentEncodings = []string{"gzip", "deflate"}
+
+// negotiateEncoding returns the preferred encoding the Accept-Encoding header
+// allows, or "" to send the response uncompressed.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := make(map[string]bool)
+	wildcard := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		ok := true
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				q, err := strconv.ParseFloat(value, 64)
+				ok = err == nil && q > 0
+			}
+		}
+		if name == "*" {
+			wildcard = ok
+			continue
+		}
+		accepted[name] = ok
+	}
+
+	for _, encoding := range contentEncodings {
+		if ok, listed := accepted[encoding]; ok || (!listed && wildcard) {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// newCompressor returns a writer compressing to w with encoding, which
+// negotiateEncoding chose. HTTP's "deflate" is the zlib format.
+func newCompressor(w io.Writer, encoding string) compressor {
+	if encoding == "deflate" {
+		return zlib.NewWriter(w)
+	}
+	return gzip.NewWriter(w)
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import "testing"
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip", "gzip"},
+		{"br, deflate", "deflate"},
+		{"GZIP;q=0.5", "gzip"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"gzip;q=0, deflate;q=0", ""},
+		{"*", "gzip"},
+		{"*, gzip;q=0", "deflate"},
+		{"*;q=0", ""},
+		{"gzip;q=bad", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, expected %q", tt.header, got, tt.want)
+		}
+	}
+}
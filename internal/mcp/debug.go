@@ -0,0 +1,57 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// debugHandler serves net/http/pprof profiles under /debug/pprof/ and
+// expvar runtime metrics, including memory statistics, at /debug/vars.
+func (s *Server) debugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return s.restrictSource(mux)
+}
+
+// serveDebug serves the debug endpoints on debug.address until ctx is
+// done. Validate has checked the address is loopback unless remote access
+// was allowed, in which case allowed_cidrs restricts the sources.
+func (s *Server) serveDebug(ctx context.Context) error {
+	addr := s.config.Debug.Address
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           s.debugHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	slog.Warn("Debug server listening; profiles expose server internals", "addr", addr)
+	if err := httpServer.Serve(listener); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestDebugEndpoints(t *testing.T) {
+	_, internal, _ := net.ParseCIDR("10.0.0.0/8")
+
+	tests := []struct {
+		name       string
+		allowed    []*net.IPNet
+		remoteAddr string
+		path       string
+		wantCode   int
+		wantBody   string
+	}{
+		{"pprof index", nil, "127.0.0.1:5000", "/debug/pprof/", http.StatusOK, "goroutine"},
+		{"heap profile", nil, "127.0.0.1:5000", "/debug/pprof/heap?debug=1", http.StatusOK, "heap profile"},
+		{"runtime metrics", nil, "127.0.0.1:5000", "/debug/vars", http.StatusOK, `"memstats"`},
+		{"allowed source", []*net.IPNet{internal}, "10.1.2.3:5000", "/debug/vars", http.StatusOK, `"memstats"`},
+		{"blocked source", []*net.IPNet{internal}, "192.168.1.1:5000", "/debug/vars", http.StatusForbidden, "Forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{config: config.DefaultConfig(), allowed: tt.allowed}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.RemoteAddr = tt.remoteAddr
+			rec := httptest.NewRecorder()
+			s.debugHandler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected status code %d, got %d", tt.wantCode, rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body containing %q, got %.200q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// elevateRoleTool is the name of the role elevation tool.
+const elevateRoleTool = "elevate_role"
+
+// Elevation errors returned to clients.
+var (
+	errElevationNotFound = errors.New("elevation request not found or expired")
+	errElevationOwner    = errors.New("elevation request belongs to another client")
+	errApprovalInvalid   = errors.New("invalid approval code")
+)
+
+// ElevationRequest is a client's request for a higher role. Its String form
+// is what the client hands to an approver, who signs it with
+// ApprovalCode.
+type ElevationRequest struct {
+	ID       string
+	Role     config.Role
+	Duration time.Duration
+	Client   string // audit.ClientKey of the requesting client
+}
+
+// String encodes the request as id:role:seconds:client. The client comes
+// last because it may itself contain colons.
+func (r *ElevationRequest) String() string {
+	return fmt.Sprintf("%s:%s:%d:%s", r.ID, r.Role, int(r.Duration/time.Second), r.Client)
+}
+
+// ParseElevationRequest decodes a request encoded by String.
+func ParseElevationRequest(s string) (*ElevationRequest, error) {
+	parts := strings.SplitN(s, ":", 4)
+	if len(parts) != 4 || parts[0] == "" {
+		return nil, fmt.Errorf("malformed elevation request: %q", s)
+	}
+	role := config.Role(parts[1])
+	if !role.Valid() {
+		return nil, fmt.Errorf("malformed elevation request: unknown role %q", parts[1])
+	}
+	seconds, err := strconv.Atoi(parts[2])
+	if err != nil || seconds <= 0 {
+		return nil, fmt.Errorf("malformed elevation request: invalid duration %q", parts[2])
+	}
+	return &ElevationRequest{
+		ID:       parts[0],
+		Role:     role,
+		Duration: time.Duration(seconds) * time.Second,
+		Client:   parts[3],
+	}, nil
+}
+
+// ApprovalCode returns the code that approves the request, signed with the
+// approval key.
+func (r *ElevationRequest) ApprovalCode(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(r.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ReadApprovalKey reads an elevation approval key from a file. Surrounding
+// whitespace is ignored.
+func ReadApprovalKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading elevation approval key: %w", err)
+	}
+	key := bytes.TrimSpace(data)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("elevation approval key file %s is empty", path)
+	}
+	return key, nil
+}
+
+// elevation is a pending request or an approved grant.
+type elevation struct {
+	request *ElevationRequest
+	reason  string
+	expires time.Time
+	timer   *time.Timer // grants only: ends the grant when it expires
+}
+
+// elevationManager tracks elevation requests awaiting approval and the
+// grants in effect. Each client has at most one of each; a new request
+// replaces the client's pending one.
+type elevationManager struct {
+	key         []byte
+	maxDuration time.Duration
+	requestTTL  time.Duration
+	onExpire    func(req *ElevationRequest)
+	now         func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]*elevation // by client
+	grants  map[string]*elevation // by client
+}
+
+// newElevationManager creates an elevation manager, or returns nil if
+// elevation is disabled.
+func newElevationManager(cfg config.ElevationConfig) (*elevationManager, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	key, err := ReadApprovalKey(cfg.ApprovalKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	maxDuration := time.Duration(cfg.MaxDurationSec) * time.Second
+	if maxDuration <= 0 {
+		maxDuration = time.Hour
+	}
+	requestTTL := time.Duration(cfg.RequestTTLSec) * time.Second
+	if requestTTL <= 0 {
+		requestTTL = 15 * time.Minute
+	}
+
+	return &elevationManager{
+		key:         key,
+		maxDuration: maxDuration,
+		requestTTL:  requestTTL,
+		now:         time.Now,
+		pending:     make(map[string]*elevation),
+		grants:      make(map[string]*elevation),
+	}, nil
+}
+
+// request records a client's request for role, replacing any request it
+// already has pending.
+func (m *elevationManager) request(client string, current, role config.Role, duration time.Duration, reason string) (*ElevationRequest, time.Time, error) {
+	if !role.CanWrite() {
+		return nil, time.Time{}, fmt.Errorf("role must be %s or %s", config.RoleReadWrite, config.RoleAdmin)
+	}
+	if role.Rank() <= current.Rank() {
+		return nil, time.Time{}, fmt.Errorf("client already has role %s", current)
+	}
+	if duration <= 0 {
+		duration = m.maxDuration
+	}
+	if duration > m.maxDuration {
+		return nil, time.Time{}, fmt.Errorf("duration exceeds the maximum of %s", m.maxDuration)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, time.Time{}, errors.New("a reason is required")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	req := &ElevationRequest{
+		ID:       uuid.New().String(),
+		Role:     role,
+		Duration: duration.Truncate(time.Second),
+		Client:   client,
+	}
+	expires := m.now().Add(m.requestTTL)
+	m.pending[client] = &elevation{request: req, reason: reason, expires: expires}
+	return req, expires, nil
+}
+
+// activate checks an approval code for the client's pending request and, if
+// it is valid, grants the requested role until the request's duration
+// elapses. A request can be activated only once.
+func (m *elevationManager) activate(client, request, code string) (*elevation, error) {
+	req, err := ParseElevationRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	if req.Client != client {
+		return nil, errElevationOwner
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending, ok := m.pending[client]
+	if !ok || pending.request.String() != req.String() || m.now().After(pending.expires) {
+		return nil, errElevationNotFound
+	}
+	if !hmac.Equal([]byte(strings.ToLower(strings.TrimSpace(code))), []byte(req.ApprovalCode(m.key))) {
+		return nil, errApprovalInvalid
+	}
+	delete(m.pending, client)
+
+	if old, ok := m.grants[client]; ok {
+		old.timer.Stop()
+	}
+	grant := &elevation{request: req, reason: pending.reason, expires: m.now().Add(req.Duration)}
+	grant.timer = time.AfterFunc(req.Duration, func() { m.expire(client, grant) })
+	m.grants[client] = grant
+	return grant, nil
+}
+
+// revoke ends the client's grant and drops its pending request. It returns
+// the grant that was ended, if any.
+func (m *elevationManager) revoke(client string) *elevation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.pending, client)
+	grant, ok := m.grants[client]
+	if !ok {
+		return nil
+	}
+	grant.timer.Stop()
+	delete(m.grants, client)
+	return grant
+}
+
+// expire ends a grant whose time is up.
+func (m *elevationManager) expire(client string, grant *elevation) {
+	m.mu.Lock()
+	current, ok := m.grants[client]
+	if ok && current == grant {
+		delete(m.grants, client)
+	}
+	m.mu.Unlock()
+
+	if ok && current == grant && m.onExpire != nil {
+		m.onExpire(grant.request)
+	}
+}
+
+// grant returns the client's grant if it is in effect.
+func (m *elevationManager) grant(client string) (*elevation, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	grant, ok := m.grants[client]
+	if !ok || !m.now().Before(grant.expires) {
+		return nil, false
+	}
+	return grant, true
+}
+
+// elevate returns ctx with the client's elevated role if it has a grant in
+// effect that outranks its role.
+func (s *Server) elevate(ctx context.Context) context.Context {
+	if s.elevation == nil {
+		return ctx
+	}
+	grant, ok := s.elevation.grant(audit.ClientKey(ctx))
+	if !ok || grant.request.Role.Rank() <= s.config.GrantedRole(ctx).Rank() {
+		return ctx
+	}
+	return config.WithRole(ctx, grant.request.Role)
+}
+
+// elevateRoleArgs are the arguments of the elevate_role tool.
+type elevateRoleArgs struct {
+	Action       string      `json:"action"`
+	Role         config.Role `json:"role"`
+	DurationSec  int         `json:"duration_sec"`
+	Reason       string      `json:"reason"`
+	Request      string      `json:"request"`
+	ApprovalCode string      `json:"approval_code"`
+}
+
+// callElevateRole handles the elevate_role tool. A client first requests a
+// role, then activates it with the approval code an approver produced for
+// the request out of band.
+func (s *Server) callElevateRole(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args elevateRoleArgs
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	client := audit.ClientKey(ctx)
+	current := s.config.GrantedRole(ctx)
+
+	switch args.Action {
+	case "request":
+		req, expires, err := s.elevation.request(client, current, args.Role, time.Duration(args.DurationSec)*time.Second, args.Reason)
+		s.logElevation(ctx, "elevation_request", err, req, args.Reason)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"request":      req.String(),
+			"role":         req.Role,
+			"duration_sec": int(req.Duration / time.Second),
+			"expires_at":   expires.UTC().Format(time.RFC3339),
+			"message": "Ask an approver to run `aerospike-mcp-server approve-elevation '" + req.String() +
+				"'`, then call elevate_role with action activate, this request, and the approval code.",
+		}, nil
+
+	case "activate":
+		grant, err := s.elevation.activate(client, args.Request, args.ApprovalCode)
+		var req *ElevationRequest
+		if grant != nil {
+			req = grant.request
+		}
+		s.logElevation(ctx, "elevation_activate", err, req, "")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"role":       req.Role,
+			"expires_at": grant.expires.UTC().Format(time.RFC3339),
+		}, nil
+
+	case "status":
+		status := map[string]interface{}{"role": current, "elevated": false}
+		if grant, ok := s.elevation.grant(client); ok && grant.request.Role == current {
+			status["elevated"] = true
+			status["expires_at"] = grant.expires.UTC().Format(time.RFC3339)
+		}
+		return status, nil
+
+	case "revoke":
+		var req *ElevationRequest
+		if grant := s.elevation.revoke(client); grant != nil {
+			req = grant.request
+			s.logElevation(ctx, "elevation_revoke", nil, req, "")
+		}
+		return map[string]interface{}{"revoked": req != nil}, nil
+	}
+	return nil, fmt.Errorf("invalid action %q: must be request, activate, status, or revoke", args.Action)
+}
+
+// logElevation records an elevation request, activation, or revocation in
+// the audit log.
+func (s *Server) logElevation(ctx context.Context, operation string, err error, req *ElevationRequest, reason string) {
+	if s.auditLogger == nil {
+		return
+	}
+	details := map[string]interface{}{}
+	if req != nil {
+		details["request_id"] = req.ID
+		details["role"] = req.Role
+		details["duration_sec"] = int(req.Duration / time.Second)
+	}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	s.auditLogger.LogAuth(ctx, operation, err == nil, details)
+}
+
+// logElevationExpired records the end of an elevation that ran its course.
+func (s *Server) logElevationExpired(req *ElevationRequest) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Log(audit.Event{
+		Level:     audit.LevelAudit,
+		Category:  audit.CategoryAuth,
+		Operation: "elevation_expire",
+		Success:   true,
+		Details: map[string]interface{}{
+			"client":     req.Client,
+			"request_id": req.ID,
+			"role":       req.Role,
+		},
+	})
+}
+
+// elevateRoleDefinition describes the elevate_role tool.
+func elevateRoleDefinition() tools.ToolDefinition {
+	return tools.ToolDefinition{
+		Name: elevateRoleTool,
+		Description: "Request temporary write or admin permissions. Call with action request, have an approver sign the " +
+			"returned request out of band, then call with action activate and the approval code. The role expires automatically.",
+		InputSchema: tools.InputSchema{
+			Type: "object",
+			Properties: map[string]tools.Property{
+				"action":        {Type: "string", Description: "Step to perform", Enum: []string{"request", "activate", "status", "revoke"}},
+				"role":          {Type: "string", Description: "Role to request", Enum: []string{string(config.RoleReadWrite), string(config.RoleAdmin)}},
+				"duration_sec":  {Type: "integer", Description: "How long the role should last (request; defaults to the maximum)"},
+				"reason":        {Type: "string", Description: "Why the role is needed (request)"},
+				"request":       {Type: "string", Description: "Request returned by action request (activate)"},
+				"approval_code": {Type: "string", Description: "Code from the approver (activate)"},
+			},
+			Required: []string{"action"},
+		},
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// newTestElevationManager creates an elevation manager whose clock is
+// controlled by the returned pointer.
+func newTestElevationManager(t *testing.T) (*elevationManager, *time.Time) {
+	t.Helper()
+	keyFile := filepath.Join(t.TempDir(), "approval.key")
+	if err := os.WriteFile(keyFile, []byte("approval-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := newElevationManager(config.ElevationConfig{
+		Enabled:         true,
+		ApprovalKeyFile: keyFile,
+		MaxDurationSec:  600,
+		RequestTTLSec:   60,
+	})
+	if err != nil {
+		t.Fatalf("newElevationManager() error = %v", err)
+	}
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestElevationActivate(t *testing.T) {
+	m, now := newTestElevationManager(t)
+
+	req, _, err := m.request("user:alice", config.RoleReadOnly, config.RoleReadWrite, 5*time.Minute, "fix bad record")
+	if err != nil {
+		t.Fatalf("request() error = %v", err)
+	}
+	code := req.ApprovalCode([]byte("approval-secret"))
+
+	tests := []struct {
+		name    string
+		client  string
+		request string
+		code    string
+		want    error
+	}{
+		{"wrong code", "user:alice", req.String(), strings.Repeat("0", len(code)), errApprovalInvalid},
+		{"signed with another key", "user:alice", req.String(), req.ApprovalCode([]byte("other")), errApprovalInvalid},
+		{"other client", "user:mallory", req.String(), code, errElevationOwner},
+		{"altered role", "user:alice", strings.Replace(req.String(), "read-write", "admin", 1), code, errElevationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.activate(tt.client, tt.request, tt.code); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	grant, err := m.activate("user:alice", req.String(), strings.ToUpper(code))
+	if err != nil {
+		t.Fatalf("activate() error = %v", err)
+	}
+	if grant.request.Role != config.RoleReadWrite {
+		t.Errorf("Expected role read-write, got %s", grant.request.Role)
+	}
+	if _, err := m.activate("user:alice", req.String(), code); !errors.Is(err, errElevationNotFound) {
+		t.Errorf("Expected a request to be usable once, got %v", err)
+	}
+
+	if _, ok := m.grant("user:alice"); !ok {
+		t.Error("Expected grant to be in effect")
+	}
+	*now = now.Add(5 * time.Minute)
+	if _, ok := m.grant("user:alice"); ok {
+		t.Error("Expected grant to expire")
+	}
+	m.revoke("user:alice")
+}
+
+func TestElevationRequestExpires(t *testing.T) {
+	m, now := newTestElevationManager(t)
+
+	req, _, _ := m.request("", config.RoleReadOnly, config.RoleAdmin, 0, "rebuild index")
+	if req.Duration != 10*time.Minute {
+		t.Errorf("Expected duration to default to the maximum, got %s", req.Duration)
+	}
+
+	*now = now.Add(2 * time.Minute)
+	if _, err := m.activate("", req.String(), req.ApprovalCode(m.key)); !errors.Is(err, errElevationNotFound) {
+		t.Errorf("Expected expired request to be rejected, got %v", err)
+	}
+}
+
+func TestElevationRequestInvalid(t *testing.T) {
+	m, _ := newTestElevationManager(t)
+
+	tests := []struct {
+		name     string
+		current  config.Role
+		role     config.Role
+		duration time.Duration
+		reason   string
+	}{
+		{"read-only role", config.RoleReadOnly, config.RoleReadOnly, time.Minute, "x"},
+		{"unknown role", config.RoleReadOnly, "root", time.Minute, "x"},
+		{"not higher", config.RoleReadWrite, config.RoleReadWrite, time.Minute, "x"},
+		{"too long", config.RoleReadOnly, config.RoleAdmin, time.Hour, "x"},
+		{"no reason", config.RoleReadOnly, config.RoleAdmin, time.Minute, " "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := m.request("c", tt.current, tt.role, tt.duration, tt.reason); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestParseElevationRequest(t *testing.T) {
+	want := &ElevationRequest{ID: "abc", Role: config.RoleAdmin, Duration: 90 * time.Second, Client: "user:ops:1"}
+	got, err := ParseElevationRequest(want.String())
+	if err != nil {
+		t.Fatalf("ParseElevationRequest() error = %v", err)
+	}
+	if *got != *want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	for _, s := range []string{"", "abc:admin:90", "abc:root:90:c", "abc:admin:-5:c", ":admin:90:c"} {
+		if _, err := ParseElevationRequest(s); err == nil {
+			t.Errorf("Expected error for %q", s)
+		}
+	}
+}
+
+func TestElevateRoleTool(t *testing.T) {
+	var auditBuf bytes.Buffer
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(&auditBuf)
+
+	m, _ := newTestElevationManager(t)
+	s := &Server{
+		config:      config.DefaultConfig(),
+		auditLogger: auditLogger,
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+		elevation:   m,
+	}
+	ctx := audit.WithUser(context.Background(), "alice")
+
+	call := func(args string) map[string]interface{} {
+		t.Helper()
+		params, _ := json.Marshal(ToolsCallParams{Name: elevateRoleTool, Arguments: json.RawMessage(args)})
+		result, rpcErr := s.handleToolsCall(s.resolveRole(ctx), params)
+		if rpcErr != nil {
+			t.Fatalf("Unexpected error: %v", rpcErr)
+		}
+		if result.IsError {
+			t.Fatalf("Unexpected error result: %s", result.Content[0].Text)
+		}
+		var out map[string]interface{}
+		_ = json.Unmarshal([]byte(result.Content[0].Text), &out)
+		return out
+	}
+
+	listed, _ := s.handleToolsList(ctx)
+	if tools := listed.Tools; tools[len(tools)-1].Name != elevateRoleTool {
+		t.Error("Expected elevate_role to be listed")
+	}
+
+	out := call(`{"action":"request","role":"read-write","duration_sec":300,"reason":"fix bad record"}`)
+	request, _ := out["request"].(string)
+	req, err := ParseElevationRequest(request)
+	if err != nil {
+		t.Fatalf("ParseElevationRequest() error = %v", err)
+	}
+	if got := s.config.EffectiveRole(s.resolveRole(ctx)); got != config.RoleReadOnly {
+		t.Errorf("Expected role read-only before approval, got %s", got)
+	}
+
+	call(`{"action":"activate","request":"` + request + `","approval_code":"` + req.ApprovalCode(m.key) + `"}`)
+	if got := s.config.EffectiveRole(s.resolveRole(ctx)); got != config.RoleReadWrite {
+		t.Errorf("Expected role read-write after approval, got %s", got)
+	}
+	if other := audit.WithUser(context.Background(), "bob"); s.config.EffectiveRole(s.resolveRole(other)) != config.RoleReadOnly {
+		t.Error("Expected other clients to keep their role")
+	}
+	if out := call(`{"action":"status"}`); out["elevated"] != true {
+		t.Errorf("Expected status to report elevation, got %v", out)
+	}
+
+	call(`{"action":"revoke"}`)
+	if got := s.config.EffectiveRole(s.resolveRole(ctx)); got != config.RoleReadOnly {
+		t.Errorf("Expected role read-only after revoking, got %s", got)
+	}
+
+	for _, op := range []string{"elevation_request", "elevation_activate", "elevation_revoke"} {
+		if !strings.Contains(auditBuf.String(), `"operation":"`+op+`"`) {
+			t.Errorf("Expected %s audit event", op)
+		}
+	}
+}
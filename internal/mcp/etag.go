@@ -0,0 +1,35 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Each content of a resources/read result carries an ETag, a hash of its
+// text, in its _meta. A client polling a resource sends the ETag it last
+// received as _meta.ifNoneMatch, and if the text is unchanged gets the
+// content back without it, marked notModified, so unchanged JSON isn't
+// transferred again. The resource is still read each time.
+
+// resourceETag returns the ETag of a resource content's text.
+func resourceETag(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:16])
+}
+
+// applyETags sets the ETag of each of result's contents, and drops the
+// text of those whose ETag is ifNoneMatch.
+func applyETags(result *ResourcesReadResult, ifNoneMatch string) {
+	for i := range result.Contents {
+		content := &result.Contents[i]
+		etag := resourceETag(content.Text)
+		content.Meta = map[string]interface{}{"etag": etag}
+		if ifNoneMatch != "" && etag == ifNoneMatch {
+			content.Text = ""
+			content.Meta["notModified"] = true
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestResourceETag(t *testing.T) {
+	s := newAuditQueryTestServer(t, config.RoleAdmin)
+	read := func(ifNoneMatch string) ResourceContent {
+		t.Helper()
+		params, _ := json.Marshal(ResourcesReadParams{URI: auditRecentURI, Meta: ResourcesReadMeta{IfNoneMatch: ifNoneMatch}})
+		result, rpcErr := s.handleResourcesRead(context.Background(), params)
+		if rpcErr != nil {
+			t.Fatalf("Unexpected error: %v", rpcErr)
+		}
+		return result.Contents[0]
+	}
+
+	first := read("")
+	etag, _ := first.Meta["etag"].(string)
+	if etag == "" || first.Text == "" || first.Meta["notModified"] != nil {
+		t.Fatalf("Expected the text and an etag, got %+v", first)
+	}
+
+	unchanged := read(etag)
+	if unchanged.Text != "" || unchanged.Meta["notModified"] != true || unchanged.Meta["etag"] != etag {
+		t.Errorf("Expected an unchanged resource to be returned without text, got %+v", unchanged)
+	}
+
+	if stale := read("0123"); stale.Text != first.Text || stale.Meta["notModified"] != nil {
+		t.Errorf("Expected a stale etag to return the text, got %+v", stale)
+	}
+
+	s.auditLogger.Log(audit.Event{Level: audit.LevelAudit, Category: audit.CategoryWrite, Operation: "put_record", Success: true})
+	changed := read(etag)
+	if changed.Text == "" || changed.Meta["etag"] == etag {
+		t.Errorf("Expected a changed resource to return its text and a new etag, got %+v", changed)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+)
+
+// GRPCServiceName is the fully-qualified gRPC service name. The service exposes
+// a single unary method, Call, whose request and response are
+// google.protobuf.BytesValue messages carrying JSON-RPC payloads:
+//
+//	service MCP {
+//	  rpc Call(google.protobuf.BytesValue) returns (google.protobuf.BytesValue);
+//	}
+const GRPCServiceName = "aerospike.mcp.v1.MCP"
+
+// GRPCServer handles gRPC transport for MCP.
+type GRPCServer struct {
+	server *Server
+	port   int
+}
+
+// mcpService is the handler type registered with the gRPC server.
+type mcpService interface {
+	Call(ctx context.Context, req *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error)
+}
+
+var grpcServiceDesc = grpc.ServiceDesc{
+	ServiceName: GRPCServiceName,
+	HandlerType: (*mcpService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Call",
+			Handler:    grpcCallHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "aerospike_mcp.proto",
+}
+
+// grpcCallHandler decodes a Call request and dispatches it to the service.
+func grpcCallHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(wrapperspb.BytesValue)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(mcpService).Call(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + GRPCServiceName + "/Call",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(mcpService).Call(ctx, req.(*wrapperspb.BytesValue))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
+// NewGRPCServer creates a new gRPC server.
+func NewGRPCServer(server *Server, port int) *GRPCServer {
+	return &GRPCServer{
+		server: server,
+		port:   port,
+	}
+}
+
+// Run starts the gRPC server.
+func (g *GRPCServer) Run(ctx context.Context) error {
+	addr := fmt.Sprintf(":%d", g.port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	grpcServer, err := g.newGRPCServer()
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
+	// Start server in goroutine
+	go func() {
+		slog.Info("gRPC server listening", "addr", addr)
+		if err := grpcServer.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+			slog.Error("gRPC server error", "error", err)
+		}
+	}()
+
+	// Wait for context cancellation
+	<-ctx.Done()
+
+	// Shutdown gracefully, forcing a stop if in-flight calls don't finish
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		grpcServer.Stop()
+	}
+
+	return nil
+}
+
+// newGRPCServer builds the underlying gRPC server with the MCP service registered.
+func (g *GRPCServer) newGRPCServer() (*grpc.Server, error) {
+	var opts []grpc.ServerOption
+	if tlsCfg := g.server.config.ServerTLS; tlsCfg.Enabled {
+		tlsConfig, err := buildServerTLSConfig(tlsCfg)
+		if err != nil {
+			return nil, fmt.Errorf("configuring gRPC TLS: %w", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	opts = append(opts, grpc.UnaryInterceptor(g.authorize))
+
+	grpcServer := grpc.NewServer(opts...)
+	grpcServer.RegisterService(&grpcServiceDesc, g)
+	return grpcServer, nil
+}
+
+// authorize applies the checks the HTTP transports make to each call:
+// the caller's address must be in allowed_cidrs, and it must present an API
+// key or OIDC token in the "authorization" metadata, or a verified TLS
+// client certificate, when authentication is configured. The caller's
+// address identifies it as the client, and its credential as the user.
+func (g *GRPCServer) authorize(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s := g.server
+	var remoteAddr string
+	var tlsState *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			remoteAddr = p.Addr.String()
+		}
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			tlsState = &tlsInfo.State
+		}
+	}
+
+	if len(s.allowed) > 0 && !s.sourceAllowed(remoteAddr) {
+		g.logAuthFailure(ctx, "source_address", info.FullMethod, remoteAddr, "source address not in allowed_cidrs")
+		return nil, status.Error(codes.PermissionDenied, "source address not allowed")
+	}
+	ctx = audit.WithClientID(ctx, remoteAddr)
+
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	// A verified client certificate identifies the client unless it also
+	// presents a bearer credential
+	if user := certIdentity(tlsState); user != "" && header == "" {
+		return handler(withIdentity(ctx, &identity{user: user, method: "mtls"}), req)
+	}
+	if s.auth == nil {
+		return handler(ctx, req)
+	}
+
+	id, reason := s.auth.verifyHeader(ctx, header)
+	if id == nil {
+		g.logAuthFailure(ctx, "authenticate", info.FullMethod, remoteAddr, reason)
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	return handler(withIdentity(ctx, id), req)
+}
+
+// logAuthFailure writes a rejected call to the audit log.
+func (g *GRPCServer) logAuthFailure(ctx context.Context, operation, method, remoteAddr, reason string) {
+	if g.server.auditLogger == nil {
+		return
+	}
+	g.server.auditLogger.LogAuth(ctx, operation, false, map[string]interface{}{
+		"method":      method,
+		"remote_addr": remoteAddr,
+		"reason":      reason,
+	})
+}
+
+// Call processes a single JSON-RPC message. The client's deadline is carried
+// in ctx and bounds the request in addition to the configured request timeout.
+func (g *GRPCServer) Call(ctx context.Context, req *wrapperspb.BytesValue) (*wrapperspb.BytesValue, error) {
+	ctx, span := g.server.traceGRPC(ctx, GRPCServiceName+"/Call")
+	defer span.End()
+
+	response := g.server.processMessage(ctx, req.GetValue())
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	if response == nil {
+		return &wrapperspb.BytesValue{}, nil
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "marshaling response: %v", err)
+	}
+	return wrapperspb.Bytes(data), nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func newTestGRPCConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	return newTestGRPCServerConn(t, &Server{config: config.DefaultConfig()})
+}
+
+func newTestGRPCServerConn(t *testing.T, s *Server) *grpc.ClientConn {
+	t.Helper()
+
+	g := NewGRPCServer(s, 0)
+	grpcServer, err := g.newGRPCServer()
+	if err != nil {
+		t.Fatalf("newGRPCServer() error = %v", err)
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCCall(t *testing.T) {
+	conn := newTestGRPCConn(t)
+
+	req := wrapperspb.Bytes([]byte(`{"jsonrpc":"2.0","id":7,"method":"prompts/list"}`))
+	resp := new(wrapperspb.BytesValue)
+	if err := conn.Invoke(context.Background(), "/"+GRPCServiceName+"/Call", req, resp); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(resp.GetValue(), &parsed); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if parsed["id"] != float64(7) {
+		t.Errorf("Expected id 7, got %v", parsed["id"])
+	}
+	if parsed["error"] != nil {
+		t.Errorf("Unexpected error: %v", parsed["error"])
+	}
+}
+
+func TestGRPCCallDeadlineExceeded(t *testing.T) {
+	conn := newTestGRPCConn(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	time.Sleep(time.Millisecond)
+
+	req := wrapperspb.Bytes([]byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+	err := conn.Invoke(ctx, "/"+GRPCServiceName+"/Call", req, new(wrapperspb.BytesValue))
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGRPCAuthentication(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleAdmin
+	cfg.Auth = config.AuthConfig{APIKeys: []config.APIKey{{Name: "ci", Key: "secret"}}}
+	cfg.ClientRoles = map[string]config.Role{"ci": config.RoleReadOnly}
+	s := &Server{config: cfg, auth: newAuthenticator(cfg.Auth), tools: tools.NewRegistry(nil, cfg)}
+	conn := newTestGRPCServerConn(t, s)
+
+	call := func(ctx context.Context) (map[string]interface{}, error) {
+		req := wrapperspb.Bytes([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		resp := new(wrapperspb.BytesValue)
+		if err := conn.Invoke(ctx, "/"+GRPCServiceName+"/Call", req, resp); err != nil {
+			return nil, err
+		}
+		var parsed map[string]interface{}
+		err := json.Unmarshal(resp.GetValue(), &parsed)
+		return parsed, err
+	}
+
+	if _, err := call(context.Background()); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without credentials, got %v", err)
+	}
+	bad := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	if _, err := call(bad); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated with a wrong key, got %v", err)
+	}
+
+	// The key's name selects its client_roles entry, not the process-wide role
+	good := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	parsed, err := call(good)
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	for _, tool := range parsed["result"].(map[string]interface{})["tools"].([]interface{}) {
+		if name := tool.(map[string]interface{})["name"]; name == "put_record" || name == setReadOnlyTool {
+			t.Errorf("Expected the read-only client not to be offered %s", name)
+		}
+	}
+}
+
+func TestGRPCAllowedCIDRs(t *testing.T) {
+	allowed, _ := (&config.Config{AllowedCIDRs: []string{"10.0.0.0/8"}}).AllowedNetworks()
+	s := &Server{config: config.DefaultConfig(), allowed: allowed}
+	conn := newTestGRPCServerConn(t, s)
+
+	req := wrapperspb.Bytes([]byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+	err := conn.Invoke(context.Background(), "/"+GRPCServiceName+"/Call", req, new(wrapperspb.BytesValue))
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied from an address outside allowed_cidrs, got %v", err)
+	}
+}
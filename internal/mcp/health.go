@@ -0,0 +1,196 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+)
+
+// Health statuses. A degraded server still serves requests, but something
+// it depends on, such as an audit sink, is failing.
+const (
+	healthHealthy   = "healthy"
+	healthDegraded  = "degraded"
+	healthUnhealthy = "unhealthy"
+)
+
+// healthCheck is the outcome of checking one dependency.
+type healthCheck struct {
+	Status    string  `json:"status"`
+	Error     string  `json:"error,omitempty"`
+	Nodes     int     `json:"nodes,omitempty"`
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+}
+
+// healthReport is the body of the /health endpoint.
+type healthReport struct {
+	Status    string                 `json:"status"`
+	Reasons   []string               `json:"reasons,omitempty"`
+	Checks    map[string]healthCheck `json:"checks"`
+	Transport string                 `json:"transport"`
+	Clients   *int                   `json:"clients,omitempty"`
+	Server    string                 `json:"server"`
+	Version   string                 `json:"version"`
+}
+
+// errDegraded is returned for tool calls that need the cluster while the
+// client isn't connected to it.
+var errDegraded = fmt.Errorf("server is degraded, retrying the cluster connection: %w", aerospike.ErrNotConnected)
+
+// handleHealthEndpoints adds /health, /ready, and /live to a mux. They are
+// not authenticated, so orchestrators can probe them. clients, if not nil,
+// reports the transport's connected clients.
+func (s *Server) handleHealthEndpoints(mux *http.ServeMux, clients func() int) {
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		report := s.checkHealth(r.Context())
+		if clients != nil {
+			n := clients()
+			report.Clients = &n
+		}
+		code := http.StatusOK
+		if report.Status == healthUnhealthy {
+			code = http.StatusServiceUnavailable
+		}
+		writeHealthJSON(w, code, report)
+	})
+	mux.HandleFunc("/ready", s.handleReady)
+	mux.HandleFunc("/live", handleLive)
+}
+
+// handleReady reports whether the server can serve requests, which requires
+// a reachable cluster.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	check := s.checkCluster(r.Context())
+	if check.Status != healthHealthy {
+		writeHealthJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"status": "not_ready",
+			"reason": "aerospike: " + check.Error,
+		})
+		return
+	}
+	writeHealthJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// handleLive reports that the process is running and serving HTTP. It
+// checks nothing else, so a cluster outage doesn't get the server restarted.
+func handleLive(w http.ResponseWriter, r *http.Request) {
+	writeHealthJSON(w, http.StatusOK, map[string]string{"status": "alive"})
+}
+
+func writeHealthJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// checkHealth checks the cluster and the audit log. The server is unhealthy
+// if the cluster is unreachable (degraded with lazy_connect), and degraded
+// if auditing is failing.
+func (s *Server) checkHealth(ctx context.Context) healthReport {
+	report := healthReport{
+		Status:    healthHealthy,
+		Checks:    make(map[string]healthCheck),
+		Transport: s.config.Transport,
+		Server:    ServerName,
+		Version:   ServerVersion,
+	}
+
+	// With lazy_connect the server keeps running while the client retries,
+	// so a missing cluster only degrades it
+	cluster := s.checkCluster(ctx)
+	if cluster.Status != healthHealthy && s.config.LazyConnect {
+		cluster.Status = healthDegraded
+	}
+	report.Checks["aerospike"] = cluster
+	if cluster.Status != healthHealthy {
+		report.Status = cluster.Status
+		report.Reasons = append(report.Reasons, "aerospike: "+cluster.Error)
+	}
+
+	if s.config.Audit.Enabled {
+		auditCheck := healthCheck{Status: healthHealthy}
+		if s.auditLogger == nil {
+			auditCheck = healthCheck{Status: healthDegraded, Error: "audit logger failed to start"}
+		} else if failing := s.auditLogger.FailingSinks(); len(failing) > 0 {
+			auditCheck = healthCheck{Status: healthDegraded, Error: "failing sinks: " + strings.Join(failing, ", ")}
+		}
+		report.Checks["audit"] = auditCheck
+		if auditCheck.Status != healthHealthy {
+			report.Reasons = append(report.Reasons, "audit: "+auditCheck.Error)
+			if report.Status == healthHealthy {
+				report.Status = healthDegraded
+			}
+		}
+	}
+
+	return report
+}
+
+// checkCluster pings the cluster, giving up after health.timeout_ms.
+func (s *Server) checkCluster(ctx context.Context) healthCheck {
+	if s.ping == nil {
+		return healthCheck{Status: healthUnhealthy, Error: "no cluster client"}
+	}
+
+	timeout := time.Duration(s.config.Health.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	nodes, err := s.ping(ctx)
+	check := healthCheck{
+		Status:    healthHealthy,
+		Nodes:     nodes,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		check.Status = healthUnhealthy
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// serveHealth serves the health endpoints on health.port until ctx is done.
+// Like the metrics listener, it is restricted to allowed_cidrs but not
+// authenticated.
+func (s *Server) serveHealth(ctx context.Context) error {
+	addr := fmt.Sprintf(":%d", s.config.Health.Port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	s.handleHealthEndpoints(mux, nil)
+	httpServer := &http.Server{
+		Handler:           s.restrictSource(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Health server listening", "addr", addr)
+	if err := httpServer.Serve(listener); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	reachable := func(context.Context) (int, error) { return 3, nil }
+	unreachable := func(context.Context) (int, error) { return 0, errors.New("connection refused") }
+
+	tests := []struct {
+		name       string
+		ping       func(context.Context) (int, error)
+		audit      bool
+		lazy       bool
+		path       string
+		wantCode   int
+		wantStatus string
+	}{
+		{"healthy", reachable, false, false, "/health", http.StatusOK, "healthy"},
+		{"degraded without audit logger", reachable, true, false, "/health", http.StatusOK, "degraded"},
+		{"unhealthy", unreachable, false, false, "/health", http.StatusServiceUnavailable, "unhealthy"},
+		{"degraded while connecting lazily", unreachable, false, true, "/health", http.StatusOK, "degraded"},
+		{"no client", nil, false, false, "/health", http.StatusServiceUnavailable, "unhealthy"},
+		{"ready", reachable, false, false, "/ready", http.StatusOK, "ready"},
+		{"not ready", unreachable, false, false, "/ready", http.StatusServiceUnavailable, "not_ready"},
+		{"not ready while connecting lazily", unreachable, false, true, "/ready", http.StatusServiceUnavailable, "not_ready"},
+		{"live without cluster", unreachable, false, false, "/live", http.StatusOK, "alive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Audit.Enabled = tt.audit
+			cfg.LazyConnect = tt.lazy
+			s := &Server{config: cfg, ping: tt.ping}
+
+			mux := http.NewServeMux()
+			s.handleHealthEndpoints(mux, func() int { return 2 })
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected status code %d, got %d", tt.wantCode, rec.Code)
+			}
+			var body struct {
+				Status  string   `json:"status"`
+				Reasons []string `json:"reasons"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected JSON body, got %q", rec.Body.String())
+			}
+			if body.Status != tt.wantStatus {
+				t.Errorf("Expected status %s, got %s", tt.wantStatus, body.Status)
+			}
+			if tt.path == "/health" && (tt.wantStatus == "healthy") != (len(body.Reasons) == 0) {
+				t.Errorf("Expected reasons only when not healthy, got %v", body.Reasons)
+			}
+		})
+	}
+}
+
+func TestCheckHealthReport(t *testing.T) {
+	s := &Server{
+		config: config.DefaultConfig(),
+		ping:   func(context.Context) (int, error) { return 3, nil },
+	}
+	report := s.checkHealth(context.Background())
+
+	cluster, ok := report.Checks["aerospike"]
+	if !ok {
+		t.Fatal("Expected an aerospike check")
+	}
+	if cluster.Nodes != 3 {
+		t.Errorf("Expected 3 nodes, got %d", cluster.Nodes)
+	}
+	if report.Server != ServerName || report.Version != ServerVersion {
+		t.Errorf("Expected server %s %s, got %s %s", ServerName, ServerVersion, report.Server, report.Version)
+	}
+}
+
+func TestCheckClusterTimeout(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Health.TimeoutMs = 10
+	s := &Server{config: cfg, ping: func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}}
+
+	check := s.checkCluster(context.Background())
+	if check.Status != healthUnhealthy || check.Error == "" {
+		t.Errorf("Expected an unhealthy check after the timeout, got %+v", check)
+	}
+}
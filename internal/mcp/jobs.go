@@ -0,0 +1,466 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/resources"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// Job tool names.
+const (
+	listJobsTool  = "list_server_jobs"
+	getJobTool    = "get_job"
+	cancelJobTool = "cancel_job"
+)
+
+// Job resources: the caller's jobs, and one job by ID.
+const (
+	jobsURI       = "aerospike://jobs"
+	jobURIPrefix  = "aerospike://jobs/"
+	jobStatusDesc = "running, succeeded, failed, or cancelled"
+)
+
+// asyncTools are the tools that run as a background job when called with
+// "async": true.
+var asyncTools = map[string]bool{
+	"scan_set":         true,
+	"query_records":    true,
+	"aggregate_simple": true,
+	"sql_query":        true,
+	"truncate_set":     true,
+	"create_index":     true,
+	"execute_udf":      true,
+}
+
+// Job states.
+const (
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+	jobCancelled = "cancelled"
+)
+
+var (
+	errJobNotFound = errors.New("job not found or expired")
+	errJobsBusy    = errors.New("too many jobs running")
+)
+
+// job is a tool call running, or finished, in the background.
+type job struct {
+	id         string
+	tool       string
+	owner      string // audit.ClientKey of the caller
+	status     string
+	startedAt  time.Time
+	finishedAt time.Time
+	progress   *aerospike.Progress
+	cancel     context.CancelFunc
+	result     json.RawMessage
+	err        error
+}
+
+// JobInfo describes a job for the job tools and resources. Result is only
+// filled in by get_job once the job has succeeded.
+type JobInfo struct {
+	ID               string              `json:"job_id"`
+	URI              string              `json:"uri"`
+	Tool             string              `json:"tool"`
+	Status           string              `json:"status"`
+	StartedAt        time.Time           `json:"started_at"`
+	FinishedAt       *time.Time          `json:"finished_at,omitempty"`
+	ElapsedMs        int64               `json:"elapsed_ms"`
+	RecordsProcessed int64               `json:"records_processed"`
+	Error            string              `json:"error,omitempty"`
+	ErrorCode        aerospike.ErrorCode `json:"error_code,omitempty"`
+	Result           json.RawMessage     `json:"result,omitempty"`
+}
+
+// jobManager runs background jobs, bounding how many run at once, and keeps
+// finished jobs until they expire or are pushed out by newer ones.
+type jobManager struct {
+	cfg config.JobsConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	order   []string // job IDs, oldest first
+	running int
+	wg      sync.WaitGroup
+}
+
+// newJobManager returns a job manager, or nil if jobs are disabled.
+func newJobManager(cfg config.JobsConfig) *jobManager {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &jobManager{cfg: cfg, now: time.Now, jobs: make(map[string]*job)}
+}
+
+// start runs fn in the background as a job owned by owner and returns the
+// job at once. The job keeps ctx's values, such as the caller's identity,
+// but not its cancellation; it stops when cancelled or after the job
+// timeout. fn returns the job's JSON result.
+func (m *jobManager) start(ctx context.Context, tool, owner string, fn func(ctx context.Context, id string) ([]byte, error)) (JobInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evictLocked()
+	if m.running >= m.cfg.MaxRunning {
+		return JobInfo{}, fmt.Errorf("%w: %d of %d, wait for one to finish or cancel one", errJobsBusy, m.running, m.cfg.MaxRunning)
+	}
+
+	jobCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Duration(m.cfg.TimeoutSec)*time.Second)
+	jobCtx, progress := aerospike.WithProgress(jobCtx)
+	j := &job{
+		id:        uuid.New().String(),
+		tool:      tool,
+		owner:     owner,
+		status:    jobRunning,
+		startedAt: m.now(),
+		progress:  progress,
+		cancel:    cancel,
+	}
+	m.jobs[j.id] = j
+	m.order = append(m.order, j.id)
+	m.running++
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer cancel()
+		result, err := fn(jobCtx, j.id)
+		m.finish(j, result, err, jobCtx.Err())
+	}()
+
+	return m.infoLocked(j, false), nil
+}
+
+// finish records the outcome of a job.
+func (m *jobManager) finish(j *job, result []byte, err, ctxErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j.finishedAt = m.now()
+	switch {
+	case err != nil && errors.Is(ctxErr, context.Canceled):
+		j.status = jobCancelled
+		j.err = err
+	case err != nil:
+		j.status = jobFailed
+		j.err = err
+	default:
+		j.status = jobSucceeded
+		j.result = result
+	}
+	m.running--
+}
+
+// get returns the job with the given ID, including its result, if it is
+// visible to owner.
+func (m *jobManager) get(id, owner string, all bool) (JobInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evictLocked()
+	j, ok := m.jobs[id]
+	if !ok || !(all || j.owner == owner) {
+		return JobInfo{}, fmt.Errorf("%w: %s", errJobNotFound, id)
+	}
+	return m.infoLocked(j, true), nil
+}
+
+// list returns the jobs visible to owner, newest first, optionally only
+// those with the given status.
+func (m *jobManager) list(owner string, all bool, status string) []JobInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evictLocked()
+	infos := []JobInfo{}
+	for i := len(m.order) - 1; i >= 0; i-- {
+		j := m.jobs[m.order[i]]
+		if (all || j.owner == owner) && (status == "" || j.status == status) {
+			infos = append(infos, m.infoLocked(j, false))
+		}
+	}
+	return infos
+}
+
+// cancel stops a running job visible to owner. The job reports cancelled
+// once its operation has stopped.
+func (m *jobManager) cancel(id, owner string, all bool) (JobInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, ok := m.jobs[id]
+	if !ok || !(all || j.owner == owner) {
+		return JobInfo{}, fmt.Errorf("%w: %s", errJobNotFound, id)
+	}
+	if j.status != jobRunning {
+		return JobInfo{}, fmt.Errorf("job %s is no longer running: %s", id, j.status)
+	}
+	j.cancel()
+	return m.infoLocked(j, false), nil
+}
+
+// stop cancels every running job and waits for them to finish.
+func (m *jobManager) stop() {
+	m.mu.Lock()
+	for _, j := range m.jobs {
+		if j.status == jobRunning {
+			j.cancel()
+		}
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+}
+
+// infoLocked describes j, with its result if withResult is set.
+func (m *jobManager) infoLocked(j *job, withResult bool) JobInfo {
+	info := JobInfo{
+		ID:               j.id,
+		URI:              jobURIPrefix + j.id,
+		Tool:             j.tool,
+		Status:           j.status,
+		StartedAt:        j.startedAt,
+		RecordsProcessed: j.progress.Records(),
+	}
+	end := m.now()
+	if j.status != jobRunning {
+		finished := j.finishedAt
+		info.FinishedAt = &finished
+		end = finished
+	}
+	info.ElapsedMs = end.Sub(j.startedAt).Milliseconds()
+	if j.err != nil {
+		info.Error = j.err.Error()
+		info.ErrorCode = errorCode(j.err)
+	}
+	if withResult {
+		info.Result = j.result
+	}
+	return info
+}
+
+// evictLocked removes finished jobs past their retention, and the oldest
+// finished jobs beyond max_finished. Running jobs are never removed.
+func (m *jobManager) evictLocked() {
+	retention := time.Duration(m.cfg.RetentionSec) * time.Second
+	now := m.now()
+
+	finished := 0
+	for _, id := range m.order {
+		if m.jobs[id].status != jobRunning {
+			finished++
+		}
+	}
+
+	kept := m.order[:0]
+	for _, id := range m.order {
+		j := m.jobs[id]
+		if j.status != jobRunning && (now.Sub(j.finishedAt) >= retention || finished > m.cfg.MaxFinished) {
+			delete(m.jobs, id)
+			finished--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	m.order = kept
+}
+
+// isAsync reports whether tool arguments ask for the call to run as a job.
+func isAsync(args json.RawMessage) bool {
+	var a struct {
+		Async bool `json:"async"`
+	}
+	_ = json.Unmarshal(args, &a)
+	return a.Async
+}
+
+// startJob runs an async tool call as a job, once the caller's role allows
+// the tool. records is the write quota reserved for the call, returned if
+// the job fails. The job's outcome is audited when it finishes.
+func (s *Server) startJob(ctx context.Context, name string, args json.RawMessage, records int) (interface{}, error) {
+	if err := s.tools.Authorize(ctx, name, args); err != nil {
+		return nil, err
+	}
+	client := audit.ClientKey(ctx)
+	return s.jobs.start(ctx, name, client, func(ctx context.Context, id string) ([]byte, error) {
+		start := time.Now()
+		data, err := s.runJob(ctx, name, args)
+		if err != nil {
+			s.writeQuota.Release(client, records)
+			slog.WarnContext(ctx, "Job failed", "job_id", id, "duration", time.Since(start), "error", err)
+		} else {
+			slog.InfoContext(ctx, "Job completed", "job_id", id, "duration", time.Since(start))
+		}
+		if s.auditLogger != nil {
+			s.auditLogger.Log(audit.Event{
+				Level:     audit.LevelAudit,
+				Category:  toolCategory(name),
+				Operation: name,
+				User:      audit.UserFromContext(ctx),
+				ClientID:  audit.ClientIDFromContext(ctx),
+				RequestID: audit.RequestIDFromContext(ctx),
+				Duration:  time.Since(start),
+				Success:   err == nil,
+				Error:     errorString(err),
+				Details:   map[string]interface{}{"job_id": id},
+			})
+		}
+		return data, err
+	})
+}
+
+// runJob makes a job's tool call and returns its redacted JSON result.
+func (s *Server) runJob(ctx context.Context, name string, args json.RawMessage) ([]byte, error) {
+	result, err := s.tools.Call(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	namespace, set := s.requestScope(args)
+	return s.redactResult(ctx, name, namespace, set, data)
+}
+
+// jobArgs are the arguments of the job tools.
+type jobArgs struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+}
+
+// callJobTool handles list_server_jobs, get_job, and cancel_job. Admins see
+// every client's jobs; other clients see their own.
+func (s *Server) callJobTool(ctx context.Context, name string, raw json.RawMessage) (interface{}, error) {
+	var args jobArgs
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	owner, all := audit.ClientKey(ctx), s.config.GrantedRole(ctx).CanAdmin()
+
+	switch name {
+	case listJobsTool:
+		jobs := s.jobs.list(owner, all, args.Status)
+		return map[string]interface{}{"jobs": jobs, "count": len(jobs)}, nil
+	case getJobTool:
+		return s.jobs.get(args.JobID, owner, all)
+	default:
+		return s.jobs.cancel(args.JobID, owner, all)
+	}
+}
+
+// isJobURI reports whether uri names the jobs resource or one job.
+func isJobURI(uri string) bool {
+	return uri == jobsURI || strings.HasPrefix(uri, jobURIPrefix)
+}
+
+// readJobResource handles reads of aerospike://jobs and
+// aerospike://jobs/{id}.
+func (s *Server) readJobResource(ctx context.Context, uri string) (string, error) {
+	owner, all := audit.ClientKey(ctx), s.config.GrantedRole(ctx).CanAdmin()
+
+	var value interface{}
+	if uri == jobsURI {
+		value = map[string]interface{}{"jobs": s.jobs.list(owner, all, "")}
+	} else {
+		info, err := s.jobs.get(strings.TrimPrefix(uri, jobURIPrefix), owner, all)
+		if err != nil {
+			return "", err
+		}
+		value = info
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// jobsResource describes the jobs resource.
+func jobsResource() resources.ResourceDefinition {
+	return resources.ResourceDefinition{
+		URI:         jobsURI,
+		Name:        "Background Jobs",
+		Description: "Background jobs started with \"async\": true, newest first; read aerospike://jobs/{job_id} for one job and its result",
+		MimeType:    "application/json",
+	}
+}
+
+// withAsync adds the async argument to the definitions of tools that can
+// run as jobs.
+func withAsync(definitions []tools.ToolDefinition) []tools.ToolDefinition {
+	for i, def := range definitions {
+		if !asyncTools[def.Name] {
+			continue
+		}
+		properties := make(map[string]tools.Property, len(def.InputSchema.Properties)+1)
+		for name, property := range def.InputSchema.Properties {
+			properties[name] = property
+		}
+		properties["async"] = tools.Property{
+			Type:        "boolean",
+			Description: "Run in the background and return a job ID at once; follow it with get_job",
+		}
+		definitions[i].InputSchema.Properties = properties
+	}
+	return definitions
+}
+
+// jobToolDefinitions describes the job tools.
+func jobToolDefinitions() []tools.ToolDefinition {
+	return []tools.ToolDefinition{
+		{
+			Name:        listJobsTool,
+			Description: "List background jobs started with \"async\": true, newest first, with their status and progress",
+			InputSchema: tools.InputSchema{
+				Type: "object",
+				Properties: map[string]tools.Property{
+					"status": {Type: "string", Description: "Only list jobs in this state: " + jobStatusDesc},
+				},
+			},
+		},
+		{
+			Name:        getJobTool,
+			Description: "Get a background job's status, progress, and, once it has succeeded, its result",
+			InputSchema: tools.InputSchema{
+				Type: "object",
+				Properties: map[string]tools.Property{
+					"job_id": {Type: "string", Description: "Job ID returned when the job was started"},
+				},
+				Required: []string{"job_id"},
+			},
+		},
+		{
+			Name:        cancelJobTool,
+			Description: "Cancel a running background job",
+			InputSchema: tools.InputSchema{
+				Type: "object",
+				Properties: map[string]tools.Property{
+					"job_id": {Type: "string", Description: "Job ID returned when the job was started"},
+				},
+				Required: []string{"job_id"},
+			},
+		},
+	}
+}
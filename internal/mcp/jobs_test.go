@@ -0,0 +1,218 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func testJobManager() *jobManager {
+	return newJobManager(config.JobsConfig{Enabled: true, MaxRunning: 2, MaxFinished: 2, RetentionSec: 60, TimeoutSec: 60})
+}
+
+// waitForJob waits until the job is no longer running.
+func waitForJob(t *testing.T, m *jobManager, id string) JobInfo {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		info, err := m.get(id, "", true)
+		if err != nil {
+			t.Fatalf("get(%s) error = %v", id, err)
+		}
+		if info.Status != jobRunning {
+			return info
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Job %s still running", id)
+	return JobInfo{}
+}
+
+func TestJobOutcomes(t *testing.T) {
+	tests := []struct {
+		name       string
+		fn         func(ctx context.Context, id string) ([]byte, error)
+		cancel     bool
+		wantStatus string
+		wantResult string
+	}{
+		{
+			name:       "succeeded",
+			fn:         func(ctx context.Context, id string) ([]byte, error) { return []byte(`{"count":3}`), nil },
+			wantStatus: jobSucceeded,
+			wantResult: `{"count":3}`,
+		},
+		{
+			name:       "failed",
+			fn:         func(ctx context.Context, id string) ([]byte, error) { return nil, errors.New("scan failed") },
+			wantStatus: jobFailed,
+		},
+		{
+			name: "cancelled",
+			fn: func(ctx context.Context, id string) ([]byte, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+			cancel:     true,
+			wantStatus: jobCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testJobManager()
+			started, err := m.start(context.Background(), "scan_set", "user:alice", tt.fn)
+			if err != nil {
+				t.Fatalf("start() error = %v", err)
+			}
+			if started.Status != jobRunning || started.URI != jobURIPrefix+started.ID {
+				t.Errorf("Expected a running job with its URI, got %+v", started)
+			}
+			if tt.cancel {
+				if _, err := m.cancel(started.ID, "user:alice", false); err != nil {
+					t.Fatalf("cancel() error = %v", err)
+				}
+			}
+
+			info := waitForJob(t, m, started.ID)
+			if info.Status != tt.wantStatus {
+				t.Errorf("Expected status %s, got %s (%s)", tt.wantStatus, info.Status, info.Error)
+			}
+			if string(info.Result) != tt.wantResult {
+				t.Errorf("Expected result %q, got %q", tt.wantResult, info.Result)
+			}
+			if info.FinishedAt == nil {
+				t.Error("Expected a finish time")
+			}
+			if _, err := m.cancel(started.ID, "user:alice", false); err == nil {
+				t.Error("Expected cancelling a finished job to fail")
+			}
+		})
+	}
+}
+
+func TestJobOwnership(t *testing.T) {
+	m := testJobManager()
+	done := func(ctx context.Context, id string) ([]byte, error) { return []byte(`{}`), nil }
+	alice, _ := m.start(context.Background(), "scan_set", "user:alice", done)
+	bob, _ := m.start(context.Background(), "truncate_set", "user:bob", done)
+	waitForJob(t, m, alice.ID)
+	waitForJob(t, m, bob.ID)
+
+	if jobs := m.list("user:alice", false, ""); len(jobs) != 1 || jobs[0].ID != alice.ID {
+		t.Errorf("Expected alice to see only her job, got %+v", jobs)
+	}
+	if jobs := m.list("user:alice", true, ""); len(jobs) != 2 || jobs[0].ID != bob.ID {
+		t.Errorf("Expected an admin to see both jobs, newest first, got %+v", jobs)
+	}
+	if _, err := m.get(bob.ID, "user:alice", false); !errors.Is(err, errJobNotFound) {
+		t.Errorf("Expected alice not to find bob's job, got %v", err)
+	}
+	if jobs := m.list("user:alice", true, jobFailed); len(jobs) != 0 {
+		t.Errorf("Expected no failed jobs, got %+v", jobs)
+	}
+}
+
+func TestJobLimits(t *testing.T) {
+	m := testJobManager()
+	release := make(chan struct{})
+	block := func(ctx context.Context, id string) ([]byte, error) {
+		<-release
+		return []byte(`{}`), nil
+	}
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		info, err := m.start(context.Background(), "scan_set", "", block)
+		if err != nil {
+			t.Fatalf("start() error = %v", err)
+		}
+		ids = append(ids, info.ID)
+	}
+	if _, err := m.start(context.Background(), "scan_set", "", block); !errors.Is(err, errJobsBusy) {
+		t.Errorf("Expected a third job to be refused, got %v", err)
+	}
+	close(release)
+	for _, id := range ids {
+		waitForJob(t, m, id)
+	}
+
+	// A third finished job pushes out the oldest
+	info, err := m.start(context.Background(), "scan_set", "", block)
+	if err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	waitForJob(t, m, info.ID)
+	if jobs := m.list("", true, ""); len(jobs) != 2 {
+		t.Errorf("Expected 2 finished jobs kept, got %d", len(jobs))
+	}
+	if _, err := m.get(ids[0], "", true); !errors.Is(err, errJobNotFound) {
+		t.Errorf("Expected the oldest job to be evicted, got %v", err)
+	}
+
+	// Finished jobs expire after the retention period
+	now := time.Now().Add(time.Minute)
+	m.now = func() time.Time { return now }
+	if jobs := m.list("", true, ""); len(jobs) != 0 {
+		t.Errorf("Expected expired jobs to be removed, got %d", len(jobs))
+	}
+}
+
+func TestJobStop(t *testing.T) {
+	m := testJobManager()
+	info, _ := m.start(context.Background(), "scan_set", "", func(ctx context.Context, id string) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	m.stop()
+
+	if got, _ := m.get(info.ID, "", true); got.Status != jobCancelled {
+		t.Errorf("Expected stop to cancel running jobs, got %s", got.Status)
+	}
+}
+
+func TestWithAsync(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleAdmin
+	s := &Server{config: cfg, tools: tools.NewRegistry(nil, cfg), notifier: newNotifier(), jobs: testJobManager()}
+	result, _ := s.handleToolsList(context.Background())
+
+	found := map[string]bool{}
+	for _, tool := range result.Tools {
+		found[tool.Name] = true
+		_, async := tool.InputSchema.Properties["async"]
+		if async != asyncTools[tool.Name] {
+			t.Errorf("Expected async argument on %s to be %v", tool.Name, asyncTools[tool.Name])
+		}
+	}
+	for _, name := range []string{"scan_set", "truncate_set", listJobsTool, getJobTool, cancelJobTool} {
+		if !found[name] {
+			t.Errorf("Expected %s to be listed", name)
+		}
+	}
+}
+
+func TestIsAsync(t *testing.T) {
+	tests := []struct {
+		args string
+		want bool
+	}{
+		{`{"namespace": "test", "async": true}`, true},
+		{`{"namespace": "test", "async": false}`, false},
+		{`{"namespace": "test"}`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := isAsync(json.RawMessage(tt.args)); got != tt.want {
+			t.Errorf("isAsync(%s) = %v, expected %v", tt.args, got, tt.want)
+		}
+	}
+}
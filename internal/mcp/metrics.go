@@ -0,0 +1,62 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// handleMetrics adds the metrics endpoint to a transport's mux if metrics
+// are enabled and not served on their own listener. It requires the same
+// authentication as the transport's other endpoints.
+func (s *Server) handleMetrics(mux *http.ServeMux) {
+	if s.metrics == nil || s.config.Metrics.Port != 0 {
+		return
+	}
+	mux.Handle(s.metricsPath(), s.authenticate(s.metrics.Handler()))
+}
+
+// serveMetrics serves the metrics endpoint on metrics.port until ctx is
+// done. The listener is restricted to allowed_cidrs but not authenticated,
+// as scrapers usually can't present credentials.
+func (s *Server) serveMetrics(ctx context.Context) error {
+	addr := fmt.Sprintf(":%d", s.config.Metrics.Port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(s.metricsPath(), s.metrics.Handler())
+	httpServer := &http.Server{
+		Handler:           s.restrictSource(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Metrics server listening", "addr", addr, "path", s.metricsPath())
+	if err := httpServer.Serve(listener); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// metricsPath returns the path the metrics are served on.
+func (s *Server) metricsPath() string {
+	if s.config.Metrics.Path == "" {
+		return "/metrics"
+	}
+	return s.config.Metrics.Path
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/metrics"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestToolCallMetrics(t *testing.T) {
+	s := &Server{
+		config: config.DefaultConfig(),
+		rateLimiter: audit.NewClientRateLimiter(true, map[audit.Category]audit.RateLimitConfig{
+			audit.CategoryWrite: {RequestsPerSec: 0.5, BurstSize: 1},
+		}),
+		validator: audit.NewValidator(audit.DefaultValidatorConfig()),
+		metrics:   metrics.New(),
+	}
+	s.rateLimiter.Allow("", audit.CategoryWrite)
+
+	_, _ = s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"put_record","arguments":{"namespace":"test","key":"k","bins":{"a":1}}}`))
+	_, _ = s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"get_record","arguments":{"namespace":"test"}}`))
+
+	var buf bytes.Buffer
+	_ = s.metrics.Write(&buf)
+	for _, line := range []string{
+		`aerospike_mcp_tool_calls_total{outcome="rate_limited",tool="put_record"} 1`,
+		`aerospike_mcp_tool_calls_total{outcome="invalid",tool="get_record"} 1`,
+		`aerospike_mcp_rate_limit_rejections_total{category="write"} 1`,
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Transport = "sse"
+	cfg.Metrics = config.MetricsConfig{Enabled: true, Path: "/metrics"}
+	s := &Server{
+		config:   cfg,
+		sessions: newSessionManager(cfg.Sessions),
+		auth: newAuthenticator(config.AuthConfig{
+			APIKeys: []config.APIKey{{Name: "prometheus", Key: "scrape-key"}},
+		}),
+		metrics: metrics.New(),
+	}
+	s.metrics.ConnectedClients("sse", s.sessions.count)
+	_, _, _ = s.sessions.create("")
+	handler := NewSSEServer(s, 0).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without credentials, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), `aerospike_mcp_connected_clients{transport="sse"} 1`) {
+		t.Errorf("Expected connected clients gauge, got:\n%s", rec.Body.String())
+	}
+
+	// A separate listener takes the endpoint off the transport
+	cfg.Metrics.Port = 9464
+	rec = httptest.NewRecorder()
+	NewSSEServer(s, 0).Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 with a separate metrics listener, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+)
+
+// Notification represents a JSON-RPC notification sent by the server.
+type Notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// LoggingMessageParams represents the notifications/message parameters.
+type LoggingMessageParams struct {
+	Level  string      `json:"level"`
+	Logger string      `json:"logger,omitempty"`
+	Data   interface{} `json:"data"`
+}
+
+// notifier delivers server-initiated notifications to connected clients.
+// Transports that can push messages subscribe each connection with a send
+// function, which must not block.
+type notifier struct {
+	mu      sync.Mutex
+	clients map[string]func([]byte)
+}
+
+// newNotifier creates a notifier with no subscribers.
+func newNotifier() *notifier {
+	return &notifier{clients: make(map[string]func([]byte))}
+}
+
+// subscribe delivers notifications to the connection id until the returned
+// function is called.
+func (n *notifier) subscribe(id string, send func([]byte)) func() {
+	if n == nil {
+		return func() {}
+	}
+	n.mu.Lock()
+	n.clients[id] = send
+	n.mu.Unlock()
+
+	return func() {
+		n.mu.Lock()
+		delete(n.clients, id)
+		n.mu.Unlock()
+	}
+}
+
+// count returns the number of subscribed connections.
+func (n *notifier) count() int {
+	if n == nil {
+		return 0
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.clients)
+}
+
+// notify sends a notification to every subscribed connection.
+func (n *notifier) notify(method string, params interface{}) {
+	if n == nil {
+		return
+	}
+	message, err := json.Marshal(Notification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		slog.Error("Error marshaling notification", "method", method, "error", err)
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, send := range n.clients {
+		send(message)
+	}
+}
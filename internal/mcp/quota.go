@@ -0,0 +1,70 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+)
+
+// quotaRecords returns the number of records a tool call may modify or
+// delete, to count against the client's write quota. Dry runs modify
+// nothing. UDFs are counted because they can write to the record.
+func (s *Server) quotaRecords(name string, args json.RawMessage) int {
+	var a struct {
+		DryRun     bool              `json:"dry_run"`
+		Operations []json.RawMessage `json:"operations"`
+	}
+	_ = json.Unmarshal(args, &a)
+
+	switch name {
+	case "put_record", "delete_record", "operate", "batch_write":
+		if a.DryRun || s.config.DryRun {
+			return 0
+		}
+		if name == "batch_write" {
+			return len(a.Operations)
+		}
+		return 1
+	case "execute_udf":
+		return 1
+	}
+	return 0
+}
+
+// quotaExceededResult returns the error result for a tool call over the
+// client's write quota, including when the quota resets.
+func quotaExceededResult(err error) *ToolsCallResult {
+	var quotaErr *audit.QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		return &ToolsCallResult{
+			Content: []ContentBlock{{Type: "text", Text: fmt.Sprintf("Error: %v", err)}},
+			IsError: true,
+		}
+	}
+
+	seconds := int(math.Ceil(time.Until(quotaErr.ResetAt).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &ToolsCallResult{
+		Content: []ContentBlock{
+			{Type: "text", Text: fmt.Sprintf("Error: %v", err)},
+		},
+		StructuredContent: map[string]interface{}{
+			"error":       "quota_exceeded",
+			"window":      quotaErr.Window,
+			"limit":       quotaErr.Limit,
+			"used":        quotaErr.Used,
+			"requested":   quotaErr.Requested,
+			"retry_after": seconds,
+		},
+		IsError: true,
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// setReadOnlyTool is the name of the tool that switches read-only mode.
+const setReadOnlyTool = "set_read_only"
+
+// errReadOnlyLift is returned when an MCP client asks to lift read-only
+// mode. The mode exists to stop the agents connected over MCP, so only the
+// operator can lift it, by reloading a configuration without read_only.
+var errReadOnlyLift = errors.New("read-only mode can only be lifted by the operator: set read_only to false in the configuration and send SIGHUP")
+
+// setReadOnlyArgs are the arguments of the set_read_only tool.
+type setReadOnlyArgs struct {
+	Enabled *bool  `json:"enabled"`
+	Reason  string `json:"reason"`
+}
+
+// SetReadOnly switches the server into or out of read-only mode, in which
+// every client is limited to reads whatever its role. It tells connected
+// clients to fetch the tool list again if the mode changed.
+func (s *Server) SetReadOnly(ctx context.Context, enabled bool, reason string) bool {
+	changed := s.config.SetReadOnly(enabled)
+	if changed {
+		s.notifier.notify("notifications/tools/list_changed", nil)
+	}
+	s.logReadOnly(ctx, enabled, changed, reason)
+	return changed
+}
+
+// logReadOnly logs and audits a switch into or out of read-only mode.
+func (s *Server) logReadOnly(ctx context.Context, enabled, changed bool, reason string) {
+	if enabled {
+		slog.WarnContext(ctx, "Read-only mode enabled", "reason", reason, "changed", changed)
+	} else {
+		slog.InfoContext(ctx, "Read-only mode disabled", "reason", reason, "changed", changed)
+	}
+	if s.auditLogger != nil {
+		s.auditLogger.Log(audit.Event{
+			Level:     audit.LevelWarning,
+			Category:  audit.CategorySystem,
+			Operation: "read_only",
+			ClientID:  audit.ClientIDFromContext(ctx),
+			User:      audit.UserFromContext(ctx),
+			RequestID: audit.RequestIDFromContext(ctx),
+			Success:   true,
+			Details:   map[string]interface{}{"enabled": enabled, "changed": changed, "reason": reason},
+		})
+	}
+}
+
+// callSetReadOnly handles the set_read_only tool. It checks the role the
+// client was granted, so an admin keeps the tool while read-only, but it
+// only ever turns the mode on: see errReadOnlyLift.
+func (s *Server) callSetReadOnly(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	if role := s.config.GrantedRole(ctx); !role.CanAdmin() {
+		return nil, &config.RoleError{Operation: "tool " + setReadOnlyTool, Role: role}
+	}
+
+	var args setReadOnlyArgs
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	if args.Enabled == nil {
+		return nil, fmt.Errorf("invalid arguments: enabled is required")
+	}
+	if !*args.Enabled {
+		return nil, errReadOnlyLift
+	}
+
+	changed := s.SetReadOnly(ctx, *args.Enabled, args.Reason)
+	return map[string]interface{}{"read_only": *args.Enabled, "changed": changed}, nil
+}
+
+// setReadOnlyDefinition describes the set_read_only tool.
+func setReadOnlyDefinition() tools.ToolDefinition {
+	return tools.ToolDefinition{
+		Name: setReadOnlyTool,
+		Description: "Switch the whole server into read-only mode without a restart. While read-only, " +
+			"every write and admin tool is refused for all clients, whatever their role. Only the operator " +
+			"can lift the mode, by reloading the configuration",
+		InputSchema: tools.InputSchema{
+			Type: "object",
+			Properties: map[string]tools.Property{
+				"enabled": {Type: "boolean", Description: "true to refuse writes; false is refused, since only the operator can lift the mode"},
+				"reason":  {Type: "string", Description: "Why the mode is changing, recorded in the audit log"},
+			},
+			Required: []string{"enabled"},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestSetReadOnlyTool(t *testing.T) {
+	tests := []struct {
+		role     config.Role
+		wantTool bool
+	}{
+		{config.RoleAdmin, true},
+		{config.RoleReadWrite, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Role = tt.role
+			s := &Server{config: cfg, notifier: newNotifier()}
+
+			var notifications []string
+			defer s.notifier.subscribe("client", func(msg []byte) { notifications = append(notifications, string(msg)) })()
+
+			listed := func() bool {
+				result, _ := s.handleToolsList(context.Background())
+				for _, tool := range result.Tools {
+					if tool.Name == setReadOnlyTool {
+						return true
+					}
+				}
+				return false
+			}
+			if listed() != tt.wantTool {
+				t.Errorf("Expected set_read_only listed=%v for role %s", tt.wantTool, tt.role)
+			}
+
+			_, err := s.callSetReadOnly(context.Background(), json.RawMessage(`{"enabled": true, "reason": "incident"}`))
+			if (err != nil) == tt.wantTool {
+				t.Fatalf("callSetReadOnly() error = %v", err)
+			}
+			if !tt.wantTool {
+				return
+			}
+			if !cfg.IsReadOnly() || len(notifications) != 1 {
+				t.Errorf("Expected read-only mode and a tools/list_changed notification, got %v and %v", cfg.IsReadOnly(), notifications)
+			}
+
+			// The admin keeps the tool, but can't lift read-only mode over MCP
+			if !listed() {
+				t.Error("Expected set_read_only to stay listed while read-only")
+			}
+			if _, err := s.callSetReadOnly(context.Background(), json.RawMessage(`{"enabled": false}`)); !errors.Is(err, errReadOnlyLift) {
+				t.Errorf("Expected errReadOnlyLift, got %v", err)
+			}
+			if !cfg.IsReadOnly() {
+				t.Error("Expected read-only mode to stay on")
+			}
+			if code := errorCode(errReadOnlyLift); code != aerospike.ErrorForbidden {
+				t.Errorf("Expected error code %s, got %s", aerospike.ErrorForbidden, code)
+			}
+
+			if _, err := s.callSetReadOnly(context.Background(), json.RawMessage(`{}`)); err == nil {
+				t.Error("Expected an error without enabled")
+			}
+		})
+	}
+}
+
+func TestOperatorReloadLiftsReadOnly(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleAdmin
+	s := &Server{config: cfg, notifier: newNotifier()}
+	s.SetConfigLoader(func() (*config.Config, error) {
+		next := config.DefaultConfig()
+		next.Role = config.RoleAdmin
+		return next, nil
+	})
+	s.SetReadOnly(context.Background(), true, "incident")
+
+	// A reload requested over MCP keeps read-only mode
+	if changed, err := s.ReloadConfig(context.Background()); err != nil || len(changed) != 0 || !cfg.IsReadOnly() {
+		t.Fatalf("Expected ReloadConfig to keep read-only mode, got %v, %v", changed, err)
+	}
+
+	var notifications []string
+	defer s.notifier.subscribe("client", func(msg []byte) { notifications = append(notifications, string(msg)) })()
+
+	changed, err := s.OperatorReload(context.Background())
+	if err != nil {
+		t.Fatalf("OperatorReload() error = %v", err)
+	}
+	if len(changed) != 1 || changed[0] != "read_only" || cfg.IsReadOnly() {
+		t.Errorf("Expected the operator's reload to lift read-only mode, got %v", changed)
+	}
+	if len(notifications) != 1 {
+		t.Errorf("Expected one tools/list_changed notification, got %v", notifications)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/redact"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// reloadConfigTool is the name of the tool that reloads the configuration.
+const reloadConfigTool = "reload_config"
+
+// errReloadUnavailable is returned when the server wasn't given a way to
+// load its configuration again.
+var errReloadUnavailable = errors.New("configuration reload is not available")
+
+// SetConfigLoader sets the function that loads the configuration again for
+// ReloadConfig, typically from the file and flags the server started with.
+func (s *Server) SetConfigLoader(load func() (*config.Config, error)) {
+	s.loadConfig = load
+}
+
+// ReloadConfig loads the configuration again and applies the settings that
+// can change without a restart: role, client_roles, redaction rules, PII
+// masking, namespace policies, rate limits, and turning on read_only.
+// Other settings keep their startup values, and changes to them are logged
+// as needing a restart. It returns the names of the settings that changed,
+// and tells connected clients to fetch the tool list again if their tools
+// may have changed.
+func (s *Server) ReloadConfig(ctx context.Context) ([]string, error) {
+	changed, _, err := s.reloadConfig(ctx, false)
+	return changed, err
+}
+
+// OperatorReload reloads the configuration like ReloadConfig, at the
+// request of the operator rather than an MCP client, for example on
+// SIGHUP. Unlike ReloadConfig, it also lifts read-only mode when the
+// configuration no longer sets read_only.
+func (s *Server) OperatorReload(ctx context.Context) ([]string, error) {
+	changed, _, err := s.reloadConfig(ctx, true)
+	return changed, err
+}
+
+// reloadConfig implements ReloadConfig and OperatorReload. It also returns
+// the changed settings that weren't applied.
+func (s *Server) reloadConfig(ctx context.Context, liftReadOnly bool) ([]string, []string, error) {
+	if s.loadConfig == nil {
+		return nil, nil, errReloadUnavailable
+	}
+
+	next, err := s.loadConfig()
+	if err != nil {
+		s.logReload(ctx, nil, nil, err)
+		return nil, nil, fmt.Errorf("loading configuration: %w", err)
+	}
+
+	changed := s.config.Reload(next)
+	if liftReadOnly && !next.ReadOnly && s.config.SetReadOnly(false) {
+		changed = append(changed, "read_only")
+		s.logReadOnly(ctx, false, true, "configuration reloaded by the operator")
+	}
+	toolsChanged := false
+	redactionChanged := false
+	for _, name := range changed {
+		switch name {
+		case "role", "client_roles", "read_only":
+			toolsChanged = true
+		case "redact", "pii_masking":
+			redactionChanged = true
+		case "audit.rate_limits":
+			if s.rateLimiter != nil {
+				s.rateLimiter.SetLimits(next.Audit.RateLimitEnabled, rateLimits(next.Audit))
+			}
+		case "namespaces":
+			s.reloadMu.Lock()
+			s.nsLimiters = namespaceRateLimiters(next.Namespaces)
+			s.reloadMu.Unlock()
+		}
+	}
+	if redactionChanged {
+		s.reloadMu.Lock()
+		s.redactor = redact.New(next.Redact, next.PIIMasking)
+		s.reloadMu.Unlock()
+	}
+	if toolsChanged {
+		s.notifier.notify("notifications/tools/list_changed", nil)
+	}
+
+	ignored := s.config.RestartRequired(next)
+	s.logReload(ctx, changed, ignored, nil)
+	return changed, ignored, nil
+}
+
+// logReload logs and audits a configuration reload.
+func (s *Server) logReload(ctx context.Context, changed, ignored []string, err error) {
+	details := map[string]interface{}{"changed": changed}
+	if err != nil {
+		details["error"] = err.Error()
+		slog.ErrorContext(ctx, "Failed to reload configuration", "error", err)
+	} else {
+		slog.InfoContext(ctx, "Configuration reloaded", "changed", changed)
+	}
+	if len(ignored) > 0 {
+		details["restart_required"] = ignored
+		slog.WarnContext(ctx, "Configuration changes need a restart to take effect", "settings", ignored)
+	}
+
+	if s.auditLogger != nil {
+		s.auditLogger.Log(audit.Event{
+			Level:     audit.LevelInfo,
+			Category:  audit.CategorySystem,
+			Operation: "config_reload",
+			ClientID:  audit.ClientIDFromContext(ctx),
+			User:      audit.UserFromContext(ctx),
+			RequestID: audit.RequestIDFromContext(ctx),
+			Success:   err == nil,
+			Details:   details,
+		})
+	}
+}
+
+// namespaceLimiter returns the rate limiter from namespace's policy, or nil
+// if it has no rate limits.
+func (s *Server) namespaceLimiter(namespace string) *audit.ClientRateLimiter {
+	s.reloadMu.RLock()
+	defer s.reloadMu.RUnlock()
+	return s.nsLimiters[namespace]
+}
+
+// currentRedactor returns the redactor for the current configuration.
+func (s *Server) currentRedactor() *redact.Redactor {
+	s.reloadMu.RLock()
+	defer s.reloadMu.RUnlock()
+	return s.redactor
+}
+
+// callReloadConfig handles the reload_config tool.
+func (s *Server) callReloadConfig(ctx context.Context) (interface{}, error) {
+	if role := s.config.GrantedRole(ctx); !role.CanAdmin() {
+		return nil, &config.RoleError{Operation: "tool " + reloadConfigTool, Role: role}
+	}
+
+	changed, ignored, err := s.reloadConfig(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if changed == nil {
+		changed = []string{}
+	}
+	result := map[string]interface{}{"changed": changed}
+	if len(ignored) > 0 {
+		result["restart_required"] = ignored
+	}
+	return result, nil
+}
+
+// reloadConfigDefinition describes the reload_config tool.
+func reloadConfigDefinition() tools.ToolDefinition {
+	return tools.ToolDefinition{
+		Name: reloadConfigTool,
+		Description: "Reload the server configuration, applying changes to the role, client roles, redaction rules, " +
+			"PII masking, namespace policies, and rate limits without a restart. Returns the settings that changed, " +
+			"and any changed settings that need a restart in restart_required",
+		InputSchema: tools.InputSchema{
+			Type:       "object",
+			Properties: map[string]tools.Property{},
+		},
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestReloadConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadOnly
+	s := &Server{
+		config:      cfg,
+		rateLimiter: audit.NewClientRateLimiter(true, rateLimits(cfg.Audit)),
+		notifier:    newNotifier(),
+	}
+
+	var notifications []string
+	defer s.notifier.subscribe("client", func(msg []byte) { notifications = append(notifications, string(msg)) })()
+
+	if _, err := s.ReloadConfig(context.Background()); !errors.Is(err, errReloadUnavailable) {
+		t.Errorf("Expected reload to be unavailable without a loader, got %v", err)
+	}
+
+	next := config.DefaultConfig()
+	next.Role = config.RoleAdmin
+	next.Redact = []config.RedactionRule{{Bin: "ssn"}}
+	next.Audit.RateLimits = map[string]config.RateLimit{"read": {RPS: 1, Burst: 1}}
+	next.Transport = "sse" // not reloadable
+	var loadErr error
+	s.SetConfigLoader(func() (*config.Config, error) { return next, loadErr })
+
+	changed, err := s.ReloadConfig(context.Background())
+	if err != nil {
+		t.Fatalf("ReloadConfig() error = %v", err)
+	}
+	if got := strings.Join(changed, ","); got != "role,redact,audit.rate_limits" {
+		t.Errorf("Expected role, redact, and rate limits to change, got %s", got)
+	}
+	if role := cfg.EffectiveRole(context.Background()); role != config.RoleAdmin {
+		t.Errorf("Expected role admin after reload, got %s", role)
+	}
+	if cfg.Transport != "stdio" {
+		t.Errorf("Expected the transport to keep its startup value, got %s", cfg.Transport)
+	}
+	if s.currentRedactor() == nil {
+		t.Error("Expected a redactor for the new redaction rules")
+	}
+	s.rateLimiter.Allow("ci", audit.CategoryRead)
+	if ok, _ := s.rateLimiter.Allow("ci", audit.CategoryRead); ok {
+		t.Error("Expected the new read rate limit to apply")
+	}
+	if len(notifications) != 1 || notifications[0] != `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}` {
+		t.Errorf("Expected one tools/list_changed notification, got %v", notifications)
+	}
+
+	// Reloading the same configuration changes nothing
+	notifications = nil
+	if changed, _ := s.ReloadConfig(context.Background()); len(changed) != 0 || len(notifications) != 0 {
+		t.Errorf("Expected no changes or notifications, got %v and %v", changed, notifications)
+	}
+
+	// A configuration that fails to load leaves the current one in place
+	loadErr = errors.New("invalid role: superuser")
+	if _, err := s.ReloadConfig(context.Background()); err == nil {
+		t.Error("Expected an error when the configuration fails to load")
+	}
+	if role := cfg.EffectiveRole(context.Background()); role != config.RoleAdmin {
+		t.Errorf("Expected role admin to be kept, got %s", role)
+	}
+}
+
+func TestReloadConfigTool(t *testing.T) {
+	tests := []struct {
+		role     config.Role
+		wantTool bool
+	}{
+		{config.RoleAdmin, true},
+		{config.RoleReadWrite, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Role = tt.role
+			s := &Server{config: cfg, notifier: newNotifier()}
+			s.SetConfigLoader(func() (*config.Config, error) {
+				next := config.DefaultConfig()
+				next.Role = tt.role
+				next.ClientRoles = map[string]config.Role{"ci": config.RoleReadOnly}
+				return next, nil
+			})
+
+			listed, _ := s.handleToolsList(context.Background())
+			found := false
+			for _, tool := range listed.Tools {
+				found = found || tool.Name == reloadConfigTool
+			}
+			if found != tt.wantTool {
+				t.Errorf("Expected reload_config listed=%v for role %s", tt.wantTool, tt.role)
+			}
+
+			result, err := s.callReloadConfig(context.Background())
+			if (err != nil) == tt.wantTool {
+				t.Fatalf("callReloadConfig() error = %v", err)
+			}
+			if tt.wantTool {
+				changed := result.(map[string]interface{})["changed"].([]string)
+				if len(changed) != 1 || changed[0] != "client_roles" {
+					t.Errorf("Expected client_roles to change, got %v", changed)
+				}
+			}
+		})
+	}
+}
+
+func TestReloadNamespacePolicies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleAdmin
+	s := &Server{config: cfg, notifier: newNotifier()}
+	s.SetConfigLoader(func() (*config.Config, error) {
+		next := config.DefaultConfig()
+		next.Role = config.RoleAdmin
+		next.Namespaces = map[string]config.NamespacePolicy{
+			"test": {Role: config.RoleReadOnly, RateLimits: map[string]config.RateLimit{"read": {RPS: 1, Burst: 1}}},
+		}
+		next.Transport = "sse"        // not reloadable
+		next.Audit.FilePath = "a.log" // not reloadable
+		return next, nil
+	})
+
+	result, err := s.callReloadConfig(context.Background())
+	if err != nil {
+		t.Fatalf("callReloadConfig() error = %v", err)
+	}
+	got := result.(map[string]interface{})
+	if changed := strings.Join(got["changed"].([]string), ","); changed != "namespaces" {
+		t.Errorf("Expected namespaces to change, got %s", changed)
+	}
+	if ignored := strings.Join(got["restart_required"].([]string), ","); ignored != "audit.file_path,transport" {
+		t.Errorf("Expected audit.file_path and transport to need a restart, got %s", ignored)
+	}
+
+	if role := cfg.NamespaceRole(context.Background(), "test"); role != config.RoleReadOnly {
+		t.Errorf("Expected the reloaded namespace role read-only, got %s", role)
+	}
+	limiter := s.namespaceLimiter("test")
+	if limiter == nil {
+		t.Fatal("Expected a rate limiter for the reloaded namespace policy")
+	}
+	limiter.Allow("ci", audit.CategoryRead)
+	if ok, _ := limiter.Allow("ci", audit.CategoryRead); ok {
+		t.Error("Expected the reloaded namespace rate limit to apply")
+	}
+}
+
+func TestInitializeToolsListChanged(t *testing.T) {
+	// set_read_only can change the tool list even without a config loader
+	s := &Server{config: config.DefaultConfig()}
+	if result, _ := s.handleInitialize(context.Background(), nil); !result.Capabilities.Tools.ListChanged {
+		t.Error("Expected tools.listChanged without a config loader")
+	}
+
+	s.SetConfigLoader(func() (*config.Config, error) { return config.DefaultConfig(), nil })
+	if result, _ := s.handleInitialize(context.Background(), nil); !result.Capabilities.Tools.ListChanged {
+		t.Error("Expected tools.listChanged with a config loader")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+)
+
+// withRequestID returns ctx carrying a new request ID, unless it already
+// carries one. Unlike the JSON-RPC ID, which the client chooses and may
+// reuse, the request ID is unique, so it identifies one call across server
+// logs, audit events, traces, and the error returned to the client.
+func withRequestID(ctx context.Context) (context.Context, string) {
+	if id := audit.RequestIDFromContext(ctx); id != "" {
+		return ctx, id
+	}
+	id := uuid.NewString()
+	return audit.WithRequestID(ctx, id), id
+}
+
+// tagResponse adds the request ID to an error response's data, or to the
+// _meta of a failed tool call's result.
+func tagResponse(response *Response, id string) {
+	if response == nil {
+		return
+	}
+	if response.Error != nil {
+		response.Error.Data = errorData(response.Error.Data, id)
+		return
+	}
+	if result, ok := response.Result.(*ToolsCallResult); ok && result.IsError {
+		if result.Meta == nil {
+			result.Meta = make(map[string]interface{})
+		}
+		result.Meta["request_id"] = id
+	}
+}
+
+// errorData adds the request ID to an error's data. Data that isn't an
+// object is moved to its detail field.
+func errorData(data interface{}, id string) map[string]interface{} {
+	switch d := data.(type) {
+	case nil:
+		return map[string]interface{}{"request_id": id}
+	case map[string]interface{}:
+		d["request_id"] = id
+		return d
+	default:
+		return map[string]interface{}{"detail": d, "request_id": id}
+	}
+}
+
+// rpcID formats a JSON-RPC request ID for logging. Notifications have no
+// ID.
+func rpcID(id interface{}) string {
+	switch id := id.(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		return fmt.Sprint(id)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestRequestIDInErrors(t *testing.T) {
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(io.Discard)
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadOnly
+	s := &Server{
+		config:      cfg,
+		auditLogger: auditLogger,
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+	}
+
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{"parse error", `{`},
+		{"invalid version", `{"jsonrpc":"1.0","id":1,"method":"ping"}`},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"nope"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := audit.WithRequestID(context.Background(), "req-"+tt.name)
+			response := s.handleMessage(ctx, []byte(tt.message))
+			if response == nil || response.Error == nil {
+				t.Fatalf("Expected an error response, got %+v", response)
+			}
+			data, ok := response.Error.Data.(map[string]interface{})
+			if !ok {
+				t.Fatalf("Expected object error data, got %T", response.Error.Data)
+			}
+			if data["request_id"] != "req-"+tt.name {
+				t.Errorf("Expected request_id req-%s, got %v", tt.name, data["request_id"])
+			}
+			// The original data is kept alongside the request ID
+			if _, ok := data["detail"]; !ok {
+				t.Errorf("Expected the error detail, got %v", data)
+			}
+		})
+	}
+}
+
+func TestRequestIDInToolResults(t *testing.T) {
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(io.Discard)
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadOnly
+	s := &Server{
+		config:      cfg,
+		auditLogger: auditLogger,
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+	}
+
+	// Non-admins may not query the audit log, so the call fails
+	response := s.processMessage(context.Background(),
+		[]byte(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"query_audit_log"}}`))
+	result, ok := response.Result.(*ToolsCallResult)
+	if !ok || !result.IsError {
+		t.Fatalf("Expected a failed tool result, got %+v", response)
+	}
+	id, _ := result.Meta["request_id"].(string)
+	if id == "" {
+		t.Fatalf("Expected a request_id in _meta, got %v", result.Meta)
+	}
+
+	events := auditLogger.Query(audit.Query{RequestID: id})
+	if len(events) != 1 || events[0].Operation != queryAuditLogTool {
+		t.Errorf("Expected the tool call's audit event, got %+v", events)
+	}
+
+	other := s.processMessage(context.Background(),
+		[]byte(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"query_audit_log"}}`))
+	if other.Result.(*ToolsCallResult).Meta["request_id"] == id {
+		t.Error("Expected each call to get a new request ID")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// resultURIPrefix is the URI prefix for stored tool results.
+	resultURIPrefix = "aerospike://results/"
+
+	// maxStoredResults bounds the number of results kept in memory.
+	maxStoredResults = 32
+
+	// resultRetention is how long a stored result remains readable.
+	resultRetention = 15 * time.Minute
+)
+
+// storedResult is a large tool result held for retrieval via resources/read.
+type storedResult struct {
+	tool      string
+	data      []byte
+	createdAt time.Time
+}
+
+// resultStore keeps large tool results in memory so they can be returned as
+// resource links instead of being inlined into the tool response.
+type resultStore struct {
+	mu      sync.Mutex
+	results map[string]*storedResult
+	order   []string
+}
+
+func newResultStore() *resultStore {
+	return &resultStore{
+		results: make(map[string]*storedResult),
+	}
+}
+
+// put stores a result and returns its resource URI.
+func (r *resultStore) put(tool string, data []byte) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evictLocked(time.Now())
+
+	id := uuid.New().String()
+	r.results[id] = &storedResult{
+		tool:      tool,
+		data:      data,
+		createdAt: time.Now(),
+	}
+	r.order = append(r.order, id)
+
+	for len(r.order) > maxStoredResults {
+		delete(r.results, r.order[0])
+		r.order = r.order[1:]
+	}
+
+	return resultURIPrefix + id
+}
+
+// get returns the stored result for a resource URI.
+func (r *resultStore) get(uri string) ([]byte, error) {
+	id := strings.TrimPrefix(uri, resultURIPrefix)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evictLocked(time.Now())
+
+	result, ok := r.results[id]
+	if !ok {
+		return nil, fmt.Errorf("result not found or expired: %s", uri)
+	}
+	return result.data, nil
+}
+
+// evictLocked removes results older than the retention period.
+func (r *resultStore) evictLocked(now time.Time) {
+	for len(r.order) > 0 {
+		oldest, ok := r.results[r.order[0]]
+		if ok && now.Sub(oldest.createdAt) < resultRetention {
+			return
+		}
+		delete(r.results, r.order[0])
+		r.order = r.order[1:]
+	}
+}
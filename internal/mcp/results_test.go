@@ -0,0 +1,87 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestResultStore(t *testing.T) {
+	store := newResultStore()
+
+	uri := store.put("scan_set", []byte(`{"records":[]}`))
+	if !strings.HasPrefix(uri, resultURIPrefix) {
+		t.Fatalf("Expected URI with prefix %s, got %s", resultURIPrefix, uri)
+	}
+
+	data, err := store.get(uri)
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if string(data) != `{"records":[]}` {
+		t.Errorf("Unexpected data: %s", data)
+	}
+
+	if _, err := store.get(resultURIPrefix + "missing"); err == nil {
+		t.Error("Expected error for missing result")
+	}
+}
+
+func TestResultStoreBounded(t *testing.T) {
+	store := newResultStore()
+
+	first := store.put("scan_set", []byte("first"))
+	for i := 0; i < maxStoredResults; i++ {
+		store.put("scan_set", []byte("more"))
+	}
+
+	if _, err := store.get(first); err == nil {
+		t.Error("Expected oldest result to be evicted")
+	}
+	if len(store.results) != maxStoredResults {
+		t.Errorf("Expected %d results, got %d", maxStoredResults, len(store.results))
+	}
+}
+
+func TestResultStoreExpiry(t *testing.T) {
+	store := newResultStore()
+
+	uri := store.put("scan_set", []byte("data"))
+	store.results[strings.TrimPrefix(uri, resultURIPrefix)].createdAt = time.Now().Add(-2 * resultRetention)
+
+	if _, err := store.get(uri); err == nil {
+		t.Error("Expected expired result to be removed")
+	}
+}
+
+func TestLinkResult(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MaxInlineResultBytes = 10
+	s := &Server{config: cfg, results: newResultStore()}
+
+	result := s.linkResult("scan_set", []byte(`{"records":["a","b","c"]}`))
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected 2 content blocks, got %d", len(result.Content))
+	}
+
+	link := result.Content[1]
+	if link.Type != "resource_link" {
+		t.Errorf("Expected resource_link block, got %s", link.Type)
+	}
+
+	params, _ := json.Marshal(ResourcesReadParams{URI: link.URI})
+	read, rpcErr := s.handleResourcesRead(context.Background(), params)
+	if rpcErr != nil {
+		t.Fatalf("handleResourcesRead() error = %v", rpcErr.Data)
+	}
+	if read.Contents[0].Text != `{"records":["a","b","c"]}` {
+		t.Errorf("Unexpected resource content: %s", read.Contents[0].Text)
+	}
+}
@@ -6,18 +6,29 @@ package mcp
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"math"
+	"net"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
 	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/logging"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/metrics"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/prompts"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/redact"
 	"github.com/dringdahl0320/aerospike-mcp-server/internal/resources"
 	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tracing"
 	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
 )
 
@@ -33,9 +44,34 @@ type Server struct {
 	config      *config.Config
 	tools       *tools.Registry
 	resources   *resources.Registry
+	prompts     *prompts.Registry
 	auditLogger *audit.Logger
-	rateLimiter *audit.RateLimiter
+	rateLimiter *audit.ClientRateLimiter
+	nsLimiters  map[string]*audit.ClientRateLimiter // per-namespace budgets from namespace policies
+	writeQuota  *audit.WriteQuota
 	validator   *audit.Validator
+	results     *resultStore
+	auth        *authenticator
+	allowed     []*net.IPNet // source networks allowed on HTTP transports; empty allows any
+	redactor    *redact.Redactor
+	sessions    *sessionManager
+	elevation   *elevationManager // nil unless elevation is enabled
+	metrics     *metrics.Metrics  // nil unless metrics are enabled
+	tracer      *tracing.Tracer   // nil unless tracing is enabled
+	stats       *metrics.ToolStats
+	notifier    *notifier
+	jobs        *jobManager // nil unless jobs are enabled
+	started     time.Time
+
+	// loadConfig reads the configuration again for ReloadConfig
+	loadConfig func() (*config.Config, error)
+	reloadMu   sync.RWMutex // guards redactor and nsLimiters
+
+	// ping checks the cluster is reachable, returning its node count
+	ping func(ctx context.Context) (int, error)
+
+	// clusterState polls the cluster for the cluster event watcher
+	clusterState func(ctx context.Context) (*aerospike.ClusterState, error)
 }
 
 // NewServer creates a new MCP server instance.
@@ -45,37 +81,119 @@ func NewServer(client *aerospike.Client, cfg *config.Config) *Server {
 		Enabled:    cfg.Audit.Enabled,
 		FilePath:   cfg.Audit.FilePath,
 		BufferSize: cfg.Audit.BufferSize,
+
+		HashChain:      cfg.Audit.HashChain,
+		SigningKeyFile: cfg.Audit.SigningKeyFile,
+		SignEvery:      cfg.Audit.SignEvery,
+
+		Rotation: audit.RotationConfig{
+			MaxSizeMB:           cfg.Audit.Rotation.MaxSizeMB,
+			RotateIntervalHours: cfg.Audit.Rotation.RotateIntervalHours,
+			Compress:            cfg.Audit.Rotation.Compress,
+			MaxFiles:            cfg.Audit.Rotation.MaxFiles,
+			MaxAgeDays:          cfg.Audit.Rotation.MaxAgeDays,
+		},
 	}
 	auditLogger, err := audit.NewLogger(auditCfg)
 	if err != nil {
-		log.Printf("Warning: Failed to initialize audit logger: %v", err)
+		slog.Warn("Failed to initialize audit logger", "error", err)
+	}
+	if auditLogger != nil && cfg.Audit.Aerospike != nil {
+		auditLogger.AddSink("aerospike", aerospike.NewAuditSink(client, cfg.Audit.Aerospike))
+	}
+	if auditLogger != nil {
+		for _, sinkCfg := range cfg.Audit.Sinks {
+			sink, err := newAuditSink(sinkCfg)
+			if err != nil {
+				slog.Warn("Failed to initialize audit sink", "sink", sinkCfg.Name, "error", err)
+				continue
+			}
+			auditLogger.AddSink(sinkCfg.Name, sink)
+		}
 	}
 
 	// Initialize rate limiter
-	rateLimitCfg := audit.RateLimitConfig{
-		Enabled:        cfg.Audit.RateLimitEnabled,
-		RequestsPerSec: cfg.Audit.RateLimitRPS,
-		BurstSize:      cfg.Audit.RateLimitBurst,
-	}
-	rateLimiter := audit.NewRateLimiter(rateLimitCfg)
+	rateLimiter := audit.NewClientRateLimiter(cfg.Audit.RateLimitEnabled, rateLimits(cfg.Audit))
 
 	// Initialize validator
-	validator := audit.NewValidator(audit.DefaultValidatorConfig())
+	validatorCfg := audit.DefaultValidatorConfig()
+	if limit := cfg.BatchLimit(); limit > 0 {
+		validatorCfg.MaxBatchSize = limit
+	}
+	validator := audit.NewValidator(validatorCfg)
+
+	// Validate has already checked the allowlist
+	allowed, _ := cfg.AllowedNetworks()
 
 	s := &Server{
 		client:      client,
 		config:      cfg,
 		auditLogger: auditLogger,
 		rateLimiter: rateLimiter,
+		nsLimiters:  namespaceRateLimiters(cfg.Namespaces),
+		writeQuota:  audit.NewWriteQuota(cfg.WriteQuota.RecordsPerHour, cfg.WriteQuota.RecordsPerDay),
 		validator:   validator,
+		results:     newResultStore(),
+		auth:        newAuthenticator(cfg.Auth),
+		allowed:     allowed,
+		redactor:    redact.New(cfg.Redact, cfg.PIIMasking),
+		sessions:    newSessionManager(cfg.Sessions),
+		stats:       metrics.NewToolStats(),
+		notifier:    newNotifier(),
+		jobs:        newJobManager(cfg.Jobs),
+		started:     time.Now(),
+	}
+	if client != nil {
+		s.ping = client.Ping
+		s.clusterState = client.ClusterState
+	}
+
+	elevation, err := newElevationManager(cfg.Elevation)
+	if err != nil {
+		slog.Warn("Role elevation disabled", "error", err)
+	}
+	if elevation != nil {
+		elevation.onExpire = s.logElevationExpired
+		s.elevation = elevation
+	}
+
+	if cfg.Metrics.Enabled {
+		s.metrics = metrics.New()
+		switch cfg.Transport {
+		case "sse", "websocket", "unix":
+			s.metrics.ConnectedClients(cfg.Transport, s.sessions.count)
+		}
+		if client != nil {
+			client.SetObserver(s.metrics.AerospikeOperation)
+		}
+	}
+
+	if cfg.Tracing.Enabled {
+		tracer, err := tracing.New(tracing.Config{
+			Endpoint:       cfg.Tracing.Endpoint,
+			Headers:        cfg.Tracing.Headers,
+			ServiceName:    cfg.Tracing.ServiceName,
+			ServiceVersion: ServerVersion,
+			SampleRatio:    cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			slog.Warn("Tracing disabled", "error", err)
+		}
+		s.tracer = tracer
 	}
 
 	// Initialize tool registry
 	s.tools = tools.NewRegistry(client, cfg)
+	s.tools.SetAuditLogger(auditLogger)
 
 	// Initialize resource registry
 	s.resources = resources.NewRegistry(client, cfg)
 
+	// Initialize prompt registry
+	s.prompts = prompts.NewRegistry(client, cfg)
+	s.prompts.SetAuditLogger(auditLogger)
+	s.prompts.SetRedactor(s.redactResult)
+
 	return s
 }
 
@@ -90,11 +208,43 @@ func (s *Server) Run(ctx context.Context) error {
 			Success:   true,
 			Details: map[string]interface{}{
 				"transport": s.config.Transport,
-				"role":      s.config.Role,
+				"role":      s.config.EffectiveRole(ctx),
 			},
 		})
 	}
 
+	// Serve metrics on their own listener if configured
+	if s.metrics != nil && s.config.Metrics.Port != 0 {
+		go func() {
+			if err := s.serveMetrics(ctx); err != nil {
+				slog.Error("Metrics server error", "error", err)
+			}
+		}()
+	}
+
+	// Serve health endpoints on their own listener if configured
+	if s.config.Health.Port != 0 {
+		go func() {
+			if err := s.serveHealth(ctx); err != nil {
+				slog.Error("Health server error", "error", err)
+			}
+		}()
+	}
+
+	// Serve profiles on a debug listener if enabled
+	if s.config.Debug.Enabled {
+		go func() {
+			if err := s.serveDebug(ctx); err != nil {
+				slog.Error("Debug server error", "error", err)
+			}
+		}()
+	}
+
+	// Notify connected clients of cluster changes
+	if s.config.ClusterEvents.Enabled && s.clusterState != nil {
+		go s.watchCluster(ctx)
+	}
+
 	// Run transport
 	var err error
 	switch s.config.Transport {
@@ -104,10 +254,19 @@ func (s *Server) Run(ctx context.Context) error {
 		err = s.runSSE(ctx)
 	case "websocket":
 		err = s.runWebSocket(ctx)
+	case "unix":
+		err = s.runUnix(ctx)
+	case "grpc":
+		err = s.runGRPC(ctx)
 	default:
 		err = fmt.Errorf("unsupported transport: %s", s.config.Transport)
 	}
 
+	// Stop background jobs before the audit log closes
+	if s.jobs != nil {
+		s.jobs.stop()
+	}
+
 	// Log server shutdown
 	if s.auditLogger != nil {
 		s.auditLogger.Log(audit.Event{
@@ -120,45 +279,247 @@ func (s *Server) Run(ctx context.Context) error {
 		s.auditLogger.Close()
 	}
 
+	// Export any spans still queued
+	if s.tracer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.tracer.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("Failed to flush traces", "error", err)
+		}
+	}
+
 	return err
 }
 
 // runStdio runs the server using stdio transport.
 func (s *Server) runStdio(ctx context.Context) error {
-	reader := bufio.NewReader(os.Stdin)
-	writer := os.Stdout
-
-	log.Println("MCP server started (stdio transport)")
+	slog.Info("MCP server started", "transport", "stdio")
+	return s.serveStream(ctx, os.Stdin, os.Stdout)
+}
 
-	for {
+// serveStream reads newline-delimited JSON-RPC messages from r and writes
+// responses and notifications to w. Requests are processed concurrently by a bounded pool of
+// workers; requests sharing the same JSON-RPC ID are processed in order.
+func (s *Server) serveStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reader := bufio.NewReader(r)
+	workers := make(chan struct{}, s.config.MaxConcurrentRequests)
+	sequencer := newIDSequencer()
+
+	var (
+		wg       sync.WaitGroup
+		writeMu  sync.Mutex
+		writeErr error
+	)
+	defer wg.Wait()
+
+	// Write notifications between responses until the stream ends
+	notifications := make(chan []byte, 100)
+	defer s.notifier.subscribe("stdio", func(msg []byte) {
 		select {
-		case <-ctx.Done():
-			return ctx.Err()
+		case notifications <- msg:
 		default:
-			// Read JSON-RPC message
-			line, err := reader.ReadBytes('\n')
-			if err != nil {
-				if err == io.EOF {
-					return nil
+		}
+	})()
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(stop)
+		<-stopped
+	}()
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case msg := <-notifications:
+				writeMu.Lock()
+				if _, err := w.Write(append(msg, '\n')); err != nil && writeErr == nil {
+					writeErr = fmt.Errorf("writing notification: %w", err)
+					cancel()
 				}
-				return fmt.Errorf("reading input: %w", err)
+				writeMu.Unlock()
+			case <-stop:
+				return
 			}
+		}
+	}()
+
+	for {
+		if ctx.Err() != nil {
+			writeMu.Lock()
+			err := writeErr
+			writeMu.Unlock()
+			if err != nil {
+				return err
+			}
+			return ctx.Err()
+		}
+
+		// Read JSON-RPC message
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			// Acquire a worker slot
+			select {
+			case workers <- struct{}{}:
+			case <-ctx.Done():
+				continue
+			}
+
+			wait, done := sequencer.enter(messageIDKey(line))
+			wg.Add(1)
+			go func(message []byte) {
+				defer wg.Done()
+				defer func() { <-workers }()
+				defer done()
+
+				if wait != nil {
+					select {
+					case <-wait:
+					case <-ctx.Done():
+						return
+					}
+				}
 
-			// Process message
-			response := s.handleMessage(ctx, line)
-			if response != nil {
+				// Process message. The worker slot stays held until the
+				// handler returns, even if it has already timed out.
+				response, finished := s.startMessage(ctx, message)
+				defer func() { <-finished }()
+				if response == nil {
+					return
+				}
 				responseBytes, err := json.Marshal(response)
 				if err != nil {
-					log.Printf("Error marshaling response: %v", err)
-					continue
+					slog.ErrorContext(ctx, "Error marshaling response", "error", err)
+					return
 				}
 				responseBytes = append(responseBytes, '\n')
-				if _, err := writer.Write(responseBytes); err != nil {
-					return fmt.Errorf("writing response: %w", err)
+
+				writeMu.Lock()
+				defer writeMu.Unlock()
+				if _, err := w.Write(responseBytes); err != nil && writeErr == nil {
+					writeErr = fmt.Errorf("writing response: %w", err)
+					cancel()
 				}
+			}(line)
+		}
+
+		if err != nil {
+			if err == io.EOF {
+				return nil
 			}
+			return fmt.Errorf("reading input: %w", err)
+		}
+	}
+}
+
+// processMessage handles a message under the configured per-request timeout.
+// If the handler does not finish in time, a timeout error is returned to the
+// client; the handler's eventual result is discarded.
+func (s *Server) processMessage(ctx context.Context, message []byte) *Response {
+	response, _ := s.startMessage(ctx, message)
+	return response
+}
+
+// startMessage is processMessage that also returns a channel closed once the
+// handler has returned, which may be after a timeout response. The handler's
+// context is cancelled at the deadline, so cluster calls made with it stop.
+func (s *Server) startMessage(ctx context.Context, message []byte) (*Response, <-chan struct{}) {
+	ctx, id := withRequestID(ctx)
+	finished := make(chan struct{})
+	timeout := time.Duration(s.config.RequestTimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		defer close(finished)
+		return s.handleMessage(ctx, message), finished
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	result := make(chan *Response, 1)
+	go func() {
+		defer close(finished)
+		defer cancel()
+		result <- s.handleMessage(ctx, message)
+	}()
+
+	select {
+	case response := <-result:
+		return response, finished
+	case <-ctx.Done():
+		var req Request
+		if err := json.Unmarshal(message, &req); err != nil || req.ID == nil {
+			return nil, finished
+		}
+		return &Response{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &Error{
+				Code:    InternalError,
+				Message: "Request timed out",
+				Data: errorData(map[string]interface{}{
+					"error":  aerospike.ErrorTimeout,
+					"detail": fmt.Sprintf("request exceeded %s", timeout),
+				}, id),
+			},
+		}, finished
+	}
+}
+
+// idSequencer serializes processing of messages that share a JSON-RPC ID so
+// their responses are emitted in the order the requests were received.
+type idSequencer struct {
+	mu    sync.Mutex
+	tails map[string]chan struct{}
+}
+
+func newIDSequencer() *idSequencer {
+	return &idSequencer{tails: make(map[string]chan struct{})}
+}
+
+// enter registers a message with the given ID key. The returned wait channel
+// (nil if there is no predecessor) is closed once the previous message with
+// the same key is done; done must be called when processing completes.
+func (q *idSequencer) enter(key string) (<-chan struct{}, func()) {
+	if key == "" {
+		return nil, func() {}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	prev := q.tails[key]
+	tail := make(chan struct{})
+	q.tails[key] = tail
+
+	var wait <-chan struct{}
+	if prev != nil {
+		wait = prev
+	}
+
+	return wait, func() {
+		close(tail)
+		q.mu.Lock()
+		if q.tails[key] == tail {
+			delete(q.tails, key)
 		}
+		q.mu.Unlock()
+	}
+}
+
+// messageIDKey returns a canonical key for a message's JSON-RPC ID, or an
+// empty string for notifications and unparseable messages.
+func messageIDKey(message []byte) string {
+	var envelope struct {
+		ID json.RawMessage `json:"id"`
 	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return ""
+	}
+	id := bytes.TrimSpace(envelope.ID)
+	if len(id) == 0 || bytes.Equal(id, []byte("null")) {
+		return ""
+	}
+	return string(id)
 }
 
 // runSSE runs the server using Server-Sent Events transport.
@@ -182,6 +543,23 @@ func (s *Server) runWebSocket(ctx context.Context) error {
 	return wsServer.Run(ctx)
 }
 
+// runUnix runs the HTTP transport endpoints over a unix domain socket.
+func (s *Server) runUnix(ctx context.Context) error {
+	unixServer := NewUnixServer(s, s.config.SocketPath, s.config.SocketFileMode())
+	return unixServer.Run(ctx)
+}
+
+// runGRPC runs the server using gRPC transport.
+func (s *Server) runGRPC(ctx context.Context) error {
+	port := s.config.Port
+	if port == 0 {
+		port = 50051
+	}
+
+	grpcServer := NewGRPCServer(s, port)
+	return grpcServer.Run(ctx)
+}
+
 // ============================================================================
 // JSON-RPC Types
 // ============================================================================
@@ -222,8 +600,17 @@ const (
 // Message Handling
 // ============================================================================
 
-// handleMessage processes a JSON-RPC message and returns a response.
+// handleMessage assigns a message a request ID, unless processMessage
+// already has, and processes it. The ID is added to error responses.
 func (s *Server) handleMessage(ctx context.Context, message []byte) *Response {
+	ctx, id := withRequestID(ctx)
+	response := s.dispatchMessage(ctx, message)
+	tagResponse(response, id)
+	return response
+}
+
+// dispatchMessage processes a JSON-RPC message and returns a response.
+func (s *Server) dispatchMessage(ctx context.Context, message []byte) *Response {
 	var req Request
 	if err := json.Unmarshal(message, &req); err != nil {
 		return &Response{
@@ -248,9 +635,17 @@ func (s *Server) handleMessage(ctx context.Context, message []byte) *Response {
 		}
 	}
 
-	// Route to appropriate handler
+	ctx, span := s.startMessageSpan(ctx, &req)
+	defer span.End()
+	span.SetAttributes(tracing.String("mcp.request_id", audit.RequestIDFromContext(ctx)))
+	slog.DebugContext(ctx, "Handling request", "method", req.Method, "rpc_id", rpcID(req.ID))
+
+	// Route to appropriate handler with the client's role
+	ctx = s.resolveRole(ctx)
 	result, err := s.routeMethod(ctx, req.Method, req.Params)
 	if err != nil {
+		span.SetError(errors.New(err.Message))
+		slog.DebugContext(ctx, "Request failed", "method", req.Method, "code", err.Code, "error", err.Message)
 		return &Response{
 			JSONRPC: "2.0",
 			ID:      req.ID,
@@ -291,6 +686,8 @@ func (s *Server) routeMethod(ctx context.Context, method string, params json.Raw
 	// Prompts methods (optional)
 	case "prompts/list":
 		return s.handlePromptsList(ctx)
+	case "prompts/get":
+		return s.handlePromptsGet(ctx, params)
 
 	default:
 		return nil, &Error{
@@ -327,6 +724,7 @@ type InitializeResult struct {
 		Tools     *ToolsCapability     `json:"tools,omitempty"`
 		Resources *ResourcesCapability `json:"resources,omitempty"`
 		Prompts   *PromptsCapability   `json:"prompts,omitempty"`
+		Logging   *struct{}            `json:"logging,omitempty"`
 	} `json:"capabilities"`
 	ServerInfo struct {
 		Name    string `json:"name"`
@@ -347,7 +745,7 @@ type PromptsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
-func (s *Server) handleInitialize(_ context.Context, params json.RawMessage) (*InitializeResult, *Error) {
+func (s *Server) handleInitialize(ctx context.Context, params json.RawMessage) (*InitializeResult, *Error) {
 	var initParams InitializeParams
 	if params != nil {
 		if err := json.Unmarshal(params, &initParams); err != nil {
@@ -359,14 +757,18 @@ func (s *Server) handleInitialize(_ context.Context, params json.RawMessage) (*I
 		}
 	}
 
-	log.Printf("Client connected: %s %s", initParams.ClientInfo.Name, initParams.ClientInfo.Version)
+	slog.InfoContext(ctx, "Client connected", "client_name", initParams.ClientInfo.Name, "client_version", initParams.ClientInfo.Version)
 
 	result := &InitializeResult{
 		ProtocolVersion: MCPVersion,
 	}
-	result.Capabilities.Tools = &ToolsCapability{}
+	// Reloads and set_read_only change the tools a client may call
+	result.Capabilities.Tools = &ToolsCapability{ListChanged: true}
 	result.Capabilities.Resources = &ResourcesCapability{}
 	result.Capabilities.Prompts = &PromptsCapability{}
+	if s.config.ClusterEvents.Enabled {
+		result.Capabilities.Logging = &struct{}{}
+	}
 	result.ServerInfo.Name = ServerName
 	result.ServerInfo.Version = ServerVersion
 
@@ -382,10 +784,25 @@ type ToolsListResult struct {
 	Tools []tools.ToolDefinition `json:"tools"`
 }
 
-func (s *Server) handleToolsList(_ context.Context) (*ToolsListResult, *Error) {
-	return &ToolsListResult{
-		Tools: s.tools.List(),
-	}, nil
+func (s *Server) handleToolsList(ctx context.Context) (*ToolsListResult, *Error) {
+	definitions := s.tools.ListForRole(s.config.EffectiveRole(ctx))
+	definitions = append(definitions, serverStatsDefinition())
+	if s.jobs != nil {
+		definitions = append(withAsync(definitions), jobToolDefinitions()...)
+	}
+	if s.elevation != nil {
+		definitions = append(definitions, elevateRoleDefinition())
+	}
+	if s.canQueryAudit(ctx) {
+		definitions = append(definitions, queryAuditLogDefinition())
+	}
+	if s.config.GrantedRole(ctx).CanAdmin() {
+		definitions = append(definitions, serverInfoDefinition(), setReadOnlyDefinition())
+		if s.loadConfig != nil {
+			definitions = append(definitions, reloadConfigDefinition())
+		}
+	}
+	return &ToolsListResult{Tools: definitions}, nil
 }
 
 // ToolsCallParams represents the tools/call request parameters.
@@ -396,14 +813,25 @@ type ToolsCallParams struct {
 
 // ToolsCallResult represents the tools/call response.
 type ToolsCallResult struct {
-	Content []ContentBlock `json:"content"`
-	IsError bool           `json:"isError,omitempty"`
+	Content           []ContentBlock         `json:"content"`
+	StructuredContent interface{}            `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
+	Meta              map[string]interface{} `json:"_meta,omitempty"`
 }
 
 // ContentBlock represents a content block in tool results.
 type ContentBlock struct {
 	Type string `json:"type"`
 	Text string `json:"text,omitempty"`
+
+	// Fields for "resource_link" blocks
+	URI      string `json:"uri,omitempty"`
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Size     int    `json:"size,omitempty"`
+
+	// Embedded content for "resource" blocks
+	Resource *ResourceContent `json:"resource,omitempty"`
 }
 
 func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (*ToolsCallResult, *Error) {
@@ -418,44 +846,121 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (*
 		}
 	}
 
-	// Check rate limit for write operations
-	if isWriteOperation(callParams.Name) {
-		if !s.rateLimiter.Allow() {
-			if s.auditLogger != nil {
-				s.auditLogger.Log(audit.Event{
-					Level:     audit.LevelWarning,
-					Category:  audit.CategoryWrite,
-					Operation: callParams.Name,
-					Success:   false,
-					Error:     "rate limit exceeded",
-				})
-			}
-			return &ToolsCallResult{
-				Content: []ContentBlock{
-					{Type: "text", Text: "Error: rate limit exceeded, please try again later"},
-				},
-				IsError: true,
-			}, nil
+	ctx = logging.WithTool(ctx, callParams.Name)
+
+	// Reject invalid arguments before they reach the cluster
+	if err := s.validateToolArgs(callParams.Name, callParams.Arguments); err != nil {
+		if s.auditLogger != nil {
+			s.auditLogger.Log(audit.Event{
+				Level:     audit.LevelWarning,
+				Category:  toolCategory(callParams.Name),
+				Operation: callParams.Name,
+				User:      audit.UserFromContext(ctx),
+				ClientID:  audit.ClientIDFromContext(ctx),
+				RequestID: audit.RequestIDFromContext(ctx),
+				Success:   false,
+				Error:     err.Error(),
+			})
+		}
+		s.recordToolCall(callParams.Name, metrics.OutcomeInvalid, time.Since(startTime))
+		return nil, &Error{
+			Code:    InvalidParams,
+			Message: "Invalid params",
+			Data:    map[string]interface{}{"error": aerospike.ErrorInvalid, "errors": err},
 		}
 	}
 
-	result, err := s.tools.Call(ctx, callParams.Name, callParams.Arguments)
+	// Check the client's rate limit for this category of tool
+	category := toolCategory(callParams.Name)
+	client := audit.ClientKey(ctx)
+	if allowed, retryAfter := s.allowRequest(ctx, callParams.Name, category, toolNamespace(callParams.Arguments)); !allowed {
+		s.recordToolCall(callParams.Name, metrics.OutcomeRateLimited, time.Since(startTime))
+		return rateLimitedResult(category, retryAfter), nil
+	}
+
+	// Count the records the call may modify against the client's write quota
+	records := s.quotaRecords(callParams.Name, callParams.Arguments)
+	if err := s.writeQuota.Reserve(client, records); err != nil {
+		if s.auditLogger != nil {
+			s.auditLogger.Log(audit.Event{
+				Level:       audit.LevelWarning,
+				Category:    category,
+				Operation:   callParams.Name,
+				User:        audit.UserFromContext(ctx),
+				ClientID:    audit.ClientIDFromContext(ctx),
+				RequestID:   audit.RequestIDFromContext(ctx),
+				Success:     false,
+				Error:       err.Error(),
+				RecordCount: records,
+			})
+		}
+		s.recordToolCall(callParams.Name, metrics.OutcomeQuotaExceeded, time.Since(startTime))
+		return quotaExceededResult(err), nil
+	}
+
+	toolCtx, span := tracing.Start(ctx, "tool "+callParams.Name, tracing.KindInternal,
+		tracing.String("mcp.tool.name", callParams.Name))
+	var opTimes *aerospike.OperationTimes
+	if s.slowCallThreshold() > 0 {
+		toolCtx, opTimes = aerospike.WithOperationTimes(toolCtx)
+	}
+	budget := s.toolTimeout(category)
+	if budget > 0 {
+		var cancel context.CancelFunc
+		toolCtx, cancel = context.WithTimeout(toolCtx, budget)
+		defer cancel()
+	}
+	var result interface{}
+	var err error
+	switch {
+	case callParams.Name == elevateRoleTool && s.elevation != nil:
+		result, err = s.callElevateRole(toolCtx, callParams.Arguments)
+	case callParams.Name == queryAuditLogTool && s.auditLogger != nil:
+		result, err = s.callQueryAuditLog(toolCtx, callParams.Arguments)
+	case callParams.Name == serverStatsTool:
+		result, err = s.callServerStats(callParams.Arguments)
+	case callParams.Name == serverInfoTool:
+		result, err = s.callServerInfo(toolCtx)
+	case callParams.Name == reloadConfigTool && s.loadConfig != nil:
+		result, err = s.callReloadConfig(toolCtx)
+	case callParams.Name == setReadOnlyTool:
+		result, err = s.callSetReadOnly(toolCtx, callParams.Arguments)
+	case (callParams.Name == listJobsTool || callParams.Name == getJobTool || callParams.Name == cancelJobTool) && s.jobs != nil:
+		result, err = s.callJobTool(toolCtx, callParams.Name, callParams.Arguments)
+	case s.client != nil && !s.client.IsConnected():
+		err = errDegraded
+	case asyncTools[callParams.Name] && s.jobs != nil && isAsync(callParams.Arguments):
+		result, err = s.startJob(ctx, callParams.Name, callParams.Arguments, records)
+	default:
+		result, err = s.tools.Call(toolCtx, callParams.Name, callParams.Arguments)
+	}
+	if err != nil && budget > 0 && errors.Is(toolCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%s exceeded its %s time limit: %w", callParams.Name, budget, err)
+	}
+	span.SetError(err)
+	span.End()
 	duration := time.Since(startTime)
+	if err != nil {
+		s.writeQuota.Release(client, records)
+		s.recordToolCall(callParams.Name, metrics.OutcomeError, duration)
+		slog.WarnContext(ctx, "Tool call failed", "duration", duration, "error", err, "error_code", errorCode(err))
+	} else {
+		s.recordToolCall(callParams.Name, metrics.OutcomeSuccess, duration)
+		slog.DebugContext(ctx, "Tool call completed", "duration", duration)
+	}
+	if opTimes != nil && duration >= s.slowCallThreshold() {
+		s.logSlowCall(ctx, callParams.Name, callParams.Arguments, result, duration, opTimes, err)
+	}
 
 	// Audit log the operation
 	if s.auditLogger != nil {
-		category := audit.CategoryRead
-		if isWriteOperation(callParams.Name) {
-			category = audit.CategoryWrite
-		}
-		if isAdminOperation(callParams.Name) {
-			category = audit.CategoryAdmin
-		}
-
 		s.auditLogger.Log(audit.Event{
 			Level:     audit.LevelAudit,
 			Category:  category,
 			Operation: callParams.Name,
+			User:      audit.UserFromContext(ctx),
+			ClientID:  audit.ClientIDFromContext(ctx),
+			RequestID: audit.RequestIDFromContext(ctx),
 			Duration:  duration,
 			Success:   err == nil,
 			Error:     errorString(err),
@@ -463,17 +968,24 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (*
 	}
 
 	if err != nil {
-		return &ToolsCallResult{
-			Content: []ContentBlock{
-				{Type: "text", Text: fmt.Sprintf("Error: %v", err)},
-			},
-			IsError: true,
-		}, nil
+		return toolErrorResult(err), nil
 	}
 
 	// Convert result to JSON string
 	resultJSON, _ := json.MarshalIndent(result, "", "  ")
 
+	// Withhold sensitive bins before the result leaves the server
+	namespace, set := s.requestScope(callParams.Arguments)
+	resultJSON, err = s.redactResult(ctx, callParams.Name, namespace, set, resultJSON)
+	if err != nil {
+		return toolErrorResult(err), nil
+	}
+
+	// Return large results as a resource link rather than inlining them
+	if limit := s.config.MaxInlineResultBytes; limit > 0 && len(resultJSON) > limit && s.results != nil {
+		return s.linkResult(callParams.Name, resultJSON), nil
+	}
+
 	return &ToolsCallResult{
 		Content: []ContentBlock{
 			{Type: "text", Text: string(resultJSON)},
@@ -481,6 +993,103 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (*
 	}, nil
 }
 
+// allowRequest charges a request to the client's rate limit for category,
+// and to namespace's if it has its own. A refused request is audited and
+// counted, and the time to wait before retrying returned.
+func (s *Server) allowRequest(ctx context.Context, operation string, category audit.Category, namespace string) (bool, time.Duration) {
+	client := audit.ClientKey(ctx)
+	allowed, retryAfter := s.rateLimiter.Allow(client, category)
+	if limiter := s.namespaceLimiter(namespace); allowed && limiter != nil {
+		allowed, retryAfter = limiter.Allow(client, category)
+	}
+	if allowed {
+		return true, 0
+	}
+
+	if s.auditLogger != nil {
+		s.auditLogger.Log(audit.Event{
+			Level:     audit.LevelWarning,
+			Category:  category,
+			Operation: operation,
+			Namespace: namespace,
+			User:      audit.UserFromContext(ctx),
+			ClientID:  audit.ClientIDFromContext(ctx),
+			RequestID: audit.RequestIDFromContext(ctx),
+			Success:   false,
+			Error:     "rate limit exceeded",
+		})
+	}
+	s.metrics.RateLimited(strings.ToLower(string(category)))
+	return false, retryAfter
+}
+
+// requestScope returns the namespace and set named in tool arguments,
+// defaulting to the configured namespace.
+func (s *Server) requestScope(args json.RawMessage) (string, string) {
+	var scope struct {
+		Namespace string `json:"namespace"`
+		SetName   string `json:"set_name"`
+	}
+	_ = json.Unmarshal(args, &scope)
+	if scope.Namespace == "" && s.config != nil {
+		scope.Namespace = s.config.Namespace
+	}
+	return scope.Namespace, scope.SetName
+}
+
+// toolNamespace returns the namespace named in tool arguments, if any.
+// Unlike requestScope, it doesn't default to the configured namespace, so
+// tools that don't take one aren't counted against its budget.
+func toolNamespace(args json.RawMessage) string {
+	var scope struct {
+		Namespace string `json:"namespace"`
+	}
+	_ = json.Unmarshal(args, &scope)
+	return scope.Namespace
+}
+
+// redactResult replaces the values of sensitive bins in a JSON response,
+// masks detected PII, and records any changes in the audit log. namespace
+// and set apply to bins that appear without their own.
+func (s *Server) redactResult(ctx context.Context, operation, namespace, set string, data []byte) ([]byte, error) {
+	redactor := s.currentRedactor()
+	if redactor == nil {
+		return data, nil
+	}
+
+	out, report, err := redactor.JSON(data, namespace, set)
+	if err != nil {
+		// Never return a response that couldn't be checked
+		return nil, fmt.Errorf("redacting response: %w", err)
+	}
+	if !report.Empty() && s.auditLogger != nil {
+		s.auditLogger.LogRedaction(ctx, operation, namespace, set, report.Redacted, report.Masked)
+	}
+	return out, nil
+}
+
+// linkResult stores a large tool result and returns a resource link to it,
+// along with a text summary for clients that don't support resource links.
+func (s *Server) linkResult(tool string, data []byte) *ToolsCallResult {
+	uri := s.results.put(tool, data)
+	return &ToolsCallResult{
+		Content: []ContentBlock{
+			{
+				Type: "text",
+				Text: fmt.Sprintf("Result of %s is %d bytes and exceeds the inline limit of %d bytes. "+
+					"Read the resource %s to retrieve it.", tool, len(data), s.config.MaxInlineResultBytes, uri),
+			},
+			{
+				Type:     "resource_link",
+				URI:      uri,
+				Name:     fmt.Sprintf("%s result", tool),
+				MimeType: "application/json",
+				Size:     len(data),
+			},
+		},
+	}
+}
+
 // isWriteOperation returns true if the operation modifies data.
 func isWriteOperation(op string) bool {
 	writeOps := map[string]bool{
@@ -488,6 +1097,7 @@ func isWriteOperation(op string) bool {
 		"delete_record": true,
 		"batch_write":   true,
 		"operate":       true,
+		"execute_udf":   true,
 	}
 	return writeOps[op]
 }
@@ -498,12 +1108,149 @@ func isAdminOperation(op string) bool {
 		"create_index": true,
 		"drop_index":   true,
 		"truncate_set": true,
+		"benchmark":    true,
+		"list_udfs":    true,
 		"register_udf": true,
 		"remove_udf":   true,
+
+		"get_namespace_config": true,
+		"set_namespace_config": true,
+
+		queryAuditLogTool: true,
+		serverInfoTool:    true,
+		reloadConfigTool:  true,
+		setReadOnlyTool:   true,
 	}
 	return adminOps[op]
 }
 
+// rateLimits returns the per-client budget for each category. Without
+// explicit rate_limits, only writes are limited.
+func rateLimits(cfg config.AuditConfig) map[audit.Category]audit.RateLimitConfig {
+	if len(cfg.RateLimits) == 0 {
+		return map[audit.Category]audit.RateLimitConfig{
+			audit.CategoryWrite: {RequestsPerSec: cfg.RateLimitRPS, BurstSize: cfg.RateLimitBurst},
+		}
+	}
+
+	return categoryLimits(cfg.RateLimits)
+}
+
+// categoryLimits converts rate limits keyed by category name.
+func categoryLimits(rateLimits map[string]config.RateLimit) map[audit.Category]audit.RateLimitConfig {
+	limits := make(map[audit.Category]audit.RateLimitConfig, len(rateLimits))
+	for category, limit := range rateLimits {
+		limits[audit.Category(strings.ToUpper(category))] = audit.RateLimitConfig{
+			RequestsPerSec: limit.RPS,
+			BurstSize:      limit.Burst,
+		}
+	}
+	return limits
+}
+
+// namespaceRateLimiters returns a rate limiter for each namespace policy
+// with rate limits.
+func namespaceRateLimiters(policies map[string]config.NamespacePolicy) map[string]*audit.ClientRateLimiter {
+	limiters := make(map[string]*audit.ClientRateLimiter)
+	for namespace, policy := range policies {
+		if len(policy.RateLimits) > 0 {
+			limiters[namespace] = audit.NewClientRateLimiter(true, categoryLimits(policy.RateLimits))
+		}
+	}
+	return limiters
+}
+
+// newAuditSink creates the syslog or webhook sink described by cfg.
+func newAuditSink(cfg config.AuditSinkConfig) (audit.Sink, error) {
+	if cfg.Type == "webhook" {
+		return audit.NewWebhookSink(audit.WebhookConfig{
+			URL:           cfg.URL,
+			Headers:       cfg.Headers,
+			BatchSize:     cfg.BatchSize,
+			FlushInterval: time.Duration(cfg.FlushIntervalMs) * time.Millisecond,
+			MaxRetries:    cfg.MaxRetries,
+			Timeout:       time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		})
+	}
+	return audit.NewSyslogSink(audit.SyslogConfig{
+		Network:  cfg.Network,
+		Address:  cfg.Address,
+		Facility: cfg.Facility,
+		AppName:  cfg.AppName,
+		CAFile:   cfg.CAFile,
+		CertFile: cfg.CertFile,
+		KeyFile:  cfg.KeyFile,
+	})
+}
+
+// toolTimeout returns the maximum execution time of tools in category, or 0
+// if they aren't limited beyond the request timeout.
+func (s *Server) toolTimeout(category audit.Category) time.Duration {
+	ms := s.config.ToolTimeoutsMs[strings.ToLower(string(category))]
+	return time.Duration(ms) * time.Millisecond
+}
+
+// toolErrorResult returns the result for a failed tool call. Its structured
+// content carries the error's code, so agents can tell a missing record from
+// a hot key or an overloaded device without parsing the message.
+func toolErrorResult(err error) *ToolsCallResult {
+	return &ToolsCallResult{
+		Content: []ContentBlock{
+			{Type: "text", Text: fmt.Sprintf("Error: %v", err)},
+		},
+		StructuredContent: map[string]interface{}{
+			"error":   errorCode(err),
+			"message": err.Error(),
+		},
+		IsError: true,
+	}
+}
+
+// errorCode classifies a tool error, including the server's own tools'
+// errors, which aerospike.Code doesn't know about.
+func errorCode(err error) aerospike.ErrorCode {
+	switch {
+	case errors.Is(err, errElevationNotFound), errors.Is(err, errJobNotFound):
+		return aerospike.ErrorNotFound
+	case errors.Is(err, errJobsBusy):
+		return aerospike.ErrorUnavailable
+	case errors.Is(err, errElevationOwner), errors.Is(err, errApprovalInvalid), errors.Is(err, errReadOnlyLift):
+		return aerospike.ErrorForbidden
+	}
+	return aerospike.Code(err)
+}
+
+// rateLimitedResult returns the error result for a rate-limited tool call,
+// including when the client may retry.
+func rateLimitedResult(category audit.Category, retryAfter time.Duration) *ToolsCallResult {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &ToolsCallResult{
+		Content: []ContentBlock{
+			{Type: "text", Text: fmt.Sprintf("Error: rate limit exceeded for %s operations, retry after %ds", strings.ToLower(string(category)), seconds)},
+		},
+		StructuredContent: map[string]interface{}{
+			"error":       "rate_limit_exceeded",
+			"category":    strings.ToLower(string(category)),
+			"retry_after": seconds,
+		},
+		IsError: true,
+	}
+}
+
+// toolCategory returns the audit category for a tool.
+func toolCategory(op string) audit.Category {
+	switch {
+	case isAdminOperation(op):
+		return audit.CategoryAdmin
+	case isWriteOperation(op):
+		return audit.CategoryWrite
+	}
+	return audit.CategoryRead
+}
+
 // errorString returns error string or empty if nil.
 func errorString(err error) string {
 	if err == nil {
@@ -521,15 +1268,30 @@ type ResourcesListResult struct {
 	Resources []resources.ResourceDefinition `json:"resources"`
 }
 
-func (s *Server) handleResourcesList(_ context.Context) (*ResourcesListResult, *Error) {
+func (s *Server) handleResourcesList(ctx context.Context) (*ResourcesListResult, *Error) {
+	definitions := s.resources.List()
+	if s.canQueryAudit(ctx) {
+		definitions = append(definitions, auditRecentResources()...)
+	}
+	definitions = append(definitions, serverMetricsResource())
+	if s.jobs != nil {
+		definitions = append(definitions, jobsResource())
+	}
 	return &ResourcesListResult{
-		Resources: s.resources.List(),
+		Resources: definitions,
 	}, nil
 }
 
 // ResourcesReadParams represents the resources/read request parameters.
 type ResourcesReadParams struct {
-	URI string `json:"uri"`
+	URI  string            `json:"uri"`
+	Meta ResourcesReadMeta `json:"_meta"`
+}
+
+// ResourcesReadMeta holds the optional conditions of a resources/read
+// request. IfNoneMatch is the ETag of the content the client already has.
+type ResourcesReadMeta struct {
+	IfNoneMatch string `json:"ifNoneMatch,omitempty"`
 }
 
 // ResourcesReadResult represents the resources/read response.
@@ -539,9 +1301,10 @@ type ResourcesReadResult struct {
 
 // ResourceContent represents a resource content block.
 type ResourceContent struct {
-	URI      string `json:"uri"`
-	MimeType string `json:"mimeType,omitempty"`
-	Text     string `json:"text,omitempty"`
+	URI      string                 `json:"uri"`
+	MimeType string                 `json:"mimeType,omitempty"`
+	Text     string                 `json:"text,omitempty"`
+	Meta     map[string]interface{} `json:"_meta,omitempty"`
 }
 
 func (s *Server) handleResourcesRead(ctx context.Context, params json.RawMessage) (*ResourcesReadResult, *Error) {
@@ -554,6 +1317,80 @@ func (s *Server) handleResourcesRead(ctx context.Context, params json.RawMessage
 		}
 	}
 
+	result, rpcErr := s.readResource(ctx, readParams)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	applyETags(result, readParams.Meta.IfNoneMatch)
+	return result, nil
+}
+
+// readResource reads the resource readParams names.
+func (s *Server) readResource(ctx context.Context, readParams ResourcesReadParams) (*ResourcesReadResult, *Error) {
+	if strings.HasPrefix(readParams.URI, resultURIPrefix) && s.results != nil {
+		data, err := s.results.get(readParams.URI)
+		if err != nil {
+			return nil, &Error{
+				Code:    InvalidParams,
+				Message: "Resource not found",
+				Data:    err.Error(),
+			}
+		}
+		return &ResourcesReadResult{
+			Contents: []ResourceContent{
+				{URI: readParams.URI, MimeType: "application/json", Text: string(data)},
+			},
+		}, nil
+	}
+
+	if readParams.URI == serverMetricsURI {
+		content, err := s.readServerMetricsResource()
+		if err != nil {
+			return nil, &Error{
+				Code:    InternalError,
+				Message: "Resource read failed",
+				Data:    err.Error(),
+			}
+		}
+		return &ResourcesReadResult{
+			Contents: []ResourceContent{
+				{URI: readParams.URI, MimeType: "application/json", Text: content},
+			},
+		}, nil
+	}
+
+	if isJobURI(readParams.URI) && s.jobs != nil {
+		content, err := s.readJobResource(ctx, readParams.URI)
+		if err != nil {
+			return nil, &Error{
+				Code:    InvalidParams,
+				Message: "Resource not found",
+				Data:    err.Error(),
+			}
+		}
+		return &ResourcesReadResult{
+			Contents: []ResourceContent{
+				{URI: readParams.URI, MimeType: "application/json", Text: content},
+			},
+		}, nil
+	}
+
+	if isAuditRecentURI(readParams.URI) {
+		content, err := s.readAuditResource(ctx, readParams.URI)
+		if err != nil {
+			return nil, &Error{
+				Code:    InternalError,
+				Message: "Resource read failed",
+				Data:    err.Error(),
+			}
+		}
+		return &ResourcesReadResult{
+			Contents: []ResourceContent{
+				{URI: readParams.URI, MimeType: "application/json", Text: content},
+			},
+		}, nil
+	}
+
 	content, mimeType, err := s.resources.Read(ctx, readParams.URI)
 	if err != nil {
 		return nil, &Error{
@@ -563,6 +1400,18 @@ func (s *Server) handleResourcesRead(ctx context.Context, params json.RawMessage
 		}
 	}
 
+	if mimeType == "application/json" {
+		redacted, err := s.redactResult(ctx, "resources/read", "", "", []byte(content))
+		if err != nil {
+			return nil, &Error{
+				Code:    InternalError,
+				Message: "Resource read failed",
+				Data:    err.Error(),
+			}
+		}
+		content = string(redacted)
+	}
+
 	return &ResourcesReadResult{
 		Contents: []ResourceContent{
 			{
@@ -580,12 +1429,86 @@ func (s *Server) handleResourcesRead(ctx context.Context, params json.RawMessage
 
 // PromptsListResult represents the prompts/list response.
 type PromptsListResult struct {
-	Prompts []interface{} `json:"prompts"`
+	Prompts []prompts.PromptDefinition `json:"prompts"`
 }
 
 func (s *Server) handlePromptsList(_ context.Context) (*PromptsListResult, *Error) {
-	// No prompts defined for now
+	definitions := []prompts.PromptDefinition{}
+	if s.prompts != nil {
+		definitions = s.prompts.List()
+	}
 	return &PromptsListResult{
-		Prompts: []interface{}{},
+		Prompts: definitions,
 	}, nil
 }
+
+// promptsGetOperation names prompts/get requests in the audit log.
+const promptsGetOperation = "prompts/get"
+
+// PromptsGetParams represents the prompts/get request parameters.
+type PromptsGetParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+func (s *Server) handlePromptsGet(ctx context.Context, params json.RawMessage) (*prompts.PromptResult, *Error) {
+	var getParams PromptsGetParams
+	if err := json.Unmarshal(params, &getParams); err != nil {
+		return nil, &Error{
+			Code:    InvalidParams,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		}
+	}
+	if s.prompts == nil {
+		return nil, &Error{
+			Code:    InvalidParams,
+			Message: "Prompt not found",
+			Data:    getParams.Name,
+		}
+	}
+
+	// Prompts read the cluster as read tools do, so they share the read
+	// rate limit, time limit, and audit trail
+	startTime := time.Now()
+	namespace := getParams.Arguments["namespace"]
+	if allowed, retryAfter := s.allowRequest(ctx, promptsGetOperation, audit.CategoryRead, namespace); !allowed {
+		return nil, &Error{
+			Code:    InvalidRequest,
+			Message: "Rate limit exceeded",
+			Data:    rateLimitedResult(audit.CategoryRead, retryAfter).StructuredContent,
+		}
+	}
+	promptCtx := ctx
+	if budget := s.toolTimeout(audit.CategoryRead); budget > 0 {
+		var cancel context.CancelFunc
+		promptCtx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	result, err := s.prompts.Get(promptCtx, getParams.Name, getParams.Arguments)
+	if s.auditLogger != nil {
+		s.auditLogger.Log(audit.Event{
+			Level:     audit.LevelAudit,
+			Category:  audit.CategoryRead,
+			Operation: promptsGetOperation,
+			Namespace: namespace,
+			Set:       getParams.Arguments["set"],
+			User:      audit.UserFromContext(ctx),
+			ClientID:  audit.ClientIDFromContext(ctx),
+			RequestID: audit.RequestIDFromContext(ctx),
+			Duration:  time.Since(startTime),
+			Success:   err == nil,
+			Error:     errorString(err),
+			Details:   map[string]interface{}{"prompt": getParams.Name},
+		})
+	}
+	if err != nil {
+		return nil, &Error{
+			Code:    InvalidParams,
+			Message: "Prompt not available",
+			Data:    err.Error(),
+		}
+	}
+	return result, nil
+}
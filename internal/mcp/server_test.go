@@ -4,9 +4,19 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/prompts"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/redact"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
 )
 
 func TestRequestParsing(t *testing.T) {
@@ -157,6 +167,24 @@ func TestInitializeResult(t *testing.T) {
 	}
 }
 
+func TestRPCID(t *testing.T) {
+	tests := []struct {
+		id   interface{}
+		want string
+	}{
+		{nil, ""},
+		{"abc", "abc"},
+		{float64(7), "7"},
+		{float64(1.5), "1.5"},
+	}
+
+	for _, tt := range tests {
+		if got := rpcID(tt.id); got != tt.want {
+			t.Errorf("Expected RPC ID %q, got %q", tt.want, got)
+		}
+	}
+}
+
 func TestToolsCallParams(t *testing.T) {
 	params := ToolsCallParams{
 		Name:      "get_record",
@@ -211,6 +239,7 @@ func TestIsWriteOperation(t *testing.T) {
 		{"delete_record", true},
 		{"batch_write", true},
 		{"operate", true},
+		{"execute_udf", true},
 		{"get_record", false},
 		{"list_namespaces", false},
 		{"cluster_info", false},
@@ -233,8 +262,10 @@ func TestIsAdminOperation(t *testing.T) {
 		{"create_index", true},
 		{"drop_index", true},
 		{"truncate_set", true},
+		{"set_namespace_config", true},
 		{"register_udf", true},
 		{"remove_udf", true},
+		{"list_udfs", true},
 		{"put_record", false},
 		{"get_record", false},
 	}
@@ -248,6 +279,25 @@ func TestIsAdminOperation(t *testing.T) {
 	}
 }
 
+func TestToolCategory(t *testing.T) {
+	tests := []struct {
+		op   string
+		want audit.Category
+	}{
+		{"get_record", audit.CategoryRead},
+		{"put_record", audit.CategoryWrite},
+		{"execute_udf", audit.CategoryWrite},
+		{"list_udfs", audit.CategoryAdmin},
+		{"create_index", audit.CategoryAdmin},
+	}
+
+	for _, tt := range tests {
+		if got := toolCategory(tt.op); got != tt.want {
+			t.Errorf("toolCategory(%s) = %v, want %v", tt.op, got, tt.want)
+		}
+	}
+}
+
 func TestErrorString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -306,3 +356,387 @@ func TestMCPConstants(t *testing.T) {
 		t.Errorf("Expected ServerVersion '0.1.0', got '%s'", ServerVersion)
 	}
 }
+
+func TestMessageIDKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"numeric id", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, "1"},
+		{"string id", `{"jsonrpc":"2.0","id":"abc","method":"tools/list"}`, `"abc"`},
+		{"notification", `{"jsonrpc":"2.0","method":"initialized"}`, ""},
+		{"null id", `{"jsonrpc":"2.0","id":null,"method":"initialized"}`, ""},
+		{"invalid json", `{"jsonrpc":`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageIDKey([]byte(tt.input)); got != tt.want {
+				t.Errorf("messageIDKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIDSequencer(t *testing.T) {
+	q := newIDSequencer()
+
+	wait1, done1 := q.enter("1")
+	if wait1 != nil {
+		t.Fatal("Expected no predecessor for first message")
+	}
+
+	wait2, done2 := q.enter("1")
+	if wait2 == nil {
+		t.Fatal("Expected second message with same ID to wait")
+	}
+
+	waitOther, doneOther := q.enter("2")
+	if waitOther != nil {
+		t.Error("Expected message with different ID not to wait")
+	}
+	doneOther()
+
+	select {
+	case <-wait2:
+		t.Fatal("Second message released before first finished")
+	default:
+	}
+
+	done1()
+	<-wait2
+	done2()
+
+	if len(q.tails) != 0 {
+		t.Errorf("Expected sequencer to be empty, got %d entries", len(q.tails))
+	}
+}
+
+func TestServeStream(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := &Server{config: cfg}
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`,
+		``,
+		`{"jsonrpc":"2.0","id":2,"method":"unknown/method"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"prompts/list"}`,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	if err := s.serveStream(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("serveStream() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 responses, got %d: %q", len(lines), out.String())
+	}
+
+	seen := make(map[float64]bool)
+	for _, line := range lines {
+		var resp map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response %q: %v", line, err)
+		}
+		id, _ := resp["id"].(float64)
+		seen[id] = true
+		if id == 2 && resp["error"] == nil {
+			t.Error("Expected error response for unknown method")
+		}
+	}
+
+	for _, id := range []float64{1, 2, 3} {
+		if !seen[id] {
+			t.Errorf("Missing response for id %v", id)
+		}
+	}
+}
+
+func TestRedactResult(t *testing.T) {
+	var auditBuf bytes.Buffer
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(&auditBuf)
+
+	s := &Server{
+		config:      config.DefaultConfig(),
+		auditLogger: auditLogger,
+		redactor:    redact.New([]config.RedactionRule{{Bin: "ssn"}}, nil),
+	}
+
+	out, err := s.redactResult(context.Background(), "get_record", "ad", "users",
+		[]byte(`{"key":"u1","namespace":"ad","set":"users","bins":{"ssn":"123-45-6789"}}`))
+	if err != nil {
+		t.Fatalf("redactResult() error = %v", err)
+	}
+	if strings.Contains(string(out), "123-45-6789") {
+		t.Errorf("Expected ssn to be redacted, got %s", out)
+	}
+	if !strings.Contains(auditBuf.String(), `"category":"REDACT"`) || !strings.Contains(auditBuf.String(), "ad.users.ssn") {
+		t.Errorf("Expected redaction audit event, got %s", auditBuf.String())
+	}
+}
+
+func TestRequestScope(t *testing.T) {
+	s := &Server{config: &config.Config{Namespace: "ad_platform"}}
+
+	ns, set := s.requestScope(json.RawMessage(`{"namespace":"test","set_name":"users","key":"1"}`))
+	if ns != "test" || set != "users" {
+		t.Errorf("Expected test/users, got %s/%s", ns, set)
+	}
+
+	ns, set = s.requestScope(json.RawMessage(`{"key":"1"}`))
+	if ns != "ad_platform" || set != "" {
+		t.Errorf("Expected default namespace, got %s/%s", ns, set)
+	}
+}
+
+func TestToolsCallDegraded(t *testing.T) {
+	s := &Server{
+		client:      &aerospike.Client{},
+		config:      config.DefaultConfig(),
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+		validator:   audit.NewValidator(audit.DefaultValidatorConfig()),
+	}
+
+	result, rpcErr := s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"get_record","arguments":{"namespace":"test","key":"k"}}`))
+	if rpcErr != nil {
+		t.Fatalf("handleToolsCall() error = %v", rpcErr)
+	}
+	if content, _ := result.StructuredContent.(map[string]interface{}); !result.IsError || content["error"] != aerospike.ErrorUnavailable {
+		t.Errorf("Expected unavailable error result, got %+v", result)
+	}
+
+	// Tools that don't need the cluster still work
+	result, rpcErr = s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"server_stats","arguments":{}}`))
+	if rpcErr != nil || result.IsError {
+		t.Errorf("Expected server_stats to succeed while degraded, got %+v, %v", result, rpcErr)
+	}
+}
+
+func TestNamespaceRateLimit(t *testing.T) {
+	s := &Server{
+		config:      config.DefaultConfig(),
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+		nsLimiters: namespaceRateLimiters(map[string]config.NamespacePolicy{
+			"prod": {RateLimits: map[string]config.RateLimit{"read": {RPS: 0.5, Burst: 1}}},
+			"test": {TimeoutMs: 5000},
+		}),
+		validator: audit.NewValidator(audit.DefaultValidatorConfig()),
+	}
+	if len(s.nsLimiters) != 1 || s.nsLimiters["prod"] == nil {
+		t.Fatalf("Expected a rate limiter for prod only, got %v", s.nsLimiters)
+	}
+	s.nsLimiters["prod"].Allow("", audit.CategoryRead)
+
+	result, err := s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"get_record","arguments":{"namespace":"prod","key":"k"}}`))
+	if err != nil {
+		t.Fatalf("handleToolsCall() error = %v", err)
+	}
+	if content, _ := result.StructuredContent.(map[string]interface{}); !result.IsError || content["error"] != "rate_limit_exceeded" {
+		t.Errorf("Expected rate limited result, got %+v", result)
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	legacy := rateLimits(config.AuditConfig{RateLimitRPS: 10, RateLimitBurst: 20})
+	if len(legacy) != 1 || legacy[audit.CategoryWrite].RequestsPerSec != 10 || legacy[audit.CategoryWrite].BurstSize != 20 {
+		t.Errorf("Expected only writes to be limited by default, got %v", legacy)
+	}
+
+	limits := rateLimits(config.AuditConfig{RateLimits: map[string]config.RateLimit{
+		"read":  {RPS: 50},
+		"admin": {RPS: 1, Burst: 2},
+	}})
+	if limits[audit.CategoryRead].RequestsPerSec != 50 || limits[audit.CategoryAdmin].BurstSize != 2 {
+		t.Errorf("Expected configured limits, got %v", limits)
+	}
+	if _, ok := limits[audit.CategoryWrite]; ok {
+		t.Error("Expected writes to be unlimited when not configured")
+	}
+}
+
+func TestToolsCallRateLimited(t *testing.T) {
+	s := &Server{
+		rateLimiter: audit.NewClientRateLimiter(true, map[audit.Category]audit.RateLimitConfig{
+			audit.CategoryWrite: {RequestsPerSec: 0.5, BurstSize: 1},
+		}),
+	}
+	// Exhaust the write budget
+	s.rateLimiter.Allow("", audit.CategoryWrite)
+
+	result, rpcErr := s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"put_record","arguments":{}}`))
+	if rpcErr != nil {
+		t.Fatalf("Unexpected error: %v", rpcErr)
+	}
+	if !result.IsError {
+		t.Fatal("Expected an error result")
+	}
+
+	data, _ := json.Marshal(result.StructuredContent)
+	if string(data) != `{"category":"write","error":"rate_limit_exceeded","retry_after":2}` {
+		t.Errorf("Expected retry hint, got %s", data)
+	}
+}
+
+func TestToolsCallQuotaExceeded(t *testing.T) {
+	s := &Server{
+		config:      config.DefaultConfig(),
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+		writeQuota:  audit.NewWriteQuota(2, 0),
+	}
+	_ = s.writeQuota.Reserve("", 1)
+
+	result, rpcErr := s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"batch_write","arguments":{"operations":[{},{}]}}`))
+	if rpcErr != nil {
+		t.Fatalf("Unexpected error: %v", rpcErr)
+	}
+	if !result.IsError {
+		t.Fatal("Expected an error result")
+	}
+
+	content, _ := result.StructuredContent.(map[string]interface{})
+	if content["error"] != "quota_exceeded" || content["window"] != "hour" || content["requested"] != 2 {
+		t.Errorf("Expected hourly quota error for 2 records, got %v", content)
+	}
+}
+
+func TestToolsCallErrorCode(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Role = config.RoleReadOnly
+	s := &Server{
+		config:      cfg,
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+	}
+
+	result, rpcErr := s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"server_info"}`))
+	if rpcErr != nil {
+		t.Fatalf("Unexpected error: %v", rpcErr)
+	}
+	if !result.IsError {
+		t.Fatal("Expected an error result")
+	}
+
+	content, _ := result.StructuredContent.(map[string]interface{})
+	if content["error"] != aerospike.ErrorForbidden || content["message"] != "tool server_info not permitted for role: read-only" {
+		t.Errorf("Expected forbidden error, got %v", content)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want aerospike.ErrorCode
+	}{
+		{errElevationNotFound, aerospike.ErrorNotFound},
+		{errApprovalInvalid, aerospike.ErrorForbidden},
+		{aerospike.ErrNotConnected, aerospike.ErrorUnavailable},
+	}
+
+	for _, tt := range tests {
+		if got := errorCode(tt.err); got != tt.want {
+			t.Errorf("Expected %q for %v, got %q", tt.want, tt.err, got)
+		}
+	}
+}
+
+func TestToolTimeout(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ToolTimeoutsMs = map[string]int{"read": 5000, "admin": 60000}
+	s := &Server{config: cfg}
+
+	tests := []struct {
+		category audit.Category
+		want     time.Duration
+	}{
+		{audit.CategoryRead, 5 * time.Second},
+		{audit.CategoryWrite, 0},
+		{audit.CategoryAdmin, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := s.toolTimeout(tt.category); got != tt.want {
+			t.Errorf("Expected %v for %s, got %v", tt.want, tt.category, got)
+		}
+	}
+}
+
+func TestQuotaRecords(t *testing.T) {
+	s := &Server{config: config.DefaultConfig()}
+
+	tests := []struct {
+		name     string
+		tool     string
+		args     string
+		expected int
+	}{
+		{"put", "put_record", `{"key":"k"}`, 1},
+		{"delete", "delete_record", `{}`, 1},
+		{"batch", "batch_write", `{"operations":[{},{},{}]}`, 3},
+		{"dry run", "put_record", `{"dry_run":true}`, 0},
+		{"udf", "execute_udf", `{}`, 1},
+		{"read", "get_record", `{}`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.quotaRecords(tt.tool, json.RawMessage(tt.args)); got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestStartMessageFinished(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RequestTimeoutMs = 1000
+	s := &Server{config: cfg}
+
+	response, finished := s.startMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+	if response == nil || response.Error != nil {
+		t.Fatalf("Expected a successful response, got %+v", response)
+	}
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Error("Expected finished to be closed once the handler returned")
+	}
+}
+
+func TestPromptsHandlers(t *testing.T) {
+	cfg := config.DefaultConfig()
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(io.Discard)
+	s := &Server{
+		config:      cfg,
+		auditLogger: auditLogger,
+		rateLimiter: audit.NewClientRateLimiter(true, map[audit.Category]audit.RateLimitConfig{
+			audit.CategoryRead: {RequestsPerSec: 0.001, BurstSize: 1},
+		}),
+	}
+
+	listed, _ := s.handlePromptsList(context.Background())
+	if listed.Prompts == nil || len(listed.Prompts) != 0 {
+		t.Errorf("Expected an empty prompt list without a registry, got %+v", listed.Prompts)
+	}
+
+	s.prompts = prompts.NewRegistry(nil, cfg)
+	listed, _ = s.handlePromptsList(context.Background())
+	if len(listed.Prompts) == 0 {
+		t.Error("Expected prompts to be listed")
+	}
+
+	params, _ := json.Marshal(PromptsGetParams{Name: "unknown"})
+	if _, rpcErr := s.handlePromptsGet(context.Background(), params); rpcErr == nil || rpcErr.Code != InvalidParams {
+		t.Errorf("Expected invalid params for an unknown prompt, got %v", rpcErr)
+	}
+	events := auditLogger.Query(audit.Query{Operation: promptsGetOperation})
+	if len(events) != 1 || events[0].Category != audit.CategoryRead || events[0].Success {
+		t.Errorf("Expected the failed prompts/get to be audited as a read, got %+v", events)
+	}
+
+	if _, rpcErr := s.handlePromptsGet(context.Background(), params); rpcErr == nil || rpcErr.Message != "Rate limit exceeded" {
+		t.Errorf("Expected prompts/get to share the read rate limit, got %v", rpcErr)
+	}
+}
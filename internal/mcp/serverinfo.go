@@ -0,0 +1,74 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// serverInfoTool is the name of the self-diagnostics tool.
+const serverInfoTool = "server_info"
+
+// callServerInfo handles the server_info tool. It describes every client's
+// limits and the cluster connection, so only admins may call it.
+func (s *Server) callServerInfo(ctx context.Context) (interface{}, error) {
+	if role := s.config.GrantedRole(ctx); !role.CanAdmin() {
+		return nil, &config.RoleError{Operation: "tool " + serverInfoTool, Role: role}
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	info := map[string]interface{}{
+		"server":         ServerName,
+		"version":        ServerVersion,
+		"uptime_seconds": math.Round(time.Since(s.started).Seconds()),
+		"go_version":     runtime.Version(),
+		"goroutines":     runtime.NumGoroutine(),
+		"memory": map[string]interface{}{
+			"heap_alloc_bytes":  mem.HeapAlloc,
+			"heap_inuse_bytes":  mem.HeapInuse,
+			"sys_bytes":         mem.Sys,
+			"gc_cycles":         mem.NumGC,
+			"gc_pause_total_ms": float64(mem.PauseTotalNs) / 1e6,
+		},
+		"transport": s.config.Transport,
+		"read_only": s.config.IsReadOnly(),
+	}
+	if s.sessions != nil {
+		info["connected_clients"] = s.sessions.count()
+	}
+	if s.rateLimiter != nil {
+		info["rate_limiter"] = s.rateLimiter.Stats()
+	}
+	if s.client != nil {
+		stats, err := s.client.ClusterStats()
+		if err != nil {
+			info["aerospike"] = map[string]interface{}{"error": err.Error()}
+		} else {
+			stats["connected"] = s.client.IsConnected()
+			info["aerospike"] = stats
+		}
+	}
+	return info, nil
+}
+
+// serverInfoDefinition describes the server_info tool.
+func serverInfoDefinition() tools.ToolDefinition {
+	return tools.ToolDefinition{
+		Name: serverInfoTool,
+		Description: "Get server diagnostics for troubleshooting: uptime, Go version, goroutines, memory, " +
+			"transport and connected clients, rate limiter state, and Aerospike client connection statistics",
+		InputSchema: tools.InputSchema{
+			Type:       "object",
+			Properties: map[string]tools.Property{},
+		},
+	}
+}
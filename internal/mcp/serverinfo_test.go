@@ -0,0 +1,71 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestServerInfoTool(t *testing.T) {
+	tests := []struct {
+		role    config.Role
+		wantErr bool
+	}{
+		{config.RoleAdmin, false},
+		{config.RoleReadWrite, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Role = tt.role
+			s := &Server{
+				config:      cfg,
+				rateLimiter: audit.NewClientRateLimiter(true, map[audit.Category]audit.RateLimitConfig{audit.CategoryWrite: {RequestsPerSec: 10}}),
+				sessions:    newSessionManager(cfg.Sessions),
+				started:     time.Now().Add(-time.Minute),
+			}
+
+			listed, _ := s.handleToolsList(context.Background())
+			found := false
+			for _, tool := range listed.Tools {
+				found = found || tool.Name == serverInfoTool
+			}
+			if found == tt.wantErr {
+				t.Errorf("Expected server_info listed=%v, got %v", !tt.wantErr, found)
+			}
+
+			params, _ := json.Marshal(ToolsCallParams{Name: serverInfoTool})
+			result, rpcErr := s.handleToolsCall(context.Background(), params)
+			if rpcErr != nil {
+				t.Fatalf("Unexpected error: %v", rpcErr)
+			}
+			if result.IsError != tt.wantErr {
+				t.Fatalf("Expected IsError=%v, got %s", tt.wantErr, result.Content[0].Text)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var info map[string]interface{}
+			if err := json.Unmarshal([]byte(result.Content[0].Text), &info); err != nil {
+				t.Fatal(err)
+			}
+			for _, field := range []string{"go_version", "goroutines", "memory", "transport", "connected_clients", "rate_limiter"} {
+				if _, ok := info[field]; !ok {
+					t.Errorf("Expected %s in server_info, got %v", field, info)
+				}
+			}
+			if uptime, _ := info["uptime_seconds"].(float64); uptime < 60 {
+				t.Errorf("Expected uptime of at least 60s, got %v", info["uptime_seconds"])
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/metrics"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/resources"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+)
+
+// serverStatsTool is the name of the per-tool statistics tool.
+const serverStatsTool = "server_stats"
+
+// serverMetricsURI is the resource holding the per-tool statistics.
+const serverMetricsURI = "aerospike://server/metrics"
+
+// recordToolCall records a tool call in the in-process statistics and, if
+// enabled, the Prometheus metrics.
+func (s *Server) recordToolCall(tool, outcome string, duration time.Duration) {
+	s.stats.Record(tool, outcome, duration)
+	s.metrics.ToolCall(tool, outcome, duration)
+}
+
+// serverStats returns the statistics of every tool, or only of tool if it is
+// not empty.
+func (s *Server) serverStats(tool string) map[string]interface{} {
+	all := s.stats.Snapshot()
+	stats := make([]metrics.ToolStat, 0, len(all))
+	for _, stat := range all {
+		if tool == "" || stat.Tool == tool {
+			stats = append(stats, stat)
+		}
+	}
+	return map[string]interface{}{
+		"uptime_seconds": math.Round(s.stats.Uptime().Seconds()),
+		"tools":          stats,
+	}
+}
+
+// callServerStats handles the server_stats tool.
+func (s *Server) callServerStats(raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Tool string `json:"tool"`
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	return s.serverStats(args.Tool), nil
+}
+
+// readServerMetricsResource handles reads of aerospike://server/metrics.
+func (s *Server) readServerMetricsResource() (string, error) {
+	data, err := json.MarshalIndent(s.serverStats(""), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// serverMetricsResource describes the per-tool statistics resource.
+func serverMetricsResource() resources.ResourceDefinition {
+	return resources.ResourceDefinition{
+		URI:         serverMetricsURI,
+		Name:        "Server Tool Statistics",
+		Description: "Call counts, error rates, and latency percentiles of each tool since the server started, most called first",
+		MimeType:    "application/json",
+	}
+}
+
+// serverStatsDefinition describes the server_stats tool.
+func serverStatsDefinition() tools.ToolDefinition {
+	return tools.ToolDefinition{
+		Name: serverStatsTool,
+		Description: "Get call counts, error rates, and latency percentiles (p50, p95, p99) of each tool since the server started. " +
+			"Percentiles cover each tool's most recent 1024 calls.",
+		InputSchema: tools.InputSchema{
+			Type: "object",
+			Properties: map[string]tools.Property{
+				"tool": {Type: "string", Description: "Only report this tool"},
+			},
+		},
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/metrics"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestServerStatsTool(t *testing.T) {
+	s := &Server{
+		config:      config.DefaultConfig(),
+		rateLimiter: audit.NewClientRateLimiter(false, nil),
+		stats:       metrics.NewToolStats(),
+	}
+	s.recordToolCall("get_record", metrics.OutcomeSuccess, 0)
+	s.recordToolCall("put_record", metrics.OutcomeError, 0)
+
+	tests := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{"all", `{}`, []string{"get_record", "put_record"}},
+		{"one tool", `{"tool":"put_record"}`, []string{"put_record"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _ := json.Marshal(ToolsCallParams{Name: serverStatsTool, Arguments: json.RawMessage(tt.args)})
+			result, rpcErr := s.handleToolsCall(context.Background(), params)
+			if rpcErr != nil {
+				t.Fatalf("Unexpected error: %v", rpcErr)
+			}
+			if result.IsError {
+				t.Fatalf("Unexpected error result: %s", result.Content[0].Text)
+			}
+
+			var out struct {
+				Tools []metrics.ToolStat `json:"tools"`
+			}
+			if err := json.Unmarshal([]byte(result.Content[0].Text), &out); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, stat := range out.Tools {
+				got = append(got, stat.Tool)
+			}
+			if len(got) != len(tt.want) || got[0] != tt.want[0] {
+				t.Errorf("Expected tools %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestServerMetricsResource(t *testing.T) {
+	s := &Server{config: config.DefaultConfig(), stats: metrics.NewToolStats()}
+	s.recordToolCall("get_record", metrics.OutcomeSuccess, 0)
+
+	params, _ := json.Marshal(ResourcesReadParams{URI: serverMetricsURI})
+	result, rpcErr := s.handleResourcesRead(context.Background(), params)
+	if rpcErr != nil {
+		t.Fatalf("Unexpected error: %v", rpcErr)
+	}
+
+	var out struct {
+		UptimeSeconds float64            `json:"uptime_seconds"`
+		Tools         []metrics.ToolStat `json:"tools"`
+	}
+	if err := json.Unmarshal([]byte(result.Contents[0].Text), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Tools) != 1 || out.Tools[0].Tool != "get_record" || out.Tools[0].Calls != 1 {
+		t.Errorf("Expected one get_record call, got %+v", out.Tools)
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// Session errors returned to HTTP clients.
+var (
+	errSessionInvalid  = errors.New("invalid session token")
+	errSessionExpired  = errors.New("session expired")
+	errSessionNotFound = errors.New("session not found")
+	errSessionOwner    = errors.New("session belongs to another client")
+	errTooManySessions = errors.New("too many sessions for this client")
+)
+
+// session is an HTTP transport session. Its token is signed and names the
+// session, its version, and its expiry; rotating the token bumps the version
+// so earlier tokens stop working.
+type session struct {
+	id      string
+	owner   string // audit user that created the session; "" if unauthenticated
+	version int
+	expires time.Time
+	timer   *time.Timer
+	done    chan struct{} // closed when the session ends
+}
+
+// sessionManager issues and verifies the session tokens used by the SSE and
+// WebSocket transports. Tokens are signed with a key generated at startup,
+// so they can't be guessed or forged, and sessions end when they expire,
+// when the client logs out, or when the transport drops the connection.
+type sessionManager struct {
+	key          []byte
+	ttl          time.Duration
+	maxPerClient int
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// newSessionManager creates a session manager with a random signing key.
+func newSessionManager(cfg config.SessionConfig) *sessionManager {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("generating session key: %v", err))
+	}
+
+	ttl := time.Duration(cfg.TTLSec) * time.Second
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+
+	return &sessionManager{
+		key:          key,
+		ttl:          ttl,
+		maxPerClient: cfg.MaxPerClient,
+		sessions:     make(map[string]*session),
+	}
+}
+
+// create starts a session for owner and returns it with its token.
+func (m *sessionManager) create(owner string) (*session, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if owner != "" && m.maxPerClient > 0 {
+		count := 0
+		for _, sess := range m.sessions {
+			if sess.owner == owner {
+				count++
+			}
+		}
+		if count >= m.maxPerClient {
+			return nil, "", errTooManySessions
+		}
+	}
+
+	sess := &session{
+		id:      uuid.New().String(),
+		owner:   owner,
+		expires: time.Now().Add(m.ttl),
+		done:    make(chan struct{}),
+	}
+	id := sess.id
+	sess.timer = time.AfterFunc(m.ttl, func() {
+		slog.Info("Session expired", "client_id", id)
+		m.end(id)
+	})
+	m.sessions[sess.id] = sess
+	return sess, m.sign(sess), nil
+}
+
+// verify returns the session named by token if the token is authentic,
+// current, and unexpired, and the session belongs to owner.
+func (m *sessionManager) verify(token, owner string) (*session, error) {
+	id, version, expires, err := m.parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(expires) {
+		return nil, errSessionExpired
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess, ok := m.sessions[id]
+	if !ok || sess.version != version {
+		return nil, errSessionNotFound
+	}
+	if sess.owner != owner {
+		return nil, errSessionOwner
+	}
+	return sess, nil
+}
+
+// rotate replaces a session's token with a new one carrying a fresh expiry.
+// The old token stops working.
+func (m *sessionManager) rotate(token, owner string) (*session, string, error) {
+	sess, err := m.verify(token, owner)
+	if err != nil {
+		return nil, "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess.version++
+	sess.expires = time.Now().Add(m.ttl)
+	sess.timer.Reset(m.ttl)
+	return sess, m.sign(sess), nil
+}
+
+// end terminates a session. It is safe to call more than once.
+func (m *sessionManager) end(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sess, ok := m.sessions[id]
+	if !ok {
+		return
+	}
+	sess.timer.Stop()
+	close(sess.done)
+	delete(m.sessions, id)
+}
+
+// count returns the number of active sessions.
+func (m *sessionManager) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// sign returns the token for the session's current version and expiry. The
+// caller must hold m.mu or have exclusive access to sess.
+func (m *sessionManager) sign(sess *session) string {
+	payload := fmt.Sprintf("%s.%d.%d", sess.id, sess.version, sess.expires.Unix())
+	return payload + "." + m.mac(payload)
+}
+
+// parse checks a token's signature and returns its fields.
+func (m *sessionManager) parse(token string) (string, int, time.Time, error) {
+	i := strings.LastIndex(token, ".")
+	if i < 0 {
+		return "", 0, time.Time{}, errSessionInvalid
+	}
+	payload, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(m.mac(payload))) {
+		return "", 0, time.Time{}, errSessionInvalid
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 3 {
+		return "", 0, time.Time{}, errSessionInvalid
+	}
+	version, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, time.Time{}, errSessionInvalid
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", 0, time.Time{}, errSessionInvalid
+	}
+	return parts[0], version, time.Unix(expires, 0), nil
+}
+
+func (m *sessionManager) mac(payload string) string {
+	h := hmac.New(sha256.New, m.key)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// sessionStatus returns the HTTP status for a session error.
+func sessionStatus(err error) int {
+	switch {
+	case errors.Is(err, errTooManySessions):
+		return http.StatusTooManyRequests
+	case errors.Is(err, errSessionOwner):
+		return http.StatusForbidden
+	case errors.Is(err, errSessionNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusUnauthorized
+}
+
+// startSession creates a session for the request's authenticated user,
+// writing an error response if the user has too many sessions.
+func (s *Server) startSession(w http.ResponseWriter, r *http.Request) (*session, string, bool) {
+	sess, token, err := s.sessions.create(audit.UserFromContext(r.Context()))
+	if err != nil {
+		s.sessionError(w, r, "session_create", err)
+		return nil, "", false
+	}
+	return sess, token, true
+}
+
+// requireSession verifies a session token sent with a request, writing an
+// error response if it is missing or not valid for the request's user.
+func (s *Server) requireSession(w http.ResponseWriter, r *http.Request, token string) (*session, bool) {
+	if token == "" {
+		http.Error(w, "Missing session token", http.StatusBadRequest)
+		return nil, false
+	}
+	sess, err := s.sessions.verify(token, audit.UserFromContext(r.Context()))
+	if err != nil {
+		s.sessionError(w, r, "session_verify", err)
+		return nil, false
+	}
+	return sess, true
+}
+
+// handleSession rotates (POST) or ends (DELETE) the session named by token.
+// Ending a session disconnects its transport client.
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request, token string) {
+	switch r.Method {
+	case http.MethodPost:
+		sess, newToken, err := s.sessions.rotate(token, audit.UserFromContext(r.Context()))
+		if err != nil {
+			s.sessionError(w, r, "session_rotate", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(sessionResponse(sess, newToken))
+
+	case http.MethodDelete:
+		sess, ok := s.requireSession(w, r, token)
+		if !ok {
+			return
+		}
+		s.sessions.end(sess.id)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// sessionError writes the response for a session error and records the
+// failure in the audit log. Tokens are never logged.
+func (s *Server) sessionError(w http.ResponseWriter, r *http.Request, operation string, err error) {
+	if s.auditLogger != nil {
+		s.auditLogger.LogAuth(r.Context(), operation, false, map[string]interface{}{
+			"path":        r.URL.Path,
+			"remote_addr": r.RemoteAddr,
+			"reason":      err.Error(),
+		})
+	}
+	http.Error(w, err.Error(), sessionStatus(err))
+}
+
+// sessionResponse describes a newly issued session token.
+func sessionResponse(sess *session, token string) map[string]string {
+	return map[string]string{
+		"session_id": token,
+		"expires_at": sess.expires.UTC().Format(time.RFC3339),
+	}
+}
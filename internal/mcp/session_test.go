@@ -0,0 +1,201 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestSessionVerify(t *testing.T) {
+	m := newSessionManager(config.SessionConfig{TTLSec: 60})
+	sess, token, err := m.create("ci")
+	if err != nil {
+		t.Fatalf("create() error = %v", err)
+	}
+
+	got, err := m.verify(token, "ci")
+	if err != nil {
+		t.Fatalf("verify() error = %v", err)
+	}
+	if got.id != sess.id {
+		t.Errorf("Expected session %s, got %s", sess.id, got.id)
+	}
+
+	other := newSessionManager(config.SessionConfig{TTLSec: 60})
+	tests := []struct {
+		name  string
+		token string
+		owner string
+		want  error
+	}{
+		{"wrong owner", token, "other", errSessionOwner},
+		{"bare session id", sess.id, "ci", errSessionInvalid},
+		{"tampered version", strings.Replace(token, ".0.", ".1.", 1), "ci", errSessionInvalid},
+		{"signed by another server", other.sign(sess), "ci", errSessionInvalid},
+		{"empty", "", "ci", errSessionInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.verify(tt.token, tt.owner); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSessionRotate(t *testing.T) {
+	m := newSessionManager(config.SessionConfig{TTLSec: 60})
+	_, token, _ := m.create("")
+
+	_, rotated, err := m.rotate(token, "")
+	if err != nil {
+		t.Fatalf("rotate() error = %v", err)
+	}
+	if _, err := m.verify(rotated, ""); err != nil {
+		t.Errorf("Expected rotated token to verify, got %v", err)
+	}
+	if _, err := m.verify(token, ""); !errors.Is(err, errSessionNotFound) {
+		t.Errorf("Expected old token to be rejected, got %v", err)
+	}
+}
+
+func TestSessionEnd(t *testing.T) {
+	m := newSessionManager(config.SessionConfig{TTLSec: 60})
+	sess, token, _ := m.create("")
+
+	m.end(sess.id)
+	m.end(sess.id)
+
+	select {
+	case <-sess.done:
+	default:
+		t.Error("Expected session to be done")
+	}
+	if _, err := m.verify(token, ""); !errors.Is(err, errSessionNotFound) {
+		t.Errorf("Expected ended session to be rejected, got %v", err)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	m := newSessionManager(config.SessionConfig{TTLSec: 60})
+	m.ttl = 10 * time.Millisecond
+	sess, _, _ := m.create("")
+
+	select {
+	case <-sess.done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected session to expire")
+	}
+
+	m.mu.Lock()
+	count := len(m.sessions)
+	m.mu.Unlock()
+	if count != 0 {
+		t.Errorf("Expected expired session to be removed, got %d sessions", count)
+	}
+}
+
+func TestSessionLimit(t *testing.T) {
+	m := newSessionManager(config.SessionConfig{TTLSec: 60, MaxPerClient: 2})
+
+	first, _, _ := m.create("ci")
+	if _, _, err := m.create("ci"); err != nil {
+		t.Fatalf("create() error = %v", err)
+	}
+	if _, _, err := m.create("ci"); !errors.Is(err, errTooManySessions) {
+		t.Errorf("Expected %v, got %v", errTooManySessions, err)
+	}
+	if _, _, err := m.create("other"); err != nil {
+		t.Errorf("Expected other client to get a session, got %v", err)
+	}
+
+	// Ending a session frees a slot
+	m.end(first.id)
+	if _, _, err := m.create("ci"); err != nil {
+		t.Errorf("Expected session after logout, got %v", err)
+	}
+}
+
+func TestWebSocketSessionEndpoints(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := &Server{config: cfg, sessions: newSessionManager(cfg.Sessions)}
+	ws := NewWebSocketServer(s, 0)
+	handler := ws.Handler()
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("X-Client-ID", token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var connected map[string]string
+	if err := json.Unmarshal(do(http.MethodGet, "/ws", "").Body.Bytes(), &connected); err != nil {
+		t.Fatalf("Failed to decode /ws response: %v", err)
+	}
+	token := connected["client_id"]
+	if connected["expires_at"] == "" {
+		t.Error("Expected expires_at in /ws response")
+	}
+
+	rec := do(http.MethodPost, "/ws/session", token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 from rotate, got %d", rec.Code)
+	}
+	var rotated map[string]string
+	_ = json.Unmarshal(rec.Body.Bytes(), &rotated)
+
+	if rec := do(http.MethodGet, "/ws/receive", token); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for rotated-out token, got %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/ws/session", rotated["session_id"]); rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204 from logout, got %d", rec.Code)
+	}
+
+	// Logout disconnects the client asynchronously
+	deadline := time.Now().Add(time.Second)
+	for {
+		ws.mu.RLock()
+		n := len(ws.clients)
+		ws.mu.RUnlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected client to be disconnected after logout")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSSESessionOwner(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := &Server{config: cfg, sessions: newSessionManager(cfg.Sessions)}
+	sse := NewSSEServer(s, 0)
+
+	sess, token, _ := s.sessions.create("ci")
+	sse.clients[sess.id] = &SSEClient{id: sess.id, messages: make(chan []byte, 1), done: make(chan struct{})}
+
+	req := httptest.NewRequest(http.MethodPost, "/message?sessionId="+url.QueryEscape(token), strings.NewReader("{}"))
+	req = req.WithContext(audit.WithUser(req.Context(), "other"))
+	rec := httptest.NewRecorder()
+	sse.handleMessage(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for another client's session, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tools"
+)
+
+// maxSummaryString is the length at which string arguments are cut short in
+// slow call summaries.
+const maxSummaryString = 64
+
+// slowCallThreshold returns the duration above which tool calls are logged
+// as slow, or 0 if slow calls aren't logged.
+func (s *Server) slowCallThreshold() time.Duration {
+	return time.Duration(s.config.SlowCallThresholdMs) * time.Millisecond
+}
+
+// logSlowCall logs and audits a tool call that took at least the slow call
+// threshold, with a summary of its arguments, the records it returned, and
+// the time spent in cluster operations.
+func (s *Server) logSlowCall(ctx context.Context, tool string, args json.RawMessage, result interface{}, duration time.Duration, ops *aerospike.OperationTimes, err error) {
+	opCount, opTime := ops.Totals()
+	summary := summarizeArgs(args)
+	records := resultRecords(result)
+
+	slog.WarnContext(ctx, "Slow tool call",
+		"duration", duration,
+		"threshold", s.slowCallThreshold(),
+		"arguments", summary,
+		"records", records,
+		"aerospike_ops", opCount,
+		"aerospike_time", opTime,
+		"error", errorString(err),
+	)
+
+	if s.auditLogger != nil {
+		s.auditLogger.Log(audit.Event{
+			Level:       audit.LevelWarning,
+			Category:    toolCategory(tool),
+			Operation:   "slow_call",
+			User:        audit.UserFromContext(ctx),
+			ClientID:    audit.ClientIDFromContext(ctx),
+			RequestID:   audit.RequestIDFromContext(ctx),
+			Duration:    duration,
+			Success:     err == nil,
+			Error:       errorString(err),
+			RecordCount: records,
+			Details: map[string]interface{}{
+				"tool":              tool,
+				"threshold_ms":      s.config.SlowCallThresholdMs,
+				"arguments":         summary,
+				"aerospike_ops":     opCount,
+				"aerospike_time_ms": float64(opTime.Microseconds()) / 1000,
+			},
+		})
+	}
+}
+
+// summarizeArgs describes tool arguments without their data: strings,
+// numbers, and booleans are shown (long strings cut short), objects and
+// arrays only by size. Bin values and record contents aren't logged.
+func summarizeArgs(raw json.RawMessage) string {
+	var args map[string]interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &args) != nil {
+		return ""
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		var value string
+		switch v := args[name].(type) {
+		case string:
+			if len(v) > maxSummaryString {
+				v = v[:maxSummaryString] + "..."
+			}
+			value = fmt.Sprintf("%q", v)
+		case map[string]interface{}:
+			value = fmt.Sprintf("{%d fields}", len(v))
+		case []interface{}:
+			value = fmt.Sprintf("[%d items]", len(v))
+		default:
+			value = fmt.Sprint(v)
+		}
+		parts = append(parts, name+"="+value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// resultRecords returns the number of records in a tool result: the length
+// of a list of records, or 1 for a single record.
+func resultRecords(result interface{}) int {
+	switch r := result.(type) {
+	case *aerospike.Record:
+		if r != nil {
+			return 1
+		}
+		return 0
+	case *tools.PartialRecords:
+		return len(r.Records)
+	case nil:
+		return 0
+	}
+	if v := reflect.ValueOf(result); v.Kind() == reflect.Slice {
+		return v.Len()
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestSummarizeArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{"empty", ``, ""},
+		{"scalars", `{"namespace":"test","set_name":"users","max_records":1000}`, `max_records=1000 namespace="test" set_name="users"`},
+		{"data by size", `{"bins":{"name":"Alice","email":"a@example.com"},"keys":["k1","k2","k3"]}`, `bins={2 fields} keys=[3 items]`},
+		{"long string", `{"key":"` + strings.Repeat("x", 100) + `"}`, `key="` + strings.Repeat("x", 64) + `..."`},
+		{"not an object", `[1,2]`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeArgs(json.RawMessage(tt.args)); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResultRecords(t *testing.T) {
+	tests := []struct {
+		name   string
+		result interface{}
+		want   int
+	}{
+		{"nil", nil, 0},
+		{"record", &aerospike.Record{}, 1},
+		{"nil record", (*aerospike.Record)(nil), 0},
+		{"records", []*aerospike.Record{{}, {}, {}}, 3},
+		{"other", map[string]interface{}{"status": "ok"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultRecords(tt.result); got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLogSlowCall(t *testing.T) {
+	auditLogger, _ := audit.NewLogger(audit.Config{Enabled: true})
+	auditLogger.SetOutput(io.Discard)
+	cfg := config.DefaultConfig()
+	cfg.SlowCallThresholdMs = 500
+	s := &Server{config: cfg, auditLogger: auditLogger}
+
+	_, ops := aerospike.WithOperationTimes(context.Background())
+	args := json.RawMessage(`{"namespace":"test","set_name":"events"}`)
+	records := []*aerospike.Record{{}, {}}
+	s.logSlowCall(context.Background(), "scan_set", args, records, 2*time.Second, ops, errors.New("timeout"))
+
+	events := auditLogger.GetRecentEvents(1)
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 audit event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Operation != "slow_call" || event.Level != audit.LevelWarning || event.Category != audit.CategoryRead {
+		t.Errorf("Expected a READ slow_call warning, got %s %s %s", event.Category, event.Operation, event.Level)
+	}
+	if event.RecordCount != 2 || event.Success || event.Duration != 2*time.Second {
+		t.Errorf("Expected 2 records, failure, and 2s, got %d, %v, and %v", event.RecordCount, event.Success, event.Duration)
+	}
+	if event.Details["tool"] != "scan_set" || event.Details["arguments"] != `namespace="test" set_name="events"` {
+		t.Errorf("Expected tool and argument summary in details, got %v", event.Details)
+	}
+}
+
+func TestSlowCallThresholdDisabled(t *testing.T) {
+	s := &Server{config: config.DefaultConfig()}
+	if threshold := s.slowCallThreshold(); threshold != 0 {
+		t.Errorf("Expected slow call logging disabled by default, got %v", threshold)
+	}
+}
@@ -6,14 +6,19 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
 )
 
 // SSEServer handles Server-Sent Events transport for MCP.
@@ -22,48 +27,187 @@ type SSEServer struct {
 	port    int
 	clients map[string]*SSEClient
 	mu      sync.RWMutex
+
+	keepalive    time.Duration // interval between ": ping" comments
+	writeTimeout time.Duration // per event written to a connection
+	grace        time.Duration // how long a dropped client's session waits for it
+	replaySize   int           // events kept per client for resumption
+	compression  bool          // compress streams for clients that accept it
+}
+
+// sseStream is one connection's event stream, compressed if the client
+// accepts a content encoding.
+type sseStream struct {
+	w http.ResponseWriter
+	z compressor // nil when uncompressed
+}
+
+// openStream starts the response for an SSE connection, choosing its
+// content encoding from the request's Accept-Encoding header.
+func (s *SSEServer) openStream(w http.ResponseWriter, r *http.Request) *sseStream {
+	stream := &sseStream{w: w}
+	if !s.compression {
+		return stream
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	if encoding := negotiateEncoding(r.Header.Get("Accept-Encoding")); encoding != "" {
+		w.Header().Set("Content-Encoding", encoding)
+		stream.z = newCompressor(w, encoding)
+	}
+	return stream
+}
+
+// close ends the compressed stream, if any.
+func (st *sseStream) close() {
+	if st.z != nil {
+		_ = st.z.Close()
+	}
 }
 
-// SSEClient represents a connected SSE client.
+// SSEClient represents a connected SSE client. Its session outlives the
+// connection: a client that drops can reconnect within the grace period and
+// resume the stream from the last event it received.
 type SSEClient struct {
-	id       string
-	messages chan []byte
-	done     chan struct{}
+	id          string
+	messages    chan []byte
+	done        chan struct{}
+	unsubscribe func()
+
+	// stream is held by the connection currently writing events, so a
+	// reconnecting client waits for the previous connection to let go
+	stream sync.Mutex
+
+	mu     sync.Mutex
+	nextID uint64
+	replay []sseEvent    // the most recent events, oldest first
+	conn   chan struct{} // closed when a newer connection takes over; nil while disconnected
+	expiry *time.Timer   // ends the session of a disconnected client
+}
+
+// sseEvent is a message event with the ID clients resume from.
+type sseEvent struct {
+	id   uint64
+	data []byte
+}
+
+// send queues a message for the client, dropping it if the client's buffer
+// is full.
+func (c *SSEClient) send(msg []byte) {
+	select {
+	case c.messages <- msg:
+	default:
+	}
+}
+
+// record assigns msg the next event ID and keeps it for replay, discarding
+// the oldest event beyond size.
+func (c *SSEClient) record(msg []byte, size int) sseEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nextID++
+	event := sseEvent{id: c.nextID, data: msg}
+	c.replay = append(c.replay, event)
+	if len(c.replay) > size {
+		c.replay = c.replay[len(c.replay)-size:]
+	}
+	return event
+}
+
+// since returns the kept events after lastID, and whether some events after
+// lastID have already been discarded.
+func (c *SSEClient) since(lastID uint64) ([]sseEvent, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var events []sseEvent
+	for _, event := range c.replay {
+		if event.id > lastID {
+			events = append(events, event)
+		}
+	}
+	oldest := c.nextID - uint64(len(c.replay)) + 1
+	return events, lastID+1 < oldest
+}
+
+// attach makes a new connection the client's stream, taking over from any
+// earlier connection and cancelling a pending expiry. The returned channel
+// is closed if another connection takes over in turn.
+func (c *SSEClient) attach() chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		close(c.conn)
+	}
+	if c.expiry != nil {
+		c.expiry.Stop()
+		c.expiry = nil
+	}
+	c.conn = make(chan struct{})
+	return c.conn
+}
+
+// detach records that conn dropped. Unless another connection has already
+// taken over, expire runs if the client doesn't reconnect within grace.
+func (c *SSEClient) detach(conn chan struct{}, grace time.Duration, expire func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != conn {
+		return
+	}
+	c.conn = nil
+	c.expiry = time.AfterFunc(grace, expire)
 }
 
 // NewSSEServer creates a new SSE server.
 func NewSSEServer(server *Server, port int) *SSEServer {
+	cfg := config.SSEConfig{KeepaliveSec: 15, WriteTimeoutSec: 10, ReconnectGraceSec: 30, ReplayBufferSize: 100, Compression: true}
+	if server.config != nil && server.config.SSE.KeepaliveSec > 0 {
+		cfg = server.config.SSE
+	}
+
 	return &SSEServer{
-		server:  server,
-		port:    port,
-		clients: make(map[string]*SSEClient),
+		server:       server,
+		port:         port,
+		clients:      make(map[string]*SSEClient),
+		keepalive:    time.Duration(cfg.KeepaliveSec) * time.Second,
+		writeTimeout: time.Duration(cfg.WriteTimeoutSec) * time.Second,
+		grace:        time.Duration(cfg.ReconnectGraceSec) * time.Second,
+		replaySize:   cfg.ReplayBufferSize,
+		compression:  cfg.Compression,
 	}
 }
 
 // Run starts the SSE HTTP server.
 func (s *SSEServer) Run(ctx context.Context) error {
-	mux := http.NewServeMux()
-
-	// SSE endpoint for receiving events
-	mux.HandleFunc("/sse", s.handleSSE)
+	addr := fmt.Sprintf(":%d", s.port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
 
-	// Message endpoint for sending requests
-	mux.HandleFunc("/message", s.handleMessage)
+	tlsCfg := s.server.config.ServerTLS
+	if listener, err = serverTLSListener(tlsCfg, listener); err != nil {
+		return fmt.Errorf("configuring SSE TLS: %w", err)
+	}
 
-	// Health check
-	mux.HandleFunc("/health", s.handleHealth)
+	slog.Info("SSE server listening", "addr", addr, "tls", tlsCfg.Enabled)
+	return s.Serve(ctx, listener)
+}
 
-	addr := fmt.Sprintf(":%d", s.port)
+// Serve runs the SSE HTTP endpoints on the given listener until ctx is done.
+func (s *SSEServer) Serve(ctx context.Context, listener net.Listener) error {
 	httpServer := &http.Server{
-		Addr:    addr,
-		Handler: mux,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
 	}
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("SSE server listening on %s", addr)
-		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
-			log.Printf("HTTP server error: %v", err)
+		if err := httpServer.Serve(listener); err != http.ErrServerClosed {
+			slog.Error("HTTP server error", "error", err)
 		}
 	}()
 
@@ -77,66 +221,202 @@ func (s *SSEServer) Run(ctx context.Context) error {
 	return httpServer.Shutdown(shutdownCtx)
 }
 
-// handleSSE handles new SSE connections.
+// Handler returns the HTTP handler serving the SSE endpoints.
+func (s *SSEServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	auth := s.server.authenticate
+
+	// SSE endpoint for receiving events
+	mux.Handle("/sse", auth(http.HandlerFunc(s.handleSSE)))
+
+	// Message endpoint for sending requests
+	mux.Handle("/message", s.server.traceHTTP(auth(http.HandlerFunc(s.handleMessage))))
+
+	// Session rotation and logout
+	mux.Handle("/session", auth(http.HandlerFunc(s.handleSession)))
+
+	// Health, readiness, and liveness checks
+	s.server.handleHealthEndpoints(mux, s.clientCount)
+
+	// Prometheus metrics, unless served on their own listener
+	s.server.handleMetrics(mux)
+
+	return s.server.restrictSource(mux)
+}
+
+// handleSSE handles SSE connections. A request with a sessionId resumes
+// that session's stream after the event named by the Last-Event-ID header,
+// or from the oldest kept event without one; otherwise it starts a new
+// session.
 func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
+	var lastID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		lastID = id
+	}
+
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-	// Create new client
-	clientID := uuid.New().String()
-	client := &SSEClient{
-		id:       clientID,
-		messages: make(chan []byte, 100),
-		done:     make(chan struct{}),
+	client, sess, token, ok := s.connect(w, r)
+	if !ok {
+		return
 	}
+	conn := client.attach()
+	client.stream.Lock()
+	defer client.stream.Unlock()
 
-	s.mu.Lock()
-	s.clients[clientID] = client
-	s.mu.Unlock()
+	stream := s.openStream(w, r)
+	defer stream.close()
 
-	log.Printf("SSE client connected: %s", clientID)
+	ctx := audit.WithClientID(r.Context(), client.id)
 
-	// Send initial endpoint event with message URL
-	messageURL := fmt.Sprintf("/message?sessionId=%s", clientID)
-	initialEvent := fmt.Sprintf("event: endpoint\ndata: %s\n\n", messageURL)
-	if _, err := w.Write([]byte(initialEvent)); err != nil {
-		log.Printf("Error sending initial event: %v", err)
+	// Send the endpoint event with the message URL, then any missed events
+	messageURL := "/message?sessionId=" + url.QueryEscape(token)
+	if err := s.write(stream, fmt.Sprintf("event: endpoint\ndata: %s\n\n", messageURL)); err != nil {
+		slog.WarnContext(ctx, "Error sending initial event", "error", err)
+		client.detach(conn, s.grace, func() { s.remove(client) })
 		return
 	}
-	if f, ok := w.(http.Flusher); ok {
-		f.Flush()
+	if r.URL.Query().Has("sessionId") {
+		events, missed := client.since(lastID)
+		if missed {
+			slog.WarnContext(ctx, "Events lost before SSE client resumed", "last_event_id", lastID)
+		}
+		slog.InfoContext(ctx, "SSE client resumed", "last_event_id", lastID, "replayed", len(events))
+		for _, event := range events {
+			if err := s.writeMessage(stream, event); err != nil {
+				client.detach(conn, s.grace, func() { s.remove(client) })
+				return
+			}
+		}
 	}
 
-	// Send messages to client
-	defer func() {
-		s.mu.Lock()
-		delete(s.clients, clientID)
-		s.mu.Unlock()
-		close(client.done)
-		log.Printf("SSE client disconnected: %s", clientID)
-	}()
+	keepalive := time.NewTicker(s.keepalive)
+	defer keepalive.Stop()
 
 	for {
+		var err error
 		select {
 		case msg := <-client.messages:
-			// Format as SSE event
-			event := fmt.Sprintf("event: message\ndata: %s\n\n", string(msg))
-			if _, err := w.Write([]byte(event)); err != nil {
-				return
-			}
-			if f, ok := w.(http.Flusher); ok {
-				f.Flush()
-			}
+			err = s.writeMessage(stream, client.record(msg, s.replaySize))
+
+		case <-keepalive.C:
+			err = s.write(stream, ": ping\n\n")
+
+		case <-conn:
+			// A newer connection resumed the stream
+			return
+
+		case <-sess.done:
+			// Session expired or logged out
+			s.remove(client)
+			return
 
 		case <-r.Context().Done():
+			err = r.Context().Err()
+		}
+
+		if err != nil {
+			slog.InfoContext(ctx, "SSE client dropped", "error", err, "reconnect_grace", s.grace)
+			client.detach(conn, s.grace, func() { s.remove(client) })
 			return
 		}
 	}
 }
 
+// connect returns the client for an SSE request: the existing client if the
+// request names its session, or a new client with a new session. It writes
+// an error response if it can't.
+func (s *SSEServer) connect(w http.ResponseWriter, r *http.Request) (*SSEClient, *session, string, bool) {
+	if r.URL.Query().Has("sessionId") {
+		token := r.URL.Query().Get("sessionId")
+		sess, ok := s.server.requireSession(w, r, token)
+		if !ok {
+			return nil, nil, "", false
+		}
+		s.mu.RLock()
+		client, ok := s.clients[sess.id]
+		s.mu.RUnlock()
+		if !ok {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return nil, nil, "", false
+		}
+		return client, sess, token, true
+	}
+
+	sess, token, ok := s.server.startSession(w, r)
+	if !ok {
+		return nil, nil, "", false
+	}
+	client := &SSEClient{
+		id:       sess.id,
+		messages: make(chan []byte, 100),
+		done:     make(chan struct{}),
+	}
+	client.unsubscribe = s.server.notifier.subscribe(client.id, client.send)
+
+	s.mu.Lock()
+	s.clients[client.id] = client
+	s.mu.Unlock()
+
+	slog.InfoContext(audit.WithClientID(r.Context(), client.id), "SSE client connected")
+	return client, sess, token, true
+}
+
+// remove disconnects a client for good and ends its session.
+func (s *SSEServer) remove(client *SSEClient) {
+	s.mu.Lock()
+	if s.clients[client.id] != client {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.clients, client.id)
+	s.mu.Unlock()
+
+	client.unsubscribe()
+	close(client.done)
+	s.server.sessions.end(client.id)
+	slog.InfoContext(audit.WithClientID(context.Background(), client.id), "SSE client disconnected")
+}
+
+// writeMessage writes a message event with its ID.
+func (s *SSEServer) writeMessage(stream *sseStream, event sseEvent) error {
+	return s.write(stream, fmt.Sprintf("id: %d\nevent: message\ndata: %s\n\n", event.id, event.data))
+}
+
+// write sends raw event stream text to the client and flushes it, failing
+// if the client doesn't accept it within the write timeout.
+func (s *SSEServer) write(stream *sseStream, text string) error {
+	rc := http.NewResponseController(stream.w)
+	if err := rc.SetWriteDeadline(time.Now().Add(s.writeTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if stream.z == nil {
+		if _, err := io.WriteString(stream.w, text); err != nil {
+			return err
+		}
+	} else {
+		if _, err := io.WriteString(stream.z, text); err != nil {
+			return err
+		}
+		if err := stream.z.Flush(); err != nil {
+			return err
+		}
+	}
+	if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
 // handleMessage handles incoming JSON-RPC messages.
 func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -144,12 +424,12 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get session ID
-	sessionID := r.URL.Query().Get("sessionId")
-	if sessionID == "" {
-		http.Error(w, "Missing sessionId", http.StatusBadRequest)
+	// Verify session
+	sess, ok := s.server.requireSession(w, r, r.URL.Query().Get("sessionId"))
+	if !ok {
 		return
 	}
+	sessionID := sess.id
 
 	// Find client
 	s.mu.RLock()
@@ -170,7 +450,8 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	// Process message
-	response := s.server.handleMessage(r.Context(), body)
+	ctx := audit.WithClientID(r.Context(), sessionID)
+	response := s.server.processMessage(ctx, body)
 
 	// Send response via SSE
 	if response != nil {
@@ -184,7 +465,7 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 		case client.messages <- responseBytes:
 			// Message sent
 		default:
-			log.Printf("Client message buffer full: %s", sessionID)
+			slog.WarnContext(ctx, "Client message buffer full")
 		}
 	}
 
@@ -193,21 +474,15 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write([]byte("Accepted"))
 }
 
-// handleHealth returns server health status.
-func (s *SSEServer) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+// handleSession rotates or ends the session named by the sessionId query
+// parameter.
+func (s *SSEServer) handleSession(w http.ResponseWriter, r *http.Request) {
+	s.server.handleSession(w, r, r.URL.Query().Get("sessionId"))
+}
 
+// clientCount returns the number of connected clients.
+func (s *SSEServer) clientCount() int {
 	s.mu.RLock()
-	clientCount := len(s.clients)
-	s.mu.RUnlock()
-
-	response := map[string]interface{}{
-		"status":  "healthy",
-		"clients": clientCount,
-		"server":  ServerName,
-		"version": ServerVersion,
-	}
-
-	_ = json.NewEncoder(w).Encode(response)
+	defer s.mu.RUnlock()
+	return len(s.clients)
 }
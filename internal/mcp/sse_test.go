@@ -0,0 +1,274 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestSSEClientReplay(t *testing.T) {
+	client := &SSEClient{}
+	for i := 0; i < 5; i++ {
+		client.record([]byte("msg"), 3)
+	}
+
+	tests := []struct {
+		lastID     uint64
+		wantIDs    []uint64
+		wantMissed bool
+	}{
+		{0, []uint64{3, 4, 5}, true},
+		{1, []uint64{3, 4, 5}, true},
+		{2, []uint64{3, 4, 5}, false},
+		{4, []uint64{5}, false},
+		{5, nil, false},
+	}
+
+	for _, tt := range tests {
+		events, missed := client.since(tt.lastID)
+		var ids []uint64
+		for _, event := range events {
+			ids = append(ids, event.id)
+		}
+		if len(ids) != len(tt.wantIDs) || (len(ids) > 0 && ids[0] != tt.wantIDs[0]) {
+			t.Errorf("since(%d): expected events %v, got %v", tt.lastID, tt.wantIDs, ids)
+		}
+		if missed != tt.wantMissed {
+			t.Errorf("since(%d): expected missed=%v, got %v", tt.lastID, tt.wantMissed, missed)
+		}
+	}
+}
+
+// readSSEEvent reads one event or comment from an event stream.
+func readSSEEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Error reading event stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return strings.Join(lines, "\n")
+		}
+		lines = append(lines, line)
+	}
+}
+
+// openSSE connects to the event stream at path and returns a reader over it.
+func openSSE(t *testing.T, ctx context.Context, baseURL, path, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Error connecting: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func TestSSEResume(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := &Server{config: cfg, sessions: newSessionManager(cfg.Sessions), notifier: newNotifier()}
+	sse := NewSSEServer(s, 0)
+	ts := httptest.NewServer(sse.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resp, events := openSSE(t, ctx, ts.URL, "/sse", "")
+	endpoint := strings.TrimPrefix(readSSEEvent(t, events), "event: endpoint\ndata: ")
+
+	post := func(id string) {
+		body := `{"jsonrpc":"2.0","id":` + id + `,"method":"prompts/list"}`
+		r, err := http.Post(ts.URL+endpoint, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Body.Close()
+	}
+
+	post("1")
+	if event := readSSEEvent(t, events); !strings.HasPrefix(event, "id: 1\nevent: message\n") {
+		t.Fatalf("Expected event 1, got %q", event)
+	}
+
+	// Drop the connection and send a request while the client is away
+	client := onlyClient(t, sse)
+	cancel()
+	resp.Body.Close()
+	for {
+		client.mu.Lock()
+		detached := client.conn == nil
+		client.mu.Unlock()
+		if detached {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	post("2")
+
+	token, _ := url.QueryUnescape(strings.TrimPrefix(endpoint, "/message?sessionId="))
+	resp, events = openSSE(t, context.Background(), ts.URL, "/sse?sessionId="+url.QueryEscape(token), "1")
+	defer resp.Body.Close()
+
+	if event := readSSEEvent(t, events); event != "event: endpoint\ndata: "+endpoint {
+		t.Errorf("Expected the endpoint event on resume, got %q", event)
+	}
+	if event := readSSEEvent(t, events); !strings.HasPrefix(event, "id: 2\nevent: message\n") || !strings.Contains(event, `"id":2`) {
+		t.Errorf("Expected event 2 after resuming, got %q", event)
+	}
+	if sse.clientCount() != 1 {
+		t.Errorf("Expected the session to survive the reconnect, got %d clients", sse.clientCount())
+	}
+}
+
+// onlyClient returns the SSE server's only client.
+func onlyClient(t *testing.T, sse *SSEServer) *SSEClient {
+	t.Helper()
+	sse.mu.RLock()
+	defer sse.mu.RUnlock()
+	for _, client := range sse.clients {
+		return client
+	}
+	t.Fatal("Expected a connected client")
+	return nil
+}
+
+func TestSSEKeepaliveAndExpiry(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := &Server{config: cfg, sessions: newSessionManager(cfg.Sessions), notifier: newNotifier()}
+	sse := NewSSEServer(s, 0)
+	sse.keepalive = 10 * time.Millisecond
+	sse.grace = 20 * time.Millisecond
+	ts := httptest.NewServer(sse.Handler())
+	defer ts.Close()
+
+	resp, events := openSSE(t, context.Background(), ts.URL, "/sse", "")
+	readSSEEvent(t, events)
+	if event := readSSEEvent(t, events); event != ": ping" {
+		t.Errorf("Expected a keepalive comment, got %q", event)
+	}
+	resp.Body.Close()
+
+	// The session ends when the client doesn't come back within the grace period
+	deadline := time.Now().Add(time.Second)
+	for sse.clientCount() != 0 || s.sessions.count() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the session to end after the reconnect grace period")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if s.notifier.count() != 0 {
+		t.Error("Expected the client to stop receiving notifications")
+	}
+}
+
+func TestSSECompression(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		compression    bool
+		wantEncoding   string
+	}{
+		{"gzip", "gzip, deflate", true, "gzip"},
+		{"deflate", "deflate", true, "deflate"},
+		{"not accepted", "identity", true, ""},
+		{"disabled", "gzip", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.SSE.Compression = tt.compression
+			s := &Server{config: cfg, sessions: newSessionManager(cfg.Sessions), notifier: newNotifier()}
+			sse := NewSSEServer(s, 0)
+			sse.keepalive = 10 * time.Millisecond
+			ts := httptest.NewServer(sse.Handler())
+			defer ts.Close()
+
+			// Setting Accept-Encoding stops the client decompressing for us
+			req, _ := http.NewRequest(http.MethodGet, ts.URL+"/sse", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Error connecting: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if got := resp.Header.Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Expected Content-Encoding %q, got %q", tt.wantEncoding, got)
+			}
+			var body io.Reader = resp.Body
+			switch tt.wantEncoding {
+			case "gzip":
+				if body, err = gzip.NewReader(resp.Body); err != nil {
+					t.Fatalf("Error reading gzip stream: %v", err)
+				}
+			case "deflate":
+				if body, err = zlib.NewReader(resp.Body); err != nil {
+					t.Fatalf("Error reading deflate stream: %v", err)
+				}
+			}
+
+			// Each event arrives without waiting for the stream to end
+			events := bufio.NewReader(body)
+			if event := readSSEEvent(t, events); !strings.HasPrefix(event, "event: endpoint\n") {
+				t.Errorf("Expected the endpoint event, got %q", event)
+			}
+			if event := readSSEEvent(t, events); event != ": ping" {
+				t.Errorf("Expected a keepalive comment, got %q", event)
+			}
+		})
+	}
+}
+
+func TestSSEResumeErrors(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := &Server{config: cfg, sessions: newSessionManager(cfg.Sessions)}
+	sse := NewSSEServer(s, 0)
+	_, token, _ := s.sessions.create("")
+
+	tests := []struct {
+		name        string
+		path        string
+		lastEventID string
+		wantCode    int
+	}{
+		{"bad last event id", "/sse?sessionId=" + url.QueryEscape(token), "abc", http.StatusBadRequest},
+		{"invalid token", "/sse?sessionId=forged", "", http.StatusUnauthorized},
+		{"no client", "/sse?sessionId=" + url.QueryEscape(token), "3", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			rec := httptest.NewRecorder()
+			sse.handleSSE(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, rec.Code)
+			}
+		})
+	}
+}
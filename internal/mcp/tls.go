@@ -0,0 +1,167 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// certCheckInterval is the minimum time between checks of the certificate
+// files for changes.
+const certCheckInterval = 5 * time.Second
+
+// buildServerTLSConfig creates a TLS configuration for the network listeners.
+// The certificate is reloaded when its files change, so renewed certificates
+// take effect without a restart.
+func buildServerTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("server TLS requires cert_file and key_file")
+	}
+
+	reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	// Verify client certificates against the configured CA
+	if cfg.CAFile != "" {
+		caCert, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", cfg.CAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		if cfg.ClientAuth == config.ClientAuthOptional {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// certIdentity returns the identity in a verified client certificate: its
+// common name, else its first DNS, email, or URI subject alternative name.
+// It returns "" if the client presented no verified certificate.
+func certIdentity(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	leaf := state.VerifiedChains[0][0]
+	switch {
+	case leaf.Subject.CommonName != "":
+		return leaf.Subject.CommonName
+	case len(leaf.DNSNames) > 0:
+		return leaf.DNSNames[0]
+	case len(leaf.EmailAddresses) > 0:
+		return leaf.EmailAddresses[0]
+	case len(leaf.URIs) > 0:
+		return leaf.URIs[0].String()
+	}
+	return ""
+}
+
+// serverTLSListener wraps listener with TLS if server_tls is enabled.
+func serverTLSListener(cfg config.TLSConfig, listener net.Listener) (net.Listener, error) {
+	if !cfg.Enabled {
+		return listener, nil
+	}
+
+	tlsConfig, err := buildServerTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return tls.NewListener(listener, tlsConfig), nil
+}
+
+// certReloader serves a certificate loaded from files, reloading it when the
+// files' modification times change.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return nil, fmt.Errorf("loading server certificate: %w", err)
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate returns the current certificate, reloading it first if the
+// files have changed. If a reload fails the previous certificate is kept.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastCheck) >= certCheckInterval {
+		r.lastCheck = time.Now()
+		if modTime, err := r.latestModTime(); err != nil {
+			slog.Warn("Checking server certificate", "error", err)
+		} else if !modTime.Equal(r.modTime) {
+			if err := r.load(modTime); err != nil {
+				slog.Error("Reloading server certificate", "error", err)
+			} else {
+				slog.Info("Reloaded server certificate", "file", r.certFile)
+			}
+		}
+	}
+	return r.cert, nil
+}
+
+// load reads the certificate and key. The caller must hold r.mu or have
+// exclusive access to r.
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading server certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	r.lastCheck = time.Now()
+	return nil
+}
+
+// latestModTime returns the later modification time of the two files.
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,270 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// writeTestCert writes a self-signed certificate for commonName and its key
+// to dir, returning the file paths.
+func writeTestCert(t *testing.T, dir, commonName string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func leafCommonName(t *testing.T, cert *tls.Certificate) string {
+	t.Helper()
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir, "first")
+
+	r, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newCertReloader() error = %v", err)
+	}
+	cert, _ := r.GetCertificate(nil)
+	if name := leafCommonName(t, cert); name != "first" {
+		t.Errorf("Expected 'first', got '%s'", name)
+	}
+
+	// Replace the files and make the change visible to the next check
+	writeTestCert(t, dir, "second")
+	future := time.Now().Add(time.Minute)
+	_ = os.Chtimes(certFile, future, future)
+	r.lastCheck = time.Time{}
+
+	cert, _ = r.GetCertificate(nil)
+	if name := leafCommonName(t, cert); name != "second" {
+		t.Errorf("Expected reloaded certificate 'second', got '%s'", name)
+	}
+
+	// A broken replacement keeps the previous certificate
+	if err := os.WriteFile(keyFile, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	future = future.Add(time.Minute)
+	_ = os.Chtimes(keyFile, future, future)
+	r.lastCheck = time.Time{}
+
+	cert, _ = r.GetCertificate(nil)
+	if name := leafCommonName(t, cert); name != "second" {
+		t.Errorf("Expected previous certificate to be kept, got '%s'", name)
+	}
+}
+
+func TestServerTLSListener(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir(), "localhost")
+
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	listener, err := serverTLSListener(config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile}, raw)
+	if err != nil {
+		t.Fatalf("serverTLSListener() error = %v", err)
+	}
+
+	s := NewSSEServer(&Server{config: config.DefaultConfig()}, 0)
+	srv := &http.Server{Handler: s.Handler()}
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(func() { srv.Close() })
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // self-signed test certificate
+	}}
+	resp, err := client.Get("https://" + raw.Addr().String() + "/live")
+	if err != nil {
+		t.Fatalf("GET /live over TLS error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerTLSListenerDisabled(t *testing.T) {
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer raw.Close()
+
+	listener, err := serverTLSListener(config.TLSConfig{}, raw)
+	if err != nil {
+		t.Fatalf("serverTLSListener() error = %v", err)
+	}
+	if listener != raw {
+		t.Error("Expected the plain listener when TLS is disabled")
+	}
+}
+
+// newTestClientCert creates a CA and a client certificate for commonName
+// signed by it. It writes the CA certificate to dir and returns its path
+// along with the client certificate.
+func newTestClientCert(t *testing.T, dir, commonName string) (string, tls.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	caFile := filepath.Join(dir, "client-ca.crt")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caTemplate, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	return caFile, tls.Certificate{Certificate: [][]byte{clientDER}, PrivateKey: clientKey}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir, "localhost")
+	caFile, clientCert := newTestClientCert(t, dir, "agent-1")
+
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	listener, err := serverTLSListener(config.TLSConfig{
+		Enabled:  true,
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   caFile,
+	}, raw)
+	if err != nil {
+		t.Fatalf("serverTLSListener() error = %v", err)
+	}
+
+	s := &Server{}
+	var gotUser string
+	srv := &http.Server{Handler: s.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser = audit.UserFromContext(r.Context())
+	}))}
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(func() { srv.Close() })
+
+	url := "https://" + raw.Addr().String() + "/sse"
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true, Certificates: certs}, // self-signed test certificate
+		}}
+	}
+
+	resp, err := newClient(clientCert).Get(url)
+	if err != nil {
+		t.Fatalf("GET with client certificate error = %v", err)
+	}
+	resp.Body.Close()
+	if gotUser != "agent-1" {
+		t.Errorf("Expected audit user 'agent-1', got '%s'", gotUser)
+	}
+
+	if resp, err := newClient().Get(url); err == nil {
+		resp.Body.Close()
+		t.Error("Expected the handshake to fail without a client certificate")
+	}
+}
+
+func TestCertIdentity(t *testing.T) {
+	if id := certIdentity(nil); id != "" {
+		t.Errorf("Expected no identity without TLS, got '%s'", id)
+	}
+
+	leaf := &x509.Certificate{DNSNames: []string{"agent.example.com"}}
+	state := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leaf}}}
+	if id := certIdentity(state); id != "agent.example.com" {
+		t.Errorf("Expected SAN identity 'agent.example.com', got '%s'", id)
+	}
+
+	leaf.Subject.CommonName = "agent-1"
+	if id := certIdentity(state); id != "agent-1" {
+		t.Errorf("Expected CN identity 'agent-1', got '%s'", id)
+	}
+
+	unverified := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}
+	if id := certIdentity(unverified); id != "" {
+		t.Errorf("Expected unverified certificates to be ignored, got '%s'", id)
+	}
+}
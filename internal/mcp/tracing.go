@@ -0,0 +1,96 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tracing"
+)
+
+// traceparentHeader carries W3C trace context on HTTP requests and gRPC
+// metadata.
+const traceparentHeader = "traceparent"
+
+// traceHTTP records a server span for each request to an HTTP message
+// endpoint, continuing the caller's trace if the request carries a
+// traceparent header.
+func (s *Server) traceHTTP(next http.Handler) http.Handler {
+	if s.tracer == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := tracing.Extract(r.Context(), r.Header.Get(traceparentHeader))
+		ctx, span := s.tracer.Start(ctx, r.Method+" "+r.URL.Path, tracing.KindServer,
+			tracing.String("http.request.method", r.Method),
+			tracing.String("url.path", r.URL.Path),
+			tracing.String("mcp.transport", s.config.Transport),
+		)
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		span.SetAttributes(tracing.Int("http.response.status_code", rec.status))
+	})
+}
+
+// statusRecorder remembers the status code written to a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// traceGRPC records a server span for a gRPC call, continuing the caller's
+// trace if the call's metadata carries a traceparent.
+func (s *Server) traceGRPC(ctx context.Context, method string) (context.Context, *tracing.Span) {
+	if s.tracer == nil {
+		return ctx, nil
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(traceparentHeader); len(values) > 0 {
+			ctx = tracing.Extract(ctx, values[0])
+		}
+	}
+	return s.tracer.Start(ctx, method, tracing.KindServer,
+		tracing.String("rpc.system", "grpc"),
+		tracing.String("rpc.method", method),
+	)
+}
+
+// startMessageSpan starts the span for a JSON-RPC message. It is a child of
+// the transport's span if there is one; otherwise it is the server span,
+// continuing any trace the client passed in params._meta.traceparent.
+func (s *Server) startMessageSpan(ctx context.Context, req *Request) (context.Context, *tracing.Span) {
+	if s.tracer == nil {
+		return ctx, nil
+	}
+
+	kind := tracing.KindInternal
+	if tracing.SpanFromContext(ctx) == nil {
+		kind = tracing.KindServer
+		var params struct {
+			Meta struct {
+				Traceparent string `json:"traceparent"`
+			} `json:"_meta"`
+		}
+		if len(req.Params) > 0 && json.Unmarshal(req.Params, &params) == nil {
+			ctx = tracing.Extract(ctx, params.Meta.Traceparent)
+		}
+	}
+
+	return s.tracer.Start(ctx, req.Method, kind,
+		tracing.String("rpc.system", "jsonrpc"),
+		tracing.String("mcp.method.name", req.Method),
+		tracing.String("mcp.transport", s.config.Transport),
+	)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/tracing"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func newTracingTestServer(t *testing.T) *Server {
+	t.Helper()
+	tracer, err := tracing.New(tracing.Config{Endpoint: "http://127.0.0.1:4318", SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("tracing.New() error = %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_ = tracer.Shutdown(ctx)
+	})
+	return &Server{config: config.DefaultConfig(), tracer: tracer}
+}
+
+func TestStartMessageSpan(t *testing.T) {
+	s := newTracingTestServer(t)
+	remote, _ := tracing.ParseTraceparent(testTraceparent)
+
+	req := &Request{
+		JSONRPC: "2.0",
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"get_record","_meta":{"traceparent":"` + testTraceparent + `"}}`),
+	}
+	ctx, span := s.startMessageSpan(context.Background(), req)
+	if span.SpanContext().TraceID() != remote.TraceID() {
+		t.Error("Expected message span to continue the trace from params._meta")
+	}
+
+	_, tool := tracing.Start(ctx, "tool get_record", tracing.KindInternal)
+	if tool.SpanContext().TraceID() != remote.TraceID() {
+		t.Error("Expected tool span to join the message's trace")
+	}
+
+	req.Params = json.RawMessage(`{"name":"get_record"}`)
+	if _, span := s.startMessageSpan(context.Background(), req); span.SpanContext().TraceID() == remote.TraceID() {
+		t.Error("Expected a new trace without a traceparent")
+	}
+}
+
+func TestTraceHTTP(t *testing.T) {
+	s := newTracingTestServer(t)
+	remote, _ := tracing.ParseTraceparent(testTraceparent)
+
+	var message *tracing.Span
+	handler := s.traceHTTP(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, message = s.startMessageSpan(r.Context(), &Request{Method: "tools/list"})
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/message", nil)
+	req.Header.Set(traceparentHeader, testTraceparent)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", rec.Code)
+	}
+	if message.SpanContext().TraceID() != remote.TraceID() {
+		t.Error("Expected message span to continue the trace from the traceparent header")
+	}
+
+	var untraced Server
+	if h := untraced.traceHTTP(http.NotFoundHandler()); h == nil {
+		t.Error("Expected handler without tracing")
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !unix
+
+package mcp
+
+// restrictUmask does nothing where there is no umask.
+func restrictUmask() (restore func()) {
+	return func() {}
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unix
+
+package mcp
+
+import "syscall"
+
+// restrictUmask makes files the process creates readable and writable only
+// by their owner, until the returned function restores the previous umask.
+func restrictUmask() (restore func()) {
+	old := syscall.Umask(0o177)
+	return func() { syscall.Umask(old) }
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"os"
+)
+
+// UnixServer serves the HTTP transport endpoints over a unix domain socket,
+// allowing local agent hosts to connect without opening a TCP port.
+type UnixServer struct {
+	server *Server
+	path   string
+	mode   fs.FileMode
+}
+
+// NewUnixServer creates a new unix socket server.
+func NewUnixServer(server *Server, path string, mode fs.FileMode) *UnixServer {
+	return &UnixServer{
+		server: server,
+		path:   path,
+		mode:   mode,
+	}
+}
+
+// Run listens on the unix socket and serves the SSE endpoints until ctx is done.
+func (u *UnixServer) Run(ctx context.Context) error {
+	listener, err := u.listen()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(u.path)
+
+	slog.Info("Unix socket server listening", "path", u.path, "mode", fmt.Sprintf("%04o", u.mode))
+
+	sseServer := NewSSEServer(u.server, 0)
+	return sseServer.Serve(ctx, listener)
+}
+
+// listen creates the socket, replacing a stale socket file left behind by a
+// previous process, and applies the configured file permissions.
+func (u *UnixServer) listen() (net.Listener, error) {
+	if info, err := os.Lstat(u.path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("socket path %s exists and is not a socket", u.path)
+		}
+		if err := os.Remove(u.path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("checking socket path: %w", err)
+	}
+
+	// Create the socket accessible only to its owner, so other users can't
+	// connect before the configured permissions are applied
+	restore := restrictUmask()
+	listener, err := net.Listen("unix", u.path)
+	restore()
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", u.path, err)
+	}
+
+	if err := os.Chmod(u.path, u.mode); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("setting socket permissions: %w", err)
+	}
+
+	return listener, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnixServerListen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.sock")
+	u := NewUnixServer(nil, path, 0660)
+
+	listener, err := u.listen()
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Mode().Perm() != 0660 {
+		t.Errorf("Expected mode 0660, got %o", info.Mode().Perm())
+	}
+
+	// Simulate a stale socket left behind by a crashed process
+	if ul, ok := listener.(interface{ SetUnlinkOnClose(bool) }); ok {
+		ul.SetUnlinkOnClose(false)
+	}
+	listener.Close()
+
+	listener, err = u.listen()
+	if err != nil {
+		t.Fatalf("listen() over stale socket error = %v", err)
+	}
+	listener.Close()
+}
+
+func TestUnixServerRejectsRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-socket")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	u := NewUnixServer(nil, path, 0600)
+	if _, err := u.listen(); err == nil {
+		t.Error("Expected error when socket path is a regular file")
+	}
+}
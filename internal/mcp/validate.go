@@ -0,0 +1,292 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+)
+
+// toolArgs holds the tool arguments checked by the validator. Fields whose
+// shape differs between tools are decoded per tool.
+type toolArgs struct {
+	Namespace    string          `json:"namespace"`
+	SetName      string          `json:"set_name"`
+	Key          string          `json:"key"`
+	Bins         json.RawMessage `json:"bins"`
+	DeleteBins   json.RawMessage `json:"delete_bins"`
+	Keys         json.RawMessage `json:"keys"`
+	Operations   json.RawMessage `json:"operations"`
+	IndexName    string          `json:"index_name"`
+	BinName      string          `json:"bin_name"`
+	OrderBy      string          `json:"order_by"`
+	SQL          string          `json:"sql"`
+	ModuleName   string          `json:"module_name"`
+	FunctionName string          `json:"function_name"`
+	Code         string          `json:"code"`
+	JobID        string          `json:"job_id"`
+	Status       string          `json:"status"`
+}
+
+// validateToolArgs checks the namespaces, sets, keys, bin names, and batch
+// sizes in a tool call before it reaches the cluster. It returns
+// audit.ValidationErrors listing every invalid field.
+func (s *Server) validateToolArgs(name string, args json.RawMessage) error {
+	if s.validator == nil {
+		return nil
+	}
+
+	var a toolArgs
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &a); err != nil {
+			return audit.ValidationErrors{{Field: "arguments", Message: err.Error()}}
+		}
+	}
+
+	v := s.validator
+	var errs audit.ValidationErrors
+
+	switch name {
+	case "describe_namespace", "list_sets", "list_indexes":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+
+	case "refresh_metadata":
+		if a.Namespace != "" {
+			errs.Add("", v.ValidateNamespace(a.Namespace))
+		}
+
+	case "describe_set", "scan_set", "truncate_set", "get_namespace_config", "set_namespace_config":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateSetName(a.SetName))
+		if name == "scan_set" {
+			validateBinList(v, &errs, "bins", a.Bins)
+			validateOrderBy(v, &errs, a.OrderBy)
+		}
+
+	case "get_record", "delete_record", "execute_udf":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateSetName(a.SetName))
+		errs.Add("", v.ValidateKey(a.Key))
+		switch name {
+		case "get_record":
+			validateBinList(v, &errs, "bins", a.Bins)
+		case "execute_udf":
+			// UDFs are executed by package name, without the .lua extension
+			if a.ModuleName == "" {
+				errs.Add("module_name", fmt.Errorf("cannot be empty"))
+			}
+			if a.FunctionName == "" {
+				errs.Add("function_name", fmt.Errorf("cannot be empty"))
+			}
+		}
+
+	case "put_record":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateSetName(a.SetName))
+		errs.Add("", v.ValidateKey(a.Key))
+		validateBinWrite(v, &errs, "", a.Bins, a.DeleteBins)
+
+	case "operate":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateSetName(a.SetName))
+		errs.Add("", v.ValidateKey(a.Key))
+
+		var ops []struct {
+			Type    string `json:"type"`
+			BinName string `json:"bin_name"`
+		}
+		if err := decodeField(a.Operations, &ops); err != nil {
+			errs.Add("operations", err)
+			break
+		}
+		if len(ops) == 0 {
+			errs.Add("operations", fmt.Errorf("cannot be empty"))
+		}
+		for i, op := range ops {
+			// touch and whole-record reads don't name a bin
+			if op.BinName == "" && (op.Type == "touch" || op.Type == "read") {
+				continue
+			}
+			errs.Add(fmt.Sprintf("operations[%d].bin_name", i), v.ValidateBinName(op.BinName))
+		}
+
+	case "batch_get":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+
+		var keys []struct {
+			Key  string          `json:"key"`
+			Set  string          `json:"set"`
+			Bins json.RawMessage `json:"bins"`
+		}
+		if err := decodeField(a.Keys, &keys); err != nil {
+			errs.Add("keys", err)
+			break
+		}
+		errs.Add("keys", v.ValidateBatchSize(len(keys)))
+		for i, k := range keys {
+			prefix := fmt.Sprintf("keys[%d].", i)
+			errs.Add(prefix+"key", v.ValidateKey(k.Key))
+			errs.Add(prefix+"set", v.ValidateSetName(k.Set))
+			validateBinList(v, &errs, prefix+"bins", k.Bins)
+		}
+
+	case "batch_exists":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateSetName(a.SetName))
+
+		var keys []string
+		if err := decodeField(a.Keys, &keys); err != nil {
+			errs.Add("keys", err)
+			break
+		}
+		errs.Add("keys", v.ValidateBatchSize(len(keys)))
+		for i, key := range keys {
+			errs.Add(fmt.Sprintf("keys[%d]", i), v.ValidateKey(key))
+		}
+
+	case "batch_write":
+		var ops []struct {
+			Namespace  string          `json:"namespace"`
+			Set        string          `json:"set"`
+			Key        string          `json:"key"`
+			Bins       json.RawMessage `json:"bins"`
+			DeleteBins json.RawMessage `json:"delete_bins"`
+			Operation  string          `json:"operation"`
+		}
+		if err := decodeField(a.Operations, &ops); err != nil {
+			errs.Add("operations", err)
+			break
+		}
+		errs.Add("operations", v.ValidateBatchSize(len(ops)))
+		for i, op := range ops {
+			prefix := fmt.Sprintf("operations[%d].", i)
+			errs.Add(prefix+"namespace", v.ValidateNamespace(op.Namespace))
+			errs.Add(prefix+"set", v.ValidateSetName(op.Set))
+			errs.Add(prefix+"key", v.ValidateKey(op.Key))
+			if op.Operation != "delete" {
+				validateBinWrite(v, &errs, prefix, op.Bins, op.DeleteBins)
+			}
+		}
+
+	case "query_records":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateSetName(a.SetName))
+		validateOrderBy(v, &errs, a.OrderBy)
+
+	case "aggregate_simple":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateSetName(a.SetName))
+		if a.BinName != "" {
+			errs.Add("", v.ValidateBinName(a.BinName))
+		}
+
+	case "sql_query":
+		if strings.TrimSpace(a.SQL) == "" {
+			errs.Add("sql", fmt.Errorf("cannot be empty"))
+		}
+
+	case "create_index":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateSetName(a.SetName))
+		errs.Add("", v.ValidateIndexName(a.IndexName))
+		errs.Add("", v.ValidateBinName(a.BinName))
+
+	case "drop_index":
+		errs.Add("", v.ValidateNamespace(a.Namespace))
+		errs.Add("", v.ValidateIndexName(a.IndexName))
+
+	case "register_udf":
+		errs.Add("", v.ValidateModuleName(a.ModuleName))
+		errs.Add("", v.ValidateUDFCode(a.Code))
+
+	case "remove_udf":
+		errs.Add("", v.ValidateModuleName(a.ModuleName))
+
+	case getJobTool, cancelJobTool:
+		if a.JobID == "" {
+			errs.Add("job_id", fmt.Errorf("cannot be empty"))
+		}
+
+	case listJobsTool:
+		switch a.Status {
+		case "", jobRunning, jobSucceeded, jobFailed, jobCancelled:
+		default:
+			errs.Add("status", fmt.Errorf("must be %s", jobStatusDesc))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateBinList checks an optional list of bin names.
+func validateBinList(v *audit.Validator, errs *audit.ValidationErrors, field string, raw json.RawMessage) {
+	var bins []string
+	if err := decodeField(raw, &bins); err != nil {
+		errs.Add(field, err)
+		return
+	}
+	for i, bin := range bins {
+		errs.Add(fmt.Sprintf("%s[%d]", field, i), v.ValidateBinName(bin))
+	}
+}
+
+// validateOrderBy checks the bin a scan or query is sorted by, if any.
+func validateOrderBy(v *audit.Validator, errs *audit.ValidationErrors, orderBy string) {
+	if orderBy != "" {
+		errs.Add("order_by", v.ValidateBinName(orderBy))
+	}
+}
+
+// validateBinWrite checks the bins a put writes and the delete_bins it
+// removes. A put must name at least one bin to write or delete.
+func validateBinWrite(v *audit.Validator, errs *audit.ValidationErrors, prefix string, bins, deleteBins json.RawMessage) {
+	var deletes []string
+	if err := decodeField(deleteBins, &deletes); err != nil {
+		errs.Add(prefix+"delete_bins", err)
+		return
+	}
+	validateBinList(v, errs, prefix+"delete_bins", deleteBins)
+	validateBinMap(v, errs, prefix+"bins", bins, len(deletes) == 0)
+}
+
+// validateBinMap checks the names in a map of bin values, which must not be
+// empty if required.
+func validateBinMap(v *audit.Validator, errs *audit.ValidationErrors, field string, raw json.RawMessage, required bool) {
+	var bins map[string]json.RawMessage
+	if err := decodeField(raw, &bins); err != nil {
+		errs.Add(field, err)
+		return
+	}
+	if len(bins) == 0 && required {
+		errs.Add(field, fmt.Errorf("cannot be empty"))
+		return
+	}
+	names := make([]string, 0, len(bins))
+	for bin := range bins {
+		names = append(names, bin)
+	}
+	sort.Strings(names)
+	for _, bin := range names {
+		errs.Add(field+"."+bin, v.ValidateBinName(bin))
+	}
+}
+
+// decodeField decodes an optional argument, leaving out unchanged if it is
+// absent or null.
+func decodeField(raw json.RawMessage, out interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("has the wrong type")
+	}
+	return nil
+}
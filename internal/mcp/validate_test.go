@@ -0,0 +1,155 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+)
+
+func TestValidateToolArgs(t *testing.T) {
+	s := &Server{validator: audit.NewValidator(audit.DefaultValidatorConfig())}
+
+	tests := []struct {
+		name       string
+		tool       string
+		args       string
+		wantFields []string
+	}{
+		{
+			name: "valid get",
+			tool: "get_record",
+			args: `{"namespace":"test","set_name":"users","key":"u1","bins":["name"]}`,
+		},
+		{
+			name:       "missing namespace and key",
+			tool:       "get_record",
+			args:       `{"set_name":"users"}`,
+			wantFields: []string{"namespace", "key"},
+		},
+		{
+			name:       "bad bin names in put",
+			tool:       "put_record",
+			args:       `{"namespace":"test","key":"u1","bins":{"ok":1,"much_too_long_bin_name":2,"bad name":3}}`,
+			wantFields: []string{"bins.bad name", "bins.much_too_long_bin_name"},
+		},
+		{
+			name:       "empty put",
+			tool:       "put_record",
+			args:       `{"namespace":"test","key":"u1"}`,
+			wantFields: []string{"bins"},
+		},
+		{
+			name: "put deleting bins",
+			tool: "put_record",
+			args: `{"namespace":"test","key":"u1","delete_bins":["old"]}`,
+		},
+		{
+			name:       "batch write delete bins",
+			tool:       "batch_write",
+			args:       `{"operations":[{"namespace":"test","key":"a","delete_bins":["bad name"]},{"namespace":"test","key":"b","delete_bins":[]}]}`,
+			wantFields: []string{"operations[0].delete_bins[0]", "operations[1].bins"},
+		},
+		{
+			name:       "batch write items",
+			tool:       "batch_write",
+			args:       `{"operations":[{"namespace":"test","key":"a","bins":{"x":1}},{"namespace":"te st","key":"","operation":"delete"}]}`,
+			wantFields: []string{"operations[1].namespace", "operations[1].key"},
+		},
+		{
+			name:       "empty batch",
+			tool:       "batch_get",
+			args:       `{"namespace":"test","keys":[]}`,
+			wantFields: []string{"keys"},
+		},
+		{
+			name:       "aggregate bin name",
+			tool:       "aggregate_simple",
+			args:       `{"namespace":"test","bin_name":"much_too_long_bin_name"}`,
+			wantFields: []string{"bin_name"},
+		},
+		{
+			name:       "empty sql",
+			tool:       "sql_query",
+			args:       `{"sql":"  "}`,
+			wantFields: []string{"sql"},
+		},
+		{
+			name:       "batch exists keys",
+			tool:       "batch_exists",
+			args:       `{"namespace":"test","keys":["a",""]}`,
+			wantFields: []string{"keys[1]"},
+		},
+		{
+			name: "operate touch without bin",
+			tool: "operate",
+			args: `{"namespace":"test","key":"u1","operations":[{"type":"touch"},{"type":"increment","bin_name":"count","value":1}]}`,
+		},
+		{
+			name:       "operate increment without bin",
+			tool:       "operate",
+			args:       `{"namespace":"test","key":"u1","operations":[{"type":"increment","value":1}]}`,
+			wantFields: []string{"operations[0].bin_name"},
+		},
+		{
+			name:       "wrong argument type",
+			tool:       "get_record",
+			args:       `{"namespace":"test","key":"u1","bins":"name"}`,
+			wantFields: []string{"bins"},
+		},
+		{
+			name: "tool without arguments",
+			tool: "cluster_info",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.validateToolArgs(tt.tool, json.RawMessage(tt.args))
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			errs, ok := err.(audit.ValidationErrors)
+			if !ok {
+				t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+			}
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("Expected fields %v, got %v", tt.wantFields, fields)
+			}
+		})
+	}
+}
+
+func TestToolsCallInvalidParams(t *testing.T) {
+	s := &Server{validator: audit.NewValidator(audit.DefaultValidatorConfig())}
+
+	_, rpcErr := s.handleToolsCall(context.Background(), json.RawMessage(`{"name":"get_record","arguments":{"namespace":"test"}}`))
+	if rpcErr == nil {
+		t.Fatal("Expected an error for a missing key")
+	}
+	if rpcErr.Code != InvalidParams {
+		t.Errorf("Expected code %d, got %d", InvalidParams, rpcErr.Code)
+	}
+
+	data, _ := json.Marshal(rpcErr.Data)
+	if !strings.Contains(string(data), `"errors":[{"field":"key","message":"cannot be empty"}]`) {
+		t.Errorf("Expected field-level details, got %s", data)
+	}
+	if !strings.Contains(string(data), `"error":"invalid_request"`) {
+		t.Errorf("Expected the invalid_request error code, got %s", data)
+	}
+}
@@ -8,12 +8,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
 )
 
 // WebSocketServer handles WebSocket transport for MCP.
@@ -32,6 +33,17 @@ type WSClient struct {
 	messages chan []byte
 	done     chan struct{}
 	lastPing time.Time
+
+	unsubscribe func() // stops notifications
+}
+
+// send queues a message for the client to receive, dropping it if the
+// client's buffer is full.
+func (c *WSClient) send(msg []byte) {
+	select {
+	case c.messages <- msg:
+	default:
+	}
 }
 
 // NewWebSocketServer creates a new WebSocket server.
@@ -45,31 +57,28 @@ func NewWebSocketServer(server *Server, port int) *WebSocketServer {
 
 // Run starts the WebSocket HTTP server.
 func (s *WebSocketServer) Run(ctx context.Context) error {
-	mux := http.NewServeMux()
-
-	// WebSocket endpoint (simulated with HTTP for simplicity)
-	mux.HandleFunc("/ws", s.handleWebSocket)
-
-	// HTTP fallback for message handling
-	mux.HandleFunc("/ws/send", s.handleSend)
-	mux.HandleFunc("/ws/receive", s.handleReceive)
+	addr := fmt.Sprintf(":%d", s.port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
 
-	// Health check
-	mux.HandleFunc("/health", s.handleHealth)
+	tlsCfg := s.server.config.ServerTLS
+	if listener, err = serverTLSListener(tlsCfg, listener); err != nil {
+		return fmt.Errorf("configuring WebSocket TLS: %w", err)
+	}
 
-	addr := fmt.Sprintf(":%d", s.port)
 	httpServer := &http.Server{
-		Addr:         addr,
-		Handler:      mux,
+		Handler:      s.Handler(),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("WebSocket server listening on %s", addr)
-		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
-			log.Printf("HTTP server error: %v", err)
+		slog.Info("WebSocket server listening", "addr", addr, "tls", tlsCfg.Enabled)
+		if err := httpServer.Serve(listener); err != http.ErrServerClosed {
+			slog.Error("HTTP server error", "error", err)
 		}
 	}()
 
@@ -86,10 +95,38 @@ func (s *WebSocketServer) Run(ctx context.Context) error {
 	return httpServer.Shutdown(shutdownCtx)
 }
 
+// Handler returns the HTTP handler serving the WebSocket endpoints.
+func (s *WebSocketServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	auth := s.server.authenticate
+
+	// WebSocket endpoint (simulated with HTTP for simplicity)
+	mux.Handle("/ws", auth(http.HandlerFunc(s.handleWebSocket)))
+
+	// HTTP fallback for message handling
+	mux.Handle("/ws/send", s.server.traceHTTP(auth(http.HandlerFunc(s.handleSend))))
+	mux.Handle("/ws/receive", auth(http.HandlerFunc(s.handleReceive)))
+
+	// Session rotation and logout
+	mux.Handle("/ws/session", auth(http.HandlerFunc(s.handleSession)))
+
+	// Health, readiness, and liveness checks
+	s.server.handleHealthEndpoints(mux, s.clientCount)
+
+	// Prometheus metrics, unless served on their own listener
+	s.server.handleMetrics(mux)
+
+	return s.server.restrictSource(mux)
+}
+
 // handleWebSocket handles WebSocket connection establishment.
 func (s *WebSocketServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	// Generate client ID
-	clientID := uuid.New().String()
+	// Start a session; its token is the client ID
+	sess, token, ok := s.server.startSession(w, r)
+	if !ok {
+		return
+	}
+	clientID := sess.id
 
 	// Create client
 	client := &WSClient{
@@ -102,16 +139,43 @@ func (s *WebSocketServer) handleWebSocket(w http.ResponseWriter, r *http.Request
 	s.mu.Lock()
 	s.clients[clientID] = client
 	s.mu.Unlock()
+	client.unsubscribe = s.server.notifier.subscribe(clientID, client.send)
+
+	// Disconnect when the session expires or is logged out
+	go func() {
+		<-sess.done
+		s.Disconnect(clientID)
+	}()
 
 	// Return client ID for subsequent requests
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]string{
-		"client_id": clientID,
-		"status":    "connected",
-		"message":   "Use /ws/send to send messages and /ws/receive to poll for responses",
+		"client_id":  token,
+		"expires_at": sess.expires.UTC().Format(time.RFC3339),
+		"status":     "connected",
+		"message":    "Use /ws/send to send messages and /ws/receive to poll for responses",
 	})
 }
 
+// client returns the client for the session token in the X-Client-ID
+// header, writing an error response if there is none.
+func (s *WebSocketServer) client(w http.ResponseWriter, r *http.Request) (*WSClient, bool) {
+	sess, ok := s.server.requireSession(w, r, r.Header.Get("X-Client-ID"))
+	if !ok {
+		return nil, false
+	}
+
+	s.mu.RLock()
+	client, ok := s.clients[sess.id]
+	s.mu.RUnlock()
+
+	if !ok {
+		http.Error(w, "Client not found", http.StatusNotFound)
+		return nil, false
+	}
+	return client, true
+}
+
 // handleSend handles incoming messages from clients.
 func (s *WebSocketServer) handleSend(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -119,18 +183,8 @@ func (s *WebSocketServer) handleSend(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clientID := r.Header.Get("X-Client-ID")
-	if clientID == "" {
-		http.Error(w, "X-Client-ID header required", http.StatusBadRequest)
-		return
-	}
-
-	s.mu.RLock()
-	client, ok := s.clients[clientID]
-	s.mu.RUnlock()
-
+	client, ok := s.client(w, r)
 	if !ok {
-		http.Error(w, "Client not found", http.StatusNotFound)
 		return
 	}
 
@@ -145,8 +199,8 @@ func (s *WebSocketServer) handleSend(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Process request
-	ctx := r.Context()
-	response := s.server.handleMessage(ctx, requestData)
+	ctx := audit.WithClientID(r.Context(), client.id)
+	response := s.server.processMessage(ctx, requestData)
 
 	// Send response
 	responseData, err := json.Marshal(response)
@@ -174,18 +228,8 @@ func (s *WebSocketServer) handleSend(w http.ResponseWriter, r *http.Request) {
 
 // handleReceive handles long-polling for responses.
 func (s *WebSocketServer) handleReceive(w http.ResponseWriter, r *http.Request) {
-	clientID := r.Header.Get("X-Client-ID")
-	if clientID == "" {
-		http.Error(w, "X-Client-ID header required", http.StatusBadRequest)
-		return
-	}
-
-	s.mu.RLock()
-	client, ok := s.clients[clientID]
-	s.mu.RUnlock()
-
+	client, ok := s.client(w, r)
 	if !ok {
-		http.Error(w, "Client not found", http.StatusNotFound)
 		return
 	}
 
@@ -203,25 +247,23 @@ func (s *WebSocketServer) handleReceive(w http.ResponseWriter, r *http.Request)
 		// No message, return empty response
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(map[string]string{"status": "no_messages"})
+	case <-client.done:
+		http.Error(w, "Session ended", http.StatusGone)
 	case <-r.Context().Done():
 		return
 	}
 }
 
-// handleHealth returns server health status.
-func (s *WebSocketServer) handleHealth(w http.ResponseWriter, r *http.Request) {
-	s.mu.RLock()
-	clientCount := len(s.clients)
-	s.mu.RUnlock()
+// handleSession rotates or ends the session named by the X-Client-ID header.
+func (s *WebSocketServer) handleSession(w http.ResponseWriter, r *http.Request) {
+	s.server.handleSession(w, r, r.Header.Get("X-Client-ID"))
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"status":    "healthy",
-		"transport": "websocket",
-		"clients":   clientCount,
-		"server":    ServerName,
-		"version":   ServerVersion,
-	})
+// clientCount returns the number of connected clients.
+func (s *WebSocketServer) clientCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.clients)
 }
 
 // cleanupStaleClients removes clients that haven't pinged recently.
@@ -233,17 +275,25 @@ func (s *WebSocketServer) cleanupStaleClients(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			s.mu.Lock()
-			now := time.Now()
-			for id, client := range s.clients {
-				if now.Sub(client.lastPing) > 5*time.Minute {
-					close(client.done)
-					delete(s.clients, id)
-					log.Printf("Cleaned up stale client: %s", id)
-				}
-			}
-			s.mu.Unlock()
+		case now := <-ticker.C:
+			s.removeStaleClients(now)
+		}
+	}
+}
+
+// removeStaleClients disconnects clients whose last ping was more than five
+// minutes before now.
+func (s *WebSocketServer) removeStaleClients(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, client := range s.clients {
+		if now.Sub(client.lastPing) > 5*time.Minute {
+			client.unsubscribe()
+			close(client.done)
+			delete(s.clients, id)
+			s.server.sessions.end(id)
+			slog.Info("Cleaned up stale client", "client_id", id)
 		}
 	}
 }
@@ -254,7 +304,9 @@ func (s *WebSocketServer) Disconnect(clientID string) {
 	defer s.mu.Unlock()
 
 	if client, ok := s.clients[clientID]; ok {
+		client.unsubscribe()
 		close(client.done)
 		delete(s.clients, clientID)
+		s.server.sessions.end(clientID)
 	}
 }
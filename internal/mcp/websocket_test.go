@@ -0,0 +1,36 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestRemoveStaleClients(t *testing.T) {
+	cfg := config.DefaultConfig()
+	s := &Server{config: cfg, notifier: newNotifier(), sessions: newSessionManager(cfg.Sessions)}
+	ws := NewWebSocketServer(s, 0)
+
+	now := time.Now()
+	for id, lastPing := range map[string]time.Time{"stale": now.Add(-10 * time.Minute), "live": now} {
+		client := &WSClient{id: id, messages: make(chan []byte, 1), done: make(chan struct{}), lastPing: lastPing}
+		client.unsubscribe = s.notifier.subscribe(id, client.send)
+		ws.clients[id] = client
+	}
+
+	ws.removeStaleClients(now)
+
+	if _, ok := ws.clients["stale"]; ok {
+		t.Error("Expected the stale client to be removed")
+	}
+	if _, ok := ws.clients["live"]; !ok {
+		t.Error("Expected the live client to be kept")
+	}
+	if got := s.notifier.count(); got != 1 {
+		t.Errorf("Expected 1 notification subscriber, got %d", got)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics collects server metrics with the Prometheus client
+// library and exposes them in the Prometheus exposition format.
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+)
+
+// Tool call outcomes.
+const (
+	OutcomeSuccess       = "success"
+	OutcomeError         = "error"
+	OutcomeInvalid       = "invalid"
+	OutcomeRateLimited   = "rate_limited"
+	OutcomeQuotaExceeded = "quota_exceeded"
+)
+
+// defaultBuckets are the latency histogram bounds in seconds.
+var defaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics holds the server's metrics in a registry of its own, so separate
+// servers, such as those in tests, don't share counts. A nil *Metrics
+// discards observations, so callers needn't check whether metrics are
+// enabled.
+type Metrics struct {
+	registry      *prometheus.Registry
+	toolCalls     *prometheus.CounterVec
+	toolDuration  *prometheus.HistogramVec
+	aerospikeOps  *prometheus.HistogramVec
+	aerospikeErrs *prometheus.CounterVec
+	rateLimited   *prometheus.CounterVec
+
+	clientsDesc *prometheus.Desc
+	mu          sync.Mutex
+	clients     map[string]func() int // connected clients by transport
+}
+
+// New creates an empty set of metrics.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aerospike_mcp_tool_calls_total",
+			Help: "Tool calls by tool and outcome.",
+		}, []string{"tool", "outcome"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "aerospike_mcp_tool_call_duration_seconds",
+			Help:    "Tool call latency by tool.",
+			Buckets: defaultBuckets,
+		}, []string{"tool"}),
+		aerospikeOps: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "aerospike_mcp_aerospike_operation_duration_seconds",
+			Help:    "Aerospike cluster operation latency by operation.",
+			Buckets: defaultBuckets,
+		}, []string{"operation"}),
+		aerospikeErrs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aerospike_mcp_aerospike_operation_errors_total",
+			Help: "Failed Aerospike cluster operations by operation.",
+		}, []string{"operation"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "aerospike_mcp_rate_limit_rejections_total",
+			Help: "Tool calls rejected by the rate limiter by category.",
+		}, []string{"category"}),
+		clientsDesc: prometheus.NewDesc("aerospike_mcp_connected_clients",
+			"Clients connected by transport.", []string{"transport"}, nil),
+		clients: make(map[string]func() int),
+	}
+	m.registry.MustRegister(m.toolCalls, m.toolDuration, m.aerospikeOps, m.aerospikeErrs, m.rateLimited, clientsCollector{m})
+	return m
+}
+
+// ToolCall records a tool call and how long it took.
+func (m *Metrics) ToolCall(tool, outcome string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.toolCalls.WithLabelValues(tool, outcome).Inc()
+	m.toolDuration.WithLabelValues(tool).Observe(duration.Seconds())
+}
+
+// AerospikeOperation records a cluster operation and how long it took.
+func (m *Metrics) AerospikeOperation(operation string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.aerospikeOps.WithLabelValues(operation).Observe(duration.Seconds())
+	if err != nil {
+		m.aerospikeErrs.WithLabelValues(operation).Inc()
+	}
+}
+
+// RateLimited records a call rejected by the rate limiter.
+func (m *Metrics) RateLimited(category string) {
+	if m == nil {
+		return
+	}
+	m.rateLimited.WithLabelValues(category).Inc()
+}
+
+// ConnectedClients reports the number of clients connected over a
+// transport, as returned by count at collection time.
+func (m *Metrics) ConnectedClients(transport string, count func() int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clients[transport] = count
+}
+
+// Handler returns an HTTP handler serving the metrics in the format the
+// scraper asks for.
+func (m *Metrics) Handler() http.Handler {
+	if m == nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Write writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) Write(w io.Writer) error {
+	if m == nil {
+		return nil
+	}
+	families, err := m.registry.Gather()
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(bw, family); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// clientsCollector reports the connected clients gauge, calling each
+// transport's count function at collection time.
+type clientsCollector struct {
+	m *Metrics
+}
+
+func (c clientsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.m.clientsDesc
+}
+
+func (c clientsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.m.mu.Lock()
+	counts := make(map[string]func() int, len(c.m.clients))
+	for transport, count := range c.m.clients {
+		counts[transport] = count
+	}
+	c.m.mu.Unlock()
+
+	for transport, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.m.clientsDesc, prometheus.GaugeValue, float64(count()), transport)
+	}
+}
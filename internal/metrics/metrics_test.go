@@ -0,0 +1,94 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	m := New()
+	m.ToolCall("get_record", OutcomeSuccess, 3*time.Millisecond)
+	m.ToolCall("get_record", OutcomeSuccess, 2*time.Second)
+	m.ToolCall("put_record", OutcomeRateLimited, 0)
+	m.AerospikeOperation("get", 20*time.Millisecond, nil)
+	m.AerospikeOperation("put", time.Millisecond, errors.New("timeout"))
+	m.RateLimited("write")
+	m.ConnectedClients("sse", func() int { return 3 })
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := buf.String()
+
+	expected := []string{
+		"# TYPE aerospike_mcp_tool_calls_total counter",
+		`aerospike_mcp_tool_calls_total{outcome="success",tool="get_record"} 2`,
+		`aerospike_mcp_tool_calls_total{outcome="rate_limited",tool="put_record"} 1`,
+		"# TYPE aerospike_mcp_tool_call_duration_seconds histogram",
+		`aerospike_mcp_tool_call_duration_seconds_bucket{tool="get_record",le="0.005"} 1`,
+		`aerospike_mcp_tool_call_duration_seconds_bucket{tool="get_record",le="2.5"} 2`,
+		`aerospike_mcp_tool_call_duration_seconds_bucket{tool="get_record",le="+Inf"} 2`,
+		`aerospike_mcp_tool_call_duration_seconds_sum{tool="get_record"} 2.003`,
+		`aerospike_mcp_tool_call_duration_seconds_count{tool="get_record"} 2`,
+		`aerospike_mcp_aerospike_operation_duration_seconds_bucket{operation="get",le="0.01"} 0`,
+		`aerospike_mcp_aerospike_operation_duration_seconds_bucket{operation="get",le="0.025"} 1`,
+		`aerospike_mcp_aerospike_operation_errors_total{operation="put"} 1`,
+		`aerospike_mcp_rate_limit_rejections_total{category="write"} 1`,
+		"# TYPE aerospike_mcp_connected_clients gauge",
+		`aerospike_mcp_connected_clients{transport="sse"} 3`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `errors_total{operation="get"}`) {
+		t.Error("Expected no error count for successful operations")
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	m := New()
+	m.ToolCall("a\"b\\c\nd", OutcomeSuccess, 0)
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := `{outcome="success",tool="a\"b\\c\nd"} 1`; !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected escaped labels %s, got:\n%s", want, buf.String())
+	}
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.RateLimited("read")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Expected Prometheus text content type, got %s", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `aerospike_mcp_rate_limit_rejections_total{category="read"} 1`) {
+		t.Errorf("Expected rate limit counter, got:\n%s", rec.Body.String())
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	m.ToolCall("get_record", OutcomeSuccess, time.Millisecond)
+	m.AerospikeOperation("get", time.Millisecond, nil)
+	m.RateLimited("read")
+	m.ConnectedClients("sse", func() int { return 0 })
+	if err := m.Write(&bytes.Buffer{}); err != nil {
+		t.Errorf("Expected nil metrics to write nothing, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsWindow is the number of recent calls per tool that latency
+// percentiles are computed from.
+const statsWindow = 1024
+
+// ToolStats keeps per-tool call counts and recent latencies in memory, for
+// reporting to clients rather than scraping. A nil *ToolStats discards
+// observations.
+type ToolStats struct {
+	started time.Time
+
+	mu    sync.Mutex
+	tools map[string]*toolStats
+}
+
+type toolStats struct {
+	calls    uint64
+	errors   uint64
+	rejected uint64 // invalid, rate limited, or over quota
+	total    time.Duration
+	max      time.Duration
+	last     time.Time
+
+	window []time.Duration // ring buffer of recent latencies
+	next   int
+}
+
+// ToolStat is a summary of one tool's calls.
+type ToolStat struct {
+	Tool      string      `json:"tool"`
+	Calls     uint64      `json:"calls"`
+	Errors    uint64      `json:"errors"`
+	Rejected  uint64      `json:"rejected"`
+	ErrorRate float64     `json:"error_rate"` // errors per executed call
+	Latency   LatencyStat `json:"latency_ms"`
+	LastCall  time.Time   `json:"last_call"`
+}
+
+// LatencyStat summarizes executed calls' latency in milliseconds. The
+// percentiles cover the most recent calls; the mean and max cover all of
+// them.
+type LatencyStat struct {
+	P50  float64 `json:"p50"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+}
+
+// NewToolStats creates empty tool statistics.
+func NewToolStats() *ToolStats {
+	return &ToolStats{started: time.Now(), tools: make(map[string]*toolStats)}
+}
+
+// Record records a tool call with the given outcome. Only executed calls,
+// successful or not, count towards latency.
+func (s *ToolStats) Record(tool, outcome string, duration time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tools[tool]
+	if !ok {
+		t = &toolStats{}
+		s.tools[tool] = t
+	}
+	t.calls++
+	t.last = time.Now()
+
+	switch outcome {
+	case OutcomeSuccess, OutcomeError:
+		if outcome == OutcomeError {
+			t.errors++
+		}
+		t.total += duration
+		if duration > t.max {
+			t.max = duration
+		}
+		if len(t.window) < statsWindow {
+			t.window = append(t.window, duration)
+		} else {
+			t.window[t.next] = duration
+			t.next = (t.next + 1) % statsWindow
+		}
+	default:
+		t.rejected++
+	}
+}
+
+// Uptime returns the time since the statistics were created.
+func (s *ToolStats) Uptime() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Since(s.started)
+}
+
+// Snapshot returns the statistics of every tool called so far, most called
+// first.
+func (s *ToolStats) Snapshot() []ToolStat {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	stats := make([]ToolStat, 0, len(s.tools))
+	for name, t := range s.tools {
+		stats = append(stats, t.summary(name))
+	}
+	s.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Calls != stats[j].Calls {
+			return stats[i].Calls > stats[j].Calls
+		}
+		return stats[i].Tool < stats[j].Tool
+	})
+	return stats
+}
+
+// summary computes a tool's statistics. The caller must hold the lock.
+func (t *toolStats) summary(name string) ToolStat {
+	stat := ToolStat{
+		Tool:     name,
+		Calls:    t.calls,
+		Errors:   t.errors,
+		Rejected: t.rejected,
+		LastCall: t.last,
+	}
+	executed := t.calls - t.rejected
+	if executed == 0 {
+		return stat
+	}
+	stat.ErrorRate = float64(t.errors) / float64(executed)
+
+	sorted := make([]time.Duration, len(t.window))
+	copy(sorted, t.window)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	stat.Latency = LatencyStat{
+		P50:  milliseconds(percentile(sorted, 0.50)),
+		P95:  milliseconds(percentile(sorted, 0.95)),
+		P99:  milliseconds(percentile(sorted, 0.99)),
+		Mean: milliseconds(t.total / time.Duration(executed)),
+		Max:  milliseconds(t.max),
+	}
+	return stat
+}
+
+// Latencies summarizes a set of latencies, such as those of a benchmark.
+func Latencies(latencies []time.Duration) LatencyStat {
+	if len(latencies) == 0 {
+		return LatencyStat{}
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return LatencyStat{
+		P50:  milliseconds(percentile(sorted, 0.50)),
+		P95:  milliseconds(percentile(sorted, 0.95)),
+		P99:  milliseconds(percentile(sorted, 0.99)),
+		Mean: milliseconds(total / time.Duration(len(sorted))),
+		Max:  milliseconds(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// milliseconds converts d to milliseconds, rounded to microseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
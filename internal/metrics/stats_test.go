@@ -0,0 +1,88 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToolStats(t *testing.T) {
+	s := NewToolStats()
+	for i := 1; i <= 100; i++ {
+		s.Record("get_record", OutcomeSuccess, time.Duration(i)*time.Millisecond)
+	}
+	s.Record("put_record", OutcomeSuccess, 4*time.Millisecond)
+	s.Record("put_record", OutcomeError, 6*time.Millisecond)
+	s.Record("put_record", OutcomeRateLimited, 0)
+
+	stats := s.Snapshot()
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 tools, got %d", len(stats))
+	}
+
+	get := stats[0]
+	if get.Tool != "get_record" || get.Calls != 100 {
+		t.Errorf("Expected get_record with 100 calls first, got %s with %d", get.Tool, get.Calls)
+	}
+	want := LatencyStat{P50: 50, P95: 95, P99: 99, Mean: 50.5, Max: 100}
+	if get.Latency != want {
+		t.Errorf("Expected latency %+v, got %+v", want, get.Latency)
+	}
+
+	put := stats[1]
+	if put.Calls != 3 || put.Errors != 1 || put.Rejected != 1 {
+		t.Errorf("Expected 3 calls, 1 error, and 1 rejection, got %+v", put)
+	}
+	if put.ErrorRate != 0.5 {
+		t.Errorf("Expected error rate 0.5, got %v", put.ErrorRate)
+	}
+	if put.Latency.Max != 6 || put.Latency.Mean != 5 {
+		t.Errorf("Expected rejected calls excluded from latency, got %+v", put.Latency)
+	}
+}
+
+func TestToolStatsWindow(t *testing.T) {
+	s := NewToolStats()
+	for i := 0; i < statsWindow; i++ {
+		s.Record("scan_set", OutcomeSuccess, time.Second)
+	}
+	for i := 0; i < statsWindow; i++ {
+		s.Record("scan_set", OutcomeSuccess, time.Millisecond)
+	}
+
+	stat := s.Snapshot()[0]
+	if stat.Latency.P99 != 1 {
+		t.Errorf("Expected percentiles from recent calls only, got p99 %v", stat.Latency.P99)
+	}
+	if stat.Latency.Max != 1000 {
+		t.Errorf("Expected max over all calls, got %v", stat.Latency.Max)
+	}
+}
+
+func TestNilToolStats(t *testing.T) {
+	var s *ToolStats
+	s.Record("get_record", OutcomeSuccess, time.Millisecond)
+	if stats := s.Snapshot(); stats != nil {
+		t.Errorf("Expected no stats, got %v", stats)
+	}
+}
+
+func TestLatencies(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	want := LatencyStat{P50: 50, P95: 95, P99: 99, Mean: 50.5, Max: 100}
+	if got := Latencies(latencies); got != want {
+		t.Errorf("Expected latency %+v, got %+v", want, got)
+	}
+	if latencies[0] != 100*time.Millisecond {
+		t.Error("Expected the latencies to be left unsorted")
+	}
+	if got := Latencies(nil); got != (LatencyStat{}) {
+		t.Errorf("Expected no latency for no samples, got %+v", got)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+)
+
+// dataModelPromptSample is the number of records the data modeling prompt
+// samples by default.
+const dataModelPromptSample = 1000
+
+// dataModelQuestions guide the model through the review.
+const dataModelQuestions = `## Review
+
+Using the data above, review the set's data model against Aerospike best practices:
+
+1. Bin count: are there many bins per record, or bins present in only a few records, that would be better grouped into a map? Are bin names short, given that each record stores them?
+2. Record size: how close do the largest records come to the namespace's write-block or max-record-size limit? Would large or growing records be better split across records?
+3. Key design: are user keys stored with the records, and is that needed? Do the key lengths suggest composite keys, and do they spread records evenly or concentrate writes on a few hot keys, as high generations hint?
+4. CDT depth: are lists and maps nested deeper than operations and indexes can easily reach? Are unbounded lists or maps growing within one record?
+5. TTL strategy: do the records' TTLs match the namespace's default-ttl and the data's lifetime? Are records that never expire intended to be kept, and could eviction or expiration be relied on instead of deletes?
+6. Access pattern: from the read, write, and delete counters, is the namespace read- or write-heavy, and do not-found reads, generation failures, or key-busy errors point to a modeling problem?
+
+List the findings from most to least impactful, each with the evidence for it and a concrete change, and note what the sample is too small to tell.
+`
+
+// registerDataModelPrompt adds the review_data_model prompt.
+func (r *Registry) registerDataModelPrompt() {
+	r.register(PromptDefinition{
+		Name:        "review_data_model",
+		Title:       "Data modeling advisor",
+		Description: "Gather a set's inferred schema, record size distribution, and access statistics, with questions reviewing bin count, key design, CDT depth, and TTL strategy",
+		Arguments: []PromptArgument{
+			{Name: "namespace", Description: "Namespace of the set", Required: true},
+			{Name: "set", Description: "Set to review", Required: true},
+			{Name: "sample_size", Description: fmt.Sprintf("Number of records to sample (default %d)", dataModelPromptSample)},
+		},
+	}, r.dataModelPrompt)
+}
+
+// dataModelPrompt writes the review_data_model prompt.
+func (r *Registry) dataModelPrompt(ctx context.Context, args map[string]string) (string, error) {
+	namespace, setName := args["namespace"], args["set"]
+	sampleSize, err := sampleSizeArg(args["sample_size"], dataModelPromptSample)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Review the data model of set %s.%s from the data below.\n\n", namespace, setName)
+
+	set, err := r.findSet(ctx, namespace, setName)
+	r.writeSection(ctx, &b, "Set", set, err)
+
+	config, err := r.client.GetNamespaceConfig(ctx, namespace, setName)
+	r.writeSection(ctx, &b, "Namespace and set configuration (default TTL in seconds)", config, err)
+
+	records, err := r.sampleSet(ctx, namespace, setName, sampleSize)
+	if err != nil {
+		r.writeSection(ctx, &b, "Inferred schema", nil, err)
+		r.writeSection(ctx, &b, "Record profile", nil, err)
+	} else {
+		schema := aerospike.InferSchema(records)
+		schema.Namespace, schema.Set = namespace, setName
+		r.writeSection(ctx, &b, "Inferred schema", schema, nil)
+		r.writeSection(ctx, &b, "Record profile (sizes in bytes as JSON, TTLs in seconds remaining)", aerospike.ProfileRecords(records), nil)
+	}
+
+	access, err := r.client.GetAccessStats(ctx, namespace, setName)
+	r.writeSection(ctx, &b, "Access statistics (summed over nodes since they started)", access, err)
+
+	b.WriteString(dataModelQuestions)
+	return b.String(), nil
+}
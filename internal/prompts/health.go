@@ -0,0 +1,95 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package prompts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+)
+
+// healthPromptErrors is the number of recent failed events the health
+// prompt includes.
+const healthPromptErrors = 20
+
+// healthQuestions guide the model through the triage.
+const healthQuestions = `## Triage
+
+Using the data above, assess the cluster's health:
+
+1. Are all nodes active, and do they agree on the cluster size? Is any node's uptime much shorter than the others', suggesting a restart?
+2. Are migrations in progress? Could they explain higher latency or load?
+3. Is any namespace in stop-writes, or close to its memory or storage limits?
+4. Which latency histograms have more than a few percent of operations over 8 units, and on which nodes? Is one node slower than the rest?
+5. Do the recent errors share a cause, such as one namespace, node, operation, or error code?
+
+Rate the cluster healthy, degraded, or critical. List the findings from most to least severe, each with the evidence for it, and recommend next steps, naming the tools that would investigate further, such as node_stats, describe_namespace, or cluster_info.
+`
+
+// registerHealthPrompt adds the diagnose_cluster_health prompt.
+func (r *Registry) registerHealthPrompt() {
+	r.register(PromptDefinition{
+		Name:        "diagnose_cluster_health",
+		Title:       "Diagnose cluster health",
+		Description: "Gather node statistics, namespace utilization, latency histograms, migration status, and recent errors, with questions guiding a health triage",
+		Arguments: []PromptArgument{
+			{Name: "namespace", Description: "Limit namespace utilization and latency to this namespace"},
+		},
+	}, r.healthPrompt)
+}
+
+// healthPrompt writes the diagnose_cluster_health prompt.
+func (r *Registry) healthPrompt(ctx context.Context, args map[string]string) (string, error) {
+	namespace := args["namespace"]
+
+	var b strings.Builder
+	b.WriteString("Diagnose the health of this Aerospike cluster from the live data below.\n\n")
+
+	state, err := r.client.ClusterState(ctx)
+	r.writeSection(ctx, &b, "Cluster state (active nodes, migrations remaining, namespaces in stop-writes)", state, err)
+
+	nodes, err := r.client.GetNodeStats(ctx, "")
+	for i := range nodes {
+		nodes[i].Stats = nil
+	}
+	r.writeSection(ctx, &b, "Nodes", nodes, err)
+
+	if namespace != "" {
+		ns, err := r.client.DescribeNamespace(ctx, namespace)
+		r.writeSection(ctx, &b, "Namespace utilization", ns, err)
+	} else {
+		namespaces, err := r.client.ListNamespaces(ctx)
+		r.writeSection(ctx, &b, "Namespace utilization", namespaces, err)
+	}
+
+	latencies, err := r.client.GetLatencies(ctx)
+	if namespace != "" {
+		latencies = namespaceLatencies(latencies, namespace)
+	}
+	r.writeSection(ctx, &b, "Latency histograms (percent of operations slower than 1, 8, and 64 units)", latencies, err)
+
+	if r.audit != nil && r.config.GrantedRole(ctx).CanAdmin() {
+		failed := false
+		events := r.audit.Query(audit.Query{Namespace: namespace, Success: &failed, Limit: healthPromptErrors})
+		r.writeSection(ctx, &b, "Recent errors (failed audited operations, newest first)", events, nil)
+	}
+
+	b.WriteString(healthQuestions)
+	return b.String(), nil
+}
+
+// namespaceLatencies returns the histograms of namespace, named
+// {namespace}-read and so on.
+func namespaceLatencies(histograms []aerospike.LatencyHistogram, namespace string) []aerospike.LatencyHistogram {
+	prefix := "{" + namespace + "}-"
+	var kept []aerospike.LatencyHistogram
+	for _, h := range histograms {
+		if strings.HasPrefix(h.Name, prefix) {
+			kept = append(kept, h)
+		}
+	}
+	return kept
+}
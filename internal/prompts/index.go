@@ -0,0 +1,160 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package prompts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+)
+
+// indexPromptSample is the number of records the index prompt samples by
+// default.
+const indexPromptSample = 1000
+
+// maxPromptSample is the largest sample_size a prompt accepts.
+const maxPromptSample = 10000
+
+// indexQuestions guide the model to an index recommendation.
+const indexQuestions = `## Recommendation
+
+Using the data above, recommend whether and how to index the bin:
+
+1. Index type: NUMERIC for integers, STRING for strings, GEO2DSPHERE for GeoJSON, or BLOB for bytes. If the bin holds more than one type, only values of the index type are indexed; say which records that leaves out.
+2. Collection type: DEFAULT for a scalar bin, LIST to index list elements, or MAPKEYS or MAPVALUES to index a map's keys or values. Base the choice on the queries the index should serve.
+3. Selectivity: with the distinct values and the top value's share, how many records would a typical equality query match? Is an index worth it, or would a scan with a filter expression do?
+4. Memory cost: starting from the estimated index entries, estimate the index's memory per node and across the cluster. State the per-entry size you assume and how the replication factor and number of nodes enter, rather than presenting the figure as exact.
+5. Is an existing index on the set already serving the bin?
+
+Finish with the create_index arguments to use (index name, bin, index type, and collection type), or explain why not to create one.
+`
+
+// indexEstimate is a set's size and the index entries an index on a bin
+// would hold, extrapolated from the sample: one entry for each element of
+// a list or map, or for a scalar value.
+type indexEstimate struct {
+	ObjectCount      int64 `json:"set_object_count"`
+	EstimatedEntries int64 `json:"estimated_index_entries"`
+}
+
+// registerIndexPrompt adds the design_secondary_index prompt.
+func (r *Registry) registerIndexPrompt() {
+	r.register(PromptDefinition{
+		Name:        "design_secondary_index",
+		Title:       "Design a secondary index",
+		Description: "Profile a bin's values from a sample of a set, with questions guiding a recommendation of index type, collection type, and memory cost",
+		Arguments: []PromptArgument{
+			{Name: "namespace", Description: "Namespace of the set", Required: true},
+			{Name: "set", Description: "Set holding the bin", Required: true},
+			{Name: "bin", Description: "Bin to index", Required: true},
+			{Name: "sample_size", Description: fmt.Sprintf("Number of records to sample (default %d)", indexPromptSample)},
+		},
+	}, r.indexPrompt)
+}
+
+// indexPrompt writes the design_secondary_index prompt.
+func (r *Registry) indexPrompt(ctx context.Context, args map[string]string) (string, error) {
+	namespace, setName, bin := args["namespace"], args["set"], args["bin"]
+	sampleSize, err := sampleSizeArg(args["sample_size"], indexPromptSample)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Design a secondary index on bin %s of set %s.%s from the data below.\n\n", bin, namespace, setName)
+
+	set, setErr := r.findSet(ctx, namespace, setName)
+	records, err := r.sampleSet(ctx, namespace, setName, sampleSize)
+	if err != nil {
+		r.writeSection(ctx, &b, "Inferred schema", nil, err)
+		r.writeSection(ctx, &b, "Bin profile", nil, err)
+	} else {
+		schema := aerospike.InferSchema(records)
+		schema.Namespace, schema.Set = namespace, setName
+		r.writeSection(ctx, &b, "Inferred schema", schema, nil)
+
+		profile := aerospike.ProfileBin(records, bin)
+		r.writeSection(ctx, &b, "Bin profile (cardinality of the sampled values)", profile, nil)
+		if setErr == nil {
+			estimate := indexEstimate{ObjectCount: set.ObjectCount, EstimatedEntries: estimateEntries(set.ObjectCount, profile)}
+			r.writeSection(ctx, &b, "Index size estimate", estimate, nil)
+		}
+	}
+	if setErr != nil {
+		r.writeSection(ctx, &b, "Index size estimate", nil, setErr)
+	}
+
+	indexes, err := r.client.ListIndexes(ctx, namespace)
+	r.writeSection(ctx, &b, "Existing indexes on the set", setIndexes(indexes, setName), err)
+
+	b.WriteString(indexQuestions)
+	return b.String(), nil
+}
+
+// sampleSizeArg parses a sample_size argument, or returns def if it is
+// empty. Samples are limited to maxPromptSample records, and to the
+// namespace's max_records by the scan itself.
+func sampleSizeArg(arg string, def int) (int, error) {
+	if arg == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n <= 0 || n > maxPromptSample {
+		return 0, fmt.Errorf("sample_size must be an integer from 1 to %d, got %q", maxPromptSample, arg)
+	}
+	return n, nil
+}
+
+// findSet returns the metadata of a set.
+func (r *Registry) findSet(ctx context.Context, namespace, setName string) (aerospike.SetInfo, error) {
+	sets, err := r.client.ListSets(ctx, namespace)
+	if err != nil {
+		return aerospike.SetInfo{}, err
+	}
+	for _, set := range sets {
+		if set.Name == setName {
+			return set, nil
+		}
+	}
+	return aerospike.SetInfo{}, fmt.Errorf("set not found: %s.%s", namespace, setName)
+}
+
+// sampleSet reads up to sampleSize records of a set. A sample cut short by
+// the deadline or max_result_bytes still describes the records it holds.
+func (r *Registry) sampleSet(ctx context.Context, namespace, setName string, sampleSize int) ([]*aerospike.Record, error) {
+	page, err := r.client.ScanSet(ctx, namespace, setName, nil, sampleSize, 0, "")
+	if err != nil && !errors.Is(err, aerospike.ErrIncomplete) && !errors.Is(err, aerospike.ErrResultTooLarge) {
+		return nil, err
+	}
+	return page.Records, nil
+}
+
+// estimateEntries extrapolates the index entries for a bin over objects
+// records from its profile.
+func estimateEntries(objects int64, p *aerospike.BinProfile) int64 {
+	if p.Sampled == 0 {
+		return 0
+	}
+	perRecord := float64(p.Present) / float64(p.Sampled)
+	if p.AvgElements > 0 {
+		perRecord *= p.AvgElements
+	}
+	return int64(math.Round(float64(objects) * perRecord))
+}
+
+// setIndexes returns the indexes on setName.
+func setIndexes(indexes []aerospike.IndexInfo, setName string) []aerospike.IndexInfo {
+	kept := []aerospike.IndexInfo{}
+	for _, index := range indexes {
+		if index.Set == setName {
+			kept = append(kept, index)
+		}
+	}
+	return kept
+}
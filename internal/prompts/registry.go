@@ -0,0 +1,157 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package prompts implements MCP prompt templates that gather live cluster
+// context into the first message of a conversation.
+package prompts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// PromptDefinition represents an MCP prompt definition.
+type PromptDefinition struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+// PromptArgument describes an argument a prompt accepts.
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptResult is a prompt filled in with its arguments and cluster data.
+type PromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// PromptMessage is one message of a prompt.
+type PromptMessage struct {
+	Role    string        `json:"role"`
+	Content PromptContent `json:"content"`
+}
+
+// PromptContent is the text content of a prompt message.
+type PromptContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// prompt is a registered prompt: its definition, and the function writing
+// its text from the arguments.
+type prompt struct {
+	definition PromptDefinition
+	build      func(ctx context.Context, args map[string]string) (string, error)
+}
+
+// Redactor withholds sensitive bins from a JSON document, as the server
+// does for tool results, naming operation in the audit log.
+type Redactor func(ctx context.Context, operation, namespace, set string, data []byte) ([]byte, error)
+
+// Registry manages available MCP prompts.
+type Registry struct {
+	client  *aerospike.Client
+	config  *config.Config
+	audit   *audit.Logger
+	redact  Redactor
+	prompts map[string]prompt
+}
+
+// NewRegistry creates a new prompt registry.
+func NewRegistry(client *aerospike.Client, cfg *config.Config) *Registry {
+	r := &Registry{
+		client:  client,
+		config:  cfg,
+		prompts: make(map[string]prompt),
+	}
+	r.registerHealthPrompt()
+	r.registerIndexPrompt()
+	r.registerDataModelPrompt()
+	return r
+}
+
+// SetAuditLogger sets the audit log whose recent failures prompts may
+// include for admins.
+func (r *Registry) SetAuditLogger(logger *audit.Logger) {
+	r.audit = logger
+}
+
+// SetRedactor sets the redaction applied to the data prompts gather.
+func (r *Registry) SetRedactor(redact Redactor) {
+	r.redact = redact
+}
+
+// register adds a prompt.
+func (r *Registry) register(definition PromptDefinition, build func(ctx context.Context, args map[string]string) (string, error)) {
+	r.prompts[definition.Name] = prompt{definition: definition, build: build}
+}
+
+// List returns all prompt definitions, sorted by name.
+func (r *Registry) List() []PromptDefinition {
+	definitions := make([]PromptDefinition, 0, len(r.prompts))
+	for _, p := range r.prompts {
+		definitions = append(definitions, p.definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+// Get fills in the prompt called name with args, reading the cluster data
+// it gathers. It fails if the prompt is unknown or a required argument is
+// missing.
+func (r *Registry) Get(ctx context.Context, name string, args map[string]string) (*PromptResult, error) {
+	p, ok := r.prompts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt: %s", name)
+	}
+	for _, arg := range p.definition.Arguments {
+		if arg.Required && args[arg.Name] == "" {
+			return nil, fmt.Errorf("prompt %s requires argument %s", name, arg.Name)
+		}
+	}
+
+	text, err := p.build(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return &PromptResult{
+		Description: p.definition.Description,
+		Messages: []PromptMessage{
+			{Role: "user", Content: PromptContent{Type: "text", Text: text}},
+		},
+	}, nil
+}
+
+// writeSection writes a titled section holding v as JSON, redacted, or the
+// error that kept it from being read. A prompt is still useful with some
+// of its data missing, so errors don't fail it.
+func (r *Registry) writeSection(ctx context.Context, b *strings.Builder, title string, v interface{}, err error) {
+	fmt.Fprintf(b, "## %s\n\n", title)
+	var data []byte
+	if err == nil {
+		data, err = json.MarshalIndent(v, "", "  ")
+	}
+	if err == nil && r.redact != nil {
+		data, err = r.redact(ctx, "prompts/get", "", "", data)
+	}
+	if err != nil {
+		fmt.Fprintf(b, "Unavailable: %v\n\n", err)
+		return
+	}
+	fmt.Fprintf(b, "```json\n%s\n```\n\n", data)
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package prompts
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestRegistryList(t *testing.T) {
+	r := NewRegistry(nil, config.DefaultConfig())
+
+	definitions := r.List()
+	if len(definitions) == 0 {
+		t.Fatal("Expected prompts to be registered")
+	}
+	for i, def := range definitions {
+		if def.Name == "" || def.Description == "" {
+			t.Errorf("Expected prompt %d to have a name and description, got %+v", i, def)
+		}
+		if i > 0 && definitions[i-1].Name >= def.Name {
+			t.Errorf("Expected prompts sorted by name, got %s before %s", definitions[i-1].Name, def.Name)
+		}
+	}
+}
+
+func TestRegistryGet(t *testing.T) {
+	r := NewRegistry(nil, config.DefaultConfig())
+	r.register(PromptDefinition{
+		Name:      "echo",
+		Arguments: []PromptArgument{{Name: "text", Required: true}},
+	}, func(_ context.Context, args map[string]string) (string, error) {
+		return "say " + args["text"], nil
+	})
+
+	result, err := r.Get(context.Background(), "echo", map[string]string{"text": "hi"})
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(result.Messages) != 1 || result.Messages[0].Role != "user" || result.Messages[0].Content.Text != "say hi" {
+		t.Errorf("Expected one user message saying hi, got %+v", result.Messages)
+	}
+
+	if _, err := r.Get(context.Background(), "echo", nil); err == nil {
+		t.Error("Expected error for a missing required argument")
+	}
+	if _, err := r.Get(context.Background(), "unknown", nil); err == nil {
+		t.Error("Expected error for an unknown prompt")
+	}
+}
+
+func TestWriteSection(t *testing.T) {
+	r := NewRegistry(nil, config.DefaultConfig())
+	ctx := context.Background()
+
+	var b strings.Builder
+	r.writeSection(ctx, &b, "Data", map[string]int{"n": 1}, nil)
+	r.writeSection(ctx, &b, "Missing", nil, errors.New("node timeout"))
+	want := "## Data\n\n```json\n{\n  \"n\": 1\n}\n```\n\n## Missing\n\nUnavailable: node timeout\n\n"
+	if b.String() != want {
+		t.Errorf("Expected %q, got %q", want, b.String())
+	}
+
+	r.SetRedactor(func(_ context.Context, operation, _, _ string, data []byte) ([]byte, error) {
+		if operation != "prompts/get" {
+			t.Errorf("Expected operation prompts/get, got %s", operation)
+		}
+		return []byte(strings.ReplaceAll(string(data), "secret", "[REDACTED]")), nil
+	})
+	b.Reset()
+	r.writeSection(ctx, &b, "Data", map[string]string{"token": "secret"}, nil)
+	if strings.Contains(b.String(), "secret") || !strings.Contains(b.String(), "[REDACTED]") {
+		t.Errorf("Expected the section to be redacted, got %q", b.String())
+	}
+
+	r.SetRedactor(func(context.Context, string, string, string, []byte) ([]byte, error) {
+		return nil, errors.New("redacting response: bad JSON")
+	})
+	b.Reset()
+	r.writeSection(ctx, &b, "Data", map[string]string{"token": "secret"}, nil)
+	if strings.Contains(b.String(), "secret") || !strings.Contains(b.String(), "Unavailable: redacting response") {
+		t.Errorf("Expected a section that can't be redacted to be withheld, got %q", b.String())
+	}
+}
+
+func TestNamespaceLatencies(t *testing.T) {
+	histograms := []aerospike.LatencyHistogram{
+		{Name: "{test}-read"}, {Name: "{prod}-read"}, {Name: "batch-index"}, {Name: "{test}-write"},
+	}
+
+	got := namespaceLatencies(histograms, "test")
+	if len(got) != 2 || got[0].Name != "{test}-read" || got[1].Name != "{test}-write" {
+		t.Errorf("Expected the test namespace's histograms, got %+v", got)
+	}
+}
+
+func TestSampleSizeArg(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    int
+		wantErr bool
+	}{
+		{"", indexPromptSample, false},
+		{"250", 250, false},
+		{"0", 0, true},
+		{"-5", 0, true},
+		{"10000", 10000, false},
+		{"10001", 0, true},
+		{"many", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := sampleSizeArg(tt.arg, indexPromptSample)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("sampleSizeArg(%q): expected %d (error %v), got %d (%v)", tt.arg, tt.want, tt.wantErr, got, err)
+		}
+	}
+}
+
+func TestEstimateEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects int64
+		profile aerospike.BinProfile
+		want    int64
+	}{
+		{"scalar in every record", 1000, aerospike.BinProfile{Sampled: 100, Present: 100}, 1000},
+		{"scalar in some records", 1000, aerospike.BinProfile{Sampled: 100, Present: 25}, 250},
+		{"list elements", 1000, aerospike.BinProfile{Sampled: 100, Present: 50, AvgElements: 3.5}, 1750},
+		{"empty sample", 1000, aerospike.BinProfile{}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := estimateEntries(tt.objects, &tt.profile); got != tt.want {
+			t.Errorf("%s: expected %d entries, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSetIndexes(t *testing.T) {
+	indexes := []aerospike.IndexInfo{
+		{Name: "users_age", Set: "users"}, {Name: "orders_total", Set: "orders"}, {Name: "users_email", Set: "users"},
+	}
+
+	got := setIndexes(indexes, "users")
+	if len(got) != 2 || got[0].Name != "users_age" || got[1].Name != "users_email" {
+		t.Errorf("Expected the users set's indexes, got %+v", got)
+	}
+	if got := setIndexes(nil, "users"); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty list, got %#v", got)
+	}
+}
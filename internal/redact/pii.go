@@ -0,0 +1,170 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// PII detector names.
+const (
+	DetectorEmail      = "email"
+	DetectorCreditCard = "credit_card"
+	DetectorPhone      = "phone"
+)
+
+// Detectors lists the supported PII detectors in the order they are applied.
+var Detectors = []string{DetectorCreditCard, DetectorEmail, DetectorPhone}
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	creditCardPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	phonePattern      = regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{3}\)|\b\d{3})[ .-]?\d{3}[ .-]?\d{4}\b`)
+)
+
+// piiMasker masks PII found in string bin values.
+type piiMasker struct {
+	defaults   []string
+	namespaces map[string][]string
+}
+
+func newPIIMasker(cfg *config.PIIConfig) *piiMasker {
+	if cfg == nil {
+		return nil
+	}
+	defaults := cfg.Detectors
+	if len(defaults) == 0 {
+		defaults = Detectors
+	}
+	return &piiMasker{defaults: defaults, namespaces: cfg.Namespaces}
+}
+
+// detectors returns the detectors enabled for a namespace.
+func (m *piiMasker) detectors(namespace string) []string {
+	if d, ok := m.namespaces[namespace]; ok {
+		return d
+	}
+	return m.defaults
+}
+
+// mask returns v with PII masked in every string it contains, and whether
+// anything was masked. Maps and lists are modified in place.
+func (m *piiMasker) mask(v interface{}, detectors []string) (interface{}, bool) {
+	switch t := v.(type) {
+	case string:
+		masked := maskString(t, detectors)
+		return masked, masked != t
+	case map[string]interface{}:
+		changed := false
+		for k, item := range t {
+			masked, ok := m.mask(item, detectors)
+			if ok {
+				t[k] = masked
+				changed = true
+			}
+		}
+		return t, changed
+	case []interface{}:
+		changed := false
+		for i, item := range t {
+			masked, ok := m.mask(item, detectors)
+			if ok {
+				t[i] = masked
+				changed = true
+			}
+		}
+		return t, changed
+	}
+	return v, false
+}
+
+// maskString masks each kind of PII in s. Credit cards are checked before
+// phone numbers so that card digits aren't mistaken for phone numbers.
+func maskString(s string, detectors []string) string {
+	for _, d := range Detectors {
+		if !contains(detectors, d) {
+			continue
+		}
+		switch d {
+		case DetectorCreditCard:
+			s = creditCardPattern.ReplaceAllStringFunc(s, func(match string) string {
+				if !luhnValid(match) {
+					return match
+				}
+				return maskDigits(match)
+			})
+		case DetectorEmail:
+			s = emailPattern.ReplaceAllStringFunc(s, maskEmail)
+		case DetectorPhone:
+			s = phonePattern.ReplaceAllStringFunc(s, maskDigits)
+		}
+	}
+	return s
+}
+
+// maskEmail keeps the first character of the local part and the domain.
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskDigits replaces all but the last four digits with '*', keeping separators.
+func maskDigits(s string) string {
+	digits := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+
+	var b strings.Builder
+	seen := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			seen++
+			if seen <= digits-4 {
+				b.WriteByte('*')
+				continue
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
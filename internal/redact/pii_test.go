@@ -0,0 +1,121 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestMaskString(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		detectors []string
+		expected  string
+	}{
+		{
+			name:      "email",
+			input:     "contact alice@example.com today",
+			detectors: Detectors,
+			expected:  "contact a***@example.com today",
+		},
+		{
+			name:      "credit card",
+			input:     "4111 1111 1111 1111",
+			detectors: Detectors,
+			expected:  "**** **** **** 1111",
+		},
+		{
+			name:      "digits failing luhn are not a card",
+			input:     "order 1234567890123456",
+			detectors: []string{DetectorCreditCard},
+			expected:  "order 1234567890123456",
+		},
+		{
+			name:      "phone",
+			input:     "call (555) 123-4567",
+			detectors: Detectors,
+			expected:  "call (***) ***-4567",
+		},
+		{
+			name:      "disabled detector",
+			input:     "alice@example.com",
+			detectors: []string{DetectorPhone},
+			expected:  "alice@example.com",
+		},
+		{
+			name:      "no pii",
+			input:     "premium",
+			detectors: Detectors,
+			expected:  "premium",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskString(tt.input, tt.detectors); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"4111111111111111", true},
+		{"5500-0000-0000-0004", true},
+		{"4111111111111112", false},
+		{"0000000000", false}, // too short
+	}
+
+	for _, tt := range tests {
+		if got := luhnValid(tt.input); got != tt.expected {
+			t.Errorf("luhnValid(%q): expected %v, got %v", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestJSONMasksPII(t *testing.T) {
+	r := New([]config.RedactionRule{{Bin: "ssn"}}, &config.PIIConfig{
+		Namespaces: map[string][]string{
+			"analytics": {},
+			"crm":       {DetectorEmail},
+		},
+	})
+
+	input := `[
+		{"namespace":"ad","set":"users","bins":{"ssn":"123-45-6789","contact":"bob@example.com","tags":["call 555-123-4567"]}},
+		{"namespace":"analytics","set":"events","bins":{"contact":"carol@example.com"}},
+		{"namespace":"crm","set":"leads","bins":{"contact":"dave@example.com","phone":"555-987-6543"}}
+	]`
+
+	out, report, err := r.JSON([]byte(input), "", "")
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+
+	if expected := []string{"ad.users.ssn"}; !reflect.DeepEqual(report.Redacted, expected) {
+		t.Errorf("Expected redacted %v, got %v", expected, report.Redacted)
+	}
+	if expected := []string{"ad.users.contact", "ad.users.tags", "crm.leads.contact"}; !reflect.DeepEqual(report.Masked, expected) {
+		t.Errorf("Expected masked %v, got %v", expected, report.Masked)
+	}
+
+	s := string(out)
+	for _, want := range []string{"b***@example.com", "***-***-4567", "carol@example.com", "d***@example.com", "555-987-6543"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Expected output to contain %q, got %s", want, s)
+		}
+	}
+	if strings.Contains(s, "123-45-6789") {
+		t.Errorf("Expected redacted ssn to be removed, got %s", s)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redact removes sensitive bin values from responses before they
+// are returned to clients.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"sort"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// Placeholder replaces redacted bin values.
+const Placeholder = "[REDACTED]"
+
+// Redactor replaces the values of bins matching the configured rules and,
+// optionally, masks PII detected in the remaining bins.
+type Redactor struct {
+	rules []config.RedactionRule
+	pii   *piiMasker
+}
+
+// Report lists the bins altered in a response as "namespace.set.bin" names.
+type Report struct {
+	Redacted []string // values replaced by a redaction rule
+	Masked   []string // values in which PII was masked
+}
+
+// Empty returns true if nothing was altered.
+func (r *Report) Empty() bool {
+	return len(r.Redacted) == 0 && len(r.Masked) == 0
+}
+
+// New creates a redactor for the given rules and PII masking settings. It
+// returns nil if there is nothing to redact.
+func New(rules []config.RedactionRule, pii *config.PIIConfig) *Redactor {
+	if len(rules) == 0 && pii == nil {
+		return nil
+	}
+	return &Redactor{rules: rules, pii: newPIIMasker(pii)}
+}
+
+// Matches returns true if the bin should be redacted.
+func (r *Redactor) Matches(namespace, set, bin string) bool {
+	for _, rule := range r.rules {
+		if match(rule.Namespace, namespace) && match(rule.Set, set) && match(rule.Bin, bin) {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether name matches pattern; an empty pattern matches anything.
+func match(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// JSON redacts a JSON document. Bins are recognized in objects with a
+// "bins" field, either a map of bin name to value (records) or a list of
+// objects with "name" and "sample" fields (inferred schemas). The namespace
+// and set of each record are taken from its own fields, falling back to the
+// given defaults. The document is returned unchanged if nothing was altered.
+func (r *Redactor) JSON(data []byte, namespace, set string) ([]byte, *Report, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, nil, err
+	}
+
+	redacted := make(map[string]bool)
+	masked := make(map[string]bool)
+	r.walk(v, namespace, set, redacted, masked)
+
+	report := &Report{Redacted: sortedKeys(redacted), Masked: sortedKeys(masked)}
+	if report.Empty() {
+		return data, report, nil
+	}
+
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, report, nil
+}
+
+func (r *Redactor) walk(v interface{}, namespace, set string, redacted, masked map[string]bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if ns, ok := t["namespace"].(string); ok {
+			namespace = ns
+			// A record without a set field belongs to the null set
+			set, _ = t["set"].(string)
+		}
+
+		switch bins := t["bins"].(type) {
+		case map[string]interface{}:
+			for name, value := range bins {
+				if value, ok := r.filter(value, namespace, set, name, redacted, masked); ok {
+					bins[name] = value
+				}
+			}
+		case []interface{}:
+			for _, item := range bins {
+				bin, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := bin["name"].(string)
+				sample, ok := bin["sample"]
+				if name == "" || !ok {
+					continue
+				}
+				if value, ok := r.filter(sample, namespace, set, name, redacted, masked); ok {
+					bin["sample"] = value
+				}
+			}
+		}
+
+		for key, child := range t {
+			if key != "bins" {
+				r.walk(child, namespace, set, redacted, masked)
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			r.walk(item, namespace, set, redacted, masked)
+		}
+	}
+}
+
+// filter returns the value to send for a bin and whether it differs from
+// the original.
+func (r *Redactor) filter(value interface{}, namespace, set, bin string, redacted, masked map[string]bool) (interface{}, bool) {
+	name := namespace + "." + set + "." + bin
+	if r.Matches(namespace, set, bin) {
+		redacted[name] = true
+		return Placeholder, true
+	}
+	if r.pii != nil {
+		if value, ok := r.pii.mask(value, r.pii.detectors(namespace)); ok {
+			masked[name] = true
+			return value, true
+		}
+	}
+	return value, false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
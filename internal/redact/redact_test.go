@@ -0,0 +1,134 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestNewWithoutRules(t *testing.T) {
+	if New(nil, nil) != nil {
+		t.Error("Expected nil redactor without rules")
+	}
+}
+
+func TestMatches(t *testing.T) {
+	r := New([]config.RedactionRule{
+		{Bin: "ssn"},
+		{Namespace: "ad_platform", Set: "users", Bin: "password_*"},
+	}, nil)
+
+	tests := []struct {
+		namespace, set, bin string
+		want                bool
+	}{
+		{"any", "any", "ssn", true},
+		{"ad_platform", "users", "password_hash", true},
+		{"ad_platform", "campaigns", "password_hash", false},
+		{"ad_platform", "users", "email", false},
+	}
+
+	for _, tt := range tests {
+		if got := r.Matches(tt.namespace, tt.set, tt.bin); got != tt.want {
+			t.Errorf("Matches(%s, %s, %s) = %v, want %v", tt.namespace, tt.set, tt.bin, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	r := New([]config.RedactionRule{
+		{Set: "users", Bin: "ssn"},
+		{Bin: "password_hash"},
+	}, nil)
+
+	tests := []struct {
+		name         string
+		input        string
+		namespace    string
+		set          string
+		wantRedacted []string
+		wantContains []string
+		wantAbsent   []string
+	}{
+		{
+			name:         "single record",
+			input:        `{"key":"u1","namespace":"ad","set":"users","bins":{"ssn":"123-45-6789","age":42}}`,
+			wantRedacted: []string{"ad.users.ssn"},
+			wantContains: []string{Placeholder, `"age":42`},
+			wantAbsent:   []string{"123-45-6789"},
+		},
+		{
+			name:         "record list uses each record's set",
+			input:        `[{"namespace":"ad","set":"users","bins":{"ssn":"1"}},{"namespace":"ad","set":"orders","bins":{"ssn":"2"}}]`,
+			wantRedacted: []string{"ad.users.ssn"},
+			wantContains: []string{`"ssn":"2"`},
+		},
+		{
+			name:         "bins without scope use request scope",
+			input:        `{"bins":{"ssn":"123-45-6789"},"generation":3}`,
+			namespace:    "ad",
+			set:          "users",
+			wantRedacted: []string{"ad.users.ssn"},
+			wantAbsent:   []string{"123-45-6789"},
+		},
+		{
+			name:         "schema samples",
+			input:        `{"namespace":"ad","set":"users","bins":[{"name":"password_hash","sample":"$2a$10$abc"},{"name":"age","sample":42}]}`,
+			wantRedacted: []string{"ad.users.password_hash"},
+			wantAbsent:   []string{"$2a$10$abc"},
+			wantContains: []string{`"sample":42`},
+		},
+		{
+			name:  "nothing to redact",
+			input: `{"namespace":"ad","set":"users","bins":{"age":42}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, report, err := r.JSON([]byte(tt.input), tt.namespace, tt.set)
+			if err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(report.Redacted, tt.wantRedacted) {
+				t.Errorf("Expected redacted %v, got %v", tt.wantRedacted, report.Redacted)
+			}
+
+			var v interface{}
+			if err := json.Unmarshal(out, &v); err != nil {
+				t.Fatalf("Output is not valid JSON: %v", err)
+			}
+			data, _ := json.Marshal(v)
+			compact := string(data)
+
+			for _, s := range tt.wantContains {
+				if !strings.Contains(compact, s) {
+					t.Errorf("Expected output to contain %q, got %s", s, compact)
+				}
+			}
+			for _, s := range tt.wantAbsent {
+				if strings.Contains(compact, s) {
+					t.Errorf("Expected output not to contain %q, got %s", s, compact)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONPreservesLargeIntegers(t *testing.T) {
+	r := New([]config.RedactionRule{{Bin: "ssn"}}, nil)
+
+	out, _, err := r.JSON([]byte(`{"namespace":"ad","bins":{"ssn":"x","id":9007199254740993}}`), "", "")
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	if !strings.Contains(string(out), "9007199254740993") {
+		t.Errorf("Expected integer to be preserved, got %s", out)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -46,6 +47,24 @@ func (r *Registry) List() []ResourceDefinition {
 			Description: "Cluster topology and status",
 			MimeType:    "application/json",
 		},
+		{
+			URI:         "aerospike://nodes",
+			Name:        "Nodes",
+			Description: "Cluster nodes with their key metrics",
+			MimeType:    "application/json",
+		},
+	}
+
+	// Add node resources dynamically
+	if info, err := r.client.GetClusterInfo(context.Background()); err == nil {
+		for _, node := range info.Nodes {
+			resources = append(resources, ResourceDefinition{
+				URI:         fmt.Sprintf("aerospike://nodes/%s/stats", node.Name),
+				Name:        fmt.Sprintf("Node: %s", node.Name),
+				Description: "Node statistics and service configuration",
+				MimeType:    "application/json",
+			})
+		}
 	}
 
 	// Add namespace resources dynamically
@@ -72,6 +91,19 @@ func (r *Registry) List() []ResourceDefinition {
 					MimeType:    "application/json",
 				},
 			)
+
+			indexes, err := r.client.ListIndexes(context.Background(), ns.Name)
+			if err != nil {
+				continue
+			}
+			for _, idx := range indexes {
+				resources = append(resources, ResourceDefinition{
+					URI:         fmt.Sprintf("aerospike://ns/%s/indexes/%s", ns.Name, idx.Name),
+					Name:        fmt.Sprintf("Index: %s.%s", ns.Name, idx.Name),
+					Description: "Secondary index configuration and per-node statistics",
+					MimeType:    "application/json",
+				})
+			}
 		}
 	}
 
@@ -100,6 +132,12 @@ func (r *Registry) Read(ctx context.Context, uri string) (string, string, error)
 	case path == "cluster/info":
 		return r.readClusterInfo(ctx)
 
+	case path == "nodes":
+		return r.readNodes(ctx)
+
+	case strings.HasPrefix(path, "nodes/"):
+		return r.readNodeStats(ctx, path)
+
 	case strings.HasPrefix(path, "ns/"):
 		return r.readNamespaceResource(ctx, path)
 
@@ -109,6 +147,9 @@ func (r *Registry) Read(ctx context.Context, uri string) (string, string, error)
 	case strings.HasPrefix(path, "schema/"):
 		return r.readSchema(ctx, path)
 
+	case strings.HasPrefix(path, "record/"):
+		return r.readRecord(ctx, path)
+
 	default:
 		return "", "", fmt.Errorf("unknown resource: %s", uri)
 	}
@@ -129,9 +170,52 @@ func (r *Registry) readClusterInfo(ctx context.Context) (string, string, error)
 	return string(data), "application/json", nil
 }
 
+// readNodes returns every node's key metrics, without their full
+// statistics.
+func (r *Registry) readNodes(ctx context.Context) (string, string, error) {
+	nodes, err := r.client.GetNodeStats(ctx, "")
+	if err != nil {
+		return "", "", err
+	}
+	for i := range nodes {
+		nodes[i].Stats = nil
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"nodes": nodes,
+	}, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+
+	return string(data), "application/json", nil
+}
+
+// readNodeStats returns a node's statistics and service configuration.
+func (r *Registry) readNodeStats(ctx context.Context, path string) (string, string, error) {
+	// Parse path: nodes/{name}/stats
+	nodeName, ok := strings.CutSuffix(strings.TrimPrefix(path, "nodes/"), "/stats")
+	if !ok || nodeName == "" || strings.Contains(nodeName, "/") {
+		return "", "", fmt.Errorf("invalid node path: %s", path)
+	}
+
+	details, err := r.client.GetNodeDetails(ctx, nodeName)
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+
+	return string(data), "application/json", nil
+}
+
 // readNamespaceResource handles namespace-related resources.
 func (r *Registry) readNamespaceResource(ctx context.Context, path string) (string, string, error) {
-	// Parse path: ns/{name}, ns/{name}/sets, ns/{name}/indexes
+	// Parse path: ns/{name}, ns/{name}/sets, ns/{name}/indexes,
+	// ns/{name}/indexes/{index}
 	parts := strings.Split(strings.TrimPrefix(path, "ns/"), "/")
 	if len(parts) == 0 {
 		return "", "", fmt.Errorf("invalid namespace path: %s", path)
@@ -165,6 +249,20 @@ func (r *Registry) readNamespaceResource(ctx context.Context, path string) (stri
 		return string(data), "application/json", nil
 
 	case "indexes":
+		if len(parts) == 3 && parts[2] != "" {
+			details, err := r.client.GetIndexDetails(ctx, namespace, parts[2])
+			if err != nil {
+				return "", "", err
+			}
+			data, err := json.MarshalIndent(details, "", "  ")
+			if err != nil {
+				return "", "", err
+			}
+			return string(data), "application/json", nil
+		}
+		if len(parts) > 2 {
+			return "", "", fmt.Errorf("invalid namespace path: %s", path)
+		}
 		indexes, err := r.client.ListIndexes(ctx, namespace)
 		if err != nil {
 			return "", "", err
@@ -210,13 +308,13 @@ func (r *Registry) readSchema(ctx context.Context, path string) (string, string,
 	setName := matches[2]
 
 	// Sample a few records to infer schema
-	records, err := r.client.ScanSet(ctx, namespace, setName, nil, 10, 0)
+	page, err := r.client.ScanSet(ctx, namespace, setName, nil, 10, 0, "")
 	if err != nil {
 		return "", "", err
 	}
 
 	// Build schema from sampled records
-	schema := inferSchema(records)
+	schema := aerospike.InferSchema(page.Records)
 
 	data, err := json.MarshalIndent(schema, "", "  ")
 	if err != nil {
@@ -226,94 +324,42 @@ func (r *Registry) readSchema(ctx context.Context, path string) (string, string,
 	return string(data), "application/json", nil
 }
 
-// BinSchema represents inferred schema for a bin.
-type BinSchema struct {
-	Name     string      `json:"name"`
-	Types    []string    `json:"types"`
-	Nullable bool        `json:"nullable"`
-	Sample   interface{} `json:"sample,omitempty"`
-}
-
-// SetSchema represents inferred schema for a set.
-type SetSchema struct {
-	Namespace  string      `json:"namespace"`
-	Set        string      `json:"set"`
-	Bins       []BinSchema `json:"bins"`
-	SampleSize int         `json:"sample_size"`
-}
-
-// inferSchema builds a schema from sampled records.
-func inferSchema(records []*aerospike.Record) *SetSchema {
-	if len(records) == 0 {
-		return &SetSchema{Bins: []BinSchema{}}
-	}
-
-	binTypes := make(map[string]map[string]bool)
-	binSamples := make(map[string]interface{})
-	binNullable := make(map[string]bool)
-
-	for _, rec := range records {
-		if rec == nil {
-			continue
-		}
-		for binName, value := range rec.Bins {
-			if _, ok := binTypes[binName]; !ok {
-				binTypes[binName] = make(map[string]bool)
-				binSamples[binName] = value
-			}
-			if value == nil {
-				binNullable[binName] = true
-			} else {
-				binTypes[binName][getTypeName(value)] = true
-			}
-		}
-	}
-
-	bins := make([]BinSchema, 0, len(binTypes))
-	for name, types := range binTypes {
-		typeList := make([]string, 0, len(types))
-		for t := range types {
-			typeList = append(typeList, t)
-		}
-		bins = append(bins, BinSchema{
-			Name:     name,
-			Types:    typeList,
-			Nullable: binNullable[name],
-			Sample:   binSamples[name],
-		})
+// readRecord returns one record, as get_record does. Key rules apply for
+// the caller's role, and sensitive bins are redacted by the server like
+// any JSON resource.
+func (r *Registry) readRecord(ctx context.Context, path string) (string, string, error) {
+	// Parse path: record/{ns}/{set}/{key}, with an empty set for the null
+	// set; the key is the rest of the path, and each part may be
+	// percent-encoded
+	namespace, setName, key, err := parseRecordPath(path)
+	if err != nil {
+		return "", "", err
 	}
 
-	schema := &SetSchema{
-		Bins:       bins,
-		SampleSize: len(records),
+	record, err := r.client.GetRecord(ctx, namespace, setName, key, nil)
+	if err != nil {
+		return "", "", err
 	}
 
-	if len(records) > 0 && records[0] != nil {
-		schema.Namespace = records[0].Namespace
-		schema.Set = records[0].Set
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", "", err
 	}
 
-	return schema
+	return string(data), "application/json", nil
 }
 
-// getTypeName returns the type name for a value.
-func getTypeName(v interface{}) string {
-	switch v.(type) {
-	case string:
-		return "string"
-	case int, int32, int64:
-		return "integer"
-	case float32, float64:
-		return "float"
-	case bool:
-		return "boolean"
-	case []interface{}:
-		return "list"
-	case map[string]interface{}, map[interface{}]interface{}:
-		return "map"
-	case []byte:
-		return "bytes"
-	default:
-		return fmt.Sprintf("%T", v)
+// parseRecordPath splits a record/{ns}/{set}/{key} path into its decoded
+// parts.
+func parseRecordPath(path string) (namespace, setName, key string, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "record/"), "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid record path: %s (expected record/{ns}/{set}/{key})", path)
+	}
+	for i, part := range parts {
+		if parts[i], err = url.PathUnescape(part); err != nil {
+			return "", "", "", fmt.Errorf("invalid record path: %s: %w", path, err)
+		}
 	}
+	return parts[0], parts[1], parts[2], nil
 }
@@ -4,6 +4,7 @@
 package resources
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -86,6 +87,18 @@ func TestResourceURIParsing(t *testing.T) {
 			isValid:    true,
 			expectPath: "ns/test-ns/sets",
 		},
+		{
+			name:       "nodes",
+			uri:        "aerospike://nodes",
+			isValid:    true,
+			expectPath: "nodes",
+		},
+		{
+			name:       "node stats",
+			uri:        "aerospike://nodes/BB9020011AC4202/stats",
+			isValid:    true,
+			expectPath: "nodes/BB9020011AC4202/stats",
+		},
 		{
 			name:       "udfs",
 			uri:        "aerospike://udfs",
@@ -165,3 +178,41 @@ func TestResourceURIScheme(t *testing.T) {
 		}
 	}
 }
+
+func TestReadNodeStatsPath(t *testing.T) {
+	r := &Registry{config: &config.Config{}}
+
+	for _, uri := range []string{"aerospike://nodes/", "aerospike://nodes/BB9/config", "aerospike://nodes//stats", "aerospike://nodes/a/b/stats"} {
+		if _, _, err := r.Read(context.Background(), uri); err == nil {
+			t.Errorf("Expected error for %s", uri)
+		}
+	}
+}
+
+func TestParseRecordPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    [3]string
+		wantErr bool
+	}{
+		{"record/test/users/user1", [3]string{"test", "users", "user1"}, false},
+		{"record/test//user1", [3]string{"test", "", "user1"}, false},
+		{"record/test/users/orders/2024/1", [3]string{"test", "users", "orders/2024/1"}, false},
+		{"record/test/users/a%20b%3F", [3]string{"test", "users", "a b?"}, false},
+		{"record/test/users/", [3]string{}, true},
+		{"record/test/users", [3]string{}, true},
+		{"record//users/k", [3]string{}, true},
+		{"record/test/users/%zz", [3]string{}, true},
+	}
+
+	for _, tt := range tests {
+		ns, set, key, err := parseRecordPath(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Expected error %v, got %v", tt.path, tt.wantErr, err)
+			continue
+		}
+		if got := [3]string{ns, set, key}; !tt.wantErr && got != tt.want {
+			t.Errorf("%s: Expected %q, got %q", tt.path, tt.want, got)
+		}
+	}
+}
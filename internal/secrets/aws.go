@@ -0,0 +1,211 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// AWSSecretsManager resolves "aws-sm:<secret-id>[#field]" references, where
+// secret-id is a secret name or ARN. Requests are signed with Signature
+// Version 4 using credentials from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// and AWS_SESSION_TOKEN.
+type AWSSecretsManager struct {
+	cfg        config.AWSSecretsConfig
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewAWSSecretsManager creates an AWS Secrets Manager provider.
+func NewAWSSecretsManager(cfg config.AWSSecretsConfig) *AWSSecretsManager {
+	return &AWSSecretsManager{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// Resolve returns the secret string, or the named field of a JSON secret.
+func (a *AWSSecretsManager) Resolve(ctx context.Context, ref string) (string, error) {
+	secretID, field, _ := strings.Cut(ref, "#")
+
+	region := a.region(secretID)
+	if region == "" {
+		return "", fmt.Errorf("no region for %s: set secrets.aws.region or AWS_REGION", secretID)
+	}
+
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return "", fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+
+	endpoint := a.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", fmt.Errorf("encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	signV4(req, body, accessKey, secretKey, region, "secretsmanager", a.now())
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("GetSecretValue %s: %w", secretID, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &errResp)
+		return "", fmt.Errorf("GetSecretValue %s: %s %s %s", secretID, resp.Status, errResp.Type, errResp.Message)
+	}
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+		SecretBinary string `json:"SecretBinary"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+
+	secret := out.SecretString
+	if secret == "" && out.SecretBinary != "" {
+		data, err := base64.StdEncoding.DecodeString(out.SecretBinary)
+		if err != nil {
+			return "", fmt.Errorf("decoding SecretBinary: %w", err)
+		}
+		secret = string(data)
+	}
+	return jsonField(secret, field)
+}
+
+// region returns the region for a secret: from its ARN, the config, or the
+// standard environment variables.
+func (a *AWSSecretsManager) region(secretID string) string {
+	// arn:aws:secretsmanager:<region>:<account>:secret:<name>
+	if parts := strings.Split(secretID, ":"); len(parts) > 3 && parts[0] == "arn" {
+		return parts[3]
+	}
+	if a.cfg.Region != "" {
+		return a.cfg.Region
+	}
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
+
+// signV4 adds AWS Signature Version 4 headers to req.
+func signV4(req *http.Request, body []byte, accessKey, secretKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	// Canonical headers: every header set so far, lowercased and sorted
+	names := []string{"host"}
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := req.URL.Host
+		if name != "host" {
+			value = strings.TrimSpace(req.Header.Get(name))
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hexSHA256(body),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := signingKey(secretKey, date, region, service)
+	signature := hex.EncodeToString(hmacSHA256(key, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// signingKey derives the SigV4 signing key for a date, region, and service.
+func signingKey(secretKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), []byte(date))
+	key = hmacSHA256(key, []byte(region))
+	key = hmacSHA256(key, []byte(service))
+	return hmacSHA256(key, []byte("aws4_request"))
+}
+
+func canonicalQuery(values url.Values) string {
+	// url.Values.Encode sorts by key and escapes spaces as '+'; SigV4 wants '%20'
+	return strings.ReplaceAll(values.Encode(), "+", "%20")
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
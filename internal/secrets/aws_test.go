@@ -0,0 +1,87 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestSigningKey(t *testing.T) {
+	// Example from the AWS Signature Version 4 documentation
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	want := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+	if got := hex.EncodeToString(key); got != want {
+		t.Errorf("Expected signing key %s, got %s", want, got)
+	}
+}
+
+func TestAWSRegion(t *testing.T) {
+	t.Setenv("AWS_REGION", "eu-west-1")
+
+	a := NewAWSSecretsManager(config.AWSSecretsConfig{})
+	if got := a.region("arn:aws:secretsmanager:us-east-2:123456789012:secret:aerospike-AbCdEf"); got != "us-east-2" {
+		t.Errorf("Expected region from ARN, got %s", got)
+	}
+	if got := a.region("aerospike"); got != "eu-west-1" {
+		t.Errorf("Expected region from environment, got %s", got)
+	}
+
+	a = NewAWSSecretsManager(config.AWSSecretsConfig{Region: "ap-south-1"})
+	if got := a.region("aerospike"); got != "ap-south-1" {
+		t.Errorf("Expected configured region, got %s", got)
+	}
+}
+
+func TestAWSResolve(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			t.Errorf("Unexpected target %q", r.Header.Get("X-Amz-Target"))
+		}
+		authz := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authz, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240115/us-east-1/secretsmanager/aws4_request") {
+			t.Errorf("Unexpected Authorization header %q", authz)
+		}
+		if !strings.Contains(authz, "x-amz-security-token") {
+			t.Errorf("Expected session token to be signed, got %q", authz)
+		}
+
+		var req struct{ SecretId string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.SecretId != "prod/aerospike" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"SecretString":"{\"password\":\"s3cret\"}"}`))
+	}))
+	defer srv.Close()
+
+	a := NewAWSSecretsManager(config.AWSSecretsConfig{Region: "us-east-1", Endpoint: srv.URL})
+	a.now = func() time.Time { return time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC) }
+
+	got, err := a.Resolve(context.Background(), "prod/aerospike#password")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("Expected 's3cret', got '%s'", got)
+	}
+
+	if _, err := a.Resolve(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for missing secret")
+	}
+}
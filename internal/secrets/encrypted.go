@@ -0,0 +1,151 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// DefaultKeyEnv is the environment variable holding the config encryption
+// key when secrets.encryption.key_env is not set.
+const DefaultKeyEnv = "AEROSPIKE_MCP_CONFIG_KEY"
+
+// encPrefix marks an encrypted config value.
+const encPrefix = "enc:"
+
+// Decrypter resolves "enc:<base64>" values: a random 12-byte nonce followed
+// by the AES-256-GCM ciphertext of the secret. The key is read on first use,
+// from the environment or from a secret reference resolved by the other
+// providers.
+type Decrypter struct {
+	cfg      config.EncryptionConfig
+	resolver *Resolver
+
+	mu   sync.Mutex
+	aead cipher.AEAD
+}
+
+// NewDecrypter creates a provider for encrypted values. resolver resolves
+// cfg.KeyRef, and may be nil if no key reference is configured.
+func NewDecrypter(cfg config.EncryptionConfig, resolver *Resolver) *Decrypter {
+	return &Decrypter{cfg: cfg, resolver: resolver}
+}
+
+// Resolve decrypts ref, the base64 text after "enc:".
+func (d *Decrypter) Resolve(ctx context.Context, ref string) (string, error) {
+	aead, err := d.cipher(ctx)
+	if err != nil {
+		return "", err
+	}
+	return decrypt(aead, ref)
+}
+
+// cipher returns the AEAD for the configured key, loading the key the first
+// time. A failed load is retried on the next call.
+func (d *Decrypter) cipher(ctx context.Context) (cipher.AEAD, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.aead != nil {
+		return d.aead, nil
+	}
+
+	var encoded string
+	if d.cfg.KeyRef != "" {
+		if strings.HasPrefix(d.cfg.KeyRef, encPrefix) || d.resolver == nil || !d.resolver.IsRef(d.cfg.KeyRef) {
+			return nil, fmt.Errorf("secrets.encryption.key_ref is not a secret reference: %q", d.cfg.KeyRef)
+		}
+		var err error
+		if encoded, err = d.resolver.Resolve(ctx, d.cfg.KeyRef); err != nil {
+			return nil, fmt.Errorf("reading encryption key: %w", err)
+		}
+	} else {
+		env := d.cfg.KeyEnv
+		if env == "" {
+			env = DefaultKeyEnv
+		}
+		if encoded = os.Getenv(env); encoded == "" {
+			return nil, fmt.Errorf("encryption key not set: set %s or secrets.encryption.key_ref", env)
+		}
+	}
+
+	key, err := ParseKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if d.aead, err = newAEAD(key); err != nil {
+		return nil, err
+	}
+	return d.aead, nil
+}
+
+// ParseKey decodes a base64 AES-256 key.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("encryption key is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// GenerateKey returns a new random key, base64 encoded.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt encrypts plaintext with key and returns the "enc:..." value to
+// put in the config file.
+func Encrypt(key []byte, plaintext string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens a base64 nonce and ciphertext produced by Encrypt.
+func decrypt(aead cipher.AEAD, encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("encrypted value is not valid base64: %w", err)
+	}
+	if len(sealed) < aead.NonceSize()+aead.Overhead() {
+		return "", fmt.Errorf("encrypted value is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypting value: wrong key or corrupted ciphertext")
+	}
+	return string(plaintext), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
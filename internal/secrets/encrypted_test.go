@@ -0,0 +1,100 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestEncryptedValues(t *testing.T) {
+	encoded, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	key, err := ParseKey(encoded)
+	if err != nil {
+		t.Fatalf("ParseKey() error = %v", err)
+	}
+	value, err := Encrypt(key, "s3cret")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(value, "enc:") {
+		t.Fatalf("Expected an enc: value, got %q", value)
+	}
+
+	t.Setenv("TEST_CONFIG_KEY", encoded)
+	cfg := config.DefaultConfig()
+	cfg.Password = value
+	cfg.Auth.APIKeys = []config.APIKey{{Name: "ci", Key: value}}
+	cfg.Secrets.Encryption = &config.EncryptionConfig{KeyEnv: "TEST_CONFIG_KEY"}
+
+	resolved, err := FindRefs(cfg, NewResolver(cfg.Secrets, nil)).Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	resolved.Apply(cfg)
+	if cfg.Password != "s3cret" || cfg.Auth.APIKeys[0].Key != "s3cret" {
+		t.Errorf("Expected decrypted values, got password %q, key %q", cfg.Password, cfg.Auth.APIKeys[0].Key)
+	}
+}
+
+func TestEncryptedValueErrors(t *testing.T) {
+	encoded, _ := GenerateKey()
+	key, _ := ParseKey(encoded)
+	value, _ := Encrypt(key, "s3cret")
+	ref := strings.TrimPrefix(value, "enc:")
+	other, _ := GenerateKey()
+
+	tests := []struct {
+		name string
+		env  string
+		ref  string
+		want string
+	}{
+		{"key not set", "", ref, "encryption key not set"},
+		{"short key", "c2hvcnQ=", ref, "must be 32 bytes"},
+		{"wrong key", other, ref, "wrong key"},
+		{"not base64", encoded, "!!!", "not valid base64"},
+		{"truncated", encoded, "YWJj", "too short"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DefaultKeyEnv, tt.env)
+			_, err := NewDecrypter(config.EncryptionConfig{}, nil).Resolve(context.Background(), tt.ref)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestEncryptionKeyRef(t *testing.T) {
+	encoded, _ := GenerateKey()
+	key, _ := ParseKey(encoded)
+	value, _ := Encrypt(key, "s3cret")
+
+	// The key itself comes from another provider, such as a KMS-backed store
+	r := &Resolver{providers: make(map[string]Provider)}
+	r.Register("test", mapProvider{"config-key": encoded})
+	r.Register("enc", NewDecrypter(config.EncryptionConfig{KeyRef: "test:config-key"}, r))
+
+	got, err := r.Resolve(context.Background(), value)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("Expected 's3cret', got %q", got)
+	}
+
+	r.Register("enc", NewDecrypter(config.EncryptionConfig{KeyRef: "plain-key"}, r))
+	if _, err := r.Resolve(context.Background(), value); err == nil {
+		t.Error("Expected error for a key_ref that is not a secret reference")
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// CredentialFiles watches the files cluster credentials are read from:
+// password_file, and the TLS CA, certificate, and key files given as paths.
+// Files rotated in place, for example by a Kubernetes secret mount or
+// cert-manager, are picked up without a restart.
+type CredentialFiles struct {
+	password string   // password_file
+	tls      []string // TLS files read on each connect
+	sums     map[string][sha256.Size]byte
+}
+
+// FindFiles collects the credential files in cfg and records their current
+// contents. Call it after secret references have been applied, so TLS
+// settings that hold a reference rather than a path are skipped.
+func FindFiles(cfg *config.Config) *CredentialFiles {
+	f := &CredentialFiles{password: cfg.PasswordFile}
+
+	_, _, tls := cfg.ClusterCredentials()
+	if tls.Enabled {
+		if tls.CAPEM == nil && tls.CAFile != "" {
+			f.tls = append(f.tls, tls.CAFile)
+		}
+		if (tls.CertPEM == nil || tls.KeyPEM == nil) && tls.CertFile != "" && tls.KeyFile != "" {
+			f.tls = append(f.tls, tls.CertFile, tls.KeyFile)
+		}
+	}
+
+	f.sums = f.checksums()
+	return f
+}
+
+// Empty returns true if there are no files to watch.
+func (f *CredentialFiles) Empty() bool {
+	return f.password == "" && len(f.tls) == 0
+}
+
+// paths returns every watched file.
+func (f *CredentialFiles) paths() []string {
+	if f.password == "" {
+		return f.tls
+	}
+	return append([]string{f.password}, f.tls...)
+}
+
+// checksums returns the SHA-256 of each readable file.
+func (f *CredentialFiles) checksums() map[string][sha256.Size]byte {
+	sums := make(map[string][sha256.Size]byte)
+	for _, path := range f.paths() {
+		if data, err := os.ReadFile(path); err == nil {
+			sums[path] = sha256.Sum256(data)
+		}
+	}
+	return sums
+}
+
+// Changed checks the files again, and returns whether any changed since the
+// last check and the current password if password_file is watched. A file
+// that can't be read, as happens briefly while some tools replace it, is
+// treated as unchanged until it can be.
+func (f *CredentialFiles) Changed() (bool, *Credentials, error) {
+	sums := f.checksums()
+	changed := false
+	for path, sum := range sums {
+		if f.sums[path] != sum {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil, nil
+	}
+
+	// The change is recorded only once the password is read, so a failed
+	// read is retried on the next check
+	creds := &Credentials{}
+	if f.password != "" {
+		password, err := config.ReadPasswordFile(f.password)
+		if err != nil {
+			return false, nil, err
+		}
+		creds.Password = password
+	}
+	for path, sum := range sums {
+		f.sums[path] = sum
+	}
+	return true, creds, nil
+}
+
+// Watch checks the files every interval until ctx is cancelled, calling
+// onChange with the current password, if password_file is watched, when any
+// file changes. The caller applies the password and reconnects, which also
+// re-reads the TLS files.
+func (f *CredentialFiles) Watch(ctx context.Context, interval time.Duration, onChange func(*Credentials)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, creds, err := f.Changed()
+		if err != nil {
+			slog.WarnContext(ctx, "Reading rotated credential file failed", "error", err)
+			continue
+		}
+		if changed {
+			slog.InfoContext(ctx, "Credential files changed")
+			onChange(creds)
+		}
+	}
+}
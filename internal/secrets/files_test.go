@@ -0,0 +1,78 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestCredentialFiles(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	for path, content := range map[string]string{
+		passwordFile: "first\n",
+		certFile:     "CERT 1",
+		keyFile:      "KEY 1",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.PasswordFile = passwordFile
+	cfg.TLS = config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile}
+
+	files := FindFiles(cfg)
+	if files.Empty() {
+		t.Fatal("Expected credential files to be found")
+	}
+	if changed, _, err := files.Changed(); changed || err != nil {
+		t.Fatalf("Expected no change, got %v, %v", changed, err)
+	}
+
+	// A rotated password is returned
+	if err := os.WriteFile(passwordFile, []byte("second\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	changed, creds, err := files.Changed()
+	if err != nil || !changed {
+		t.Fatalf("Expected a change, got %v, %v", changed, err)
+	}
+	if creds.Password != "second" {
+		t.Errorf("Expected password 'second', got %q", creds.Password)
+	}
+	if changed, _, _ := files.Changed(); changed {
+		t.Error("Expected the change to be reported once")
+	}
+
+	// A rotated certificate is reported too, so the client reconnects
+	if err := os.WriteFile(keyFile, []byte("KEY 2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _, _ := files.Changed(); !changed {
+		t.Error("Expected a rotated key file to be reported")
+	}
+}
+
+func TestCredentialFilesSkipsPEM(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.TLS = config.TLSConfig{
+		Enabled:  true,
+		CertFile: "vault:secret/data/tls#cert",
+		KeyFile:  "vault:secret/data/tls#key",
+		CertPEM:  []byte("CERT"),
+		KeyPEM:   []byte("KEY"),
+	}
+
+	if files := FindFiles(cfg); !files.Empty() {
+		t.Errorf("Expected TLS material from a secret store not to be watched, got %v", files.paths())
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+const (
+	gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+
+	// gcpMetadataTokenURL returns access tokens for the instance's service account.
+	gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCPSecretManager resolves "gcp-sm:<secret>[#field]" references, where
+// secret is "projects/<project>/secrets/<name>[/versions/<version>]" or a
+// bare name in the configured project. The latest version is used unless one
+// is given. Access tokens come from the configured environment variable or
+// the GCE metadata server.
+type GCPSecretManager struct {
+	cfg        config.GCPSecretsConfig
+	httpClient *http.Client
+	tokenURL   string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCPSecretManager creates a GCP Secret Manager provider.
+func NewGCPSecretManager(cfg config.GCPSecretsConfig) *GCPSecretManager {
+	return &GCPSecretManager{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		tokenURL:   gcpMetadataTokenURL,
+	}
+}
+
+// Resolve returns the secret payload, or the named field of a JSON secret.
+func (g *GCPSecretManager) Resolve(ctx context.Context, ref string) (string, error) {
+	name, field, _ := strings.Cut(ref, "#")
+
+	if !strings.HasPrefix(name, "projects/") {
+		if g.cfg.Project == "" {
+			return "", fmt.Errorf("secret %s has no project: use projects/<project>/secrets/<name> or set secrets.gcp.project", name)
+		}
+		name = "projects/" + g.cfg.Project + "/secrets/" + name
+	}
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := g.cfg.Endpoint
+	if endpoint == "" {
+		endpoint = gcpSecretManagerEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/v1/"+name+":access", nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := g.do(req, &out); err != nil {
+		return "", fmt.Errorf("accessing %s: %w", name, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("decoding payload of %s: %w", name, err)
+	}
+	return jsonField(string(data), field)
+}
+
+// accessToken returns an OAuth access token, caching metadata server tokens
+// until shortly before they expire.
+func (g *GCPSecretManager) accessToken(ctx context.Context) (string, error) {
+	if g.cfg.AccessTokenEnv != "" {
+		token := os.Getenv(g.cfg.AccessTokenEnv)
+		if token == "" {
+			return "", fmt.Errorf("%s is not set", g.cfg.AccessTokenEnv)
+		}
+		return token, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.token != "" && time.Now().Before(g.tokenExpiry) {
+		return g.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.tokenURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating token request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := g.do(req, &out); err != nil {
+		return "", fmt.Errorf("fetching metadata server token: %w", err)
+	}
+
+	g.token = out.AccessToken
+	g.tokenExpiry = time.Now().Add(time.Duration(out.ExpiresIn)*time.Second - time.Minute)
+	return g.token, nil
+}
+
+// do performs a request and decodes the JSON response into out.
+func (g *GCPSecretManager) do(req *http.Request, out interface{}) error {
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &errResp)
+		return fmt.Errorf("%s %s", resp.Status, errResp.Error.Message)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func TestGCPResolve(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte("s3cret"))
+
+	var tokenRequests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		tokenRequests++
+		_, _ = w.Write([]byte(`{"access_token":"metadata-token","expires_in":3600}`))
+	})
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer metadata-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/projects/ads/secrets/aerospike/versions/latest:access" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"message":"secret not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"payload":{"data":"` + payload + `"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	g := NewGCPSecretManager(config.GCPSecretsConfig{Project: "ads", Endpoint: srv.URL})
+	g.tokenURL = srv.URL + "/token"
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"aerospike", "s3cret", false},
+		{"projects/ads/secrets/aerospike", "s3cret", false},
+		{"projects/other/secrets/aerospike", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := g.Resolve(context.Background(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("Expected metadata token to be cached, got %d requests", tokenRequests)
+	}
+}
+
+func TestGCPResolveRequiresProject(t *testing.T) {
+	g := NewGCPSecretManager(config.GCPSecretsConfig{AccessTokenEnv: "TEST_GCP_TOKEN"})
+	if _, err := g.Resolve(context.Background(), "aerospike"); err == nil {
+		t.Error("Expected error for reference without a project")
+	}
+}
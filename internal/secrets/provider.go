@@ -0,0 +1,251 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// Provider resolves references to secrets held in an external store.
+type Provider interface {
+	// Resolve returns the secret value for ref, which excludes the scheme.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// Resolver dispatches secret references of the form "<scheme>:<ref>" to the
+// provider registered for the scheme.
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// NewResolver creates a resolver with the providers enabled by cfg. AWS
+// Secrets Manager ("aws-sm") and GCP Secret Manager ("gcp-sm") are always
+// available, as are values encrypted with the config key ("enc"); "vault" is
+// available when a Vault client is given.
+func NewResolver(cfg config.SecretsConfig, vault *VaultClient) *Resolver {
+	r := &Resolver{providers: make(map[string]Provider)}
+
+	aws := config.AWSSecretsConfig{}
+	if cfg.AWS != nil {
+		aws = *cfg.AWS
+	}
+	r.Register("aws-sm", NewAWSSecretsManager(aws))
+
+	gcp := config.GCPSecretsConfig{}
+	if cfg.GCP != nil {
+		gcp = *cfg.GCP
+	}
+	r.Register("gcp-sm", NewGCPSecretManager(gcp))
+
+	if vault != nil {
+		r.Register("vault", vault)
+	}
+
+	enc := config.EncryptionConfig{}
+	if cfg.Encryption != nil {
+		enc = *cfg.Encryption
+	}
+	r.Register("enc", NewDecrypter(enc, r))
+	return r
+}
+
+// Register adds a provider for the given scheme.
+func (r *Resolver) Register(scheme string, p Provider) {
+	r.providers[scheme] = p
+}
+
+// IsRef returns true if value is a reference to a registered provider.
+func (r *Resolver) IsRef(value string) bool {
+	scheme, _, ok := strings.Cut(value, ":")
+	if !ok {
+		return false
+	}
+	_, ok = r.providers[scheme]
+	return ok
+}
+
+// Resolve returns the secret value for a reference.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	p, found := r.providers[scheme]
+	if !ok || !found {
+		return "", fmt.Errorf("not a secret reference: %q", value)
+	}
+
+	secret, err := p.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s secret: %w", scheme, err)
+	}
+	return secret, nil
+}
+
+// jsonField returns the named field of a JSON object secret, or the secret
+// itself if field is empty.
+func jsonField(secret, field string) (string, error) {
+	if field == "" {
+		return secret, nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &obj); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot select field %q", field)
+	}
+	value, ok := obj[field].(string)
+	if !ok {
+		return "", fmt.Errorf("secret has no string field %q", field)
+	}
+	return value, nil
+}
+
+// ConfigRefs records the secret references found in a configuration so they
+// can be resolved at load time and re-resolved when secrets rotate.
+type ConfigRefs struct {
+	resolver *Resolver
+
+	password string
+	apiKeys  map[int]string // index in auth.api_keys -> reference
+	caFile   string
+	certFile string
+	keyFile  string
+
+	apiKeyNames []config.APIKey // API keys with their configured values
+}
+
+// Resolved holds the current values of a configuration's secret references.
+type Resolved struct {
+	Credentials Credentials
+	APIKeys     []config.APIKey
+}
+
+// FindRefs collects the secret references in cfg: the cluster password,
+// API key values, and TLS CA, certificate, and key files.
+func FindRefs(cfg *config.Config, resolver *Resolver) *ConfigRefs {
+	refs := &ConfigRefs{resolver: resolver, apiKeys: make(map[int]string)}
+
+	if resolver.IsRef(cfg.Password) {
+		refs.password = cfg.Password
+	}
+	for i, key := range cfg.Auth.APIKeys {
+		if resolver.IsRef(key.Key) {
+			refs.apiKeys[i] = key.Key
+		}
+	}
+	refs.apiKeyNames = append(refs.apiKeyNames, cfg.Auth.APIKeys...)
+	if resolver.IsRef(cfg.TLS.CAFile) {
+		refs.caFile = cfg.TLS.CAFile
+	}
+	if resolver.IsRef(cfg.TLS.CertFile) {
+		refs.certFile = cfg.TLS.CertFile
+	}
+	if resolver.IsRef(cfg.TLS.KeyFile) {
+		refs.keyFile = cfg.TLS.KeyFile
+	}
+	return refs
+}
+
+// Empty returns true if the configuration contains no secret references.
+func (c *ConfigRefs) Empty() bool {
+	return c.password == "" && len(c.apiKeys) == 0 &&
+		c.caFile == "" && c.certFile == "" && c.keyFile == ""
+}
+
+// Resolve fetches the current value of every reference.
+func (c *ConfigRefs) Resolve(ctx context.Context) (*Resolved, error) {
+	resolved := &Resolved{}
+
+	var err error
+	if c.password != "" {
+		if resolved.Credentials.Password, err = c.resolver.Resolve(ctx, c.password); err != nil {
+			return nil, fmt.Errorf("password: %w", err)
+		}
+	}
+
+	if len(c.apiKeys) > 0 {
+		resolved.APIKeys = make([]config.APIKey, len(c.apiKeyNames))
+		copy(resolved.APIKeys, c.apiKeyNames)
+		for i, ref := range c.apiKeys {
+			if resolved.APIKeys[i].Key, err = c.resolver.Resolve(ctx, ref); err != nil {
+				return nil, fmt.Errorf("auth.api_keys[%d]: %w", i, err)
+			}
+		}
+	}
+
+	for _, pem := range []struct {
+		name string
+		ref  string
+		dst  *[]byte
+	}{
+		{"tls.ca_file", c.caFile, &resolved.Credentials.CAPEM},
+		{"tls.cert_file", c.certFile, &resolved.Credentials.CertPEM},
+		{"tls.key_file", c.keyFile, &resolved.Credentials.KeyPEM},
+	} {
+		if pem.ref == "" {
+			continue
+		}
+		value, err := c.resolver.Resolve(ctx, pem.ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pem.name, err)
+		}
+		*pem.dst = []byte(value)
+	}
+
+	return resolved, nil
+}
+
+// Apply copies resolved values into cfg.
+func (r *Resolved) Apply(cfg *config.Config) {
+	r.Credentials.Apply(cfg)
+	if r.APIKeys != nil {
+		cfg.UpdateCredentials(func(cfg *config.Config) {
+			cfg.Auth.APIKeys = r.APIKeys
+		})
+	}
+}
+
+// Watch re-resolves the references every interval until ctx is cancelled,
+// calling onChange when any value differs from current. Errors are logged
+// and retried on the next cycle.
+func (c *ConfigRefs) Watch(ctx context.Context, current *Resolved, interval time.Duration, onChange func(*Resolved)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		resolved, err := c.Resolve(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "Secret refresh failed", "error", err)
+			continue
+		}
+
+		if !resolved.Credentials.Equal(&current.Credentials) || !equalKeys(resolved.APIKeys, current.APIKeys) {
+			current = resolved
+			onChange(resolved)
+		}
+	}
+}
+
+// equalKeys reports whether two API key lists are identical.
+func equalKeys(a, b []config.APIKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
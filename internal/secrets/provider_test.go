@@ -0,0 +1,180 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// mapProvider resolves references from a fixed map.
+type mapProvider map[string]string
+
+func (m mapProvider) Resolve(_ context.Context, ref string) (string, error) {
+	value, ok := m[ref]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", ref)
+	}
+	return value, nil
+}
+
+func TestResolverIsRef(t *testing.T) {
+	r := NewResolver(config.SecretsConfig{}, nil)
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"aws-sm:prod/aerospike#password", true},
+		{"gcp-sm:aerospike-password", true},
+		{"vault:secret/data/aerospike#password", false}, // no Vault client configured
+		{"enc:c2VjcmV0", true},
+		{"plain-password", false},
+		{"/etc/ssl/ca.pem", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := r.IsRef(tt.value); got != tt.want {
+				t.Errorf("IsRef(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		field   string
+		want    string
+		wantErr bool
+	}{
+		{"whole secret", "s3cret", "", "s3cret", false},
+		{"field", `{"user":"mcp","password":"s3cret"}`, "password", "s3cret", false},
+		{"missing field", `{"user":"mcp"}`, "password", "", true},
+		{"not json", "s3cret", "password", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonField(tt.secret, tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("jsonField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfigRefs(t *testing.T) {
+	provider := mapProvider{
+		"db-password": "s3cret",
+		"ci-key":      "key-value",
+		"tls-key":     "KEY PEM",
+	}
+	r := &Resolver{providers: make(map[string]Provider)}
+	r.Register("test", provider)
+
+	cfg := config.DefaultConfig()
+	cfg.Password = "test:db-password"
+	cfg.TLS.CAFile = "/etc/ssl/ca.pem"
+	cfg.TLS.KeyFile = "test:tls-key"
+	cfg.Auth.APIKeys = []config.APIKey{
+		{Name: "static", Key: "static-key"},
+		{Name: "ci", Key: "test:ci-key"},
+	}
+
+	refs := FindRefs(cfg, r)
+	if refs.Empty() {
+		t.Fatal("Expected references to be found")
+	}
+
+	resolved, err := refs.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	resolved.Apply(cfg)
+
+	if cfg.Password != "s3cret" {
+		t.Errorf("Expected password 's3cret', got '%s'", cfg.Password)
+	}
+	if string(cfg.TLS.KeyPEM) != "KEY PEM" {
+		t.Errorf("Expected TLS key from secret, got %q", cfg.TLS.KeyPEM)
+	}
+	if cfg.TLS.CAPEM != nil {
+		t.Errorf("Expected CA file to be left alone, got %q", cfg.TLS.CAPEM)
+	}
+	if cfg.Auth.APIKeys[0].Key != "static-key" || cfg.Auth.APIKeys[1].Key != "key-value" {
+		t.Errorf("Unexpected API keys: %+v", cfg.Auth.APIKeys)
+	}
+
+	// Rotation is picked up by re-resolving the original references
+	provider["ci-key"] = "rotated"
+	rotated, err := refs.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if equalKeys(rotated.APIKeys, resolved.APIKeys) {
+		t.Error("Expected rotated API key to differ")
+	}
+	if rotated.APIKeys[1].Key != "rotated" {
+		t.Errorf("Expected rotated key, got '%s'", rotated.APIKeys[1].Key)
+	}
+}
+
+func TestConfigRefsEmpty(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Password = "plain"
+
+	if refs := FindRefs(cfg, NewResolver(config.SecretsConfig{}, nil)); !refs.Empty() {
+		t.Error("Expected no references in plain config")
+	}
+}
+
+func TestConfigRefsResolveError(t *testing.T) {
+	r := &Resolver{providers: make(map[string]Provider)}
+	r.Register("test", mapProvider{})
+
+	cfg := config.DefaultConfig()
+	cfg.Password = "test:missing"
+
+	if _, err := FindRefs(cfg, r).Resolve(context.Background()); err == nil {
+		t.Error("Expected error for unresolvable reference")
+	}
+}
+
+func TestResolvedApplyConcurrent(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	// Rotation applies credentials while reconnects read them; run with
+	// -race to check they share cfg's lock
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			r := &Resolved{Credentials: Credentials{User: "svc", Password: fmt.Sprintf("pass-%d", i)}}
+			r.Apply(cfg)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			cfg.ClusterCredentials()
+		}
+	}()
+	wg.Wait()
+
+	if user, password, _ := cfg.ClusterCredentials(); user != "svc" || password != "pass-99" {
+		t.Errorf("Expected the last credentials applied, got %s/%s", user, password)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package secrets resolves cluster credentials and TLS key material from
+// external secret stores.
+package secrets
+
+import (
+	"bytes"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// Credentials holds secret material used to connect to the cluster. Empty
+// fields were not provided by the secret store.
+type Credentials struct {
+	User     string
+	Password string
+	CAPEM    []byte
+	CertPEM  []byte
+	KeyPEM   []byte
+}
+
+// Apply copies the non-empty credentials into cfg. It holds cfg's lock, so
+// a reconnect running at the same time sees either the old or the new
+// credentials.
+func (c *Credentials) Apply(cfg *config.Config) {
+	cfg.UpdateCredentials(func(cfg *config.Config) {
+		if c.User != "" {
+			cfg.User = c.User
+		}
+		if c.Password != "" {
+			cfg.Password = c.Password
+		}
+		if c.CAPEM != nil {
+			cfg.TLS.CAPEM = c.CAPEM
+		}
+		if c.CertPEM != nil {
+			cfg.TLS.CertPEM = c.CertPEM
+		}
+		if c.KeyPEM != nil {
+			cfg.TLS.KeyPEM = c.KeyPEM
+		}
+	})
+}
+
+// Equal reports whether c and other hold the same credentials.
+func (c *Credentials) Equal(other *Credentials) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.User == other.User &&
+		c.Password == other.Password &&
+		bytes.Equal(c.CAPEM, other.CAPEM) &&
+		bytes.Equal(c.CertPEM, other.CertPEM) &&
+		bytes.Equal(c.KeyPEM, other.KeyPEM)
+}
@@ -0,0 +1,224 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+// defaultRenewInterval is used when neither the config nor the secret's
+// lease specifies how often to re-read it.
+const defaultRenewInterval = 5 * time.Minute
+
+// VaultClient reads credentials from a HashiCorp Vault KV secret.
+type VaultClient struct {
+	cfg        config.VaultConfig
+	token      string
+	httpClient *http.Client
+}
+
+// NewVaultClient creates a Vault client. The token is taken from the config,
+// then the configured token_env variable, then VAULT_TOKEN.
+func NewVaultClient(cfg *config.VaultConfig) (*VaultClient, error) {
+	token := cfg.Token
+	if token == "" && cfg.TokenEnv != "" {
+		token = os.Getenv(cfg.TokenEnv)
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("vault token is required")
+	}
+
+	return &VaultClient{
+		cfg:        *cfg,
+		token:      token,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// vaultResponse is the envelope of a Vault read.
+type vaultResponse struct {
+	LeaseDuration int                    `json:"lease_duration"`
+	Data          map[string]interface{} `json:"data"`
+	Errors        []string               `json:"errors"`
+}
+
+// read returns the fields of the secret at path and its lease duration.
+func (v *VaultClient) read(ctx context.Context, path string) (map[string]interface{}, time.Duration, error) {
+	var resp vaultResponse
+	if err := v.do(ctx, http.MethodGet, strings.TrimPrefix(path, "/"), &resp); err != nil {
+		return nil, 0, err
+	}
+
+	data := resp.Data
+	// KV v2 nests the secret under data.data alongside its metadata
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	return data, time.Duration(resp.LeaseDuration) * time.Second, nil
+}
+
+// Resolve returns a field of a Vault secret. The reference has the form
+// "<path>#<field>".
+func (v *VaultClient) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || field == "" {
+		return "", fmt.Errorf("vault reference %q must name a field (path#field)", ref)
+	}
+
+	data, _, err := v.read(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no string field %q", path, field)
+	}
+	return value, nil
+}
+
+// Fetch reads the configured secret and returns its credentials along with
+// the interval after which it should be read again.
+func (v *VaultClient) Fetch(ctx context.Context) (*Credentials, time.Duration, error) {
+	data, lease, err := v.read(ctx, v.cfg.Path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	field := func(name string) (string, error) {
+		if name == "" {
+			return "", nil
+		}
+		value, ok := data[name]
+		if !ok {
+			return "", fmt.Errorf("vault secret %s has no field %q", v.cfg.Path, name)
+		}
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("vault secret %s field %q is not a string", v.cfg.Path, name)
+		}
+		return s, nil
+	}
+
+	creds := &Credentials{}
+	if creds.User, err = field(v.cfg.UserField); err != nil {
+		return nil, 0, err
+	}
+	if creds.Password, err = field(v.cfg.PasswordField); err != nil {
+		return nil, 0, err
+	}
+	for _, pem := range []struct {
+		name string
+		dst  *[]byte
+	}{
+		{v.cfg.CAField, &creds.CAPEM},
+		{v.cfg.CertField, &creds.CertPEM},
+		{v.cfg.KeyField, &creds.KeyPEM},
+	} {
+		s, err := field(pem.name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if s != "" {
+			*pem.dst = []byte(s)
+		}
+	}
+
+	interval := time.Duration(v.cfg.RenewIntervalSec) * time.Second
+	if interval == 0 {
+		interval = lease
+	}
+	if interval <= 0 {
+		interval = defaultRenewInterval
+	}
+
+	return creds, interval, nil
+}
+
+// RenewToken extends the lease of the client's own token.
+func (v *VaultClient) RenewToken(ctx context.Context) error {
+	return v.do(ctx, http.MethodPost, "auth/token/renew-self", nil)
+}
+
+// Watch re-reads the secret on its renewal schedule until ctx is cancelled,
+// calling onChange whenever the credentials differ from current. Errors are
+// logged and retried on the next cycle.
+func (v *VaultClient) Watch(ctx context.Context, current *Credentials, interval time.Duration, onChange func(*Credentials)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if err := v.RenewToken(ctx); err != nil {
+			slog.WarnContext(ctx, "Vault token renewal failed", "error", err)
+		}
+
+		creds, next, err := v.Fetch(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "Vault secret refresh failed", "error", err)
+			continue
+		}
+		interval = next
+
+		if !creds.Equal(current) {
+			current = creds
+			onChange(creds)
+		}
+	}
+}
+
+// do performs a Vault API request and decodes the response into out, if non-nil.
+func (v *VaultClient) do(ctx context.Context, method, path string, out interface{}) error {
+	url := strings.TrimSuffix(v.cfg.Address, "/") + "/v1/" + path
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.cfg.Namespace)
+	}
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault request %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("reading vault response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp vaultResponse
+		if json.Unmarshal(body, &errResp) == nil && len(errResp.Errors) > 0 {
+			return fmt.Errorf("vault request %s: %s: %s", path, resp.Status, strings.Join(errResp.Errors, "; "))
+		}
+		return fmt.Errorf("vault request %s: %s", path, resp.Status)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("decoding vault response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
+)
+
+func newTestVault(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVaultFetch(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		renew        int
+		wantUser     string
+		wantPassword string
+		wantInterval time.Duration
+		wantErr      bool
+	}{
+		{
+			name:         "kv v2",
+			body:         `{"lease_duration":0,"data":{"data":{"user":"mcp","password":"s3cret"},"metadata":{"version":3}}}`,
+			wantUser:     "mcp",
+			wantPassword: "s3cret",
+			wantInterval: defaultRenewInterval,
+		},
+		{
+			name:         "kv v1 with lease",
+			body:         `{"lease_duration":120,"data":{"user":"mcp","password":"s3cret"}}`,
+			wantUser:     "mcp",
+			wantPassword: "s3cret",
+			wantInterval: 2 * time.Minute,
+		},
+		{
+			name:         "configured interval",
+			body:         `{"lease_duration":120,"data":{"user":"mcp","password":"s3cret"}}`,
+			renew:        30,
+			wantUser:     "mcp",
+			wantPassword: "s3cret",
+			wantInterval: 30 * time.Second,
+		},
+		{
+			name:    "missing field",
+			body:    `{"data":{"user":"mcp"}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestVault(t, tt.body)
+			v, err := NewVaultClient(&config.VaultConfig{
+				Address:          srv.URL,
+				Token:            "test-token",
+				Path:             "secret/data/aerospike",
+				UserField:        "user",
+				PasswordField:    "password",
+				RenewIntervalSec: tt.renew,
+			})
+			if err != nil {
+				t.Fatalf("NewVaultClient() error = %v", err)
+			}
+
+			creds, interval, err := v.Fetch(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if creds.User != tt.wantUser || creds.Password != tt.wantPassword {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.wantUser, tt.wantPassword, creds.User, creds.Password)
+			}
+			if interval != tt.wantInterval {
+				t.Errorf("Expected interval %v, got %v", tt.wantInterval, interval)
+			}
+		})
+	}
+}
+
+func TestVaultFetchTLSMaterial(t *testing.T) {
+	srv := newTestVault(t, `{"data":{"data":{"user":"mcp","password":"pw","cert":"CERT","key":"KEY"},"metadata":{}}}`)
+	v, err := NewVaultClient(&config.VaultConfig{
+		Address:       srv.URL,
+		Token:         "test-token",
+		Path:          "secret/data/aerospike",
+		UserField:     "user",
+		PasswordField: "password",
+		CertField:     "cert",
+		KeyField:      "key",
+	})
+	if err != nil {
+		t.Fatalf("NewVaultClient() error = %v", err)
+	}
+
+	creds, _, err := v.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	creds.Apply(cfg)
+	if cfg.User != "mcp" || cfg.Password != "pw" {
+		t.Errorf("Expected credentials applied, got %s/%s", cfg.User, cfg.Password)
+	}
+	if string(cfg.TLS.CertPEM) != "CERT" || string(cfg.TLS.KeyPEM) != "KEY" {
+		t.Errorf("Expected TLS material applied, got %q/%q", cfg.TLS.CertPEM, cfg.TLS.KeyPEM)
+	}
+	if cfg.TLS.CAPEM != nil {
+		t.Errorf("Expected no CA material, got %q", cfg.TLS.CAPEM)
+	}
+}
+
+func TestVaultPermissionDenied(t *testing.T) {
+	srv := newTestVault(t, `{}`)
+	v, err := NewVaultClient(&config.VaultConfig{Address: srv.URL, Token: "wrong", Path: "secret/aerospike"})
+	if err != nil {
+		t.Fatalf("NewVaultClient() error = %v", err)
+	}
+
+	if _, _, err := v.Fetch(context.Background()); err == nil {
+		t.Error("Expected error for rejected token")
+	}
+}
+
+func TestNewVaultClientRequiresToken(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+	if _, err := NewVaultClient(&config.VaultConfig{Address: "http://127.0.0.1:8200", Path: "secret/aerospike"}); err == nil {
+		t.Error("Expected error when no token is configured")
+	}
+
+	t.Setenv("AEROSPIKE_VAULT_TOKEN", "from-env")
+	v, err := NewVaultClient(&config.VaultConfig{Address: "http://127.0.0.1:8200", TokenEnv: "AEROSPIKE_VAULT_TOKEN", Path: "secret/aerospike"})
+	if err != nil {
+		t.Fatalf("NewVaultClient() error = %v", err)
+	}
+	if v.token != "from-env" {
+		t.Errorf("Expected token from environment, got %q", v.token)
+	}
+}
+
+func TestCredentialsEqual(t *testing.T) {
+	a := &Credentials{User: "u", Password: "p", KeyPEM: []byte("k")}
+	b := &Credentials{User: "u", Password: "p", KeyPEM: []byte("k")}
+	c := &Credentials{User: "u", Password: "rotated", KeyPEM: []byte("k")}
+
+	if !a.Equal(b) {
+		t.Error("Expected identical credentials to be equal")
+	}
+	if a.Equal(c) {
+		t.Error("Expected rotated credentials to differ")
+	}
+	if a.Equal(nil) {
+		t.Error("Expected credentials not to equal nil")
+	}
+}
+
+func TestVaultResolve(t *testing.T) {
+	srv := newTestVault(t, `{"data":{"data":{"api_key":"k-123"},"metadata":{}}}`)
+	v, err := NewVaultClient(&config.VaultConfig{Address: srv.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatalf("NewVaultClient() error = %v", err)
+	}
+
+	got, err := v.Resolve(context.Background(), "secret/data/mcp#api_key")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "k-123" {
+		t.Errorf("Expected 'k-123', got '%s'", got)
+	}
+
+	if _, err := v.Resolve(context.Background(), "secret/data/mcp"); err == nil {
+		t.Error("Expected error for reference without a field")
+	}
+}
@@ -7,9 +7,12 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/audit"
 	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
 )
 
@@ -29,11 +32,12 @@ type InputSchema struct {
 
 // Property represents a property in the input schema.
 type Property struct {
-	Type        string      `json:"type"`
-	Description string      `json:"description,omitempty"`
-	Enum        []string    `json:"enum,omitempty"`
-	Items       *Property   `json:"items,omitempty"`
-	Default     interface{} `json:"default,omitempty"`
+	Type        string              `json:"type"`
+	Description string              `json:"description,omitempty"`
+	Enum        []string            `json:"enum,omitempty"`
+	Items       *Property           `json:"items,omitempty"`
+	Properties  map[string]Property `json:"properties,omitempty"`
+	Default     interface{}         `json:"default,omitempty"`
 }
 
 // Registry manages available MCP tools.
@@ -41,6 +45,8 @@ type Registry struct {
 	client *aerospike.Client
 	config *config.Config
 	tools  map[string]ToolHandler
+	roles  map[string]config.Role // minimum role required per tool
+	audit  *audit.Logger          // records overrides of safety checks and configuration changes
 }
 
 // ToolHandler is a function that handles a tool call.
@@ -52,6 +58,7 @@ func NewRegistry(client *aerospike.Client, cfg *config.Config) *Registry {
 		client: client,
 		config: cfg,
 		tools:  make(map[string]ToolHandler),
+		roles:  make(map[string]config.Role),
 	}
 
 	// Register schema/namespace tools
@@ -60,15 +67,12 @@ func NewRegistry(client *aerospike.Client, cfg *config.Config) *Registry {
 	// Register query/read tools
 	r.registerReadTools()
 
-	// Register write tools (if permitted)
-	if cfg.CanWrite() {
-		r.registerWriteTools()
-	}
+	// Register write tools. All tools are registered regardless of the
+	// configured role; Call enforces the role of each request.
+	r.registerWriteTools()
 
-	// Register index tools (if admin)
-	if cfg.CanAdmin() {
-		r.registerIndexTools()
-	}
+	// Register index tools
+	r.registerIndexTools()
 
 	// Register cluster tools
 	r.registerClusterTools()
@@ -76,8 +80,20 @@ func NewRegistry(client *aerospike.Client, cfg *config.Config) *Registry {
 	return r
 }
 
-// List returns all available tool definitions.
+// SetAuditLogger sets the logger that records calls confirming an operation
+// a safety check would refuse, such as a scan of a large set, and the
+// details of configuration changes.
+func (r *Registry) SetAuditLogger(logger *audit.Logger) {
+	r.audit = logger
+}
+
+// List returns the tool definitions available to the process-wide role.
 func (r *Registry) List() []ToolDefinition {
+	return r.ListForRole(r.config.EffectiveRole(context.Background()))
+}
+
+// ListForRole returns the tool definitions available to role.
+func (r *Registry) ListForRole(role config.Role) []ToolDefinition {
 	definitions := []ToolDefinition{
 		// Schema/Namespace Tools
 		{
@@ -109,28 +125,39 @@ func (r *Registry) List() []ToolDefinition {
 		},
 		{
 			Name:        "describe_set",
-			Description: "Retrieve detailed statistics for a specific set including bin schema inference",
+			Description: "Retrieve detailed statistics for a specific set including bin schema inference: the bins, types, and nullability seen in a sample of its records, and their average size",
 			InputSchema: InputSchema{
 				Type: "object",
 				Properties: map[string]Property{
-					"namespace": {Type: "string", Description: "Target namespace name"},
-					"set_name":  {Type: "string", Description: "Target set name"},
+					"namespace":   {Type: "string", Description: "Target namespace name"},
+					"set_name":    {Type: "string", Description: "Target set name"},
+					"sample_size": {Type: "integer", Description: "Records to sample for the schema (default: 100)", Default: aerospike.DefaultSchemaSample},
 				},
 				Required: []string{"namespace", "set_name"},
 			},
 		},
+		{
+			Name:        "refresh_metadata",
+			Description: "Discard cached namespace, set, index, and UDF listings so the next call reads the cluster",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: map[string]Property{
+					"namespace": {Type: "string", Description: "Namespace to refresh (default: all metadata)"},
+				},
+			},
+		},
 		// Query/Read Tools
 		{
 			Name:        "get_record",
 			Description: "Retrieve a single record by primary key",
 			InputSchema: InputSchema{
 				Type: "object",
-				Properties: map[string]Property{
+				Properties: withReadOptions(map[string]Property{
 					"namespace": {Type: "string", Description: "Target namespace"},
 					"set_name":  {Type: "string", Description: "Target set (optional)"},
 					"key":       {Type: "string", Description: "Primary key value"},
 					"bins":      {Type: "array", Description: "Specific bins to retrieve (default: all)", Items: &Property{Type: "string"}},
-				},
+				}),
 				Required: []string{"namespace", "key"},
 			},
 		},
@@ -139,11 +166,25 @@ func (r *Registry) List() []ToolDefinition {
 			Description: "Retrieve multiple records in a single network round-trip",
 			InputSchema: InputSchema{
 				Type: "object",
-				Properties: map[string]Property{
+				Properties: withReadOptions(map[string]Property{
 					"namespace":      {Type: "string", Description: "Target namespace"},
 					"keys":           {Type: "array", Description: "Array of key objects", Items: &Property{Type: "object"}},
 					"max_concurrent": {Type: "integer", Description: "Maximum concurrent requests (default: 100)", Default: 100},
-				},
+					"cursor":         {Type: "string", Description: "Cursor from a truncated result, to continue with its remaining keys"},
+				}),
+				Required: []string{"namespace", "keys"},
+			},
+		},
+		{
+			Name:        "batch_exists",
+			Description: "Check which keys have records, reading only record headers. Returns exists, generation, and TTL per key",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: withReadOptions(map[string]Property{
+					"namespace": {Type: "string", Description: "Target namespace"},
+					"set_name":  {Type: "string", Description: "Target set (optional)"},
+					"keys":      {Type: "array", Description: "Primary keys to check", Items: &Property{Type: "string"}},
+				}),
 				Required: []string{"namespace", "keys"},
 			},
 		},
@@ -152,31 +193,61 @@ func (r *Registry) List() []ToolDefinition {
 			Description: "Execute a secondary index query with optional filter expressions",
 			InputSchema: InputSchema{
 				Type: "object",
-				Properties: map[string]Property{
+				Properties: withRecordOrder(withScanThrottle(withReadOptions(map[string]Property{
 					"namespace":   {Type: "string", Description: "Target namespace"},
 					"set_name":    {Type: "string", Description: "Target set (optional)"},
 					"index_name":  {Type: "string", Description: "Secondary index to query"},
-					"filter":      {Type: "object", Description: "Filter expression (equality, range, or geo)"},
+					"filter":      {Type: "object", Description: "Index filter with bin_name and filter_type: equal (value), range (begin, end), or contains (collection_type LIST, MAPKEYS, or MAPVALUES, and value or begin and end)"},
 					"max_records": {Type: "integer", Description: "Result limit (default: 1000)", Default: 1000},
-				},
+					"cursor":      {Type: "string", Description: "Cursor from a truncated result, to continue where it ended"},
+				}))),
 				Required: []string{"namespace", "index_name", "filter"},
 			},
 		},
 		{
 			Name:        "scan_set",
-			Description: "Perform a full set scan with sampling and projection support. Requires explicit confirmation for sets exceeding 100,000 records.",
+			Description: "Perform a full set scan with sampling and projection support. Requires explicit confirmation (confirm_large_scan) for sets exceeding 100,000 records.",
 			InputSchema: InputSchema{
 				Type: "object",
-				Properties: map[string]Property{
-					"namespace":      {Type: "string", Description: "Target namespace"},
-					"set_name":       {Type: "string", Description: "Target set (optional)"},
-					"bins":           {Type: "array", Description: "Specific bins to retrieve", Items: &Property{Type: "string"}},
-					"max_records":    {Type: "integer", Description: "Maximum records to return (default: 1000)", Default: 1000},
-					"sample_percent": {Type: "integer", Description: "Sample percentage (1-100)"},
-				},
+				Properties: withRecordOrder(withScanThrottle(map[string]Property{
+					"namespace":          {Type: "string", Description: "Target namespace"},
+					"set_name":           {Type: "string", Description: "Target set (optional)"},
+					"bins":               {Type: "array", Description: "Specific bins to retrieve", Items: &Property{Type: "string"}},
+					"max_records":        {Type: "integer", Description: "Maximum records to return (default: 1000)", Default: 1000},
+					"sample_percent":     {Type: "integer", Description: "Sample percentage (1-100)"},
+					"cursor":             {Type: "string", Description: "Cursor from a truncated result, to continue where it ended"},
+					"confirm_large_scan": {Type: "boolean", Description: "Confirm a scan of a set holding more records than the configured threshold (default: 100,000)"},
+				})),
 				Required: []string{"namespace"},
 			},
 		},
+		{
+			Name:        "aggregate_simple",
+			Description: "Compute the record count, and the min, max, sum, and average of a numeric bin, over a set or the records matching an index filter or filter expression. Only the bin is read from each record, and nothing but the count if no bin is named.",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: withScanThrottle(withReadOptions(map[string]Property{
+					"namespace":         {Type: "string", Description: "Target namespace"},
+					"set_name":          {Type: "string", Description: "Target set (optional)"},
+					"bin_name":          {Type: "string", Description: "Numeric bin to aggregate (optional; without it only records are counted)"},
+					"filter":            {Type: "object", Description: "Index filter, as for query_records (optional)"},
+					"filter_expression": {Type: "object", Description: "Expression records must match, as for operate's exp_read (optional)"},
+				})),
+				Required: []string{"namespace"},
+			},
+		},
+		{
+			Name:        "sql_query",
+			Description: "Run a restricted SQL SELECT: SELECT bins FROM namespace.set [WHERE ...] [ORDER BY bin [ASC|DESC]] [LIMIT n]. The statement is planned onto a primary-key read, a batch read, a secondary index query, or an expression-filtered scan, and the plan is returned with the records.",
+			InputSchema: InputSchema{
+				Type: "object",
+				Properties: withScanThrottle(withReadOptions(map[string]Property{
+					"sql":     {Type: "string", Description: "SELECT statement; WHERE supports =, !=, <>, <, <=, >, >=, BETWEEN, IN, AND, OR, NOT, and PK for the primary key"},
+					"explain": {Type: "boolean", Description: "Return the plan without running it (default: false)"},
+				})),
+				Required: []string{"sql"},
+			},
+		},
 		// Cluster Tools
 		{
 			Name:        "cluster_info",
@@ -197,21 +268,25 @@ func (r *Registry) List() []ToolDefinition {
 	}
 
 	// Add write tools if permitted
-	if r.config.CanWrite() {
+	if role.CanWrite() {
 		definitions = append(definitions,
 			ToolDefinition{
 				Name:        "put_record",
-				Description: "Insert or update a single record",
+				Description: "Insert or update a single record, or remove some of its bins with delete_bins",
 				InputSchema: InputSchema{
 					Type: "object",
 					Properties: map[string]Property{
-						"namespace": {Type: "string", Description: "Target namespace"},
-						"set_name":  {Type: "string", Description: "Target set (optional)"},
-						"key":       {Type: "string", Description: "Primary key"},
-						"bins":      {Type: "object", Description: "Bin name-value pairs"},
-						"ttl":       {Type: "integer", Description: "Record TTL in seconds (-1 for namespace default)", Default: -1},
+						"namespace":      {Type: "string", Description: "Target namespace"},
+						"set_name":       {Type: "string", Description: "Target set (optional)"},
+						"key":            {Type: "string", Description: "Primary key"},
+						"bins":           {Type: "object", Description: `Bin name-value pairs. Wrap a value as {"$float": 42} or {"$int": 42} to fix its type, or a GeoJSON object as {"$geojson": {...}} to store it as GeoJSON`},
+						"delete_bins":    {Type: "array", Description: "Names of bins to remove from the record. Removing every bin deletes the record", Items: &Property{Type: "string"}},
+						"ttl":            {Type: "integer", Description: "Record TTL in seconds (-1 for namespace default)", Default: -1},
+						"mode":           {Type: "string", Description: "How to treat an existing record: create_only fails if it exists, update_only fails if it doesn't, replace and replace_only (which fails if it doesn't exist) remove bins not written. Default: create or update the bins written", Enum: writeModes},
+						"dry_run":        {Type: "boolean", Description: "Report what would change without writing"},
+						"preserve_float": {Type: "boolean", Description: "Write whole-number floats such as 42.0 as floats instead of integers (default from preserve_float)"},
 					},
-					Required: []string{"namespace", "key", "bins"},
+					Required: []string{"namespace", "key"},
 				},
 			},
 			ToolDefinition{
@@ -223,6 +298,7 @@ func (r *Registry) List() []ToolDefinition {
 						"namespace": {Type: "string", Description: "Target namespace"},
 						"set_name":  {Type: "string", Description: "Target set (optional)"},
 						"key":       {Type: "string", Description: "Primary key"},
+						"dry_run":   {Type: "boolean", Description: "Report what would change without deleting"},
 					},
 					Required: []string{"namespace", "key"},
 				},
@@ -238,16 +314,20 @@ func (r *Registry) List() []ToolDefinition {
 							Description: "Array of write operations",
 							Items: &Property{
 								Type:        "object",
-								Description: "Write operation with namespace, set, key, bins, ttl, and operation type (put/delete)",
+								Description: `Write operation with namespace, set, key, bins, delete_bins (bin names a put removes), ttl, and operation type (put/delete). Optional generation (fail unless the record's generation matches), mode (a put's mode, overriding the batch's), and durable_delete apply to this operation alone. Bin values may be wrapped as {"$float": 42}, {"$int": 42}, or {"$geojson": {...}}`,
 							},
 						},
+						"mode":                {Type: "string", Description: "How puts treat existing records, as for put_record", Enum: writeModes},
+						"stop_on_first_error": {Type: "boolean", Description: "Stop at the first operation that fails: later operations are not sent, and the cluster stops writing to each node at its first failure"},
+						"dry_run":             {Type: "boolean", Description: "Report what would change without writing"},
+						"preserve_float":      {Type: "boolean", Description: "Write whole-number floats such as 42.0 as floats instead of integers (default from preserve_float)"},
 					},
 					Required: []string{"operations"},
 				},
 			},
 			ToolDefinition{
 				Name:        "operate",
-				Description: "Execute atomic read-modify-write operations on a single record. Supports increment, append, prepend, touch, and read operations.",
+				Description: "Execute atomic read-modify-write operations on a single record. Supports increment, append, prepend, touch, and read operations, list and map operations that can reach into nested values with a ctx path, and expression operations that compute a value on the server and return it (exp_read) or write it (exp_write).",
 				InputSchema: InputSchema{
 					Type: "object",
 					Properties: map[string]Property{
@@ -256,10 +336,11 @@ func (r *Registry) List() []ToolDefinition {
 						"key":       {Type: "string", Description: "Primary key"},
 						"operations": {
 							Type:        "array",
-							Description: "Array of operations: {type: 'increment'|'append'|'prepend'|'touch'|'read', bin_name: string, value: any}",
+							Description: "Array of operations: {type: 'increment'|'append'|'prepend'|'touch'|'read'|'list_append'|'list_get'|'list_remove'|'list_size'|'map_put'|'map_get'|'map_remove'|'map_increment'|'map_size'|'exp_read'|'exp_write', bin_name: string, value: any, key: map key, index: list index, ctx: [{type: 'list_index'|'list_rank'|'list_value'|'map_index'|'map_rank'|'map_key'|'map_value', value: any}], expression: exp, flags: [string]}. exp_read and exp_write take an expression: a literal, {bin: name, type: 'int'|'float'|'string'|'bool'|'list'|'map'|'blob'|'geo'}, or {op: 'add'|'sub'|'mul'|'div'|'min'|'max'|'and'|'or'|'eq'|'ne'|'gt'|'ge'|'lt'|'le'|'mod'|'pow'|'not'|'abs'|'floor'|'ceil'|'to_int'|'to_float'|'cond'|'ttl'|'last_update'|'since_update'|'record_size'|'key_exists'|'bin_exists'|'unknown', args: [exp]}",
 							Items:       &Property{Type: "object"},
 						},
-						"ttl": {Type: "integer", Description: "Record TTL in seconds", Default: -1},
+						"ttl":     {Type: "integer", Description: "Record TTL in seconds", Default: -1},
+						"dry_run": {Type: "boolean", Description: "Report what would change without writing"},
 					},
 					Required: []string{"namespace", "key", "operations"},
 				},
@@ -268,7 +349,7 @@ func (r *Registry) List() []ToolDefinition {
 	}
 
 	// Add admin tools if permitted
-	if r.config.CanAdmin() {
+	if role.CanAdmin() {
 		definitions = append(definitions,
 			ToolDefinition{
 				Name:        "create_index",
@@ -282,6 +363,11 @@ func (r *Registry) List() []ToolDefinition {
 						"bin_name":        {Type: "string", Description: "Bin to index"},
 						"index_type":      {Type: "string", Description: "Index type", Enum: []string{"NUMERIC", "STRING", "GEO2DSPHERE", "BLOB"}},
 						"collection_type": {Type: "string", Description: "Collection type", Enum: []string{"DEFAULT", "LIST", "MAPKEYS", "MAPVALUES"}},
+						"ctx": {
+							Type:        "array",
+							Description: "Path to a list or map nested in the bin to index instead of the bin itself: [{type: 'list_index'|'list_rank'|'list_value'|'map_index'|'map_rank'|'map_key'|'map_value', value: any}]",
+							Items:       &Property{Type: "object"},
+						},
 					},
 					Required: []string{"namespace", "index_name", "bin_name", "index_type"},
 				},
@@ -301,16 +387,44 @@ func (r *Registry) List() []ToolDefinition {
 			},
 			ToolDefinition{
 				Name:        "truncate_set",
-				Description: "Remove all records from a set. EXTREME CAUTION - Requires double confirmation.",
+				Description: "Remove all records from a set. EXTREME CAUTION - Requires double confirmation. Returns the records and bytes removed; preview_only reports them without truncating, so the impact can be reviewed before confirming.",
 				InputSchema: InputSchema{
 					Type: "object",
 					Properties: map[string]Property{
 						"namespace":           {Type: "string", Description: "Target namespace"},
 						"set_name":            {Type: "string", Description: "Target set"},
-						"confirm":             {Type: "boolean", Description: "First confirmation flag"},
-						"confirm_destructive": {Type: "boolean", Description: "Second confirmation flag"},
+						"confirm":             {Type: "boolean", Description: "First confirmation flag (required unless preview_only)"},
+						"confirm_destructive": {Type: "boolean", Description: "Second confirmation flag (required unless preview_only)"},
+						"preview_only":        {Type: "boolean", Description: "Report the records and bytes the truncation would remove without truncating"},
 					},
-					Required: []string{"namespace", "set_name", "confirm", "confirm_destructive"},
+					Required: []string{"namespace", "set_name"},
+				},
+			},
+			ToolDefinition{
+				Name:        "get_namespace_config",
+				Description: "View a namespace's default TTL and, with set_name, the set's enable-index and stop-writes-count configuration",
+				InputSchema: InputSchema{
+					Type: "object",
+					Properties: map[string]Property{
+						"namespace": {Type: "string", Description: "Target namespace"},
+						"set_name":  {Type: "string", Description: "Target set (optional)"},
+					},
+					Required: []string{"namespace"},
+				},
+			},
+			ToolDefinition{
+				Name:        "set_namespace_config",
+				Description: "Change a namespace's default TTL, or a set's enable-index and stop-writes-count, on every cluster node. Returns the configuration before and after the change.",
+				InputSchema: InputSchema{
+					Type: "object",
+					Properties: map[string]Property{
+						"namespace":         {Type: "string", Description: "Target namespace"},
+						"set_name":          {Type: "string", Description: "Target set, for enable_index and stop_writes_count"},
+						"default_ttl":       {Type: "integer", Description: "Namespace default TTL in seconds (0 = never expire); omit set_name"},
+						"enable_index":      {Type: "boolean", Description: "Keep a set index so scans of the set don't read the whole namespace"},
+						"stop_writes_count": {Type: "integer", Description: "Refuse writes once the set holds this many records (0 = no limit)"},
+					},
+					Required: []string{"namespace"},
 				},
 			},
 			// UDF Management
@@ -359,6 +473,21 @@ func (r *Registry) List() []ToolDefinition {
 					Required: []string{"namespace", "key", "module_name", "function_name"},
 				},
 			},
+			ToolDefinition{
+				Name: "benchmark",
+				Description: "Run a short read/write workload against the configured benchmark set and report p50/p95/p99 latency and throughput, " +
+					"to tell whether the cluster or the network is slow. Records are written before and deleted after the run.",
+				InputSchema: InputSchema{
+					Type: "object",
+					Properties: map[string]Property{
+						"operations":   {Type: "integer", Description: "Operations to run, up to benchmark.max_operations", Default: 1000},
+						"concurrency":  {Type: "integer", Description: "Operations in flight at once, up to benchmark.max_concurrency", Default: 4},
+						"read_ratio":   {Type: "number", Description: "Share of operations that are reads, from 0 to 1", Default: 0.5},
+						"record_bytes": {Type: "integer", Description: "Size of each record written", Default: 100},
+						"keys":         {Type: "integer", Description: "Distinct records the workload reads and writes", Default: 100},
+					},
+				},
+			},
 		)
 	}
 
@@ -374,18 +503,177 @@ func (r *Registry) List() []ToolDefinition {
 		},
 	})
 
+	return withCallPolicy(definitions)
+}
+
+// writeModes are the values of put_record's and batch_write's mode.
+var writeModes = []string{
+	string(aerospike.WriteModeCreateOnly),
+	string(aerospike.WriteModeUpdateOnly),
+	string(aerospike.WriteModeReplace),
+	string(aerospike.WriteModeReplaceOnly),
+}
+
+// policyTools are the read and write tools that accept a policy argument
+// overriding the configured client policies for the call.
+var policyTools = map[string]bool{
+	"get_record":       true,
+	"batch_get":        true,
+	"batch_exists":     true,
+	"query_records":    true,
+	"scan_set":         true,
+	"aggregate_simple": true,
+	"sql_query":        true,
+	"put_record":       true,
+	"delete_record":    true,
+	"batch_write":      true,
+	"operate":          true,
+	"execute_udf":      true,
+}
+
+// withCallPolicy adds the policy argument to the definitions of
+// policyTools.
+func withCallPolicy(definitions []ToolDefinition) []ToolDefinition {
+	readModes := []string{config.ReadModeAPOne, config.ReadModeAPAll, config.ReadModeSCSession,
+		config.ReadModeSCLinearize, config.ReadModeSCAllowReplica, config.ReadModeSCAllowUnavailable}
+	policy := Property{
+		Type:        "object",
+		Description: "Client policy overrides for this call (defaults from config)",
+		Properties: map[string]Property{
+			"timeout_ms":     {Type: "integer", Description: "Total timeout of each cluster command; the tool's time limit still applies"},
+			"max_retries":    {Type: "integer", Description: "Retries of each cluster command (0-10)"},
+			"read_mode":      {Type: "string", Description: "Read consistency: one or all in AP namespaces, the others in strong consistency namespaces", Enum: readModes},
+			"durable_delete": {Type: "boolean", Description: "Leave a tombstone when deleting, so the record can't return after a cold restart"},
+			"send_key":       {Type: "boolean", Description: "Store the user key with the record"},
+		},
+	}
+	for i, def := range definitions {
+		if policyTools[def.Name] {
+			definitions[i].InputSchema.Properties["policy"] = policy
+		}
+	}
 	return definitions
 }
 
+// withScanThrottle adds the per-call scan and query throttle options to a
+// scan tool's properties.
+func withScanThrottle(properties map[string]Property) map[string]Property {
+	properties["records_per_second"] = Property{
+		Type:        "integer",
+		Description: "Records per second to read from each node, capped at scan_limits.records_per_second (default from config)",
+	}
+	properties["max_concurrent_nodes"] = Property{
+		Type:        "integer",
+		Description: "Nodes to scan at once, capped at scan_limits.max_concurrent_nodes (default from config)",
+	}
+	return properties
+}
+
+// withRecordOrder adds the sort and offset options to a scan tool's
+// properties.
+func withRecordOrder(properties map[string]Property) map[string]Property {
+	properties["order_by"] = Property{
+		Type:        "string",
+		Description: "Bin to sort the returned records by. Only the records read, up to max_records, are sorted, and the result has no cursor",
+	}
+	properties["direction"] = Property{
+		Type:        "string",
+		Description: "Sort direction for order_by (default: asc)",
+		Enum:        []string{"asc", "desc"},
+	}
+	properties["offset"] = Property{
+		Type:        "integer",
+		Description: "Records to skip from the start of the sorted records",
+	}
+	return properties
+}
+
+// withReadOptions adds the per-call read consistency and replica options
+// to a read tool's properties.
+func withReadOptions(properties map[string]Property) map[string]Property {
+	properties["read_mode_ap"] = Property{
+		Type:        "string",
+		Description: "Read consistency in AP namespaces (default from config)",
+		Enum:        []string{config.ReadModeAPOne, config.ReadModeAPAll},
+	}
+	properties["read_mode_sc"] = Property{
+		Type:        "string",
+		Description: "Read consistency in strong consistency namespaces (default from config)",
+		Enum:        []string{config.ReadModeSCSession, config.ReadModeSCLinearize, config.ReadModeSCAllowReplica, config.ReadModeSCAllowUnavailable},
+	}
+	properties["replica"] = Property{
+		Type:        "string",
+		Description: "Which replica serves the read (default from config)",
+		Enum:        []string{config.ReplicaMaster, config.ReplicaMasterProles, config.ReplicaRandom, config.ReplicaSequence, config.ReplicaPreferRack},
+	}
+	return properties
+}
+
 // Call executes a tool by name with the given arguments.
 func (r *Registry) Call(ctx context.Context, name string, args json.RawMessage) (interface{}, error) {
 	handler, ok := r.tools[name]
 	if !ok {
 		return nil, fmt.Errorf("unknown tool: %s", name)
 	}
+	if err := r.Authorize(ctx, name, args); err != nil {
+		return nil, err
+	}
+	if policyTools[name] {
+		policy, err := argsPolicy(args)
+		if err != nil {
+			return nil, err
+		}
+		ctx = aerospike.WithCallPolicy(ctx, policy)
+	}
 	return handler(ctx, args)
 }
 
+// Authorize checks that the caller's role, in the namespace named in args,
+// allows the named tool.
+func (r *Registry) Authorize(ctx context.Context, name string, args json.RawMessage) error {
+	required, ok := r.roles[name]
+	if !ok {
+		return nil
+	}
+	role := r.config.EffectiveRole(ctx)
+	if namespace := argsNamespace(args); namespace != "" {
+		role = r.config.NamespaceRole(ctx, namespace)
+	}
+	if role.Rank() < required.Rank() {
+		return &config.RoleError{Operation: "tool " + name, Role: role, ReadOnly: r.config.IsReadOnly()}
+	}
+	return nil
+}
+
+// argsNamespace returns the namespace named in tool arguments, if any.
+func argsNamespace(args json.RawMessage) string {
+	var a struct {
+		Namespace string `json:"namespace"`
+	}
+	_ = json.Unmarshal(args, &a)
+	return a.Namespace
+}
+
+// argsPolicy returns the validated policy overrides in tool arguments.
+func argsPolicy(args json.RawMessage) (aerospike.CallPolicy, error) {
+	var a struct {
+		Policy aerospike.CallPolicy `json:"policy"`
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &a); err != nil {
+			return a.Policy, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	return a.Policy, a.Policy.Validate()
+}
+
+// requireRole records the minimum role needed to call the named tools.
+func (r *Registry) requireRole(role config.Role, names ...string) {
+	for _, name := range names {
+		r.roles[name] = role
+	}
+}
+
 // ============================================================================
 // Tool Registration
 // ============================================================================
@@ -395,13 +683,17 @@ func (r *Registry) registerSchemaTools() {
 	r.tools["describe_namespace"] = r.handleDescribeNamespace
 	r.tools["list_sets"] = r.handleListSets
 	r.tools["describe_set"] = r.handleDescribeSet
+	r.tools["refresh_metadata"] = r.handleRefreshMetadata
 }
 
 func (r *Registry) registerReadTools() {
 	r.tools["get_record"] = r.handleGetRecord
 	r.tools["batch_get"] = r.handleBatchGet
+	r.tools["batch_exists"] = r.handleBatchExists
 	r.tools["query_records"] = r.handleQueryRecords
 	r.tools["scan_set"] = r.handleScanSet
+	r.tools["aggregate_simple"] = r.handleAggregateSimple
+	r.tools["sql_query"] = r.handleSQLQuery
 }
 
 func (r *Registry) registerWriteTools() {
@@ -409,23 +701,30 @@ func (r *Registry) registerWriteTools() {
 	r.tools["delete_record"] = r.handleDeleteRecord
 	r.tools["batch_write"] = r.handleBatchWrite
 	r.tools["operate"] = r.handleOperate
+	r.requireRole(config.RoleReadWrite, "put_record", "delete_record", "batch_write", "operate")
 }
 
 func (r *Registry) registerIndexTools() {
 	r.tools["create_index"] = r.handleCreateIndex
 	r.tools["drop_index"] = r.handleDropIndex
 	r.tools["truncate_set"] = r.handleTruncateSet
+	r.tools["get_namespace_config"] = r.handleGetNamespaceConfig
+	r.tools["set_namespace_config"] = r.handleSetNamespaceConfig
 	// UDF tools
 	r.tools["list_udfs"] = r.handleListUDFs
 	r.tools["register_udf"] = r.handleRegisterUDF
 	r.tools["remove_udf"] = r.handleRemoveUDF
 	r.tools["execute_udf"] = r.handleExecuteUDF
+	r.requireRole(config.RoleAdmin, "create_index", "drop_index", "truncate_set",
+		"get_namespace_config", "set_namespace_config", "list_udfs", "register_udf", "remove_udf", "execute_udf")
 }
 
 func (r *Registry) registerClusterTools() {
 	r.tools["cluster_info"] = r.handleClusterInfo
 	r.tools["list_indexes"] = r.handleListIndexes
 	r.tools["node_stats"] = r.handleNodeStats
+	r.tools["benchmark"] = r.handleBenchmark
+	r.requireRole(config.RoleAdmin, "benchmark")
 }
 
 // ============================================================================
@@ -461,8 +760,9 @@ func (r *Registry) handleListSets(ctx context.Context, args json.RawMessage) (in
 }
 
 type describeSetArgs struct {
-	Namespace string `json:"namespace"`
-	SetName   string `json:"set_name"`
+	Namespace  string `json:"namespace"`
+	SetName    string `json:"set_name"`
+	SampleSize int    `json:"sample_size"`
 }
 
 func (r *Registry) handleDescribeSet(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -470,7 +770,25 @@ func (r *Registry) handleDescribeSet(ctx context.Context, args json.RawMessage)
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
-	return r.client.DescribeSet(ctx, a.Namespace, a.SetName)
+	return r.client.DescribeSet(ctx, a.Namespace, a.SetName, a.SampleSize)
+}
+
+type refreshMetadataArgs struct {
+	Namespace string `json:"namespace"`
+}
+
+func (r *Registry) handleRefreshMetadata(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a refreshMetadataArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	r.client.RefreshMetadata(ctx, a.Namespace)
+
+	refreshed := a.Namespace
+	if refreshed == "" {
+		refreshed = "all"
+	}
+	return map[string]interface{}{"status": "ok", "refreshed": refreshed}, nil
 }
 
 type getRecordArgs struct {
@@ -478,6 +796,7 @@ type getRecordArgs struct {
 	SetName   string   `json:"set_name"`
 	Key       string   `json:"key"`
 	Bins      []string `json:"bins"`
+	aerospike.ReadOptions
 }
 
 func (r *Registry) handleGetRecord(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -485,7 +804,10 @@ func (r *Registry) handleGetRecord(ctx context.Context, args json.RawMessage) (i
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
-	return r.client.GetRecord(ctx, a.Namespace, a.SetName, a.Key, a.Bins)
+	if err := a.ReadOptions.Validate(); err != nil {
+		return nil, err
+	}
+	return r.client.GetRecord(aerospike.WithReadOptions(ctx, a.ReadOptions), a.Namespace, a.SetName, a.Key, a.Bins)
 }
 
 type batchGetArgs struct {
@@ -495,7 +817,9 @@ type batchGetArgs struct {
 		Set  string   `json:"set"`
 		Bins []string `json:"bins"`
 	} `json:"keys"`
-	MaxConcurrent int `json:"max_concurrent"`
+	MaxConcurrent int    `json:"max_concurrent"`
+	Cursor        string `json:"cursor"`
+	aerospike.ReadOptions
 }
 
 func (r *Registry) handleBatchGet(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -503,6 +827,9 @@ func (r *Registry) handleBatchGet(ctx context.Context, args json.RawMessage) (in
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
+	if err := a.ReadOptions.Validate(); err != nil {
+		return nil, err
+	}
 
 	requests := make([]aerospike.BatchGetRequest, len(a.Keys))
 	for i, k := range a.Keys {
@@ -514,7 +841,37 @@ func (r *Registry) handleBatchGet(ctx context.Context, args json.RawMessage) (in
 		}
 	}
 
-	return r.client.BatchGet(ctx, requests)
+	page, err := r.client.BatchGetPage(aerospike.WithReadOptions(ctx, a.ReadOptions), requests, a.Cursor)
+	return pageResult(page, err, a.Cursor)
+}
+
+type batchExistsArgs struct {
+	Namespace string   `json:"namespace"`
+	SetName   string   `json:"set_name"`
+	Keys      []string `json:"keys"`
+	aerospike.ReadOptions
+}
+
+func (r *Registry) handleBatchExists(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a batchExistsArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if err := a.ReadOptions.Validate(); err != nil {
+		return nil, err
+	}
+
+	results, err := r.client.BatchExists(aerospike.WithReadOptions(ctx, a.ReadOptions), a.Namespace, a.SetName, a.Keys)
+	if err != nil {
+		return nil, err
+	}
+	found := 0
+	for _, result := range results {
+		if result.Exists {
+			found++
+		}
+	}
+	return map[string]interface{}{"results": results, "found": found}, nil
 }
 
 type queryRecordsArgs struct {
@@ -523,6 +880,10 @@ type queryRecordsArgs struct {
 	IndexName  string                `json:"index_name"`
 	Filter     aerospike.QueryFilter `json:"filter"`
 	MaxRecords int                   `json:"max_records"`
+	Cursor     string                `json:"cursor"`
+	aerospike.ReadOptions
+	aerospike.ScanThrottle
+	aerospike.RecordOrder
 }
 
 func (r *Registry) handleQueryRecords(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -530,7 +891,19 @@ func (r *Registry) handleQueryRecords(ctx context.Context, args json.RawMessage)
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
-	return r.client.QueryRecords(ctx, a.Namespace, a.SetName, a.IndexName, a.Filter, a.MaxRecords)
+	if err := a.ReadOptions.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.ScanThrottle.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.RecordOrder.Validate(); err != nil {
+		return nil, err
+	}
+	ctx = aerospike.WithScanThrottle(aerospike.WithReadOptions(ctx, a.ReadOptions), a.ScanThrottle)
+	ctx = aerospike.WithRecordOrder(ctx, a.RecordOrder)
+	page, err := r.client.QueryRecords(ctx, a.Namespace, a.SetName, a.IndexName, a.Filter, a.MaxRecords, a.Cursor)
+	return pageResult(page, err, a.Cursor)
 }
 
 type scanSetArgs struct {
@@ -539,6 +912,10 @@ type scanSetArgs struct {
 	Bins          []string `json:"bins"`
 	MaxRecords    int      `json:"max_records"`
 	SamplePercent int      `json:"sample_percent"`
+	Cursor        string   `json:"cursor"`
+	ConfirmLarge  bool     `json:"confirm_large_scan"`
+	aerospike.ScanThrottle
+	aerospike.RecordOrder
 }
 
 func (r *Registry) handleScanSet(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -546,15 +923,131 @@ func (r *Registry) handleScanSet(ctx context.Context, args json.RawMessage) (int
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
-	return r.client.ScanSet(ctx, a.Namespace, a.SetName, a.Bins, a.MaxRecords, a.SamplePercent)
+	if err := a.ScanThrottle.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.RecordOrder.Validate(); err != nil {
+		return nil, err
+	}
+	objects, err := r.client.CheckLargeScan(ctx, a.Namespace, a.SetName, a.ConfirmLarge)
+	if err != nil {
+		return nil, err
+	}
+	if objects > 0 && r.audit != nil {
+		r.audit.LogOverride(ctx, audit.CategoryRead, "scan_set", a.Namespace, a.SetName, map[string]interface{}{
+			"confirm_large_scan": true,
+			"object_count":       objects,
+			"threshold":          r.config.ScanLimits.ConfirmAboveRecords,
+		})
+	}
+	ctx = aerospike.WithRecordOrder(aerospike.WithScanThrottle(ctx, a.ScanThrottle), a.RecordOrder)
+	page, err := r.client.ScanSet(ctx, a.Namespace, a.SetName, a.Bins, a.MaxRecords, a.SamplePercent, a.Cursor)
+	return pageResult(page, err, a.Cursor)
+}
+
+type aggregateSimpleArgs struct {
+	Namespace        string                 `json:"namespace"`
+	SetName          string                 `json:"set_name"`
+	BinName          string                 `json:"bin_name"`
+	Filter           *aerospike.QueryFilter `json:"filter"`
+	FilterExpression interface{}            `json:"filter_expression"`
+	aerospike.ReadOptions
+	aerospike.ScanThrottle
+}
+
+func (r *Registry) handleAggregateSimple(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a aggregateSimpleArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if err := a.ReadOptions.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.ScanThrottle.Validate(); err != nil {
+		return nil, err
+	}
+	ctx = aerospike.WithScanThrottle(aerospike.WithReadOptions(ctx, a.ReadOptions), a.ScanThrottle)
+	return r.client.Aggregate(ctx, a.Namespace, a.SetName, a.BinName, a.Filter, a.FilterExpression)
+}
+
+type sqlQueryArgs struct {
+	SQL     string `json:"sql"`
+	Explain bool   `json:"explain"`
+	aerospike.ReadOptions
+	aerospike.ScanThrottle
+}
+
+func (r *Registry) handleSQLQuery(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a sqlQueryArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if err := a.ReadOptions.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.ScanThrottle.Validate(); err != nil {
+		return nil, err
+	}
+	if a.Explain {
+		plan, err := r.client.ExplainSQL(ctx, a.SQL)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"plan": plan}, nil
+	}
+	ctx = aerospike.WithScanThrottle(aerospike.WithReadOptions(ctx, a.ReadOptions), a.ScanThrottle)
+	return r.client.SQLQuery(ctx, a.SQL)
+}
+
+// PartialRecords is the result of a scan, query, or batch that returned
+// only some of its records: a scan or query that ran out of time, holding
+// the records read before its deadline, one stopped at max_result_bytes, or
+// a result truncated to fit max_response_bytes, with a cursor to continue
+// from.
+type PartialRecords struct {
+	Records         []*aerospike.Record `json:"records"`
+	Partial         bool                `json:"partial,omitempty"`
+	Reason          string              `json:"reason,omitempty"`
+	Truncated       bool                `json:"truncated"`
+	RecordsReturned int                 `json:"records_returned"`
+	EstimatedTotal  int                 `json:"estimated_total,omitempty"`
+	Cursor          string              `json:"cursor,omitempty"`
+}
+
+// pageResult returns the records of a page of a scan, query, or batch. A
+// page that ran out of time, was truncated, or continues from a cursor is
+// returned with its metadata; a complete first page is returned as the
+// plain list of records.
+func pageResult(page *aerospike.RecordPage, err error, cursor string) (interface{}, error) {
+	if errors.Is(err, aerospike.ErrIncomplete) || errors.Is(err, aerospike.ErrResultTooLarge) {
+		return &PartialRecords{Records: page.Records, Partial: true, Reason: err.Error(), RecordsReturned: len(page.Records), Cursor: page.Cursor}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !page.Truncated && cursor == "" {
+		return page.Records, nil
+	}
+	return &PartialRecords{
+		Records:         page.Records,
+		Truncated:       page.Truncated,
+		RecordsReturned: len(page.Records),
+		EstimatedTotal:  page.EstimatedTotal,
+		Cursor:          page.Cursor,
+	}, nil
 }
 
 type putRecordArgs struct {
-	Namespace string                 `json:"namespace"`
-	SetName   string                 `json:"set_name"`
-	Key       string                 `json:"key"`
-	Bins      map[string]interface{} `json:"bins"`
-	TTL       int                    `json:"ttl"`
+	Namespace  string                 `json:"namespace"`
+	SetName    string                 `json:"set_name"`
+	Key        string                 `json:"key"`
+	Bins       map[string]interface{} `json:"bins"`
+	DeleteBins []string               `json:"delete_bins"`
+	TTL        int                    `json:"ttl"`
+	Mode       aerospike.WriteMode    `json:"mode"`
+	DryRun     bool                   `json:"dry_run"`
+
+	PreserveFloat *bool `json:"preserve_float"`
 }
 
 func (r *Registry) handlePutRecord(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -562,7 +1055,14 @@ func (r *Registry) handlePutRecord(ctx context.Context, args json.RawMessage) (i
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
-	if err := r.client.PutRecord(ctx, a.Namespace, a.SetName, a.Key, a.Bins, a.TTL); err != nil {
+	if err := a.Mode.Validate(); err != nil {
+		return nil, err
+	}
+	ctx = aerospike.WithWriteMode(aerospike.WithPreserveFloat(ctx, a.PreserveFloat), a.Mode)
+	if r.dryRun(a.DryRun) {
+		return r.client.DryRunPut(ctx, a.Namespace, a.SetName, a.Key, a.Bins, a.DeleteBins, a.TTL)
+	}
+	if err := r.client.PutRecord(ctx, a.Namespace, a.SetName, a.Key, a.Bins, a.DeleteBins, a.TTL); err != nil {
 		return nil, err
 	}
 	return map[string]string{"status": "ok"}, nil
@@ -572,6 +1072,7 @@ type deleteRecordArgs struct {
 	Namespace string `json:"namespace"`
 	SetName   string `json:"set_name"`
 	Key       string `json:"key"`
+	DryRun    bool   `json:"dry_run"`
 }
 
 func (r *Registry) handleDeleteRecord(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -579,15 +1080,23 @@ func (r *Registry) handleDeleteRecord(ctx context.Context, args json.RawMessage)
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
+	if r.dryRun(a.DryRun) {
+		return r.client.DryRunDelete(ctx, a.Namespace, a.SetName, a.Key)
+	}
 	existed, err := r.client.DeleteRecord(ctx, a.Namespace, a.SetName, a.Key)
 	if err != nil {
 		return nil, err
 	}
-	return map[string]interface{}{"existed": existed}, nil
+	return map[string]interface{}{"found": existed}, nil
 }
 
 type batchWriteArgs struct {
-	Operations []aerospike.BatchWriteRequest `json:"operations"`
+	Operations       []aerospike.BatchWriteRequest `json:"operations"`
+	Mode             aerospike.WriteMode           `json:"mode"`
+	StopOnFirstError bool                          `json:"stop_on_first_error"`
+	DryRun           bool                          `json:"dry_run"`
+
+	PreserveFloat *bool `json:"preserve_float"`
 }
 
 func (r *Registry) handleBatchWrite(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -595,7 +1104,14 @@ func (r *Registry) handleBatchWrite(ctx context.Context, args json.RawMessage) (
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
-	return r.client.BatchWrite(ctx, a.Operations)
+	if err := a.Mode.Validate(); err != nil {
+		return nil, err
+	}
+	ctx = aerospike.WithWriteMode(aerospike.WithPreserveFloat(ctx, a.PreserveFloat), a.Mode)
+	if r.dryRun(a.DryRun) {
+		return r.client.DryRunBatchWrite(ctx, a.Operations)
+	}
+	return r.client.BatchWrite(ctx, a.Operations, a.StopOnFirstError)
 }
 
 type operateArgs struct {
@@ -604,6 +1120,7 @@ type operateArgs struct {
 	Key        string                     `json:"key"`
 	Operations []aerospike.OperateRequest `json:"operations"`
 	TTL        int                        `json:"ttl"`
+	DryRun     bool                       `json:"dry_run"`
 }
 
 func (r *Registry) handleOperate(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -611,9 +1128,18 @@ func (r *Registry) handleOperate(ctx context.Context, args json.RawMessage) (int
 	if err := json.Unmarshal(args, &a); err != nil {
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
+	if r.dryRun(a.DryRun) {
+		return r.client.DryRunOperate(ctx, a.Namespace, a.SetName, a.Key, a.Operations, a.TTL)
+	}
 	return r.client.Operate(ctx, a.Namespace, a.SetName, a.Key, a.Operations, a.TTL)
 }
 
+// dryRun returns true if a write should only be reported. The dry_run
+// setting applies to every call; callers can also request it per call.
+func (r *Registry) dryRun(requested bool) bool {
+	return requested || r.config.DryRun
+}
+
 func (r *Registry) handleClusterInfo(ctx context.Context, args json.RawMessage) (interface{}, error) {
 	return r.client.GetClusterInfo(ctx)
 }
@@ -653,6 +1179,8 @@ type createIndexArgs struct {
 	BinName        string `json:"bin_name"`
 	IndexType      string `json:"index_type"`
 	CollectionType string `json:"collection_type"`
+
+	Ctx []aerospike.CDTContextStep `json:"ctx"`
 }
 
 func (r *Registry) handleCreateIndex(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -661,13 +1189,14 @@ func (r *Registry) handleCreateIndex(ctx context.Context, args json.RawMessage)
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
 
+	start := time.Now()
 	err := r.client.CreateIndex(ctx, a.Namespace, a.SetName, a.IndexName, a.BinName,
-		aerospike.IndexType(a.IndexType), aerospike.CollectionType(a.CollectionType))
+		aerospike.IndexType(a.IndexType), aerospike.CollectionType(a.CollectionType), a.Ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]string{"status": "ok", "index": a.IndexName}, nil
+	return map[string]interface{}{"status": "ok", "index": a.IndexName, "elapsed_ms": time.Since(start).Milliseconds()}, nil
 }
 
 type dropIndexArgs struct {
@@ -698,6 +1227,7 @@ type truncateSetArgs struct {
 	SetName            string `json:"set_name"`
 	Confirm            bool   `json:"confirm"`
 	ConfirmDestructive bool   `json:"confirm_destructive"`
+	PreviewOnly        bool   `json:"preview_only"`
 }
 
 func (r *Registry) handleTruncateSet(ctx context.Context, args json.RawMessage) (interface{}, error) {
@@ -706,15 +1236,75 @@ func (r *Registry) handleTruncateSet(ctx context.Context, args json.RawMessage)
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
 
+	if a.PreviewOnly {
+		preview, err := r.client.PreviewTruncate(ctx, a.Namespace, a.SetName)
+		if err != nil {
+			return nil, err
+		}
+		preview.PreviewOnly = true
+		return preview, nil
+	}
+
 	if !a.Confirm || !a.ConfirmDestructive {
 		return nil, fmt.Errorf("truncate_set requires both confirm=true and confirm_destructive=true")
 	}
 
+	// Measure what will be removed before it is gone
+	preview, err := r.client.PreviewTruncate(ctx, a.Namespace, a.SetName)
+	if err != nil {
+		return nil, fmt.Errorf("previewing truncation: %w", err)
+	}
 	if err := r.client.TruncateSet(ctx, a.Namespace, a.SetName); err != nil {
 		return nil, err
 	}
 
-	return map[string]string{"status": "ok", "truncated": fmt.Sprintf("%s.%s", a.Namespace, a.SetName)}, nil
+	return map[string]interface{}{
+		"status":    "ok",
+		"truncated": fmt.Sprintf("%s.%s", a.Namespace, a.SetName),
+		"records":   preview.Records,
+		"bytes":     preview.Bytes,
+	}, nil
+}
+
+type namespaceConfigArgs struct {
+	Namespace string `json:"namespace"`
+	SetName   string `json:"set_name"`
+	aerospike.ConfigChange
+}
+
+func (r *Registry) handleGetNamespaceConfig(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a namespaceConfigArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	return r.client.GetNamespaceConfig(ctx, a.Namespace, a.SetName)
+}
+
+func (r *Registry) handleSetNamespaceConfig(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a namespaceConfigArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	start := time.Now()
+	before, err := r.client.SetNamespaceConfig(ctx, a.Namespace, a.SetName, a.ConfigChange)
+	if r.audit != nil && before != nil {
+		r.audit.LogAdmin(ctx, "set_namespace_config", map[string]interface{}{
+			"namespace": a.Namespace,
+			"set":       a.SetName,
+			"before":    before,
+			"change":    a.ConfigChange,
+		}, time.Since(start), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := r.client.GetNamespaceConfig(ctx, a.Namespace, a.SetName)
+	if err != nil {
+		return nil, fmt.Errorf("reading config after the change: %w", err)
+	}
+	return map[string]interface{}{"status": "ok", "before": before, "after": after}, nil
 }
 
 // ============================================================================
@@ -736,11 +1326,12 @@ func (r *Registry) handleRegisterUDF(ctx context.Context, args json.RawMessage)
 		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
 
+	start := time.Now()
 	if err := r.client.RegisterUDF(ctx, a.ModuleName, a.Code); err != nil {
 		return nil, err
 	}
 
-	return map[string]string{"status": "ok", "module": a.ModuleName}, nil
+	return map[string]interface{}{"status": "ok", "module": a.ModuleName, "elapsed_ms": time.Since(start).Milliseconds()}, nil
 }
 
 type removeUDFArgs struct {
@@ -758,11 +1349,12 @@ func (r *Registry) handleRemoveUDF(ctx context.Context, args json.RawMessage) (i
 		return nil, fmt.Errorf("remove_udf requires confirm=true")
 	}
 
+	start := time.Now()
 	if err := r.client.RemoveUDF(ctx, a.ModuleName); err != nil {
 		return nil, err
 	}
 
-	return map[string]string{"status": "ok", "removed": a.ModuleName}, nil
+	return map[string]interface{}{"status": "ok", "removed": a.ModuleName, "elapsed_ms": time.Since(start).Milliseconds()}, nil
 }
 
 type executeUDFArgs struct {
@@ -787,3 +1379,42 @@ func (r *Registry) handleExecuteUDF(ctx context.Context, args json.RawMessage) (
 
 	return map[string]interface{}{"result": result}, nil
 }
+
+type benchmarkArgs struct {
+	Operations  *int     `json:"operations"`
+	Concurrency *int     `json:"concurrency"`
+	ReadRatio   *float64 `json:"read_ratio"`
+	RecordBytes *int     `json:"record_bytes"`
+	Keys        *int     `json:"keys"`
+}
+
+// options returns the workload the arguments describe, with defaults for
+// those left out. The default key count never exceeds the operations.
+func (a benchmarkArgs) options() aerospike.BenchmarkOptions {
+	opts := aerospike.BenchmarkOptions{Operations: 1000, Concurrency: 4, ReadRatio: 0.5, RecordBytes: 100, Keys: 100}
+	if a.Operations != nil {
+		opts.Operations = *a.Operations
+		opts.Keys = min(opts.Keys, opts.Operations)
+	}
+	if a.Concurrency != nil {
+		opts.Concurrency = *a.Concurrency
+	}
+	if a.ReadRatio != nil {
+		opts.ReadRatio = *a.ReadRatio
+	}
+	if a.RecordBytes != nil {
+		opts.RecordBytes = *a.RecordBytes
+	}
+	if a.Keys != nil {
+		opts.Keys = *a.Keys
+	}
+	return opts
+}
+
+func (r *Registry) handleBenchmark(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a benchmarkArgs
+	if err := json.Unmarshal(args, &a); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	return r.client.Benchmark(ctx, a.options())
+}
@@ -4,9 +4,14 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/dringdahl0320/aerospike-mcp-server/internal/aerospike"
 	"github.com/dringdahl0320/aerospike-mcp-server/pkg/config"
 )
 
@@ -307,6 +312,7 @@ func TestSchemaToolsAlwaysPresent(t *testing.T) {
 		"describe_namespace",
 		"list_sets",
 		"describe_set",
+		"refresh_metadata",
 	}
 
 	for _, role := range []config.Role{config.RoleReadOnly, config.RoleReadWrite, config.RoleAdmin} {
@@ -332,3 +338,251 @@ func TestSchemaToolsAlwaysPresent(t *testing.T) {
 		})
 	}
 }
+
+func TestCallEnforcesRequestRole(t *testing.T) {
+	cfg := &config.Config{Role: config.RoleReadOnly}
+	r := &Registry{
+		config: cfg,
+		tools:  make(map[string]ToolHandler),
+		roles:  make(map[string]config.Role),
+	}
+
+	noop := func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		return "ok", nil
+	}
+	r.tools["get_record"] = noop
+	r.tools["put_record"] = noop
+	r.tools["create_index"] = noop
+	r.requireRole(config.RoleReadWrite, "put_record")
+	r.requireRole(config.RoleAdmin, "create_index")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		tool    string
+		wantErr bool
+	}{
+		{"process role read", context.Background(), "get_record", false},
+		{"process role write", context.Background(), "put_record", true},
+		{"client read-write write", config.WithRole(context.Background(), config.RoleReadWrite), "put_record", false},
+		{"client read-write admin", config.WithRole(context.Background(), config.RoleReadWrite), "create_index", true},
+		{"client admin admin", config.WithRole(context.Background(), config.RoleAdmin), "create_index", false},
+		{"unknown tool", context.Background(), "missing", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Call(tt.ctx, tt.tool, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Call(%s) error = %v, wantErr %v", tt.tool, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCallReadOnly(t *testing.T) {
+	cfg := &config.Config{Role: config.RoleAdmin}
+	r := &Registry{
+		config: cfg,
+		tools:  make(map[string]ToolHandler),
+		roles:  make(map[string]config.Role),
+	}
+	noop := func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		return "ok", nil
+	}
+	r.tools["get_record"] = noop
+	r.tools["put_record"] = noop
+	r.requireRole(config.RoleReadWrite, "put_record")
+
+	cfg.SetReadOnly(true)
+	if _, err := r.Call(context.Background(), "get_record", nil); err != nil {
+		t.Errorf("Expected reads to be allowed while read-only, got %v", err)
+	}
+	_, err := r.Call(context.Background(), "put_record", nil)
+	var roleErr *config.RoleError
+	if !errors.As(err, &roleErr) || !roleErr.ReadOnly || !strings.Contains(err.Error(), "read-only mode") {
+		t.Errorf("Expected a read-only mode error, got %v", err)
+	}
+
+	cfg.SetReadOnly(false)
+	if _, err := r.Call(context.Background(), "put_record", nil); err != nil {
+		t.Errorf("Expected writes to be allowed again, got %v", err)
+	}
+}
+
+func TestCallEnforcesNamespaceRole(t *testing.T) {
+	cfg := &config.Config{
+		Role: config.RoleAdmin,
+		Namespaces: map[string]config.NamespacePolicy{
+			"prod": {Role: config.RoleReadOnly},
+			"test": {Role: config.RoleReadWrite},
+		},
+	}
+	r := &Registry{
+		config: cfg,
+		tools:  make(map[string]ToolHandler),
+		roles:  make(map[string]config.Role),
+	}
+
+	noop := func(ctx context.Context, args json.RawMessage) (interface{}, error) {
+		return "ok", nil
+	}
+	r.tools["get_record"] = noop
+	r.tools["put_record"] = noop
+	r.tools["create_index"] = noop
+	r.tools["register_udf"] = noop
+	r.requireRole(config.RoleReadWrite, "put_record")
+	r.requireRole(config.RoleAdmin, "create_index", "register_udf")
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    string
+		wantErr bool
+	}{
+		{"read in read-only namespace", "get_record", `{"namespace":"prod"}`, false},
+		{"write in read-only namespace", "put_record", `{"namespace":"prod"}`, true},
+		{"write in read-write namespace", "put_record", `{"namespace":"test"}`, false},
+		{"admin in read-write namespace", "create_index", `{"namespace":"test"}`, true},
+		{"admin in unrestricted namespace", "create_index", `{"namespace":"other"}`, false},
+		{"tool without namespace", "register_udf", `{"module_name":"m"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Call(context.Background(), tt.tool, json.RawMessage(tt.args))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Call(%s) error = %v, wantErr %v", tt.tool, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadOptionArguments(t *testing.T) {
+	r := &Registry{config: &config.Config{Role: config.RoleReadOnly}}
+	for _, def := range r.List() {
+		switch def.Name {
+		case "get_record", "batch_get", "query_records":
+			for _, name := range []string{"read_mode_ap", "read_mode_sc", "replica"} {
+				if _, ok := def.InputSchema.Properties[name]; !ok {
+					t.Errorf("Expected %s to accept %s", def.Name, name)
+				}
+			}
+		}
+	}
+
+	_, err := r.handleGetRecord(context.Background(), json.RawMessage(`{"namespace":"test","key":"k","replica":"nearest"}`))
+	if err == nil || !strings.Contains(err.Error(), "invalid replica") {
+		t.Errorf("Expected invalid replica error, got %v", err)
+	}
+}
+
+func TestCallPolicyArgument(t *testing.T) {
+	r := NewRegistry(nil, &config.Config{Role: config.RoleAdmin})
+	for _, def := range r.ListForRole(config.RoleAdmin) {
+		if _, ok := def.InputSchema.Properties["policy"]; ok != policyTools[def.Name] {
+			t.Errorf("Expected policy argument on %s to be %v", def.Name, policyTools[def.Name])
+		}
+	}
+
+	tests := []struct {
+		args    string
+		wantErr bool
+	}{
+		{`{"namespace":"test"}`, false},
+		{`{"policy":{"timeout_ms":250,"max_retries":0,"read_mode":"linearize","durable_delete":true,"send_key":true}}`, false},
+		{`{"policy":{"timeout_ms":0}}`, true},
+		{`{"policy":{"max_retries":11}}`, true},
+		{`{"policy":{"read_mode":"quorum"}}`, true},
+		{`{"policy":"fast"}`, true},
+	}
+	for _, tt := range tests {
+		if _, err := argsPolicy(json.RawMessage(tt.args)); (err != nil) != tt.wantErr {
+			t.Errorf("argsPolicy(%s) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+		}
+	}
+
+	_, err := r.Call(context.Background(), "get_record", json.RawMessage(`{"namespace":"test","key":"k","policy":{"max_retries":-1}}`))
+	if err == nil || !strings.Contains(err.Error(), "policy.max_retries") {
+		t.Errorf("Expected invalid policy error, got %v", err)
+	}
+}
+
+func TestListForRole(t *testing.T) {
+	r := &Registry{config: &config.Config{Role: config.RoleReadOnly}}
+
+	if len(r.ListForRole(config.RoleAdmin)) <= len(r.List()) {
+		t.Error("Expected admin role to list more tools than the process-wide read-only role")
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		global    bool
+		requested bool
+		expected  bool
+	}{
+		{"disabled", false, false, false},
+		{"per call", false, true, true},
+		{"global", true, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Registry{config: &config.Config{DryRun: tt.global}}
+			if got := r.dryRun(tt.requested); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPageResult(t *testing.T) {
+	records := []*aerospike.Record{{Key: "1"}, {Key: "2"}}
+
+	result, err := pageResult(&aerospike.RecordPage{Records: records}, fmt.Errorf("scan: %w", aerospike.ErrIncomplete), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	partial, ok := result.(*PartialRecords)
+	if !ok || !partial.Partial || len(partial.Records) != 2 {
+		t.Errorf("Expected 2 partial records, got %+v", result)
+	}
+
+	result, _ = pageResult(&aerospike.RecordPage{Records: records[:1], Cursor: "next"}, fmt.Errorf("batch get: %w", aerospike.ErrResultTooLarge), "")
+	if partial, ok := result.(*PartialRecords); !ok || !partial.Partial || len(partial.Records) != 1 || partial.Cursor != "next" {
+		t.Errorf("Expected a partial record with a cursor, got %+v", result)
+	}
+
+	if result, _ := pageResult(&aerospike.RecordPage{Records: records, Cursor: "next"}, nil, ""); len(result.([]*aerospike.Record)) != 2 {
+		t.Errorf("Expected complete records unchanged, got %+v", result)
+	}
+	if _, err := pageResult(nil, errors.New("boom"), ""); err == nil {
+		t.Error("Expected other errors to be returned")
+	}
+
+	tests := []struct {
+		name   string
+		page   *aerospike.RecordPage
+		cursor string
+	}{
+		{"truncated", &aerospike.RecordPage{Records: records, Truncated: true, EstimatedTotal: 10, Cursor: "next"}, ""},
+		{"continued", &aerospike.RecordPage{Records: records, EstimatedTotal: 10, Cursor: "next"}, "previous"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := pageResult(tt.page, nil, tt.cursor)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			page, ok := result.(*PartialRecords)
+			if !ok {
+				t.Fatalf("Expected *PartialRecords, got %T", result)
+			}
+			if page.Truncated != tt.page.Truncated || page.RecordsReturned != 2 || page.EstimatedTotal != 10 || page.Cursor != "next" {
+				t.Errorf("Expected page metadata, got %+v", page)
+			}
+		})
+	}
+}
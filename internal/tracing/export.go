@@ -0,0 +1,80 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Export defaults.
+const (
+	defaultFlushInterval = 5 * time.Second
+	exportTimeout        = 10 * time.Second
+)
+
+// Config configures a Tracer.
+type Config struct {
+	// Endpoint is the OTLP/HTTP collector URL. /v1/traces is appended if it
+	// has no path.
+	Endpoint string
+
+	// Headers are sent with every export, for example for authentication.
+	Headers map[string]string
+
+	// ServiceName and ServiceVersion identify this process in traces.
+	ServiceName    string
+	ServiceVersion string
+
+	// SampleRatio is the fraction of new traces recorded. Traces continued
+	// from a caller follow the caller's sampling decision.
+	SampleRatio float64
+
+	// FlushInterval is how often queued spans are exported.
+	FlushInterval time.Duration
+}
+
+// New creates a tracer exporting spans to cfg.Endpoint with the OTLP/HTTP
+// exporter, in batches.
+func New(cfg Config) (*Tracer, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid tracing endpoint: %q", cfg.Endpoint)
+	}
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = "/v1/traces"
+	}
+
+	interval := cfg.FlushInterval
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpointURL(endpoint.String()),
+		otlptracehttp.WithTimeout(exportTimeout),
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating trace exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		String("service.name", cfg.ServiceName),
+		String("service.version", cfg.ServiceVersion),
+	)
+	return newTracer(cfg.SampleRatio,
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exporter, sdktrace.WithBatchTimeout(interval)),
+	), nil
+}
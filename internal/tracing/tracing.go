@@ -0,0 +1,203 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing records OpenTelemetry trace spans and exports them to a
+// collector over OTLP/HTTP. It is a thin layer over the OpenTelemetry SDK
+// that keeps tracing optional: a nil *Tracer or *Span ignores all calls.
+// Trace context is propagated in the W3C traceparent format.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanKind describes a span's role in a trace.
+type SpanKind = trace.SpanKind
+
+// Span kinds.
+const (
+	KindInternal = trace.SpanKindInternal
+	KindServer   = trace.SpanKindServer
+	KindClient   = trace.SpanKindClient
+)
+
+// Attribute is a key-value pair describing a span.
+type Attribute = attribute.KeyValue
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return attribute.String(key, value)
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return attribute.Int(key, value)
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute {
+	return attribute.Bool(key, value)
+}
+
+// SpanContext identifies a span within a trace.
+type SpanContext = trace.SpanContext
+
+// propagator reads and writes W3C traceparent headers.
+var propagator = propagation.TraceContext{}
+
+// ParseTraceparent parses a W3C traceparent header value.
+func ParseTraceparent(value string) (SpanContext, error) {
+	ctx := propagator.Extract(context.Background(), propagation.MapCarrier{"traceparent": value})
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return sc, fmt.Errorf("invalid traceparent: %q", value)
+	}
+	return sc, nil
+}
+
+// Traceparent formats a span context as a W3C traceparent header value.
+func Traceparent(sc SpanContext) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier)
+	return carrier.Get("traceparent")
+}
+
+type spanContextKey struct{}
+
+// ContextWithRemoteParent returns ctx carrying a span context received from
+// a caller, which the next span started by a Tracer continues.
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Extract returns ctx carrying the parent described by a traceparent header
+// value. ctx is returned unchanged if the value is empty or invalid.
+func Extract(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	sc, err := ParseTraceparent(traceparent)
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteParent(ctx, sc)
+}
+
+// SpanFromContext returns the current span, or nil if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// Span is a timed operation within a trace. A nil *Span ignores all calls,
+// so callers needn't check whether tracing is enabled.
+type Span struct {
+	tracer *Tracer
+	span   trace.Span
+}
+
+// SpanContext returns the span's identity.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.span.SpanContext()
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// SetError marks the span as failed with err. A nil err is ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End finishes the span and queues it for export if it is sampled.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// Tracer starts spans and sends the sampled ones to its exporter.
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// newTracer creates a tracer that records new traces with sampleRatio and
+// follows the sampling decision of traces continued from a caller.
+func newTracer(sampleRatio float64, opts ...sdktrace.TracerProviderOption) *Tracer {
+	opts = append(opts, sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))))
+	provider := sdktrace.NewTracerProvider(opts...)
+	return &Tracer{provider: provider, tracer: provider.Tracer(instrumentationName)}
+}
+
+// instrumentationName identifies the spans this package creates.
+const instrumentationName = "github.com/dringdahl0320/aerospike-mcp-server"
+
+// Start begins a span. It continues the span in ctx, or the remote parent
+// added by Extract, and otherwise starts a new trace that is sampled with
+// the tracer's sample ratio.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	return t.start(ctx, name, kind, attrs)
+}
+
+// start implements Start, with extra options for the underlying span.
+func (t *Tracer) start(ctx context.Context, name string, kind SpanKind, attrs []Attribute, opts ...trace.SpanStartOption) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	opts = append(opts, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	ctx, s := t.tracer.Start(ctx, name, opts...)
+	span := &Span{tracer: t, span: s}
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// Shutdown exports any spans still queued. The tracer must not be used
+// afterwards.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	return t.provider.Shutdown(ctx)
+}
+
+// Start begins a child of the span in ctx. Without a span in ctx, tracing is
+// off for the request and Start returns ctx and a nil span.
+func Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.Start(ctx, name, kind, attrs...)
+}
+
+// Record adds a finished child span to the span in ctx for an operation
+// that began at start and has just ended with err.
+func Record(ctx context.Context, name string, kind SpanKind, start time.Time, err error, attrs ...Attribute) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return
+	}
+	_, span := parent.tracer.start(ctx, name, kind, attrs, trace.WithTimestamp(start))
+	span.SetError(err)
+	span.End()
+}
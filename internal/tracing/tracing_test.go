@@ -0,0 +1,176 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		sampled bool
+		wantErr bool
+	}{
+		{"sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, false},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", false, false},
+		{"future version with extra fields", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true, false},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, true},
+		{"short span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01", false, true},
+		{"not hex", "00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01", false, true},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, true},
+		{"empty", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceparent(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTraceparent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && sc.IsSampled() != tt.sampled {
+				t.Errorf("Expected sampled=%v, got %v", tt.sampled, sc.IsSampled())
+			}
+		})
+	}
+
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, _ := ParseTraceparent(value)
+	if got := Traceparent(sc); got != value {
+		t.Errorf("Expected %s, got %s", value, got)
+	}
+}
+
+// parentOf returns the span ID of span's parent.
+func parentOf(span *Span) string {
+	return span.span.(sdktrace.ReadOnlySpan).Parent().SpanID().String()
+}
+
+func TestStart(t *testing.T) {
+	tracer := newTracer(1)
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, root := tracer.Start(ContextWithRemoteParent(context.Background(), remote), "root", KindServer)
+	if root.SpanContext().TraceID() != remote.TraceID() || parentOf(root) != remote.SpanID().String() {
+		t.Error("Expected root span to continue the remote trace")
+	}
+
+	_, child := Start(ctx, "child", KindInternal)
+	if child.SpanContext().TraceID() != remote.TraceID() || parentOf(child) != root.SpanContext().SpanID().String() {
+		t.Error("Expected child span to be a child of the root span")
+	}
+
+	if _, span := Start(context.Background(), "orphan", KindInternal); span != nil {
+		t.Error("Expected no span without a parent")
+	}
+
+	unsampled := newTracer(0)
+	if _, span := unsampled.Start(context.Background(), "root", KindServer); span.SpanContext().IsSampled() {
+		t.Error("Expected new trace not to be sampled with ratio 0")
+	}
+
+	// A caller's sampling decision is kept
+	if _, span := unsampled.Start(ContextWithRemoteParent(context.Background(), remote), "root", KindServer); !span.SpanContext().IsSampled() {
+		t.Error("Expected a sampled remote trace to stay sampled")
+	}
+}
+
+func TestNilSpan(t *testing.T) {
+	var tracer *Tracer
+	ctx, span := tracer.Start(context.Background(), "root", KindServer)
+	span.SetAttributes(String("k", "v"))
+	span.SetError(errors.New("failed"))
+	span.End()
+	Record(ctx, "op", KindClient, time.Now(), nil)
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*coltracepb.ExportTraceServiceRequest
+	var header string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("Expected path /v1/traces, got %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		req := new(coltracepb.ExportTraceServiceRequest)
+		if err := proto.Unmarshal(body, req); err != nil {
+			t.Errorf("Invalid OTLP request: %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		header = r.Header.Get("X-Api-Key")
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	tracer, err := New(Config{
+		Endpoint:    collector.URL,
+		Headers:     map[string]string{"X-Api-Key": "secret"},
+		ServiceName: "test-service",
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, root := tracer.Start(context.Background(), "tools/call", KindServer, String("mcp.method.name", "tools/call"))
+	Record(ctx, "aerospike get", KindClient, time.Now().Add(-time.Millisecond), errors.New("timeout"), Int("attempts", 2))
+	root.End()
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 || header != "secret" {
+		t.Fatalf("Expected one export with headers, got %d (header %q)", len(requests), header)
+	}
+	resource := requests[0].ResourceSpans[0]
+	found := false
+	for _, attr := range resource.Resource.Attributes {
+		found = found || (attr.Key == "service.name" && attr.Value.GetStringValue() == "test-service")
+	}
+	if !found {
+		t.Error("Expected service.name resource attribute")
+	}
+	spans := resource.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	client, server := spans[0], spans[1]
+	if client.Name != "aerospike get" || client.Kind != tracepb.Span_SPAN_KIND_CLIENT {
+		t.Errorf("Unexpected client span %+v", client)
+	}
+	if string(client.ParentSpanId) != string(server.SpanId) || string(client.TraceId) != string(server.TraceId) {
+		t.Error("Expected client span to be a child of the server span")
+	}
+	if client.Status == nil || client.Status.Code != tracepb.Status_STATUS_CODE_ERROR || client.Status.Message != "timeout" {
+		t.Errorf("Expected error status, got %+v", client.Status)
+	}
+	if client.Attributes[0].Value.GetIntValue() != 2 {
+		t.Error("Expected the integer attribute")
+	}
+	if len(server.ParentSpanId) != 0 {
+		t.Error("Expected root span to have no parent")
+	}
+}
@@ -5,10 +5,22 @@
 package config
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // Host represents an Aerospike cluster node.
@@ -20,11 +32,27 @@ type Host struct {
 // TLSConfig holds TLS configuration options.
 type TLSConfig struct {
 	Enabled  bool   `json:"enabled"`
-	CAFile   string `json:"ca_file,omitempty"`
+	CAFile   string `json:"ca_file,omitempty"` // for server_tls, the CA that issues client certificates
 	CertFile string `json:"cert_file,omitempty"`
 	KeyFile  string `json:"key_file,omitempty"`
+
+	// For server_tls: whether clients must present a certificate signed by
+	// CAFile, "require" (default when CAFile is set) or "optional".
+	ClientAuth string `json:"client_auth,omitempty"`
+
+	// PEM material resolved from a secrets provider. When set, it takes
+	// precedence over the corresponding file.
+	CAPEM   []byte `json:"-"`
+	CertPEM []byte `json:"-"`
+	KeyPEM  []byte `json:"-"`
 }
 
+// Client certificate requirements for server_tls.
+const (
+	ClientAuthRequire  = "require"
+	ClientAuthOptional = "optional"
+)
+
 // Role defines the permission level for database operations.
 type Role string
 
@@ -34,37 +62,470 @@ const (
 	RoleAdmin     Role = "admin"
 )
 
+// Valid returns true if r is a known role.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleReadOnly, RoleReadWrite, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Rank orders roles by privilege; unknown roles rank lowest.
+func (r Role) Rank() int {
+	switch r {
+	case RoleReadOnly:
+		return 1
+	case RoleReadWrite:
+		return 2
+	case RoleAdmin:
+		return 3
+	}
+	return 0
+}
+
+// CanWrite returns true if r permits write operations.
+func (r Role) CanWrite() bool {
+	return r == RoleReadWrite || r == RoleAdmin
+}
+
+// CanAdmin returns true if r permits administrative operations.
+func (r Role) CanAdmin() bool {
+	return r == RoleAdmin
+}
+
+// Replica policies: which node serves a read.
+const (
+	ReplicaMaster       = "master"        // the node holding the master partition
+	ReplicaMasterProles = "master_proles" // master and replica nodes in turn
+	ReplicaRandom       = "random"        // any node in the cluster
+	ReplicaSequence     = "sequence"      // the master first, then replicas on retry
+	ReplicaPreferRack   = "prefer_rack"   // nodes on rack_ids first
+)
+
+// Read modes for AP and strong consistency namespaces.
+const (
+	ReadModeAPOne              = "one"               // read from one replica
+	ReadModeAPAll              = "all"               // read from all replicas, returning the newest
+	ReadModeSCSession          = "session"           // this client sees its own writes
+	ReadModeSCLinearize        = "linearize"         // every client sees the latest write
+	ReadModeSCAllowReplica     = "allow_replica"     // any replica may serve the read
+	ReadModeSCAllowUnavailable = "allow_unavailable" // replicas may serve reads while partitions are unavailable
+)
+
+// RoleError is returned for an operation the caller's role doesn't permit.
+type RoleError struct {
+	Operation string // what was refused, e.g. "write operations"
+	Role      Role
+	ReadOnly  bool // refused because the server is read-only, not for the role
+}
+
+func (e *RoleError) Error() string {
+	if e.ReadOnly {
+		return fmt.Sprintf("%s not permitted: the server is in read-only mode", e.Operation)
+	}
+	return fmt.Sprintf("%s not permitted for role: %s", e.Operation, e.Role)
+}
+
+// Cluster authentication modes.
+const (
+	AuthModeInternal = "internal" // users managed by the cluster
+	AuthModeExternal = "external" // users verified by an external service such as LDAP
+	AuthModePKI      = "pki"      // users identified by their TLS client certificate
+)
+
 // Config holds the complete configuration for the Aerospike MCP server.
 type Config struct {
 	// Cluster connection settings
 	Hosts     []Host `json:"hosts"`
 	Namespace string `json:"namespace,omitempty"`
 
+	// Connect to the addresses nodes advertise as alternate-access-address,
+	// for clusters behind NAT or in Kubernetes whose access addresses are
+	// unreachable from the server
+	UseServicesAlternate bool `json:"use_services_alternate,omitempty"`
+
+	// Start even if the cluster can't be reached, reporting degraded health
+	// and failing tool calls until the client, which keeps trying the seed
+	// hosts every client_policy.tend_interval_ms, connects
+	LazyConnect bool `json:"lazy_connect,omitempty"`
+
+	// Replica selection for reads, batches, and queries. With rack_aware,
+	// reads prefer nodes on rack_ids, in order, to keep traffic in the
+	// server's zone.
+	RackAware bool   `json:"rack_aware,omitempty"`
+	RackIDs   []int  `json:"rack_ids,omitempty"`
+	Replica   string `json:"replica,omitempty"` // default "sequence", or "prefer_rack" with rack_aware
+
+	// Read consistency for AP namespaces ("one" or "all") and strong
+	// consistency namespaces ("session", "linearize", "allow_replica", or
+	// "allow_unavailable")
+	ReadModeAP string `json:"read_mode_ap,omitempty"` // default "one"
+	ReadModeSC string `json:"read_mode_sc,omitempty"` // default "session"
+
 	// Authentication
+	AuthMode    string `json:"auth_mode,omitempty"` // "internal" (default), "external", or "pki"
 	User        string `json:"user,omitempty"`
 	Password    string `json:"password,omitempty"`
 	PasswordEnv string `json:"password_env,omitempty"`
+	// A file holding the password, such as a mounted Kubernetes secret.
+	// It is re-read when it changes.
+	PasswordFile string `json:"password_file,omitempty"`
 
 	// TLS configuration
 	TLS TLSConfig `json:"tls,omitempty"`
 
+	// External secret stores for credentials and TLS key material
+	Secrets SecretsConfig `json:"secrets,omitempty"`
+
 	// Authorization
 	Role Role `json:"role"`
 
+	// Per-client role overrides, keyed by API key name, OIDC user, or
+	// client_id. Clients without an entry use Role.
+	ClientRoles map[string]Role `json:"client_roles,omitempty"`
+
+	// Temporary, approved role elevation through the elevate_role tool
+	Elevation ElevationConfig `json:"elevation,omitempty"`
+
+	// Refuse write and admin operations whatever the role, for incident
+	// response. The set_read_only tool switches it while the server runs.
+	ReadOnly bool `json:"read_only,omitempty"`
+
+	// Client authentication for the HTTP transports
+	Auth AuthConfig `json:"auth,omitempty"`
+
 	// Client settings
 	TimeoutMs  int `json:"timeout_ms"`
 	MaxRetries int `json:"max_retries"`
 
+	// Connection pool and cluster tending
+	ClientPolicy ClientPolicyConfig `json:"client_policy,omitempty"`
+
+	// How long namespace, set, index, and UDF listings are cached; -1
+	// disables the cache
+	MetadataCacheTTLSec int `json:"metadata_cache_ttl_sec,omitempty"` // default 30
+
+	// Info requests sent to every node, such as for node_stats
+	NodeInfo NodeInfoConfig `json:"node_info,omitempty"`
+
+	// Checks that the cluster is usable before serving requests
+	StartupProbe StartupProbeConfig `json:"startup_probe,omitempty"`
+
+	// Set the benchmark tool may write to; the tool is offered only when a
+	// set is configured
+	Benchmark BenchmarkConfig `json:"benchmark,omitempty"`
+
+	// Timeouts and retry backoff by operation class
+	Policies OperationPolicies `json:"policies,omitempty"`
+
 	// Safety constraints
 	DefaultMaxRecords int `json:"default_max_records"`
 	MaxBatchSize      int `json:"max_batch_size"`
 
+	// Splitting of batch_get and batch_write calls over max_batch_size
+	BatchSplit BatchSplitConfig `json:"batch_split,omitempty"`
+
+	// Records each client may modify or delete per hour and per day
+	WriteQuota WriteQuotaConfig `json:"write_quota,omitempty"`
+
+	// Report what write tools would change without performing the writes
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Store the user key with written records, so scans and queries can
+	// return it
+	SendKey bool `json:"send_key,omitempty"`
+
+	// Write whole-number floats such as 42.0 as floats rather than
+	// integers
+	PreserveFloat bool `json:"preserve_float,omitempty"`
+
+	// Bins whose values are replaced with "[REDACTED]" in responses
+	Redact []RedactionRule `json:"redact,omitempty"`
+
+	// Primary keys that may be read and written, by namespace and set
+	KeyRules []KeyRule `json:"key_rules,omitempty"`
+
+	// Masking of PII detected in response bin values
+	PIIMasking *PIIConfig `json:"pii_masking,omitempty"`
+
+	// Timeouts, record limits, rate limits, and roles for individual
+	// namespaces, keyed by namespace name
+	Namespaces map[string]NamespacePolicy `json:"namespaces,omitempty"`
+
+	// Tool results larger than this many bytes are returned as a resource
+	// link instead of inline text. Zero disables the limit.
+	MaxInlineResultBytes int `json:"max_inline_result_bytes"`
+
+	// Scan, query, and batch results whose records serialize to more than
+	// this many bytes are truncated, with a cursor to continue from. Zero
+	// disables the limit.
+	MaxResponseBytes int `json:"max_response_bytes"`
+
+	// Scans, queries, and batches whose records would hold more than this
+	// many bytes in memory, serialized, stop early and return the records
+	// read so far, flagged as partial. Zero disables the limit.
+	MaxResultBytes int `json:"max_result_bytes"`
+
 	// Server settings
-	Transport string `json:"transport"` // "stdio", "sse", "websocket"
-	Port      int    `json:"port,omitempty"`
+	Transport  string `json:"transport"` // "stdio", "sse", "websocket", "unix", "grpc"
+	Port       int    `json:"port,omitempty"`
+	SocketPath string `json:"socket_path,omitempty"`
+	SocketMode string `json:"socket_mode,omitempty"` // octal file permissions, e.g. "0660"
+
+	// Source networks allowed to connect to the SSE and WebSocket
+	// transports, as CIDRs or single addresses. Empty allows any source.
+	AllowedCIDRs []string `json:"allowed_cidrs,omitempty"`
+
+	// Sessions on the SSE and WebSocket transports
+	Sessions SessionConfig `json:"sessions,omitempty"`
+
+	// Keepalives and resumption on the SSE and unix transports
+	SSE SSEConfig `json:"sse,omitempty"`
+
+	// TLS for the server's own network listeners
+	ServerTLS TLSConfig `json:"server_tls,omitempty"`
+
+	// Request processing
+	MaxConcurrentRequests int `json:"max_concurrent_requests"`
+	RequestTimeoutMs      int `json:"request_timeout_ms"`
+
+	// Maximum execution time of a tool call by category ("read", "write", "admin")
+	ToolTimeoutsMs map[string]int `json:"tool_timeouts_ms,omitempty"`
+
+	// Tool calls taking at least this long are logged and audited; 0 disables
+	SlowCallThresholdMs int `json:"slow_call_threshold_ms,omitempty"`
+
+	// Load limits for scans and queries
+	ScanLimits ScanLimitConfig `json:"scan_limits,omitempty"`
 
 	// Audit settings
 	Audit AuditConfig `json:"audit,omitempty"`
+
+	// Prometheus metrics endpoint
+	Metrics MetricsConfig `json:"metrics,omitempty"`
+
+	// Health, readiness, and liveness endpoints
+	Health HealthConfig `json:"health,omitempty"`
+
+	// Notifications of cluster topology and health changes
+	ClusterEvents ClusterEventsConfig `json:"cluster_events,omitempty"`
+
+	// Long tool calls run in the background
+	Jobs JobsConfig `json:"jobs,omitempty"`
+
+	// Profiling and runtime metrics listener
+	Debug DebugConfig `json:"debug,omitempty"`
+
+	// OpenTelemetry tracing
+	Tracing TracingConfig `json:"tracing,omitempty"`
+
+	// Server log output
+	Log LogConfig `json:"log,omitempty"`
+
+	// Guards the settings Reload replaces while the server runs
+	mu sync.RWMutex
+}
+
+// AuthConfig holds client authentication settings for the HTTP transports.
+type AuthConfig struct {
+	APIKeys     []APIKey    `json:"api_keys,omitempty"`
+	APIKeysFile string      `json:"api_keys_file,omitempty"` // one "name:key" or "key" per line
+	OIDC        *OIDCConfig `json:"oidc,omitempty"`
+}
+
+// OIDCConfig holds settings for validating JWT bearer tokens issued by an
+// OpenID Connect provider.
+type OIDCConfig struct {
+	Issuer    string          `json:"issuer"`
+	JWKSURL   string          `json:"jwks_url,omitempty"` // discovered from the issuer if empty
+	Audience  string          `json:"audience,omitempty"`
+	UserClaim string          `json:"user_claim,omitempty"` // default "sub"
+	RoleClaim string          `json:"role_claim,omitempty"`
+	RoleMap   map[string]Role `json:"role_map,omitempty"` // role claim value -> role
+}
+
+// APIKey is a named API key accepted in the Authorization header.
+type APIKey struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// Enabled returns true if any client authentication method is configured.
+func (a AuthConfig) Enabled() bool {
+	return len(a.APIKeys) > 0 || a.OIDC != nil
+}
+
+// SecretsConfig configures external secret stores. Besides the Vault
+// credentials block, password, auth.api_keys[].key, and tls.*_file values
+// may hold secret references ("aws-sm:...", "gcp-sm:...", "vault:...") or
+// values encrypted with the config key ("enc:...").
+type SecretsConfig struct {
+	Vault      *VaultConfig      `json:"vault,omitempty"`
+	AWS        *AWSSecretsConfig `json:"aws,omitempty"`
+	GCP        *GCPSecretsConfig `json:"gcp,omitempty"`
+	Encryption *EncryptionConfig `json:"encryption,omitempty"`
+
+	// How often secret references are re-resolved to pick up rotation.
+	RefreshIntervalSec int `json:"refresh_interval_sec,omitempty"` // default 300
+}
+
+// AWSSecretsConfig holds settings for AWS Secrets Manager references.
+// Credentials are read from the standard AWS_* environment variables.
+type AWSSecretsConfig struct {
+	Region   string `json:"region,omitempty"`   // default from the ARN or $AWS_REGION
+	Endpoint string `json:"endpoint,omitempty"` // override, e.g. a VPC endpoint
+}
+
+// GCPSecretsConfig holds settings for GCP Secret Manager references.
+type GCPSecretsConfig struct {
+	Project        string `json:"project,omitempty"`          // for references that omit "projects/..."
+	AccessTokenEnv string `json:"access_token_env,omitempty"` // default: metadata server credentials
+	Endpoint       string `json:"endpoint,omitempty"`
+}
+
+// EncryptionConfig locates the AES-256 key that decrypts "enc:" values. The
+// key is base64 encoded, and read from an environment variable or from a
+// secret reference such as "aws-sm:..." so it can live in a KMS-backed store.
+type EncryptionConfig struct {
+	KeyEnv string `json:"key_env,omitempty"` // default AEROSPIKE_MCP_CONFIG_KEY
+	KeyRef string `json:"key_ref,omitempty"` // secret reference holding the key; overrides key_env
+}
+
+// VaultConfig holds settings for reading cluster credentials from a
+// HashiCorp Vault KV secret. Each *_field names a key within the secret at
+// Path; empty fields are not read. Without a Path, the Vault connection is
+// only used for "vault:" secret references.
+type VaultConfig struct {
+	Address   string `json:"address,omitempty"`   // default $VAULT_ADDR
+	Token     string `json:"token,omitempty"`     // default $VAULT_TOKEN
+	TokenEnv  string `json:"token_env,omitempty"` // environment variable holding the token
+	Namespace string `json:"namespace,omitempty"` // Vault Enterprise namespace
+	Path      string `json:"path,omitempty"`      // e.g. "secret/data/aerospike" (KV v2) or "secret/aerospike" (KV v1)
+
+	UserField     string `json:"user_field,omitempty"`     // default "user"
+	PasswordField string `json:"password_field,omitempty"` // default "password"
+	CAField       string `json:"ca_field,omitempty"`
+	CertField     string `json:"cert_field,omitempty"`
+	KeyField      string `json:"key_field,omitempty"`
+
+	// How often the secret is re-read. Zero uses the secret's lease
+	// duration, or 5 minutes if it has none.
+	RenewIntervalSec int `json:"renew_interval_sec,omitempty"`
+}
+
+// RedactionRule selects bins to redact. Each field is a path.Match pattern
+// (e.g. "users", "pii_*"); an empty namespace or set matches any.
+type RedactionRule struct {
+	Namespace string `json:"namespace,omitempty"`
+	Set       string `json:"set,omitempty"`
+	Bin       string `json:"bin"`
+}
+
+// KeyRule restricts the primary keys that may be accessed in matching
+// namespaces and sets. Namespace and Set are path.Match patterns as in
+// RedactionRule; Pattern is a regular expression that keys must match in
+// full (e.g. "acme:.*"). A rule with Roles applies only to requests with one
+// of those roles.
+type KeyRule struct {
+	Namespace string `json:"namespace,omitempty"`
+	Set       string `json:"set,omitempty"`
+	Pattern   string `json:"pattern"`
+	Roles     []Role `json:"roles,omitempty"`
+}
+
+// ClientPolicyConfig tunes the cluster connection pool and tending. Zero
+// values keep the Aerospike client's defaults, shown in the comments;
+// high-latency links usually want longer login and idle timeouts and a
+// larger error window.
+type ClientPolicyConfig struct {
+	ConnectionQueueSize   int `json:"connection_queue_size,omitempty"`    // connections per node; default 100
+	MinConnectionsPerNode int `json:"min_connections_per_node,omitempty"` // kept open when idle; default 0
+	MaxErrorRate          int `json:"max_error_rate,omitempty"`           // errors per node per window before backing off; default 100, -1 disables
+	ErrorRateWindow       int `json:"error_rate_window,omitempty"`        // tend iterations; default 1
+	IdleTimeoutMs         int `json:"idle_timeout_ms,omitempty"`          // close pooled connections idle this long; default never
+	LoginTimeoutMs        int `json:"login_timeout_ms,omitempty"`         // default 10000
+	TendIntervalMs        int `json:"tend_interval_ms,omitempty"`         // cluster state checks; default 1000
+	WarmUpConnections     int `json:"warm_up_connections,omitempty"`      // opened per node at startup; default 0
+}
+
+// NodeInfoConfig bounds the info requests sent to every node at once. Each
+// node has its own timeout, so one slow node doesn't hold up the others.
+type NodeInfoConfig struct {
+	Parallelism int `json:"parallelism,omitempty"` // nodes queried at once; default 16
+	TimeoutMs   int `json:"timeout_ms,omitempty"`  // per node; default timeout_ms
+}
+
+// StartupProbeConfig configures the checks made after connecting and before
+// accepting MCP traffic. The default namespace and RequiredNamespaces must
+// exist in the cluster, or the server exits.
+type StartupProbeConfig struct {
+	Enabled            bool     `json:"enabled,omitempty"`
+	TimeoutMs          int      `json:"timeout_ms,omitempty"` // default 10000
+	RequiredNamespaces []string `json:"required_namespaces,omitempty"`
+}
+
+// BenchmarkConfig designates the set the benchmark admin tool reads and
+// writes, and bounds the workload a call may ask for. The tool writes
+// records keyed "benchmark-<n>" to the set and deletes them afterwards.
+type BenchmarkConfig struct {
+	Namespace      string `json:"namespace,omitempty"`       // default namespace
+	Set            string `json:"set,omitempty"`             // required to enable the tool
+	MaxOperations  int    `json:"max_operations,omitempty"`  // default 10000
+	MaxConcurrency int    `json:"max_concurrency,omitempty"` // default 16
+}
+
+// OperationPolicies tunes timeouts and retries separately for each class
+// of operation.
+type OperationPolicies struct {
+	Read  OperationPolicy `json:"read,omitempty"`
+	Write OperationPolicy `json:"write,omitempty"`
+	Scan  OperationPolicy `json:"scan,omitempty"`
+	Query OperationPolicy `json:"query,omitempty"`
+	Batch OperationPolicy `json:"batch,omitempty"`
+}
+
+// OperationPolicy sets the timeouts and retry backoff of one class of
+// operation. Zero values keep timeout_ms for the total timeout and the
+// Aerospike client's defaults, shown in the comments, for the rest.
+type OperationPolicy struct {
+	TotalTimeoutMs        int     `json:"total_timeout_ms,omitempty"`         // whole operation, including retries
+	SocketTimeoutMs       int     `json:"socket_timeout_ms,omitempty"`        // each attempt's network I/O; default 30000
+	SleepBetweenRetriesMs int     `json:"sleep_between_retries_ms,omitempty"` // default 1
+	SleepMultiplier       float64 `json:"sleep_multiplier,omitempty"`         // backoff factor per retry; default 1
+}
+
+// validate checks the policy's settings; name is its config path.
+func (p OperationPolicy) validate(name string) error {
+	if p.TotalTimeoutMs < 0 || p.SocketTimeoutMs < 0 || p.SleepBetweenRetriesMs < 0 {
+		return fmt.Errorf("invalid %s: timeouts and sleeps must not be negative", name)
+	}
+	if p.SleepMultiplier != 0 && p.SleepMultiplier < 1 {
+		return fmt.Errorf("invalid %s.sleep_multiplier: %g (must be at least 1)", name, p.SleepMultiplier)
+	}
+	return nil
+}
+
+// NamespacePolicy overrides settings for one namespace. Zero values keep
+// the server-wide settings.
+type NamespacePolicy struct {
+	TimeoutMs  int `json:"timeout_ms,omitempty"`  // replaces timeout_ms for operations in the namespace
+	MaxRecords int `json:"max_records,omitempty"` // default and limit for scans and queries
+
+	// Highest role permitted in the namespace; a "read-only" namespace
+	// refuses writes from every client
+	Role Role `json:"role,omitempty"`
+
+	// Per-client budgets keyed by request category, applied in addition to
+	// audit.rate_limits
+	RateLimits map[string]RateLimit `json:"rate_limits,omitempty"`
+}
+
+// PIIConfig enables masking of PII detected in bin values. Detectors are
+// "email", "credit_card", and "phone".
+type PIIConfig struct {
+	Detectors  []string            `json:"detectors,omitempty"`  // default: all
+	Namespaces map[string][]string `json:"namespaces,omitempty"` // per-namespace detectors; an empty list disables masking
 }
 
 // AuditConfig holds audit logging configuration.
@@ -75,6 +536,206 @@ type AuditConfig struct {
 	RateLimitEnabled bool    `json:"rate_limit_enabled"`
 	RateLimitRPS     float64 `json:"rate_limit_rps"`
 	RateLimitBurst   int     `json:"rate_limit_burst"`
+
+	// Per-client budgets keyed by request category: "read", "write", or
+	// "admin". If unset, only writes are limited, using RateLimitRPS and
+	// RateLimitBurst.
+	RateLimits map[string]RateLimit `json:"rate_limits,omitempty"`
+
+	// Tamper evidence: each event carries the hash of the previous one, and
+	// if a key file is set the chain is signed every SignEvery events.
+	HashChain      bool   `json:"hash_chain"`
+	SigningKeyFile string `json:"signing_key_file,omitempty"`
+	SignEvery      int    `json:"sign_every,omitempty"` // default 100
+
+	// Rotation and retention of FilePath
+	Rotation AuditRotationConfig `json:"rotation,omitempty"`
+
+	// Copy events into an Aerospike set
+	Aerospike *AuditAerospikeConfig `json:"aerospike,omitempty"`
+
+	// Copy events to syslog collectors and webhooks
+	Sinks []AuditSinkConfig `json:"sinks,omitempty"`
+}
+
+// AuditSinkConfig describes an external destination for audit events. Type
+// selects which of the remaining fields apply.
+type AuditSinkConfig struct {
+	Type string `json:"type"`           // "syslog" or "webhook"
+	Name string `json:"name,omitempty"` // shown in server logs; defaults to the type
+
+	// syslog: RFC 5424 messages over UDP, TCP, or TLS
+	Network  string `json:"network,omitempty"`  // "udp", "tcp", or "tls"; default "udp"
+	Address  string `json:"address,omitempty"`  // host:port
+	Facility string `json:"facility,omitempty"` // default "local0"
+	AppName  string `json:"app_name,omitempty"` // default "aerospike-mcp-server"
+	CAFile   string `json:"ca_file,omitempty"`
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+
+	// webhook: batches of events POSTed as JSON arrays
+	URL             string            `json:"url,omitempty"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	BatchSize       int               `json:"batch_size,omitempty"`        // default 100
+	FlushIntervalMs int               `json:"flush_interval_ms,omitempty"` // default 1000
+	MaxRetries      int               `json:"max_retries,omitempty"`       // default 3; -1 disables retries
+	TimeoutMs       int               `json:"timeout_ms,omitempty"`        // default 5000
+}
+
+// AuditAerospikeConfig writes audit events as records in an Aerospike set,
+// one record per event. Tools can read the set but not modify it.
+type AuditAerospikeConfig struct {
+	Namespace string `json:"namespace"`
+	Set       string `json:"set,omitempty"`     // default "mcp_audit"
+	TTLSec    int    `json:"ttl_sec,omitempty"` // 0 uses the namespace default; -1 never expires
+}
+
+// AuditRotationConfig controls rotation of the audit log file. The file is
+// rotated when it would exceed MaxSizeMB or its first event is older than
+// RotateIntervalHours; rotated files beyond MaxFiles or older than
+// MaxAgeDays are deleted. Zero values disable each limit.
+type AuditRotationConfig struct {
+	MaxSizeMB           int  `json:"max_size_mb,omitempty"`
+	RotateIntervalHours int  `json:"rotate_interval_hours,omitempty"`
+	Compress            bool `json:"compress,omitempty"` // gzip rotated files
+	MaxFiles            int  `json:"max_files,omitempty"`
+	MaxAgeDays          int  `json:"max_age_days,omitempty"`
+}
+
+// RateLimit is a token bucket budget. Burst defaults to one second's worth
+// of requests.
+type RateLimit struct {
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst,omitempty"`
+}
+
+// ElevationConfig controls the elevate_role tool, which lets a client
+// request a higher role for a limited time. Requests take effect only once
+// an approver signs them with the approval key.
+type ElevationConfig struct {
+	Enabled         bool   `json:"enabled"`
+	ApprovalKeyFile string `json:"approval_key_file,omitempty"` // HMAC key approvers sign requests with
+	MaxDurationSec  int    `json:"max_duration_sec,omitempty"`  // longest elevation a client may request
+	RequestTTLSec   int    `json:"request_ttl_sec,omitempty"`   // how long a request waits for approval
+}
+
+// WriteQuotaConfig caps the records each client may modify or delete. Zero
+// values are unlimited.
+type WriteQuotaConfig struct {
+	RecordsPerHour int `json:"records_per_hour"`
+	RecordsPerDay  int `json:"records_per_day"`
+}
+
+// ScanLimitConfig bounds the load scans and queries put on the cluster. Zero
+// values are unlimited.
+type ScanLimitConfig struct {
+	MaxConcurrent          int `json:"max_concurrent"`            // scans and queries running on this server
+	MaxConcurrentPerClient int `json:"max_concurrent_per_client"` // scans and queries running per client
+	RecordsPerSecond       int `json:"records_per_second"`        // per scan or query, on each cluster node
+	MaxConcurrentNodes     int `json:"max_concurrent_nodes"`      // cluster nodes each scan or query runs on at once
+
+	// scan_set of a set holding more records than this requires
+	// confirm_large_scan; 0 disables the check
+	ConfirmAboveRecords int64 `json:"confirm_above_records"`
+}
+
+// SessionConfig controls the signed session tokens issued by the SSE and
+// WebSocket transports.
+type SessionConfig struct {
+	TTLSec       int `json:"ttl_sec"`                  // token lifetime; rotating a token renews it
+	MaxPerClient int `json:"max_per_client,omitempty"` // sessions per authenticated client; 0 is unlimited
+}
+
+// SSEConfig controls the SSE event stream, which the unix transport also
+// serves. A client whose connection drops keeps its session for
+// ReconnectGraceSec and can resume the stream with the Last-Event-ID header,
+// replaying the events it missed from a buffer of the most recent ones.
+type SSEConfig struct {
+	KeepaliveSec      int  `json:"keepalive_sec,omitempty"`       // interval between ": ping" comments; default 15
+	WriteTimeoutSec   int  `json:"write_timeout_sec,omitempty"`   // drop a connection that doesn't accept an event in time; default 10
+	ReconnectGraceSec int  `json:"reconnect_grace_sec,omitempty"` // how long a dropped client's session waits for it; default 30
+	ReplayBufferSize  int  `json:"replay_buffer_size,omitempty"`  // events kept for replay on resume; default 100
+	Compression       bool `json:"compression"`                   // gzip or deflate the stream for clients that accept it; default true
+}
+
+// MetricsConfig controls the Prometheus metrics endpoint. The SSE,
+// WebSocket, and unix transports serve it alongside their other endpoints
+// unless Port sets a separate listener, which the stdio and grpc transports
+// require.
+type MetricsConfig struct {
+	Enabled bool   `json:"enabled"`
+	Port    int    `json:"port,omitempty"`
+	Path    string `json:"path,omitempty"` // default "/metrics"
+}
+
+// HealthConfig controls the /health, /ready, and /live endpoints. The SSE,
+// WebSocket, and unix transports always serve them; Port adds a separate
+// admin listener, so probes also work with the stdio and grpc transports.
+type HealthConfig struct {
+	Port      int `json:"port,omitempty"`
+	TimeoutMs int `json:"timeout_ms,omitempty"` // cluster check timeout; default 2000
+}
+
+// ClusterEventsConfig controls the watcher that polls the cluster's node
+// membership, migrations, and stop-writes flags, and notifies connected
+// clients when they change.
+type ClusterEventsConfig struct {
+	Enabled        bool `json:"enabled"`
+	PollIntervalMs int  `json:"poll_interval_ms,omitempty"` // default 10000
+}
+
+// BatchSplitConfig lets batch calls exceed max_batch_size: they are sent
+// as several batch commands of at most max_batch_size keys, Concurrency at
+// a time, and their results merged in request order.
+type BatchSplitConfig struct {
+	Enabled     bool `json:"enabled"`
+	Concurrency int  `json:"concurrency,omitempty"` // batch commands in flight; default 4
+	MaxKeys     int  `json:"max_keys,omitempty"`    // largest call accepted; default 10 times max_batch_size
+}
+
+// BatchLimit returns the most keys a batch call may name: max_batch_size,
+// or batch_split.max_keys when batches are split.
+func (c *Config) BatchLimit() int {
+	if c.BatchSplit.Enabled {
+		return c.BatchSplit.MaxKeys
+	}
+	return c.MaxBatchSize
+}
+
+// JobsConfig controls background jobs. When enabled, long tool calls made
+// with "async": true return a job ID at once and run in the background,
+// where they can be followed and cancelled.
+type JobsConfig struct {
+	Enabled      bool `json:"enabled"`
+	MaxRunning   int  `json:"max_running,omitempty"`   // jobs running at once; default 4
+	MaxFinished  int  `json:"max_finished,omitempty"`  // finished jobs kept; default 100
+	RetentionSec int  `json:"retention_sec,omitempty"` // how long finished jobs are kept; default 3600
+	TimeoutSec   int  `json:"timeout_sec,omitempty"`   // longest a job may run; default 3600
+}
+
+// DebugConfig controls the opt-in listener serving net/http/pprof profiles
+// and expvar runtime metrics. It binds to a loopback address unless
+// AllowRemote is set, which also requires allowed_cidrs.
+type DebugConfig struct {
+	Enabled     bool   `json:"enabled"`
+	Address     string `json:"address,omitempty"`      // default "127.0.0.1:6060"
+	AllowRemote bool   `json:"allow_remote,omitempty"` // permit a non-loopback address
+}
+
+// TracingConfig controls export of OpenTelemetry trace spans to an OTLP/HTTP
+// collector.
+type TracingConfig struct {
+	Enabled     bool              `json:"enabled"`
+	Endpoint    string            `json:"endpoint,omitempty"`     // collector URL, e.g. http://localhost:4318
+	Headers     map[string]string `json:"headers,omitempty"`      // sent with every export
+	ServiceName string            `json:"service_name,omitempty"` // default "aerospike-mcp-server"
+	SampleRatio float64           `json:"sample_ratio,omitempty"` // fraction of new traces recorded; default 1
+}
+
+// LogConfig controls the server's own log output on stderr.
+type LogConfig struct {
+	Level  string `json:"level,omitempty"`  // "debug", "info", "warn", or "error"
+	Format string `json:"format,omitempty"` // "text" or "json"
 }
 
 // DefaultConfig returns a configuration with sensible defaults.
@@ -89,6 +750,20 @@ func DefaultConfig() *Config {
 		DefaultMaxRecords: 1000,
 		MaxBatchSize:      5000,
 		Transport:         "stdio",
+
+		MaxInlineResultBytes: 256 * 1024,
+		MaxResponseBytes:     1024 * 1024,
+		MaxResultBytes:       64 * 1024 * 1024,
+
+		ScanLimits: ScanLimitConfig{ConfirmAboveRecords: 100000},
+
+		Sessions:  SessionConfig{TTLSec: 3600},
+		SSE:       SSEConfig{KeepaliveSec: 15, WriteTimeoutSec: 10, ReconnectGraceSec: 30, ReplayBufferSize: 100, Compression: true},
+		Elevation: ElevationConfig{MaxDurationSec: 3600, RequestTTLSec: 900},
+
+		MaxConcurrentRequests: 16,
+		RequestTimeoutMs:      30000,
+
 		Audit: AuditConfig{
 			Enabled:          true,
 			BufferSize:       100,
@@ -96,33 +771,72 @@ func DefaultConfig() *Config {
 			RateLimitRPS:     100,
 			RateLimitBurst:   200,
 		},
+
+		Log: LogConfig{Level: "info", Format: "text"},
 	}
 }
 
-// Load reads configuration from a file path or uses defaults.
+// Overrides holds option values given on the command line, keyed by the
+// option's JSON path (e.g. "log.level") and parsed like environment
+// variables.
+type Overrides map[string]string
+
+// Load reads configuration from a file path or uses defaults, then applies
+// AEROSPIKE_MCP_* environment variables and any overrides over them.
 // If configPath is empty, it checks for AEROSPIKE_MCP_CONFIG env var.
-func Load(configPath string) (*Config, error) {
+func Load(configPath string, overrides ...Overrides) (*Config, error) {
 	// Check environment variable if no path provided
 	if configPath == "" {
 		configPath = os.Getenv("AEROSPIKE_MCP_CONFIG")
 	}
 
 	cfg := DefaultConfig()
+	env := environment()
+	delete(env, "AEROSPIKE_MCP_CONFIG")
 
-	// If still no config path, return defaults
-	if configPath == "" {
+	// If still no config path, environment variables, or overrides, return
+	// defaults
+	overridden := len(env) > 0
+	for _, o := range overrides {
+		overridden = overridden || len(o) > 0
+	}
+	if configPath == "" && !overridden {
 		return cfg, nil
 	}
 
-	// Read config file
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("reading config file: %w", err)
+	if configPath != "" {
+		// Read config file
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+
+		// YAML and TOML files are converted to JSON, so every format shares
+		// the same field names
+		switch strings.ToLower(filepath.Ext(configPath)) {
+		case ".yaml", ".yml":
+			data, err = yamlToJSON(data)
+		case ".toml":
+			data, err = tomlToJSON(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing config file: %w", err)
+		}
+
+		// Parse JSON
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing config file: %w", err)
+		}
 	}
 
-	// Parse JSON
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("parsing config file: %w", err)
+	// Environment variables take precedence over the file
+	if err := applyEnv(cfg, env); err != nil {
+		return nil, fmt.Errorf("reading environment: %w", err)
+	}
+	for _, o := range overrides {
+		if err := applyOverrides(cfg, o); err != nil {
+			return nil, err
+		}
 	}
 
 	// Resolve password from environment variable if specified
@@ -130,6 +844,27 @@ func Load(configPath string) (*Config, error) {
 		cfg.Password = os.Getenv(cfg.PasswordEnv)
 	}
 
+	// Or from a file, which must then be the only source
+	if cfg.PasswordFile != "" {
+		if cfg.Password != "" || cfg.PasswordEnv != "" {
+			return nil, fmt.Errorf("invalid configuration: password_file cannot be combined with password or password_env")
+		}
+		password, err := ReadPasswordFile(cfg.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Password = password
+	}
+
+	// Load API keys from file if specified
+	if cfg.Auth.APIKeysFile != "" {
+		keys, err := loadAPIKeysFile(cfg.Auth.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Auth.APIKeys = append(cfg.Auth.APIKeys, keys...)
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -138,6 +873,44 @@ func Load(configPath string) (*Config, error) {
 	return cfg, nil
 }
 
+// ReadPasswordFile reads a password from a file, without a trailing
+// newline.
+func ReadPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading password file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// loadAPIKeysFile reads API keys from a file containing one key per line,
+// optionally prefixed with a name ("name:key"). Blank lines and lines
+// starting with '#' are ignored.
+func loadAPIKeysFile(path string) ([]APIKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading API keys file: %w", err)
+	}
+
+	var keys []APIKey
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key := APIKey{Key: line}
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			key = APIKey{Name: strings.TrimSpace(name), Key: strings.TrimSpace(value)}
+		}
+		if key.Name == "" {
+			key.Name = fmt.Sprintf("%s:%d", filepath.Base(path), i+1)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
 // Validate checks the configuration for errors.
 func (c *Config) Validate() error {
 	if len(c.Hosts) == 0 {
@@ -162,7 +935,169 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid role: %s (must be read-only, read-write, or admin)", c.Role)
 	}
 
-	validTransports := []string{"stdio", "sse", "websocket"}
+	for client, role := range c.ClientRoles {
+		if !role.Valid() {
+			return fmt.Errorf("client_roles[%s]: invalid role %s", client, role)
+		}
+	}
+
+	switch strings.ToLower(c.AuthMode) {
+	case "", AuthModeInternal:
+	case AuthModeExternal:
+		// Credentials are sent to the cluster in clear text for external verification
+		if !c.TLS.Enabled {
+			return fmt.Errorf("auth_mode external requires tls.enabled")
+		}
+		if c.User == "" {
+			return fmt.Errorf("auth_mode external requires user")
+		}
+	case AuthModePKI:
+		vaultCert := c.Secrets.Vault != nil && c.Secrets.Vault.CertField != "" && c.Secrets.Vault.KeyField != ""
+		if !c.TLS.Enabled || ((c.TLS.CertFile == "" || c.TLS.KeyFile == "") && !vaultCert) {
+			return fmt.Errorf("auth_mode pki requires tls.enabled with a client certificate and key")
+		}
+	default:
+		return fmt.Errorf("invalid auth_mode: %s (must be internal, external, or pki)", c.AuthMode)
+	}
+
+	for i, rule := range c.Redact {
+		if rule.Bin == "" {
+			return fmt.Errorf("redact[%d]: bin is required", i)
+		}
+		for _, pattern := range []string{rule.Namespace, rule.Set, rule.Bin} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("redact[%d]: invalid pattern %q", i, pattern)
+			}
+		}
+	}
+
+	if pii := c.PIIMasking; pii != nil {
+		lists := map[string][]string{"pii_masking.detectors": pii.Detectors}
+		for ns, detectors := range pii.Namespaces {
+			lists["pii_masking.namespaces["+ns+"]"] = detectors
+		}
+		for field, detectors := range lists {
+			for _, d := range detectors {
+				switch d {
+				case "email", "credit_card", "phone":
+				default:
+					return fmt.Errorf("%s: unknown detector %s (must be email, credit_card, or phone)", field, d)
+				}
+			}
+		}
+	}
+
+	for i, rule := range c.KeyRules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("key_rules[%d]: pattern is required", i)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("key_rules[%d]: invalid pattern: %w", i, err)
+		}
+		for _, pattern := range []string{rule.Namespace, rule.Set} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("key_rules[%d]: invalid pattern %q", i, pattern)
+			}
+		}
+		for _, role := range rule.Roles {
+			if !role.Valid() {
+				return fmt.Errorf("key_rules[%d]: invalid role: %s", i, role)
+			}
+		}
+	}
+
+	for namespace, policy := range c.Namespaces {
+		field := "namespaces." + namespace
+		if policy.TimeoutMs < 0 {
+			return fmt.Errorf("invalid %s.timeout_ms: %d (must not be negative)", field, policy.TimeoutMs)
+		}
+		if policy.MaxRecords < 0 {
+			return fmt.Errorf("invalid %s.max_records: %d (must not be negative)", field, policy.MaxRecords)
+		}
+		if policy.Role != "" && !policy.Role.Valid() {
+			return fmt.Errorf("%s: invalid role: %s", field, policy.Role)
+		}
+		for category, limit := range policy.RateLimits {
+			switch category {
+			case "read", "write", "admin":
+			default:
+				return fmt.Errorf("%s.rate_limits: unknown category %s (must be read, write, or admin)", field, category)
+			}
+			if limit.RPS <= 0 || limit.Burst < 0 {
+				return fmt.Errorf("%s.rate_limits.%s: rps must be positive and burst non-negative", field, category)
+			}
+		}
+	}
+
+	if c.Debug.Enabled {
+		if c.Debug.Address == "" {
+			c.Debug.Address = "127.0.0.1:6060"
+		}
+		host, _, err := net.SplitHostPort(c.Debug.Address)
+		if err != nil {
+			return fmt.Errorf("invalid debug.address: %w", err)
+		}
+		if !c.Debug.AllowRemote && !isLoopback(host) {
+			return fmt.Errorf("debug.address %s is not a loopback address (set debug.allow_remote to permit it)", c.Debug.Address)
+		}
+		if c.Debug.AllowRemote && len(c.AllowedCIDRs) == 0 {
+			return fmt.Errorf("debug.allow_remote requires allowed_cidrs")
+		}
+	}
+
+	for category, ms := range c.ToolTimeoutsMs {
+		switch category {
+		case "read", "write", "admin":
+		default:
+			return fmt.Errorf("tool_timeouts_ms: unknown category %s (must be read, write, or admin)", category)
+		}
+		if ms <= 0 {
+			return fmt.Errorf("tool_timeouts_ms.%s: must be positive", category)
+		}
+	}
+
+	for category, limit := range c.Audit.RateLimits {
+		switch category {
+		case "read", "write", "admin":
+		default:
+			return fmt.Errorf("audit.rate_limits: unknown category %s (must be read, write, or admin)", category)
+		}
+		if limit.RPS <= 0 || limit.Burst < 0 {
+			return fmt.Errorf("audit.rate_limits.%s: rps must be positive and burst non-negative", category)
+		}
+	}
+
+	if c.Audit.SigningKeyFile != "" && !c.Audit.HashChain {
+		return fmt.Errorf("audit.signing_key_file requires audit.hash_chain")
+	}
+	if c.Audit.SignEvery < 0 {
+		return fmt.Errorf("invalid audit.sign_every: %d (must not be negative)", c.Audit.SignEvery)
+	}
+	if sink := c.Audit.Aerospike; sink != nil {
+		if sink.Namespace == "" {
+			return fmt.Errorf("audit.aerospike.namespace is required")
+		}
+		if sink.Set == "" {
+			sink.Set = "mcp_audit"
+		}
+		if sink.TTLSec < -1 {
+			return fmt.Errorf("invalid audit.aerospike.ttl_sec: %d (must be -1 or more)", sink.TTLSec)
+		}
+	}
+	for i := range c.Audit.Sinks {
+		if err := c.Audit.Sinks[i].validate(); err != nil {
+			return fmt.Errorf("audit.sinks[%d]: %w", i, err)
+		}
+	}
+	rotation := c.Audit.Rotation
+	if rotation.MaxSizeMB < 0 || rotation.RotateIntervalHours < 0 || rotation.MaxFiles < 0 || rotation.MaxAgeDays < 0 {
+		return fmt.Errorf("invalid audit.rotation: limits must not be negative")
+	}
+	if (rotation.MaxSizeMB > 0 || rotation.RotateIntervalHours > 0) && c.Audit.FilePath == "" {
+		return fmt.Errorf("audit.rotation requires audit.file_path")
+	}
+
+	validTransports := []string{"stdio", "sse", "websocket", "unix", "grpc"}
 	transportValid := false
 	for _, t := range validTransports {
 		if strings.EqualFold(c.Transport, t) {
@@ -171,7 +1106,237 @@ func (c *Config) Validate() error {
 		}
 	}
 	if !transportValid {
-		return fmt.Errorf("invalid transport: %s (must be stdio, sse, or websocket)", c.Transport)
+		return fmt.Errorf("invalid transport: %s (must be stdio, sse, websocket, unix, or grpc)", c.Transport)
+	}
+
+	if strings.EqualFold(c.Transport, "unix") && c.SocketPath == "" {
+		return fmt.Errorf("socket_path is required for unix transport")
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	case "":
+		c.Log.Level = "info"
+	default:
+		return fmt.Errorf("invalid log.level: %s (must be debug, info, warn, or error)", c.Log.Level)
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	case "":
+		c.Log.Format = "text"
+	default:
+		return fmt.Errorf("invalid log.format: %s (must be text or json)", c.Log.Format)
+	}
+
+	if c.Tracing.Enabled {
+		if c.Tracing.Endpoint == "" {
+			return fmt.Errorf("tracing.endpoint is required when tracing is enabled")
+		}
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid tracing.endpoint: %s (must be an http or https URL)", c.Tracing.Endpoint)
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("invalid tracing.sample_ratio: %g (must be between 0 and 1)", c.Tracing.SampleRatio)
+		}
+		if c.Tracing.SampleRatio == 0 {
+			c.Tracing.SampleRatio = 1
+		}
+		if c.Tracing.ServiceName == "" {
+			c.Tracing.ServiceName = "aerospike-mcp-server"
+		}
+	}
+
+	if c.Metrics.Enabled {
+		if c.Metrics.Port < 0 || c.Metrics.Port > 65535 {
+			return fmt.Errorf("invalid metrics.port: %d", c.Metrics.Port)
+		}
+		if c.Metrics.Port == 0 && (strings.EqualFold(c.Transport, "stdio") || strings.EqualFold(c.Transport, "grpc")) {
+			return fmt.Errorf("metrics.port is required for %s transport", c.Transport)
+		}
+		if c.Metrics.Port != 0 && c.Metrics.Port == c.Port {
+			return fmt.Errorf("metrics.port must differ from port")
+		}
+		if c.Metrics.Path == "" {
+			c.Metrics.Path = "/metrics"
+		}
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			return fmt.Errorf("invalid metrics.path: %s (must start with /)", c.Metrics.Path)
+		}
+	}
+
+	if c.Health.Port < 0 || c.Health.Port > 65535 {
+		return fmt.Errorf("invalid health.port: %d", c.Health.Port)
+	}
+	if c.Health.Port != 0 && (c.Health.Port == c.Port || (c.Metrics.Enabled && c.Health.Port == c.Metrics.Port)) {
+		return fmt.Errorf("health.port must differ from port and metrics.port")
+	}
+	if c.Health.TimeoutMs < 0 {
+		return fmt.Errorf("invalid health.timeout_ms: %d (must not be negative)", c.Health.TimeoutMs)
+	}
+	if c.Health.TimeoutMs == 0 {
+		c.Health.TimeoutMs = 2000
+	}
+
+	if c.ClusterEvents.Enabled {
+		if strings.EqualFold(c.Transport, "grpc") {
+			return fmt.Errorf("cluster_events is not supported by the grpc transport")
+		}
+		if c.ClusterEvents.PollIntervalMs < 0 {
+			return fmt.Errorf("invalid cluster_events.poll_interval_ms: %d (must not be negative)", c.ClusterEvents.PollIntervalMs)
+		}
+		if c.ClusterEvents.PollIntervalMs == 0 {
+			c.ClusterEvents.PollIntervalMs = 10000
+		}
+	}
+
+	if c.Jobs.Enabled {
+		if c.Jobs.MaxRunning < 0 || c.Jobs.MaxFinished < 0 || c.Jobs.RetentionSec < 0 || c.Jobs.TimeoutSec < 0 {
+			return fmt.Errorf("jobs.max_running, jobs.max_finished, jobs.retention_sec, and jobs.timeout_sec must not be negative")
+		}
+		if c.Jobs.MaxRunning == 0 {
+			c.Jobs.MaxRunning = 4
+		}
+		if c.Jobs.MaxFinished == 0 {
+			c.Jobs.MaxFinished = 100
+		}
+		if c.Jobs.RetentionSec == 0 {
+			c.Jobs.RetentionSec = 3600
+		}
+		if c.Jobs.TimeoutSec == 0 {
+			c.Jobs.TimeoutSec = 3600
+		}
+	}
+
+	for i, key := range c.Auth.APIKeys {
+		if key.Key == "" {
+			return fmt.Errorf("auth.api_keys[%d]: key is required", i)
+		}
+		if key.Name == "" {
+			c.Auth.APIKeys[i].Name = fmt.Sprintf("api_key_%d", i)
+		}
+	}
+
+	if oidc := c.Auth.OIDC; oidc != nil {
+		if oidc.Issuer == "" {
+			return fmt.Errorf("auth.oidc.issuer is required")
+		}
+		if oidc.UserClaim == "" {
+			oidc.UserClaim = "sub"
+		}
+		for value, role := range oidc.RoleMap {
+			if !role.Valid() {
+				return fmt.Errorf("auth.oidc.role_map[%s]: invalid role %s", value, role)
+			}
+		}
+	}
+
+	if vault := c.Secrets.Vault; vault != nil {
+		if vault.Address == "" {
+			vault.Address = os.Getenv("VAULT_ADDR")
+		}
+		if vault.Address == "" {
+			return fmt.Errorf("secrets.vault.address is required (or set VAULT_ADDR)")
+		}
+		if vault.UserField == "" {
+			vault.UserField = "user"
+		}
+		if vault.PasswordField == "" {
+			vault.PasswordField = "password"
+		}
+		if vault.RenewIntervalSec < 0 {
+			return fmt.Errorf("secrets.vault.renew_interval_sec must not be negative")
+		}
+	}
+
+	if c.Secrets.RefreshIntervalSec < 0 {
+		return fmt.Errorf("secrets.refresh_interval_sec must not be negative")
+	}
+	if c.Secrets.RefreshIntervalSec == 0 {
+		c.Secrets.RefreshIntervalSec = 300
+	}
+
+	if c.ServerTLS.Enabled && (c.ServerTLS.CertFile == "" || c.ServerTLS.KeyFile == "") {
+		return fmt.Errorf("server_tls requires cert_file and key_file")
+	}
+	if _, err := c.AllowedNetworks(); err != nil {
+		return err
+	}
+
+	switch c.ServerTLS.ClientAuth {
+	case "":
+	case ClientAuthRequire, ClientAuthOptional:
+		if c.ServerTLS.CAFile == "" {
+			return fmt.Errorf("server_tls.client_auth requires ca_file")
+		}
+	default:
+		return fmt.Errorf("invalid server_tls.client_auth: %s (must be %s or %s)", c.ServerTLS.ClientAuth, ClientAuthRequire, ClientAuthOptional)
+	}
+
+	if c.SocketMode == "" {
+		c.SocketMode = "0600"
+	}
+	if mode, err := strconv.ParseUint(c.SocketMode, 8, 32); err != nil || mode > 0o777 {
+		return fmt.Errorf("invalid socket_mode: %s (must be octal permissions up to 0777, e.g. 0660)", c.SocketMode)
+	}
+
+	if c.Sessions.TTLSec < 0 {
+		return fmt.Errorf("invalid sessions.ttl_sec: %d (must be positive)", c.Sessions.TTLSec)
+	}
+	if c.Sessions.TTLSec == 0 {
+		c.Sessions.TTLSec = 3600
+	}
+	if c.Sessions.MaxPerClient < 0 {
+		return fmt.Errorf("invalid sessions.max_per_client: %d (must not be negative)", c.Sessions.MaxPerClient)
+	}
+
+	if c.SSE.KeepaliveSec < 0 {
+		return fmt.Errorf("invalid sse.keepalive_sec: %d (must be positive)", c.SSE.KeepaliveSec)
+	}
+	if c.SSE.KeepaliveSec == 0 {
+		c.SSE.KeepaliveSec = 15
+	}
+	if c.SSE.WriteTimeoutSec < 0 {
+		return fmt.Errorf("invalid sse.write_timeout_sec: %d (must be positive)", c.SSE.WriteTimeoutSec)
+	}
+	if c.SSE.WriteTimeoutSec == 0 {
+		c.SSE.WriteTimeoutSec = 10
+	}
+	if c.SSE.ReconnectGraceSec < 0 {
+		return fmt.Errorf("invalid sse.reconnect_grace_sec: %d (must be positive)", c.SSE.ReconnectGraceSec)
+	}
+	if c.SSE.ReconnectGraceSec == 0 {
+		c.SSE.ReconnectGraceSec = 30
+	}
+	if c.SSE.ReplayBufferSize < 0 {
+		return fmt.Errorf("invalid sse.replay_buffer_size: %d (must be positive)", c.SSE.ReplayBufferSize)
+	}
+	if c.SSE.ReplayBufferSize == 0 {
+		c.SSE.ReplayBufferSize = 100
+	}
+
+	if c.Elevation.Enabled && c.Elevation.ApprovalKeyFile == "" {
+		return fmt.Errorf("elevation.approval_key_file is required when elevation is enabled")
+	}
+	if c.Elevation.MaxDurationSec < 0 {
+		return fmt.Errorf("invalid elevation.max_duration_sec: %d (must be positive)", c.Elevation.MaxDurationSec)
+	}
+	if c.Elevation.MaxDurationSec == 0 {
+		c.Elevation.MaxDurationSec = 3600
+	}
+	if c.Elevation.RequestTTLSec < 0 {
+		return fmt.Errorf("invalid elevation.request_ttl_sec: %d (must be positive)", c.Elevation.RequestTTLSec)
+	}
+	if c.Elevation.RequestTTLSec == 0 {
+		c.Elevation.RequestTTLSec = 900
+	}
+
+	if c.WriteQuota.RecordsPerHour < 0 || c.WriteQuota.RecordsPerDay < 0 {
+		return fmt.Errorf("invalid write_quota: limits must not be negative")
+	}
+
+	if c.ScanLimits.MaxConcurrent < 0 || c.ScanLimits.MaxConcurrentPerClient < 0 || c.ScanLimits.RecordsPerSecond < 0 ||
+		c.ScanLimits.MaxConcurrentNodes < 0 || c.ScanLimits.ConfirmAboveRecords < 0 {
+		return fmt.Errorf("invalid scan_limits: limits must not be negative")
 	}
 
 	if c.TimeoutMs <= 0 {
@@ -182,6 +1347,105 @@ func (c *Config) Validate() error {
 		c.MaxRetries = 2
 	}
 
+	if err := c.ClientPolicy.validate(); err != nil {
+		return err
+	}
+
+	switch {
+	case c.MetadataCacheTTLSec < -1:
+		return fmt.Errorf("invalid metadata_cache_ttl_sec: %d (must be -1 or more)", c.MetadataCacheTTLSec)
+	case c.MetadataCacheTTLSec == 0:
+		c.MetadataCacheTTLSec = 30
+	}
+
+	if c.NodeInfo.Parallelism < 0 || c.NodeInfo.TimeoutMs < 0 {
+		return fmt.Errorf("node_info.parallelism and node_info.timeout_ms must not be negative")
+	}
+	if c.NodeInfo.Parallelism == 0 {
+		c.NodeInfo.Parallelism = 16
+	}
+	if c.NodeInfo.TimeoutMs == 0 {
+		c.NodeInfo.TimeoutMs = c.TimeoutMs
+	}
+
+	if c.StartupProbe.TimeoutMs < 0 {
+		return fmt.Errorf("startup_probe.timeout_ms must not be negative")
+	}
+	if c.StartupProbe.TimeoutMs == 0 {
+		c.StartupProbe.TimeoutMs = 10000
+	}
+	if c.StartupProbe.Enabled && c.LazyConnect {
+		return fmt.Errorf("startup_probe.enabled cannot be combined with lazy_connect")
+	}
+
+	if c.Benchmark.Set != "" {
+		if c.Benchmark.Namespace == "" {
+			c.Benchmark.Namespace = c.Namespace
+		}
+		if c.Benchmark.Namespace == "" {
+			return fmt.Errorf("benchmark.namespace is required when namespace is not set")
+		}
+		if c.Benchmark.MaxOperations < 0 || c.Benchmark.MaxConcurrency < 0 {
+			return fmt.Errorf("benchmark.max_operations and benchmark.max_concurrency must not be negative")
+		}
+		if c.Benchmark.MaxOperations == 0 {
+			c.Benchmark.MaxOperations = 10000
+		}
+		if c.Benchmark.MaxConcurrency == 0 {
+			c.Benchmark.MaxConcurrency = 16
+		}
+	}
+
+	for name, policy := range map[string]OperationPolicy{
+		"policies.read":  c.Policies.Read,
+		"policies.write": c.Policies.Write,
+		"policies.scan":  c.Policies.Scan,
+		"policies.query": c.Policies.Query,
+		"policies.batch": c.Policies.Batch,
+	} {
+		if err := policy.validate(name); err != nil {
+			return err
+		}
+	}
+
+	if c.Replica == "" {
+		c.Replica = ReplicaSequence
+		if c.RackAware {
+			c.Replica = ReplicaPreferRack
+		}
+	}
+	switch c.Replica {
+	case ReplicaMaster, ReplicaMasterProles, ReplicaRandom, ReplicaSequence:
+	case ReplicaPreferRack:
+		if !c.RackAware {
+			return fmt.Errorf("replica %s requires rack_aware", c.Replica)
+		}
+	default:
+		return fmt.Errorf("invalid replica: %s (must be master, master_proles, random, sequence, or prefer_rack)", c.Replica)
+	}
+	if c.ReadModeAP == "" {
+		c.ReadModeAP = ReadModeAPOne
+	}
+	if c.ReadModeAP != ReadModeAPOne && c.ReadModeAP != ReadModeAPAll {
+		return fmt.Errorf("invalid read_mode_ap: %s (must be one or all)", c.ReadModeAP)
+	}
+	if c.ReadModeSC == "" {
+		c.ReadModeSC = ReadModeSCSession
+	}
+	switch c.ReadModeSC {
+	case ReadModeSCSession, ReadModeSCLinearize, ReadModeSCAllowReplica, ReadModeSCAllowUnavailable:
+	default:
+		return fmt.Errorf("invalid read_mode_sc: %s (must be session, linearize, allow_replica, or allow_unavailable)", c.ReadModeSC)
+	}
+	if c.RackAware && len(c.RackIDs) == 0 {
+		return fmt.Errorf("rack_aware requires rack_ids")
+	}
+	for _, id := range c.RackIDs {
+		if id < 0 {
+			return fmt.Errorf("invalid rack_ids entry: %d (must not be negative)", id)
+		}
+	}
+
 	if c.DefaultMaxRecords <= 0 {
 		c.DefaultMaxRecords = 1000
 	}
@@ -190,15 +1454,373 @@ func (c *Config) Validate() error {
 		c.MaxBatchSize = 5000
 	}
 
+	if c.BatchSplit.Concurrency < 0 || c.BatchSplit.MaxKeys < 0 {
+		return fmt.Errorf("batch_split.concurrency and batch_split.max_keys must not be negative")
+	}
+	if c.BatchSplit.Concurrency == 0 {
+		c.BatchSplit.Concurrency = 4
+	}
+	if c.BatchSplit.MaxKeys == 0 {
+		c.BatchSplit.MaxKeys = 10 * c.MaxBatchSize
+	}
+
+	if c.MaxInlineResultBytes < 0 {
+		c.MaxInlineResultBytes = 0
+	}
+
+	if c.MaxResponseBytes < 0 {
+		c.MaxResponseBytes = 0
+	}
+
+	if c.MaxResultBytes < 0 {
+		c.MaxResultBytes = 0
+	}
+
+	if c.MaxConcurrentRequests <= 0 {
+		c.MaxConcurrentRequests = 16
+	}
+
+	if c.RequestTimeoutMs <= 0 {
+		c.RequestTimeoutMs = 30000
+	}
+	if c.SlowCallThresholdMs < 0 {
+		return fmt.Errorf("invalid slow_call_threshold_ms: %d (must not be negative)", c.SlowCallThresholdMs)
+	}
+
+	return nil
+}
+
+// validate checks an audit sink and fills in its defaults.
+func (s *AuditSinkConfig) validate() error {
+	switch s.Type {
+	case "syslog":
+		if s.Network == "" {
+			s.Network = "udp"
+		}
+		switch s.Network {
+		case "udp", "tcp", "tls":
+		default:
+			return fmt.Errorf("invalid network: %s (must be udp, tcp, or tls)", s.Network)
+		}
+		if s.Address == "" {
+			return fmt.Errorf("address is required")
+		}
+		if (s.CertFile == "") != (s.KeyFile == "") {
+			return fmt.Errorf("cert_file and key_file must be set together")
+		}
+	case "webhook":
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid url: %q (must be an http or https URL)", s.URL)
+		}
+		if s.BatchSize < 0 || s.FlushIntervalMs < 0 || s.TimeoutMs < 0 || s.MaxRetries < -1 {
+			return fmt.Errorf("batch_size, flush_interval_ms, and timeout_ms must not be negative, and max_retries must be -1 or more")
+		}
+	default:
+		return fmt.Errorf("invalid type: %q (must be syslog or webhook)", s.Type)
+	}
+	if s.Name == "" {
+		s.Name = s.Type
+	}
 	return nil
 }
 
+// SocketFileMode returns the file permissions for the unix socket.
+func (c *Config) SocketFileMode() fs.FileMode {
+	mode, err := strconv.ParseUint(c.SocketMode, 8, 32)
+	if err != nil || c.SocketMode == "" || mode > 0o777 {
+		return 0600
+	}
+	return fs.FileMode(mode)
+}
+
+// isLoopback reports whether host names the local machine. An empty host
+// listens on every interface, so it isn't.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// AllowedNetworks parses AllowedCIDRs. A single address is treated as a
+// network containing only that address.
+func (c *Config) AllowedNetworks() ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(c.AllowedCIDRs))
+	for _, cidr := range c.AllowedCIDRs {
+		if ip := net.ParseIP(cidr); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed_cidrs entry: %s", cidr)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
 // CanWrite returns true if the role permits write operations.
 func (c *Config) CanWrite() bool {
-	return c.Role == RoleReadWrite || c.Role == RoleAdmin
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Role.CanWrite() && !c.ReadOnly
 }
 
 // CanAdmin returns true if the role permits administrative operations.
 func (c *Config) CanAdmin() bool {
-	return c.Role == RoleAdmin
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Role.CanAdmin() && !c.ReadOnly
+}
+
+// GrantedRole returns the role granted to the request in ctx, whether or
+// not the server is read-only. A role resolved for the client takes
+// precedence over the process-wide role.
+func (c *Config) GrantedRole(ctx context.Context) Role {
+	if role, ok := RoleFromContext(ctx); ok {
+		return role
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Role
+}
+
+// EffectiveRole returns the role for the request in ctx: the granted role,
+// lowered to read-only while the server is read-only.
+func (c *Config) EffectiveRole(ctx context.Context) Role {
+	if c.IsReadOnly() {
+		return RoleReadOnly
+	}
+	return c.GrantedRole(ctx)
+}
+
+// IsReadOnly reports whether the server refuses write and admin
+// operations.
+func (c *Config) IsReadOnly() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ReadOnly
+}
+
+// SetReadOnly switches the server into or out of read-only mode. It
+// returns whether the mode changed.
+func (c *Config) SetReadOnly(enabled bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changed := c.ReadOnly != enabled
+	c.ReadOnly = enabled
+	return changed
+}
+
+// validate checks the client policy settings.
+func (p *ClientPolicyConfig) validate() error {
+	for name, value := range map[string]int{
+		"connection_queue_size":    p.ConnectionQueueSize,
+		"min_connections_per_node": p.MinConnectionsPerNode,
+		"error_rate_window":        p.ErrorRateWindow,
+		"idle_timeout_ms":          p.IdleTimeoutMs,
+		"login_timeout_ms":         p.LoginTimeoutMs,
+		"tend_interval_ms":         p.TendIntervalMs,
+		"warm_up_connections":      p.WarmUpConnections,
+	} {
+		if value < 0 {
+			return fmt.Errorf("invalid client_policy.%s: %d (must not be negative)", name, value)
+		}
+	}
+	if p.MaxErrorRate < -1 {
+		return fmt.Errorf("invalid client_policy.max_error_rate: %d (must be -1 or more)", p.MaxErrorRate)
+	}
+	queueSize := p.ConnectionQueueSize
+	if queueSize == 0 {
+		queueSize = 100
+	}
+	if p.MinConnectionsPerNode > queueSize {
+		return fmt.Errorf("client_policy.min_connections_per_node %d exceeds connection_queue_size %d", p.MinConnectionsPerNode, queueSize)
+	}
+	if p.WarmUpConnections > queueSize {
+		return fmt.Errorf("client_policy.warm_up_connections %d exceeds connection_queue_size %d", p.WarmUpConnections, queueSize)
+	}
+	return nil
+}
+
+// NamespaceRole returns the role for the request in ctx within namespace:
+// the effective role, lowered to the namespace's role if that is lower.
+func (c *Config) NamespaceRole(ctx context.Context, namespace string) Role {
+	role := c.EffectiveRole(ctx)
+	if limit := c.NamespacePolicy(namespace).Role; limit != "" && limit.Rank() < role.Rank() {
+		return limit
+	}
+	return role
+}
+
+// UpdateCredentials calls update with c locked, for changing the cluster
+// credentials, TLS material, or API keys while the server runs.
+func (c *Config) UpdateCredentials(update func(*Config)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	update(c)
+}
+
+// ClusterCredentials returns the user, password, and TLS settings for
+// connecting to the cluster, as last set by UpdateCredentials.
+func (c *Config) ClusterCredentials() (user, password string, tls TLSConfig) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.User, c.Password, c.TLS
+}
+
+// NamespacePolicy returns the policy for namespace, which is empty if the
+// namespace has none.
+func (c *Config) NamespacePolicy(namespace string) NamespacePolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Namespaces[namespace]
+}
+
+// Reload copies the settings that can change while the server runs from
+// next, which must have been validated: role, client_roles, redact,
+// pii_masking, namespaces, and the audit rate limits. read_only is only ever turned
+// on, so a reload can't lift read-only mode set with SetReadOnly. It
+// returns the names of the settings that changed.
+func (c *Config) Reload(next *Config) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var changed []string
+	if c.Role != next.Role {
+		c.Role = next.Role
+		changed = append(changed, "role")
+	}
+	if next.ReadOnly && !c.ReadOnly {
+		c.ReadOnly = true
+		changed = append(changed, "read_only")
+	}
+	if !reflect.DeepEqual(c.ClientRoles, next.ClientRoles) {
+		c.ClientRoles = next.ClientRoles
+		changed = append(changed, "client_roles")
+	}
+	if !reflect.DeepEqual(c.Redact, next.Redact) {
+		c.Redact = next.Redact
+		changed = append(changed, "redact")
+	}
+	if !reflect.DeepEqual(c.PIIMasking, next.PIIMasking) {
+		c.PIIMasking = next.PIIMasking
+		changed = append(changed, "pii_masking")
+	}
+	if c.Audit.RateLimitEnabled != next.Audit.RateLimitEnabled || c.Audit.RateLimitRPS != next.Audit.RateLimitRPS ||
+		c.Audit.RateLimitBurst != next.Audit.RateLimitBurst || !reflect.DeepEqual(c.Audit.RateLimits, next.Audit.RateLimits) {
+		c.Audit.RateLimitEnabled = next.Audit.RateLimitEnabled
+		c.Audit.RateLimitRPS = next.Audit.RateLimitRPS
+		c.Audit.RateLimitBurst = next.Audit.RateLimitBurst
+		c.Audit.RateLimits = next.Audit.RateLimits
+		changed = append(changed, "audit.rate_limits")
+	}
+	if !reflect.DeepEqual(c.Namespaces, next.Namespaces) {
+		c.Namespaces = next.Namespaces
+		changed = append(changed, "namespaces")
+	}
+	return changed
+}
+
+// restartOnly lists settings RestartRequired doesn't report: read_only,
+// which only the operator lifts, and the credentials that are resolved from
+// secret references and rotated without a reload.
+var restartOnly = map[string]bool{
+	"read_only":     true,
+	"user":          true,
+	"password":      true,
+	"tls":           true,
+	"auth.api_keys": true,
+}
+
+// RestartRequired returns the settings that differ between c and next
+// after Reload, which therefore keep their startup values until the server
+// restarts. Settings are named by their option names, with a second level
+// for sections, such as "audit.file_path".
+func (c *Config) RestartRequired(next *Config) []string {
+	c.mu.RLock()
+	current, err := json.Marshal(c)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil
+	}
+	updated, err := json.Marshal(next)
+	if err != nil {
+		return nil
+	}
+
+	var differ []string
+	jsonDiff("", current, updated, 2, &differ)
+	names := differ[:0]
+	for _, name := range differ {
+		section, _, _ := strings.Cut(name, ".")
+		if !restartOnly[name] && !restartOnly[section] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// jsonDiff appends to differ the names, under prefix, of the keys whose
+// values differ between the JSON objects a and b, descending depth levels.
+func jsonDiff(prefix string, a, b json.RawMessage, depth int, differ *[]string) {
+	var objA, objB map[string]json.RawMessage
+	if depth == 0 || json.Unmarshal(a, &objA) != nil || json.Unmarshal(b, &objB) != nil {
+		if !bytes.Equal(a, b) {
+			*differ = append(*differ, strings.TrimSuffix(prefix, "."))
+		}
+		return
+	}
+
+	keys := make([]string, 0, len(objA)+len(objB))
+	for k := range objA {
+		keys = append(keys, k)
+	}
+	for k := range objB {
+		if _, ok := objA[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		jsonDiff(prefix+k+".", objA[k], objB[k], depth-1, differ)
+	}
+}
+
+// RoleForClient returns the role mapped to the first matching client
+// identity in client_roles.
+func (c *Config) RoleForClient(identities ...string) (Role, bool) {
+	for _, id := range identities {
+		if id == "" {
+			continue
+		}
+		c.mu.RLock()
+		role, ok := c.ClientRoles[id]
+		c.mu.RUnlock()
+		if ok {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// roleContextKey is the context key for a per-request role.
+type roleContextKey struct{}
+
+// WithRole returns a context carrying the role asserted by the client's credentials.
+func WithRole(ctx context.Context, role Role) context.Context {
+	return context.WithValue(ctx, roleContextKey{}, role)
+}
+
+// RoleFromContext returns the role stored in context, if any.
+func RoleFromContext(ctx context.Context) (Role, bool) {
+	role, ok := ctx.Value(roleContextKey{}).(Role)
+	return role, ok
 }
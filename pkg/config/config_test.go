@@ -4,8 +4,10 @@
 package config
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -93,6 +95,1018 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unix transport without socket path",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "unix",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unix transport with socket path",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "unix",
+				SocketPath: "/tmp/aerospike-mcp.sock",
+				SocketMode: "0660",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid socket mode",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "unix",
+				SocketPath: "/tmp/aerospike-mcp.sock",
+				SocketMode: "rw-rw----",
+			},
+			wantErr: true,
+		},
+		{
+			name: "socket mode with bits beyond permissions",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "unix",
+				SocketPath: "/tmp/aerospike-mcp.sock",
+				SocketMode: "04770",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid client role",
+			config: &Config{
+				Hosts:       []Host{{Host: "localhost", Port: 3000}},
+				Role:        RoleReadOnly,
+				Transport:   "sse",
+				ClientRoles: map[string]Role{"ci": "superuser"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative vault renew interval",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Secrets:   SecretsConfig{Vault: &VaultConfig{Address: "https://vault:8200", RenewIntervalSec: -1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid auth mode",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				AuthMode:  "kerberos",
+			},
+			wantErr: true,
+		},
+		{
+			name: "external auth without tls",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				AuthMode:  "external",
+				User:      "ldap-user",
+			},
+			wantErr: true,
+		},
+		{
+			name: "external auth with tls",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				AuthMode:  "EXTERNAL",
+				User:      "ldap-user",
+				TLS:       TLSConfig{Enabled: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pki auth without client certificate",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				AuthMode:  "pki",
+				TLS:       TLSConfig{Enabled: true, CAFile: "/etc/ssl/ca.pem"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pki auth with client certificate",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				AuthMode:  "pki",
+				TLS:       TLSConfig{Enabled: true, CertFile: "/etc/ssl/client.pem", KeyFile: "/etc/ssl/client.key"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "redaction rule without bin",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Redact:    []RedactionRule{{Set: "users"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "redaction rule with bad pattern",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Redact:    []RedactionRule{{Bin: "ssn["}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pii masking with known detectors",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				PIIMasking: &PIIConfig{Detectors: []string{"email"}, Namespaces: map[string][]string{"logs": {}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "pii masking with unknown detector",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				PIIMasking: &PIIConfig{Namespaces: map[string][]string{"crm": {"ssn"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "per-category rate limits",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{RateLimits: map[string]RateLimit{"read": {RPS: 50}, "write": {RPS: 5, Burst: 10}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rate limit for unknown category",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{RateLimits: map[string]RateLimit{"query": {RPS: 5}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rate limit without rps",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{RateLimits: map[string]RateLimit{"write": {Burst: 5}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "server tls with optional client certificates",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				ServerTLS: TLSConfig{Enabled: true, CertFile: "s.crt", KeyFile: "s.key", CAFile: "ca.crt", ClientAuth: "optional"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "server tls client auth without ca",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				ServerTLS: TLSConfig{Enabled: true, CertFile: "s.crt", KeyFile: "s.key", ClientAuth: "require"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "server tls invalid client auth",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				ServerTLS: TLSConfig{Enabled: true, CertFile: "s.crt", KeyFile: "s.key", CAFile: "ca.crt", ClientAuth: "always"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "allowed cidrs",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "sse",
+				AllowedCIDRs: []string{"10.0.0.0/8", "127.0.0.1", "::1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid allowed cidr",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "sse",
+				AllowedCIDRs: []string{"10.0.0.0/33"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "audit signing key without hash chain",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{SigningKeyFile: "audit.key"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "audit hash chain with signing key",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{HashChain: true, SigningKeyFile: "audit.key", SignEvery: 50},
+			},
+			wantErr: false,
+		},
+		{
+			name: "audit rotation without file",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{Rotation: AuditRotationConfig{MaxSizeMB: 100}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative audit retention",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{FilePath: "audit.log", Rotation: AuditRotationConfig{MaxSizeMB: 100, MaxFiles: -1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "audit rotation",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{FilePath: "audit.log", Rotation: AuditRotationConfig{RotateIntervalHours: 24, Compress: true, MaxAgeDays: 90}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "audit aerospike sink without namespace",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{Aerospike: &AuditAerospikeConfig{Set: "audit"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "audit aerospike sink",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{Aerospike: &AuditAerospikeConfig{Namespace: "ops", TTLSec: -1}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "audit syslog and webhook sinks",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit: AuditConfig{Sinks: []AuditSinkConfig{
+					{Type: "syslog", Network: "tls", Address: "siem:6514"},
+					{Type: "webhook", URL: "https://hooks.example.com/audit", BatchSize: 50},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "audit syslog sink without address",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{Sinks: []AuditSinkConfig{{Type: "syslog"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "audit webhook sink with invalid url",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{Sinks: []AuditSinkConfig{{Type: "webhook", URL: "hooks.example.com"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "audit sink with unknown type",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Audit:     AuditConfig{Sinks: []AuditSinkConfig{{Type: "kafka"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "key rules",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				KeyRules:  []KeyRule{{Namespace: "test", Set: "users", Pattern: "acme:.*", Roles: []Role{RoleReadWrite}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "key rule with invalid regexp",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				KeyRules:  []KeyRule{{Namespace: "test", Pattern: "acme:("}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "key rule without pattern",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				KeyRules:  []KeyRule{{Namespace: "test"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "key rule with invalid role",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				KeyRules:  []KeyRule{{Pattern: "acme:.*", Roles: []Role{"root"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative scan limit",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				ScanLimits: ScanLimitConfig{MaxConcurrentPerClient: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative scan max concurrent nodes",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				ScanLimits: ScanLimitConfig{MaxConcurrentNodes: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative large scan threshold",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				ScanLimits: ScanLimitConfig{ConfirmAboveRecords: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "elevation without approval key",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Elevation: ElevationConfig{Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "metrics on stdio without port",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Metrics:   MetricsConfig{Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "metrics on sse",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				Metrics:   MetricsConfig{Enabled: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "metrics path without slash",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Metrics:   MetricsConfig{Enabled: true, Port: 9464, Path: "metrics"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "health port on stdio",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Health:    HealthConfig{Port: 8081},
+			},
+			wantErr: false,
+		},
+		{
+			name: "health port same as metrics port",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Metrics:   MetricsConfig{Enabled: true, Port: 9464},
+				Health:    HealthConfig{Port: 9464},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid health timeout",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Health:    HealthConfig{TimeoutMs: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "debug on localhost",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Debug:     DebugConfig{Enabled: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "debug on all interfaces",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Debug:     DebugConfig{Enabled: true, Address: ":6060"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "debug remote without allowlist",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Debug:     DebugConfig{Enabled: true, Address: "0.0.0.0:6060", AllowRemote: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "debug remote with allowlist",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "stdio",
+				AllowedCIDRs: []string{"10.0.0.0/8"},
+				Debug:        DebugConfig{Enabled: true, Address: "0.0.0.0:6060", AllowRemote: true},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid debug address",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Debug:     DebugConfig{Enabled: true, Address: "localhost"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tool timeouts",
+			config: &Config{
+				Hosts:          []Host{{Host: "localhost", Port: 3000}},
+				Role:           RoleReadOnly,
+				Transport:      "stdio",
+				ToolTimeoutsMs: map[string]int{"read": 5000, "admin": 60000},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown tool timeout category",
+			config: &Config{
+				Hosts:          []Host{{Host: "localhost", Port: 3000}},
+				Role:           RoleReadOnly,
+				Transport:      "stdio",
+				ToolTimeoutsMs: map[string]int{"scan": 5000},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero tool timeout",
+			config: &Config{
+				Hosts:          []Host{{Host: "localhost", Port: 3000}},
+				Role:           RoleReadOnly,
+				Transport:      "stdio",
+				ToolTimeoutsMs: map[string]int{"write": 0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cluster events on grpc",
+			config: &Config{
+				Hosts:         []Host{{Host: "localhost", Port: 3000}},
+				Role:          RoleReadOnly,
+				Transport:     "grpc",
+				ClusterEvents: ClusterEventsConfig{Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative cluster events interval",
+			config: &Config{
+				Hosts:         []Host{{Host: "localhost", Port: 3000}},
+				Role:          RoleReadOnly,
+				Transport:     "stdio",
+				ClusterEvents: ClusterEventsConfig{Enabled: true, PollIntervalMs: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sse settings",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				SSE:       SSEConfig{KeepaliveSec: 5, ReconnectGraceSec: 120},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative sse keepalive",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				SSE:       SSEConfig{KeepaliveSec: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative sse replay buffer",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				SSE:       SSEConfig{ReplayBufferSize: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative slow call threshold",
+			config: &Config{
+				Hosts:               []Host{{Host: "localhost", Port: 3000}},
+				Role:                RoleReadOnly,
+				Transport:           "stdio",
+				SlowCallThresholdMs: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid log level",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Log:       LogConfig{Level: "verbose"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid log format",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Log:       LogConfig{Format: "xml"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "json logs",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Log:       LogConfig{Level: "DEBUG", Format: "json"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "tracing without endpoint",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Tracing:   TracingConfig{Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tracing with invalid sample ratio",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Tracing:   TracingConfig{Enabled: true, Endpoint: "http://localhost:4318", SampleRatio: 1.5},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tracing",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Tracing:   TracingConfig{Enabled: true, Endpoint: "http://localhost:4318"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative write quota",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadWrite,
+				Transport:  "stdio",
+				WriteQuota: WriteQuotaConfig{RecordsPerDay: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative session ttl",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				Sessions:  SessionConfig{TTLSec: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative sessions per client",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "sse",
+				Sessions:  SessionConfig{MaxPerClient: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "client policy",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "stdio",
+				ClientPolicy: ClientPolicyConfig{ConnectionQueueSize: 256, MinConnectionsPerNode: 16, MaxErrorRate: -1, TendIntervalMs: 3000},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative client policy timeout",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "stdio",
+				ClientPolicy: ClientPolicyConfig{LoginTimeoutMs: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "min connections above queue size",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "stdio",
+				ClientPolicy: ClientPolicyConfig{MinConnectionsPerNode: 200},
+			},
+			wantErr: true,
+		},
+		{
+			name: "warm up exceeds queue size",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "stdio",
+				ClientPolicy: ClientPolicyConfig{ConnectionQueueSize: 8, WarmUpConnections: 16},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative node info parallelism",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				NodeInfo:  NodeInfoConfig{Parallelism: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "benchmark without a namespace",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleAdmin,
+				Transport: "stdio",
+				Benchmark: BenchmarkConfig{Set: "bench"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "benchmark in the default namespace",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Namespace: "test",
+				Role:      RoleAdmin,
+				Transport: "stdio",
+				Benchmark: BenchmarkConfig{Set: "bench"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative batch split concurrency",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				BatchSplit: BatchSplitConfig{Enabled: true, Concurrency: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative jobs timeout",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Jobs:      JobsConfig{Enabled: true, TimeoutSec: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "metadata cache disabled",
+			config: &Config{
+				Hosts:               []Host{{Host: "localhost", Port: 3000}},
+				Role:                RoleReadOnly,
+				Transport:           "stdio",
+				MetadataCacheTTLSec: -1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative metadata cache ttl",
+			config: &Config{
+				Hosts:               []Host{{Host: "localhost", Port: 3000}},
+				Role:                RoleReadOnly,
+				Transport:           "stdio",
+				MetadataCacheTTLSec: -5,
+			},
+			wantErr: true,
+		},
+		{
+			name: "startup probe",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "stdio",
+				StartupProbe: StartupProbeConfig{Enabled: true, RequiredNamespaces: []string{"test"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "startup probe with lazy connect",
+			config: &Config{
+				Hosts:        []Host{{Host: "localhost", Port: 3000}},
+				Role:         RoleReadOnly,
+				Transport:    "stdio",
+				LazyConnect:  true,
+				StartupProbe: StartupProbeConfig{Enabled: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "operation policies",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Policies: OperationPolicies{
+					Read: OperationPolicy{TotalTimeoutMs: 500, SocketTimeoutMs: 200, SleepBetweenRetriesMs: 10, SleepMultiplier: 2},
+					Scan: OperationPolicy{TotalTimeoutMs: 60000},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative operation socket timeout",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Policies:  OperationPolicies{Write: OperationPolicy{SocketTimeoutMs: -1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "operation sleep multiplier below one",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Policies:  OperationPolicies{Batch: OperationPolicy{SleepMultiplier: 0.5}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rack aware",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				RackAware: true,
+				RackIDs:   []int{1, 2},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rack aware without racks",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				RackAware: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "prefer rack without rack aware",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Replica:   ReplicaPreferRack,
+			},
+			wantErr: true,
+		},
+		{
+			name: "strong consistency read mode",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				ReadModeAP: ReadModeAPAll,
+				ReadModeSC: ReadModeSCLinearize,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid read mode ap",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				ReadModeAP: "quorum",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid read mode sc",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				ReadModeSC: "eventual",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid replica",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadOnly,
+				Transport: "stdio",
+				Replica:   "nearest",
+			},
+			wantErr: true,
+		},
+		{
+			name: "namespace policies",
+			config: &Config{
+				Hosts:     []Host{{Host: "localhost", Port: 3000}},
+				Role:      RoleReadWrite,
+				Transport: "stdio",
+				Namespaces: map[string]NamespacePolicy{
+					"test": {TimeoutMs: 5000, MaxRecords: 10000},
+					"prod": {Role: RoleReadOnly, RateLimits: map[string]RateLimit{"read": {RPS: 10}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative namespace timeout",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				Namespaces: map[string]NamespacePolicy{"test": {TimeoutMs: -1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative namespace max records",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				Namespaces: map[string]NamespacePolicy{"test": {MaxRecords: -1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid namespace role",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				Namespaces: map[string]NamespacePolicy{"prod": {Role: "root"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown namespace rate limit category",
+			config: &Config{
+				Hosts:      []Host{{Host: "localhost", Port: 3000}},
+				Role:       RoleReadOnly,
+				Transport:  "stdio",
+				Namespaces: map[string]NamespacePolicy{"prod": {RateLimits: map[string]RateLimit{"scan": {RPS: 1}}}},
+			},
+			wantErr: true,
+		},
 		{
 			name: "all roles valid",
 			config: &Config{
@@ -114,6 +1128,44 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestAuditSinkDefaults(t *testing.T) {
+	cfg := &Config{
+		Hosts:     []Host{{Host: "localhost", Port: 3000}},
+		Role:      RoleReadOnly,
+		Transport: "stdio",
+		Audit:     AuditConfig{Sinks: []AuditSinkConfig{{Type: "syslog", Address: "localhost:514"}}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	sink := cfg.Audit.Sinks[0]
+	if sink.Network != "udp" || sink.Name != "syslog" {
+		t.Errorf("Expected network udp and name syslog, got %s and %s", sink.Network, sink.Name)
+	}
+}
+
+func TestSocketFileMode(t *testing.T) {
+	tests := []struct {
+		mode string
+		want os.FileMode
+	}{
+		{"", 0600},
+		{"0660", 0660},
+		{"755", 0755},
+		{"invalid", 0600},
+		{"04770", 0600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := &Config{SocketMode: tt.mode}
+			if got := cfg.SocketFileMode(); got != tt.want {
+				t.Errorf("SocketFileMode() = %o, want %o", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCanWrite(t *testing.T) {
 	tests := []struct {
 		role     Role
@@ -154,6 +1206,150 @@ func TestCanAdmin(t *testing.T) {
 	}
 }
 
+func TestEffectiveRole(t *testing.T) {
+	cfg := &Config{
+		Role:        RoleReadOnly,
+		ClientRoles: map[string]Role{"ci": RoleReadWrite, "ops@example.com": RoleAdmin},
+	}
+
+	if got := cfg.EffectiveRole(context.Background()); got != RoleReadOnly {
+		t.Errorf("Expected process-wide role '%s', got '%s'", RoleReadOnly, got)
+	}
+
+	ctx := WithRole(context.Background(), RoleAdmin)
+	if got := cfg.EffectiveRole(ctx); got != RoleAdmin {
+		t.Errorf("Expected context role '%s', got '%s'", RoleAdmin, got)
+	}
+
+	tests := []struct {
+		identities []string
+		want       Role
+		found      bool
+	}{
+		{[]string{"ci"}, RoleReadWrite, true},
+		{[]string{"", "ops@example.com"}, RoleAdmin, true},
+		{[]string{"ci", "ops@example.com"}, RoleReadWrite, true},
+		{[]string{"unknown"}, "", false},
+		{nil, "", false},
+	}
+
+	for _, tt := range tests {
+		role, ok := cfg.RoleForClient(tt.identities...)
+		if role != tt.want || ok != tt.found {
+			t.Errorf("RoleForClient(%v) = %s, %v, want %s, %v", tt.identities, role, ok, tt.want, tt.found)
+		}
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	cfg := &Config{Role: RoleAdmin}
+	admin := WithRole(context.Background(), RoleAdmin)
+
+	if !cfg.SetReadOnly(true) || cfg.SetReadOnly(true) {
+		t.Error("Expected only the first SetReadOnly(true) to report a change")
+	}
+	if got := cfg.EffectiveRole(admin); got != RoleReadOnly {
+		t.Errorf("Expected effective role '%s' while read-only, got '%s'", RoleReadOnly, got)
+	}
+	if got := cfg.GrantedRole(admin); got != RoleAdmin {
+		t.Errorf("Expected granted role '%s', got '%s'", RoleAdmin, got)
+	}
+	if cfg.CanWrite() || cfg.CanAdmin() {
+		t.Error("Expected writes and admin operations to be refused while read-only")
+	}
+
+	// A reload can turn read-only mode on, but not off
+	if changed := cfg.Reload(&Config{Role: RoleAdmin}); len(changed) != 0 || !cfg.IsReadOnly() {
+		t.Errorf("Expected reload to keep read-only mode, got changes %v", changed)
+	}
+	cfg.SetReadOnly(false)
+	if got := cfg.EffectiveRole(admin); got != RoleAdmin {
+		t.Errorf("Expected effective role '%s' after read-only mode ends, got '%s'", RoleAdmin, got)
+	}
+	if changed := cfg.Reload(&Config{Role: RoleAdmin, ReadOnly: true}); len(changed) != 1 || changed[0] != "read_only" || !cfg.IsReadOnly() {
+		t.Errorf("Expected reload to turn on read-only mode, got changes %v", changed)
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Password = "resolved-from-vault"
+	cfg.SetReadOnly(true)
+
+	next := DefaultConfig()
+	next.Password = "vault:secret/aerospike#password" // rotated, not reloaded
+	next.Role = RoleAdmin
+	next.Port = 9090
+	next.Log.Level = "debug"
+	cfg.Reload(next)
+
+	if got := strings.Join(cfg.RestartRequired(next), ","); got != "log.level,port" {
+		t.Errorf("Expected log.level and port to need a restart, got %s", got)
+	}
+}
+
+func TestReplicaDefault(t *testing.T) {
+	tests := []struct {
+		name      string
+		rackAware bool
+		replica   string
+		expected  string
+	}{
+		{"default", false, "", ReplicaSequence},
+		{"rack aware", true, "", ReplicaPreferRack},
+		{"explicit", true, ReplicaMaster, ReplicaMaster},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.RackAware = tt.rackAware
+			cfg.RackIDs = []int{1}
+			cfg.Replica = tt.replica
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if cfg.Replica != tt.expected {
+				t.Errorf("Expected replica '%s', got '%s'", tt.expected, cfg.Replica)
+			}
+		})
+	}
+}
+
+func TestNamespaceRole(t *testing.T) {
+	cfg := &Config{
+		Role: RoleReadWrite,
+		Namespaces: map[string]NamespacePolicy{
+			"prod":    {Role: RoleReadOnly},
+			"staging": {Role: RoleAdmin},
+			"test":    {TimeoutMs: 5000},
+		},
+	}
+	admin := WithRole(context.Background(), RoleAdmin)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		namespace string
+		want      Role
+	}{
+		{"lowered", context.Background(), "prod", RoleReadOnly},
+		{"lowered from client role", admin, "prod", RoleReadOnly},
+		{"never raised", context.Background(), "staging", RoleReadWrite},
+		{"client role within limit", admin, "staging", RoleAdmin},
+		{"policy without role", admin, "test", RoleAdmin},
+		{"no policy", context.Background(), "other", RoleReadWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.NamespaceRole(tt.ctx, tt.namespace); got != tt.want {
+				t.Errorf("Expected role '%s', got '%s'", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestLoadFromFile(t *testing.T) {
 	// Create temp config file
 	tmpDir := t.TempDir()
@@ -212,3 +1408,108 @@ func TestLoadFromEnv(t *testing.T) {
 		t.Errorf("Expected password 'secret123', got '%s'", cfg.Password)
 	}
 }
+
+func TestLoadAPIKeysFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	keysPath := filepath.Join(tmpDir, "keys")
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	keysContent := "# deployment keys\nci:abc123\n\nxyz789\n"
+	if err := os.WriteFile(keysPath, []byte(keysContent), 0600); err != nil {
+		t.Fatalf("Failed to write keys file: %v", err)
+	}
+
+	configContent := `{
+		"hosts": [{"host": "localhost", "port": 3000}],
+		"transport": "sse",
+		"auth": {
+			"api_keys": [{"name": "static", "key": "static-key"}],
+			"api_keys_file": "` + keysPath + `"
+		}
+	}`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.Auth.APIKeys) != 3 {
+		t.Fatalf("Expected 3 API keys, got %d", len(cfg.Auth.APIKeys))
+	}
+	if cfg.Auth.APIKeys[1].Name != "ci" || cfg.Auth.APIKeys[1].Key != "abc123" {
+		t.Errorf("Unexpected named key: %+v", cfg.Auth.APIKeys[1])
+	}
+	if cfg.Auth.APIKeys[2].Key != "xyz789" || cfg.Auth.APIKeys[2].Name == "" {
+		t.Errorf("Unexpected unnamed key: %+v", cfg.Auth.APIKeys[2])
+	}
+	if !cfg.Auth.Enabled() {
+		t.Error("Expected auth to be enabled")
+	}
+}
+
+func TestLoadPasswordFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	passwordPath := filepath.Join(tmpDir, "password")
+	configPath := filepath.Join(tmpDir, "config.json")
+
+	if err := os.WriteFile(passwordPath, []byte("secret123\n"), 0600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+	configContent := `{
+		"hosts": [{"host": "localhost", "port": 3000}],
+		"password_file": "` + passwordPath + `"
+	}`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Password != "secret123" {
+		t.Errorf("Expected password 'secret123', got '%s'", cfg.Password)
+	}
+
+	// The file must be the only source of the password
+	configContent = `{
+		"hosts": [{"host": "localhost", "port": 3000}],
+		"password": "literal",
+		"password_file": "` + passwordPath + `"
+	}`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if _, err := Load(configPath); err == nil {
+		t.Error("Expected error for password combined with password_file")
+	}
+}
+
+func TestBatchLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		split BatchSplitConfig
+		want  int
+	}{
+		{"not split", BatchSplitConfig{}, 100},
+		{"split with default max keys", BatchSplitConfig{Enabled: true}, 1000},
+		{"split with max keys", BatchSplitConfig{Enabled: true, MaxKeys: 250}, 250},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.MaxBatchSize = 100
+			cfg.BatchSplit = tt.split
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if got := cfg.BatchLimit(); got != tt.want {
+				t.Errorf("Expected batch limit %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the name of every environment variable that sets a
+// config field.
+const EnvPrefix = "AEROSPIKE_MCP_"
+
+// environment returns the set, non-empty variables starting with EnvPrefix.
+func environment() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(name, EnvPrefix) && value != "" {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// applyEnv overrides config fields with environment variables. Each field's
+// variable is EnvPrefix followed by its JSON path in upper case, joined with
+// underscores: log.level is AEROSPIKE_MCP_LOG_LEVEL. Scalars are parsed from
+// their text; lists take comma-separated values (hosts as host:port), maps
+// take comma-separated key=value pairs, and any list, map, or list of
+// objects may also be given as JSON.
+func applyEnv(cfg *Config, env map[string]string) error {
+	return applyValues(reflect.ValueOf(cfg).Elem(), "", env, envName)
+}
+
+// applyOverrides sets config fields from values keyed by JSON path, parsed
+// the same way as environment variables.
+func applyOverrides(cfg *Config, overrides Overrides) error {
+	return applyValues(reflect.ValueOf(cfg).Elem(), "", overrides, func(path string) string { return path })
+}
+
+// envName returns the environment variable for a JSON path.
+func envName(path string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+}
+
+// applyValues sets the fields of the struct v, at JSON path prefix, from
+// values keyed by name(path).
+func applyValues(v reflect.Value, prefix string, values map[string]string, name func(string) string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || tag == "-" || tag == "" {
+			continue
+		}
+		path := prefix + tag
+		fv := v.Field(i)
+
+		// Nested objects are set field by field
+		switch {
+		case field.Type.Kind() == reflect.Struct:
+			if err := applyValues(fv, path+".", values, name); err != nil {
+				return err
+			}
+			continue
+		case field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct:
+			if !hasPrefix(values, name(path+".")) {
+				continue
+			}
+			if fv.IsNil() {
+				fv.Set(reflect.New(field.Type.Elem()))
+			}
+			if err := applyValues(fv.Elem(), path+".", values, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := values[name(path)]
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(fv, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name(path), err)
+		}
+	}
+	return nil
+}
+
+// hasPrefix reports whether any key starts with prefix.
+func hasPrefix(values map[string]string, prefix string) bool {
+	for key := range values {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// setFromEnv sets v from an environment variable or override's value.
+func setFromEnv(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+			ptr := reflect.New(v.Type())
+			if err := json.Unmarshal([]byte(value), ptr.Interface()); err != nil {
+				return err
+			}
+			v.Set(ptr.Elem())
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Type().Elem() == reflect.TypeOf(Host{}) {
+			hosts, err := parseHostList(value)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(hosts))
+			return nil
+		}
+		items := strings.Split(value, ",")
+		list := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setScalar(list.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		v.Set(list)
+		return nil
+
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range strings.Split(value, ",") {
+			key, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("expected key=value pairs or JSON, got %q", pair)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setScalar(elem, strings.TrimSpace(val)); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+		return nil
+	}
+	return setScalar(v, value)
+}
+
+// setScalar parses value into a string, bool, or number.
+func setScalar(v reflect.Value, value string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a non-negative integer, got %q", value)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", value)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("must be given as JSON")
+	}
+	return nil
+}
+
+// parseHostList parses comma-separated host:port pairs. The port defaults
+// to 3000.
+func parseHostList(value string) ([]Host, error) {
+	var hosts []Host
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		host := Host{Host: entry, Port: 3000}
+		if i := strings.LastIndex(entry, ":"); i >= 0 && !strings.HasSuffix(entry, "]") {
+			port, err := strconv.Atoi(entry[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid port in %q", entry)
+			}
+			host = Host{Host: strings.Trim(entry[:i], "[]"), Port: port}
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
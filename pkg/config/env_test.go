@@ -0,0 +1,171 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestApplyEnv(t *testing.T) {
+	cfg := DefaultConfig()
+	err := applyEnv(cfg, map[string]string{
+		"AEROSPIKE_MCP_HOSTS":                          "node1:3100, node2",
+		"AEROSPIKE_MCP_ROLE":                           "admin",
+		"AEROSPIKE_MCP_TRANSPORT":                      "sse",
+		"AEROSPIKE_MCP_PORT":                           "9090",
+		"AEROSPIKE_MCP_TLS_ENABLED":                    "true",
+		"AEROSPIKE_MCP_ALLOWED_CIDRS":                  "10.0.0.0/8,192.168.1.1",
+		"AEROSPIKE_MCP_TOOL_TIMEOUTS_MS":               "read=500, admin=60000",
+		"AEROSPIKE_MCP_CLIENT_ROLES":                   `{"ci": "read-only"}`,
+		"AEROSPIKE_MCP_AUDIT_BUFFER_SIZE":              "500",
+		"AEROSPIKE_MCP_AUTH_OIDC_ISSUER":               "https://sso.example.com",
+		"AEROSPIKE_MCP_SCAN_LIMITS_RECORDS_PER_SECOND": "1000",
+	})
+	if err != nil {
+		t.Fatalf("applyEnv() error = %v", err)
+	}
+
+	if want := []Host{{Host: "node1", Port: 3100}, {Host: "node2", Port: 3000}}; !reflect.DeepEqual(cfg.Hosts, want) {
+		t.Errorf("Expected hosts %v, got %v", want, cfg.Hosts)
+	}
+	if cfg.Role != RoleAdmin || cfg.Transport != "sse" || cfg.Port != 9090 || !cfg.TLS.Enabled {
+		t.Errorf("Expected role, transport, port, and TLS to be set, got %s %s %d %v", cfg.Role, cfg.Transport, cfg.Port, cfg.TLS.Enabled)
+	}
+	if want := []string{"10.0.0.0/8", "192.168.1.1"}; !reflect.DeepEqual(cfg.AllowedCIDRs, want) {
+		t.Errorf("Expected allowed CIDRs %v, got %v", want, cfg.AllowedCIDRs)
+	}
+	if want := map[string]int{"read": 500, "admin": 60000}; !reflect.DeepEqual(cfg.ToolTimeoutsMs, want) {
+		t.Errorf("Expected tool timeouts %v, got %v", want, cfg.ToolTimeoutsMs)
+	}
+	if cfg.ClientRoles["ci"] != RoleReadOnly {
+		t.Errorf("Expected client role read-only for ci, got %v", cfg.ClientRoles)
+	}
+	if cfg.Audit.BufferSize != 500 || !cfg.Audit.Enabled {
+		t.Errorf("Expected audit buffer 500 with audit still enabled, got %d %v", cfg.Audit.BufferSize, cfg.Audit.Enabled)
+	}
+	if cfg.Auth.OIDC == nil || cfg.Auth.OIDC.Issuer != "https://sso.example.com" {
+		t.Errorf("Expected the OIDC issuer to be set, got %+v", cfg.Auth.OIDC)
+	}
+	if cfg.ScanLimits.RecordsPerSecond != 1000 {
+		t.Errorf("Expected scan records per second 1000, got %d", cfg.ScanLimits.RecordsPerSecond)
+	}
+	if cfg.PIIMasking != nil {
+		t.Error("Expected optional sections without variables to stay unset")
+	}
+}
+
+func TestApplyEnvErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"bad int", "AEROSPIKE_MCP_PORT", "eighty"},
+		{"bad bool", "AEROSPIKE_MCP_TLS_ENABLED", "maybe"},
+		{"bad host port", "AEROSPIKE_MCP_HOSTS", "node1:abc"},
+		{"bad map", "AEROSPIKE_MCP_TOOL_TIMEOUTS_MS", "read"},
+		{"bad json", "AEROSPIKE_MCP_AUTH_API_KEYS", `[{"name": }]`},
+		{"object list without json", "AEROSPIKE_MCP_AUTH_API_KEYS", "ci"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := applyEnv(DefaultConfig(), map[string]string{tt.key: tt.value}); err == nil {
+				t.Errorf("Expected an error for %s=%s", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestLoadEnvPrecedence(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	configContent := `{
+		"hosts": [{"host": "filehost", "port": 3000}],
+		"role": "read-write",
+		"timeout_ms": 500
+	}`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	t.Setenv("AEROSPIKE_MCP_ROLE", "read-only")
+	t.Setenv("AEROSPIKE_MCP_LOG_LEVEL", "debug")
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Role != RoleReadOnly {
+		t.Errorf("Expected the environment to override the file's role, got '%s'", cfg.Role)
+	}
+	if cfg.Hosts[0].Host != "filehost" || cfg.TimeoutMs != 500 {
+		t.Errorf("Expected file values without variables to be kept, got %s %d", cfg.Hosts[0].Host, cfg.TimeoutMs)
+	}
+	if cfg.Log.Level != "debug" {
+		t.Errorf("Expected log level 'debug', got '%s'", cfg.Log.Level)
+	}
+
+	// Without a file, the environment alone configures the server
+	t.Setenv("AEROSPIKE_MCP_HOSTS", "envhost:3300")
+	t.Setenv("AEROSPIKE_MCP_TRANSPORT", "grpc")
+	t.Setenv("AEROSPIKE_MCP_PORT", "50051")
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Hosts[0].Host != "envhost" || cfg.Hosts[0].Port != 3300 || cfg.Transport != "grpc" {
+		t.Errorf("Expected the environment to configure hosts and transport, got %v %s", cfg.Hosts, cfg.Transport)
+	}
+
+	t.Setenv("AEROSPIKE_MCP_ROLE", "superuser")
+	if _, err := Load(""); err == nil {
+		t.Error("Expected an invalid role from the environment to fail validation")
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	configContent := `{
+		"hosts": [{"host": "filehost", "port": 3000}],
+		"namespace": "file_ns",
+		"role": "admin",
+		"transport": "stdio"
+	}`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv("AEROSPIKE_MCP_ROLE", "read-write")
+	t.Setenv("AEROSPIKE_MCP_NAMESPACE", "env_ns")
+
+	cfg, err := Load(configPath, Overrides{"role": "read-only", "transport": "sse", "port": "9000"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Role != RoleReadOnly {
+		t.Errorf("Expected the override to take precedence over the environment, got role '%s'", cfg.Role)
+	}
+	if cfg.Namespace != "env_ns" || cfg.Hosts[0].Host != "filehost" {
+		t.Errorf("Expected values without overrides to be kept, got namespace '%s' and host '%s'", cfg.Namespace, cfg.Hosts[0].Host)
+	}
+	if cfg.Transport != "sse" || cfg.Port != 9000 {
+		t.Errorf("Expected transport sse on port 9000, got %s on %d", cfg.Transport, cfg.Port)
+	}
+
+	// Overrides alone configure the server
+	t.Setenv("AEROSPIKE_MCP_ROLE", "")
+	cfg, err = Load("", Overrides{"hosts": "localhost:3000", "role": "read-only", "transport": "sse"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Hosts[0].Host != "localhost" || cfg.Role != RoleReadOnly || cfg.Transport != "sse" {
+		t.Errorf("Expected overrides to configure the server, got %v %s %s", cfg.Hosts, cfg.Role, cfg.Transport)
+	}
+
+	if _, err := Load("", Overrides{"port": "http"}); err == nil || err.Error() != `invalid port: expected an integer, got "http"` {
+		t.Errorf("Expected an invalid port error, got %v", err)
+	}
+}
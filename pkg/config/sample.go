@@ -0,0 +1,40 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// sampleYAML is a commented configuration listing every option with its
+// default, written by the init subcommand.
+//
+//go:embed sample.yaml
+var sampleYAML []byte
+
+// Sample returns the example configuration in format "yaml" or "json".
+// The YAML sample lists every option with comments, optional settings
+// commented out; JSON has no comments, so it holds only the active
+// settings.
+func Sample(format string) ([]byte, error) {
+	switch format {
+	case "yaml", "yml":
+		return bytes.Clone(sampleYAML), nil
+	case "json":
+		data, err := yamlToJSON(sampleYAML)
+		if err != nil {
+			return nil, fmt.Errorf("converting sample configuration: %w", err)
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, data, "", "  "); err != nil {
+			return nil, fmt.Errorf("formatting sample configuration: %w", err)
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported sample format %q (must be yaml or json)", format)
+}
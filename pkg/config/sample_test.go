@@ -0,0 +1,86 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSampleLoads(t *testing.T) {
+	tmpDir := t.TempDir()
+	emptyPath := filepath.Join(tmpDir, "empty.json")
+	if err := os.WriteFile(emptyPath, []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	defaults, err := Load(emptyPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want, _ := json.Marshal(defaults)
+
+	for _, format := range []string{"yaml", "json"} {
+		data, err := Sample(format)
+		if err != nil {
+			t.Fatalf("Sample(%q) error = %v", format, err)
+		}
+		path := filepath.Join(tmpDir, "sample."+format)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s sample) error = %v", format, err)
+		}
+		// The sample's active settings are the defaults
+		if got, _ := json.Marshal(cfg); string(got) != string(want) {
+			t.Errorf("Expected %s sample to match the defaults, got %s", format, got)
+		}
+	}
+
+	if _, err := Sample("toml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+// TestSampleListsEveryOption keeps sample.yaml in step with the Config
+// struct: every option must appear in it, set or commented out.
+func TestSampleListsEveryOption(t *testing.T) {
+	sample := string(sampleYAML)
+	for _, path := range optionPaths(reflect.TypeOf(Config{}), "") {
+		name := path[strings.LastIndex(path, ".")+1:]
+		if !strings.Contains(sample, name+":") {
+			t.Errorf("Expected sample configuration to list %s", path)
+		}
+	}
+}
+
+// optionPaths returns the JSON paths of the fields of struct type t,
+// including those of nested objects, lists of objects, and maps of objects.
+func optionPaths(t reflect.Type, prefix string) []string {
+	var paths []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || tag == "-" || tag == "" {
+			continue
+		}
+		path := prefix + tag
+		paths = append(paths, path)
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Map {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			paths = append(paths, optionPaths(ft, path+".")...)
+		}
+	}
+	return paths
+}
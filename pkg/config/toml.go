@@ -0,0 +1,57 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlToJSON converts a TOML config file to JSON, so it can be decoded with
+// the same field names and defaults as a JSON file. TOML values are already
+// typed, so no schema is needed; dates and times are kept as strings.
+func tomlToJSON(data []byte) ([]byte, error) {
+	var doc map[string]interface{}
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	return json.Marshal(tomlDates(doc))
+}
+
+// tomlDates replaces the dates and times in v with their TOML text.
+func tomlDates(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = tomlDates(item)
+		}
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = tomlDates(item)
+		}
+		return out
+	case []interface{}:
+		for i, item := range v {
+			v[i] = tomlDates(item)
+		}
+	case time.Time:
+		// The decoder marks local dates and times with these zone names
+		switch v.Location().String() {
+		case "date-local":
+			return v.Format("2006-01-02")
+		case "time-local":
+			return v.Format("15:04:05.999999999")
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999")
+		}
+		return v.Format(time.RFC3339Nano)
+	}
+	return v
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTOMLToJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		toml    string
+		want    string
+		wantErr bool
+	}{
+		{"empty", "# nothing\n", `{}`, false},
+		{"scalars", "a = 1_000\nb = 2.5\nc = true\nd = \"text # not a comment\" # comment\ne = 'C:\\path'\nf = 0o660\ng = 1979-05-27", `{"a":1000,"b":2.5,"c":true,"d":"text # not a comment","e":"C:\\path","f":432,"g":"1979-05-27"}`, false},
+		{"tables", "[log]\nlevel = \"debug\"\n\n[audit.sink]\nset = \"audit\"", `{"audit":{"sink":{"set":"audit"}},"log":{"level":"debug"}}`, false},
+		{"dotted keys", "log.level = \"debug\"\nlog.format = \"json\"", `{"log":{"format":"json","level":"debug"}}`, false},
+		{"array of tables", "[[hosts]]\nhost = \"a\"\nport = 3000\n\n[[hosts]]\nhost = \"b\"", `{"hosts":[{"host":"a","port":3000},{"host":"b"}]}`, false},
+		{"multiline array", "cidrs = [\n  \"10.0.0.0/8\", # internal\n  \"192.168.0.1\",\n]", `{"cidrs":["10.0.0.0/8","192.168.0.1"]}`, false},
+		{"inline table", "roles = { ci = \"read-only\", ops = \"admin\" }", `{"roles":{"ci":"read-only","ops":"admin"}}`, false},
+		{"duplicate key", "a = 1\na = 2", "", true},
+		{"leading zero", "a = 0660", "", true},
+		{"missing value", "a =", "", true},
+		{"multiline string", "a = \"\"\"\nline one\nline two\"\"\"", `{"a":"line one\nline two"}`, false},
+		{"escapes", `a = "tab\there \u00e9"`, `{"a":"tab\there é"}`, false},
+		{"trailing text", "a = 1 2", "", true},
+		{"key through value", "a = 1\n[a.b]", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tomlToJSON([]byte(tt.toml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tomlToJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadTOML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	configContent := `# Aerospike MCP server
+role = "admin"
+transport = "sse"
+timeout_ms = 500
+
+[[hosts]]
+host = "testhost"
+port = 3001
+
+[audit]
+enabled = false
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Hosts[0].Host != "testhost" || cfg.Hosts[0].Port != 3001 {
+		t.Errorf("Expected host testhost:3001, got %s:%d", cfg.Hosts[0].Host, cfg.Hosts[0].Port)
+	}
+	if cfg.Role != RoleAdmin {
+		t.Errorf("Expected role 'admin', got '%s'", cfg.Role)
+	}
+	if cfg.Audit.Enabled {
+		t.Error("Expected audit to be disabled")
+	}
+
+	// Errors name the line
+	badPath := filepath.Join(t.TempDir(), "bad.toml")
+	if err := os.WriteFile(badPath, []byte("role = \"admin\"\nrole = \"read-only\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if _, err := Load(badPath); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected a duplicate key error on line 2, got %v", err)
+	}
+}
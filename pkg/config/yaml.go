@@ -0,0 +1,162 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlToJSON converts a YAML config file to JSON, so it can be decoded with
+// the same field names and defaults as a JSON file. Scalars are converted
+// according to the Config field they set: any scalar given for a string
+// field stays a string, so password: 0123 isn't read as a number. Multiple
+// documents are rejected.
+func yamlToJSON(data []byte) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return []byte(`{}`), nil
+		}
+		return nil, err
+	}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("line %d: multiple documents are not supported", extra.Line)
+	}
+
+	v, err := yamlValue(&doc, reflect.TypeOf(Config{}))
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		v = map[string]interface{}{}
+	}
+	return json.Marshal(v)
+}
+
+// yamlValue converts node to a value that marshals to JSON decodable into t.
+// A nil t means the target type is unknown, and scalars keep their YAML
+// types.
+func yamlValue(node *yaml.Node, t reflect.Type) (interface{}, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(node.Content[0], t)
+
+	case yaml.AliasNode:
+		return yamlValue(node.Alias, t)
+
+	case yaml.MappingNode:
+		out := make(map[string]interface{}, len(node.Content)/2)
+		if err := yamlMapping(node, t, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+
+	case yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		out := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			v, err := yamlValue(item, elem)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+
+	case yaml.ScalarNode:
+		if t != nil && t.Kind() == reflect.String && node.ShortTag() != "!!null" {
+			return node.Value, nil
+		}
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// yamlMapping adds node's entries to out, merging << keys and rejecting
+// duplicates.
+func yamlMapping(node *yaml.Node, t reflect.Type, out map[string]interface{}) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.ShortTag() == "!!merge" {
+			merged := value
+			if merged.Kind == yaml.AliasNode {
+				merged = merged.Alias
+			}
+			if merged.Kind != yaml.MappingNode {
+				return fmt.Errorf("line %d: merge value must be a mapping", value.Line)
+			}
+			if err := yamlMapping(merged, t, out); err != nil {
+				return err
+			}
+			continue
+		}
+		if key.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: keys must be scalars", key.Line)
+		}
+		if _, ok := out[key.Value]; ok {
+			return fmt.Errorf("line %d: duplicate key %q", key.Line, key.Value)
+		}
+		v, err := yamlValue(value, fieldType(t, key.Value))
+		if err != nil {
+			return err
+		}
+		out[key.Value] = v
+	}
+	return nil
+}
+
+// fieldType returns the type of the value name sets in a t: the struct
+// field with that JSON name, or the element type of a map. It returns nil
+// when t is unknown or has no such field.
+func fieldType(t reflect.Type, name string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if tag == "-" {
+				continue
+			}
+			if tag == "" {
+				tag = f.Name
+			}
+			if strings.EqualFold(tag, name) {
+				return f.Type
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 OnChain Media Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    string
+		wantErr bool
+	}{
+		{"empty", "# nothing\n", `{}`, false},
+		{"scalars", "a: 1\nb: 2.5\nc: true\nd: ~\ne: text # comment\nf: \"quoted # not a comment\"\ng: 'it''s'\nh: '0660'", `{"a":1,"b":2.5,"c":true,"d":null,"e":"text","f":"quoted # not a comment","g":"it's","h":"0660"}`, false},
+		{"nested", "---\nlog:\n  level: debug\n  format: json\n", `{"log":{"format":"json","level":"debug"}}`, false},
+		{"sequence of mappings", "hosts:\n  - host: a\n    port: 3000\n  - host: b\n    port: 3001\n", `{"hosts":[{"host":"a","port":3000},{"host":"b","port":3001}]}`, false},
+		{"sequence at key indent", "cidrs:\n- 10.0.0.0/8\n- 192.168.0.1\nport: 1", `{"cidrs":["10.0.0.0/8","192.168.0.1"],"port":1}`, false},
+		{"flow collections", "tools: [get_record, \"put_record\"]\nroles: {ci: read-only, ops: admin}\nnone: []", `{"none":[],"roles":{"ci":"read-only","ops":"admin"},"tools":["get_record","put_record"]}`, false},
+		{"colons in values", "endpoint: http://collector:4318/v1/traces\ntime: 12:30", `{"endpoint":"http://collector:4318/v1/traces","time":"12:30"}`, false},
+		{"nested sequence item", "a:\n  -\n    - 1\n    - 2\n", `{"a":[[1,2]]}`, false},
+		{"block scalars", "a: |\n  line one\n  line two\nb: >\n  folded\n  text\n", `{"a":"line one\nline two\n","b":"folded text\n"}`, false},
+		{"yaml escapes", `a: "tab\there \x41 \u00e9"`, `{"a":"tab\there A é"}`, false},
+		{"anchor and alias", "a: &x 1\nb: *x", `{"a":1,"b":1}`, false},
+		{"multiline flow", "a: [1,\n  2]", `{"a":[1,2]}`, false},
+		{"string fields stay strings", "password: 0123\nuser: 1e3\ntimeout_ms: 0123\nrole: true", `{"password":"0123","role":"true","timeout_ms":83,"user":"1e3"}`, false},
+		{"nested string fields", "hosts:\n  - host: 10\n    port: 3000\nclient_roles:\n  ci: 007", `{"client_roles":{"ci":"007"},"hosts":[{"host":"10","port":3000}]}`, false},
+		{"duplicate key", "a: 1\na: 2", "", true},
+		{"bad indentation", "a:\n    b: 1\n  c: 2", "", true},
+		{"tab indentation", "a:\n\tb: 1", "", true},
+		{"multiple documents", "a: 1\n---\nb: 2", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := yamlToJSON([]byte(tt.yaml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("yamlToJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `# Aerospike MCP server
+hosts:
+  - host: testhost
+    port: 3001
+role: admin
+transport: sse
+timeout_ms: 500
+allowed_cidrs: [10.0.0.0/8]
+audit:
+  enabled: false
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Hosts[0].Host != "testhost" || cfg.Hosts[0].Port != 3001 {
+		t.Errorf("Expected host testhost:3001, got %s:%d", cfg.Hosts[0].Host, cfg.Hosts[0].Port)
+	}
+	if cfg.Role != RoleAdmin {
+		t.Errorf("Expected role 'admin', got '%s'", cfg.Role)
+	}
+	if len(cfg.AllowedCIDRs) != 1 || cfg.AllowedCIDRs[0] != "10.0.0.0/8" {
+		t.Errorf("Expected allowed_cidrs [10.0.0.0/8], got %v", cfg.AllowedCIDRs)
+	}
+	if cfg.Audit.Enabled {
+		t.Error("Expected audit to be disabled")
+	}
+	if cfg.Audit.BufferSize != 100 {
+		t.Errorf("Expected default audit buffer size 100, got %d", cfg.Audit.BufferSize)
+	}
+}